@@ -23,6 +23,9 @@ func main() {
 		&model.Property{},
 		&model.Subdomain{},
 		&model.ClientAppointment{},
+		&model.OutboxMessage{},
+		&model.ClientDataRequest{},
+		&model.ClientDependent{},
 
 		// Tenant schema models (TenantModels)
 		&model.Appointment{},
@@ -34,7 +37,27 @@ func main() {
 		&model.EmployeeWorkRange{},
 		&model.Employee{},
 		&model.Service{},
+		&model.ServiceOverride{},
 		&model.Payment{},
+		&model.PromoCode{},
+		&model.ServicePackage{},
+		&model.ClientPackage{},
+		&model.WebhookSubscription{},
+		&model.WebhookDelivery{},
+		&model.BookableResource{},
+		&model.ServiceResourceRequirement{},
+		&model.AppointmentResourceAllocation{},
+		&model.ScheduleTemplate{},
+		&model.ScheduleTemplateRange{},
+		&model.ClientProfile{},
+		&model.IntakeForm{},
+		&model.IntakeFormVersion{},
+		&model.IntakeResponse{},
+		&model.Review{},
+		&model.LoyaltyProgram{},
+		&model.LoyaltyAccount{},
+		&model.LoyaltyTransaction{},
+		&model.EmployeeInvitation{},
 	)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to load gorm schema: %v\n", err)
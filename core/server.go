@@ -9,16 +9,27 @@ import (
 	"mynute-go/core/src/config/db/model"
 	"mynute-go/core/src/lib"
 	myUploader "mynute-go/core/src/lib/cloud_uploader"
+	"mynute-go/core/src/lib/email"
+	"mynute-go/core/src/lib/intake"
+	"mynute-go/core/src/lib/invitation"
+	"mynute-go/core/src/lib/outbox"
+	"mynute-go/core/src/lib/payment"
+	"mynute-go/core/src/lib/privacy"
+	"mynute-go/core/src/lib/receipt"
+	"mynute-go/core/src/lib/review"
+	"mynute-go/core/src/lib/webhook"
 	"mynute-go/core/src/middleware"
 	"mynute-go/debug"
 	"os"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type Server struct {
-	App *fiber.App
-	Db  *database.Database
+	App         *fiber.App
+	Db          *database.Database
+	stopWorkers []func()
 }
 
 // Creates a new server instance
@@ -68,8 +79,23 @@ func NewServer() *Server {
 	if err := myUploader.StartProvider(); err != nil {
 		panic(err)
 	}
+	outbox.Register(model.OutboxTopicAppointmentEmail, email.HandleAppointmentEmail)
+	outbox.Register(model.OutboxTopicWebhookEvent, webhook.HandleEvent)
+	outbox.Register(model.OutboxTopicWebhookDelivery, webhook.HandleDelivery)
+	outbox.Register(model.OutboxTopicPaymentRefund, payment.HandleRefund)
+	outbox.Register(model.OutboxTopicReceiptEmail, receipt.HandleEmail)
+	outbox.Register(model.OutboxTopicClientExport, privacy.HandleExport)
+	outbox.Register(model.OutboxTopicClientErasure, privacy.HandleErasure)
+	outbox.Register(model.OutboxTopicIntakeEmail, intake.HandleEmail)
+	outbox.Register(model.OutboxTopicReviewEmail, review.HandleEmail)
+	outbox.Register(model.OutboxTopicInvitationEmail, invitation.HandleEmail)
+	outbox.Register(model.OutboxTopicAccountEmail, email.HandleAccountEmail)
+	stopWorkers := []func(){
+		outbox.StartWorker(db.Gorm, 2*time.Second),
+		webhook.StartRetryWorker(db.Gorm, time.Minute),
+	}
 	debug.Clear()
-	return &Server{App: app, Db: db}
+	return &Server{App: app, Db: db, stopWorkers: stopWorkers}
 }
 
 func (s *Server) Shutdown() {
@@ -80,6 +106,9 @@ func (s *Server) Shutdown() {
 	if err := s.App.Shutdown(); err != nil {
 		fmt.Printf("Server did not shutdown gracefully: %v", err)
 	}
+	for _, stop := range s.stopWorkers {
+		stop()
+	}
 	s.Db.Test().Clear()
 	s.Db.Disconnect()
 	fmt.Printf("Finished server shutdown procedure. \n")
@@ -14,6 +14,19 @@ type CreateAppointment struct {
 	CompanyID  uuid.UUID `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
 	StartTime  string    `json:"start_time" example:"2028-01-01T09:00:00Z"`
 	TimeZone   string    `json:"time_zone" example:"America/New_York"` // Timezone in IANA format, e.g., "America/New_York"
+	// Required when the service asks for a deposit or full prepayment
+	Prepayment *PaymentInput `json:"prepayment"`
+	PromoCode  string        `json:"promo_code" example:"FIRSTVISIT20"`
+	// Pay with a credit of a package or membership of the client instead of a payment
+	UseCredit bool `json:"use_credit" example:"false"`
+	// Let the server pick the employee free at the start time, by the assignment strategy of the service
+	AnyEmployee bool `json:"any_employee" example:"false"`
+	// Dependent of the client attending, the client itself when empty
+	DependentID *uuid.UUID `json:"dependent_id" example:"00000000-0000-0000-0000-000000000000"`
+	// Answers to the intake forms of the service, the client is emailed a link to the others
+	Intake []IntakeSubmission `json:"intake"`
+	// Loyalty points to redeem as a discount, only those the program allows on the price are used
+	RedeemPoints int64 `json:"redeem_points" example:"200"`
 }
 
 type UpdateAppointment struct {
@@ -26,6 +39,7 @@ type Appointment struct {
 	ServiceID             uuid.UUID                `json:"service_id" example:"00000000-0000-0000-0000-000000000000"`
 	EmployeeID            uuid.UUID                `json:"employee_id" example:"00000000-0000-0000-0000-000000000000"`
 	ClientID              uuid.UUID                `json:"client_id" example:"00000000-0000-0000-0000-000000000000"`
+	DependentID           *uuid.UUID               `json:"dependent_id" example:"00000000-0000-0000-0000-000000000000"` // Dependent of the client attending, the client itself when empty
 	BranchID              uuid.UUID                `json:"branch_id" example:"00000000-0000-0000-0000-000000000000"`
 	CompanyID             uuid.UUID                `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
 	PaymentID             uuid.UUID                `json:"payment_id" example:"00000000-0000-0000-0000-000000000000"`
@@ -40,6 +54,12 @@ type Appointment struct {
 	IsCancelledByClient   bool                     `json:"is_cancelled_by_client" example:"false"`
 	IsCancelledByEmployee bool                     `json:"is_cancelled_by_employee" example:"true"`
 	IsConfirmedByClient   bool                     `json:"is_confirmed_by_client" example:"true"`
+	Price                 int64                    `json:"price" example:"180"`   // Price for the employee and branch when it was booked
+	Discount              int64                    `json:"discount" example:"36"` // Promo code discount on the price
+	PromoCodeID           *uuid.UUID               `json:"promo_code_id" example:"00000000-0000-0000-0000-000000000000"`
+	ClientPackageID       *uuid.UUID               `json:"client_package_id" example:"00000000-0000-0000-0000-000000000000"` // Package or membership whose credit paid the appointment
+	LoyaltyDiscount       int64                    `json:"loyalty_discount" example:"20"`                                    // Loyalty points redeemed on the price
+	ReceiptURL            string                   `json:"receipt_url" example:"https://cdn.example.com/appointment/receipt.pdf"`
 	History               dJSON.AppointmentHistory `json:"history"`
 	Comments              dJSON.Comments           `json:"comments"`
 }
@@ -78,6 +98,8 @@ type AppointmentBasicInfo struct {
 	IsCancelledByClient   bool      `json:"is_cancelled_by_client" example:"false"`
 	IsCancelledByEmployee bool      `json:"is_cancelled_by_employee" example:"true"`
 	IsConfirmedByClient   bool      `json:"is_confirmed_by_client" example:"true"`
+	Price                 int64     `json:"price" example:"180"`
+	Discount              int64     `json:"discount" example:"36"`
 }
 
 type AppointmentList struct {
@@ -89,3 +111,23 @@ type AppointmentList struct {
 	Page         int                    `json:"page" example:"1"`
 	PageSize     int                    `json:"page_size" example:"10"`
 }
+
+// AppointmentHistoryPage is a page of appointment changes, newest first.
+// old_value and new_value keep their JSON type as told by value_type
+// (string, number, bool, time, uuid or json).
+type AppointmentHistoryPage struct {
+	Changes    []dJSON.FieldChange `json:"changes"`
+	TotalCount int                 `json:"total_count" example:"100"`
+	Page       int                 `json:"page" example:"1"`
+	PageSize   int                 `json:"page_size" example:"10"`
+}
+
+type AppointmentReceipt struct {
+	AppointmentID uuid.UUID `json:"appointment_id" example:"00000000-0000-0000-0000-000000000000"`
+	Number        string    `json:"number" example:"20300110-1A2B3C4D"`
+	IssuedAt      string    `json:"issued_at" example:"2030-01-10T10:30:00Z"`
+	Total         int64     `json:"total" example:"144"` // Price less the discount
+	Paid          int64     `json:"paid" example:"144"`  // Paid online when booking
+	Currency      string    `json:"currency" example:"BRL"`
+	URL           string    `json:"url" example:"https://cdn.mynute.app/appointment/00000000-0000-0000-0000-000000000000/receipt-20300110-1A2B3C4D.pdf"`
+}
@@ -0,0 +1,50 @@
+package DTO
+
+import "github.com/google/uuid"
+
+type CreateBookableResource struct {
+	Name     string `json:"name" example:"Laser machine"`
+	Kind     string `json:"kind" example:"EQUIPMENT"` // ROOM, CHAIR or EQUIPMENT
+	Quantity uint32 `json:"quantity" example:"1"`     // Interchangeable units the branch has
+}
+
+type UpdateBookableResource struct {
+	Name     *string `json:"name" example:"Massage room"`
+	Kind     *string `json:"kind" example:"ROOM"`
+	Quantity *uint32 `json:"quantity" example:"3"`
+}
+
+// @description	Bookable resource DTO
+// @name			BookableResourceDTO
+// @tag.name		bookable_resource.dto
+type BookableResource struct {
+	ID       uuid.UUID `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	BranchID uuid.UUID `json:"branch_id" example:"00000000-0000-0000-0000-000000000000"`
+	Name     string    `json:"name" example:"Massage room"`
+	Kind     string    `json:"kind" example:"ROOM"`
+	Quantity uint32    `json:"quantity" example:"3"`
+}
+
+type BookableResourceList struct {
+	Resources []BookableResource `json:"resources"`
+}
+
+type AddServiceResource struct {
+	ResourceID uuid.UUID `json:"resource_id" example:"00000000-0000-0000-0000-000000000000"`
+	Quantity   uint32    `json:"quantity" example:"1"` // Units an appointment holds, 1 when empty
+}
+
+// @description	Resource a service holds while it runs
+// @name			ServiceResourceRequirementDTO
+// @tag.name		service.resource.dto
+type ServiceResourceRequirement struct {
+	ResourceID uuid.UUID `json:"resource_id" example:"00000000-0000-0000-0000-000000000000"`
+	BranchID   uuid.UUID `json:"branch_id" example:"00000000-0000-0000-0000-000000000000"`
+	Name       string    `json:"name" example:"Massage room"`
+	Quantity   uint32    `json:"quantity" example:"1"` // Units an appointment holds
+	Capacity   uint32    `json:"capacity" example:"3"` // Units the branch has
+}
+
+type ServiceResourceRequirementList struct {
+	Resources []ServiceResourceRequirement `json:"resources"`
+}
@@ -0,0 +1,9 @@
+package DTO
+
+// BookingWindow limits how soon and how far ahead appointments can be booked.
+// Empty fields on a service fall back to its branch, then to the company.
+type BookingWindow struct {
+	MinNoticeMinutes *uint32 `json:"min_notice_minutes" example:"120"` // Minimum advance notice
+	MaxDaysAhead     *uint16 `json:"max_days_ahead" example:"60"`      // Last day that can be booked, counted from today, 0 for today only
+	SameDayCutoff    *string `json:"same_day_cutoff" example:"12:00"`  // HH:MM local time from which today can no longer be booked
+}
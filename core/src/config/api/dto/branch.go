@@ -21,6 +21,7 @@ type CreateBranch struct {
 	State        string    `json:"state" example:"NY"`
 	Country      string    `json:"country" example:"USA"`
 	TimeZone     string    `json:"time_zone" example:"America/New_York"` // Time zone in IANA format
+	BookingWindow
 }
 
 // @description	Branch Update DTO
@@ -60,6 +61,8 @@ type BranchBase struct {
 	TimeZone            string       `json:"time_zone" example:"America/New_York"` // Time zone in IANA format
 	TotalServiceDensity int32        `json:"total_service_density" example:"100"`
 	Design              dJSON.Design `json:"design"`
+	BookingWindow
+	Rating RatingSummary `json:"rating"` // Of the published reviews
 }
 
 type ServiceDensity struct {
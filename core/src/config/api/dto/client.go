@@ -17,6 +17,17 @@ type CreateClient struct {
 	Password string `json:"password" example:"1SecurePswd!"`
 }
 
+type CreateGuestClient struct {
+	Name    string `json:"name" example:"John"`
+	Surname string `json:"surname" example:"Doe"`
+	Email   string `json:"email" example:"john.doe@example.com"`
+	Phone   string `json:"phone" example:"+15555555555"`
+}
+
+type UpgradeGuestClient struct {
+	Password string `json:"password" example:"1SecurePswd!"`
+}
+
 type Client struct {
 	ID       uuid.UUID `json:"id" example:"00000000-0000-0000-0000-000000000000"`
 	Name     string    `json:"name" example:"John"`
@@ -24,6 +35,7 @@ type Client struct {
 	Email    string    `json:"email" example:"john.doe@example.com"`
 	Phone    string    `json:"phone" example:"+15555555555"`
 	Verified bool      `json:"verified" example:"false"`
+	IsGuest  bool      `json:"is_guest" example:"false"` // Booked without an account
 }
 
 type ClientPopulated struct {
@@ -0,0 +1,28 @@
+package DTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// @description	Data export or erasure request of a client, and its audit trail
+// @name			ClientDataRequestDTO
+// @tag.name		client_data_request.dto
+type ClientDataRequest struct {
+	ID              uuid.UUID   `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	ClientID        uuid.UUID   `json:"client_id" example:"00000000-0000-0000-0000-000000000000"`
+	Kind            string      `json:"kind" example:"EXPORT" enums:"EXPORT,ERASURE"`
+	Status          string      `json:"status" example:"PENDING" enums:"PENDING,COMPLETED"`
+	RequestedByID   *uuid.UUID  `json:"requested_by_id" example:"00000000-0000-0000-0000-000000000000"`
+	RequestedByType string      `json:"requested_by_type" example:"client"`
+	RequestIP       string      `json:"request_ip" example:"203.0.113.7"`
+	CompanyIDs      []uuid.UUID `json:"company_ids"` // Companies the request reached, set when completed
+	CreatedAt       time.Time   `json:"created_at" example:"2028-03-01T12:00:00Z"`
+	CompletedAt     *time.Time  `json:"completed_at" example:"2028-03-01T12:00:05Z"`
+	ExpiresAt       *time.Time  `json:"expires_at" example:"2028-03-08T12:00:05Z"` // Exports only, when the download stops being available
+}
+
+type ClientDataRequestList struct {
+	DataRequests []ClientDataRequest `json:"data_requests"`
+}
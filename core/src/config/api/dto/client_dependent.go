@@ -0,0 +1,34 @@
+package DTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateClientDependent struct {
+	Name      string     `json:"name" example:"Maria"`
+	BirthDate *time.Time `json:"birth_date" example:"2018-04-02T00:00:00Z"`
+	Notes     string     `json:"notes" example:"Allergic to latex"`
+}
+
+type UpdateClientDependent struct {
+	Name      *string    `json:"name" example:"Maria"`
+	BirthDate *time.Time `json:"birth_date" example:"2018-04-02T00:00:00Z"`
+	Notes     *string    `json:"notes" example:"Allergic to latex"`
+}
+
+// @description	Someone a client books for, without an account of their own
+// @name			ClientDependentDTO
+// @tag.name		client_dependent.dto
+type ClientDependent struct {
+	ID        uuid.UUID  `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	ClientID  uuid.UUID  `json:"client_id" example:"00000000-0000-0000-0000-000000000000"`
+	Name      string     `json:"name" example:"Maria"`
+	BirthDate *time.Time `json:"birth_date" example:"2018-04-02T00:00:00Z"`
+	Notes     string     `json:"notes" example:"Allergic to latex"`
+}
+
+type ClientDependentList struct {
+	Dependents []ClientDependent `json:"dependents"`
+}
@@ -0,0 +1,53 @@
+package DTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UpdateClientProfile struct {
+	Name             *string    `json:"name" example:"Johnny"`        // How the company knows the client, empty to use the client's own
+	Surname          *string    `json:"surname" example:"Doe"`        // How the company knows the client, empty to use the client's own
+	Phone            *string    `json:"phone" example:"+15555555555"` // Number the company reaches the client at, empty to use the client's own
+	Tags             *[]string  `json:"tags"`                         // Replaces every tag of the client
+	Notes            *string    `json:"notes" example:"Prefers to be called by the surname"`
+	Preferences      *string    `json:"preferences" example:"Allergic to lavender"`
+	Birthday         *time.Time `json:"birthday" example:"1990-05-17T00:00:00Z"`
+	MarketingConsent *bool      `json:"marketing_consent" example:"true"`
+}
+
+type ClientStats struct {
+	Appointments      int64      `json:"appointments" example:"12"`
+	Fulfilled         int64      `json:"fulfilled" example:"10"`
+	Cancelled         int64      `json:"cancelled" example:"2"`
+	TotalSpent        int64      `json:"total_spent" example:"85000"` // In cents
+	FirstVisitAt      *time.Time `json:"first_visit_at" example:"2027-01-10T14:00:00Z"`
+	LastVisitAt       *time.Time `json:"last_visit_at" example:"2028-02-20T10:00:00Z"`
+	NextAppointmentAt *time.Time `json:"next_appointment_at" example:"2028-03-05T09:30:00Z"`
+}
+
+// @description	What a company keeps about a client
+// @name			ClientProfileDTO
+// @tag.name		client_profile.dto
+type ClientProfile struct {
+	ID                 uuid.UUID       `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	Client             ClientBasicInfo `json:"client"`
+	Name               string          `json:"name" example:"Johnny"` // How the company knows the client, empty to use the client's own
+	Surname            string          `json:"surname" example:"Doe"`
+	Phone              string          `json:"phone" example:"+15555555555"`
+	Tags               []string        `json:"tags" example:"vip"`
+	Notes              string          `json:"notes" example:"Prefers to be called by the surname"`
+	Preferences        string          `json:"preferences" example:"Allergic to lavender"`
+	Birthday           *time.Time      `json:"birthday" example:"1990-05-17T00:00:00Z"`
+	MarketingConsent   bool            `json:"marketing_consent" example:"true"`
+	MarketingConsentAt *time.Time      `json:"marketing_consent_at" example:"2028-01-01T09:00:00Z"`
+	Stats              ClientStats     `json:"stats"`
+}
+
+type ClientProfileList struct {
+	Clients    []ClientProfile `json:"clients"`
+	TotalCount int             `json:"total_count" example:"100"`
+	Page       int             `json:"page" example:"1"`
+	PageSize   int             `json:"page_size" example:"10"`
+}
@@ -40,4 +40,7 @@ type CompanyBase struct {
 	Design     dJSON.Design `json:"design"`
 	Sectors    []*Sector    `json:"sectors"`
 	Subdomains []*Subdomain `json:"subdomains"`
+	PixKey     string       `json:"pix_key" example:"pix@yourcompany.com"`
+	PixCity    string       `json:"pix_city" example:"Sao Paulo"`
+	BookingWindow
 }
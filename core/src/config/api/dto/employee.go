@@ -55,6 +55,7 @@ type CreateEmployee struct {
 type EmployeeFull struct {
 	EmployeeBase
 	Verified             bool                `json:"verified" example:"true"`
+	Status               string              `json:"status" example:"ACTIVE"` // INVITED, ACTIVE or SUSPENDED
 	Email                string              `json:"email" example:"john.doe@example.com"`
 	Phone                string              `json:"phone" example:"+15555555555"`
 	EmployeeWorkSchedule []EmployeeWorkRange `json:"work_schedule"`
@@ -74,4 +75,5 @@ type EmployeeBase struct {
 	TimeZone            string         `json:"time_zone" example:"America/Sao_Paulo"`
 	TotalServiceDensity uint32         `json:"total_service_density" example:"100"`
 	Meta                dJSON.UserMeta `json:"meta"`
+	Rating              RatingSummary  `json:"rating"` // Of the published reviews
 }
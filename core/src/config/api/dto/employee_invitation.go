@@ -0,0 +1,54 @@
+package DTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InviteEmployee invites someone to join the company as an employee.
+type InviteEmployee struct {
+	Email      string      `json:"email" example:"john.doe@example.com"`
+	Name       string      `json:"name" example:"John"`
+	Surname    string      `json:"surname" example:"Doe"`
+	Phone      string      `json:"phone" example:"+15555555555"`
+	TimeZone   string      `json:"time_zone" example:"America/Sao_Paulo"` // Defaults to the one of the first branch
+	RoleIDs    []uuid.UUID `json:"role_ids"`
+	BranchIDs  []uuid.UUID `json:"branch_ids"`
+	ServiceIDs []uuid.UUID `json:"service_ids"`
+}
+
+// AcceptEmployeeInvitation sets the password of the invited employee.
+type AcceptEmployeeInvitation struct {
+	Password string `json:"password" example:"1SecurePswd!"`
+}
+
+type UpdateEmployeeStatus struct {
+	Status string `json:"status" example:"SUSPENDED"` // ACTIVE or SUSPENDED
+}
+
+// @description	Employee Invitation DTO
+// @name			EmployeeInvitationDTO
+// @tag.name		employee.invitation.dto
+type EmployeeInvitation struct {
+	ID          uuid.UUID  `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	CompanyID   uuid.UUID  `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	EmployeeID  uuid.UUID  `json:"employee_id" example:"00000000-0000-0000-0000-000000000000"`
+	Email       string     `json:"email" example:"john.doe@example.com"`
+	Status      string     `json:"status" example:"PENDING"` // PENDING, EXPIRED, ACCEPTED or REVOKED
+	ExpiresAt   time.Time  `json:"expires_at" example:"2028-01-08T09:00:00Z"`
+	SentCount   int        `json:"sent_count" example:"1"`
+	LastSentAt  *time.Time `json:"last_sent_at" example:"2028-01-01T09:00:00Z"`
+	InvitedByID *uuid.UUID `json:"invited_by_id" example:"00000000-0000-0000-0000-000000000000"`
+	AcceptedAt  *time.Time `json:"accepted_at" example:"2028-01-02T09:00:00Z"`
+	RevokedAt   *time.Time `json:"revoked_at" example:"2028-01-02T09:00:00Z"`
+	CreatedAt   time.Time  `json:"created_at" example:"2028-01-01T09:00:00Z"`
+}
+
+// EmployeeInvitationDetails is the invitation the employee was emailed a link to accept.
+type EmployeeInvitationDetails struct {
+	CompanyName  string    `json:"company_name" example:"Beauty Studio"`
+	EmployeeName string    `json:"employee_name" example:"John Doe"`
+	Email        string    `json:"email" example:"john.doe@example.com"`
+	ExpiresAt    time.Time `json:"expires_at" example:"2028-01-08T09:00:00Z"`
+}
@@ -0,0 +1,22 @@
+package DTO
+
+import "github.com/google/uuid"
+
+// @description	Import result DTO
+// @name			ImportResultDTO
+// @tag.name		import.result.dto
+type ImportResult struct {
+	Kind    string      `json:"kind" example:"employees"` // services, employees or clients
+	DryRun  bool        `json:"dry_run" example:"true"`   // Nothing was saved
+	Created int         `json:"created" example:"12"`
+	Updated int         `json:"updated" example:"3"`
+	Failed  int         `json:"failed" example:"1"`
+	Rows    []ImportRow `json:"rows"`
+}
+
+type ImportRow struct {
+	Line   int        `json:"line" example:"2"`                                            // Line in the file, the header being line 1
+	Action string     `json:"action" example:"CREATED"`                                    // CREATED, UPDATED or FAILED
+	ID     *uuid.UUID `json:"id" example:"00000000-0000-0000-0000-000000000000"`           // Of the record created or updated
+	Errors []string   `json:"errors" example:"Invalid import row,branch Centro not found"` // Why the row failed
+}
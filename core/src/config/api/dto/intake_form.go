@@ -0,0 +1,106 @@
+package DTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IntakeField struct {
+	Key      string   `json:"key" example:"allergies"` // snake_case, unique in the form
+	Label    string   `json:"label" example:"Do you have any allergies?"`
+	Type     string   `json:"type" example:"LONG_TEXT"` // TEXT, LONG_TEXT, NUMBER, DATE, BOOLEAN, CHOICE or MULTI_CHOICE
+	Required bool     `json:"required" example:"true"`
+	Options  []string `json:"options,omitempty"` // Choices of CHOICE and MULTI_CHOICE fields
+	Help     string   `json:"help,omitempty" example:"Include medicines and cosmetics"`
+}
+
+type CreateIntakeForm struct {
+	CompanyID   uuid.UUID     `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	Name        string        `json:"name" example:"Skin treatment consent"`
+	Description string        `json:"description" example:"Asked before the first peeling"`
+	ServiceIDs  []uuid.UUID   `json:"service_ids"` // Services asking for the form
+	Fields      []IntakeField `json:"fields"`
+	IsConsent   bool          `json:"is_consent" example:"true"` // Signed by the client and kept as a PDF
+	ConsentText string        `json:"consent_text" example:"I understand the risks of the treatment..."`
+}
+
+type UpdateIntakeForm struct {
+	Name        *string        `json:"name" example:"Skin treatment consent"`
+	Description *string        `json:"description" example:"Asked before the first peeling"`
+	ServiceIDs  *[]uuid.UUID   `json:"service_ids"`
+	Fields      *[]IntakeField `json:"fields"` // Makes a new version
+	IsConsent   *bool          `json:"is_consent" example:"true"`
+	ConsentText *string        `json:"consent_text" example:"I understand the risks of the treatment..."` // Makes a new version
+	IsActive    *bool          `json:"is_active" example:"true"`
+}
+
+// @description	Intake form DTO
+// @name			IntakeFormDTO
+// @tag.name		intake_form.dto
+type IntakeForm struct {
+	ID          uuid.UUID     `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	CompanyID   uuid.UUID     `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	Name        string        `json:"name" example:"Skin treatment consent"`
+	Description string        `json:"description" example:"Asked before the first peeling"`
+	ServiceIDs  []uuid.UUID   `json:"service_ids"`
+	Fields      []IntakeField `json:"fields"`
+	IsConsent   bool          `json:"is_consent" example:"true"`
+	ConsentText string        `json:"consent_text" example:"I understand the risks of the treatment..."`
+	Version     int           `json:"version" example:"2"` // Current version, answers keep the version they were given to
+	IsActive    bool          `json:"is_active" example:"true"`
+}
+
+type IntakeFormList struct {
+	IntakeForms []IntakeForm `json:"intake_forms"`
+}
+
+// IntakeFormVersion is a form as it was when answered.
+type IntakeFormVersion struct {
+	ID          uuid.UUID     `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	FormID      uuid.UUID     `json:"form_id" example:"00000000-0000-0000-0000-000000000000"`
+	Version     int           `json:"version" example:"2"`
+	Name        string        `json:"name" example:"Skin treatment consent"`
+	Fields      []IntakeField `json:"fields"`
+	IsConsent   bool          `json:"is_consent" example:"true"`
+	ConsentText string        `json:"consent_text" example:"I understand the risks of the treatment..."`
+}
+
+// IntakeSubmission answers a form when booking.
+type IntakeSubmission struct {
+	FormID     uuid.UUID      `json:"form_id" example:"00000000-0000-0000-0000-000000000000"`
+	Answers    map[string]any `json:"answers"`                          // By field key
+	SignedName string         `json:"signed_name" example:"John Clark"` // Full name of the client, required by consent forms
+}
+
+// SubmitIntakeForm answers a form through the emailed link.
+type SubmitIntakeForm struct {
+	Answers    map[string]any `json:"answers"`                          // By field key
+	SignedName string         `json:"signed_name" example:"John Clark"` // Full name of the client, required by consent forms
+}
+
+// @description	Intake response DTO
+// @name			IntakeResponseDTO
+// @tag.name		intake_response.dto
+type IntakeResponse struct {
+	ID            uuid.UUID         `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	FormID        uuid.UUID         `json:"form_id" example:"00000000-0000-0000-0000-000000000000"`
+	Version       int               `json:"version" example:"2"`
+	Form          IntakeFormVersion `json:"form"`
+	ClientID      uuid.UUID         `json:"client_id" example:"00000000-0000-0000-0000-000000000000"`
+	DependentID   *uuid.UUID        `json:"dependent_id" example:"00000000-0000-0000-0000-000000000000"`
+	AppointmentID *uuid.UUID        `json:"appointment_id" example:"00000000-0000-0000-0000-000000000000"`
+	Status        string            `json:"status" example:"COMPLETED"` // PENDING or COMPLETED
+	Answers       map[string]any    `json:"answers"`
+	SignedName    string            `json:"signed_name" example:"John Clark"`
+	SignedAt      *time.Time        `json:"signed_at" example:"2028-01-01T09:00:00Z"`
+	SignatureIP   string            `json:"signature_ip" example:"203.0.113.7"`
+	ConsentURL    string            `json:"consent_url" example:"https://cdn.mynute.app/intake_response/consent.pdf"`
+	CompletedAt   *time.Time        `json:"completed_at" example:"2028-01-01T09:00:00Z"`
+}
+
+type AppointmentIntake struct {
+	AppointmentID uuid.UUID        `json:"appointment_id" example:"00000000-0000-0000-0000-000000000000"`
+	Complete      bool             `json:"complete" example:"false"` // Every form asked by the service was answered
+	Responses     []IntakeResponse `json:"responses"`
+}
@@ -1,13 +1,24 @@
 package dJSON
 
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
 type AppointmentHistory struct {
 	FieldChanges []FieldChange `json:"field_changes"`
 }
 
 type FieldChange struct {
-	CreatedAt string `json:"created_at" example:"2021-01-01T09:00:00Z"`
-	Field     string `json:"field" example:"field_name"`
-	OldValue  string `json:"old_value" example:"old_value"`
-	NewValue  string `json:"new_value" example:"new_value"`
-	Reason    string `json:"reason" example:"Some reason."`
+	CreatedAt string          `json:"created_at" example:"2021-01-01T09:00:00Z"`
+	Field     string          `json:"field" example:"StartTime"`
+	ValueType string          `json:"value_type" example:"time"`
+	OldValue  json.RawMessage `json:"old_value" swaggertype:"string" example:"2021-01-01T09:00:00Z"`
+	NewValue  json.RawMessage `json:"new_value" swaggertype:"string" example:"2021-01-01T10:00:00Z"`
+	Reason    string          `json:"reason" example:"Some reason."`
+	ActorID   *uuid.UUID      `json:"actor_id" example:"00000000-0000-0000-0000-000000000000"`
+	ActorType string          `json:"actor_type" example:"employee"`
+	Endpoint  string          `json:"endpoint" example:"PATCH /appointment/:id"`
+	IP        string          `json:"ip" example:"203.0.113.7"`
 }
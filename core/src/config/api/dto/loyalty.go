@@ -0,0 +1,75 @@
+package DTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoyaltyEarning is how many points a fulfilled appointment earns.
+type LoyaltyEarning struct {
+	PointsPerAppointment int64 `json:"points_per_appointment" example:"10"`
+	PointsPerAmount      int64 `json:"points_per_amount" example:"1"`
+	AmountUnit           int64 `json:"amount_unit" example:"1000"` // In cents, e.g. 1000 for one point every 10.00 spent
+}
+
+type LoyaltyRule struct {
+	ServiceID uuid.UUID `json:"service_id" example:"00000000-0000-0000-0000-000000000000"`
+	LoyaltyEarning
+	Excluded bool `json:"excluded" example:"false"` // The service earns no points
+}
+
+// SaveLoyaltyProgram creates the loyalty program of the company or replaces its settings.
+type SaveLoyaltyProgram struct {
+	IsActive bool `json:"is_active" example:"true"`
+	LoyaltyEarning
+	Rules            []LoyaltyRule `json:"rules"`                           // By service, services without a rule use the program earning
+	PointValue       int64         `json:"point_value" example:"5"`         // Discount in cents of one point
+	MinRedeemPoints  int64         `json:"min_redeem_points" example:"100"` // Fewest points redeemed at once
+	MaxRedeemPercent int64         `json:"max_redeem_percent" example:"50"` // Share of the price that can be paid with points
+}
+
+// @description	Loyalty program DTO
+// @name			LoyaltyProgramDTO
+// @tag.name		loyalty_program.dto
+type LoyaltyProgram struct {
+	ID        uuid.UUID `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	CompanyID uuid.UUID `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	IsActive  bool      `json:"is_active" example:"true"`
+	LoyaltyEarning
+	Rules            []LoyaltyRule `json:"rules"`
+	PointValue       int64         `json:"point_value" example:"5"`
+	MinRedeemPoints  int64         `json:"min_redeem_points" example:"100"`
+	MaxRedeemPercent int64         `json:"max_redeem_percent" example:"50"`
+}
+
+// AdjustLoyaltyPoints grants points to a client, or removes them when negative.
+type AdjustLoyaltyPoints struct {
+	Points      int64  `json:"points" example:"50"`
+	Description string `json:"description" example:"Compensation for the delay"`
+}
+
+type LoyaltyTransaction struct {
+	ID            uuid.UUID  `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	Type          string     `json:"type" example:"EARN"` // EARN, REDEEM, REVERSAL, RESTORE or ADJUSTMENT
+	Points        int64      `json:"points" example:"25"` // Negative when debited
+	BalanceAfter  int64      `json:"balance_after" example:"125"`
+	AppointmentID *uuid.UUID `json:"appointment_id" example:"00000000-0000-0000-0000-000000000000"`
+	Description   string     `json:"description" example:"Fulfilled appointment"`
+	CreatedByID   *uuid.UUID `json:"created_by_id" example:"00000000-0000-0000-0000-000000000000"`
+	CreatedAt     time.Time  `json:"created_at" example:"2028-01-01T09:00:00Z"`
+}
+
+// LoyaltyBalance is the points balance of a client at a company, with its ledger newest first.
+type LoyaltyBalance struct {
+	CompanyID    uuid.UUID            `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	ClientID     uuid.UUID            `json:"client_id" example:"00000000-0000-0000-0000-000000000000"`
+	Balance      int64                `json:"balance" example:"125"` // May be negative when earned points were reversed after being redeemed
+	Earned       int64                `json:"earned" example:"300"`
+	Redeemed     int64                `json:"redeemed" example:"175"`
+	Value        int64                `json:"value" example:"625"` // Discount in cents the balance is worth, 0 without an active program
+	Transactions []LoyaltyTransaction `json:"transactions"`
+	TotalCount   int                  `json:"total_count" example:"100"`
+	Page         int                  `json:"page" example:"1"`
+	PageSize     int                  `json:"page_size" example:"10"`
+}
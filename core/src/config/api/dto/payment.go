@@ -0,0 +1,51 @@
+package DTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentInput is how the client pays the prepayment of a booking.
+type PaymentInput struct {
+	Method string `json:"method" example:"CREDIT_CARD"`
+	Token  string `json:"token" example:"tok_visa"` // Token issued by the payment provider for the client's card or wallet
+}
+
+// @description	Payment DTO
+// @name			PaymentDTO
+// @tag.name		payment.dto
+type Payment struct {
+	ID             uuid.UUID  `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	Price          int64      `json:"price" example:"50"`
+	Currency       string     `json:"currency" example:"BRL"`
+	Status         string     `json:"status" example:"COMPLETED"`
+	Type           string     `json:"type" example:"DEPOSIT"`
+	PaymentMethod  string     `json:"payment_method" example:"CREDIT_CARD"`
+	TransactionID  *string    `json:"transaction_id" example:"fake_00000000-0000-0000-0000-000000000000"`
+	Provider       string     `json:"provider" example:"fake"`
+	FailureReason  string     `json:"failure_reason" example:""`
+	PixPayload     string     `json:"pix_payload" example:"00020126...6304ABCD"`
+	PixQRCodeURL   string     `json:"pix_qr_code_url" example:"https://cdn.mynute.app/payment/00000000-0000-0000-0000-000000000000/pix-qr.png"`
+	CompanyID      uuid.UUID  `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	ClientID       uuid.UUID  `json:"client_id" example:"00000000-0000-0000-0000-000000000000"`
+	AppointmentID  *uuid.UUID `json:"appointment_id" example:"00000000-0000-0000-0000-000000000000"`
+	RefundedAmount int64      `json:"refunded_amount" example:"0"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	FailedAt       *time.Time `json:"failed_at"`
+	RefundedAt     *time.Time `json:"refunded_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PixCharge is what the client needs to pay a Pix payment.
+type PixCharge struct {
+	PaymentID uuid.UUID `json:"payment_id" example:"00000000-0000-0000-0000-000000000000"`
+	Amount    int64     `json:"amount" example:"50"`
+	Status    string    `json:"status" example:"PENDING"`
+	Payload   string    `json:"payload" example:"00020126...6304ABCD"` // "Copia e cola" code
+	QRCodeURL string    `json:"qr_code_url" example:"https://cdn.mynute.app/payment/00000000-0000-0000-0000-000000000000/pix-qr.png"`
+}
+
+type RefundPayment struct {
+	Amount int64 `json:"amount" example:"50"` // Defaults to everything not refunded yet
+}
@@ -0,0 +1,64 @@
+package DTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreatePromoCode struct {
+	CompanyID        uuid.UUID   `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	Code             string      `json:"code" example:"FIRSTVISIT20"`
+	Description      string      `json:"description" example:"20% off the first visit"`
+	DiscountType     string      `json:"discount_type" example:"PERCENTAGE"` // PERCENTAGE or FIXED
+	DiscountValue    int64       `json:"discount_value" example:"20"`        // Percentage from 1 to 100, or amount in cents
+	StartsAt         *time.Time  `json:"starts_at" example:"2028-03-01T00:00:00Z"`
+	EndsAt           *time.Time  `json:"ends_at" example:"2028-04-01T00:00:00Z"`
+	MaxUses          int64       `json:"max_uses" example:"100"`          // 0 is unlimited
+	MaxUsesPerClient int64       `json:"max_uses_per_client" example:"1"` // 0 is unlimited
+	FirstVisitOnly   bool        `json:"first_visit_only" example:"true"`
+	ServiceIDs       []uuid.UUID `json:"service_ids"`  // Empty allows all services
+	BranchIDs        []uuid.UUID `json:"branch_ids"`   // Empty allows all branches
+	EmployeeIDs      []uuid.UUID `json:"employee_ids"` // Empty allows all employees
+}
+
+type UpdatePromoCode struct {
+	Description      *string      `json:"description" example:"20% off the first visit"`
+	DiscountType     *string      `json:"discount_type" example:"FIXED"`
+	DiscountValue    *int64       `json:"discount_value" example:"3000"`
+	StartsAt         *time.Time   `json:"starts_at" example:"2028-03-01T00:00:00Z"`
+	EndsAt           *time.Time   `json:"ends_at" example:"2028-04-01T00:00:00Z"`
+	MaxUses          *int64       `json:"max_uses" example:"100"`
+	MaxUsesPerClient *int64       `json:"max_uses_per_client" example:"1"`
+	FirstVisitOnly   *bool        `json:"first_visit_only" example:"false"`
+	ServiceIDs       *[]uuid.UUID `json:"service_ids"`
+	BranchIDs        *[]uuid.UUID `json:"branch_ids"`
+	EmployeeIDs      *[]uuid.UUID `json:"employee_ids"`
+	IsActive         *bool        `json:"is_active" example:"true"`
+}
+
+// @description	Promo code DTO
+// @name			PromoCodeDTO
+// @tag.name		promo_code.dto
+type PromoCode struct {
+	ID               uuid.UUID   `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	CompanyID        uuid.UUID   `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	Code             string      `json:"code" example:"FIRSTVISIT20"`
+	Description      string      `json:"description" example:"20% off the first visit"`
+	DiscountType     string      `json:"discount_type" example:"PERCENTAGE"`
+	DiscountValue    int64       `json:"discount_value" example:"20"`
+	StartsAt         *time.Time  `json:"starts_at" example:"2028-03-01T00:00:00Z"`
+	EndsAt           *time.Time  `json:"ends_at" example:"2028-04-01T00:00:00Z"`
+	MaxUses          int64       `json:"max_uses" example:"100"`
+	MaxUsesPerClient int64       `json:"max_uses_per_client" example:"1"`
+	FirstVisitOnly   bool        `json:"first_visit_only" example:"true"`
+	ServiceIDs       []uuid.UUID `json:"service_ids"`
+	BranchIDs        []uuid.UUID `json:"branch_ids"`
+	EmployeeIDs      []uuid.UUID `json:"employee_ids"`
+	IsActive         bool        `json:"is_active" example:"true"`
+	Uses             int64       `json:"uses" example:"12"` // Appointments booked with the code that are not cancelled
+}
+
+type PromoCodeList struct {
+	PromoCodes []PromoCode `json:"promo_codes"`
+}
@@ -0,0 +1,67 @@
+package DTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RatingSummary aggregates the published reviews of an employee, service or branch.
+type RatingSummary struct {
+	Average float64 `json:"average" example:"4.75"` // 0 without reviews
+	Count   int64   `json:"count" example:"12"`
+}
+
+// SubmitReview answers a review through the emailed link.
+type SubmitReview struct {
+	Rating         int    `json:"rating" example:"5"`          // 1 to 5
+	EmployeeRating *int   `json:"employee_rating" example:"5"` // Optional, 1 to 5
+	ServiceRating  *int   `json:"service_rating" example:"4"`  // Optional, 1 to 5
+	Comment        string `json:"comment" example:"Great service, very attentive staff"`
+}
+
+type ModerateReview struct {
+	Status         string `json:"status" example:"PUBLISHED"` // PUBLISHED or REJECTED
+	ModerationNote string `json:"moderation_note" example:"Offensive language"`
+}
+
+// @description	Review DTO
+// @name			ReviewDTO
+// @tag.name		review.dto
+type Review struct {
+	ID             uuid.UUID  `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	CompanyID      uuid.UUID  `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	AppointmentID  uuid.UUID  `json:"appointment_id" example:"00000000-0000-0000-0000-000000000000"`
+	ClientID       uuid.UUID  `json:"client_id" example:"00000000-0000-0000-0000-000000000000"`
+	BranchID       uuid.UUID  `json:"branch_id" example:"00000000-0000-0000-0000-000000000000"`
+	EmployeeID     uuid.UUID  `json:"employee_id" example:"00000000-0000-0000-0000-000000000000"`
+	ServiceID      uuid.UUID  `json:"service_id" example:"00000000-0000-0000-0000-000000000000"`
+	Status         string     `json:"status" example:"PENDING"` // INVITED, PENDING, PUBLISHED or REJECTED
+	Rating         int        `json:"rating" example:"5"`       // 0 while invited
+	EmployeeRating *int       `json:"employee_rating" example:"5"`
+	ServiceRating  *int       `json:"service_rating" example:"4"`
+	Comment        string     `json:"comment" example:"Great service, very attentive staff"`
+	SubmittedAt    *time.Time `json:"submitted_at" example:"2028-01-01T09:00:00Z"`
+	ModeratedByID  *uuid.UUID `json:"moderated_by_id" example:"00000000-0000-0000-0000-000000000000"`
+	ModeratedAt    *time.Time `json:"moderated_at" example:"2028-01-01T09:00:00Z"`
+	ModerationNote string     `json:"moderation_note" example:"Offensive language"`
+	CreatedAt      time.Time  `json:"created_at" example:"2028-01-01T09:00:00Z"` // When the client was invited
+}
+
+type ReviewList struct {
+	Reviews    []Review `json:"reviews"`
+	TotalCount int      `json:"total_count" example:"100"`
+	Page       int      `json:"page" example:"1"`
+	PageSize   int      `json:"page_size" example:"10"`
+}
+
+// ReviewInvitation is the appointment the client was emailed a link to review.
+type ReviewInvitation struct {
+	ID           uuid.UUID `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	CompanyName  string    `json:"company_name" example:"Beauty Studio"`
+	ServiceName  string    `json:"service_name" example:"Haircut"`
+	EmployeeName string    `json:"employee_name" example:"John Doe"`
+	StartTime    time.Time `json:"start_time" example:"2028-01-01T09:00:00Z"`
+	TimeZone     string    `json:"time_zone" example:"America/Sao_Paulo"`
+	Status       string    `json:"status" example:"INVITED"` // PENDING once answered
+}
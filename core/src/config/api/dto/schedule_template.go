@@ -0,0 +1,76 @@
+package DTO
+
+import "github.com/google/uuid"
+
+type ScheduleTemplateRange struct {
+	Weekday   uint8  `json:"weekday" example:"1"`                       // Weekday (0 = Sunday, 1 = Monday, ..., 6 = Saturday)
+	StartTime string `json:"start_time" example:"09:00" format:"HH:mm"` // Start time in the time zone of the branch
+	EndTime   string `json:"end_time" example:"17:00" format:"HH:mm"`   // End time, before the start for ranges spanning midnight
+}
+
+type CreateScheduleTemplate struct {
+	CompanyID   uuid.UUID               `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	Name        string                  `json:"name" example:"Morning shift"`
+	Description string                  `json:"description" example:"Monday to Friday, 08:00 to 14:00"`
+	Ranges      []ScheduleTemplateRange `json:"ranges"`
+}
+
+type UpdateScheduleTemplate struct {
+	Name        *string                  `json:"name" example:"Morning shift"`
+	Description *string                  `json:"description" example:"Monday to Friday, 08:00 to 14:00"`
+	Ranges      *[]ScheduleTemplateRange `json:"ranges"` // Replaces every range of the template
+}
+
+// @description	Schedule template DTO
+// @name			ScheduleTemplateDTO
+// @tag.name		schedule_template.dto
+type ScheduleTemplate struct {
+	ID          uuid.UUID               `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	CompanyID   uuid.UUID               `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	Name        string                  `json:"name" example:"Morning shift"`
+	Description string                  `json:"description" example:"Monday to Friday, 08:00 to 14:00"`
+	Ranges      []ScheduleTemplateRange `json:"ranges"`
+}
+
+type ScheduleTemplateList struct {
+	ScheduleTemplates []ScheduleTemplate `json:"schedule_templates"`
+}
+
+type ApplyScheduleTemplate struct {
+	BranchID    uuid.UUID   `json:"branch_id" example:"00000000-0000-0000-0000-000000000000"` // Branch the employees work at, required with employee_ids
+	EmployeeIDs []uuid.UUID `json:"employee_ids"`                                             // Employees getting the template as work schedule
+	BranchIDs   []uuid.UUID `json:"branch_ids"`                                               // Branches getting the template as opening hours
+	Replace     bool        `json:"replace" example:"true"`                                   // Remove the current ranges at the branch first
+	Preview     bool        `json:"preview" example:"true"`                                   // Only return the changes, without saving them
+}
+
+type CopyEmployeeWorkSchedule struct {
+	FromEmployeeID uuid.UUID `json:"from_employee_id" example:"00000000-0000-0000-0000-000000000000"` // Employee the schedule is copied from
+	BranchID       uuid.UUID `json:"branch_id" example:"00000000-0000-0000-0000-000000000000"`        // Only copy the ranges at this branch, all when empty
+	Replace        bool      `json:"replace" example:"true"`                                          // Remove the current ranges at the copied branches first
+	Preview        bool      `json:"preview" example:"true"`                                          // Only return the changes, without saving them
+}
+
+type ScheduleDiffRange struct {
+	BranchID  uuid.UUID `json:"branch_id" example:"00000000-0000-0000-0000-000000000000"`
+	Weekday   uint8     `json:"weekday" example:"1"`
+	StartTime string    `json:"start_time" example:"09:00" format:"HH:mm"`
+	EndTime   string    `json:"end_time" example:"17:00" format:"HH:mm"`
+	TimeZone  string    `json:"time_zone" example:"America/Sao_Paulo"`
+}
+
+type ScheduleTargetDiff struct {
+	EmployeeID *uuid.UUID          `json:"employee_id,omitempty" example:"00000000-0000-0000-0000-000000000000"` // Empty when the target is the opening hours of a branch
+	BranchID   uuid.UUID           `json:"branch_id" example:"00000000-0000-0000-0000-000000000000"`
+	Added      []ScheduleDiffRange `json:"added"`
+	Removed    []ScheduleDiffRange `json:"removed"`
+	Error      string              `json:"error,omitempty"` // Why the ranges of the target are invalid
+}
+
+// @description	Changes to the work schedules, saved when applied is true
+// @name			ScheduleDiffDTO
+// @tag.name		schedule_template.diff
+type ScheduleDiff struct {
+	Applied bool                 `json:"applied" example:"false"`
+	Targets []ScheduleTargetDiff `json:"targets"`
+}
@@ -12,6 +12,27 @@ type CreateService struct {
 	Description string    `json:"description" example:"A 60-minute in-depth business consultation"`
 	Price       int32     `json:"price" example:"150"`
 	Duration    uint      `json:"duration" example:"60"`
+	// LEAST_BOOKED, ROUND_ROBIN or PREFERRED, how the employee is picked when booking any employee
+	EmployeeAssignment string `json:"employee_assignment" example:"LEAST_BOOKED"`
+	ServicePayment
+	BookingWindow
+	ServiceSlotRules
+	Rating RatingSummary `json:"rating"` // Of the published reviews
+}
+
+// ServicePayment is how the service is paid upfront and refunded on cancellation.
+type ServicePayment struct {
+	PrepaymentType                string `json:"prepayment_type" example:"DEPOSIT"` // NONE, DEPOSIT or FULL
+	DepositAmount                 int64  `json:"deposit_amount" example:"50"`
+	FreeCancellationHours         uint16 `json:"free_cancellation_hours" example:"24"`
+	LateCancellationRefundPercent uint8  `json:"late_cancellation_refund_percent" example:"50"`
+}
+
+// ServiceSlotRules is how the start times of the service are laid out.
+type ServiceSlotRules struct {
+	SlotInterval  uint16 `json:"slot_interval" example:"15"`        // Minutes between starts, the slot interval of the employee when 0
+	SlotAlignment string `json:"slot_alignment" example:"MIDNIGHT"` // MIDNIGHT or SHIFT_START
+	OptimizeGaps  bool   `json:"optimize_gaps" example:"false"`     // Prefer starts next to appointments and the edges of the work range
 }
 
 // @description	Service Full DTO
@@ -34,6 +55,11 @@ type ServiceBase struct {
 	Price       int32        `json:"price" example:"150"`
 	Duration    uint         `json:"duration" example:"60"`
 	Design      dJSON.Design `json:"design"`
+	// LEAST_BOOKED, ROUND_ROBIN or PREFERRED, how the employee is picked when booking any employee
+	EmployeeAssignment string `json:"employee_assignment" example:"LEAST_BOOKED"`
+	ServicePayment
+	BookingWindow
+	ServiceSlotRules
 }
 
 type ServiceID struct {
@@ -63,4 +89,50 @@ type ServiceAvailability struct {
 	AvailableDates []AvailableDate `json:"available_dates"`
 	EmployeeInfo   []EmployeeBase  `json:"employee_info"`
 	BranchInfo     []BranchBase    `json:"branch_info"`
+	Terms          []ServiceTerms  `json:"terms"`
+}
+
+// ServiceTerms is the price and duration of the service for an employee at a branch.
+type ServiceTerms struct {
+	EmployeeID uuid.UUID `json:"employee_id" example:"00000000-0000-0000-0000-000000000000"`
+	BranchID   uuid.UUID `json:"branch_id" example:"00000000-0000-0000-0000-000000000000"`
+	Price      int64     `json:"price" example:"180"`
+	Duration   uint16    `json:"duration" example:"75"`
+}
+
+// NextAvailableSlot is the first free slot of a service, with the employee the
+// assignment strategy of the service would pick first.
+type NextAvailableSlot struct {
+	ServiceID   uuid.UUID   `json:"service_id" example:"00000000-0000-0000-0000-000000000000"`
+	BranchID    uuid.UUID   `json:"branch_id" example:"00000000-0000-0000-0000-000000000000"`
+	StartTime   string      `json:"start_time" example:"2028-01-01T09:00:00-03:00"`
+	TimeZone    string      `json:"time_zone" example:"America/Sao_Paulo"`
+	EmployeeID  uuid.UUID   `json:"employee_id" example:"00000000-0000-0000-0000-000000000000"`
+	EmployeesID []uuid.UUID `json:"employees_id"`          // Every employee free for the slot, best first
+	Price       int64       `json:"price" example:"180"`   // With the picked employee
+	Duration    uint16      `json:"duration" example:"75"` // With the picked employee
+	Branch      BranchBase  `json:"branch"`
+}
+
+type CreateServiceOverride struct {
+	EmployeeID *uuid.UUID `json:"employee_id" example:"00000000-0000-0000-0000-000000000000"`
+	BranchID   *uuid.UUID `json:"branch_id" example:"00000000-0000-0000-0000-000000000000"`
+	Price      *int64     `json:"price" example:"180"`
+	Duration   *uint16    `json:"duration" example:"75"`
+}
+
+// @description	Service price/duration override DTO
+// @name			ServiceOverrideDTO
+// @tag.name		service.override.dto
+type ServiceOverride struct {
+	ID         uuid.UUID  `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	ServiceID  uuid.UUID  `json:"service_id" example:"00000000-0000-0000-0000-000000000000"`
+	EmployeeID *uuid.UUID `json:"employee_id" example:"00000000-0000-0000-0000-000000000000"`
+	BranchID   *uuid.UUID `json:"branch_id" example:"00000000-0000-0000-0000-000000000000"`
+	Price      *int64     `json:"price" example:"180"`
+	Duration   *uint16    `json:"duration" example:"75"`
+}
+
+type ServiceOverrideList struct {
+	Overrides []ServiceOverride `json:"overrides"`
 }
@@ -0,0 +1,84 @@
+package DTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateServicePackage struct {
+	CompanyID    uuid.UUID   `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	Name         string      `json:"name" example:"10 massages"`
+	Description  string      `json:"description" example:"Ten 60-minute massages"`
+	Type         string      `json:"type" example:"PACKAGE"` // PACKAGE or MEMBERSHIP
+	Price        int64       `json:"price" example:"90000"`
+	Currency     string      `json:"currency" example:"BRL"`
+	Credits      int64       `json:"credits" example:"10"`        // Credits of a PACKAGE, 0 for a MEMBERSHIP
+	ValidityDays int         `json:"validity_days" example:"180"` // Days the credits can be used after the purchase
+	ServiceIDs   []uuid.UUID `json:"service_ids"`                 // Empty covers all services
+}
+
+type UpdateServicePackage struct {
+	Name         *string      `json:"name" example:"10 massages"`
+	Description  *string      `json:"description" example:"Ten 60-minute massages"`
+	Price        *int64       `json:"price" example:"85000"`
+	Credits      *int64       `json:"credits" example:"10"`
+	ValidityDays *int         `json:"validity_days" example:"180"`
+	ServiceIDs   *[]uuid.UUID `json:"service_ids"`
+	IsActive     *bool        `json:"is_active" example:"true"`
+}
+
+// @description	Service package DTO
+// @name			ServicePackageDTO
+// @tag.name		service_package.dto
+type ServicePackage struct {
+	ID           uuid.UUID   `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	CompanyID    uuid.UUID   `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	Name         string      `json:"name" example:"10 massages"`
+	Description  string      `json:"description" example:"Ten 60-minute massages"`
+	Type         string      `json:"type" example:"PACKAGE"`
+	Price        int64       `json:"price" example:"90000"`
+	Currency     string      `json:"currency" example:"BRL"`
+	Credits      int64       `json:"credits" example:"10"`
+	ValidityDays int         `json:"validity_days" example:"180"`
+	ServiceIDs   []uuid.UUID `json:"service_ids"`
+	IsActive     bool        `json:"is_active" example:"true"`
+}
+
+type ServicePackageList struct {
+	Packages []ServicePackage `json:"packages"`
+}
+
+type SellServicePackage struct {
+	ClientID      uuid.UUID `json:"client_id" example:"00000000-0000-0000-0000-000000000000"`
+	PaymentMethod string    `json:"payment_method" example:"CASH"` // How the client paid the company, required unless the package is free
+}
+
+// @description	Package or membership bought by a client
+// @name			ClientPackageDTO
+// @tag.name		client_package.dto
+type ClientPackage struct {
+	ID          uuid.UUID   `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	CompanyID   uuid.UUID   `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	ClientID    uuid.UUID   `json:"client_id" example:"00000000-0000-0000-0000-000000000000"`
+	PackageID   uuid.UUID   `json:"package_id" example:"00000000-0000-0000-0000-000000000000"`
+	Name        string      `json:"name" example:"10 massages"`
+	Type        string      `json:"type" example:"PACKAGE"`
+	Price       int64       `json:"price" example:"90000"`
+	Currency    string      `json:"currency" example:"BRL"`
+	Credits     int64       `json:"credits" example:"10"`
+	CreditsUsed int64       `json:"credits_used" example:"3"`
+	Remaining   int64       `json:"remaining" example:"7"` // -1 for unlimited memberships
+	ServiceIDs  []uuid.UUID `json:"service_ids"`
+	PurchasedAt time.Time   `json:"purchased_at" example:"2028-01-01T09:00:00Z"`
+	ExpiresAt   time.Time   `json:"expires_at" example:"2028-07-01T09:00:00Z"`
+	Expired     bool        `json:"expired" example:"false"`
+	PaymentID   *uuid.UUID  `json:"payment_id" example:"00000000-0000-0000-0000-000000000000"`
+}
+
+type ClientCreditBalance struct {
+	ClientID  uuid.UUID       `json:"client_id" example:"00000000-0000-0000-0000-000000000000"`
+	Unlimited bool            `json:"unlimited" example:"false"` // An active membership is held
+	Credits   int64           `json:"credits" example:"7"`       // Credits left in active packages
+	Packages  []ClientPackage `json:"packages"`
+}
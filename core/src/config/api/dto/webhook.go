@@ -0,0 +1,66 @@
+package DTO
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateWebhook struct {
+	CompanyID   uuid.UUID `json:"company_id" example:"00000000-0000-0000-0000-000000000000"` // Optional, must be the X-Company-ID when given
+	URL         string    `json:"url" example:"https://crm.example.com/hooks/mynute"`
+	Description string    `json:"description" example:"Front desk CRM"`
+	Events      []string  `json:"events" example:"appointment.created,appointment.cancelled"`
+}
+
+type UpdateWebhook struct {
+	URL         *string   `json:"url" example:"https://crm.example.com/hooks/mynute"`
+	Description *string   `json:"description" example:"Front desk CRM"`
+	Events      *[]string `json:"events" example:"appointment.created,appointment.updated"`
+	IsActive    *bool     `json:"is_active" example:"true"`
+}
+
+// @description	Webhook subscription DTO
+// @name			WebhookDTO
+// @tag.name		webhook.dto
+type Webhook struct {
+	ID          uuid.UUID `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	CompanyID   uuid.UUID `json:"company_id" example:"00000000-0000-0000-0000-000000000000"`
+	URL         string    `json:"url" example:"https://crm.example.com/hooks/mynute"`
+	Description string    `json:"description" example:"Front desk CRM"`
+	Events      []string  `json:"events" example:"appointment.created,appointment.cancelled"`
+	IsActive    bool      `json:"is_active" example:"true"`
+}
+
+// WebhookWithSecret is only returned on creation and on secret rotation, the secret is not shown again afterwards.
+type WebhookWithSecret struct {
+	Webhook
+	Secret string `json:"secret" example:"4f9a1c..."`
+}
+
+type WebhookList struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id" example:"00000000-0000-0000-0000-000000000000"`
+	SubscriptionID uuid.UUID       `json:"subscription_id" example:"00000000-0000-0000-0000-000000000000"`
+	Event          string          `json:"event" example:"appointment.created"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status" example:"SUCCEEDED"`
+	Attempts       int             `json:"attempts" example:"1"`
+	ResponseStatus int             `json:"response_status" example:"200"`
+	LastError      string          `json:"last_error" example:""`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at" example:"2028-01-01T09:00:30Z"`
+	DeliveredAt    *time.Time      `json:"delivered_at" example:"2028-01-01T09:00:00Z"`
+	ReplayOfID     *uuid.UUID      `json:"replay_of_id" example:"00000000-0000-0000-0000-000000000000"`
+	CreatedAt      time.Time       `json:"created_at" example:"2028-01-01T09:00:00Z"`
+}
+
+type WebhookDeliveryList struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	TotalCount int               `json:"total_count" example:"100"`
+	Page       int               `json:"page" example:"1"`
+	PageSize   int               `json:"page_size" example:"10"`
+}
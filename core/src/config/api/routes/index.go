@@ -17,14 +17,28 @@ func Build(DB *gorm.DB, App *fiber.App) {
 	Gorm := &handler.Gorm{DB: DB}
 
 	controller.Appointment(Gorm)
+	controller.BookableResource(Gorm)
 	controller.Auth(Gorm)
 	controller.Branch(Gorm)
 	controller.Client(Gorm)
+	controller.ClientProfile(Gorm)
+	controller.ClientDataRequest(Gorm)
+	controller.ClientDependent(Gorm)
 	controller.Company(Gorm)
 	controller.Employee(Gorm)
+	controller.EmployeeInvitation(Gorm)
 	controller.Holiday(Gorm)
+	controller.Import(Gorm)
+	controller.IntakeForm(Gorm)
+	controller.Loyalty(Gorm)
+	controller.Payment(Gorm)
+	controller.PromoCode(Gorm)
+	controller.Review(Gorm)
+	controller.ScheduleTemplate(Gorm)
 	controller.Sector(Gorm)
 	controller.Service(Gorm)
+	controller.ServicePackage(Gorm)
+	controller.Webhook(Gorm)
 
 	r := App.Group("/")
 
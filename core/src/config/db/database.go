@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/config/namespace"
 	"mynute-go/core/src/lib"
 	"os"
 	"reflect"
@@ -516,6 +517,7 @@ func DeferPanic(tx *gorm.DB) {
 	r := recover() // Capture panic if any
 	if r != nil {
 		_ = tx.Rollback()
+		runRollbackHooks(tx)
 		if err, ok := r.(error); ok {
 			log.Printf("ContextTransaction rolled back due to panic: %v", err)
 		} else {
@@ -550,7 +552,10 @@ func Commit(tx *gorm.DB) func(err error) {
 			if err.Error() != "sql: transaction has already been committed or rolled back" {
 				_ = tx.Rollback()
 			}
+			runRollbackHooks(tx)
+			return
 		}
+		runCommitHooks(tx)
 	}
 }
 
@@ -559,6 +564,7 @@ func Rollback(tx *gorm.DB) func(err error) {
 		if err == nil {
 			return // No error, no rollback needed
 		}
+		defer runRollbackHooks(tx)
 		if rollbackErr := tx.Rollback().Error; rollbackErr != nil {
 			log.Printf("Rollback failed: %v", rollbackErr)
 			return
@@ -572,6 +578,7 @@ func Transaction(db *gorm.DB) (*gorm.DB, func(error), error) {
 	if tx.Error != nil {
 		return nil, nil, lib.Error.General.DatabaseError.WithError(tx.Error)
 	}
+	track(tx)
 	return tx, DeferCallback(tx), nil
 }
 
@@ -598,7 +605,12 @@ func ContextTransaction(c *fiber.Ctx) (*gorm.DB, func(error), error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	return Transaction(session)
+	tx, end, err := Transaction(session)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.Locals(namespace.GeneralKey.Transaction, tx)
+	return tx, end, nil
 }
 
 // Locks the record for update using the given transaction and model.
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"log"
+	"mynute-go/core/src/config/namespace"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// txHooks holds the callbacks registered for an open transaction.
+type txHooks struct {
+	afterCommit   []func()
+	afterRollback []func()
+}
+
+var (
+	hooksMu sync.Mutex
+	hooks   = map[gorm.ConnPool]*txHooks{}
+)
+
+// track registers tx as an open transaction so hooks can be attached to it.
+func track(tx *gorm.DB) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks[tx.Statement.ConnPool] = &txHooks{}
+}
+
+// release forgets tx and returns the hooks that were registered on it.
+func release(tx *gorm.DB) *txHooks {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	h, ok := hooks[tx.Statement.ConnPool]
+	if !ok {
+		return nil
+	}
+	delete(hooks, tx.Statement.ConnPool)
+	return h
+}
+
+// AfterCommit runs fn once tx has been committed. It is dropped when tx
+// rolls back. When tx is not an open transaction, fn runs right away.
+func AfterCommit(tx *gorm.DB, fn func()) {
+	hooksMu.Lock()
+	h, ok := hooks[tx.Statement.ConnPool]
+	if ok {
+		h.afterCommit = append(h.afterCommit, fn)
+	}
+	hooksMu.Unlock()
+	if !ok {
+		fn()
+	}
+}
+
+// AfterRollback runs fn if tx ends up rolled back, including a failed commit.
+// It is ignored when tx is not an open transaction.
+func AfterRollback(tx *gorm.DB, fn func()) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	if h, ok := hooks[tx.Statement.ConnPool]; ok {
+		h.afterRollback = append(h.afterRollback, fn)
+	}
+}
+
+// AfterContextCommit runs fn after the transaction opened for the request
+// by ContextTransaction commits, or right away when none is open anymore.
+func AfterContextCommit(c *fiber.Ctx, fn func()) {
+	tx, ok := c.Locals(namespace.GeneralKey.Transaction).(*gorm.DB)
+	if !ok {
+		fn()
+		return
+	}
+	AfterCommit(tx, fn)
+}
+
+func runCommitHooks(tx *gorm.DB) {
+	if h := release(tx); h != nil {
+		for _, fn := range h.afterCommit {
+			runHook(fn)
+		}
+	}
+}
+
+func runRollbackHooks(tx *gorm.DB) {
+	if h := release(tx); h != nil {
+		for _, fn := range h.afterRollback {
+			runHook(fn)
+		}
+	}
+}
+
+// runHook keeps a failing hook from taking the request down with it, since
+// the transaction outcome is already settled by the time hooks run.
+func runHook(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Transaction hook panicked: %v", r)
+		}
+	}()
+	fn()
+}
@@ -17,6 +17,7 @@ type AppointmentBase struct {
 	ServiceID             uuid.UUID  `gorm:"type:uuid;not null" json:"service_id"`
 	EmployeeID            uuid.UUID  `gorm:"type:uuid;not null" json:"employee_id"`
 	ClientID              uuid.UUID  `gorm:"type:uuid;not null;index" json:"client_id"`
+	DependentID           *uuid.UUID `gorm:"type:uuid;index" json:"dependent_id"` // Dependent of the client attending, the client itself when nil
 	BranchID              uuid.UUID  `gorm:"type:uuid;not null" json:"branch_id"`
 	PaymentID             *uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"payment_id"`
 	CompanyID             uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
@@ -32,6 +33,11 @@ type AppointmentBase struct {
 	IsCancelledByClient   bool       `gorm:"default:false" json:"is_cancelled_by_client"`
 	IsCancelledByEmployee bool       `gorm:"default:false" json:"is_cancelled_by_employee"`
 	IsConfirmedByClient   bool       `gorm:"default:false" json:"is_confirmed_by_client"`
+	Price                 int64      `gorm:"not null;default:0" json:"price"`    // Service price for the employee and branch when it was booked
+	Discount              int64      `gorm:"not null;default:0" json:"discount"` // Promo code discount on the price
+	PromoCodeID           *uuid.UUID `gorm:"type:uuid;index" json:"promo_code_id"`
+	ClientPackageID       *uuid.UUID `gorm:"type:uuid;index" json:"client_package_id"`   // Package or membership whose credit paid the appointment
+	LoyaltyDiscount       int64      `gorm:"not null;default:0" json:"loyalty_discount"` // Loyalty points redeemed on the price, after the promo code discount
 }
 
 // This is the foreign key struct for the Appointment model at company schema level.
@@ -54,6 +60,8 @@ type Appointment struct {
 	AppointmentBase
 	AppointmentFK
 	AppointmentJson
+	ReceiptURL      string     `gorm:"type:text" json:"receipt_url"` // Receipt PDF, once issued
+	ReceiptIssuedAt *time.Time `json:"receipt_issued_at"`
 }
 
 const AppointmentTableName = "appointments"
@@ -79,6 +87,9 @@ func AppointmentIndexes(table string) map[string]string {
 // --- Appointment Hooks ---
 
 func (a *Appointment) AfterCreate(tx *gorm.DB) error {
+	if err := a.AllocateResources(tx); err != nil {
+		return err
+	}
 	var client Client
 	if err := tx.Model(&Client{}).Where("id = ?", a.ClientID).First(&client).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -97,6 +108,9 @@ func (a *Appointment) AfterCreate(tx *gorm.DB) error {
 	if err := tx.Save(&client).Error; err != nil {
 		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("updating client: %w", err))
 	}
+	if _, err := EnsureClientProfile(tx, a.CompanyID, a.ClientID); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -123,37 +137,52 @@ func (a *Appointment) BeforeUpdate(tx *gorm.DB) error {
 		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("loading appointment: %w", err))
 	}
 
-	if a.CompanyID != uuid.Nil && a.CompanyID != originalAppointment.CompanyID {
+	// With tx.Model(&appointment).Updates(&changes) the new values are in the
+	// update destination rather than in the model the hook is called on.
+	incoming := a
+	if dest, ok := tx.Statement.Dest.(*Appointment); ok && dest != a {
+		incoming = dest
+	}
+
+	if incoming.CompanyID != uuid.Nil && incoming.CompanyID != originalAppointment.CompanyID {
 		return lib.Error.Appointment.UpdateFailed.WithError(fmt.Errorf("cannot change company ID"))
-	} else if a.BranchID != uuid.Nil && a.BranchID != originalAppointment.BranchID {
+	} else if incoming.BranchID != uuid.Nil && incoming.BranchID != originalAppointment.BranchID {
 		return lib.Error.Appointment.UpdateFailed.WithError(fmt.Errorf("cannot change branch ID"))
-	} else if a.EmployeeID != uuid.Nil && a.EmployeeID != originalAppointment.EmployeeID {
+	} else if incoming.EmployeeID != uuid.Nil && incoming.EmployeeID != originalAppointment.EmployeeID {
 		return lib.Error.Appointment.UpdateFailed.WithError(fmt.Errorf("cannot change employee ID"))
-	} else if a.ServiceID != uuid.Nil && a.ServiceID != originalAppointment.ServiceID {
+	} else if incoming.ServiceID != uuid.Nil && incoming.ServiceID != originalAppointment.ServiceID {
 		return lib.Error.Appointment.UpdateFailed.WithError(fmt.Errorf("cannot change service ID"))
-	}
-
+	} else if incoming.Price != 0 && incoming.Price != originalAppointment.Price {
+		return lib.Error.Appointment.UpdateFailed.WithError(fmt.Errorf("cannot change price"))
+	} else if incoming.Discount != 0 && incoming.Discount != originalAppointment.Discount {
+		return lib.Error.Appointment.UpdateFailed.WithError(fmt.Errorf("cannot change discount"))
+	} else if incoming.PromoCodeID != nil && !reflect.DeepEqual(incoming.PromoCodeID, originalAppointment.PromoCodeID) {
+		return lib.Error.Appointment.UpdateFailed.WithError(fmt.Errorf("cannot change promo code"))
+	} else if incoming.DependentID != nil && !reflect.DeepEqual(incoming.DependentID, originalAppointment.DependentID) {
+		return lib.Error.Appointment.UpdateFailed.WithError(fmt.Errorf("cannot change the attendee"))
+	} else if incoming.ClientPackageID != nil && !reflect.DeepEqual(incoming.ClientPackageID, originalAppointment.ClientPackageID) {
+		return lib.Error.Appointment.UpdateFailed.WithError(fmt.Errorf("cannot change the package credit"))
+	} else if incoming.LoyaltyDiscount != 0 && incoming.LoyaltyDiscount != originalAppointment.LoyaltyDiscount {
+		return lib.Error.Appointment.UpdateFailed.WithError(fmt.Errorf("cannot change the loyalty discount"))
+	} else if (incoming.ReceiptURL != "" && incoming.ReceiptURL != originalAppointment.ReceiptURL) || (incoming.ReceiptIssuedAt != nil && !reflect.DeepEqual(incoming.ReceiptIssuedAt, originalAppointment.ReceiptIssuedAt)) {
+		return lib.Error.Appointment.UpdateFailed.WithError(fmt.Errorf("cannot change the receipt"))
+	}
+
+	audit := AuditOf(tx)
 	var changes []mJSON.FieldChange
 
-	// --- Start: The Refactored Comparison Logic ---
-	originalVal := reflect.ValueOf(originalAppointment)
-	newVal := reflect.ValueOf(*a) // The incoming struct 'a' has the new data
+	// merged is the appointment as it will be after the update, used for validation.
+	// Only the AppointmentBase fields are tracked; IDs, timestamps, relations,
+	// history and comments are not part of the audit trail.
+	merged := originalAppointment
+	mergedVal := reflect.ValueOf(&merged.AppointmentBase).Elem()
+	originalVal := reflect.ValueOf(originalAppointment.AppointmentBase)
+	newVal := reflect.ValueOf(incoming.AppointmentBase)
 
-	// Iterate through all fields of the Appointment struct
 	for i := range newVal.NumField() {
 		fieldStruct := newVal.Type().Field(i)
 		fieldName := fieldStruct.Name
 
-		// Skip fields that we don't want to track or compare
-		if fieldName == "ID" || fieldName == "CreatedAt" || fieldName == "UpdatedAt" || fieldName == "History" || fieldName == "Comments" || fieldName == "BaseModel" {
-			continue
-		}
-
-		// Prevent changing CompanyID
-		if fieldName == "CompanyID" && originalAppointment.CompanyID != a.CompanyID && a.CompanyID != uuid.Nil {
-			return lib.Error.General.UpdatedError.WithError(errors.New("the CompanyID cannot be changed after creation"))
-		}
-
 		originalFieldVal := originalVal.FieldByName(fieldName).Interface()
 		newFieldVal := newVal.Field(i).Interface()
 
@@ -163,21 +192,38 @@ func (a *Appointment) BeforeUpdate(tx *gorm.DB) error {
 
 		// If the new value is non-zero AND it's different from the original value...
 		if isNewValueNonZero && !reflect.DeepEqual(originalFieldVal, newFieldVal) {
-			changes = append(changes, mJSON.FieldChange{
-				CreatedAt: time.Now(),
-				Field:     fieldName,
-				OldValue:  fmt.Sprintf("%v", originalFieldVal),
-				NewValue:  fmt.Sprintf("%v", newFieldVal),
-			})
+			change, err := mJSON.NewFieldChange(fieldName, originalFieldVal, newFieldVal, audit)
+			if err != nil {
+				return lib.Error.Appointment.HistoryLoggingFailed.WithError(err)
+			}
+			changes = append(changes, change)
+			mergedVal.FieldByName(fieldName).Set(newVal.Field(i))
 		}
 	}
 
 	if len(changes) > 0 {
-		err := a.ValidateRules(tx, false)
-		if err != nil {
+		// Rescheduling follows the booking window like a new booking
+		if !incoming.StartTime.IsZero() && !incoming.StartTime.Equal(originalAppointment.StartTime) {
+			var service Service
+			if err := tx.Where("id = ?", merged.ServiceID).First(&service).Error; err != nil {
+				return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading service: %w", err))
+			}
+			if err := merged.CheckBookingWindow(tx, &service, time.Now()); err != nil {
+				return err
+			}
+			if err := merged.CheckEmployeeBookable(tx); err != nil {
+				return err
+			}
+		}
+		if err := merged.ValidateRules(tx, false); err != nil {
 			return err
 		}
-		a.History.FieldChanges = append(originalAppointment.History.FieldChanges, changes...)
+		history := mJSON.AppointmentHistory{FieldChanges: append(originalAppointment.History.FieldChanges, changes...)}
+		tx.Statement.SetColumn("History", history)
+		// The end follows the start and the duration of the service, worked out by ValidateRules
+		if !merged.EndTime.Equal(originalAppointment.EndTime) {
+			tx.Statement.SetColumn("EndTime", merged.EndTime)
+		}
 	}
 
 	return nil
@@ -199,24 +245,39 @@ func (a *Appointment) ValidateRules(tx *gorm.DB, isCreate bool) error {
 	if a.ServiceID == uuid.Nil || a.EmployeeID == uuid.Nil || a.ClientID == uuid.Nil || a.BranchID == uuid.Nil || a.CompanyID == uuid.Nil {
 		return lib.Error.Appointment.MissingRequiredIDs
 	}
-	if isCreate || a.StartTime.IsZero() {
-		if a.StartTime.Before(time.Now().Add(-1 * time.Minute)) {
-			return lib.Error.Appointment.StartTimeInThePast // Use specific error for past time
-		}
-	} else if a.CompanyID != uuid.Nil {
-		return lib.Error.Appointment.UpdateFailed.WithError(fmt.Errorf("can not change company id")) // Use specific error for company ID change
+	// Updates are checked against the company of the stored appointment by BeforeUpdate
+	if isCreate && a.StartTime.Before(time.Now().Add(-1*time.Minute)) {
+		return lib.Error.Appointment.StartTimeInThePast // Use specific error for past time
 	}
 
-	// 2. Calculate & Validate EndTime
-	var serviceDuration uint
-	if err := tx.Model(&Service{}).Where("id = ?", a.ServiceID).Pluck("duration", &serviceDuration).Error; err != nil {
-		return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading service duration: %w", err))
+	// 2. Calculate & Validate EndTime with the duration of the service for the employee and branch
+	var service Service
+	if err := tx.Where("id = ?", a.ServiceID).First(&service).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading service: %w", err))
+	}
+	if isCreate {
+		if err := a.CheckBookingWindow(tx, &service, time.Now()); err != nil {
+			return err
+		}
+	}
+	terms, err := service.LoadTerms(tx, a.EmployeeID, a.BranchID)
+	if err != nil {
+		return err
 	}
-	if serviceDuration <= 0 { // Use uint duration from your model
+	if terms.Duration <= 0 {
 		return lib.Error.Appointment.InvalidServiceDuration
 	}
+	// The price is snapshotted at booking, later price changes do not affect it,
+	// and the discounts and credit are only granted by a promo code, loyalty points or package applied after the creation
+	if isCreate {
+		a.Price = terms.Price
+		a.Discount = 0
+		a.PromoCodeID = nil
+		a.ClientPackageID = nil
+		a.LoyaltyDiscount = 0
+	}
 
-	a.EndTime = a.StartTime.Add(time.Duration(serviceDuration) * time.Minute)
+	a.EndTime = a.StartTime.Add(time.Duration(terms.Duration) * time.Minute)
 	if !a.EndTime.After(a.StartTime) {
 		return lib.Error.Appointment.EndTimeBeforeStart
 	}
@@ -245,6 +306,12 @@ func (a *Appointment) ValidateRules(tx *gorm.DB, isCreate bool) error {
 	if aEmployeeCompanyID != a.CompanyID.String() {
 		return lib.Error.Company.EmployeeDoesNotBelong
 	}
+	// Invited and suspended employees keep their appointments but take no new ones
+	if isCreate {
+		if err := a.CheckEmployeeBookable(tx); err != nil {
+			return err
+		}
+	}
 
 	// Check if Service belongs to the same Company as the Appointment
 	var aServiceCompanyID string
@@ -279,21 +346,19 @@ func (a *Appointment) ValidateRules(tx *gorm.DB, isCreate bool) error {
 	}
 
 	// 4. Check Employee Availability (Work Schedule)
-	weekday := a.StartTime.Weekday()
-	aStartTimeHHMMUTC := time.Date(2020, 1, 1, a.StartTime.Hour(), a.StartTime.Minute(), 0, 0, a.StartTime.Location()).UTC()
-	aEndTimeHHMMUTC := time.Date(2020, 1, 1, a.EndTime.Hour(), a.EndTime.Minute(), 0, 0, a.EndTime.Location()).UTC()
-	if err := tx.Model(&EmployeeWorkRange{}).
-		Where("employee_id = ? AND branch_id = ?", a.EmployeeID, a.BranchID).
-		Where("start_time <= ? AND end_time >= ? AND weekday = ?", aStartTimeHHMMUTC, aEndTimeHHMMUTC, weekday).
-		Count(&count).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return lib.Error.General.BadRequest.WithError(fmt.Errorf("no work schedule was found that could contain the appointment from (%s) to (%s) on day (%s) for employee %s at branch %s", a.StartTime.Format(time.RFC3339), a.EndTime.Format(time.RFC3339), weekday, a.EmployeeID, a.BranchID))
-		}
-		return lib.Error.General.InternalError.WithError(fmt.Errorf("error querying work schedule: %w", err))
+	// Ranges spanning midnight belong to the weekday they start on
+	shifts, err := a.WorkShifts(tx)
+	if err != nil {
+		return err
 	}
-
-	if count == 0 {
-		return lib.Error.General.BadRequest.WithError(fmt.Errorf("no work schedule was found that could contain the appointment from (%s) to (%s) on day (%s) for employee %s at branch %s", a.StartTime.Format(time.RFC3339), a.EndTime.Format(time.RFC3339), weekday, a.EmployeeID, a.BranchID))
+	if len(shifts) == 0 {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("no work schedule was found that could contain the appointment from (%s) to (%s) on day (%s) for employee %s at branch %s", a.StartTime.Format(time.RFC3339), a.EndTime.Format(time.RFC3339), a.StartTime.Weekday(), a.EmployeeID, a.BranchID))
+	}
+	// The branch hours may have shrunk since the employee ranges were set
+	if open, err := a.BranchOpen(tx); err != nil {
+		return err
+	} else if !open {
+		return lib.Error.Branch.Closed.WithError(fmt.Errorf("branch %s is closed from (%s) to (%s)", a.BranchID, a.StartTime.Format(time.RFC3339), a.EndTime.Format(time.RFC3339)))
 	}
 
 	ChangeSchema := func(schema string) error {
@@ -372,10 +437,24 @@ func (a *Appointment) ValidateRules(tx *gorm.DB, isCreate bool) error {
 		}
 	}
 
-	// Client Overlap (Under Company Schema Search)
+	// Resources the service holds at the branch
+	if err := a.CheckResources(tx); err != nil {
+		return err
+	}
+
+	// Start times the service offers
+	if err := a.CheckSlotRules(tx, &service); err != nil {
+		return err
+	}
+
+	// Attendee Overlap (Under Company Schema Search). Each dependent has its own schedule, apart from the client's.
+	if a.DependentID != nil {
+		if err := CheckDependentOf(tx, a.ClientID, *a.DependentID); err != nil {
+			return err
+		}
+	}
 	var clientAppointmentsCount int64
-	if err := Query().
-		Where("client_id = ?", a.ClientID).
+	if err := WhereAttendee(Query(), a.ClientID, a.DependentID).
 		Count(&clientAppointmentsCount).Error; err != nil {
 		return lib.Error.General.InternalError.WithError(fmt.Errorf("db error checking client overlap: %w", err))
 	}
@@ -383,13 +462,12 @@ func (a *Appointment) ValidateRules(tx *gorm.DB, isCreate bool) error {
 		return lib.Error.Client.ScheduleConflict
 	}
 
-	// Client Overlap (Under Public Schema Search)
+	// Attendee Overlap (Under Public Schema Search)
 	if err := ChangeSchema("public"); err != nil {
 		return err
 	}
 	clientAppointmentsCount = 0
-	if err := tx.Model(&ClientAppointment{}).
-		Where("client_id = ?", a.ClientID).
+	if err := WhereAttendee(tx.Model(&ClientAppointment{}), a.ClientID, a.DependentID).
 		Where("appointment_id != ?", a.ID).
 		Where("company_id != ?", a.CompanyID).
 		Where(cancelled, false).
@@ -407,6 +485,39 @@ func (a *Appointment) ValidateRules(tx *gorm.DB, isCreate bool) error {
 	return nil // All validations passed
 }
 
+// CheckEmployeeBookable fails unless the employee of the appointment is active.
+func (a *Appointment) CheckEmployeeBookable(tx *gorm.DB) error {
+	var status string
+	if err := tx.Model(&Employee{}).Where("id = ?", a.EmployeeID).Pluck("status", &status).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading employee status: %w", err))
+	}
+	if status != EmployeeActive {
+		return lib.Error.Employee.NotBookable.WithError(fmt.Errorf("employee %s is %s", a.EmployeeID, status))
+	}
+	return nil
+}
+
+// AssignEmployee sets the first of the employees, best first, for whom the appointment
+// passes ValidateRules. It fails with NoEmployeeAvailable when none does.
+func (a *Appointment) AssignEmployee(tx *gorm.DB, employeeIDs []uuid.UUID) error {
+	lastErr := errors.New("no employee is free for the slot")
+	for _, employeeID := range employeeIDs {
+		candidate := *a
+		candidate.EmployeeID = employeeID
+		err := candidate.ValidateRules(tx, true)
+		if err == nil {
+			a.EmployeeID = employeeID
+			return nil
+		}
+		// A failed validation may have left the session on the public schema
+		if err := lib.ChangeToCompanySchema(tx, fmt.Sprintf("company_%s", a.CompanyID.String())); err != nil {
+			return lib.Error.General.InternalError.WithError(fmt.Errorf("error changing to company schema: %w", err))
+		}
+		lastErr = err
+	}
+	return lib.Error.Appointment.NoEmployeeAvailable.WithError(lastErr)
+}
+
 func (a *Appointment) Refresh(tx *gorm.DB) error {
 	if err := tx.Model(&Appointment{}).Where("id = ?", a.ID).Preload(clause.Associations).First(a).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -431,12 +542,18 @@ func (a *Appointment) Cancel(tx *gorm.DB) error {
 	} else if time.Now().After(a.StartTime) {
 		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("cannot cancel appointment as it already happened"))
 	}
+	change, err := mJSON.NewFieldChange("IsCancelled", false, true, AuditOf(tx))
+	if err != nil {
+		return lib.Error.Appointment.HistoryLoggingFailed.WithError(err)
+	}
 	a.IsCancelled = true
 	a.CancelTime = time.Now()
+	a.History.FieldChanges = append(a.History.FieldChanges, change)
 	// Use UpdateColumn instead of Updates to skip hooks
-	err := tx.Model(a).UpdateColumns(map[string]interface{}{
+	err = tx.Model(a).UpdateColumns(map[string]interface{}{
 		"is_cancelled": true,
-		"cancel_time":  time.Now(),
+		"cancel_time":  a.CancelTime,
+		"history":      &a.History,
 	}).Error
 	if err != nil {
 		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error cancelling appointment: %w", err))
@@ -453,19 +570,20 @@ func (a *Appointment) Cancel(tx *gorm.DB) error {
 		// we must send an alert to the admin team to investigate why it happened.
 		// For now, we won't do anything.
 		// This situation should never happen in a properly functioning system.
-		if err == gorm.ErrRecordNotFound {
-			return nil // If not found, nothing more to do
+		if err != gorm.ErrRecordNotFound {
+			return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error loading client appointment: %w", err))
+		}
+	} else {
+		err = tx.Model(&ClientAppointment{}).
+			Where("appointment_id = ?", a.ID).
+			UpdateColumns(map[string]interface{}{
+				"is_cancelled": true,
+			}).Error
+		if err != nil {
+			return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error cancelling client appointment: %w", err))
 		}
-		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error loading client appointment: %w", err))
-	}
-	err = tx.Model(&ClientAppointment{}).
-		Where("appointment_id = ?", a.ID).
-		UpdateColumns(map[string]interface{}{
-			"is_cancelled": true,
-		}).Error
-	if err != nil {
-		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error cancelling client appointment: %w", err))
 	}
+	// Callers keep working on the company schema, e.g. to refund the appointment
 	companySchema := fmt.Sprintf("company_%s", a.CompanyID.String())
 	if err := lib.ChangeToCompanySchema(tx, companySchema); err != nil {
 		return lib.Error.General.InternalError.WithError(fmt.Errorf("error changing to company schema: %w", err))
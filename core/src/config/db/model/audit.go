@@ -0,0 +1,29 @@
+package model
+
+import (
+	"context"
+	mJSON "mynute-go/core/src/config/db/model/json"
+
+	"gorm.io/gorm"
+)
+
+type auditContextKey struct{}
+
+// WithAudit returns a session carrying who is making the change, so that
+// hooks recording history (e.g. Appointment.BeforeUpdate) can store it.
+func WithAudit(tx *gorm.DB, audit mJSON.Audit) *gorm.DB {
+	ctx := tx.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return tx.WithContext(context.WithValue(ctx, auditContextKey{}, audit))
+}
+
+// AuditOf returns the audit attached to the session by WithAudit, if any.
+func AuditOf(tx *gorm.DB) mJSON.Audit {
+	if tx.Statement.Context == nil {
+		return mJSON.Audit{}
+	}
+	audit, _ := tx.Statement.Context.Value(auditContextKey{}).(mJSON.Audit)
+	return audit
+}
@@ -0,0 +1,253 @@
+package model
+
+import (
+	"fmt"
+	"mynute-go/core/src/lib"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BookableResource is something of a branch that appointments hold while they run, like
+// a room, a chair or a machine. Quantity is how many interchangeable units the branch has.
+type BookableResource struct {
+	BaseModel
+	BranchID uuid.UUID `gorm:"type:uuid;not null;index" json:"branch_id"`
+	Branch   *Branch   `gorm:"foreignKey:BranchID;references:ID;constraint:OnDelete:CASCADE;" json:"-"`
+	Name     string    `gorm:"type:varchar(100);not null" json:"name"`
+	Kind     string    `gorm:"type:varchar(20);not null;default:'ROOM'" json:"kind"`
+	Quantity uint32    `gorm:"not null;default:1" json:"quantity"`
+}
+
+// Kinds of bookable resources.
+const (
+	ResourceKindRoom      = "ROOM"
+	ResourceKindChair     = "CHAIR"
+	ResourceKindEquipment = "EQUIPMENT"
+)
+
+const BookableResourceTableName = "bookable_resources"
+
+func (BookableResource) TableName() string  { return BookableResourceTableName }
+func (BookableResource) SchemaType() string { return "tenant" }
+
+func (r *BookableResource) Validate() error {
+	if r.BranchID == uuid.Nil {
+		return lib.Error.BookableResource.Invalid.WithError(fmt.Errorf("branch_id is required"))
+	}
+	if len(r.Name) < 2 || len(r.Name) > 100 {
+		return lib.Error.BookableResource.Invalid.WithError(fmt.Errorf("name must have from 2 to 100 characters"))
+	}
+	switch r.Kind {
+	case ResourceKindRoom, ResourceKindChair, ResourceKindEquipment:
+	default:
+		return lib.Error.BookableResource.Invalid.WithError(fmt.Errorf("unknown kind %q", r.Kind))
+	}
+	if r.Quantity == 0 {
+		return lib.Error.BookableResource.Invalid.WithError(fmt.Errorf("quantity must be positive"))
+	}
+	return nil
+}
+
+func (r *BookableResource) BeforeCreate(tx *gorm.DB) error {
+	if r.Kind == "" {
+		r.Kind = ResourceKindRoom
+	}
+	return r.Validate()
+}
+
+// ServiceResourceRequirement is a resource a service holds while it runs, Quantity units of it.
+// It only applies at the branch of the resource.
+type ServiceResourceRequirement struct {
+	BaseModel
+	ServiceID  uuid.UUID         `gorm:"type:uuid;not null;index" json:"service_id"`
+	Service    *Service          `gorm:"foreignKey:ServiceID;references:ID;constraint:OnDelete:CASCADE;" json:"-"`
+	ResourceID uuid.UUID         `gorm:"type:uuid;not null;index" json:"resource_id"`
+	Resource   *BookableResource `gorm:"foreignKey:ResourceID;references:ID;constraint:OnDelete:CASCADE;" json:"-"`
+	Quantity   uint32            `gorm:"not null;default:1" json:"quantity"`
+}
+
+const ServiceResourceRequirementTableName = "service_resource_requirements"
+
+func (ServiceResourceRequirement) TableName() string  { return ServiceResourceRequirementTableName }
+func (ServiceResourceRequirement) SchemaType() string { return "tenant" }
+func (ServiceResourceRequirement) Indexes() map[string]string {
+	return map[string]string{
+		"idx_service_resource_requirement_unique": fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_service_resource_requirement_unique ON %s (service_id, resource_id) WHERE deleted_at IS NULL", ServiceResourceRequirementTableName),
+	}
+}
+
+func (s *ServiceResourceRequirement) BeforeCreate(tx *gorm.DB) error {
+	if s.Quantity == 0 {
+		s.Quantity = 1
+	}
+	var resource BookableResource
+	if err := tx.Where("id = ?", s.ResourceID).First(&resource).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return lib.Error.BookableResource.NotFound
+		}
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	if s.Quantity > resource.Quantity {
+		return lib.Error.BookableResource.Invalid.WithError(fmt.Errorf("the branch only has %d of %s", resource.Quantity, resource.Name))
+	}
+	var count int64
+	tx.Table("branch_services").Where("branch_id = ? AND service_id = ?", resource.BranchID, s.ServiceID).Count(&count)
+	if count == 0 {
+		return lib.Error.Branch.ServiceDoesNotBelong
+	}
+	count = 0
+	if err := tx.Model(&ServiceResourceRequirement{}).Where("service_id = ? AND resource_id = ?", s.ServiceID, s.ResourceID).Count(&count).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	if count > 0 {
+		return lib.Error.BookableResource.Invalid.WithError(fmt.Errorf("the service already needs %s", resource.Name))
+	}
+	return nil
+}
+
+// AppointmentResourceAllocation is what an appointment holds of a resource, allocated when it is booked.
+// Cancelled appointments release their resources.
+type AppointmentResourceAllocation struct {
+	BaseModel
+	AppointmentID uuid.UUID         `gorm:"type:uuid;not null;index" json:"appointment_id"`
+	Appointment   *Appointment      `gorm:"foreignKey:AppointmentID;references:ID;constraint:OnDelete:CASCADE;" json:"-"`
+	ResourceID    uuid.UUID         `gorm:"type:uuid;not null;index" json:"resource_id"`
+	Resource      *BookableResource `gorm:"foreignKey:ResourceID;references:ID;constraint:OnDelete:CASCADE;" json:"-"`
+	Quantity      uint32            `gorm:"not null" json:"quantity"`
+}
+
+const AppointmentResourceAllocationTableName = "appointment_resource_allocations"
+
+func (AppointmentResourceAllocation) TableName() string {
+	return AppointmentResourceAllocationTableName
+}
+func (AppointmentResourceAllocation) SchemaType() string { return "tenant" }
+
+// ResourceNeed is what a service needs of a resource of a branch.
+type ResourceNeed struct {
+	ResourceID uuid.UUID
+	BranchID   uuid.UUID
+	Name       string
+	Quantity   uint32 // Units the service holds
+	Capacity   uint32 // Units the branch has
+}
+
+// ResourceUse is what an appointment not cancelled holds of a resource.
+type ResourceUse struct {
+	ResourceID uuid.UUID
+	Quantity   uint32
+	StartTime  time.Time
+	EndTime    time.Time
+}
+
+// LoadResourceNeeds lists what the service needs of the resources of every branch.
+func LoadResourceNeeds(tx *gorm.DB, serviceID uuid.UUID) ([]ResourceNeed, error) {
+	var needs []ResourceNeed
+	if err := tx.Model(&ServiceResourceRequirement{}).
+		Select("service_resource_requirements.resource_id, r.branch_id, r.name, service_resource_requirements.quantity, r.quantity AS capacity").
+		Joins("JOIN bookable_resources r ON r.id = service_resource_requirements.resource_id AND r.deleted_at IS NULL").
+		Where("service_resource_requirements.service_id = ?", serviceID).
+		Scan(&needs).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading service resources: %w", err))
+	}
+	return needs, nil
+}
+
+// LoadResourceUses lists what appointments not cancelled overlapping from and to hold
+// of the resources, except the appointment exceptID.
+func LoadResourceUses(tx *gorm.DB, resourceIDs []uuid.UUID, from, to time.Time, exceptID uuid.UUID) ([]ResourceUse, error) {
+	var uses []ResourceUse
+	if len(resourceIDs) == 0 {
+		return uses, nil
+	}
+	if err := tx.Model(&AppointmentResourceAllocation{}).
+		Select("appointment_resource_allocations.resource_id, appointment_resource_allocations.quantity, a.start_time, a.end_time").
+		Joins("JOIN appointments a ON a.id = appointment_resource_allocations.appointment_id AND a.deleted_at IS NULL").
+		Where("appointment_resource_allocations.resource_id IN ?", resourceIDs).
+		Where("a.is_cancelled = ? AND a.id != ?", false, exceptID).
+		Where("a.start_time < ? AND a.end_time > ?", to, from).
+		Scan(&uses).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading resource uses: %w", err))
+	}
+	return uses, nil
+}
+
+// FreeBetween reports whether the units the service needs are left between start and end,
+// given what appointments hold of the resource. Only the peak of the units held at once counts, so
+// back-to-back appointments share a unit.
+func (n ResourceNeed) FreeBetween(uses []ResourceUse, start, end time.Time) bool {
+	// The units held only rise when a use starts
+	points := []time.Time{start}
+	for _, u := range uses {
+		if u.ResourceID == n.ResourceID && u.StartTime.After(start) && u.StartTime.Before(end) {
+			points = append(points, u.StartTime)
+		}
+	}
+	for _, p := range points {
+		var held uint32
+		for _, u := range uses {
+			if u.ResourceID == n.ResourceID && !u.StartTime.After(p) && u.EndTime.After(p) {
+				held += u.Quantity
+			}
+		}
+		if held+n.Quantity > n.Capacity {
+			return false
+		}
+	}
+	return true
+}
+
+// branchNeeds lists what the service of the appointment needs of the resources of its branch.
+func (a *Appointment) branchNeeds(tx *gorm.DB) ([]ResourceNeed, error) {
+	needs, err := LoadResourceNeeds(tx, a.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	var branch []ResourceNeed
+	for _, n := range needs {
+		if n.BranchID == a.BranchID {
+			branch = append(branch, n)
+		}
+	}
+	return branch, nil
+}
+
+// CheckResources fails when a resource the appointment needs is fully held by other
+// appointments at some point of its time.
+func (a *Appointment) CheckResources(tx *gorm.DB) error {
+	needs, err := a.branchNeeds(tx)
+	if err != nil || len(needs) == 0 {
+		return err
+	}
+	ids := make([]uuid.UUID, len(needs))
+	for i, n := range needs {
+		ids[i] = n.ResourceID
+	}
+	uses, err := LoadResourceUses(tx, ids, a.StartTime, a.EndTime, a.ID)
+	if err != nil {
+		return err
+	}
+	for _, n := range needs {
+		if !n.FreeBetween(uses, a.StartTime, a.EndTime) {
+			return lib.Error.BookableResource.Unavailable.WithError(fmt.Errorf("%s is fully booked from %s to %s", n.Name, a.StartTime.Format(time.RFC3339), a.EndTime.Format(time.RFC3339)))
+		}
+	}
+	return nil
+}
+
+// AllocateResources holds for the appointment what its service needs of the resources of its branch.
+func (a *Appointment) AllocateResources(tx *gorm.DB) error {
+	needs, err := a.branchNeeds(tx)
+	if err != nil {
+		return err
+	}
+	for _, n := range needs {
+		allocation := AppointmentResourceAllocation{AppointmentID: a.ID, ResourceID: n.ResourceID, Quantity: n.Quantity}
+		if err := tx.Create(&allocation).Error; err != nil {
+			return lib.Error.General.CreatedError.WithError(fmt.Errorf("error allocating %s: %w", n.Name, err))
+		}
+	}
+	return nil
+}
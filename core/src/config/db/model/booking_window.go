@@ -0,0 +1,102 @@
+package model
+
+import (
+	"fmt"
+	"mynute-go/core/src/lib"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BookingWindow limits how soon and how far ahead appointments can be booked.
+// Companies, branches and services can each set it. A field the service leaves
+// empty falls back to the branch, then to the company, and is not enforced when none sets it.
+type BookingWindow struct {
+	MinNoticeMinutes *uint32 `json:"min_notice_minutes"`                     // Minimum advance notice
+	MaxDaysAhead     *uint16 `json:"max_days_ahead"`                         // Last day that can be booked, counted from today, 0 for today only
+	SameDayCutoff    *string `gorm:"type:varchar(5)" json:"same_day_cutoff"` // HH:MM local time from which today can no longer be booked
+}
+
+const sameDayCutoffLayout = "15:04"
+
+func (w BookingWindow) Validate() error {
+	if w.SameDayCutoff != nil {
+		if _, err := time.Parse(sameDayCutoffLayout, *w.SameDayCutoff); err != nil {
+			return lib.Error.General.BadRequest.WithError(fmt.Errorf("same_day_cutoff must be in the HH:MM format"))
+		}
+	}
+	return nil
+}
+
+// ResolveBookingWindow merges the windows field by field, the first set one winning.
+// Pass them from the most specific to the least: service, branch, company.
+func ResolveBookingWindow(windows ...BookingWindow) BookingWindow {
+	var w BookingWindow
+	for _, o := range windows {
+		if w.MinNoticeMinutes == nil {
+			w.MinNoticeMinutes = o.MinNoticeMinutes
+		}
+		if w.MaxDaysAhead == nil {
+			w.MaxDaysAhead = o.MaxDaysAhead
+		}
+		if w.SameDayCutoff == nil {
+			w.SameDayCutoff = o.SameDayCutoff
+		}
+	}
+	return w
+}
+
+// Check fails when an appointment starting at start can not be booked at now.
+// Days and the cutoff are in loc, the time zone of the branch.
+func (w BookingWindow) Check(start, now time.Time, loc *time.Location) error {
+	if w.MinNoticeMinutes != nil {
+		earliest := now.Add(time.Duration(*w.MinNoticeMinutes) * time.Minute)
+		if start.Before(earliest) {
+			return lib.Error.Appointment.BookingTooSoon.WithError(fmt.Errorf("appointments must be booked at least %d minutes ahead", *w.MinNoticeMinutes))
+		}
+	}
+
+	local, day := now.In(loc), start.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	startDay := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	if w.MaxDaysAhead != nil && startDay.After(today.AddDate(0, 0, int(*w.MaxDaysAhead))) {
+		return lib.Error.Appointment.BookingTooFarAhead.WithError(fmt.Errorf("appointments can be booked at most %d days ahead", *w.MaxDaysAhead))
+	}
+	if w.SameDayCutoff != nil && startDay.Equal(today) {
+		cutoff, err := time.Parse(sameDayCutoffLayout, *w.SameDayCutoff)
+		if err == nil && local.Hour()*60+local.Minute() >= cutoff.Hour()*60+cutoff.Minute() {
+			return lib.Error.Appointment.BookingClosedToday.WithError(fmt.Errorf("same day appointments can only be booked until %s", *w.SameDayCutoff))
+		}
+	}
+	return nil
+}
+
+// LoadBookingWindow resolves the booking window of the service at the branch, with
+// the time zone of the branch.
+func LoadBookingWindow(tx *gorm.DB, service *Service, branchID uuid.UUID) (BookingWindow, *time.Location, error) {
+	var branch Branch
+	if err := tx.Select("id", "time_zone", "min_notice_minutes", "max_days_ahead", "same_day_cutoff").
+		Where("id = ?", branchID).First(&branch).Error; err != nil {
+		return BookingWindow{}, nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading branch booking window: %w", err))
+	}
+	var company Company
+	if err := tx.Select("id", "min_notice_minutes", "max_days_ahead", "same_day_cutoff").
+		Where("id = ?", service.CompanyID).First(&company).Error; err != nil {
+		return BookingWindow{}, nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading company booking window: %w", err))
+	}
+	loc, err := time.LoadLocation(branch.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return ResolveBookingWindow(service.BookingWindow, branch.BookingWindow, company.BookingWindow), loc, nil
+}
+
+// CheckBookingWindow fails when the appointment can not be booked or moved to its start at now.
+func (a *Appointment) CheckBookingWindow(tx *gorm.DB, service *Service, now time.Time) error {
+	window, loc, err := LoadBookingWindow(tx, service, a.BranchID)
+	if err != nil {
+		return err
+	}
+	return window.Check(a.StartTime, now, loc)
+}
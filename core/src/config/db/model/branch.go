@@ -35,6 +35,8 @@ type Branch struct {
 	TimeZone            string                 `gorm:"type:varchar(100)" json:"time_zone" validate:"required,myTimezoneValidation"` // Time zone in IANA format (e.g., "America/New_York", "America/Sao_Paulo", etc.)
 	TotalServiceDensity int32                  `gorm:"not null;default:-1" json:"total_service_density"`
 	Design              mJSON.DesignConfig     `gorm:"type:jsonb" json:"design"`
+	BookingWindow
+	Rating RatingSummary `gorm:"embedded;embeddedPrefix:rating_" json:"rating"` // Of the published reviews
 }
 
 func (Branch) TableName() string { return "branches" }
@@ -50,7 +52,7 @@ func (b *Branch) BeforeCreate(tx *gorm.DB) error {
 	if err := lib.MyCustomStructValidator(b); err != nil {
 		return err
 	}
-	return nil
+	return b.BookingWindow.Validate()
 }
 
 func (b *Branch) BeforeUpdate(tx *gorm.DB) error {
@@ -65,6 +67,9 @@ func (b *Branch) BeforeUpdate(tx *gorm.DB) error {
 			return lib.Error.General.UpdatedError.WithError(errors.New("the CompanyID cannot be changed after creation"))
 		}
 	}
+	if err := b.BookingWindow.Validate(); err != nil {
+		return err
+	}
 
 	var serviceDensity []BranchServiceDensity
 	if err := tx.Find(&serviceDensity, "branch_id = ?", b.ID).Error; err != nil {
@@ -224,8 +229,10 @@ func (b *Branch) ValidateEmployeeWorkRangeTime(tx *gorm.DB, ewr *EmployeeWorkRan
 		return lib.Error.General.BadRequest.WithError(fmt.Errorf("employee work range branch ID %s does not match branch ID %s", ewr.BranchID, b.ID))
 	}
 
+	// Branch ranges of the day before may span midnight into the employee range
+	yesterday := (ewr.Weekday + 6) % 7
 	var bwrs []BranchWorkRange
-	if err := tx.Find(&bwrs, "branch_id = ? AND weekday = ?", b.ID, ewr.Weekday).Error; err != nil {
+	if err := tx.Find(&bwrs, "branch_id = ? AND weekday IN ?", b.ID, []time.Weekday{ewr.Weekday, yesterday}).Error; err != nil {
 		return lib.Error.General.InternalError.WithError(fmt.Errorf("failed to retrieve branch work schedule: %w", err))
 	}
 
@@ -239,14 +246,15 @@ func (b *Branch) ValidateEmployeeWorkRangeTime(tx *gorm.DB, ewr *EmployeeWorkRan
 	}
 
 	for _, bws := range bwrs { // bws is the Branch Work Schedule
-		if bws.Weekday != ewr.Weekday {
-			continue // Skip if the weekday does not match
-		}
 		bwsTZ, err := bws.GetTimeZone()
 		if err != nil {
 			return lib.Error.General.BadRequest.WithError(fmt.Errorf("branch work range (%s) has invalid time zone: %w", bws.ID, err))
 		}
-		if lib.TimeRangeFullyContained(bws.StartTime, bws.EndTime, bwsTZ, ewr.StartTime, ewr.EndTime, ewrTZ) {
+		bwsStart, bwsEnd := bws.StartTime, lib.OvernightEnd(bws.StartTime, bws.EndTime)
+		if bws.Weekday != ewr.Weekday {
+			bwsStart, bwsEnd = bwsStart.AddDate(0, 0, -1), bwsEnd.AddDate(0, 0, -1)
+		}
+		if lib.TimeRangeFullyContained(bwsStart, bwsEnd, bwsTZ, ewr.StartTime, lib.OvernightEnd(ewr.StartTime, ewr.EndTime), ewrTZ) {
 			return nil
 		}
 	}
@@ -270,8 +278,7 @@ func (b *Branch) ValidateBranchWorkRangeTime(tx *gorm.DB, newRange *BranchWorkRa
 	var existing []BranchWorkRange
 
 	err := tx.
-		Where("branch_id = ? AND weekday = ? AND id != ?", newRange.BranchID, newRange.Weekday, newRange.ID).
-		Where("start_time <= ? AND end_time >= ?", newRange.EndTime, newRange.StartTime).
+		Where("branch_id = ? AND weekday IN ? AND id != ?", newRange.BranchID, newRange.AdjacentWeekdays(), newRange.ID).
 		Find(&existing).Error
 	if err != nil {
 		return lib.Error.General.InternalError.WithError(fmt.Errorf("failed to fetch existing work ranges: %w", err))
@@ -1,9 +1,13 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
 	mJSON "mynute-go/core/src/config/db/model/json"
 	"mynute-go/core/src/lib"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -25,7 +29,33 @@ type ClientMeta struct {
 	Phone    string         `gorm:"type:varchar(20);uniqueIndex" validate:"required,e164" json:"phone"`
 	Password string         `gorm:"type:varchar(255)" validate:"required,myPasswordValidation" json:"password"`
 	Verified bool           `gorm:"default:false" json:"verified"`
+	IsGuest  bool           `gorm:"not null;default:false" json:"is_guest"` // Booked without an account, has no password
 	Meta     mJSON.UserMeta `gorm:"type:jsonb" json:"meta"`
+	// Details a guest booked with again, applied once it proves it owns the email
+	PendingDetails *GuestDetails `gorm:"type:jsonb" json:"-"`
+}
+
+// GuestDetails are the name and phone a guest books with.
+type GuestDetails struct {
+	Name    string `json:"name"`
+	Surname string `json:"surname"`
+	Phone   string `json:"phone"`
+}
+
+func (g GuestDetails) Value() (driver.Value, error) {
+	return json.Marshal(g)
+}
+
+func (g *GuestDetails) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		if str, ok := value.(string); ok {
+			bytes = []byte(str)
+		} else {
+			return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+		}
+	}
+	return json.Unmarshal(bytes, g)
 }
 
 // Updated Client model
@@ -37,6 +67,10 @@ func (Client) TableName() string  { return "public.clients" }
 func (Client) SchemaType() string { return "public" }
 
 func (c *Client) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.IsGuest {
+		c.Password = ""
+		return c.validateGuest()
+	}
 	if err := lib.MyCustomStructValidator(c); err != nil {
 		return err
 	}
@@ -65,6 +99,101 @@ func (c *Client) BeforeUpdate(tx *gorm.DB) (err error) {
 	return nil
 }
 
+// validateGuest validates a client like BeforeCreate, without a password.
+func (c *Client) validateGuest() error {
+	guest := struct {
+		Name    string `validate:"required,min=3,max=100"`
+		Surname string `validate:"required,min=3,max=100"`
+		Email   string `validate:"required,email"`
+		Phone   string `validate:"required,e164"`
+	}{c.Name, c.Surname, c.Email, c.Phone}
+	return lib.MyCustomStructValidator(guest)
+}
+
+// GuestClient returns the guest client with the email, creating it when there is none.
+// It fails when the email belongs to a client with an account. The details given for an
+// existing guest are only kept as pending, as whoever books has not proven it owns the
+// email yet; ApplyPendingDetails applies them once it has.
+func GuestClient(tx *gorm.DB, name, surname, email, phone string) (*Client, error) {
+	email = NormalizeEmail(email)
+	var client Client
+	err := tx.Where("LOWER(email) = ?", email).First(&client).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		client = Client{ClientMeta: ClientMeta{Name: name, Surname: surname, Email: email, Phone: phone, IsGuest: true}}
+		if err := tx.Create(&client).Error; err != nil {
+			return nil, lib.Error.General.CreatedError.WithError(err)
+		}
+		return &client, nil
+	}
+	if err != nil {
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	if !client.IsGuest {
+		return nil, lib.Error.Client.EmailExists.WithError(fmt.Errorf("the email has an account, log in to book"))
+	}
+
+	pending := Client{ClientMeta: ClientMeta{Name: name, Surname: surname, Email: client.Email, Phone: phone}}
+	if err := pending.validateGuest(); err != nil {
+		return nil, err
+	}
+	client.PendingDetails = &GuestDetails{Name: name, Surname: surname, Phone: phone}
+	// A fresh model keeps BeforeUpdate from checking a password the guest does not have
+	if err := tx.Model(&Client{}).Where("id = ?", client.ID).
+		Update("pending_details", client.PendingDetails).Error; err != nil {
+		return nil, lib.Error.General.UpdatedError.WithError(err)
+	}
+	return &client, nil
+}
+
+// ApplyPendingDetails replaces the details of the guest with the ones it booked with last,
+// once it verified the email.
+func (c *Client) ApplyPendingDetails(tx *gorm.DB) error {
+	if !c.IsGuest || c.PendingDetails == nil {
+		return nil
+	}
+	pending := *c.PendingDetails
+	if err := tx.Model(&Client{}).Where("id = ?", c.ID).Updates(map[string]any{
+		"name":            pending.Name,
+		"surname":         pending.Surname,
+		"phone":           pending.Phone,
+		"pending_details": nil,
+	}).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
+	}
+	c.Name, c.Surname, c.Phone = pending.Name, pending.Surname, pending.Phone
+	c.PendingDetails = nil
+	return nil
+}
+
+// NormalizeEmail trims and lowercases the email, so that the same address given in
+// another case finds the same client.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// UpgradeGuest turns the guest into a client with an account, keeping its ID and so its
+// appointments.
+func (c *Client) UpgradeGuest(tx *gorm.DB, password string) error {
+	if !c.IsGuest {
+		return lib.Error.Client.NotGuest
+	}
+	if err := lib.ValidatorV10.Var(password, "required,myPasswordValidation"); err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("password invalid"))
+	}
+	c.Password = password
+	if err := c.HashPassword(); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	if err := tx.Model(&Client{}).Where("id = ?", c.ID).Updates(map[string]any{
+		"password": c.Password,
+		"is_guest": false,
+	}).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
+	}
+	c.IsGuest = false
+	return nil
+}
+
 func (c *Client) MatchPassword(hashedPass string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPass), []byte(c.Password))
 	return err == nil
@@ -96,6 +225,7 @@ func (c *Client) AddAppointment(a *Appointment, tx *gorm.DB) error {
 	appointment := ClientAppointment{
 		AppointmentID: a.ID,
 		ClientID:      c.ID,
+		DependentID:   a.DependentID,
 		CompanyID:     a.CompanyID,
 		StartTime:     a.StartTime,
 		EndTime:       a.EndTime,
@@ -0,0 +1,69 @@
+package model
+
+import (
+	mJSON "mynute-go/core/src/config/db/model/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// --- Client data request kinds and status --- //
+
+type ClientDataRequestKind string
+
+const (
+	ClientDataExport  ClientDataRequestKind = "EXPORT"
+	ClientDataErasure ClientDataRequestKind = "ERASURE"
+)
+
+type ClientDataRequestStatus string
+
+const (
+	ClientDataRequestPending   ClientDataRequestStatus = "PENDING"
+	ClientDataRequestCompleted ClientDataRequestStatus = "COMPLETED"
+)
+
+// ClientDataExportTTL is how long a finished export can be downloaded.
+const ClientDataExportTTL = 7 * 24 * time.Hour
+
+// ClientDataRequest is a data access (export) or deletion (erasure) request of a client,
+// as required by LGPD and GDPR. It is the audit trail of the request: who asked, from
+// where, when it was done and which companies it reached. The work itself runs in the
+// outbox worker.
+type ClientDataRequest struct {
+	BaseModel
+	ClientID        uuid.UUID               `gorm:"type:uuid;not null;index" json:"client_id"`
+	Kind            ClientDataRequestKind   `gorm:"type:varchar(20);not null" json:"kind"`
+	Status          ClientDataRequestStatus `gorm:"type:varchar(20);not null;default:'PENDING'" json:"status"`
+	RequestedByID   *uuid.UUID              `gorm:"type:uuid" json:"requested_by_id"`
+	RequestedByType string                  `gorm:"type:varchar(20)" json:"requested_by_type"`
+	RequestIP       string                  `gorm:"type:varchar(45)" json:"request_ip"`
+	Endpoint        string                  `gorm:"type:varchar(255)" json:"endpoint"`
+	CompanyIDs      UUIDList                `gorm:"type:jsonb" json:"company_ids"` // Companies the request reached, set when completed
+	CompletedAt     *time.Time              `json:"completed_at"`
+	ExpiresAt       *time.Time              `json:"expires_at"`          // Exports only, the download is refused afterwards
+	ExportJSON      datatypes.JSON          `gorm:"type:jsonb" json:"-"` // Exports only
+	ExportCSV       string                  `gorm:"type:text" json:"-"`  // Exports only
+}
+
+func (ClientDataRequest) TableName() string  { return "public.client_data_requests" }
+func (ClientDataRequest) SchemaType() string { return "public" }
+
+// NewClientDataRequest builds a pending request of the client, audited with who asked.
+func NewClientDataRequest(clientID uuid.UUID, kind ClientDataRequestKind, audit mJSON.Audit) *ClientDataRequest {
+	return &ClientDataRequest{
+		ClientID:        clientID,
+		Kind:            kind,
+		Status:          ClientDataRequestPending,
+		RequestedByID:   audit.ActorID,
+		RequestedByType: audit.ActorType,
+		RequestIP:       audit.IP,
+		Endpoint:        audit.Endpoint,
+	}
+}
+
+// Downloadable reports whether the export can be downloaded at now.
+func (r *ClientDataRequest) Downloadable(now time.Time) bool {
+	return r.Kind == ClientDataExport && r.Status == ClientDataRequestCompleted && r.ExpiresAt != nil && now.Before(*r.ExpiresAt)
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"mynute-go/core/src/lib"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ClientDependent is someone a client books for, like a child or an elderly relative,
+// without an account of their own. It is shared by every company, like the client.
+type ClientDependent struct {
+	BaseModel
+	ClientID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"client_id"` // Account holder
+	Name      string     `gorm:"type:varchar(100);not null" validate:"required,min=2,max=100" json:"name"`
+	BirthDate *time.Time `gorm:"type:date" json:"birth_date"`
+	Notes     string     `gorm:"type:text" validate:"max=1000" json:"notes"` // e.g. allergies, who accompanies them
+}
+
+func (ClientDependent) TableName() string  { return "public.client_dependents" }
+func (ClientDependent) SchemaType() string { return "public" }
+
+func (d *ClientDependent) BeforeCreate(tx *gorm.DB) error {
+	return d.Validate()
+}
+
+func (d *ClientDependent) BeforeUpdate(tx *gorm.DB) error {
+	return d.Validate()
+}
+
+func (d *ClientDependent) Validate() error {
+	if err := lib.MyCustomStructValidator(d); err != nil {
+		return err
+	}
+	if d.BirthDate != nil && d.BirthDate.After(time.Now()) {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("birth date cannot be in the future"))
+	}
+	return nil
+}
+
+// CheckDependentOf fails unless the dependent belongs to the client.
+func CheckDependentOf(tx *gorm.DB, clientID, dependentID uuid.UUID) error {
+	var dependent ClientDependent
+	if err := tx.Select("id").Where("id = ? AND client_id = ?", dependentID, clientID).First(&dependent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return lib.Error.Client.DependentNotFound
+		}
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading dependent: %w", err))
+	}
+	return nil
+}
+
+// WhereAttendee narrows the appointments of the client to those attended by the dependent,
+// or by the client itself when dependentID is nil. Each attendee has its own schedule.
+func WhereAttendee(tx *gorm.DB, clientID uuid.UUID, dependentID *uuid.UUID) *gorm.DB {
+	if dependentID == nil {
+		return tx.Where("client_id = ? AND dependent_id IS NULL", clientID)
+	}
+	return tx.Where("client_id = ? AND dependent_id = ?", clientID, *dependentID)
+}
@@ -0,0 +1,189 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mynute-go/core/src/lib"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ClientProfile is what a company keeps about a client. Clients are shared by every
+// company, profiles are not. A profile is created with the first appointment of the
+// client at the company.
+type ClientProfile struct {
+	BaseModel
+	CompanyID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	ClientID           uuid.UUID  `gorm:"type:uuid;not null;index" json:"client_id"`
+	Name               string     `gorm:"type:varchar(100)" json:"name"`    // How the company knows the client, empty to use the client's own
+	Surname            string     `gorm:"type:varchar(100)" json:"surname"` // How the company knows the client, empty to use the client's own
+	Phone              string     `gorm:"type:varchar(20)" json:"phone"`    // Number the company reaches the client at, empty to use the client's own
+	Tags               TagList    `gorm:"type:jsonb" json:"tags"`
+	Notes              string     `gorm:"type:text" json:"notes"`       // Private to the company
+	Preferences        string     `gorm:"type:text" json:"preferences"` // e.g. favorite employee, allergies
+	Birthday           *time.Time `gorm:"type:date" json:"birthday"`
+	MarketingConsent   bool       `gorm:"not null;default:false" json:"marketing_consent"`
+	MarketingConsentAt *time.Time `json:"marketing_consent_at"` // When consent was last given or withdrawn
+}
+
+const ClientProfileTableName = "client_profiles"
+
+func (ClientProfile) TableName() string  { return ClientProfileTableName }
+func (ClientProfile) SchemaType() string { return "company" }
+func (ClientProfile) Indexes() map[string]string {
+	return map[string]string{
+		"idx_client_profile_company_client": fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_client_profile_company_client ON %s (company_id, client_id) WHERE deleted_at IS NULL", ClientProfileTableName),
+		"idx_client_profile_tags":           fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_client_profile_tags ON %s USING GIN (tags)", ClientProfileTableName),
+	}
+}
+
+func (p *ClientProfile) BeforeCreate(tx *gorm.DB) error {
+	p.Tags = NormalizeTags(p.Tags)
+	return p.Validate()
+}
+
+func (p *ClientProfile) BeforeUpdate(tx *gorm.DB) error {
+	p.Tags = NormalizeTags(p.Tags)
+	return p.Validate()
+}
+
+func (p *ClientProfile) Validate() error {
+	if len(p.Tags) > 20 {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("a client can have at most 20 tags"))
+	}
+	for _, tag := range p.Tags {
+		if len(tag) > 40 {
+			return lib.Error.General.BadRequest.WithError(fmt.Errorf("tag %q is longer than 40 characters", tag))
+		}
+	}
+	if len(p.Name) > 100 || len(p.Surname) > 100 {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("name and surname must be at most 100 characters"))
+	}
+	if p.Phone != "" {
+		if err := lib.ValidatorV10.Var(p.Phone, "e164"); err != nil {
+			return lib.Error.General.BadRequest.WithError(fmt.Errorf("phone %q is not a valid E.164 number", p.Phone))
+		}
+	}
+	if p.Birthday != nil && p.Birthday.After(time.Now()) {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("birthday cannot be in the future"))
+	}
+	return nil
+}
+
+// EnsureClientProfile returns the profile of the client at the company, creating an
+// empty one when there is none.
+func EnsureClientProfile(tx *gorm.DB, companyID, clientID uuid.UUID) (*ClientProfile, error) {
+	var profile ClientProfile
+	err := tx.Where("company_id = ? AND client_id = ?", companyID, clientID).First(&profile).Error
+	if err == nil {
+		return &profile, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading client profile: %w", err))
+	}
+	profile = ClientProfile{CompanyID: companyID, ClientID: clientID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&profile).Error; err != nil {
+		return nil, lib.Error.General.CreatedError.WithError(fmt.Errorf("error creating client profile: %w", err))
+	}
+	if profile.ID == uuid.Nil {
+		// Created meanwhile by another request
+		if err := tx.Where("company_id = ? AND client_id = ?", companyID, clientID).First(&profile).Error; err != nil {
+			return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading client profile: %w", err))
+		}
+	}
+	return &profile, nil
+}
+
+// ClientStats is the history of a client at a company.
+type ClientStats struct {
+	ClientID          uuid.UUID  `json:"client_id"`
+	Appointments      int64      `json:"appointments"`        // Booked, cancelled or not
+	Fulfilled         int64      `json:"fulfilled"`           // Attended
+	Cancelled         int64      `json:"cancelled"`           // Cancelled by anyone
+	TotalSpent        int64      `json:"total_spent"`         // Price minus discounts of the attended appointments, in cents
+	FirstVisitAt      *time.Time `json:"first_visit_at"`      // Start of the first appointment not cancelled
+	LastVisitAt       *time.Time `json:"last_visit_at"`       // Start of the last past appointment not cancelled
+	NextAppointmentAt *time.Time `json:"next_appointment_at"` // Start of the next appointment not cancelled
+}
+
+// LoadClientStats computes the stats of the clients at the company, by client ID,
+// archived appointments included.
+func LoadClientStats(tx *gorm.DB, companyID uuid.UUID, clientIDs []uuid.UUID, now time.Time) (map[uuid.UUID]ClientStats, error) {
+	stats := make(map[uuid.UUID]ClientStats, len(clientIDs))
+	if len(clientIDs) == 0 {
+		return stats, nil
+	}
+	const columns = "client_id, start_time, price, discount, is_fulfilled, is_cancelled"
+	history := tx.Raw(fmt.Sprintf(
+		"SELECT %[1]s FROM %[2]s WHERE company_id = @company AND client_id IN @clients AND deleted_at IS NULL UNION ALL SELECT %[1]s FROM %[3]s WHERE company_id = @company AND client_id IN @clients AND deleted_at IS NULL",
+		columns, AppointmentTableName, AppointmentArchiveTableName,
+	), map[string]any{"company": companyID, "clients": clientIDs})
+
+	var rows []ClientStats
+	if err := tx.Table("(?) AS history", history).
+		Select(`client_id,
+			COUNT(*) AS appointments,
+			COUNT(*) FILTER (WHERE is_fulfilled) AS fulfilled,
+			COUNT(*) FILTER (WHERE is_cancelled) AS cancelled,
+			COALESCE(SUM(price - discount - loyalty_discount) FILTER (WHERE is_fulfilled AND NOT is_cancelled), 0) AS total_spent,
+			MIN(start_time) FILTER (WHERE NOT is_cancelled) AS first_visit_at,
+			MAX(start_time) FILTER (WHERE NOT is_cancelled AND start_time <= ?) AS last_visit_at,
+			MIN(start_time) FILTER (WHERE NOT is_cancelled AND start_time > ?) AS next_appointment_at`, now, now).
+		Group("client_id").
+		Scan(&rows).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error computing client stats: %w", err))
+	}
+	for _, row := range rows {
+		stats[row.ClientID] = row
+	}
+	return stats, nil
+}
+
+// TagList is a list of labels stored as JSONB.
+type TagList []string
+
+// NormalizeTags trims and lowercases the tags, dropping empty and repeated ones.
+func NormalizeTags(tags []string) TagList {
+	out := make(TagList, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+func (l TagList) Value() (driver.Value, error) {
+	if l == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal([]string(l))
+}
+
+func (l *TagList) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		if value == nil {
+			*l = nil
+			return nil
+		}
+		if str, ok := value.(string); ok {
+			bytes = []byte(str)
+		} else {
+			return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+		}
+	}
+	if len(bytes) == 0 {
+		*l = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, l)
+}
@@ -19,6 +19,14 @@ type Company struct {
 	Subdomains []*Subdomain       `gorm:"constraint:OnDelete:CASCADE;" json:"subdomains"`
 	Sectors    []*Sector          `gorm:"many2many:company_sectors;constraint:OnDelete:CASCADE;" json:"sectors"`
 	Design     mJSON.DesignConfig `gorm:"type:jsonb" json:"design"`
+	CompanyPix
+	BookingWindow
+}
+
+// CompanyPix is where the company receives Pix payments.
+type CompanyPix struct {
+	PixKey  string `gorm:"type:varchar(77)" json:"pix_key"`  // E-mail, phone, CPF/CNPJ or random key
+	PixCity string `gorm:"type:varchar(15)" json:"pix_city"` // City shown to the payer, as registered in the bank
 }
 
 func (Company) TableName() string  { return "public.companies" }
@@ -32,11 +40,11 @@ func (c *Company) BeforeCreate(tx *gorm.DB) error {
 	if err := lib.MyCustomStructValidator(c); err != nil {
 		return err
 	}
-	return nil
+	return c.BookingWindow.Validate()
 }
 
 func (c *Company) BeforeUpdate(tx *gorm.DB) error {
-	return nil
+	return c.BookingWindow.Validate()
 }
 
 func (c *Company) AfterCreate(tx *gorm.DB) error {
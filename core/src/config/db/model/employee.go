@@ -32,16 +32,43 @@ type Employee struct {
 	TimeZone            string              `gorm:"type:varchar(100)" json:"time_zone" validate:"required,myTimezoneValidation"` // Time zone in IANA format (e.g., "America/New_York", "America/Sao_Paulo", etc.)
 	TotalServiceDensity uint32              `gorm:"not null;default:1" json:"total_service_density"`                             // Total service density for the employee
 	Verified            bool                `gorm:"default:false" json:"verified"`
+	Status              string              `gorm:"type:varchar(20);not null;default:'ACTIVE'" json:"status"` // INVITED and SUSPENDED employees can not log in
 	Meta                mJSON.UserMeta      `gorm:"type:jsonb" json:"meta"`
+	Rating              RatingSummary       `gorm:"embedded;embeddedPrefix:rating_" json:"rating"` // Of the published reviews
 }
 
 func (Employee) TableName() string  { return "employees" }
 func (Employee) SchemaType() string { return "company" }
 
+// Account states of an employee.
+const (
+	EmployeeInvited   = "INVITED"   // Created by an invitation not accepted yet
+	EmployeeActive    = "ACTIVE"    // Can log in
+	EmployeeSuspended = "SUSPENDED" // Barred from logging in by a manager
+)
+
+// CanLogin tells whether the state of the employee lets them log in.
+func (e *Employee) CanLogin() error {
+	switch e.Status {
+	case EmployeeInvited:
+		return lib.Error.Employee.InvitationPending
+	case EmployeeSuspended:
+		return lib.Error.Employee.Suspended
+	}
+	return nil
+}
+
 func (e *Employee) BeforeCreate(tx *gorm.DB) error {
 	if err := lib.MyCustomStructValidator(e); err != nil {
 		return err
 	}
+	switch e.Status {
+	case "":
+		e.Status = EmployeeActive
+	case EmployeeInvited, EmployeeActive, EmployeeSuspended:
+	default:
+		return lib.Error.Employee.InvalidStatus.WithError(fmt.Errorf("unknown status %q", e.Status))
+	}
 	if err := e.HashPassword(); err != nil {
 		return err
 	}
@@ -53,11 +80,15 @@ func (e *Employee) BeforeUpdate(tx *gorm.DB) error {
 	// The ID field is now populated by the handler before calling Updates()
 	if e.ID != uuid.Nil {
 		var existingEmployee Employee
-		if err := tx.Unscoped().Select("company_id").Where("id = ?", e.ID).Take(&existingEmployee).Error; err == nil {
+		if err := tx.Unscoped().Select("company_id", "status").Where("id = ?", e.ID).Take(&existingEmployee).Error; err == nil {
 			// Check if CompanyID is being changed
 			if e.CompanyID != uuid.Nil && existingEmployee.CompanyID != e.CompanyID {
 				return lib.Error.General.UpdatedError.WithError(fmt.Errorf("the CompanyID cannot be changed after creation"))
 			}
+			// The status follows the invitation and the status endpoint, it is only set by them
+			if e.Status != "" && existingEmployee.Status != e.Status {
+				return lib.Error.Employee.InvalidStatus.WithError(fmt.Errorf("the status of an employee can not be updated directly"))
+			}
 		}
 	}
 
@@ -111,8 +142,7 @@ func (e *Employee) GetWorkRangeForDay(day time.Weekday) []EmployeeWorkRange {
 func (e *Employee) ValidateEmployeeWorkRangeTime(tx *gorm.DB, ewr *EmployeeWorkRange) error {
 	var emp_work_schedule []EmployeeWorkRange
 	if err := tx.
-		Where("employee_id = ? AND weekday = ? AND id != ?", e.ID, ewr.Weekday, ewr.ID).
-		Where("start_time <= ? AND end_time >= ?", ewr.EndTime, ewr.StartTime).
+		Where("employee_id = ? AND weekday IN ? AND id != ?", e.ID, ewr.AdjacentWeekdays(), ewr.ID).
 		Find(&emp_work_schedule).Error; err != nil {
 		if err != gorm.ErrRecordNotFound {
 			return lib.Error.General.InternalError.WithError(err)
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"fmt"
+	"mynute-go/core/src/lib"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvitationTTL is how long the emailed link of an invitation is valid, each resend
+// starting it over.
+const InvitationTTL = 7 * 24 * time.Hour
+
+// InvitationResendInterval is the least time between two emails of the same invitation.
+const InvitationResendInterval = time.Minute
+
+// Statuses of an employee invitation. Pending invitations past ExpiresAt are expired.
+const (
+	InvitationPending  = "PENDING"
+	InvitationAccepted = "ACCEPTED"
+	InvitationRevoked  = "REVOKED"
+)
+
+// EmployeeInvitation is the emailed link through which an invited employee sets their
+// password and activates their account. An employee has at most one pending invitation.
+type EmployeeInvitation struct {
+	BaseModel
+	CompanyID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	EmployeeID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"employee_id"`
+	Email       string     `gorm:"type:varchar(100);not null" json:"email"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'PENDING'" json:"status"`
+	Token       *string    `gorm:"type:varchar(64);uniqueIndex" json:"-"` // Of the emailed link, only valid while pending
+	Language    string     `gorm:"type:varchar(5);not null;default:'en'" json:"language"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	SentCount   int        `gorm:"not null;default:0" json:"sent_count"`
+	LastSentAt  *time.Time `json:"last_sent_at"`
+	InvitedByID *uuid.UUID `gorm:"type:uuid" json:"invited_by_id"` // Employee who sent or last resent it
+	AcceptedAt  *time.Time `json:"accepted_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+}
+
+const EmployeeInvitationTableName = "employee_invitations"
+
+func (EmployeeInvitation) TableName() string  { return EmployeeInvitationTableName }
+func (EmployeeInvitation) SchemaType() string { return "company" }
+
+func (i *EmployeeInvitation) BeforeUpdate(tx *gorm.DB) error {
+	if tx.Statement.Changed("CompanyID") || tx.Statement.Changed("EmployeeID") {
+		return lib.Error.Invitation.Invalid.WithError(fmt.Errorf("the employee of an invitation can not be changed"))
+	}
+	return nil
+}
+
+// Expired tells whether the pending invitation can no longer be accepted at now.
+func (i *EmployeeInvitation) Expired(now time.Time) bool {
+	return i.Status == InvitationPending && !now.Before(i.ExpiresAt)
+}
+
+// DisplayStatus is the status of the invitation as shown to managers, EXPIRED for the
+// pending ones past their expiry.
+func (i *EmployeeInvitation) DisplayStatus(now time.Time) string {
+	if i.Expired(now) {
+		return "EXPIRED"
+	}
+	return i.Status
+}
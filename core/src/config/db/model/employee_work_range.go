@@ -128,20 +128,7 @@ func (ewr *EmployeeWorkRange) HasService(tx *gorm.DB, serviceID uuid.UUID) error
 }
 
 func (ewr *EmployeeWorkRange) Overlaps(other *EmployeeWorkRange) (bool, error) {
-	if ewr.Weekday != other.Weekday {
-		return false, nil
-	}
-
-	loc1, err := ewr.GetTimeZone()
-	if err != nil {
-		return false, err
-	}
-	loc2, err := other.GetTimeZone()
-	if err != nil {
-		return false, err
-	}
-
-	return lib.TimeRangeOverlaps(ewr.StartTime, ewr.EndTime, loc1, other.StartTime, other.EndTime, loc2), nil
+	return ewr.WorkRangeBase.Overlaps(&other.WorkRangeBase)
 }
 
 func (ewr *EmployeeWorkRange) AddServices(tx *gorm.DB, services ...*Service) error {
@@ -76,6 +76,33 @@ var CancelAppointmentByID = &EndPoint{
 	DenyUnauthorized: true,
 	Resource:         AppointmentResource,
 }
+var GetAppointmentHistory = &EndPoint{
+	Path:             "/appointment/:id/history",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetAppointmentHistory",
+	Description:      "View the change history of an appointment",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         AppointmentResource,
+}
+var GetAppointmentReceipt = &EndPoint{
+	Path:             "/appointment/:id/receipt",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetAppointmentReceipt",
+	Description:      "View the receipt of a fulfilled or paid appointment, issuing it the first time",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         AppointmentResource,
+}
+var GetAppointmentIntake = &EndPoint{
+	Path:             "/appointment/:id/intake",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetAppointmentIntake",
+	Description:      "View the intake form answers and signed consents of the attendee of an appointment",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         AppointmentResource,
+}
 
 // --- Auth Endpoints --- //
 
@@ -262,6 +289,42 @@ var GetBranchAppointmentsById = &EndPoint{
 	DenyUnauthorized: true,
 	Resource:         BranchResource,
 }
+var CreateBookableResource = &EndPoint{
+	Path:             "/branch/:id/resource",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "CreateBookableResource",
+	Description:      "Add a room, chair or equipment to a branch",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         BranchResource,
+}
+var GetBookableResources = &EndPoint{
+	Path:             "/branch/:id/resources",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetBookableResources",
+	Description:      "List the rooms, chairs and equipment of a branch",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         BranchResource,
+}
+var UpdateBookableResource = &EndPoint{
+	Path:             "/branch/:id/resource/:resource_id",
+	Method:           namespace.PatchActionMethod,
+	ControllerName:   "UpdateBookableResource",
+	Description:      "Update a room, chair or equipment of a branch",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         BranchResource,
+}
+var DeleteBookableResource = &EndPoint{
+	Path:             "/branch/:id/resource/:resource_id",
+	Method:           namespace.DeleteActionMethod,
+	ControllerName:   "DeleteBookableResource",
+	Description:      "Delete a room, chair or equipment of a branch",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         BranchResource,
+}
 
 // --- Client Endpoints --- //
 
@@ -307,6 +370,90 @@ var VerifyClientEmail = &EndPoint{
 	ControllerName: "VerifyClientEmail",
 	Description:    "Verify client email code",
 }
+var CreateGuestClient = &EndPoint{
+	Path:           "/client/guest",
+	Method:         namespace.CreateActionMethod,
+	ControllerName: "CreateGuestClient",
+	Description:    "Create guest client and send verification code",
+}
+var VerifyGuestClient = &EndPoint{
+	Path:           "/client/guest/verify",
+	Method:         namespace.CreateActionMethod,
+	ControllerName: "VerifyGuestClient",
+	Description:    "Verify guest client email code",
+}
+var UpgradeGuestClient = &EndPoint{
+	Path:             "/client/:id/upgrade",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "UpgradeGuestClient",
+	Description:      "Upgrade guest client to account",
+	DenyUnauthorized: true,
+	Resource:         ClientResource,
+}
+var RequestClientDataExport = &EndPoint{
+	Path:             "/client/:id/data/export",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "RequestClientDataExport",
+	Description:      "Request export of client data",
+	DenyUnauthorized: true,
+	Resource:         ClientResource,
+}
+var RequestClientDataErasure = &EndPoint{
+	Path:             "/client/:id/data/erasure",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "RequestClientDataErasure",
+	Description:      "Request erasure of client data",
+	DenyUnauthorized: true,
+	Resource:         ClientResource,
+}
+var GetClientDataRequests = &EndPoint{
+	Path:             "/client/:id/data/requests",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetClientDataRequests",
+	Description:      "View client data requests",
+	DenyUnauthorized: true,
+	Resource:         ClientResource,
+}
+var DownloadClientDataExport = &EndPoint{
+	Path:             "/client/:id/data/requests/:request_id/download",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "DownloadClientDataExport",
+	Description:      "Download client data export",
+	DenyUnauthorized: true,
+	Resource:         ClientResource,
+}
+var CreateClientDependent = &EndPoint{
+	Path:             "/client/:id/dependent",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "CreateClientDependent",
+	Description:      "Create client dependent",
+	DenyUnauthorized: true,
+	Resource:         ClientResource,
+}
+var GetClientDependents = &EndPoint{
+	Path:             "/client/:id/dependents",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetClientDependents",
+	Description:      "View client dependents",
+	DenyUnauthorized: true,
+	Resource:         ClientResource,
+}
+var UpdateClientDependent = &EndPoint{
+	Path:             "/client/:id/dependent/:dependent_id",
+	Method:           namespace.PatchActionMethod,
+	ControllerName:   "UpdateClientDependent",
+	Description:      "Update client dependent",
+	DenyUnauthorized: true,
+	Resource:         ClientResource,
+}
+var DeleteClientDependent = &EndPoint{
+	Path:             "/client/:id/dependent/:dependent_id",
+	Method:           namespace.DeleteActionMethod,
+	ControllerName:   "DeleteClientDependent",
+	Description:      "Delete client dependent",
+	DenyUnauthorized: true,
+	Resource:         ClientResource,
+}
 var GetClientByEmail = &EndPoint{
 	Path:             "/client/email/:email",
 	Method:           namespace.ViewActionMethod,
@@ -364,6 +511,33 @@ var GetClientAppointmentsById = &EndPoint{
 	NeedsCompanyId:   true,
 	Resource:         ClientResource,
 }
+var GetClientProfile = &EndPoint{
+	Path:             "/client/:client_id/profile",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetClientProfile",
+	Description:      "View the company profile of a client",
+	DenyUnauthorized: true,
+	NeedsCompanyId:   true,
+	Resource:         ClientResource,
+}
+var UpdateClientProfile = &EndPoint{
+	Path:             "/client/:client_id/profile",
+	Method:           namespace.PatchActionMethod,
+	ControllerName:   "UpdateClientProfile",
+	Description:      "Update the tags, notes, birthday, preferences or marketing consent of a client at the company",
+	DenyUnauthorized: true,
+	NeedsCompanyId:   true,
+	Resource:         ClientResource,
+}
+var GetCompanyClients = &EndPoint{
+	Path:             "/company/:company_id/clients",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetCompanyClients",
+	Description:      "Search the clients of a company",
+	DenyUnauthorized: true,
+	NeedsCompanyId:   true,
+	Resource:         CompanyResource,
+}
 
 // --- Company Endpoints --- //
 
@@ -843,14 +1017,604 @@ var GetServiceAvailability = &EndPoint{
 	NeedsCompanyId: true,
 	Resource:       ServiceResource,
 }
+var GetServiceNextAvailable = &EndPoint{
+	Path:           "/service/:id/next-available",
+	Method:         namespace.ViewActionMethod,
+	ControllerName: "GetServiceNextAvailable",
+	Description:    "Get the next available slot of a service",
+	NeedsCompanyId: true,
+	Resource:       ServiceResource,
+}
+var CreateServiceOverride = &EndPoint{
+	Path:             "/service/:id/override",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "CreateServiceOverride",
+	Description:      "Override the price or duration of a service for an employee or branch",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         ServiceResource,
+}
+var GetServiceOverrides = &EndPoint{
+	Path:             "/service/:id/overrides",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetServiceOverrides",
+	Description:      "List the price and duration overrides of a service",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         ServiceResource,
+}
+var DeleteServiceOverride = &EndPoint{
+	Path:             "/service/:id/override/:override_id",
+	Method:           namespace.DeleteActionMethod,
+	ControllerName:   "DeleteServiceOverride",
+	Description:      "Delete a price and duration override of a service",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         ServiceResource,
+}
+var AddServiceResource = &EndPoint{
+	Path:             "/service/:id/resource",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "AddServiceResource",
+	Description:      "Make a service hold a resource of a branch while it runs",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         ServiceResource,
+}
+var GetServiceResources = &EndPoint{
+	Path:             "/service/:id/resources",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetServiceResources",
+	Description:      "List the resources a service holds while it runs",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         ServiceResource,
+}
+var RemoveServiceResource = &EndPoint{
+	Path:             "/service/:id/resource/:resource_id",
+	Method:           namespace.DeleteActionMethod,
+	ControllerName:   "RemoveServiceResource",
+	Description:      "Stop a service from holding a resource",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         ServiceResource,
+}
+
+// --- Webhook Endpoints --- //
+
+var CreateWebhook = &EndPoint{
+	Path:             "/webhook",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "CreateWebhook",
+	Description:      "Create a webhook subscription",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetCompanyWebhooks = &EndPoint{
+	Path:             "/company/:company_id/webhooks",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetCompanyWebhooks",
+	Description:      "List webhook subscriptions of a company",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetWebhookById = &EndPoint{
+	Path:             "/webhook/:id",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetWebhookById",
+	Description:      "View webhook subscription by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         WebhookResource,
+}
+var UpdateWebhookById = &EndPoint{
+	Path:             "/webhook/:id",
+	Method:           namespace.PatchActionMethod,
+	ControllerName:   "UpdateWebhookById",
+	Description:      "Update webhook subscription by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         WebhookResource,
+}
+var DeleteWebhookById = &EndPoint{
+	Path:             "/webhook/:id",
+	Method:           namespace.DeleteActionMethod,
+	ControllerName:   "DeleteWebhookById",
+	Description:      "Delete webhook subscription by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         WebhookResource,
+}
+var GetWebhookDeliveries = &EndPoint{
+	Path:             "/webhook/:id/deliveries",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetWebhookDeliveries",
+	Description:      "View the delivery log of a webhook subscription",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         WebhookResource,
+}
+var ReplayWebhookDelivery = &EndPoint{
+	Path:             "/webhook/:id/delivery/:delivery_id/replay",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "ReplayWebhookDelivery",
+	Description:      "Send a previous webhook delivery again",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         WebhookResource,
+}
+var RotateWebhookSecret = &EndPoint{
+	Path:             "/webhook/:id/secret",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "RotateWebhookSecret",
+	Description:      "Replace the signing secret of a webhook subscription",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         WebhookResource,
+}
+
+// --- Payment Endpoints --- //
+
+var GetPaymentById = &EndPoint{
+	Path:             "/payment/:id",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetPaymentById",
+	Description:      "View payment by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         PaymentResource,
+}
+var GetPaymentPix = &EndPoint{
+	Path:             "/payment/:id/pix",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetPaymentPix",
+	Description:      "View the Pix code of a payment",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         PaymentResource,
+}
+var RefundPaymentById = &EndPoint{
+	Path:             "/payment/:id/refund",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "RefundPaymentById",
+	Description:      "Refund a payment",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         PaymentResource,
+}
+var PaymentProviderWebhook = &EndPoint{
+	Path:           "/payment/webhook/:provider/:company_id",
+	Method:         namespace.CreateActionMethod,
+	ControllerName: "PaymentProviderWebhook",
+	Description:    "Receive payment notifications from the payment provider",
+}
+
+// --- Promo Code Endpoints --- //
+
+var CreatePromoCode = &EndPoint{
+	Path:             "/promo_code",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "CreatePromoCode",
+	Description:      "Create a promo code",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetCompanyPromoCodes = &EndPoint{
+	Path:             "/company/:company_id/promo_codes",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetCompanyPromoCodes",
+	Description:      "List promo codes of a company",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetPromoCodeById = &EndPoint{
+	Path:             "/promo_code/:id",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetPromoCodeById",
+	Description:      "View promo code by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         PromoCodeResource,
+}
+var UpdatePromoCodeById = &EndPoint{
+	Path:             "/promo_code/:id",
+	Method:           namespace.PatchActionMethod,
+	ControllerName:   "UpdatePromoCodeById",
+	Description:      "Update promo code by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         PromoCodeResource,
+}
+var DeletePromoCodeById = &EndPoint{
+	Path:             "/promo_code/:id",
+	Method:           namespace.DeleteActionMethod,
+	ControllerName:   "DeletePromoCodeById",
+	Description:      "Delete promo code by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         PromoCodeResource,
+}
+
+// --- Schedule Template Endpoints --- //
+
+var CreateScheduleTemplate = &EndPoint{
+	Path:             "/schedule_template",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "CreateScheduleTemplate",
+	Description:      "Create a schedule template",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetCompanyScheduleTemplates = &EndPoint{
+	Path:             "/company/:company_id/schedule_templates",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetCompanyScheduleTemplates",
+	Description:      "List schedule templates of a company",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetScheduleTemplateById = &EndPoint{
+	Path:             "/schedule_template/:id",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetScheduleTemplateById",
+	Description:      "View schedule template by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         ScheduleTemplateResource,
+}
+var UpdateScheduleTemplateById = &EndPoint{
+	Path:             "/schedule_template/:id",
+	Method:           namespace.PatchActionMethod,
+	ControllerName:   "UpdateScheduleTemplateById",
+	Description:      "Update schedule template by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         ScheduleTemplateResource,
+}
+var DeleteScheduleTemplateById = &EndPoint{
+	Path:             "/schedule_template/:id",
+	Method:           namespace.DeleteActionMethod,
+	ControllerName:   "DeleteScheduleTemplateById",
+	Description:      "Delete schedule template by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         ScheduleTemplateResource,
+}
+var ApplyScheduleTemplate = &EndPoint{
+	Path:             "/schedule_template/:id/apply",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "ApplyScheduleTemplate",
+	Description:      "Apply a schedule template to employees or branches, or preview it",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         ScheduleTemplateResource,
+}
+var CopyEmployeeWorkSchedule = &EndPoint{
+	Path:             "/employee/:employee_id/work_schedule/copy",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "CopyEmployeeWorkSchedule",
+	Description:      "Copy the work schedule of another employee, or preview it",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         EmployeeResource,
+}
+
+// --- Package Endpoints --- //
+
+var CreateServicePackage = &EndPoint{
+	Path:             "/package",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "CreateServicePackage",
+	Description:      "Create a package or membership",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetCompanyServicePackages = &EndPoint{
+	Path:             "/company/:company_id/packages",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetCompanyServicePackages",
+	Description:      "List packages and memberships of a company",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetServicePackageById = &EndPoint{
+	Path:             "/package/:id",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetServicePackageById",
+	Description:      "View package by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         PackageResource,
+}
+var UpdateServicePackageById = &EndPoint{
+	Path:             "/package/:id",
+	Method:           namespace.PatchActionMethod,
+	ControllerName:   "UpdateServicePackageById",
+	Description:      "Update package by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         PackageResource,
+}
+var DeleteServicePackageById = &EndPoint{
+	Path:             "/package/:id",
+	Method:           namespace.DeleteActionMethod,
+	ControllerName:   "DeleteServicePackageById",
+	Description:      "Delete package by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         PackageResource,
+}
+var SellServicePackage = &EndPoint{
+	Path:             "/package/:id/sell",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "SellServicePackage",
+	Description:      "Sell a package or membership to a client",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         PackageResource,
+}
+var GetClientCredits = &EndPoint{
+	Path:             "/company/:company_id/client/:client_id/credits",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetClientCredits",
+	Description:      "View the credit balance of a client",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+
+// --- Intake Form Endpoints --- //
+
+var CreateIntakeForm = &EndPoint{
+	Path:             "/intake_form",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "CreateIntakeForm",
+	Description:      "Create an intake form",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetCompanyIntakeForms = &EndPoint{
+	Path:             "/company/:company_id/intake_forms",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetCompanyIntakeForms",
+	Description:      "List intake forms of a company",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetServiceIntakeForms = &EndPoint{
+	Path:             "/service/:id/intake_forms",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetServiceIntakeForms",
+	Description:      "List the intake forms asked when booking a service",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: false,
+	Resource:         ServiceResource,
+}
+var GetIntakeFormById = &EndPoint{
+	Path:             "/intake_form/:id",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetIntakeFormById",
+	Description:      "View intake form by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         IntakeFormResource,
+}
+var UpdateIntakeFormById = &EndPoint{
+	Path:             "/intake_form/:id",
+	Method:           namespace.PatchActionMethod,
+	ControllerName:   "UpdateIntakeFormById",
+	Description:      "Update intake form by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         IntakeFormResource,
+}
+var DeleteIntakeFormById = &EndPoint{
+	Path:             "/intake_form/:id",
+	Method:           namespace.DeleteActionMethod,
+	ControllerName:   "DeleteIntakeFormById",
+	Description:      "Delete intake form by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         IntakeFormResource,
+}
+var GetIntakeRequest = &EndPoint{
+	Path:             "/intake/:token",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetIntakeRequest",
+	Description:      "View the intake form a client was emailed a link to answer",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: false,
+}
+var SubmitIntakeRequest = &EndPoint{
+	Path:             "/intake/:token",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "SubmitIntakeRequest",
+	Description:      "Answer the intake form a client was emailed a link to",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: false,
+}
+
+// --- Review Endpoints --- //
+
+var GetCompanyReviews = &EndPoint{
+	Path:             "/company/:company_id/reviews",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetCompanyReviews",
+	Description:      "List reviews of a company",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetReviewById = &EndPoint{
+	Path:             "/review/:id",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetReviewById",
+	Description:      "View review by ID",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         ReviewResource,
+}
+var ModerateReviewById = &EndPoint{
+	Path:             "/review/:id/moderate",
+	Method:           namespace.PatchActionMethod,
+	ControllerName:   "ModerateReviewById",
+	Description:      "Publish or reject a review",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         ReviewResource,
+}
+var GetReviewInvitation = &EndPoint{
+	Path:             "/review_invitation/:token",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetReviewInvitation",
+	Description:      "View the appointment a client was emailed a link to review",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: false,
+}
+var SubmitReviewInvitation = &EndPoint{
+	Path:             "/review_invitation/:token",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "SubmitReviewInvitation",
+	Description:      "Review the appointment a client was emailed a link to",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: false,
+}
 
 // --- Combine all Endpoints into a slice for seeding --- //
+// --- Loyalty Endpoints --- //
+
+var GetLoyaltyProgram = &EndPoint{
+	Path:             "/company/:company_id/loyalty_program",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetLoyaltyProgram",
+	Description:      "View the loyalty program of a company",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: false,
+	Resource:         CompanyResource,
+}
+var SaveLoyaltyProgram = &EndPoint{
+	Path:             "/company/:company_id/loyalty_program",
+	Method:           namespace.PutActionMethod,
+	ControllerName:   "SaveLoyaltyProgram",
+	Description:      "Create or replace the loyalty program of a company",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetClientLoyalty = &EndPoint{
+	Path:             "/company/:company_id/client/:client_id/loyalty",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetClientLoyalty",
+	Description:      "View the loyalty points of a client",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var AdjustClientLoyalty = &EndPoint{
+	Path:             "/company/:company_id/client/:client_id/loyalty/adjust",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "AdjustClientLoyalty",
+	Description:      "Grant or remove loyalty points of a client",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+
+// --- Import Endpoints --- //
+
+var ImportRecords = &EndPoint{
+	Path:             "/company/:company_id/import/:kind",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "ImportRecords",
+	Description:      "Import services, employees or clients from a CSV or XLSX file",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+
+// --- Employee Invitation Endpoints --- //
+
+var InviteEmployee = &EndPoint{
+	Path:             "/company/:company_id/employee_invitation",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "InviteEmployee",
+	Description:      "Invite an employee to join a company",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         CompanyResource,
+}
+var GetEmployeeInvitation = &EndPoint{
+	Path:             "/employee/:employee_id/invitation",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetEmployeeInvitation",
+	Description:      "View the last invitation of an employee",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         EmployeeResource,
+}
+var ResendEmployeeInvitation = &EndPoint{
+	Path:             "/employee/:employee_id/invitation/resend",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "ResendEmployeeInvitation",
+	Description:      "Email the pending invitation of an employee again",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         EmployeeResource,
+}
+var RevokeEmployeeInvitation = &EndPoint{
+	Path:             "/employee/:employee_id/invitation",
+	Method:           namespace.DeleteActionMethod,
+	ControllerName:   "RevokeEmployeeInvitation",
+	Description:      "Revoke the pending invitation of an employee",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         EmployeeResource,
+}
+var UpdateEmployeeStatus = &EndPoint{
+	Path:             "/employee/:employee_id/status",
+	Method:           namespace.PatchActionMethod,
+	ControllerName:   "UpdateEmployeeStatus",
+	Description:      "Suspend or reactivate an employee",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: true,
+	Resource:         EmployeeResource,
+}
+var GetEmployeeInvitationByToken = &EndPoint{
+	Path:             "/employee_invitation/:token",
+	Method:           namespace.ViewActionMethod,
+	ControllerName:   "GetEmployeeInvitationByToken",
+	Description:      "View the invitation an employee was emailed a link to",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: false,
+}
+var AcceptEmployeeInvitation = &EndPoint{
+	Path:             "/employee_invitation/:token",
+	Method:           namespace.CreateActionMethod,
+	ControllerName:   "AcceptEmployeeInvitation",
+	Description:      "Accept the invitation an employee was emailed a link to",
+	NeedsCompanyId:   true,
+	DenyUnauthorized: false,
+}
+
 var endpoints = []*EndPoint{
 	// Appointment
 	CreateAppointment,
 	GetAppointmentByID,
 	UpdateAppointmentByID,
 	CancelAppointmentByID,
+	GetAppointmentHistory,
+	GetAppointmentReceipt,
+	GetAppointmentIntake,
 	// Auth
 	BeginAuthProviderCallback,
 	GetAuthCallbackFunction,
@@ -873,6 +1637,10 @@ var endpoints = []*EndPoint{
 	AddBranchWorkRangeServices,
 	DeleteBranchWorkRangeService,
 	GetBranchAppointmentsById,
+	CreateBookableResource,
+	GetBookableResources,
+	UpdateBookableResource,
+	DeleteBookableResource,
 	// Client
 	CreateClient,
 	LoginClient,
@@ -880,6 +1648,17 @@ var endpoints = []*EndPoint{
 	SendLoginCodeToClientEmail,
 	SendClientVerificationCodeByEmail,
 	VerifyClientEmail,
+	CreateGuestClient,
+	VerifyGuestClient,
+	UpgradeGuestClient,
+	RequestClientDataExport,
+	RequestClientDataErasure,
+	GetClientDataRequests,
+	DownloadClientDataExport,
+	CreateClientDependent,
+	GetClientDependents,
+	UpdateClientDependent,
+	DeleteClientDependent,
 	ResetClientPasswordByEmail,
 	GetClientByEmail,
 	GetClientById,
@@ -888,6 +1667,9 @@ var endpoints = []*EndPoint{
 	UpdateClientImages,
 	DeleteClientImage,
 	GetClientAppointmentsById,
+	GetClientProfile,
+	UpdateClientProfile,
+	GetCompanyClients,
 	// Company
 	CreateCompany,
 	GetCompanyById,
@@ -947,6 +1729,79 @@ var endpoints = []*EndPoint{
 	UpdateServiceImages,
 	DeleteServiceImage,
 	GetServiceAvailability,
+	GetServiceNextAvailable,
+	CreateServiceOverride,
+	GetServiceOverrides,
+	DeleteServiceOverride,
+	AddServiceResource,
+	GetServiceResources,
+	RemoveServiceResource,
+	// Webhook
+	CreateWebhook,
+	GetCompanyWebhooks,
+	GetWebhookById,
+	UpdateWebhookById,
+	DeleteWebhookById,
+	GetWebhookDeliveries,
+	ReplayWebhookDelivery,
+	RotateWebhookSecret,
+	// Payment
+	GetPaymentById,
+	GetPaymentPix,
+	RefundPaymentById,
+	PaymentProviderWebhook,
+	// Promo Code
+	CreatePromoCode,
+	GetCompanyPromoCodes,
+	GetPromoCodeById,
+	UpdatePromoCodeById,
+	DeletePromoCodeById,
+	// Schedule Template
+	CreateScheduleTemplate,
+	GetCompanyScheduleTemplates,
+	GetScheduleTemplateById,
+	UpdateScheduleTemplateById,
+	DeleteScheduleTemplateById,
+	ApplyScheduleTemplate,
+	CopyEmployeeWorkSchedule,
+	// Package
+	CreateServicePackage,
+	GetCompanyServicePackages,
+	GetServicePackageById,
+	UpdateServicePackageById,
+	DeleteServicePackageById,
+	SellServicePackage,
+	GetClientCredits,
+	// Intake Form
+	CreateIntakeForm,
+	GetCompanyIntakeForms,
+	GetServiceIntakeForms,
+	GetIntakeFormById,
+	UpdateIntakeFormById,
+	DeleteIntakeFormById,
+	GetIntakeRequest,
+	SubmitIntakeRequest,
+	// Review
+	GetCompanyReviews,
+	GetReviewById,
+	ModerateReviewById,
+	GetReviewInvitation,
+	SubmitReviewInvitation,
+	// Loyalty
+	GetLoyaltyProgram,
+	SaveLoyaltyProgram,
+	GetClientLoyalty,
+	AdjustClientLoyalty,
+	// Import
+	ImportRecords,
+	// Employee Invitation
+	InviteEmployee,
+	GetEmployeeInvitation,
+	ResendEmployeeInvitation,
+	RevokeEmployeeInvitation,
+	UpdateEmployeeStatus,
+	GetEmployeeInvitationByToken,
+	AcceptEmployeeInvitation,
 }
 
 type EndpointCfg struct {
@@ -34,7 +34,27 @@ var TenantModels = []any{
 	&EmployeeWorkRange{},
 	&Employee{},
 	&Service{},
+	&ServiceOverride{},
 	&Payment{},
+	&PromoCode{},
+	&ServicePackage{},
+	&ClientPackage{},
+	&WebhookSubscription{},
+	&WebhookDelivery{},
+	&BookableResource{},
+	&ServiceResourceRequirement{},
+	&AppointmentResourceAllocation{},
+	&ScheduleTemplate{},
+	&ScheduleTemplateRange{},
+	&ClientProfile{},
+	&IntakeForm{},
+	&IntakeFormVersion{},
+	&IntakeResponse{},
+	&Review{},
+	&LoyaltyProgram{},
+	&LoyaltyAccount{},
+	&LoyaltyTransaction{},
+	&EmployeeInvitation{},
 }
 
 var GeneralModels = []any{
@@ -49,6 +69,9 @@ var GeneralModels = []any{
 	&Property{},
 	&Subdomain{},
 	&ClientAppointment{},
+	&OutboxMessage{},
+	&ClientDataRequest{},
+	&ClientDependent{},
 }
 
 func GetModelFromTableName(tableName string) (any, string, error) {
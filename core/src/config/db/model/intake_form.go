@@ -0,0 +1,274 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mynute-go/core/src/lib"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Types of the fields of an intake form.
+const (
+	IntakeFieldText        = "TEXT"
+	IntakeFieldLongText    = "LONG_TEXT"
+	IntakeFieldNumber      = "NUMBER"
+	IntakeFieldDate        = "DATE" // YYYY-MM-DD
+	IntakeFieldBoolean     = "BOOLEAN"
+	IntakeFieldChoice      = "CHOICE"       // One of the options
+	IntakeFieldMultiChoice = "MULTI_CHOICE" // Any of the options
+)
+
+var intakeFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// IntakeField is a question of an intake form.
+type IntakeField struct {
+	Key      string   `json:"key"` // Identifies the answer, unique in the form
+	Label    string   `json:"label"`
+	Type     string   `json:"type"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options,omitempty"` // Choices of CHOICE and MULTI_CHOICE fields
+	Help     string   `json:"help,omitempty"`
+}
+
+// IntakeFields are the questions of an intake form, in order, stored as JSONB.
+type IntakeFields []IntakeField
+
+func (l IntakeFields) Value() (driver.Value, error) {
+	if l == nil {
+		return json.Marshal([]IntakeField{})
+	}
+	return json.Marshal([]IntakeField(l))
+}
+
+func (l *IntakeFields) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		if value == nil {
+			*l = nil
+			return nil
+		}
+		if str, ok := value.(string); ok {
+			bytes = []byte(str)
+		} else {
+			return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+		}
+	}
+	if len(bytes) == 0 {
+		*l = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// IntakeForm is a questionnaire or consent document of a company, asked of whoever attends
+// the services it is attached to before their first appointment. Every change to its
+// questions or consent text makes a new version, answers keep the version they were given to.
+type IntakeForm struct {
+	BaseModel
+	CompanyID   uuid.UUID    `gorm:"type:uuid;not null;index" json:"company_id"`
+	Name        string       `gorm:"type:varchar(100);not null" json:"name"`
+	Description string       `gorm:"type:text" json:"description"`
+	ServiceIDs  UUIDList     `gorm:"type:jsonb" json:"service_ids"` // Services asking for the form
+	Fields      IntakeFields `gorm:"type:jsonb" json:"fields"`
+	// Consent forms are signed with the full name of the client and kept as a PDF
+	IsConsent   bool   `gorm:"not null;default:false" json:"is_consent"`
+	ConsentText string `gorm:"type:text" json:"consent_text"`
+	Version     int    `gorm:"not null;default:1" json:"version"` // Current version
+	IsActive    bool   `gorm:"not null;default:true" json:"is_active"`
+}
+
+const IntakeFormTableName = "intake_forms"
+
+func (IntakeForm) TableName() string  { return IntakeFormTableName }
+func (IntakeForm) SchemaType() string { return "company" }
+func (IntakeForm) Indexes() map[string]string {
+	return map[string]string{
+		"idx_intake_form_company_name": fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_intake_form_company_name ON %s (company_id, name) WHERE deleted_at IS NULL", IntakeFormTableName),
+	}
+}
+
+func (f *IntakeForm) Validate() error {
+	if len(f.Name) < 3 || len(f.Name) > 100 {
+		return lib.Error.IntakeForm.Invalid.WithError(fmt.Errorf("name must have from 3 to 100 characters"))
+	}
+	if len(f.Fields) == 0 && !f.IsConsent {
+		return lib.Error.IntakeForm.Invalid.WithError(fmt.Errorf("a form needs at least one field or a consent text"))
+	}
+	if f.IsConsent && len(f.ConsentText) < 10 {
+		return lib.Error.IntakeForm.Invalid.WithError(fmt.Errorf("consent forms need a consent text of at least 10 characters"))
+	}
+	keys := make([]string, 0, len(f.Fields))
+	for i, field := range f.Fields {
+		if !intakeFieldKeyPattern.MatchString(field.Key) {
+			return lib.Error.IntakeForm.Invalid.WithError(fmt.Errorf("field [%d] key must be snake_case with up to 50 characters", i+1))
+		}
+		if slices.Contains(keys, field.Key) {
+			return lib.Error.IntakeForm.Invalid.WithError(fmt.Errorf("field [%d] key %q is repeated", i+1, field.Key))
+		}
+		keys = append(keys, field.Key)
+		if field.Label == "" {
+			return lib.Error.IntakeForm.Invalid.WithError(fmt.Errorf("field [%d] needs a label", i+1))
+		}
+		switch field.Type {
+		case IntakeFieldText, IntakeFieldLongText, IntakeFieldNumber, IntakeFieldDate, IntakeFieldBoolean:
+			if len(field.Options) > 0 {
+				return lib.Error.IntakeForm.Invalid.WithError(fmt.Errorf("field [%d] of type %s can not have options", i+1, field.Type))
+			}
+		case IntakeFieldChoice, IntakeFieldMultiChoice:
+			if len(field.Options) < 2 {
+				return lib.Error.IntakeForm.Invalid.WithError(fmt.Errorf("field [%d] needs at least two options", i+1))
+			}
+		default:
+			return lib.Error.IntakeForm.Invalid.WithError(fmt.Errorf("field [%d] has unknown type %q", i+1, field.Type))
+		}
+	}
+	return nil
+}
+
+func (f *IntakeForm) BeforeCreate(tx *gorm.DB) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	f.Version = 1
+	return f.checkName(tx)
+}
+
+// AfterCreate records the first version of the form.
+func (f *IntakeForm) AfterCreate(tx *gorm.DB) error {
+	return f.SaveVersion(tx)
+}
+
+func (f *IntakeForm) BeforeUpdate(tx *gorm.DB) error {
+	if tx.Statement.Changed("CompanyID") {
+		return lib.Error.Company.IdUpdateForbidden
+	}
+	if tx.Statement.Changed("Name") {
+		return f.checkName(tx)
+	}
+	return nil
+}
+
+func (f *IntakeForm) checkName(tx *gorm.DB) error {
+	var count int64
+	if err := tx.Model(&IntakeForm{}).Where("company_id = ? AND name = ? AND id <> ?", f.CompanyID, f.Name, f.ID).Count(&count).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error checking existing intake form: %w", err))
+	}
+	if count > 0 {
+		return lib.Error.IntakeForm.Invalid.WithError(fmt.Errorf("an intake form named %q already exists", f.Name))
+	}
+	return nil
+}
+
+// SaveVersion records the current questions and consent text of the form as its version f.Version.
+func (f *IntakeForm) SaveVersion(tx *gorm.DB) error {
+	version := IntakeFormVersion{
+		FormID:      f.ID,
+		Version:     f.Version,
+		Name:        f.Name,
+		Fields:      f.Fields,
+		IsConsent:   f.IsConsent,
+		ConsentText: f.ConsentText,
+	}
+	if err := tx.Create(&version).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(fmt.Errorf("error saving intake form version: %w", err))
+	}
+	return nil
+}
+
+// IntakeFormVersion is an immutable copy of the questions and consent text of a form,
+// as they were when answered.
+type IntakeFormVersion struct {
+	BaseModel
+	FormID      uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_intake_form_version,priority:1" json:"form_id"`
+	Version     int          `gorm:"not null;uniqueIndex:idx_intake_form_version,priority:2" json:"version"`
+	Name        string       `gorm:"type:varchar(100);not null" json:"name"`
+	Fields      IntakeFields `gorm:"type:jsonb" json:"fields"`
+	IsConsent   bool         `gorm:"not null;default:false" json:"is_consent"`
+	ConsentText string       `gorm:"type:text" json:"consent_text"`
+}
+
+const IntakeFormVersionTableName = "intake_form_versions"
+
+func (IntakeFormVersion) TableName() string  { return IntakeFormVersionTableName }
+func (IntakeFormVersion) SchemaType() string { return "company" }
+
+func (v *IntakeFormVersion) BeforeUpdate(tx *gorm.DB) error {
+	return lib.Error.IntakeForm.Invalid.WithError(fmt.Errorf("intake form versions can not be changed"))
+}
+
+// Status of an intake response.
+const (
+	IntakePending   = "PENDING"   // Asked for, waiting for the client through the emailed link
+	IntakeCompleted = "COMPLETED" // Answered, and signed for consent forms
+)
+
+// IntakeAnswers are the answers of a response by field key, stored as JSONB.
+type IntakeAnswers map[string]any
+
+func (a IntakeAnswers) Value() (driver.Value, error) {
+	if a == nil {
+		return json.Marshal(map[string]any{})
+	}
+	return json.Marshal(map[string]any(a))
+}
+
+func (a *IntakeAnswers) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		if value == nil {
+			*a = nil
+			return nil
+		}
+		if str, ok := value.(string); ok {
+			bytes = []byte(str)
+		} else {
+			return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+		}
+	}
+	if len(bytes) == 0 {
+		*a = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+// IntakeResponse is the answer of an attendee, the client or one of its dependents, to a
+// version of an intake form. It is created pending when the form could not be answered at
+// booking, and completed through the link emailed to the client.
+type IntakeResponse struct {
+	BaseModel
+	FormID        uuid.UUID     `gorm:"type:uuid;not null;index" json:"form_id"`
+	FormVersionID uuid.UUID     `gorm:"type:uuid;not null" json:"form_version_id"`
+	Version       int           `gorm:"not null" json:"version"`
+	ClientID      uuid.UUID     `gorm:"type:uuid;not null;index" json:"client_id"`
+	DependentID   *uuid.UUID    `gorm:"type:uuid" json:"dependent_id"`         // Dependent of the client attending, the client itself when nil
+	AppointmentID *uuid.UUID    `gorm:"type:uuid;index" json:"appointment_id"` // Appointment the form was asked for
+	Status        string        `gorm:"type:varchar(20);not null;default:'PENDING'" json:"status"`
+	Token         *string       `gorm:"type:varchar(64);uniqueIndex" json:"-"` // Of the emailed link, only valid while pending
+	Answers       IntakeAnswers `gorm:"type:jsonb" json:"answers"`
+	// Signature of consent forms
+	SignedName  string     `gorm:"type:varchar(200)" json:"signed_name"`
+	SignedAt    *time.Time `json:"signed_at"`
+	SignatureIP string     `gorm:"type:varchar(45)" json:"signature_ip"`
+	ConsentURL  string     `gorm:"type:text" json:"consent_url"` // Signed consent PDF
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+const IntakeResponseTableName = "intake_responses"
+
+func (IntakeResponse) TableName() string  { return IntakeResponseTableName }
+func (IntakeResponse) SchemaType() string { return "company" }
+
+func (r *IntakeResponse) BeforeUpdate(tx *gorm.DB) error {
+	if tx.Statement.Changed("FormVersionID") || tx.Statement.Changed("ClientID") || tx.Statement.Changed("DependentID") {
+		return lib.Error.IntakeForm.Invalid.WithError(fmt.Errorf("the form and attendee of a response can not be changed"))
+	}
+	return nil
+}
@@ -4,19 +4,101 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"reflect"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type AppointmentHistory struct {
 	FieldChanges []FieldChange `json:"field_changes"`
 }
 
+// FieldChange is one field updated on an appointment. OldValue and NewValue keep
+// the JSON encoding of the values, ValueType tells how to read them.
+// Entries recorded before values were typed hold JSON strings and no ValueType.
 type FieldChange struct {
-	CreatedAt time.Time `json:"created_at"`
-	Field     string    `json:"field"`
-	OldValue  string    `json:"old_value"`
-	NewValue  string    `json:"new_value"`
-	Reason    string    `json:"reason"`
+	CreatedAt time.Time       `json:"created_at"`
+	Field     string          `json:"field"`
+	ValueType string          `json:"value_type,omitempty"`
+	OldValue  json.RawMessage `json:"old_value"`
+	NewValue  json.RawMessage `json:"new_value"`
+	Reason    string          `json:"reason"`
+	ActorID   *uuid.UUID      `json:"actor_id,omitempty"`
+	ActorType string          `json:"actor_type,omitempty"`
+	Endpoint  string          `json:"endpoint,omitempty"`
+	IP        string          `json:"ip,omitempty"`
+}
+
+// Audit describes who made a change and through which request.
+type Audit struct {
+	ActorID   *uuid.UUID
+	ActorType string // "client", "employee" or empty for system changes
+	Endpoint  string // e.g. "PATCH /appointment/:id"
+	IP        string
+	Reason    string
+}
+
+// Value types of a FieldChange.
+const (
+	ValueTypeString = "string"
+	ValueTypeNumber = "number"
+	ValueTypeBool   = "bool"
+	ValueTypeTime   = "time"
+	ValueTypeUUID   = "uuid"
+	ValueTypeJSON   = "json"
+)
+
+// NewFieldChange builds a change entry keeping the values typed.
+func NewFieldChange(field string, oldValue, newValue any, audit Audit) (FieldChange, error) {
+	oldJSON, err := json.Marshal(oldValue)
+	if err != nil {
+		return FieldChange{}, err
+	}
+	newJSON, err := json.Marshal(newValue)
+	if err != nil {
+		return FieldChange{}, err
+	}
+	return FieldChange{
+		CreatedAt: time.Now(),
+		Field:     field,
+		ValueType: valueTypeOf(newValue),
+		OldValue:  oldJSON,
+		NewValue:  newJSON,
+		Reason:    audit.Reason,
+		ActorID:   audit.ActorID,
+		ActorType: audit.ActorType,
+		Endpoint:  audit.Endpoint,
+		IP:        audit.IP,
+	}, nil
+}
+
+func valueTypeOf(v any) string {
+	switch v.(type) {
+	case time.Time, *time.Time:
+		return ValueTypeTime
+	case uuid.UUID, *uuid.UUID:
+		return ValueTypeUUID
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ValueTypeJSON
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		return ValueTypeString
+	case reflect.Bool:
+		return ValueTypeBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return ValueTypeNumber
+	default:
+		return ValueTypeJSON
+	}
 }
 
 func (ah *AppointmentHistory) Value() (driver.Value, error) {
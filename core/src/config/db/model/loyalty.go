@@ -0,0 +1,205 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mynute-go/core/src/lib"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoyaltyEarning is how many points an appointment earns: a fixed amount per fulfilled
+// appointment plus PointsPerAmount for every AmountUnit cents paid for it.
+type LoyaltyEarning struct {
+	PointsPerAppointment int64 `json:"points_per_appointment"`
+	PointsPerAmount      int64 `json:"points_per_amount"`
+	AmountUnit           int64 `json:"amount_unit"` // In cents, e.g. 1000 for one point every 10.00 spent
+}
+
+func (e LoyaltyEarning) validate(prefix string) error {
+	if e.PointsPerAppointment < 0 || e.PointsPerAmount < 0 {
+		return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("%spoints must not be negative", prefix))
+	}
+	if e.PointsPerAmount > 0 && e.AmountUnit <= 0 {
+		return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("%samount_unit must be positive when earning points per amount", prefix))
+	}
+	return nil
+}
+
+// Points returns the points earned by an appointment of which amount cents were paid.
+func (e LoyaltyEarning) Points(amount int64) int64 {
+	points := e.PointsPerAppointment
+	if e.PointsPerAmount > 0 && e.AmountUnit > 0 && amount > 0 {
+		points += amount / e.AmountUnit * e.PointsPerAmount
+	}
+	return points
+}
+
+// LoyaltyRule overrides the earning of the program for a service.
+type LoyaltyRule struct {
+	ServiceID uuid.UUID `json:"service_id"`
+	LoyaltyEarning
+	Excluded bool `json:"excluded"` // The service earns no points
+}
+
+// LoyaltyRules are the earning rules by service of a program, stored as JSONB.
+type LoyaltyRules []LoyaltyRule
+
+func (l LoyaltyRules) Value() (driver.Value, error) {
+	if l == nil {
+		return json.Marshal([]LoyaltyRule{})
+	}
+	return json.Marshal([]LoyaltyRule(l))
+}
+
+func (l *LoyaltyRules) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		if value == nil {
+			*l = nil
+			return nil
+		}
+		if str, ok := value.(string); ok {
+			bytes = []byte(str)
+		} else {
+			return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+		}
+	}
+	if len(bytes) == 0 {
+		*l = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// LoyaltyProgram is the loyalty program of a company, at most one per company.
+// Points earned on fulfilled appointments can be redeemed as a discount when booking.
+type LoyaltyProgram struct {
+	BaseModel
+	CompanyID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"company_id"`
+	IsActive  bool      `gorm:"not null;default:true" json:"is_active"` // Inactive programs neither earn nor redeem points
+	LoyaltyEarning
+	Rules LoyaltyRules `gorm:"type:jsonb" json:"rules"` // By service, services without a rule use the program earning
+	// Redemption
+	PointValue       int64 `gorm:"not null" json:"point_value"`                    // Discount in cents of one point
+	MinRedeemPoints  int64 `gorm:"not null;default:0" json:"min_redeem_points"`    // Fewest points redeemed at once
+	MaxRedeemPercent int64 `gorm:"not null;default:100" json:"max_redeem_percent"` // Share of the price that can be paid with points
+}
+
+const LoyaltyProgramTableName = "loyalty_programs"
+
+func (LoyaltyProgram) TableName() string  { return LoyaltyProgramTableName }
+func (LoyaltyProgram) SchemaType() string { return "company" }
+
+func (p *LoyaltyProgram) BeforeCreate(tx *gorm.DB) error {
+	return p.Validate()
+}
+
+func (p *LoyaltyProgram) BeforeUpdate(tx *gorm.DB) error {
+	if tx.Statement.Changed("CompanyID") {
+		return lib.Error.Company.IdUpdateForbidden
+	}
+	return nil
+}
+
+func (p *LoyaltyProgram) Validate() error {
+	if p.CompanyID == uuid.Nil {
+		return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("company_id is required"))
+	}
+	if err := p.LoyaltyEarning.validate(""); err != nil {
+		return err
+	}
+	seen := make(map[uuid.UUID]bool, len(p.Rules))
+	for i, rule := range p.Rules {
+		if rule.ServiceID == uuid.Nil {
+			return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("rules[%d]: service_id is required", i))
+		}
+		if seen[rule.ServiceID] {
+			return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("rules[%d]: service %s has more than one rule", i, rule.ServiceID))
+		}
+		seen[rule.ServiceID] = true
+		if err := rule.LoyaltyEarning.validate(fmt.Sprintf("rules[%d]: ", i)); err != nil {
+			return err
+		}
+	}
+	if p.PointValue <= 0 {
+		return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("point_value must be positive"))
+	}
+	if p.MinRedeemPoints < 0 {
+		return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("min_redeem_points must not be negative"))
+	}
+	if p.MaxRedeemPercent < 1 || p.MaxRedeemPercent > 100 {
+		return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("max_redeem_percent must be between 1 and 100"))
+	}
+	return nil
+}
+
+// EarningFor returns how the service earns points, false when it earns none.
+func (p *LoyaltyProgram) EarningFor(serviceID uuid.UUID) (LoyaltyEarning, bool) {
+	for _, rule := range p.Rules {
+		if rule.ServiceID == serviceID {
+			return rule.LoyaltyEarning, !rule.Excluded
+		}
+	}
+	return p.LoyaltyEarning, true
+}
+
+// RedeemablePoints returns the points, up to requested, that can pay an appointment whose
+// price left to pay is due, and the discount they give.
+func (p *LoyaltyProgram) RedeemablePoints(requested, due int64) (points, discount int64) {
+	limit := due * p.MaxRedeemPercent / 100
+	points = min(requested, limit/p.PointValue)
+	return points, points * p.PointValue
+}
+
+// Types of the loyalty ledger entries.
+const (
+	LoyaltyEarn       = "EARN"       // Earned on a fulfilled appointment
+	LoyaltyRedeem     = "REDEEM"     // Redeemed as a discount when booking
+	LoyaltyReversal   = "REVERSAL"   // Earned points taken back, the appointment was cancelled or refunded
+	LoyaltyRestore    = "RESTORE"    // Redeemed points given back, the appointment was cancelled
+	LoyaltyAdjustment = "ADJUSTMENT" // Granted or removed by the staff
+)
+
+// LoyaltyAccount is the points balance of a client at a company. The balance may go negative
+// when earned points are reversed after being redeemed.
+type LoyaltyAccount struct {
+	BaseModel
+	CompanyID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_loyalty_account_client" json:"company_id"`
+	ClientID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_loyalty_account_client" json:"client_id"`
+	Balance   int64     `gorm:"not null;default:0" json:"balance"`
+	Earned    int64     `gorm:"not null;default:0" json:"earned"`   // Total earned, less reversals
+	Redeemed  int64     `gorm:"not null;default:0" json:"redeemed"` // Total redeemed, less restorations
+}
+
+const LoyaltyAccountTableName = "loyalty_accounts"
+
+func (LoyaltyAccount) TableName() string  { return LoyaltyAccountTableName }
+func (LoyaltyAccount) SchemaType() string { return "company" }
+
+// LoyaltyTransaction is an entry of the points ledger of a client. Entries are never changed,
+// corrections are new entries.
+type LoyaltyTransaction struct {
+	BaseModel
+	AccountID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"account_id"`
+	CompanyID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	ClientID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"client_id"`
+	Type          string     `gorm:"type:varchar(20);not null" json:"type"`
+	Points        int64      `gorm:"not null" json:"points"`        // Positive when credited, negative when debited
+	BalanceAfter  int64      `gorm:"not null" json:"balance_after"` // Balance of the account after the entry
+	AppointmentID *uuid.UUID `gorm:"type:uuid;index" json:"appointment_id"`
+	Description   string     `gorm:"type:varchar(255)" json:"description"`
+	CreatedByID   *uuid.UUID `gorm:"type:uuid" json:"created_by_id"` // Employee of adjustments
+}
+
+const LoyaltyTransactionTableName = "loyalty_transactions"
+
+func (LoyaltyTransaction) TableName() string  { return LoyaltyTransactionTableName }
+func (LoyaltyTransaction) SchemaType() string { return "company" }
+
+func (t *LoyaltyTransaction) BeforeUpdate(tx *gorm.DB) error {
+	return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("loyalty transactions can not be changed"))
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// --- Outbox topics --- //
+
+const (
+	OutboxTopicAppointmentEmail = "email.appointment"
+	OutboxTopicWebhookEvent     = "webhook.event"
+	OutboxTopicWebhookDelivery  = "webhook.delivery"
+	OutboxTopicPaymentRefund    = "payment.refund"
+	OutboxTopicReceiptEmail     = "email.receipt"
+	OutboxTopicClientExport     = "client.data_export"
+	OutboxTopicClientErasure    = "client.data_erasure"
+	OutboxTopicIntakeEmail      = "email.intake"
+	OutboxTopicReviewEmail      = "email.review"
+	OutboxTopicInvitationEmail  = "email.employee_invitation"
+	OutboxTopicAccountEmail     = "email.account"
+)
+
+// --- Outbox message status --- //
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "PENDING"
+	OutboxProcessed OutboxStatus = "PROCESSED"
+	OutboxDead      OutboxStatus = "DEAD"
+)
+
+// OutboxMessage is a side effect (email, webhook...) recorded in the same transaction
+// as the change that caused it. The outbox worker delivers it after the commit.
+// It lives in the public schema so a single worker drains every company.
+type OutboxMessage struct {
+	BaseModel
+	CompanyID   *uuid.UUID     `gorm:"type:uuid;index" json:"company_id"`
+	Topic       string         `gorm:"type:varchar(100);not null;index" json:"topic"`
+	Payload     datatypes.JSON `gorm:"type:jsonb;not null" json:"payload"`
+	Status      OutboxStatus   `gorm:"type:varchar(20);not null;default:'PENDING';index:idx_outbox_status_available,priority:1" json:"status"`
+	Attempts    int            `gorm:"not null;default:0" json:"attempts"`
+	LastError   string         `gorm:"type:text" json:"last_error"`
+	AvailableAt time.Time      `gorm:"not null;index:idx_outbox_status_available,priority:2" json:"available_at"`
+	ProcessedAt *time.Time     `json:"processed_at"`
+}
+
+func (OutboxMessage) TableName() string  { return "public.outbox_messages" }
+func (OutboxMessage) SchemaType() string { return "public" }
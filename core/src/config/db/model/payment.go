@@ -1,8 +1,12 @@
 package model
 
 import (
-	"gorm.io/datatypes"
+	"fmt"
+	"mynute-go/core/src/lib"
 	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
 )
 
 type PaymentStatus string
@@ -14,21 +18,55 @@ const (
 	StatusRefunded  PaymentStatus = "REFUNDED"
 )
 
+// paymentTransitions lists the statuses a payment may move to from each status.
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	StatusPending:   {StatusCompleted, StatusFailed},
+	StatusCompleted: {StatusRefunded},
+	StatusFailed:    {},
+	StatusRefunded:  {},
+}
+
+// CanMoveTo tells whether the payment status may change from s to next.
+func (s PaymentStatus) CanMoveTo(next PaymentStatus) bool {
+	for _, allowed := range paymentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type PaymentType string
+
+const (
+	PaymentTypeDeposit PaymentType = "DEPOSIT" // Part of the service price, paid when booking
+	PaymentTypeFull    PaymentType = "FULL"    // The whole service price, paid when booking
+)
+
 type Payment struct {
 	BaseModel
-	
+
 	Price    int64  `gorm:"not null" json:"price"`
 	Currency string `gorm:"type:varchar(3);not null;default:'BRL'" json:"currency"` // Default currency is BRL
 
 	// Status
-	Status PaymentStatus `gorm:"type:varchar(20);not null;index;default:'PENDING'"`
+	Status PaymentStatus `gorm:"type:varchar(20);not null;index;default:'PENDING'" json:"status"`
+	Type   PaymentType   `gorm:"type:varchar(20);not null;default:'FULL'" json:"type"`
 
 	// Payment Method Details (adjust as needed)
-	PaymentMethod string `gorm:"type:varchar(50);index"`        // e.g., "CREDIT_CARD", "PAYPAL", "BANK_TRANSFER"
-	TransactionID string `gorm:"type:varchar(100);uniqueIndex"` // External transaction ID from payment provider (often unique)
-	Provider      string `gorm:"type:varchar(50);index"`        // e.g., "Stripe", "PayPal"
+	PaymentMethod string  `gorm:"type:varchar(50);index" json:"payment_method"`        // e.g., "CREDIT_CARD", "PAYPAL", "BANK_TRANSFER"
+	TransactionID *string `gorm:"type:varchar(100);uniqueIndex" json:"transaction_id"` // External transaction ID from payment provider, set once the provider answers
+	Provider      string  `gorm:"type:varchar(50);index" json:"provider"`              // e.g., "Stripe", "PayPal"
+	FailureReason string  `gorm:"type:text" json:"failure_reason"`
+
+	// Pix: BR Code the client pays ("copia e cola") and its QR code image, once rendered
+	PixPayload   string `gorm:"type:text" json:"pix_payload"`
+	PixQRCodeURL string `gorm:"type:text" json:"pix_qr_code_url"`
 
 	// References to other models (Foreign Keys)
+	CompanyID     uuid.UUID  `gorm:"type:uuid;index" json:"company_id"`
+	ClientID      uuid.UUID  `gorm:"type:uuid;index" json:"client_id"`
+	AppointmentID *uuid.UUID `gorm:"type:uuid;index" json:"appointment_id"`
 	// Use pointers (*uint) if the relationship is optional (nullable foreign key)
 	UserID  *uint `gorm:"index"` // Foreign key to a User model (if applicable)
 	OrderID *uint `gorm:"index"` // Foreign key to an Order model (if applicable)
@@ -36,14 +74,49 @@ type Payment struct {
 
 	// Additional Metadata (optional)
 	// Use JSONB for flexible metadata storage in PostgreSQL
-	Metadata *datatypes.JSON `gorm:"type:jsonb"` // Example: Store additional provider details
+	Metadata *datatypes.JSON `gorm:"type:jsonb" json:"metadata"` // Example: Store additional provider details
 	// If you don't need Metadata right away, you can omit this field or use:
 	// Metadata string `gorm:"type:text"` // Simpler text storage if JSONB isn't needed
 
+	// Refunds
+	RefundedAmount int64 `gorm:"not null;default:0" json:"refunded_amount"`
+
 	// Optional: Timestamps specific to payment lifecycle
-	CompletedAt *time.Time // When the payment transitioned to COMPLETED
-	FailedAt    *time.Time // When the payment transitioned to FAILED
+	CompletedAt *time.Time `json:"completed_at"` // When the payment transitioned to COMPLETED
+	FailedAt    *time.Time `json:"failed_at"`    // When the payment transitioned to FAILED
+	RefundedAt  *time.Time `json:"refunded_at"`  // When the payment transitioned to REFUNDED
 }
 
 func (Payment) TableName() string  { return "payments" }
 func (Payment) SchemaType() string { return "company" }
+
+// MoveTo changes the status and stamps the matching timestamp.
+// It fails when the transition is not allowed (e.g. FAILED -> COMPLETED).
+func (p *Payment) MoveTo(next PaymentStatus, at time.Time) error {
+	if p.Status == next {
+		return nil
+	}
+	if !p.Status.CanMoveTo(next) {
+		return lib.Error.Payment.InvalidTransition.WithError(fmt.Errorf("payment %s cannot move from %s to %s", p.ID, p.Status, next))
+	}
+	p.Status = next
+	switch next {
+	case StatusCompleted:
+		p.CompletedAt = &at
+	case StatusFailed:
+		p.FailedAt = &at
+	case StatusRefunded:
+		p.RefundedAt = &at
+	}
+	return nil
+}
+
+// RecordRefund records that total of the price was refunded so far. The payment only
+// moves to REFUNDED once the whole price was given back, partial refunds keep it COMPLETED.
+func (p *Payment) RecordRefund(total int64, at time.Time) error {
+	p.RefundedAmount = max(p.RefundedAmount, min(total, p.Price))
+	if p.RefundedAmount < p.Price {
+		return nil
+	}
+	return p.MoveTo(StatusRefunded, at)
+}
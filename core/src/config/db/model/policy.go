@@ -658,6 +658,33 @@ func init_policy_array() []*PolicyRule { // --- Reusable Condition Checks --- //
 		}),
 	}
 
+	// Policy: Allow GET appointment history. Same audience as viewing the appointment.
+	var AllowGetAppointmentHistory = &PolicyRule{
+		Name:        "SDP: CanViewAppointmentHistory",
+		Description: "Allows clients to view the history of own appointments, or company users based on role/relation.",
+		Effect:      "Allow",
+		EndPointID:  GetAppointmentHistory.ID,
+		Conditions:  AllowGetAppointmentByID.Conditions,
+	}
+
+	// Policy: Allow GET appointment receipt. Same audience as viewing the appointment.
+	var AllowGetAppointmentReceipt = &PolicyRule{
+		Name:        "SDP: CanViewAppointmentReceipt",
+		Description: "Allows clients to get the receipt of own appointments, or company users based on role/relation.",
+		Effect:      "Allow",
+		EndPointID:  GetAppointmentReceipt.ID,
+		Conditions:  AllowGetAppointmentByID.Conditions,
+	}
+
+	// Policy: Allow GET appointment intake. The answers are health data, only for company users.
+	var AllowGetAppointmentIntake = &PolicyRule{
+		Name:        "SDP: CanViewAppointmentIntake",
+		Description: "Allows company members to view the intake form answers and signed consents of appointments.",
+		Effect:      "Allow",
+		EndPointID:  GetAppointmentIntake.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
 	// Policy: Allow UPDATE appointment by ID.
 	var AllowUpdateAppointmentByID = &PolicyRule{
 		Name:        "SDP: CanUpdateAppointment",
@@ -963,6 +990,38 @@ func init_policy_array() []*PolicyRule { // --- Reusable Condition Checks --- //
 		}),
 	}
 
+	var AllowCreateBookableResource = &PolicyRule{
+		Name:        "SDP: CanCreateBookableResource",
+		Description: "Allows company Owner, General Manager, or assigned Branch Manager to add rooms, chairs and equipment to a branch.",
+		Effect:      "Allow",
+		EndPointID:  CreateBookableResource.ID,
+		Conditions:  AllowUpdateBranchById.Conditions,
+	}
+
+	var AllowGetBookableResources = &PolicyRule{
+		Name:        "SDP: CanViewBookableResources",
+		Description: "Allows company members to view the rooms, chairs and equipment of a branch.",
+		Effect:      "Allow",
+		EndPointID:  GetBookableResources.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowUpdateBookableResource = &PolicyRule{
+		Name:        "SDP: CanUpdateBookableResource",
+		Description: "Allows company Owner, General Manager, or assigned Branch Manager to update rooms, chairs and equipment of a branch.",
+		Effect:      "Allow",
+		EndPointID:  UpdateBookableResource.ID,
+		Conditions:  AllowUpdateBranchById.Conditions,
+	}
+
+	var AllowDeleteBookableResource = &PolicyRule{
+		Name:        "SDP: CanDeleteBookableResource",
+		Description: "Allows company Owner, General Manager, or assigned Branch Manager to delete rooms, chairs and equipment of a branch.",
+		Effect:      "Allow",
+		EndPointID:  DeleteBookableResource.ID,
+		Conditions:  AllowUpdateBranchById.Conditions,
+	}
+
 	// --- Client Policies ---
 
 	var AllowGetClientByEmail = &PolicyRule{
@@ -1028,6 +1087,102 @@ func init_policy_array() []*PolicyRule { // --- Reusable Condition Checks --- //
 		Conditions:  JsonRawMessage(client_self_access_check), // Client can view self appointments (checks subject.id == resource.id)
 	}
 
+	var AllowUpgradeGuestClient = &PolicyRule{
+		Name:        "SDP: CanUpgradeGuestClient",
+		Description: "Allows a guest client to turn itself into a client with an account.",
+		Effect:      "Allow",
+		EndPointID:  UpgradeGuestClient.ID,
+		Conditions:  JsonRawMessage(client_self_access_check), // Guest can upgrade self (checks subject.id == resource.id)
+	}
+
+	var AllowRequestClientDataExport = &PolicyRule{
+		Name:        "SDP: CanRequestClientDataExport",
+		Description: "Allows a client to request an export of their own data.",
+		Effect:      "Allow",
+		EndPointID:  RequestClientDataExport.ID,
+		Conditions:  JsonRawMessage(client_self_access_check), // Client can export self (checks subject.id == resource.id)
+	}
+
+	var AllowRequestClientDataErasure = &PolicyRule{
+		Name:        "SDP: CanRequestClientDataErasure",
+		Description: "Allows a client to request the erasure of their own data.",
+		Effect:      "Allow",
+		EndPointID:  RequestClientDataErasure.ID,
+		Conditions:  JsonRawMessage(client_self_access_check), // Client can erase self (checks subject.id == resource.id)
+	}
+
+	var AllowGetClientDataRequests = &PolicyRule{
+		Name:        "SDP: CanViewClientDataRequests",
+		Description: "Allows a client to view their own data requests.",
+		Effect:      "Allow",
+		EndPointID:  GetClientDataRequests.ID,
+		Conditions:  JsonRawMessage(client_self_access_check), // Client can view self requests (checks subject.id == resource.id)
+	}
+
+	var AllowDownloadClientDataExport = &PolicyRule{
+		Name:        "SDP: CanDownloadClientDataExport",
+		Description: "Allows a client to download their own data exports.",
+		Effect:      "Allow",
+		EndPointID:  DownloadClientDataExport.ID,
+		Conditions:  JsonRawMessage(client_self_access_check), // Client can download self exports (checks subject.id == resource.id)
+	}
+
+	var AllowCreateClientDependent = &PolicyRule{
+		Name:        "SDP: CanCreateClientDependent",
+		Description: "Allows a client to add dependents to their own account.",
+		Effect:      "Allow",
+		EndPointID:  CreateClientDependent.ID,
+		Conditions:  JsonRawMessage(client_self_access_check), // Client can add own dependents (checks subject.id == resource.id)
+	}
+
+	var AllowGetClientDependents = &PolicyRule{
+		Name:        "SDP: CanViewClientDependents",
+		Description: "Allows a client to view their own dependents.",
+		Effect:      "Allow",
+		EndPointID:  GetClientDependents.ID,
+		Conditions:  JsonRawMessage(client_self_access_check), // Client can view own dependents (checks subject.id == resource.id)
+	}
+
+	var AllowUpdateClientDependent = &PolicyRule{
+		Name:        "SDP: CanUpdateClientDependent",
+		Description: "Allows a client to update their own dependents.",
+		Effect:      "Allow",
+		EndPointID:  UpdateClientDependent.ID,
+		Conditions:  JsonRawMessage(client_self_access_check), // Client can update own dependents (checks subject.id == resource.id)
+	}
+
+	var AllowDeleteClientDependent = &PolicyRule{
+		Name:        "SDP: CanDeleteClientDependent",
+		Description: "Allows a client to remove their own dependents.",
+		Effect:      "Allow",
+		EndPointID:  DeleteClientDependent.ID,
+		Conditions:  JsonRawMessage(client_self_access_check), // Client can remove own dependents (checks subject.id == resource.id)
+	}
+
+	var AllowGetClientProfile = &PolicyRule{
+		Name:        "SDP: CanViewClientProfile",
+		Description: "Allows company members to view what the company keeps about a client.",
+		Effect:      "Allow",
+		EndPointID:  GetClientProfile.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowUpdateClientProfile = &PolicyRule{
+		Name:        "SDP: CanUpdateClientProfile",
+		Description: "Allows company members to update the tags, notes and preferences of a client.",
+		Effect:      "Allow",
+		EndPointID:  UpdateClientProfile.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowGetCompanyClients = &PolicyRule{
+		Name:        "SDP: CanListCompanyClients",
+		Description: "Allows company members to search the clients of the company.",
+		Effect:      "Allow",
+		EndPointID:  GetCompanyClients.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
 	// --- Company Policies ---
 
 	var AllowGetCompanyById = &PolicyRule{
@@ -1349,6 +1504,483 @@ func init_policy_array() []*PolicyRule { // --- Reusable Condition Checks --- //
 		Conditions:  JsonRawMessage(company_admin_check), // Any manager of the service's company
 	}
 
+	var AllowCreateServiceOverride = &PolicyRule{
+		Name:        "SDP: CanCreateServiceOverride",
+		Description: "Allows company managers (Owner, GM, BM) to override service prices and durations.",
+		Effect:      "Allow",
+		EndPointID:  CreateServiceOverride.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowGetServiceOverrides = &PolicyRule{
+		Name:        "SDP: CanViewServiceOverrides",
+		Description: "Allows company members to view service price and duration overrides.",
+		Effect:      "Allow",
+		EndPointID:  GetServiceOverrides.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowDeleteServiceOverride = &PolicyRule{
+		Name:        "SDP: CanDeleteServiceOverride",
+		Description: "Allows company managers (Owner, GM, BM) to delete service price and duration overrides.",
+		Effect:      "Allow",
+		EndPointID:  DeleteServiceOverride.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowAddServiceResource = &PolicyRule{
+		Name:        "SDP: CanAddServiceResource",
+		Description: "Allows company managers (Owner, GM, BM) to make services hold resources.",
+		Effect:      "Allow",
+		EndPointID:  AddServiceResource.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowGetServiceResources = &PolicyRule{
+		Name:        "SDP: CanViewServiceResources",
+		Description: "Allows company members to view the resources services hold.",
+		Effect:      "Allow",
+		EndPointID:  GetServiceResources.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowRemoveServiceResource = &PolicyRule{
+		Name:        "SDP: CanRemoveServiceResource",
+		Description: "Allows company managers (Owner, GM, BM) to stop services from holding resources.",
+		Effect:      "Allow",
+		EndPointID:  RemoveServiceResource.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	// --- Webhook Policies --- (Company Admins only)
+
+	var AllowCreateWebhook = &PolicyRule{
+		Name:        "SDP: CanCreateWebhook",
+		Description: "Allows company admins (Owner, GM) to create webhook subscriptions.",
+		Effect:      "Allow",
+		EndPointID:  CreateWebhook.ID,
+		Conditions:  JsonRawMessage(company_admin_check),
+	}
+
+	var AllowGetCompanyWebhooks = &PolicyRule{
+		Name:        "SDP: CanListCompanyWebhooks",
+		Description: "Allows company admins (Owner, GM) to list webhook subscriptions.",
+		Effect:      "Allow",
+		EndPointID:  GetCompanyWebhooks.ID,
+		Conditions:  JsonRawMessage(company_admin_check),
+	}
+
+	var AllowGetWebhookById = &PolicyRule{
+		Name:        "SDP: CanViewWebhookById",
+		Description: "Allows company admins (Owner, GM) to view a webhook subscription.",
+		Effect:      "Allow",
+		EndPointID:  GetWebhookById.ID,
+		Conditions:  JsonRawMessage(company_admin_check),
+	}
+
+	var AllowUpdateWebhookById = &PolicyRule{
+		Name:        "SDP: CanUpdateWebhook",
+		Description: "Allows company admins (Owner, GM) to update webhook subscriptions.",
+		Effect:      "Allow",
+		EndPointID:  UpdateWebhookById.ID,
+		Conditions:  JsonRawMessage(company_admin_check),
+	}
+
+	var AllowDeleteWebhookById = &PolicyRule{
+		Name:        "SDP: CanDeleteWebhook",
+		Description: "Allows company admins (Owner, GM) to delete webhook subscriptions.",
+		Effect:      "Allow",
+		EndPointID:  DeleteWebhookById.ID,
+		Conditions:  JsonRawMessage(company_admin_check),
+	}
+
+	var AllowGetWebhookDeliveries = &PolicyRule{
+		Name:        "SDP: CanViewWebhookDeliveries",
+		Description: "Allows company admins (Owner, GM) to view the webhook delivery log.",
+		Effect:      "Allow",
+		EndPointID:  GetWebhookDeliveries.ID,
+		Conditions:  JsonRawMessage(company_admin_check),
+	}
+
+	var AllowReplayWebhookDelivery = &PolicyRule{
+		Name:        "SDP: CanReplayWebhookDelivery",
+		Description: "Allows company admins (Owner, GM) to replay webhook deliveries.",
+		Effect:      "Allow",
+		EndPointID:  ReplayWebhookDelivery.ID,
+		Conditions:  JsonRawMessage(company_admin_check),
+	}
+
+	var AllowRotateWebhookSecret = &PolicyRule{
+		Name:        "SDP: CanRotateWebhookSecret",
+		Description: "Allows company admins (Owner, GM) to replace the signing secret of a webhook subscription.",
+		Effect:      "Allow",
+		EndPointID:  RotateWebhookSecret.ID,
+		Conditions:  JsonRawMessage(company_admin_check),
+	}
+
+	// --- Payment Policies --- //
+
+	var AllowGetPaymentById = &PolicyRule{
+		Name:        "SDP: CanViewPaymentById",
+		Description: "Allows clients to view own payments, or company managers (Owner, GM, BM) to view the company payments.",
+		Effect:      "Allow",
+		EndPointID:  GetPaymentById.ID,
+		Conditions: JsonRawMessage(ConditionNode{
+			Description: "Allow Client Access OR Company Internal User Access",
+			LogicType:   "OR",
+			Children: []ConditionNode{
+				client_access_check,
+				company_internal_user_check,
+			},
+		}),
+	}
+
+	var AllowGetPaymentPix = &PolicyRule{
+		Name:        "SDP: CanViewPaymentPix",
+		Description: "Allows clients to view the Pix code of own payments, or company managers (Owner, GM, BM).",
+		Effect:      "Allow",
+		EndPointID:  GetPaymentPix.ID,
+		Conditions:  AllowGetPaymentById.Conditions,
+	}
+
+	var AllowRefundPaymentById = &PolicyRule{
+		Name:        "SDP: CanRefundPayment",
+		Description: "Allows company admins (Owner, GM) to refund payments.",
+		Effect:      "Allow",
+		EndPointID:  RefundPaymentById.ID,
+		Conditions:  JsonRawMessage(company_admin_check),
+	}
+
+	// --- Promo Code Policies --- //
+
+	var AllowCreatePromoCode = &PolicyRule{
+		Name:        "SDP: CanCreatePromoCode",
+		Description: "Allows company managers (Owner, GM, BM) to create promo codes.",
+		Effect:      "Allow",
+		EndPointID:  CreatePromoCode.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowGetCompanyPromoCodes = &PolicyRule{
+		Name:        "SDP: CanListCompanyPromoCodes",
+		Description: "Allows company members to list promo codes.",
+		Effect:      "Allow",
+		EndPointID:  GetCompanyPromoCodes.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowGetPromoCodeById = &PolicyRule{
+		Name:        "SDP: CanViewPromoCode",
+		Description: "Allows company members to view promo codes.",
+		Effect:      "Allow",
+		EndPointID:  GetPromoCodeById.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowUpdatePromoCodeById = &PolicyRule{
+		Name:        "SDP: CanUpdatePromoCode",
+		Description: "Allows company managers (Owner, GM, BM) to update promo codes.",
+		Effect:      "Allow",
+		EndPointID:  UpdatePromoCodeById.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowDeletePromoCodeById = &PolicyRule{
+		Name:        "SDP: CanDeletePromoCode",
+		Description: "Allows company managers (Owner, GM, BM) to delete promo codes.",
+		Effect:      "Allow",
+		EndPointID:  DeletePromoCodeById.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	// --- Schedule Template Policies --- //
+
+	var AllowCreateScheduleTemplate = &PolicyRule{
+		Name:        "SDP: CanCreateScheduleTemplate",
+		Description: "Allows company managers (Owner, GM, BM) to create schedule templates.",
+		Effect:      "Allow",
+		EndPointID:  CreateScheduleTemplate.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowGetCompanyScheduleTemplates = &PolicyRule{
+		Name:        "SDP: CanListCompanyScheduleTemplates",
+		Description: "Allows company members to list schedule templates.",
+		Effect:      "Allow",
+		EndPointID:  GetCompanyScheduleTemplates.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowGetScheduleTemplateById = &PolicyRule{
+		Name:        "SDP: CanViewScheduleTemplate",
+		Description: "Allows company members to view schedule templates.",
+		Effect:      "Allow",
+		EndPointID:  GetScheduleTemplateById.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowUpdateScheduleTemplateById = &PolicyRule{
+		Name:        "SDP: CanUpdateScheduleTemplate",
+		Description: "Allows company managers (Owner, GM, BM) to update schedule templates.",
+		Effect:      "Allow",
+		EndPointID:  UpdateScheduleTemplateById.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowDeleteScheduleTemplateById = &PolicyRule{
+		Name:        "SDP: CanDeleteScheduleTemplate",
+		Description: "Allows company managers (Owner, GM, BM) to delete schedule templates.",
+		Effect:      "Allow",
+		EndPointID:  DeleteScheduleTemplateById.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowApplyScheduleTemplate = &PolicyRule{
+		Name:        "SDP: CanApplyScheduleTemplate",
+		Description: "Allows company managers (Owner, GM, BM) to apply schedule templates to employees and branches.",
+		Effect:      "Allow",
+		EndPointID:  ApplyScheduleTemplate.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowCopyEmployeeWorkSchedule = &PolicyRule{
+		Name:        "SDP: CanCopyEmployeeWorkSchedule",
+		Description: "Allows company managers (Owner, GM, BM) to copy the work schedule of an employee to another.",
+		Effect:      "Allow",
+		EndPointID:  CopyEmployeeWorkSchedule.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	// --- Package Policies --- //
+
+	var AllowCreateServicePackage = &PolicyRule{
+		Name:        "SDP: CanCreateServicePackage",
+		Description: "Allows company managers (Owner, GM, BM) to create packages and memberships.",
+		Effect:      "Allow",
+		EndPointID:  CreateServicePackage.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowGetCompanyServicePackages = &PolicyRule{
+		Name:        "SDP: CanListCompanyServicePackages",
+		Description: "Allows company members to list packages and memberships.",
+		Effect:      "Allow",
+		EndPointID:  GetCompanyServicePackages.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowGetServicePackageById = &PolicyRule{
+		Name:        "SDP: CanViewServicePackage",
+		Description: "Allows company members to view packages and memberships.",
+		Effect:      "Allow",
+		EndPointID:  GetServicePackageById.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowUpdateServicePackageById = &PolicyRule{
+		Name:        "SDP: CanUpdateServicePackage",
+		Description: "Allows company managers (Owner, GM, BM) to update packages and memberships.",
+		Effect:      "Allow",
+		EndPointID:  UpdateServicePackageById.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowDeleteServicePackageById = &PolicyRule{
+		Name:        "SDP: CanDeleteServicePackage",
+		Description: "Allows company managers (Owner, GM, BM) to delete packages and memberships.",
+		Effect:      "Allow",
+		EndPointID:  DeleteServicePackageById.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowSellServicePackage = &PolicyRule{
+		Name:        "SDP: CanSellServicePackage",
+		Description: "Allows company managers (Owner, GM, BM) to sell packages and memberships to clients.",
+		Effect:      "Allow",
+		EndPointID:  SellServicePackage.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowGetClientCredits = &PolicyRule{
+		Name:        "SDP: CanViewClientCredits",
+		Description: "Allows clients to view their own credit balance, and company members to view the balance of any client.",
+		Effect:      "Allow",
+		EndPointID:  GetClientCredits.ID,
+		Conditions: JsonRawMessage(ConditionNode{
+			Description: "Allow Client Self Access OR Company User Access",
+			LogicType:   "OR",
+			Children: []ConditionNode{
+				{
+					Description: "Client Self Access",
+					LogicType:   "AND",
+					Children: []ConditionNode{
+						{Leaf: &ConditionLeaf{Attribute: "subject.company_id", Operator: "IsNull", Description: "Must be a Client"}},
+						{Leaf: &ConditionLeaf{Attribute: "subject.id", Operator: "Equals", ResourceAttribute: "path.client_id", Description: "Client ID in path must match Subject ID"}},
+					},
+				},
+				company_internal_user_check,
+			},
+		}),
+	}
+
+	// --- Intake Form Policies --- //
+
+	var AllowCreateIntakeForm = &PolicyRule{
+		Name:        "SDP: CanCreateIntakeForm",
+		Description: "Allows company managers (Owner, GM, BM) to create intake forms.",
+		Effect:      "Allow",
+		EndPointID:  CreateIntakeForm.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowGetCompanyIntakeForms = &PolicyRule{
+		Name:        "SDP: CanListCompanyIntakeForms",
+		Description: "Allows company members to list intake forms.",
+		Effect:      "Allow",
+		EndPointID:  GetCompanyIntakeForms.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowGetIntakeFormById = &PolicyRule{
+		Name:        "SDP: CanViewIntakeForm",
+		Description: "Allows company members to view intake forms.",
+		Effect:      "Allow",
+		EndPointID:  GetIntakeFormById.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowUpdateIntakeFormById = &PolicyRule{
+		Name:        "SDP: CanUpdateIntakeForm",
+		Description: "Allows company managers (Owner, GM, BM) to update intake forms.",
+		Effect:      "Allow",
+		EndPointID:  UpdateIntakeFormById.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowDeleteIntakeFormById = &PolicyRule{
+		Name:        "SDP: CanDeleteIntakeForm",
+		Description: "Allows company managers (Owner, GM, BM) to delete intake forms.",
+		Effect:      "Allow",
+		EndPointID:  DeleteIntakeFormById.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowGetCompanyReviews = &PolicyRule{
+		Name:        "SDP: CanListCompanyReviews",
+		Description: "Allows company members to list reviews.",
+		Effect:      "Allow",
+		EndPointID:  GetCompanyReviews.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowGetReviewById = &PolicyRule{
+		Name:        "SDP: CanViewReview",
+		Description: "Allows company members to view reviews.",
+		Effect:      "Allow",
+		EndPointID:  GetReviewById.ID,
+		Conditions:  JsonRawMessage(company_internal_user_check),
+	}
+
+	var AllowModerateReviewById = &PolicyRule{
+		Name:        "SDP: CanModerateReview",
+		Description: "Allows company managers (Owner, GM, BM) to publish or reject reviews.",
+		Effect:      "Allow",
+		EndPointID:  ModerateReviewById.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	// --- Loyalty Policies --- //
+
+	var AllowSaveLoyaltyProgram = &PolicyRule{
+		Name:        "SDP: CanSaveLoyaltyProgram",
+		Description: "Allows company managers (Owner, GM, BM) to set up the loyalty program.",
+		Effect:      "Allow",
+		EndPointID:  SaveLoyaltyProgram.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowGetClientLoyalty = &PolicyRule{
+		Name:        "SDP: CanViewClientLoyalty",
+		Description: "Allows clients to view their own loyalty points, and company members to view the points of any client.",
+		Effect:      "Allow",
+		EndPointID:  GetClientLoyalty.ID,
+		Conditions: JsonRawMessage(ConditionNode{
+			Description: "Allow Client Self Access OR Company User Access",
+			LogicType:   "OR",
+			Children: []ConditionNode{
+				{
+					Description: "Client Self Access",
+					LogicType:   "AND",
+					Children: []ConditionNode{
+						{Leaf: &ConditionLeaf{Attribute: "subject.company_id", Operator: "IsNull", Description: "Must be a Client"}},
+						{Leaf: &ConditionLeaf{Attribute: "subject.id", Operator: "Equals", ResourceAttribute: "path.client_id", Description: "Client ID in path must match Subject ID"}},
+					},
+				},
+				company_internal_user_check,
+			},
+		}),
+	}
+
+	var AllowAdjustClientLoyalty = &PolicyRule{
+		Name:        "SDP: CanAdjustClientLoyalty",
+		Description: "Allows company managers (Owner, GM, BM) to grant or remove loyalty points of clients.",
+		Effect:      "Allow",
+		EndPointID:  AdjustClientLoyalty.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	// --- Import Policies --- //
+
+	var AllowImportRecords = &PolicyRule{
+		Name:        "SDP: CanImportRecords",
+		Description: "Allows company managers (Owner, GM, BM) to import services, employees and clients.",
+		Effect:      "Allow",
+		EndPointID:  ImportRecords.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	// --- Employee Invitation Policies --- //
+
+	var AllowInviteEmployee = &PolicyRule{
+		Name:        "SDP: CanInviteEmployee",
+		Description: "Allows company managers (Owner, GM, BM) to invite employees.",
+		Effect:      "Allow",
+		EndPointID:  InviteEmployee.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowGetEmployeeInvitation = &PolicyRule{
+		Name:        "SDP: CanViewEmployeeInvitation",
+		Description: "Allows company managers (Owner, GM, BM) to view the invitation of an employee.",
+		Effect:      "Allow",
+		EndPointID:  GetEmployeeInvitation.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowResendEmployeeInvitation = &PolicyRule{
+		Name:        "SDP: CanResendEmployeeInvitation",
+		Description: "Allows company managers (Owner, GM, BM) to resend the invitation of an employee.",
+		Effect:      "Allow",
+		EndPointID:  ResendEmployeeInvitation.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowRevokeEmployeeInvitation = &PolicyRule{
+		Name:        "SDP: CanRevokeEmployeeInvitation",
+		Description: "Allows company managers (Owner, GM, BM) to revoke the invitation of an employee.",
+		Effect:      "Allow",
+		EndPointID:  RevokeEmployeeInvitation.ID,
+		Conditions:  JsonRawMessage(company_manager_check),
+	}
+
+	var AllowUpdateEmployeeStatus = &PolicyRule{
+		Name:        "SDP: CanUpdateEmployeeStatus",
+		Description: "Allows company Owner or GM to suspend or reactivate employees.",
+		Effect:      "Allow",
+		EndPointID:  UpdateEmployeeStatus.ID,
+		Conditions:  JsonRawMessage(company_admin_check), // Only Owner or GM of this company
+	}
+
 	// --- Combined Policies List ---
 	var Policies = []*PolicyRule{
 		// Appointments
@@ -1356,6 +1988,9 @@ func init_policy_array() []*PolicyRule { // --- Reusable Condition Checks --- //
 		AllowCreateAppointment,
 		AllowUpdateAppointmentByID,
 		AllowCancelAppointmentByID,
+		AllowGetAppointmentHistory,
+		AllowGetAppointmentReceipt,
+		AllowGetAppointmentIntake,
 
 		// Branches
 		AllowCreateBranch,
@@ -1375,6 +2010,10 @@ func init_policy_array() []*PolicyRule { // --- Reusable Condition Checks --- //
 		AllowAddBranchWorkRangeService,
 		AllowDeleteBranchWorkRangeService,
 		AllowGetBranchAppointmentsById,
+		AllowCreateBookableResource,
+		AllowGetBookableResources,
+		AllowUpdateBookableResource,
+		AllowDeleteBookableResource,
 
 		// Clients (Self-Management focused)
 		AllowGetClientByEmail,
@@ -1384,6 +2023,18 @@ func init_policy_array() []*PolicyRule { // --- Reusable Condition Checks --- //
 		AllowUpdateClientImages,
 		AllowDeleteClientImage,
 		AllowGetClientAppointmentsById,
+		AllowUpgradeGuestClient,
+		AllowRequestClientDataExport,
+		AllowRequestClientDataErasure,
+		AllowGetClientDataRequests,
+		AllowDownloadClientDataExport,
+		AllowCreateClientDependent,
+		AllowGetClientDependents,
+		AllowUpdateClientDependent,
+		AllowDeleteClientDependent,
+		AllowGetClientProfile,
+		AllowUpdateClientProfile,
+		AllowGetCompanyClients,
 
 		// Company
 		AllowGetCompanyById,
@@ -1431,6 +2082,70 @@ func init_policy_array() []*PolicyRule { // --- Reusable Condition Checks --- //
 		AllowDeleteServiceById,
 		AllowUpdateServiceImages,
 		AllowDeleteServiceImage,
+		AllowCreateServiceOverride,
+		AllowGetServiceOverrides,
+		AllowDeleteServiceOverride,
+		AllowAddServiceResource,
+		AllowGetServiceResources,
+		AllowRemoveServiceResource,
+
+		// Webhooks
+		AllowCreateWebhook,
+		AllowGetCompanyWebhooks,
+		AllowGetWebhookById,
+		AllowUpdateWebhookById,
+		AllowDeleteWebhookById,
+		AllowGetWebhookDeliveries,
+		AllowReplayWebhookDelivery,
+		AllowRotateWebhookSecret,
+		// Payments
+		AllowGetPaymentById,
+		AllowGetPaymentPix,
+		AllowRefundPaymentById,
+		// Promo Codes
+		AllowCreatePromoCode,
+		AllowGetCompanyPromoCodes,
+		AllowGetPromoCodeById,
+		AllowUpdatePromoCodeById,
+		AllowDeletePromoCodeById,
+		// Schedule Templates
+		AllowCreateScheduleTemplate,
+		AllowGetCompanyScheduleTemplates,
+		AllowGetScheduleTemplateById,
+		AllowUpdateScheduleTemplateById,
+		AllowDeleteScheduleTemplateById,
+		AllowApplyScheduleTemplate,
+		AllowCopyEmployeeWorkSchedule,
+		// Packages
+		AllowCreateServicePackage,
+		AllowGetCompanyServicePackages,
+		AllowGetServicePackageById,
+		AllowUpdateServicePackageById,
+		AllowDeleteServicePackageById,
+		AllowSellServicePackage,
+		AllowGetClientCredits,
+		// Intake Forms
+		AllowCreateIntakeForm,
+		AllowGetCompanyIntakeForms,
+		AllowGetIntakeFormById,
+		AllowUpdateIntakeFormById,
+		AllowDeleteIntakeFormById,
+		// Reviews
+		AllowGetCompanyReviews,
+		AllowGetReviewById,
+		AllowModerateReviewById,
+		// Loyalty
+		AllowSaveLoyaltyProgram,
+		AllowGetClientLoyalty,
+		AllowAdjustClientLoyalty,
+		// Import
+		AllowImportRecords,
+		// Employee Invitation
+		AllowInviteEmployee,
+		AllowGetEmployeeInvitation,
+		AllowResendEmployeeInvitation,
+		AllowRevokeEmployeeInvitation,
+		AllowUpdateEmployeeStatus,
 	}
 
 	return Policies
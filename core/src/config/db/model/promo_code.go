@@ -0,0 +1,155 @@
+package model
+
+import (
+	"fmt"
+	"mynute-go/core/src/lib"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Discount types of a promo code.
+const (
+	DiscountPercentage = "PERCENTAGE" // DiscountValue is a percentage of the price, from 1 to 100
+	DiscountFixed      = "FIXED"      // DiscountValue is an amount in cents, capped at the price
+)
+
+var promoCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,40}$`)
+
+// PromoCode is a company discount clients enter when booking.
+// A code is used by each appointment booked with it that is not cancelled,
+// cancelling the appointment gives the use back.
+type PromoCode struct {
+	BaseModel
+	CompanyID     uuid.UUID `gorm:"type:uuid;not null;index" json:"company_id"`
+	Code          string    `gorm:"type:varchar(40);not null" json:"code"`
+	Description   string    `gorm:"type:text" json:"description"`
+	DiscountType  string    `gorm:"type:varchar(20);not null" json:"discount_type"`
+	DiscountValue int64     `gorm:"not null" json:"discount_value"`
+	// Validity window of the code, checked at booking. nil leaves the side open.
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+	// Usage limits, 0 is unlimited
+	MaxUses          int64 `gorm:"not null;default:0" json:"max_uses"`
+	MaxUsesPerClient int64 `gorm:"not null;default:0" json:"max_uses_per_client"`
+	FirstVisitOnly   bool  `gorm:"not null;default:false" json:"first_visit_only"` // Only for clients without appointments at the company
+	// Restrictions, an empty list allows all
+	ServiceIDs  UUIDList `gorm:"type:jsonb" json:"service_ids"`
+	BranchIDs   UUIDList `gorm:"type:jsonb" json:"branch_ids"`
+	EmployeeIDs UUIDList `gorm:"type:jsonb" json:"employee_ids"`
+	IsActive    bool     `gorm:"not null;default:true" json:"is_active"`
+}
+
+const PromoCodeTableName = "promo_codes"
+
+func (PromoCode) TableName() string  { return PromoCodeTableName }
+func (PromoCode) SchemaType() string { return "company" }
+func (PromoCode) Indexes() map[string]string {
+	return PromoCodeIndexes(PromoCodeTableName)
+}
+
+func PromoCodeIndexes(table string) map[string]string {
+	return map[string]string{
+		"idx_promo_code_company_code": fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_promo_code_company_code ON %s (company_id, code) WHERE deleted_at IS NULL", table),
+	}
+}
+
+// NormalizePromoCode makes codes case insensitive.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (p *PromoCode) BeforeCreate(tx *gorm.DB) error {
+	p.Code = NormalizePromoCode(p.Code)
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	var count int64
+	if err := tx.Model(&PromoCode{}).Where("company_id = ? AND code = ?", p.CompanyID, p.Code).Count(&count).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error checking existing promo code: %w", err))
+	}
+	if count > 0 {
+		return lib.Error.PromoCode.AlreadyExists
+	}
+	return nil
+}
+
+func (p *PromoCode) BeforeUpdate(tx *gorm.DB) error {
+	if tx.Statement.Changed("CompanyID") {
+		return lib.Error.Company.IdUpdateForbidden
+	}
+	if tx.Statement.Changed("Code") {
+		return lib.Error.PromoCode.Invalid.WithError(fmt.Errorf("the code can not be changed, create a new one instead"))
+	}
+	return nil
+}
+
+func (p *PromoCode) Validate() error {
+	if p.CompanyID == uuid.Nil {
+		return lib.Error.PromoCode.Invalid.WithError(fmt.Errorf("company_id is required"))
+	}
+	if !promoCodePattern.MatchString(p.Code) {
+		return lib.Error.PromoCode.Invalid.WithError(fmt.Errorf("code must have 3 to 40 letters, digits, '-' or '_'"))
+	}
+	switch p.DiscountType {
+	case DiscountPercentage:
+		if p.DiscountValue < 1 || p.DiscountValue > 100 {
+			return lib.Error.PromoCode.Invalid.WithError(fmt.Errorf("percentage discount must be between 1 and 100"))
+		}
+	case DiscountFixed:
+		if p.DiscountValue <= 0 {
+			return lib.Error.PromoCode.Invalid.WithError(fmt.Errorf("fixed discount must be positive"))
+		}
+	default:
+		return lib.Error.PromoCode.Invalid.WithError(fmt.Errorf("discount_type must be %s or %s", DiscountPercentage, DiscountFixed))
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return lib.Error.PromoCode.Invalid.WithError(fmt.Errorf("ends_at must be after starts_at"))
+	}
+	if p.MaxUses < 0 || p.MaxUsesPerClient < 0 {
+		return lib.Error.PromoCode.Invalid.WithError(fmt.Errorf("usage limits must not be negative"))
+	}
+	return nil
+}
+
+// AppliesTo checks the state, validity window and restrictions of the code for
+// the appointment booked at now. Usage limits are checked against the database.
+func (p *PromoCode) AppliesTo(a *Appointment, now time.Time) error {
+	if !p.IsActive {
+		return lib.Error.PromoCode.Inactive
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return lib.Error.PromoCode.Inactive.WithError(fmt.Errorf("code is valid from %s", p.StartsAt.Format(time.RFC3339)))
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return lib.Error.PromoCode.Inactive.WithError(fmt.Errorf("code expired at %s", p.EndsAt.Format(time.RFC3339)))
+	}
+	if !p.ServiceIDs.AllowsAny(a.ServiceID) {
+		return lib.Error.PromoCode.NotApplicable.WithError(fmt.Errorf("code is not valid for this service"))
+	}
+	if !p.BranchIDs.AllowsAny(a.BranchID) {
+		return lib.Error.PromoCode.NotApplicable.WithError(fmt.Errorf("code is not valid at this branch"))
+	}
+	if !p.EmployeeIDs.AllowsAny(a.EmployeeID) {
+		return lib.Error.PromoCode.NotApplicable.WithError(fmt.Errorf("code is not valid with this employee"))
+	}
+	return nil
+}
+
+// DiscountOn returns the discount the code gives on price, never more than the price.
+func (p *PromoCode) DiscountOn(price int64) int64 {
+	if price <= 0 {
+		return 0
+	}
+	switch p.DiscountType {
+	case DiscountPercentage:
+		return price * p.DiscountValue / 100
+	case DiscountFixed:
+		return min(p.DiscountValue, price)
+	default:
+		return 0
+	}
+}
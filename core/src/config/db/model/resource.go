@@ -227,6 +227,97 @@ var ServiceResource = &Resource{
 	},
 }
 
+var WebhookResource = &Resource{
+	Name:        "webhook",
+	Description: "Webhook subscription resource",
+	Table:       (&WebhookSubscription{}).TableName(),
+	References: ResourceReferences{
+		SingleQueryRef(),
+		SinglePathRef(),
+		MultiplePathRef("webhook_id", "id"),
+		MultipleQueryRef("webhook_id", "id"),
+		MultipleBodyRef("webhook_id", "id"),
+	},
+}
+
+var PaymentResource = &Resource{
+	Name:        "payment",
+	Description: "Payment resource",
+	Table:       (&Payment{}).TableName(),
+	References: ResourceReferences{
+		SingleQueryRef(),
+		SinglePathRef(),
+		MultiplePathRef("payment_id", "id"),
+		MultipleQueryRef("payment_id", "id"),
+		MultipleBodyRef("payment_id", "id"),
+	},
+}
+
+var PromoCodeResource = &Resource{
+	Name:        "promo_code",
+	Description: "Promo code resource",
+	Table:       (&PromoCode{}).TableName(),
+	References: ResourceReferences{
+		SingleQueryRef(),
+		SinglePathRef(),
+		MultiplePathRef("promo_code_id", "id"),
+		MultipleQueryRef("promo_code_id", "id"),
+		MultipleBodyRef("promo_code_id", "id"),
+	},
+}
+
+var ScheduleTemplateResource = &Resource{
+	Name:        "schedule_template",
+	Description: "Schedule template resource",
+	Table:       (&ScheduleTemplate{}).TableName(),
+	References: ResourceReferences{
+		SingleQueryRef(),
+		SinglePathRef(),
+		MultiplePathRef("schedule_template_id", "id"),
+		MultipleQueryRef("schedule_template_id", "id"),
+		MultipleBodyRef("schedule_template_id", "id"),
+	},
+}
+
+var IntakeFormResource = &Resource{
+	Name:        "intake_form",
+	Description: "Intake form resource",
+	Table:       (&IntakeForm{}).TableName(),
+	References: ResourceReferences{
+		SingleQueryRef(),
+		SinglePathRef(),
+		MultiplePathRef("intake_form_id", "id"),
+		MultipleQueryRef("intake_form_id", "id"),
+		MultipleBodyRef("intake_form_id", "id"),
+	},
+}
+
+var ReviewResource = &Resource{
+	Name:        "review",
+	Description: "Review resource",
+	Table:       (&Review{}).TableName(),
+	References: ResourceReferences{
+		SingleQueryRef(),
+		SinglePathRef(),
+		MultiplePathRef("review_id", "id"),
+		MultipleQueryRef("review_id", "id"),
+		MultipleBodyRef("review_id", "id"),
+	},
+}
+
+var PackageResource = &Resource{
+	Name:        "package",
+	Description: "Package and membership resource",
+	Table:       (&ServicePackage{}).TableName(),
+	References: ResourceReferences{
+		SingleQueryRef(),
+		SinglePathRef(),
+		MultiplePathRef("package_id", "id"),
+		MultipleQueryRef("package_id", "id"),
+		MultipleBodyRef("package_id", "id"),
+	},
+}
+
 var AuthResource = &Resource{
 	Name:        "auth",
 	Description: "Auth resource",
@@ -251,6 +342,13 @@ var Resources = []*Resource{
 	SectorResource,
 	ServiceResource,
 	AuthResource,
+	WebhookResource,
+	PaymentResource,
+	PromoCodeResource,
+	PackageResource,
+	ScheduleTemplateResource,
+	IntakeFormResource,
+	ReviewResource,
 }
 
 // func SeedResources(db *gorm.DB) ([]*Resource, error) {
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"fmt"
+	"mynute-go/core/src/lib"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Status of a review.
+const (
+	ReviewInvited   = "INVITED"   // Emailed to the client after the appointment, not answered yet
+	ReviewPending   = "PENDING"   // Answered, waiting for moderation
+	ReviewPublished = "PUBLISHED" // Approved, counted in the ratings
+	ReviewRejected  = "REJECTED"  // Hidden by a manager
+)
+
+const (
+	MinRating         = 1
+	MaxRating         = 5
+	MaxReviewComment  = 2000
+	MaxModerationNote = 500
+)
+
+// Review is the feedback of a client on a fulfilled appointment. It is created invited when
+// the appointment is fulfilled, answered through the link emailed to the client and published
+// or rejected by a manager of the company.
+type Review struct {
+	BaseModel
+	CompanyID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	AppointmentID  uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"appointment_id"`
+	ClientID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"client_id"`
+	BranchID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"branch_id"`
+	EmployeeID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"employee_id"`
+	ServiceID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"service_id"`
+	Status         string     `gorm:"type:varchar(20);not null;default:'INVITED';index" json:"status"`
+	Token          *string    `gorm:"type:varchar(64);uniqueIndex" json:"-"` // Of the emailed link, only valid while invited
+	Rating         int        `gorm:"not null;default:0" json:"rating"`      // 1 to 5, 0 while invited
+	EmployeeRating *int       `json:"employee_rating"`                       // Optional rating of the employee alone
+	ServiceRating  *int       `json:"service_rating"`                        // Optional rating of the service alone
+	Comment        string     `gorm:"type:text" json:"comment"`
+	SubmittedAt    *time.Time `json:"submitted_at"`
+	// Moderation
+	ModeratedByID  *uuid.UUID `gorm:"type:uuid" json:"moderated_by_id"` // Employee who published or rejected the review
+	ModeratedAt    *time.Time `json:"moderated_at"`
+	ModerationNote string     `gorm:"type:varchar(500)" json:"moderation_note"` // Why it was rejected, not shown to the client
+}
+
+const ReviewTableName = "reviews"
+
+func (Review) TableName() string  { return ReviewTableName }
+func (Review) SchemaType() string { return "company" }
+
+// Validate checks the answer of the client.
+func (r *Review) Validate() error {
+	if r.Rating < MinRating || r.Rating > MaxRating {
+		return lib.Error.Review.Invalid.WithError(fmt.Errorf("rating must be between %d and %d", MinRating, MaxRating))
+	}
+	if r.EmployeeRating != nil && (*r.EmployeeRating < MinRating || *r.EmployeeRating > MaxRating) {
+		return lib.Error.Review.Invalid.WithError(fmt.Errorf("employee_rating must be between %d and %d", MinRating, MaxRating))
+	}
+	if r.ServiceRating != nil && (*r.ServiceRating < MinRating || *r.ServiceRating > MaxRating) {
+		return lib.Error.Review.Invalid.WithError(fmt.Errorf("service_rating must be between %d and %d", MinRating, MaxRating))
+	}
+	r.Comment = strings.TrimSpace(r.Comment)
+	if len([]rune(r.Comment)) > MaxReviewComment {
+		return lib.Error.Review.Invalid.WithError(fmt.Errorf("comment must have at most %d characters", MaxReviewComment))
+	}
+	return nil
+}
+
+func (r *Review) BeforeUpdate(tx *gorm.DB) error {
+	if tx.Statement.Changed("AppointmentID") || tx.Statement.Changed("EmployeeID") || tx.Statement.Changed("ServiceID") || tx.Statement.Changed("BranchID") {
+		return lib.Error.Review.Invalid.WithError(fmt.Errorf("the appointment of a review can not be changed"))
+	}
+	return nil
+}
+
+// RatingSummary is the aggregate of the published reviews of an employee, service or branch.
+// It is only written by RefreshRatings, never through the API.
+type RatingSummary struct {
+	Average float64 `gorm:"not null;default:0;<-:false" json:"average"` // Rounded to 2 decimals, 0 without reviews
+	Count   int64   `gorm:"not null;default:0;<-:false" json:"count"`
+}
+
+// RefreshRatings recomputes the rating summaries of the employee, service and branch of the
+// review from its published reviews. Employee and service use their own rating when given,
+// the overall rating otherwise.
+func RefreshRatings(tx *gorm.DB, r *Review) error {
+	refresh := []struct {
+		table, column, rating string
+		id                    uuid.UUID
+	}{
+		{"employees", "employee_id", "COALESCE(employee_rating, rating)", r.EmployeeID},
+		{"services", "service_id", "COALESCE(service_rating, rating)", r.ServiceID},
+		{"branches", "branch_id", "rating", r.BranchID},
+	}
+	for _, t := range refresh {
+		query := fmt.Sprintf(`UPDATE %s SET
+			rating_average = COALESCE((SELECT ROUND(AVG(%s)::numeric, 2) FROM reviews WHERE %s = ? AND status = ? AND deleted_at IS NULL), 0),
+			rating_count = (SELECT COUNT(*) FROM reviews WHERE %s = ? AND status = ? AND deleted_at IS NULL)
+			WHERE id = ?`, t.table, t.rating, t.column, t.column)
+		if err := tx.Exec(query, t.id, ReviewPublished, t.id, ReviewPublished, t.id).Error; err != nil {
+			return fmt.Errorf("failed to refresh the rating of %s %s: %w", t.table, t.id, err)
+		}
+	}
+	return nil
+}
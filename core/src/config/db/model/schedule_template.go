@@ -0,0 +1,276 @@
+package model
+
+import (
+	"fmt"
+	"mynute-go/core/src/lib"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ScheduleTemplate is a named week of work ranges of a company, applied at once to
+// employees or branches. Its times are local to the branch it is applied at.
+type ScheduleTemplate struct {
+	BaseModel
+	CompanyID   uuid.UUID               `gorm:"type:uuid;not null;index" json:"company_id"`
+	Name        string                  `gorm:"type:varchar(100);not null" json:"name"`
+	Description string                  `gorm:"type:text" json:"description"`
+	Ranges      []ScheduleTemplateRange `gorm:"foreignKey:TemplateID;constraint:OnDelete:CASCADE;" json:"ranges"`
+}
+
+const ScheduleTemplateTableName = "schedule_templates"
+
+func (ScheduleTemplate) TableName() string  { return ScheduleTemplateTableName }
+func (ScheduleTemplate) SchemaType() string { return "company" }
+func (ScheduleTemplate) Indexes() map[string]string {
+	return map[string]string{
+		"idx_schedule_template_company_name": fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_schedule_template_company_name ON %s (company_id, name) WHERE deleted_at IS NULL", ScheduleTemplateTableName),
+	}
+}
+
+// ScheduleTemplateRange is a work range of a template. Ranges ending at or before their
+// start span midnight, like work ranges.
+type ScheduleTemplateRange struct {
+	BaseModel
+	TemplateID uuid.UUID    `gorm:"type:uuid;not null;index" json:"template_id"`
+	Weekday    time.Weekday `gorm:"not null" json:"weekday"`
+	StartTime  string       `gorm:"type:varchar(5);not null" json:"start_time"` // HH:MM
+	EndTime    string       `gorm:"type:varchar(5);not null" json:"end_time"`   // HH:MM
+}
+
+const ScheduleTemplateRangeTableName = "schedule_template_ranges"
+
+func (ScheduleTemplateRange) TableName() string  { return ScheduleTemplateRangeTableName }
+func (ScheduleTemplateRange) SchemaType() string { return "company" }
+
+func (t *ScheduleTemplate) Validate() error {
+	if len(t.Name) < 3 || len(t.Name) > 100 {
+		return lib.Error.ScheduleTemplate.Invalid.WithError(fmt.Errorf("name must have from 3 to 100 characters"))
+	}
+	if len(t.Ranges) == 0 {
+		return lib.Error.ScheduleTemplate.Invalid.WithError(fmt.Errorf("a template needs at least one work range"))
+	}
+	for i, r := range t.Ranges {
+		if r.Weekday < time.Sunday || r.Weekday > time.Saturday {
+			return lib.Error.ScheduleTemplate.Invalid.WithError(fmt.Errorf("range [%d] has invalid weekday %d", i+1, r.Weekday))
+		}
+		start, err := time.Parse("15:04", r.StartTime)
+		if err != nil {
+			return lib.Error.ScheduleTemplate.Invalid.WithError(fmt.Errorf("range [%d] start_time must be in the HH:MM format", i+1))
+		}
+		end, err := time.Parse("15:04", r.EndTime)
+		if err != nil {
+			return lib.Error.ScheduleTemplate.Invalid.WithError(fmt.Errorf("range [%d] end_time must be in the HH:MM format", i+1))
+		}
+		if start.Equal(end) {
+			return lib.Error.ScheduleTemplate.Invalid.WithError(fmt.Errorf("range [%d] start time cannot be equal to end time", i+1))
+		}
+	}
+	return nil
+}
+
+func (t *ScheduleTemplate) BeforeCreate(tx *gorm.DB) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	return t.checkName(tx)
+}
+
+func (t *ScheduleTemplate) BeforeUpdate(tx *gorm.DB) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	return t.checkName(tx)
+}
+
+// checkName fails when another template of the company has the name.
+func (t *ScheduleTemplate) checkName(tx *gorm.DB) error {
+	var count int64
+	if err := tx.Model(&ScheduleTemplate{}).Where("company_id = ? AND name = ? AND id != ?", t.CompanyID, t.Name, t.ID).Count(&count).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error checking existing schedule template: %w", err))
+	}
+	if count > 0 {
+		return lib.Error.ScheduleTemplate.AlreadyExists
+	}
+	return nil
+}
+
+// ReplaceRanges swaps the ranges of the template for ranges.
+func (t *ScheduleTemplate) ReplaceRanges(tx *gorm.DB, ranges []ScheduleTemplateRange) error {
+	t.Ranges = ranges
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("template_id = ?", t.ID).Delete(&ScheduleTemplateRange{}).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
+	}
+	for i := range t.Ranges {
+		t.Ranges[i].ID = uuid.Nil
+		t.Ranges[i].TemplateID = t.ID
+	}
+	if err := tx.Create(&t.Ranges).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
+	}
+	return nil
+}
+
+// branchWorkRangeBase builds the work range of a template range at the branch.
+func (r ScheduleTemplateRange) branchWorkRangeBase(branch *Branch) (WorkRangeBase, error) {
+	start, err := lib.Parse_HHMM_To_Time(r.StartTime, branch.TimeZone)
+	if err != nil {
+		return WorkRangeBase{}, lib.Error.ScheduleTemplate.Invalid.WithError(fmt.Errorf("invalid start_time: %w", err))
+	}
+	end, err := lib.Parse_HHMM_To_Time(r.EndTime, branch.TimeZone)
+	if err != nil {
+		return WorkRangeBase{}, lib.Error.ScheduleTemplate.Invalid.WithError(fmt.Errorf("invalid end_time: %w", err))
+	}
+	return WorkRangeBase{Weekday: r.Weekday, StartTime: start, EndTime: end, TimeZone: branch.TimeZone, BranchID: branch.ID}, nil
+}
+
+// BranchRanges builds the opening hours of the template at the branch, offering every
+// service of the branch.
+func (t *ScheduleTemplate) BranchRanges(tx *gorm.DB, branchID uuid.UUID) ([]BranchWorkRange, error) {
+	var branch Branch
+	if err := tx.Preload("Services").Where("id = ?", branchID).First(&branch).Error; err != nil {
+		return nil, lib.Error.Branch.NotFound.WithError(err)
+	}
+	ranges := make([]BranchWorkRange, 0, len(t.Ranges))
+	for _, r := range t.Ranges {
+		base, err := r.branchWorkRangeBase(&branch)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, BranchWorkRange{WorkRangeBase: base, Services: branch.Services})
+	}
+	return ranges, nil
+}
+
+// EmployeeRanges builds the work ranges of the template for the employee at the branch,
+// offering the services of the employee the branch offers too.
+func (t *ScheduleTemplate) EmployeeRanges(tx *gorm.DB, employeeID, branchID uuid.UUID) ([]EmployeeWorkRange, error) {
+	var branch Branch
+	if err := tx.Where("id = ?", branchID).First(&branch).Error; err != nil {
+		return nil, lib.Error.Branch.NotFound.WithError(err)
+	}
+	services, err := SharedServices(tx, employeeID, branchID)
+	if err != nil {
+		return nil, err
+	}
+	ranges := make([]EmployeeWorkRange, 0, len(t.Ranges))
+	for _, r := range t.Ranges {
+		base, err := r.branchWorkRangeBase(&branch)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, EmployeeWorkRange{WorkRangeBase: base, EmployeeID: employeeID, Services: services})
+	}
+	return ranges, nil
+}
+
+// SharedServices lists the services the employee offers that the branch offers too.
+func SharedServices(tx *gorm.DB, employeeID, branchID uuid.UUID) ([]*Service, error) {
+	var services []*Service
+	if err := tx.
+		Joins("JOIN employee_services es ON es.service_id = services.id AND es.employee_id = ?", employeeID).
+		Joins("JOIN branch_services bs ON bs.service_id = services.id AND bs.branch_id = ?", branchID).
+		Find(&services).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading shared services: %w", err))
+	}
+	return services, nil
+}
+
+// CopyEmployeeRanges builds for the employee the work ranges of another one, at every
+// branch or only at branchID when it is set. Ranges keep the services both offer.
+func CopyEmployeeRanges(tx *gorm.DB, fromEmployeeID, toEmployeeID, branchID uuid.UUID) ([]EmployeeWorkRange, error) {
+	query := tx.Preload("Services").Where("employee_id = ?", fromEmployeeID)
+	if branchID != uuid.Nil {
+		query = query.Where("branch_id = ?", branchID)
+	}
+	var source []EmployeeWorkRange
+	if err := query.Order("weekday, start_time").Find(&source).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading work schedule: %w", err))
+	}
+
+	var own []uuid.UUID
+	if err := tx.Table("employee_services").Where("employee_id = ?", toEmployeeID).Pluck("service_id", &own).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading employee services: %w", err))
+	}
+	offered := make(map[uuid.UUID]bool, len(own))
+	for _, id := range own {
+		offered[id] = true
+	}
+
+	ranges := make([]EmployeeWorkRange, 0, len(source))
+	for _, wr := range source {
+		var services []*Service
+		for _, s := range wr.Services {
+			if offered[s.ID] {
+				services = append(services, s)
+			}
+		}
+		base := wr.WorkRangeBase
+		base.BaseModel = BaseModel{}
+		base.Branch = Branch{}
+		ranges = append(ranges, EmployeeWorkRange{WorkRangeBase: base, EmployeeID: toEmployeeID, Services: services})
+	}
+	return ranges, nil
+}
+
+// ReplaceEmployeeSchedule creates the work ranges for the employee, validated like any
+// other. With replace, the ranges of the employee at the branches of ranges are removed
+// first. It returns the removed ranges.
+func ReplaceEmployeeSchedule(tx *gorm.DB, employeeID uuid.UUID, ranges []EmployeeWorkRange, replace bool) ([]EmployeeWorkRange, error) {
+	var removed []EmployeeWorkRange
+	if replace {
+		branchIDs := map[uuid.UUID]bool{}
+		for _, wr := range ranges {
+			branchIDs[wr.BranchID] = true
+		}
+		for branchID := range branchIDs {
+			var existing []EmployeeWorkRange
+			if err := tx.Where("employee_id = ? AND branch_id = ?", employeeID, branchID).Find(&existing).Error; err != nil {
+				return nil, lib.Error.General.InternalError.WithError(err)
+			}
+			for i := range existing {
+				if err := tx.Delete(&existing[i]).Error; err != nil {
+					return nil, lib.Error.General.DeletedError.WithError(err)
+				}
+			}
+			removed = append(removed, existing...)
+		}
+	}
+	for i := range ranges {
+		if err := tx.Create(&ranges[i]).Error; err != nil {
+			return nil, rangeError(ranges[i].WorkRangeBase, err)
+		}
+	}
+	return removed, nil
+}
+
+// ReplaceBranchSchedule is ReplaceEmployeeSchedule for the opening hours of a branch.
+func ReplaceBranchSchedule(tx *gorm.DB, branchID uuid.UUID, ranges []BranchWorkRange, replace bool) ([]BranchWorkRange, error) {
+	var removed []BranchWorkRange
+	if replace {
+		if err := tx.Where("branch_id = ?", branchID).Find(&removed).Error; err != nil {
+			return nil, lib.Error.General.InternalError.WithError(err)
+		}
+		for i := range removed {
+			if err := tx.Delete(&removed[i]).Error; err != nil {
+				return nil, lib.Error.General.DeletedError.WithError(err)
+			}
+		}
+	}
+	for i := range ranges {
+		if err := tx.Create(&ranges[i]).Error; err != nil {
+			return nil, rangeError(ranges[i].WorkRangeBase, err)
+		}
+	}
+	return removed, nil
+}
+
+// rangeError tells which range of a schedule failed, keeping the error of its validation.
+func rangeError(wr WorkRangeBase, err error) error {
+	label := fmt.Errorf("work range %s %s-%s", wr.Weekday, wr.StartTime.Format("15:04"), wr.EndTime.Format("15:04"))
+	return lib.Error.ScheduleTemplate.ApplyFailed.WithError(label).WithError(err)
+}
@@ -2,8 +2,10 @@ package model
 
 import (
 	"errors"
+	"fmt"
 	mJSON "mynute-go/core/src/config/db/model/json"
 	"mynute-go/core/src/lib"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -22,6 +24,107 @@ type Service struct {
 	Employees   []*Employee        `gorm:"many2many:employee_services;constraint:OnDelete:CASCADE;" json:"employees"` // Many-to-many relation with Employee
 	Branches    []*Branch          `gorm:"many2many:branch_services;constraint:OnDelete:CASCADE;" json:"branches"`    // Many-to-many relation with Branch
 	Design      mJSON.DesignConfig `gorm:"type:jsonb" json:"design"`
+	// How the employee is picked when the client books any employee
+	EmployeeAssignment string `gorm:"type:varchar(20);not null;default:'LEAST_BOOKED'" json:"employee_assignment"`
+	ServicePayment
+	BookingWindow
+	SlotRules
+	Rating RatingSummary `gorm:"embedded;embeddedPrefix:rating_" json:"rating"` // Of the published reviews
+}
+
+// Employee assignment strategies of a service.
+const (
+	AssignLeastBooked = "LEAST_BOOKED" // The employee with the fewest appointments that day
+	AssignRoundRobin  = "ROUND_ROBIN"  // The employee assigned the longest ago for the service
+	AssignPreferred   = "PREFERRED"    // The employee who last served the client, then the least booked
+)
+
+// ValidateAssignment checks the employee assignment strategy of the service.
+func (s *Service) ValidateAssignment() error {
+	switch s.EmployeeAssignment {
+	case "", AssignLeastBooked, AssignRoundRobin, AssignPreferred:
+		return nil
+	}
+	return lib.Error.Service.InvalidAssignment.WithError(fmt.Errorf("unknown employee assignment %q", s.EmployeeAssignment))
+}
+
+// Prepayment types of a service.
+const (
+	PrepaymentNone    = "NONE"    // Nothing is charged when booking
+	PrepaymentDeposit = "DEPOSIT" // DepositAmount is charged when booking
+	PrepaymentFull    = "FULL"    // The whole price is charged when booking
+)
+
+// ServicePayment is how a service is paid and refunded.
+type ServicePayment struct {
+	PrepaymentType string `gorm:"type:varchar(20);not null;default:'NONE'" json:"prepayment_type"`
+	DepositAmount  int64  `gorm:"not null;default:0" json:"deposit_amount"`
+	// Cancellation policy: cancelling at least FreeCancellationHours before the start
+	// refunds everything paid, later cancellations refund LateCancellationRefundPercent of it.
+	FreeCancellationHours         uint16 `gorm:"not null;default:24" json:"free_cancellation_hours"`
+	LateCancellationRefundPercent uint8  `gorm:"not null;default:0" json:"late_cancellation_refund_percent"`
+}
+
+// PrepaymentAmount returns how much must be paid when booking the service and as which payment type.
+// ok is false when nothing has to be paid upfront.
+func (s *Service) PrepaymentAmount() (amount int64, paymentType PaymentType, ok bool) {
+	switch s.PrepaymentType {
+	case PrepaymentDeposit:
+		// Overrides may lower the price below the deposit
+		amount := min(s.DepositAmount, s.Price)
+		return amount, PaymentTypeDeposit, amount > 0
+	case PrepaymentFull:
+		return s.Price, PaymentTypeFull, s.Price > 0
+	default:
+		return 0, "", false
+	}
+}
+
+// RefundAmount returns how much of paid goes back to the client when the
+// appointment starting at start is cancelled at cancelledAt.
+func (s *Service) RefundAmount(paid int64, start, cancelledAt time.Time) int64 {
+	if paid <= 0 {
+		return 0
+	}
+	if s.FreeCancellation(start, cancelledAt) {
+		return paid
+	}
+	return paid * int64(s.LateCancellationRefundPercent) / 100
+}
+
+// FreeCancellation reports whether cancelling the appointment starting at start
+// at cancelledAt is within the free cancellation window of the service.
+func (s *Service) FreeCancellation(start, cancelledAt time.Time) bool {
+	return start.Sub(cancelledAt) >= time.Duration(s.FreeCancellationHours)*time.Hour
+}
+
+func (s *Service) ValidatePayment() error {
+	switch s.PrepaymentType {
+	case "", PrepaymentNone, PrepaymentFull:
+	case PrepaymentDeposit:
+		if s.DepositAmount <= 0 || s.DepositAmount > s.Price {
+			return lib.Error.Payment.InvalidPrepayment.WithError(fmt.Errorf("deposit amount must be between 1 and the service price (%d)", s.Price))
+		}
+	default:
+		return lib.Error.Payment.InvalidPrepayment.WithError(fmt.Errorf("unknown prepayment type %q", s.PrepaymentType))
+	}
+	if s.LateCancellationRefundPercent > 100 {
+		return lib.Error.Payment.InvalidPrepayment.WithError(fmt.Errorf("late cancellation refund percent must be at most 100"))
+	}
+	return nil
+}
+
+func (s *Service) BeforeCreate(tx *gorm.DB) (err error) {
+	if err := s.ValidateAssignment(); err != nil {
+		return err
+	}
+	if err := s.BookingWindow.Validate(); err != nil {
+		return err
+	}
+	if err := s.SlotRules.Validate(); err != nil {
+		return err
+	}
+	return s.ValidatePayment()
 }
 
 func (Service) TableName() string  { return "services" }
@@ -32,5 +135,37 @@ func (s *Service) BeforeUpdate(tx *gorm.DB) (err error) {
 	if tx.Statement.Changed("CompanyID") {
 		return lib.Error.General.UpdatedError.WithError(errors.New("the CompanyID cannot be changed after creation"))
 	}
+	if err := s.ValidateAssignment(); err != nil {
+		return err
+	}
+	if err := s.BookingWindow.Validate(); err != nil {
+		return err
+	}
+	if err := s.SlotRules.Validate(); err != nil {
+		return err
+	}
+	if tx.Statement.Changed("PrepaymentType", "DepositAmount", "Price", "LateCancellationRefundPercent") {
+		var current Service
+		if err := tx.First(&current, "id = ?", s.ID).Error; err != nil {
+			return lib.Error.General.UpdatedError.WithError(err)
+		}
+		// Updates only carry the non-zero fields, validate them against the stored ones.
+		merged := current
+		if s.Price != 0 {
+			merged.Price = s.Price
+		}
+		if s.PrepaymentType != "" {
+			merged.PrepaymentType = s.PrepaymentType
+		}
+		if s.DepositAmount != 0 {
+			merged.DepositAmount = s.DepositAmount
+		}
+		if s.LateCancellationRefundPercent != 0 {
+			merged.LateCancellationRefundPercent = s.LateCancellationRefundPercent
+		}
+		if err := merged.ValidatePayment(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
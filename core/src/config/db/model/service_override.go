@@ -0,0 +1,159 @@
+package model
+
+import (
+	"fmt"
+	"mynute-go/core/src/lib"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ServiceOverride changes the price and/or duration of a service when it is
+// performed by an employee, at a branch, or by an employee at a branch.
+// The most specific override wins, for the price and the duration separately:
+// employee+branch, then employee, then branch, then the service itself.
+type ServiceOverride struct {
+	BaseModel
+	ServiceID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"service_id"`
+	Service    *Service   `gorm:"foreignKey:ServiceID;references:ID;constraint:OnDelete:CASCADE;" json:"-"`
+	EmployeeID *uuid.UUID `gorm:"type:uuid;index" json:"employee_id"`
+	Employee   *Employee  `gorm:"foreignKey:EmployeeID;references:ID;constraint:OnDelete:CASCADE;" json:"-"`
+	BranchID   *uuid.UUID `gorm:"type:uuid;index" json:"branch_id"`
+	Branch     *Branch    `gorm:"foreignKey:BranchID;references:ID;constraint:OnDelete:CASCADE;" json:"-"`
+	Price      *int64     `json:"price"`    // nil keeps the price of the less specific level
+	Duration   *uint16    `json:"duration"` // In minutes, nil keeps the duration of the less specific level
+}
+
+const ServiceOverrideTableName = "service_overrides"
+
+func (ServiceOverride) TableName() string  { return ServiceOverrideTableName }
+func (ServiceOverride) SchemaType() string { return "tenant" }
+func (ServiceOverride) Indexes() map[string]string {
+	return ServiceOverrideIndexes(ServiceOverrideTableName)
+}
+
+func ServiceOverrideIndexes(table string) map[string]string {
+	return map[string]string{
+		"idx_service_override_target": fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_service_override_target ON %s (service_id, COALESCE(employee_id, '00000000-0000-0000-0000-000000000000'), COALESCE(branch_id, '00000000-0000-0000-0000-000000000000')) WHERE deleted_at IS NULL", table),
+	}
+}
+
+func (o *ServiceOverride) Validate() error {
+	if o.ServiceID == uuid.Nil {
+		return lib.Error.Service.InvalidOverride.WithError(fmt.Errorf("service_id is required"))
+	}
+	if o.EmployeeID == nil && o.BranchID == nil {
+		return lib.Error.Service.InvalidOverride.WithError(fmt.Errorf("employee_id or branch_id is required"))
+	}
+	if o.Price == nil && o.Duration == nil {
+		return lib.Error.Service.InvalidOverride.WithError(fmt.Errorf("price or duration is required"))
+	}
+	if o.Price != nil && *o.Price < 0 {
+		return lib.Error.Service.InvalidOverride.WithError(fmt.Errorf("price must not be negative"))
+	}
+	if o.Duration != nil && *o.Duration == 0 {
+		return lib.Error.Service.InvalidOverride.WithError(fmt.Errorf("duration must be positive"))
+	}
+	return nil
+}
+
+func (o *ServiceOverride) BeforeCreate(tx *gorm.DB) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	var count int64
+	if o.EmployeeID != nil {
+		tx.Table("employee_services").Where("employee_id = ? AND service_id = ?", *o.EmployeeID, o.ServiceID).Count(&count)
+		if count == 0 {
+			return lib.Error.Employee.ServiceDoesNotBelong
+		}
+	}
+	if o.BranchID != nil {
+		count = 0
+		tx.Table("branch_services").Where("branch_id = ? AND service_id = ?", *o.BranchID, o.ServiceID).Count(&count)
+		if count == 0 {
+			return lib.Error.Branch.ServiceDoesNotBelong
+		}
+	}
+
+	count = 0
+	query := tx.Model(&ServiceOverride{}).Where("service_id = ?", o.ServiceID)
+	if o.EmployeeID == nil {
+		query = query.Where("employee_id IS NULL")
+	} else {
+		query = query.Where("employee_id = ?", *o.EmployeeID)
+	}
+	if o.BranchID == nil {
+		query = query.Where("branch_id IS NULL")
+	} else {
+		query = query.Where("branch_id = ?", *o.BranchID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error checking existing service override: %w", err))
+	}
+	if count > 0 {
+		return lib.Error.Service.InvalidOverride.WithError(fmt.Errorf("an override for this employee and branch already exists"))
+	}
+	return nil
+}
+
+// ServiceTerms is the price and duration a service is booked with.
+type ServiceTerms struct {
+	Price    int64
+	Duration uint16 // In minutes
+}
+
+// specificity ranks how closely the override matches, -1 when it does not apply.
+func (o *ServiceOverride) specificity(employeeID, branchID uuid.UUID) int {
+	if o.EmployeeID != nil && *o.EmployeeID != employeeID {
+		return -1
+	}
+	if o.BranchID != nil && *o.BranchID != branchID {
+		return -1
+	}
+	switch {
+	case o.EmployeeID != nil && o.BranchID != nil:
+		return 3
+	case o.EmployeeID != nil:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// TermsFor resolves the price and duration of the service for the employee at the branch.
+// overrides may contain overrides of other employees and branches, they are skipped.
+func (s *Service) TermsFor(overrides []ServiceOverride, employeeID, branchID uuid.UUID) ServiceTerms {
+	terms := ServiceTerms{Price: s.Price, Duration: s.Duration}
+	priceLevel, durationLevel := 0, 0
+	for i := range overrides {
+		o := &overrides[i]
+		if o.ServiceID != s.ID {
+			continue
+		}
+		level := o.specificity(employeeID, branchID)
+		if level < 0 {
+			continue
+		}
+		if o.Price != nil && level > priceLevel {
+			terms.Price, priceLevel = *o.Price, level
+		}
+		if o.Duration != nil && level > durationLevel {
+			terms.Duration, durationLevel = *o.Duration, level
+		}
+	}
+	return terms
+}
+
+// LoadTerms loads the overrides that may apply and resolves the terms of the service
+// for the employee at the branch.
+func (s *Service) LoadTerms(tx *gorm.DB, employeeID, branchID uuid.UUID) (ServiceTerms, error) {
+	var overrides []ServiceOverride
+	if err := tx.Where("service_id = ?", s.ID).
+		Where("employee_id IS NULL OR employee_id = ?", employeeID).
+		Where("branch_id IS NULL OR branch_id = ?", branchID).
+		Find(&overrides).Error; err != nil {
+		return ServiceTerms{}, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading service overrides: %w", err))
+	}
+	return s.TermsFor(overrides, employeeID, branchID), nil
+}
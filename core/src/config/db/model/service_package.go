@@ -0,0 +1,136 @@
+package model
+
+import (
+	"fmt"
+	"mynute-go/core/src/lib"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Types of service packages.
+const (
+	PackageTypePackage    = "PACKAGE"    // Grants a number of credits, e.g. "10 massages"
+	PackageTypeMembership = "MEMBERSHIP" // Grants unlimited credits while valid, e.g. a monthly plan
+)
+
+// ServicePackage is a package or membership a company sells. Each credit pays
+// one appointment of the services it covers.
+type ServicePackage struct {
+	BaseModel
+	CompanyID    uuid.UUID `gorm:"type:uuid;not null;index" json:"company_id"`
+	Name         string    `gorm:"type:varchar(100);not null" json:"name"`
+	Description  string    `gorm:"type:text" json:"description"`
+	Type         string    `gorm:"type:varchar(20);not null" json:"type"`
+	Price        int64     `gorm:"not null;default:0" json:"price"`
+	Currency     string    `gorm:"type:varchar(3);default:'BRL'" json:"currency"`
+	Credits      int64     `gorm:"not null;default:0" json:"credits"`      // Credits granted by a PACKAGE, memberships are unlimited
+	ValidityDays int       `gorm:"not null" json:"validity_days"`          // Days the credits can be used after the purchase
+	ServiceIDs   UUIDList  `gorm:"type:jsonb" json:"service_ids"`          // Services covered, empty covers all
+	IsActive     bool      `gorm:"not null;default:true" json:"is_active"` // Inactive packages are no longer sold
+}
+
+const ServicePackageTableName = "service_packages"
+
+func (ServicePackage) TableName() string  { return ServicePackageTableName }
+func (ServicePackage) SchemaType() string { return "company" }
+
+func (p *ServicePackage) BeforeCreate(tx *gorm.DB) error {
+	return p.Validate()
+}
+
+func (p *ServicePackage) BeforeUpdate(tx *gorm.DB) error {
+	if tx.Statement.Changed("CompanyID") {
+		return lib.Error.Company.IdUpdateForbidden
+	}
+	return nil
+}
+
+func (p *ServicePackage) Validate() error {
+	if p.CompanyID == uuid.Nil {
+		return lib.Error.Package.Invalid.WithError(fmt.Errorf("company_id is required"))
+	}
+	if len(p.Name) < 3 || len(p.Name) > 100 {
+		return lib.Error.Package.Invalid.WithError(fmt.Errorf("name must have 3 to 100 characters"))
+	}
+	switch p.Type {
+	case PackageTypePackage:
+		if p.Credits <= 0 {
+			return lib.Error.Package.Invalid.WithError(fmt.Errorf("a package must grant at least one credit"))
+		}
+	case PackageTypeMembership:
+		if p.Credits != 0 {
+			return lib.Error.Package.Invalid.WithError(fmt.Errorf("memberships grant unlimited credits, credits must be 0"))
+		}
+	default:
+		return lib.Error.Package.Invalid.WithError(fmt.Errorf("type must be %s or %s", PackageTypePackage, PackageTypeMembership))
+	}
+	if p.ValidityDays <= 0 {
+		return lib.Error.Package.Invalid.WithError(fmt.Errorf("validity_days must be positive"))
+	}
+	if p.Price < 0 {
+		return lib.Error.Package.Invalid.WithError(fmt.Errorf("price must not be negative"))
+	}
+	return nil
+}
+
+// Sell grants the package to the client from purchasedAt. The terms of the
+// package are copied, later changes to the package do not affect sold ones.
+func (p *ServicePackage) Sell(clientID uuid.UUID, purchasedAt time.Time) *ClientPackage {
+	return &ClientPackage{
+		CompanyID:   p.CompanyID,
+		ClientID:    clientID,
+		PackageID:   p.ID,
+		Name:        p.Name,
+		Type:        p.Type,
+		Price:       p.Price,
+		Currency:    p.Currency,
+		Credits:     p.Credits,
+		ServiceIDs:  p.ServiceIDs,
+		PurchasedAt: purchasedAt,
+		ExpiresAt:   purchasedAt.AddDate(0, 0, p.ValidityDays),
+	}
+}
+
+// ClientPackage is a package or membership bought by a client, with its credit balance.
+type ClientPackage struct {
+	BaseModel
+	CompanyID   uuid.UUID       `gorm:"type:uuid;not null;index" json:"company_id"`
+	ClientID    uuid.UUID       `gorm:"type:uuid;not null;index" json:"client_id"`
+	PackageID   uuid.UUID       `gorm:"type:uuid;not null;index" json:"package_id"`
+	Package     *ServicePackage `gorm:"foreignKey:PackageID;references:ID;constraint:OnDelete:RESTRICT;" json:"-"`
+	Name        string          `gorm:"type:varchar(100);not null" json:"name"`
+	Type        string          `gorm:"type:varchar(20);not null" json:"type"`
+	Price       int64           `gorm:"not null;default:0" json:"price"`
+	Currency    string          `gorm:"type:varchar(3);default:'BRL'" json:"currency"`
+	Credits     int64           `gorm:"not null;default:0" json:"credits"` // 0 for memberships, which are unlimited
+	CreditsUsed int64           `gorm:"not null;default:0" json:"credits_used"`
+	ServiceIDs  UUIDList        `gorm:"type:jsonb" json:"service_ids"`
+	PurchasedAt time.Time       `gorm:"not null" json:"purchased_at"`
+	ExpiresAt   time.Time       `gorm:"not null;index" json:"expires_at"`
+	PaymentID   *uuid.UUID      `gorm:"type:uuid;index" json:"payment_id"` // Payment of the sale, nil for free packages
+}
+
+const ClientPackageTableName = "client_packages"
+
+func (ClientPackage) TableName() string  { return ClientPackageTableName }
+func (ClientPackage) SchemaType() string { return "company" }
+
+// Unlimited reports whether the credits are unlimited, as for memberships.
+func (p *ClientPackage) Unlimited() bool {
+	return p.Type == PackageTypeMembership
+}
+
+// Remaining returns the credits left, -1 when unlimited.
+func (p *ClientPackage) Remaining() int64 {
+	if p.Unlimited() {
+		return -1
+	}
+	return max(p.Credits-p.CreditsUsed, 0)
+}
+
+// Covers reports whether a credit can pay the service for an appointment starting at start.
+func (p *ClientPackage) Covers(serviceID uuid.UUID, start time.Time) bool {
+	return start.Before(p.ExpiresAt) && p.Remaining() != 0 && p.ServiceIDs.AllowsAny(serviceID)
+}
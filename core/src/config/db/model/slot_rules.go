@@ -0,0 +1,159 @@
+package model
+
+import (
+	"fmt"
+	"mynute-go/core/src/lib"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Slot alignments of a service.
+const (
+	AlignMidnight   = "MIDNIGHT"    // Starts are multiples of the interval since midnight
+	AlignShiftStart = "SHIFT_START" // Starts are multiples of the interval since the start of the work range
+)
+
+// SlotRules is how the start times of a service are laid out in the work ranges of its employees.
+type SlotRules struct {
+	SlotInterval  uint16 `gorm:"not null;default:0" json:"slot_interval"`                            // Minutes between starts, the slot interval of the employee when 0
+	SlotAlignment string `gorm:"type:varchar(20);not null;default:'MIDNIGHT'" json:"slot_alignment"` // MIDNIGHT or SHIFT_START
+	OptimizeGaps  bool   `gorm:"not null;default:false" json:"optimize_gaps"`                        // Prefer starts next to appointments and the edges of the work range
+}
+
+func (r SlotRules) Validate() error {
+	switch r.SlotAlignment {
+	case "", AlignMidnight, AlignShiftStart:
+	default:
+		return lib.Error.Service.InvalidSlotRules.WithError(fmt.Errorf("unknown slot alignment %q", r.SlotAlignment))
+	}
+	if r.SlotInterval > 24*60 {
+		return lib.Error.Service.InvalidSlotRules.WithError(fmt.Errorf("slot interval must be at most a day"))
+	}
+	return nil
+}
+
+// Custom reports whether the service lays out its starts itself. Bookings are only
+// checked against custom rules, the slot interval of employees is a display default.
+func (r SlotRules) Custom() bool {
+	return r.SlotInterval > 0 || r.SlotAlignment == AlignShiftStart || r.OptimizeGaps
+}
+
+// Step returns the time between starts with the employee.
+func (r SlotRules) Step(employee *Employee) time.Duration {
+	if r.SlotInterval > 0 {
+		return time.Duration(r.SlotInterval) * time.Minute
+	}
+	return time.Duration(employee.SlotTimeDiff) * time.Minute
+}
+
+// TimeSpan is a time an employee is busy, [Start, End).
+type TimeSpan struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Shift is a work range on a day, with the appointments of its employee.
+type Shift struct {
+	Start    time.Time // Midnight alignment counts from the midnight of its time zone
+	End      time.Time
+	Busy     []TimeSpan
+	Capacity uint32                          // Appointments the employee takes at once
+	Free     func(start, end time.Time) bool // Further check of a slot, nil for none
+}
+
+func (s Shift) touches(start, end time.Time) bool {
+	if start.Equal(s.Start) || end.Equal(s.End) {
+		return true
+	}
+	return slices.ContainsFunc(s.Busy, func(b TimeSpan) bool { return b.End.Equal(start) || b.Start.Equal(end) })
+}
+
+// Starts lists the free starts of a slot lasting d in the shift, step apart following the
+// alignment. A slot is free while the appointments overlapping it are fewer than the capacity.
+// With OptimizeGaps only the starts touching an edge of the shift or an appointment are
+// kept, unless none of them is free.
+func (r SlotRules) Starts(shift Shift, step, d time.Duration) []time.Time {
+	if step <= 0 {
+		return nil
+	}
+	first := shift.Start
+	if r.SlotAlignment != AlignShiftStart {
+		midnight := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, first.Location())
+		if remainder := first.Sub(midnight) % step; remainder != 0 {
+			first = first.Add(step - remainder)
+		}
+	}
+
+	var starts, touching []time.Time
+	for start := first; start.Before(shift.End); start = start.Add(step) {
+		end := start.Add(d)
+		if end.After(shift.End) {
+			break
+		}
+		var overlapping uint32
+		for _, b := range shift.Busy {
+			if start.Before(b.End) && end.After(b.Start) {
+				overlapping++
+			}
+		}
+		if overlapping >= shift.Capacity || (shift.Free != nil && !shift.Free(start, end)) {
+			continue
+		}
+		starts = append(starts, start)
+		if r.OptimizeGaps && shift.touches(start, end) {
+			touching = append(touching, start)
+		}
+	}
+	if len(touching) > 0 {
+		return touching
+	}
+	return starts
+}
+
+// CheckSlotRules fails when the service does not offer the start of the appointment in
+// the work ranges of its employee, as the availability lists it.
+func (a *Appointment) CheckSlotRules(tx *gorm.DB, service *Service) error {
+	rules := service.SlotRules
+	if !rules.Custom() {
+		return nil
+	}
+
+	var employee Employee
+	if err := tx.Select("id", "slot_time_diff", "total_service_density").Where("id = ?", a.EmployeeID).First(&employee).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading employee: %w", err))
+	}
+	capacity := employee.TotalServiceDensity
+	var densities []EmployeeServiceDensity
+	if err := tx.Where("employee_id = ? AND service_id = ?", a.EmployeeID, a.ServiceID).Find(&densities).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading employee service density: %w", err))
+	}
+	if len(densities) > 0 {
+		capacity = densities[0].Density
+	}
+
+	shifts, err := a.WorkShifts(tx)
+	if err != nil {
+		return err
+	}
+	for _, ws := range shifts {
+		shift := Shift{Start: ws.Start, End: ws.End, Capacity: capacity}
+
+		var busy []Appointment
+		if err := tx.Select("start_time", "end_time").
+			Where("employee_id = ? AND is_cancelled = ? AND id != ?", a.EmployeeID, false, a.ID).
+			Where("start_time < ? AND end_time > ?", shift.End, shift.Start).
+			Find(&busy).Error; err != nil {
+			return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading employee appointments: %w", err))
+		}
+		for _, b := range busy {
+			shift.Busy = append(shift.Busy, TimeSpan{Start: b.StartTime, End: b.EndTime})
+		}
+		starts := rules.Starts(shift, rules.Step(&employee), a.EndTime.Sub(a.StartTime))
+		if slices.ContainsFunc(starts, a.StartTime.Equal) {
+			return nil
+		}
+	}
+	return lib.Error.Appointment.StartTimeNotOffered.WithError(fmt.Errorf("the service is not offered at %s with employee %s", a.StartTime.Format(time.RFC3339), a.EmployeeID))
+}
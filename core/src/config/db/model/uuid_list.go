@@ -0,0 +1,46 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// UUIDList is a list of IDs stored as JSONB.
+type UUIDList []uuid.UUID
+
+// AllowsAny reports whether the list is empty, meaning no restriction, or contains id.
+func (l UUIDList) AllowsAny(id uuid.UUID) bool {
+	return len(l) == 0 || slices.Contains(l, id)
+}
+
+func (l UUIDList) Value() (driver.Value, error) {
+	if l == nil {
+		return json.Marshal([]uuid.UUID{})
+	}
+	return json.Marshal([]uuid.UUID(l))
+}
+
+func (l *UUIDList) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		if value == nil {
+			*l = nil
+			return nil
+		}
+		if str, ok := value.(string); ok {
+			bytes = []byte(str)
+		} else {
+			return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+		}
+	}
+	if len(bytes) == 0 {
+		*l = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, l)
+}
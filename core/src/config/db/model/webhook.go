@@ -0,0 +1,223 @@
+package model
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mynute-go/core/src/lib"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// --- Webhook events --- //
+
+const (
+	WebhookEventAppointmentCreated   = "appointment.created"
+	WebhookEventAppointmentUpdated   = "appointment.updated"
+	WebhookEventAppointmentCancelled = "appointment.cancelled"
+	WebhookEventClientCreated        = "client.created"
+	WebhookEventEmployeeUpdated      = "employee.updated"
+)
+
+var WebhookEvents = []string{
+	WebhookEventAppointmentCreated,
+	WebhookEventAppointmentUpdated,
+	WebhookEventAppointmentCancelled,
+	WebhookEventClientCreated,
+	WebhookEventEmployeeUpdated,
+}
+
+// --- Webhook delivery status --- //
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookSubscription is a company endpoint that receives signed event notifications.
+type WebhookSubscription struct {
+	BaseModel
+	CompanyID   uuid.UUID          `gorm:"type:uuid;not null;index" json:"company_id"`
+	URL         string             `gorm:"type:text;not null" json:"url"`
+	Description string             `gorm:"type:text" json:"description"`
+	Secret      string             `gorm:"type:varchar(64);not null" json:"-"` // Only shown on creation and rotation
+	Events      WebhookEventList   `gorm:"type:jsonb;not null" json:"events"`
+	IsActive    bool               `gorm:"not null;default:true" json:"is_active"`
+	Deliveries  []*WebhookDelivery `gorm:"foreignKey:SubscriptionID;constraint:OnDelete:CASCADE;" json:"deliveries,omitempty"`
+}
+
+const WebhookSubscriptionTableName = "webhook_subscriptions"
+
+func (WebhookSubscription) TableName() string  { return WebhookSubscriptionTableName }
+func (WebhookSubscription) SchemaType() string { return "company" }
+
+func (w *WebhookSubscription) BeforeCreate(tx *gorm.DB) error {
+	if w.Secret == "" {
+		if err := w.NewSecret(); err != nil {
+			return err
+		}
+	}
+	return w.Validate()
+}
+
+// NewSecret replaces the signing secret with a random one, without saving it.
+func (w *WebhookSubscription) NewSecret() error {
+	secret, err := lib.GenerateSecureToken(32)
+	if err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	w.Secret = secret
+	return nil
+}
+
+func (w *WebhookSubscription) BeforeUpdate(tx *gorm.DB) error {
+	if tx.Statement.Changed("CompanyID") {
+		return lib.Error.Company.IdUpdateForbidden
+	}
+	if tx.Statement.Changed("URL") {
+		if err := ValidateWebhookURL(w.URL); err != nil {
+			return err
+		}
+	}
+	if tx.Statement.Changed("Events") {
+		return w.Events.Validate()
+	}
+	return nil
+}
+
+func (w *WebhookSubscription) Validate() error {
+	if w.CompanyID == uuid.Nil {
+		return lib.Error.Webhook.InvalidSubscription.WithError(fmt.Errorf("company_id is required"))
+	}
+	if err := ValidateWebhookURL(w.URL); err != nil {
+		return err
+	}
+	return w.Events.Validate()
+}
+
+// ListensTo reports whether the subscription is active and registered for the event.
+func (w *WebhookSubscription) ListensTo(event string) bool {
+	return w.IsActive && slices.Contains(w.Events, event)
+}
+
+// ValidateWebhookURL checks that raw is an https URL whose host is not a loopback,
+// private or link-local address. Host names are resolved and every address checked;
+// names that do not resolve are accepted, the dialer of the dispatcher checks the
+// addresses again on every send.
+func ValidateWebhookURL(raw string) error {
+	u, err := url.ParseRequestURI(raw)
+	if err != nil {
+		return lib.Error.Webhook.InvalidURL.WithError(err)
+	}
+	if u.Scheme != "https" {
+		return lib.Error.Webhook.InvalidURL.WithError(fmt.Errorf("unsupported scheme: %s", u.Scheme))
+	}
+	host := u.Hostname()
+	if host == "" {
+		return lib.Error.Webhook.InvalidURL.WithError(fmt.Errorf("missing host"))
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ValidateWebhookAddress(ip)
+	}
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return lib.Error.Webhook.InvalidURL.WithError(fmt.Errorf("host %s is not allowed", host))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if err := ValidateWebhookAddress(addr.IP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateWebhookAddress rejects the addresses webhooks must not reach: loopback,
+// private, link-local (cloud metadata included), multicast and unspecified ones.
+func ValidateWebhookAddress(ip net.IP) error {
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return lib.Error.Webhook.InvalidURL.WithError(fmt.Errorf("address %s is not allowed", ip))
+	}
+	return nil
+}
+
+// WebhookEventList is the set of events a subscription listens to, stored as JSONB.
+type WebhookEventList []string
+
+func (e WebhookEventList) Validate() error {
+	if len(e) == 0 {
+		return lib.Error.Webhook.InvalidEvent.WithError(fmt.Errorf("at least one event is required"))
+	}
+	for _, event := range e {
+		if !slices.Contains(WebhookEvents, event) {
+			return lib.Error.Webhook.InvalidEvent.WithError(fmt.Errorf("unknown event: %s", event))
+		}
+	}
+	return nil
+}
+
+func (e WebhookEventList) Value() (driver.Value, error) {
+	if e == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal([]string(e))
+}
+
+func (e *WebhookEventList) Scan(value any) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		if value == nil {
+			*e = nil
+			return nil
+		}
+		if str, ok := value.(string); ok {
+			bytes = []byte(str)
+		} else {
+			return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+		}
+	}
+	if len(bytes) == 0 {
+		*e = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, e)
+}
+
+// WebhookDelivery is one attempt chain of sending an event payload to a subscription.
+// Every delivery is kept as a log entry so it can be inspected and replayed.
+type WebhookDelivery struct {
+	BaseModel
+	SubscriptionID uuid.UUID             `gorm:"type:uuid;not null;index" json:"subscription_id"`
+	CompanyID      uuid.UUID             `gorm:"type:uuid;not null;index" json:"company_id"`
+	Event          string                `gorm:"type:varchar(100);not null;index" json:"event"`
+	Payload        datatypes.JSON        `gorm:"type:jsonb;not null" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(20);not null;index;default:'PENDING'" json:"status"`
+	Attempts       int                   `gorm:"not null;default:0" json:"attempts"`
+	ResponseStatus int                   `json:"response_status"`
+	LastError      string                `gorm:"type:text" json:"last_error"`
+	NextAttemptAt  *time.Time            `gorm:"index" json:"next_attempt_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	ReplayOfID     *uuid.UUID            `gorm:"type:uuid" json:"replay_of_id"`
+}
+
+const WebhookDeliveryTableName = "webhook_deliveries"
+
+func (WebhookDelivery) TableName() string  { return WebhookDeliveryTableName }
+func (WebhookDelivery) SchemaType() string { return "company" }
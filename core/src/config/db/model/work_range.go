@@ -61,9 +61,10 @@ func (wr *WorkRangeBase) ValidateTime() error {
 	if wr.StartTime.Equal(wr.EndTime) {
 		return lib.Error.General.BadRequest.WithError(fmt.Errorf("start time cannot be equal to end time"))
 	}
-	// If end time is not at midnight, start time cannot be after end time
-	if wr.StartTime.After(wr.EndTime) && !(wr.EndTime.Hour() == 0 && wr.EndTime.Minute() == 0) {
-		return lib.Error.General.BadRequest.WithError(fmt.Errorf("start time (%s) cannot be after end time (%s)", wr.StartTime, wr.EndTime))
+	// A range ending before its start spans midnight and belongs to the weekday it
+	// starts on, like 20:00 to 04:00. It still has to last less than a day
+	if length := lib.OvernightEnd(wr.StartTime, wr.EndTime).Sub(wr.StartTime); length <= 0 || length >= 24*time.Hour {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("work range from (%s) to (%s) must last more than zero and less than 24 hours", wr.StartTime.Format("15:04"), wr.EndTime.Format("15:04")))
 	}
 	if wr.Weekday < 0 || wr.Weekday > 6 {
 		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid weekday %d", wr.Weekday))
@@ -91,6 +92,55 @@ func (wr *WorkRangeBase) GetTimeZone() (*time.Location, error) {
 	return loc, nil
 }
 
+// On returns when the work range starts and ends when started on the calendar date of
+// day, in loc. Ranges spanning midnight end on the next day.
+func (wr *WorkRangeBase) On(day time.Time, loc *time.Location) (time.Time, time.Time) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), wr.StartTime.Hour(), wr.StartTime.Minute(), 0, 0, loc)
+	end := time.Date(day.Year(), day.Month(), day.Day(), wr.EndTime.Hour(), wr.EndTime.Minute(), 0, 0, loc)
+	return start, lib.OvernightEnd(start, end)
+}
+
+// ShiftContaining returns when the work range starts and ends around [start, end), started
+// on the day of start or, for ranges spanning midnight, on the day before. ok is false
+// when the range does not contain it.
+func (wr *WorkRangeBase) ShiftContaining(start, end time.Time) (shiftStart, shiftEnd time.Time, ok bool) {
+	loc, err := wr.GetTimeZone()
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	local := start.In(loc)
+	for _, day := range []time.Time{local, local.AddDate(0, 0, -1)} {
+		if day.Weekday() != wr.Weekday {
+			continue
+		}
+		shiftStart, shiftEnd = wr.On(day, loc)
+		if !start.Before(shiftStart) && !end.After(shiftEnd) {
+			return shiftStart, shiftEnd, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// AdjacentWeekdays returns the weekday of the work range with the ones before and after,
+// where ranges spanning midnight may overlap it.
+func (wr *WorkRangeBase) AdjacentWeekdays() []time.Weekday {
+	return []time.Weekday{(wr.Weekday + 6) % 7, wr.Weekday, (wr.Weekday + 1) % 7}
+}
+
+// dayOffset returns in how many days other starts after wr within a week, -1 to 1,
+// and false when they are further apart and can not overlap.
+func (wr *WorkRangeBase) dayOffset(other *WorkRangeBase) (int, bool) {
+	switch (int(other.Weekday) - int(wr.Weekday) + 7) % 7 {
+	case 0:
+		return 0, true
+	case 1:
+		return 1, true
+	case 6:
+		return -1, true
+	}
+	return 0, false
+}
+
 func (wr *WorkRangeBase) GetTimeZoneString() (string, error) {
 	loc, err := wr.GetTimeZone()
 	if err != nil {
@@ -99,8 +149,11 @@ func (wr *WorkRangeBase) GetTimeZoneString() (string, error) {
 	return loc.String(), nil
 }
 
+// Overlaps reports whether the work ranges overlap, on the same weekday or, when one spans
+// midnight, into the next one.
 func (wr *WorkRangeBase) Overlaps(other *WorkRangeBase) (bool, error) {
-	if wr.Weekday != other.Weekday {
+	days, near := wr.dayOffset(other)
+	if !near {
 		return false, nil
 	}
 	loc1, err := wr.GetTimeZone()
@@ -111,7 +164,11 @@ func (wr *WorkRangeBase) Overlaps(other *WorkRangeBase) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return lib.TimeRangeOverlaps(wr.StartTime, wr.EndTime, loc1, other.StartTime, other.EndTime, loc2), nil
+	// Ranges spanning midnight end on the day after they start
+	end := lib.OvernightEnd(wr.StartTime, wr.EndTime)
+	otherStart := other.StartTime.AddDate(0, 0, days)
+	otherEnd := lib.OvernightEnd(other.StartTime, other.EndTime).AddDate(0, 0, days)
+	return lib.TimeRangeOverlaps(wr.StartTime, end, loc1, otherStart, otherEnd, loc2), nil
 }
 
 // ConvertToBranchTimeZone converts the start and end times of the BranchWorkRange to the branch's time zone.
@@ -141,3 +198,40 @@ func (wr *WorkRangeBase) ConvertToBranchTimeZone(tx *gorm.DB) error {
 	wr.TimeZone = bTZ
 	return nil
 }
+
+// WorkShift is a work range of an employee on a day.
+type WorkShift struct {
+	Range EmployeeWorkRange
+	Start time.Time
+	End   time.Time
+}
+
+// WorkShifts lists the work ranges of the employee at the branch containing the
+// appointment, with when they start and end around it.
+func (a *Appointment) WorkShifts(tx *gorm.DB) ([]WorkShift, error) {
+	var ranges []EmployeeWorkRange
+	if err := tx.Where("employee_id = ? AND branch_id = ?", a.EmployeeID, a.BranchID).Find(&ranges).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error querying work schedule: %w", err))
+	}
+	var shifts []WorkShift
+	for _, wr := range ranges {
+		if start, end, ok := wr.ShiftContaining(a.StartTime, a.EndTime); ok {
+			shifts = append(shifts, WorkShift{Range: wr, Start: start, End: end})
+		}
+	}
+	return shifts, nil
+}
+
+// BranchOpen reports whether a work range of the branch contains the appointment.
+func (a *Appointment) BranchOpen(tx *gorm.DB) (bool, error) {
+	var ranges []BranchWorkRange
+	if err := tx.Where("branch_id = ?", a.BranchID).Find(&ranges).Error; err != nil {
+		return false, lib.Error.General.InternalError.WithError(fmt.Errorf("error querying branch work schedule: %w", err))
+	}
+	for _, wr := range ranges {
+		if _, _, ok := wr.ShiftContaining(a.StartTime, a.EndTime); ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rangeTZ = "America/Sao_Paulo"
+
+func rangeOn(t *testing.T, weekday time.Weekday, start, end int) *WorkRangeBase {
+	loc, err := time.LoadLocation(rangeTZ)
+	require.NoError(t, err)
+	return &WorkRangeBase{
+		Weekday:   weekday,
+		StartTime: time.Date(2020, 1, 1, start, 0, 0, 0, loc),
+		EndTime:   time.Date(2020, 1, 1, end, 0, 0, 0, loc),
+		TimeZone:  rangeTZ,
+	}
+}
+
+func TestWorkRangeValidateTime(t *testing.T) {
+	assert.NoError(t, rangeOn(t, time.Monday, 8, 12).ValidateTime())
+	assert.NoError(t, rangeOn(t, time.Monday, 20, 4).ValidateTime(), "ranges may span midnight")
+	assert.NoError(t, rangeOn(t, time.Monday, 20, 0).ValidateTime(), "ranges may end at midnight")
+	assert.Error(t, rangeOn(t, time.Monday, 8, 8).ValidateTime(), "start equal to end")
+
+	day := rangeOn(t, time.Monday, 8, 8)
+	day.EndTime = day.EndTime.AddDate(0, 0, 1)
+	assert.Error(t, day.ValidateTime(), "a range of a whole day has no length in a week")
+}
+
+func TestWorkRangeOverlaps(t *testing.T) {
+	overlaps := func(a, b *WorkRangeBase) bool {
+		ok, err := a.Overlaps(b)
+		require.NoError(t, err)
+		return ok
+	}
+	night := rangeOn(t, time.Monday, 20, 4)
+
+	assert.True(t, overlaps(rangeOn(t, time.Monday, 8, 12), rangeOn(t, time.Monday, 10, 14)))
+	assert.False(t, overlaps(rangeOn(t, time.Monday, 8, 12), rangeOn(t, time.Monday, 13, 14)))
+	assert.False(t, overlaps(rangeOn(t, time.Monday, 8, 12), rangeOn(t, time.Wednesday, 8, 12)))
+
+	assert.True(t, overlaps(night, rangeOn(t, time.Monday, 22, 23)))
+	assert.True(t, overlaps(night, rangeOn(t, time.Tuesday, 2, 3)), "the night runs into the next day")
+	assert.True(t, overlaps(rangeOn(t, time.Tuesday, 2, 3), night), "whichever range is checked")
+	assert.False(t, overlaps(night, rangeOn(t, time.Tuesday, 5, 6)))
+	assert.False(t, overlaps(night, rangeOn(t, time.Monday, 5, 6)), "the night does not reach back into its morning")
+}
+
+func TestWorkRangeShiftContaining(t *testing.T) {
+	loc, err := time.LoadLocation(rangeTZ)
+	require.NoError(t, err)
+	monday := time.Date(2030, 1, 7, 0, 0, 0, 0, loc)
+	at := func(day, hour int) time.Time { return monday.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour) }
+	night := rangeOn(t, time.Monday, 20, 4)
+
+	start, end, ok := night.ShiftContaining(at(1, 2), at(1, 3))
+	assert.True(t, ok, "02:00 on Tuesday is in the Monday night")
+	assert.Equal(t, at(0, 20), start)
+	assert.Equal(t, at(1, 4), end)
+
+	_, _, ok = night.ShiftContaining(at(0, 21), at(0, 22))
+	assert.True(t, ok)
+
+	_, _, ok = night.ShiftContaining(at(1, 3), at(1, 5))
+	assert.False(t, ok, "the appointment runs past the end of the night")
+
+	_, _, ok = night.ShiftContaining(at(0, 2), at(0, 3))
+	assert.False(t, ok, "the night of Sunday is not worked")
+}
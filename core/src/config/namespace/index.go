@@ -9,6 +9,7 @@ type GeneralStruct struct {
 	DtoArr          string
 	Associations    string
 	DatabaseSession string
+	Transaction     string
 	Company         string
 	CompanySchema   string
 }
@@ -36,6 +37,7 @@ var GeneralKey = GeneralStruct{
 	DtoArr:          "dtoArr_key",
 	Associations:    "associations_key",
 	DatabaseSession: "db_session_key",
+	Transaction:     "db_transaction_key",
 	CompanySchema:   "company_schema_key",
 	Company:         "company_key",
 }
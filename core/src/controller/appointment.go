@@ -1,18 +1,25 @@
 package controller
 
 import (
-	"context"
 	"fmt"
-	"log"
 	DTO "mynute-go/core/src/config/api/dto"
+	dJSON "mynute-go/core/src/config/api/dto/json"
 	database "mynute-go/core/src/config/db"
 	"mynute-go/core/src/config/db/model"
+	mJSON "mynute-go/core/src/config/db/model/json"
 	"mynute-go/core/src/handler"
 	"mynute-go/core/src/lib"
+	"mynute-go/core/src/lib/credit"
 	"mynute-go/core/src/lib/email"
+	"mynute-go/core/src/lib/intake"
+	"mynute-go/core/src/lib/loyalty"
+	"mynute-go/core/src/lib/payment"
+	"mynute-go/core/src/lib/promo"
+	"mynute-go/core/src/lib/receipt"
+	"mynute-go/core/src/lib/review"
 	"mynute-go/core/src/middleware"
+	"mynute-go/core/src/service/availability"
 	"mynute-go/debug"
-	"path/filepath"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -52,20 +59,29 @@ func CreateAppointment(c *fiber.Ctx) error {
 		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid start time format: %w", err))
 	}
 
-	// Get service duration to calculate end time
-	var serviceDuration uint
-	if err := tx.Model(&model.Service{}).Where("id = ?", createDTO.ServiceID).Pluck("duration", &serviceDuration).Error; err != nil {
-		return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading service duration: %w", err))
+	if createDTO.AnyEmployee {
+		if err := assignAnyEmployee(tx, &createDTO, startTime); err != nil {
+			return err
+		}
+	}
+
+	// Get the service duration for the employee and branch to calculate end time
+	var service model.Service
+	if err := tx.Where("id = ?", createDTO.ServiceID).First(&service).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading service: %w", err))
+	}
+	terms, err := service.LoadTerms(tx, createDTO.EmployeeID, createDTO.BranchID)
+	if err != nil {
+		return err
 	}
 
 	// Calculate end time
-	endTime := startTime.Add(time.Duration(serviceDuration) * time.Minute)
+	endTime := startTime.Add(time.Duration(terms.Duration) * time.Minute)
 
-	// Query for overlapping appointments for the same client
+	// Query for overlapping appointments for the same attendee, the client or one of its dependents
 	// Overlap condition: (new_start < existing_end AND new_end > existing_start)
 	var existingAppointment model.Appointment
-	err = tx.Where("client_id = ? AND is_cancelled = ? AND start_time < ? AND end_time > ?",
-		createDTO.ClientID,
+	err = model.WhereAttendee(tx, createDTO.ClientID, createDTO.DependentID).Where("is_cancelled = ? AND start_time < ? AND end_time > ?",
 		false,
 		endTime,
 		startTime,
@@ -82,43 +98,63 @@ func CreateAppointment(c *fiber.Ctx) error {
 		return lib.Error.General.InternalError.WithError(err)
 	}
 
-	// No overlap found, proceed with creation
-	var appointment model.Appointment
-	if err := Create(c, &appointment); err != nil {
-		return err
+	if createDTO.UseCredit && createDTO.PromoCode != "" {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("a promo code can not be used when paying with a credit"))
 	}
-	if err := debug.Output("controller_CreateAppointment", appointment); err != nil {
-		return err
+	if createDTO.UseCredit && createDTO.RedeemPoints != 0 {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("loyalty points can not be redeemed when paying with a credit"))
 	}
 
 	// Get email language from query parameter (default to "en")
 	emailLanguage := c.Query("email_language", "en")
 
-	// Send appointment created emails
-	go func() {
-		ctx := context.Background()
-		tx, err := lib.Session(c)
-		if err != nil {
-			log.Printf("Failed to get database session for email: %v", err)
-			return
+	// No overlap found, proceed with creation
+	var appointment model.Appointment
+	// The employee picked for any employee bookings is not in the body
+	setEmployee := func() error {
+		if createDTO.AnyEmployee {
+			appointment.EmployeeID = createDTO.EmployeeID
 		}
-
-		// Initialize email service
-		sender, err := email.NewProvider(nil)
-		if err != nil {
-			log.Printf("Failed to create email provider: %v", err)
-			return
+		return nil
+	}
+	if err := CreatePreparedThen(c, &appointment, setEmployee, func(tx *gorm.DB) error {
+		// Everything that can refuse the booking runs before the client is charged
+		if err := intake.Require(tx, &appointment, intakeBooking(c, createDTO.Intake, emailLanguage)); err != nil {
+			return err
 		}
-
-		templateDir := filepath.Join("static", "email")
-		translationDir := filepath.Join("translation", "email")
-		emailService := email.NewAppointmentEmailService(sender, templateDir, translationDir)
-
-		if err := emailService.SendAppointmentCreatedEmails(ctx, tx, &appointment, emailLanguage); err != nil {
-			log.Printf("Failed to send appointment created emails: %v", err)
+		if createDTO.UseCredit {
+			// The credit pays the appointment, there is nothing to prepay
+			if err := credit.Consume(tx, &appointment); err != nil {
+				return err
+			}
+		} else {
+			if createDTO.PromoCode != "" {
+				if err := promo.Apply(tx, &appointment, createDTO.PromoCode); err != nil {
+					return err
+				}
+			}
+			if err := loyalty.Redeem(tx, &appointment, createDTO.RedeemPoints); err != nil {
+				return err
+			}
+			paid, err := collectPrepayment(c, tx, &appointment, createDTO.Prepayment)
+			if err != nil {
+				return err
+			}
+			if paid != nil && paid.Status == model.StatusCompleted {
+				if err := receipt.Enqueue(tx, &appointment, emailLanguage); err != nil {
+					return err
+				}
+			}
 		}
-	}()
+		return enqueueAppointmentNotifications(tx, &appointment, "appointment_created", model.WebhookEventAppointmentCreated, emailLanguage)
+	}); err != nil {
+		return err
+	}
+	if err := debug.Output("controller_CreateAppointment", appointment); err != nil {
+		return err
+	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &appointment, &DTO.Appointment{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -164,21 +200,22 @@ func GetAppointmentByID(c *fiber.Ctx) error {
 //	@Param			id				path		string					true	"ID"
 //	@Param			appointment		body		DTO.CreateAppointment	true	"Appointment"
 //	@Param			email_language	query		string					false	"Email language (en, pt, es)"	default(en)
+//	@Param			reason			query		string					false	"Reason of the change, kept in the history"
 //	@Success		200				{object}	DTO.Appointment
 //	@Failure		400				{object}	DTO.ErrorResponse
 //	@Router			/appointment/{id} [patch]
-func UpdateAppointmentByID(c *fiber.Ctx) error {
-	var err error
-
+func UpdateAppointmentByID(c *fiber.Ctx) (err error) {
 	appointment_id := c.Params("id")
 	if appointment_id == "" {
 		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("missing appointment's id in the url"))
 	}
 
-	tx, err := lib.Session(c)
+	// The update and its notifications are committed together
+	tx, end, err := database.ContextTransaction(c)
 	if err != nil {
 		return err
 	}
+	defer func() { end(err) }()
 
 	var appointment model.Appointment
 	if err = database.LockForUpdate(tx, &appointment, "id", appointment_id); err != nil {
@@ -188,6 +225,7 @@ func UpdateAppointmentByID(c *fiber.Ctx) error {
 	if appointment.IsCancelled {
 		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("appointment is cancelled"))
 	}
+	wasFulfilled := appointment.IsFulfilled
 
 	var updated_appointment model.Appointment
 
@@ -201,24 +239,35 @@ func UpdateAppointmentByID(c *fiber.Ctx) error {
 
 	// Check for overlapping appointments if start time is being updated
 	if !updated_appointment.StartTime.IsZero() {
-		// Get service duration to calculate end time
-		var serviceDuration uint
+		// Resolve the service duration for the employee at the branch, as ValidateRules does
 		serviceID := updated_appointment.ServiceID
 		if serviceID == uuid.Nil {
 			serviceID = appointment.ServiceID // Use existing service ID if not being updated
 		}
+		employeeID := updated_appointment.EmployeeID
+		if employeeID == uuid.Nil {
+			employeeID = appointment.EmployeeID
+		}
+		branchID := updated_appointment.BranchID
+		if branchID == uuid.Nil {
+			branchID = appointment.BranchID
+		}
 
-		if err := tx.Model(&model.Service{}).Where("id = ?", serviceID).Pluck("duration", &serviceDuration).Error; err != nil {
-			return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading service duration: %w", err))
+		var service model.Service
+		if err := tx.Where("id = ?", serviceID).First(&service).Error; err != nil {
+			return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading service: %w", err))
+		}
+		terms, err := service.LoadTerms(tx, employeeID, branchID)
+		if err != nil {
+			return err
 		}
 
 		// Calculate end time
-		endTime := updated_appointment.StartTime.Add(time.Duration(serviceDuration) * time.Minute)
+		endTime := updated_appointment.StartTime.Add(time.Duration(terms.Duration) * time.Minute)
 
-		// Query for overlapping appointments for the same client (excluding current appointment)
+		// Query for overlapping appointments for the same attendee, the client or one of its dependents (excluding current appointment)
 		var existingAppointment model.Appointment
-		err = tx.Where("client_id = ? AND is_cancelled = ? AND id != ? AND start_time < ? AND end_time > ?",
-			appointment.ClientID,
+		err = model.WhereAttendee(tx, appointment.ClientID, appointment.DependentID).Where("is_cancelled = ? AND id != ? AND start_time < ? AND end_time > ?",
 			false,
 			appointment.ID,
 			endTime,
@@ -237,41 +286,39 @@ func UpdateAppointmentByID(c *fiber.Ctx) error {
 		}
 	}
 
-	tx.Model(appointment).Where("id = ?", appointment_id).Updates(updated_appointment)
-	if tx.Error != nil {
-		return lib.Error.General.UpdatedError.WithError(tx.Error)
+	if err := model.WithAudit(tx, auditFromRequest(c)).Model(&appointment).Updates(&updated_appointment).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
 	}
 
-	tx.Model(appointment).Where("id = ?", appointment_id).First(&appointment)
-	if tx.Error != nil {
-		if tx.Error == gorm.ErrRecordNotFound {
+	if err := tx.Model(appointment).Where("id = ?", appointment_id).First(&appointment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
 			return lib.Error.Appointment.NotFound
 		}
-		return lib.Error.General.UpdatedError.WithError(tx.Error)
+		return lib.Error.General.UpdatedError.WithError(err)
 	}
 
 	// Get email language from query parameter (default to "en")
 	emailLanguage := c.Query("email_language", "en")
 
-	// Send appointment updated emails
-	go func() {
-		ctx := context.Background()
-		// Initialize email service
-		sender, err := email.NewProvider(nil)
-		if err != nil {
-			log.Printf("Failed to create email provider: %v", err)
-			return
+	if err := enqueueAppointmentNotifications(tx, &appointment, "appointment_updated", model.WebhookEventAppointmentUpdated, emailLanguage); err != nil {
+		return err
+	}
+	// Appointments paid online already got their receipt
+	if !wasFulfilled && appointment.IsFulfilled && appointment.ReceiptURL == "" {
+		if err := receipt.Enqueue(tx, &appointment, emailLanguage); err != nil {
+			return err
 		}
-
-		templateDir := filepath.Join("static", "email")
-		translationDir := filepath.Join("translation", "email")
-		emailService := email.NewAppointmentEmailService(sender, templateDir, translationDir)
-
-		if err := emailService.SendAppointmentUpdatedEmails(ctx, tx, &appointment, emailLanguage); err != nil {
-			log.Printf("Failed to send appointment updated emails: %v", err)
+	}
+	if !wasFulfilled && appointment.IsFulfilled {
+		if err := loyalty.Earn(tx, &appointment); err != nil {
+			return err
+		}
+		if err := review.Invite(tx, &appointment, emailLanguage, fmt.Sprintf("%s://%s", c.Protocol(), c.Hostname())); err != nil {
+			return err
 		}
-	}()
+	}
 
+	invalidateAvailability(c)
 	if err = lib.ResponseFactory(c).SendDTO(200, &appointment, &DTO.Appointment{}); err != nil {
 		return lib.Error.General.UpdatedError.WithError(err)
 	}
@@ -291,11 +338,11 @@ func UpdateAppointmentByID(c *fiber.Ctx) error {
 //	@Param			X-Company-ID	header		string	true	"X-Company-ID"
 //	@Param			id				path		string	true	"ID"
 //	@Param			email_language	query		string	false	"Email language (en, pt, es)"	default(en)
+//	@Param			reason			query		string	false	"Reason of the cancellation, kept in the history"
 //	@Success		200				{object}	DTO.Appointment
 //	@Failure		400				{object}	DTO.ErrorResponse
 //	@Router			/appointment/{id} [delete]
-func CancelAppointmentByID(c *fiber.Ctx) error {
-	var err error
+func CancelAppointmentByID(c *fiber.Ctx) (err error) {
 	appointment_id := c.Params("id")
 	if appointment_id == "" {
 		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("missing appointment's id in the url"))
@@ -306,45 +353,248 @@ func CancelAppointmentByID(c *fiber.Ctx) error {
 	}
 	var appointment model.Appointment
 	appointment.ID = uuid
-	tx, err := lib.Session(c)
+
+	// The cancellation and its notifications are committed together
+	tx, end, err := database.ContextTransaction(c)
 	if err != nil {
 		return err
 	}
+	defer func() { end(err) }()
 
 	// Load appointment data before cancelling for email
 	if err := tx.Where("id = ?", uuid).First(&appointment).Error; err != nil {
 		return lib.Error.Appointment.NotFound.WithError(err)
 	}
 
-	if err := appointment.Cancel(tx); err != nil {
+	if err := appointment.Cancel(model.WithAudit(tx, auditFromRequest(c))); err != nil {
+		return err
+	}
+
+	// Refund what the service cancellation policy grants
+	if err := payment.RefundOnCancel(tx, &appointment); err != nil {
+		return err
+	}
+	if err := credit.RestoreOnCancel(tx, &appointment); err != nil {
+		return err
+	}
+	if err := loyalty.ReverseOnCancel(tx, &appointment); err != nil {
 		return err
 	}
 
 	// Get email language from query parameter (default to "en")
 	emailLanguage := c.Query("email_language", "en")
 
-	// Send appointment cancelled emails
-	go func() {
-		ctx := context.Background()
-		// Initialize email service
-		sender, err := email.NewProvider(nil)
-		if err != nil {
-			log.Printf("Failed to create email provider: %v", err)
-			return
-		}
+	invalidateAvailability(c)
+	return enqueueAppointmentNotifications(tx, &appointment, "appointment_cancelled", model.WebhookEventAppointmentCancelled, emailLanguage)
+}
 
-		templateDir := filepath.Join("static", "email")
-		translationDir := filepath.Join("translation", "email")
-		emailService := email.NewAppointmentEmailService(sender, templateDir, translationDir)
+// GetAppointmentHistory lists the changes made to an appointment
+//
+//	@Summary		Get appointment history
+//	@Description	List the changes made to an appointment, newest first, with who made them and through which endpoint
+//	@Tags			Appointment
+//	@Produce		json
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"ID"
+//	@Param			field			query		string	false	"Only changes of this field (e.g. StartTime)"
+//	@Param			page			query		int		false	"Page number"	default(1)
+//	@Param			page_size		query		int		false	"Page size"		default(10)
+//	@Success		200				{object}	DTO.AppointmentHistoryPage
+//	@Failure		400				{object}	DTO.ErrorResponse
+//	@Router			/appointment/{id}/history [get]
+func GetAppointmentHistory(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
 
-		if err := emailService.SendAppointmentCancelledEmails(ctx, tx, &appointment, emailLanguage); err != nil {
-			log.Printf("Failed to send appointment cancelled emails: %v", err)
+	var appointment model.Appointment
+	if err := tx.Select("id", "history").Where("id = ?", c.Params("id")).First(&appointment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return lib.Error.Appointment.NotFound
 		}
-	}()
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	changes := appointment.History.FieldChanges
+	if field := c.Query("field"); field != "" {
+		changes = appointment.History.FilterByField(field)
+	}
+
+	page := max(c.QueryInt("page", 1), 1)
+	pageSize := min(max(c.QueryInt("page_size", 10), 1), 100)
 
+	history := DTO.AppointmentHistoryPage{
+		Changes:    []dJSON.FieldChange{},
+		TotalCount: len(changes),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	// Changes are stored oldest first
+	for i := len(changes) - 1 - (page-1)*pageSize; i >= 0 && len(history.Changes) < pageSize; i-- {
+		history.Changes = append(history.Changes, fieldChangeDTO(changes[i]))
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, &history); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
 	return nil
 }
 
+// GetAppointmentReceipt gets the receipt of an appointment
+//
+//	@Summary		Get appointment receipt
+//	@Description	Get the receipt of a fulfilled or paid appointment. It is issued and stored the first time.
+//	@Description	With format=pdf the PDF itself is returned.
+//	@Tags			Appointment
+//	@Produce		json
+//	@Produce		application/pdf
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"ID"
+//	@Param			format			query		string	false	"json or pdf"						default(json)
+//	@Param			language		query		string	false	"Receipt language (en, pt, es)"	default(en)
+//	@Success		200				{object}	DTO.AppointmentReceipt
+//	@Failure		400				{object}	DTO.ErrorResponse
+//	@Router			/appointment/{id}/receipt [get]
+func GetAppointmentReceipt(c *fiber.Ctx) (err error) {
+	// Issuing the receipt records it on the appointment
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	var appointment model.Appointment
+	if err = database.LockForUpdate(tx, &appointment, "id", c.Params("id")); err != nil {
+		return err
+	}
+	r, pdf, err := receipt.Issue(tx, &appointment, c.Query("language", "en"))
+	if err != nil {
+		return err
+	}
+
+	if c.Query("format") == "pdf" {
+		c.Set(fiber.HeaderContentType, "application/pdf")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", receipt.Filename(r)))
+		return c.Status(200).Send(pdf)
+	}
+	res := DTO.AppointmentReceipt{
+		AppointmentID: appointment.ID,
+		Number:        r.Number,
+		IssuedAt:      appointment.ReceiptIssuedAt.Format(time.RFC3339),
+		Total:         r.Total(),
+		Paid:          r.Paid,
+		Currency:      r.Currency,
+		URL:           appointment.ReceiptURL,
+	}
+	if err = lib.ResponseFactory(c).Send(200, &res); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+func fieldChangeDTO(change mJSON.FieldChange) dJSON.FieldChange {
+	valueType := change.ValueType
+	if valueType == "" {
+		// Recorded before values were typed, they were stored as strings
+		valueType = mJSON.ValueTypeString
+	}
+	return dJSON.FieldChange{
+		CreatedAt: change.CreatedAt.Format(time.RFC3339),
+		Field:     change.Field,
+		ValueType: valueType,
+		OldValue:  change.OldValue,
+		NewValue:  change.NewValue,
+		Reason:    change.Reason,
+		ActorID:   change.ActorID,
+		ActorType: change.ActorType,
+		Endpoint:  change.Endpoint,
+		IP:        change.IP,
+	}
+}
+
+// enqueueAppointmentNotifications records the client and employee emails and the
+// webhook event of an appointment change in the outbox, within the caller's transaction.
+func enqueueAppointmentNotifications(tx *gorm.DB, appointment *model.Appointment, emailTemplate, webhookEvent, language string) error {
+	if err := email.EnqueueAppointmentEmails(tx, appointment, emailTemplate, language); err != nil {
+		return err
+	}
+	return enqueueWebhookEvent(tx, appointment.CompanyID, webhookEvent, appointment, &DTO.Appointment{})
+}
+
+// assignAnyEmployee picks the employee of an appointment booked with any employee among
+// those free at the start time, by the assignment strategy of the service, and sets it
+// on createDTO.
+func assignAnyEmployee(tx *gorm.DB, createDTO *DTO.CreateAppointment, startTime time.Time) error {
+	loc, err := time.LoadLocation(createDTO.TimeZone)
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid time zone: %s", createDTO.TimeZone))
+	}
+	query := availability.Query{
+		CompanyID:   createDTO.CompanyID,
+		ServiceID:   createDTO.ServiceID,
+		ClientID:    createDTO.ClientID,
+		DependentID: createDTO.DependentID,
+		Location:    loc,
+	}
+	ranked, err := availability.Ranked(availability.GormStore{DB: tx}, query, createDTO.BranchID, startTime)
+	if err != nil {
+		return err
+	}
+
+	var appointment model.Appointment
+	appointment.ServiceID = createDTO.ServiceID
+	appointment.ClientID = createDTO.ClientID
+	appointment.DependentID = createDTO.DependentID
+	appointment.BranchID = createDTO.BranchID
+	appointment.CompanyID = createDTO.CompanyID
+	appointment.StartTime = startTime
+	appointment.TimeZone = createDTO.TimeZone
+	if err := appointment.AssignEmployee(tx, ranked); err != nil {
+		return err
+	}
+	createDTO.EmployeeID = appointment.EmployeeID
+	return nil
+}
+
+// collectPrepayment charges the deposit or full price the service asks for when booking.
+// It returns the payment, nil when the service asks for none.
+func collectPrepayment(c *fiber.Ctx, tx *gorm.DB, appointment *model.Appointment, input *DTO.PaymentInput) (*model.Payment, error) {
+	var service model.Service
+	if err := tx.Where("id = ?", appointment.ServiceID).First(&service).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading service: %w", err))
+	}
+	// Charge the price resolved for the employee and branch, snapshotted on the appointment, less the promo code and loyalty discounts
+	service.Price = appointment.Price - appointment.Discount - appointment.LoyaltyDiscount
+	if _, _, ok := service.PrepaymentAmount(); !ok {
+		return nil, nil
+	}
+	if input == nil {
+		return nil, lib.Error.Payment.MissingMethod
+	}
+	req := payment.ChargeRequest{Method: input.Method, Token: input.Token}
+	providerName := ""
+	if input.Method == payment.PixMethod {
+		providerName = payment.PixProviderName
+		var company model.Company
+		if err := tx.Where("id = ?", appointment.CompanyID).First(&company).Error; err != nil {
+			return nil, lib.Error.Company.NotFound.WithError(err)
+		}
+		req.Pix = &payment.PixReceiver{Key: company.PixKey, Name: company.TradeName, City: company.PixCity}
+	}
+	provider, err := payment.NewProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	return payment.Collect(c.UserContext(), tx, provider, appointment, &service, req)
+}
+
 // Constructor for appointment_controller
 func Appointment(Gorm *handler.Gorm) {
 	endpoint := &middleware.Endpoint{DB: Gorm}
@@ -353,5 +603,7 @@ func Appointment(Gorm *handler.Gorm) {
 		GetAppointmentByID,
 		UpdateAppointmentByID,
 		CancelAppointmentByID,
+		GetAppointmentHistory,
+		GetAppointmentReceipt,
 	})
 }
@@ -0,0 +1,348 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateBookableResource adds a room, chair or equipment to a branch
+//
+//	@Summary		Create bookable resource
+//	@Description	Add a room, chair or equipment to a branch, with how many interchangeable units it has
+//	@Tags			Branch
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"Branch ID"
+//	@Accept			json
+//	@Produce		json
+//	@Param			resource	body		DTO.CreateBookableResource	true	"Resource"
+//	@Success		200			{object}	DTO.BookableResource
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Router			/branch/{id}/resource [post]
+func CreateBookableResource(c *fiber.Ctx) error {
+	branchID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(errors.New("invalid branch id"))
+	}
+	var body DTO.CreateBookableResource
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	resource := model.BookableResource{BranchID: branchID, Name: body.Name, Kind: body.Kind, Quantity: body.Quantity}
+	if err := tx.Create(&resource).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, bookableResourceDTO(&resource)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetBookableResources lists the rooms, chairs and equipment of a branch
+//
+//	@Summary		List bookable resources
+//	@Description	List the rooms, chairs and equipment of a branch
+//	@Tags			Branch
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"Branch ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.BookableResourceList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/branch/{id}/resources [get]
+func GetBookableResources(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	var resources []model.BookableResource
+	if err := tx.Where("branch_id = ?", c.Params("id")).Order("name").Find(&resources).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	list := DTO.BookableResourceList{Resources: make([]DTO.BookableResource, 0, len(resources))}
+	for i := range resources {
+		list.Resources = append(list.Resources, *bookableResourceDTO(&resources[i]))
+	}
+	if err := lib.ResponseFactory(c).Send(200, &list); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// UpdateBookableResource updates a room, chair or equipment of a branch
+//
+//	@Summary		Update bookable resource
+//	@Description	Update the name, kind or quantity of a room, chair or equipment of a branch. Appointments already booked keep what they hold.
+//	@Tags			Branch
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"Branch ID"
+//	@Param			resource_id		path		string	true	"Resource ID"
+//	@Accept			json
+//	@Produce		json
+//	@Param			resource	body		DTO.UpdateBookableResource	true	"Resource"
+//	@Success		200			{object}	DTO.BookableResource
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Router			/branch/{id}/resource/{resource_id} [patch]
+func UpdateBookableResource(c *fiber.Ctx) error {
+	var body DTO.UpdateBookableResource
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	resource, err := loadBookableResource(tx, c.Params("id"), c.Params("resource_id"))
+	if err != nil {
+		return err
+	}
+
+	changes := map[string]any{}
+	if body.Name != nil {
+		resource.Name = *body.Name
+		changes["name"] = resource.Name
+	}
+	if body.Kind != nil {
+		resource.Kind = *body.Kind
+		changes["kind"] = resource.Kind
+	}
+	if body.Quantity != nil {
+		resource.Quantity = *body.Quantity
+		changes["quantity"] = resource.Quantity
+	}
+	if len(changes) == 0 {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("no changes provided"))
+	}
+	if err := resource.Validate(); err != nil {
+		return err
+	}
+	if err := tx.Model(resource).Updates(changes).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
+	}
+
+	invalidateAvailability(c)
+	if err := lib.ResponseFactory(c).Send(200, bookableResourceDTO(resource)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// DeleteBookableResource deletes a room, chair or equipment of a branch
+//
+//	@Summary		Delete bookable resource
+//	@Description	Delete a room, chair or equipment of a branch. Services stop holding it.
+//	@Tags			Branch
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"Branch ID"
+//	@Param			resource_id		path		string	true	"Resource ID"
+//	@Success		200
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/branch/{id}/resource/{resource_id} [delete]
+func DeleteBookableResource(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	resource, err := loadBookableResource(tx, c.Params("id"), c.Params("resource_id"))
+	if err != nil {
+		return err
+	}
+	if err := tx.Where("resource_id = ?", resource.ID).Delete(&model.ServiceResourceRequirement{}).Error; err != nil {
+		return lib.Error.General.DeletedError.WithError(err)
+	}
+	if err := tx.Delete(resource).Error; err != nil {
+		return lib.Error.General.DeletedError.WithError(err)
+	}
+	invalidateAvailability(c)
+	return c.SendStatus(200)
+}
+
+// AddServiceResource makes a service hold a resource of a branch while it runs
+//
+//	@Summary		Add service resource
+//	@Description	Make appointments of the service hold units of a resource of a branch, at that branch
+//	@Tags			Service
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"Service ID"
+//	@Accept			json
+//	@Produce		json
+//	@Param			resource	body		DTO.AddServiceResource	true	"Resource"
+//	@Success		200			{object}	DTO.ServiceResourceRequirementList
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Router			/service/{id}/resource [post]
+func AddServiceResource(c *fiber.Ctx) error {
+	serviceID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(errors.New("invalid service id"))
+	}
+	var body DTO.AddServiceResource
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	requirement := model.ServiceResourceRequirement{ServiceID: serviceID, ResourceID: body.ResourceID, Quantity: body.Quantity}
+	if err := tx.Create(&requirement).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
+
+	invalidateAvailability(c)
+	return sendServiceResources(c, tx, serviceID)
+}
+
+// GetServiceResources lists the resources a service holds while it runs
+//
+//	@Summary		List service resources
+//	@Description	List the resources appointments of the service hold, at the branch of each resource
+//	@Tags			Service
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"Service ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.ServiceResourceRequirementList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/service/{id}/resources [get]
+func GetServiceResources(c *fiber.Ctx) error {
+	serviceID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(errors.New("invalid service id"))
+	}
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+	return sendServiceResources(c, tx, serviceID)
+}
+
+// RemoveServiceResource stops a service from holding a resource
+//
+//	@Summary		Remove service resource
+//	@Description	Stop appointments of the service from holding a resource. Appointments already booked keep it.
+//	@Tags			Service
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"Service ID"
+//	@Param			resource_id		path		string	true	"Resource ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.ServiceResourceRequirementList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/service/{id}/resource/{resource_id} [delete]
+func RemoveServiceResource(c *fiber.Ctx) error {
+	serviceID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(errors.New("invalid service id"))
+	}
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	result := tx.Where("service_id = ? AND resource_id = ?", serviceID, c.Params("resource_id")).Delete(&model.ServiceResourceRequirement{})
+	if result.Error != nil {
+		return lib.Error.General.DeletedError.WithError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return lib.Error.BookableResource.NotFound
+	}
+
+	invalidateAvailability(c)
+	return sendServiceResources(c, tx, serviceID)
+}
+
+func loadBookableResource(tx *gorm.DB, branchID, resourceID string) (*model.BookableResource, error) {
+	var resource model.BookableResource
+	if err := tx.Where("id = ? AND branch_id = ?", resourceID, branchID).First(&resource).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, lib.Error.BookableResource.NotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	return &resource, nil
+}
+
+func sendServiceResources(c *fiber.Ctx, tx *gorm.DB, serviceID uuid.UUID) error {
+	needs, err := model.LoadResourceNeeds(tx, serviceID)
+	if err != nil {
+		return err
+	}
+	list := DTO.ServiceResourceRequirementList{Resources: make([]DTO.ServiceResourceRequirement, 0, len(needs))}
+	for _, n := range needs {
+		list.Resources = append(list.Resources, DTO.ServiceResourceRequirement{
+			ResourceID: n.ResourceID,
+			BranchID:   n.BranchID,
+			Name:       n.Name,
+			Quantity:   n.Quantity,
+			Capacity:   n.Capacity,
+		})
+	}
+	if err := lib.ResponseFactory(c).Send(200, &list); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+func bookableResourceDTO(r *model.BookableResource) *DTO.BookableResource {
+	return &DTO.BookableResource{
+		ID:       r.ID,
+		BranchID: r.BranchID,
+		Name:     r.Name,
+		Kind:     r.Kind,
+		Quantity: r.Quantity,
+	}
+}
+
+// BookableResource registers the rooms, chairs and equipment handlers
+func BookableResource(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		CreateBookableResource,
+		GetBookableResources,
+		UpdateBookableResource,
+		DeleteBookableResource,
+		AddServiceResource,
+		GetServiceResources,
+		RemoveServiceResource,
+	})
+}
@@ -123,6 +123,7 @@ func UpdateBranchById(c *fiber.Ctx) error {
 	if err := UpdateOneById(c, &branch, nil); err != nil {
 		return err
 	}
+	invalidateAvailability(c)
 
 	if err := lib.ResponseFactory(c).SendDTO(200, &branch, &DTO.BranchFull{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
@@ -145,7 +146,11 @@ func UpdateBranchById(c *fiber.Ctx) error {
 //	@Failure		400	{object}	DTO.ErrorResponse
 //	@Router			/branch/{id} [delete]
 func DeleteBranchById(c *fiber.Ctx) error {
-	return DeleteOneById(c, &model.Branch{})
+	if err := DeleteOneById(c, &model.Branch{}); err != nil {
+		return err
+	}
+	invalidateAvailability(c)
+	return nil
 }
 
 // UpdateBranchImages updates a branch's images
@@ -279,6 +284,7 @@ func CreateBranchWorkSchedule(c *fiber.Ctx) error {
 		WorkRanges: bwr,
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &bws, &DTO.BranchWorkSchedule{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -447,6 +453,7 @@ func UpdateBranchWorkRange(c *fiber.Ctx) error {
 		WorkRanges: bwr,
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &bws, &DTO.BranchWorkSchedule{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -501,6 +508,7 @@ func DeleteBranchWorkRange(c *fiber.Ctx) error {
 		WorkRanges: bwr,
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &bws, &DTO.BranchWorkSchedule{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -566,6 +574,7 @@ func AddBranchWorkRangeServices(c *fiber.Ctx) error {
 		WorkRanges: bwr,
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &bws, &DTO.BranchWorkSchedule{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -627,6 +636,7 @@ func DeleteBranchWorkRangeService(c *fiber.Ctx) error {
 		WorkRanges: bwr,
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &bws, &DTO.BranchWorkSchedule{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -718,6 +728,7 @@ func AddServiceToBranch(c *fiber.Ctx) error {
 	if err := branch.AddService(tx, &service); err != nil {
 		return err
 	}
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &branch, &DTO.BranchFull{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -766,6 +777,7 @@ func RemoveServiceFromBranch(c *fiber.Ctx) error {
 	if err := branch.RemoveService(tx, &service); err != nil {
 		return err
 	}
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &branch, &DTO.BranchFull{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -24,9 +24,10 @@ import (
 //	@Tags			Client
 //	@Accept			json
 //	@Produce		json
-//	@Param			client	body		DTO.CreateClient	true	"Client"
-//	@Success		200		{object}	DTO.Client
-//	@Failure		400		{object}	DTO.ErrorResponse
+//	@Param			X-Company-ID	header		string				false	"Company whose webhooks are notified"
+//	@Param			client			body		DTO.CreateClient	true	"Client"
+//	@Success		200				{object}	DTO.Client
+//	@Failure		400				{object}	DTO.ErrorResponse
 //	@Router			/client [post]
 func CreateClient(c *fiber.Ctx) error {
 	var err error
@@ -38,7 +39,22 @@ func CreateClient(c *fiber.Ctx) error {
 	if err != nil {
 		return err
 	}
-	if err := tx.Model(&model.Client{}).Create(&client).Error; err != nil {
+	if err := tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Client{}).Create(&client).Error; err != nil {
+			return err
+		}
+		// Clients are global. When the sign up comes from a company booking page
+		// the X-Company-ID header tells which company subscribers to notify.
+		companyID, err := uuid.Parse(c.Get(namespace.HeadersKey.Company))
+		if err != nil {
+			return nil
+		}
+		var companies int64
+		if err := tx.Model(&model.Company{}).Where("id = ?", companyID).Count(&companies).Error; err != nil || companies == 0 {
+			return err
+		}
+		return enqueueWebhookEvent(tx, companyID, model.WebhookEventClientCreated, &client, &DTO.Client{})
+	}); err != nil {
 		return err
 	}
 	if err := lib.ResponseFactory(c).SendDTO(200, &client, &DTO.Client{}); err != nil {
@@ -539,6 +555,131 @@ func VerifyClientEmail(c *fiber.Ctx) error {
 	return VerifyEmail(c, &model.Client{})
 }
 
+// CreateGuestClient starts a booking without an account
+//
+//	@Summary		Create guest client
+//	@Description	Create a guest client with name, email and phone only and send it a login code. Verifying the code at /client/guest/verify logs the guest in to book. For a guest booking again, the details given replace its stored ones only once it verifies the code.
+//	@Tags			Client
+//	@Accept			json
+//	@Produce		json
+//	@Param			guest		body		DTO.CreateGuestClient	true	"Guest"
+//	@Param			language	query		string					false	"Language for the email content"
+//	@Success		200			{object}	DTO.Client
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Router			/client/guest [post]
+func CreateGuestClient(c *fiber.Ctx) error {
+	var body DTO.CreateGuestClient
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	guest, err := model.GuestClient(tx, body.Name, body.Surname, body.Email, body.Phone)
+	if err != nil {
+		return err
+	}
+	if err := sendLoginValidationCode(c, &model.Client{}, guest.Email); err != nil {
+		return err
+	}
+
+	// The stored details of a guest booking again are only shown once it verified the email
+	res := guest.ClientMeta
+	if pending := guest.PendingDetails; pending != nil {
+		res.Name, res.Surname, res.Phone = pending.Name, pending.Surname, pending.Phone
+	}
+	if err := lib.ResponseFactory(c).SendDTO(200, &res, &DTO.Client{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// VerifyGuestClient logs a guest in with the code sent to its email
+//
+//	@Summary		Verify guest client
+//	@Description	Log a guest in with the code sent by /client/guest, verifying its email and applying the details it booked with
+//	@Tags			client/auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body	DTO.LoginByEmailCode	true	"Email and verification code"
+//	@Success		200
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/client/guest/verify [post]
+func VerifyGuestClient(c *fiber.Ctx) error {
+	var body DTO.LoginByEmailCode
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+	// Guests are found in any case of their email, as when booking
+	var guest model.Client
+	if err := tx.Select("email").Where("LOWER(email) = ?", model.NormalizeEmail(body.Email)).First(&guest).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return lib.Error.Client.NotFound
+		}
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	token, err := LoginByValidationCode(namespace.ClientKey.Name, &guest, c, guest.Email, body.Code)
+	if err != nil {
+		return err
+	}
+	if err := guest.ApplyPendingDetails(tx); err != nil {
+		return err
+	}
+	c.Response().Header.Set(namespace.HeadersKey.Auth, token)
+	return nil
+}
+
+// UpgradeGuestClient turns a guest into a client with an account
+//
+//	@Summary		Upgrade guest client
+//	@Description	Set the password of a guest, turning it into a client with an account. Its appointments are kept.
+//	@Tags			Client
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string					true	"Client ID"
+//	@Param			body			body		DTO.UpgradeGuestClient	true	"Password"
+//	@Success		200				{object}	DTO.Client
+//	@Failure		400				{object}	DTO.ErrorResponse
+//	@Router			/client/{id}/upgrade [post]
+func UpgradeGuestClient(c *fiber.Ctx) error {
+	var body DTO.UpgradeGuestClient
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	var client model.Client
+	if err := tx.Where("id = ?", c.Params("id")).First(&client).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return lib.Error.Client.NotFound
+		}
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	if err := client.UpgradeGuest(tx, body.Password); err != nil {
+		return err
+	}
+
+	if err := lib.ResponseFactory(c).SendDTO(200, &client.ClientMeta, &DTO.Client{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
 func Client(Gorm *handler.Gorm) {
 	endpoint := &middleware.Endpoint{DB: Gorm}
 	endpoint.BulkRegisterHandler([]fiber.Handler{
@@ -556,5 +697,8 @@ func Client(Gorm *handler.Gorm) {
 		DeleteClientImage,
 		SendClientVerificationCodeByEmail,
 		VerifyClientEmail,
+		CreateGuestClient,
+		VerifyGuestClient,
+		UpgradeGuestClient,
 	})
 }
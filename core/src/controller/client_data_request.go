@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	database "mynute-go/core/src/config/db"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/lib/privacy"
+	"mynute-go/core/src/middleware"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RequestClientDataExport requests an export of everything kept about a client
+//
+//	@Summary		Request client data export
+//	@Description	Queue an export of the profile, appointments and comments of the client at every company, in JSON and CSV. Download it once completed.
+//	@Tags			Client
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Client ID"
+//	@Produce		json
+//	@Success		202	{object}	DTO.ClientDataRequest
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/client/{id}/data/export [post]
+func RequestClientDataExport(c *fiber.Ctx) error {
+	return requestClientData(c, model.ClientDataExport)
+}
+
+// RequestClientDataErasure requests the erasure of a client
+//
+//	@Summary		Request client data erasure
+//	@Description	Queue the anonymization of the client at the platform and at every company. Appointments are kept for accounting, without anything identifying the client. The account is closed once done.
+//	@Tags			Client
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Client ID"
+//	@Produce		json
+//	@Success		202	{object}	DTO.ClientDataRequest
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/client/{id}/data/erasure [post]
+func RequestClientDataErasure(c *fiber.Ctx) error {
+	return requestClientData(c, model.ClientDataErasure)
+}
+
+func requestClientData(c *fiber.Ctx, kind model.ClientDataRequestKind) (err error) {
+	clientID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid id"))
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	request, err := privacy.Request(tx, clientID, kind, auditFromRequest(c))
+	if err != nil {
+		return err
+	}
+	if err := lib.ResponseFactory(c).SendDTO(fiber.StatusAccepted, request, &DTO.ClientDataRequest{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetClientDataRequests lists the data requests of a client
+//
+//	@Summary		List client data requests
+//	@Description	Data export and erasure requests of the client, newest first, with who asked and when they were done
+//	@Tags			Client
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Client ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.ClientDataRequestList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/client/{id}/data/requests [get]
+func GetClientDataRequests(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	var requests []model.ClientDataRequest
+	if err := tx.Omit("export_json", "export_csv").Where("client_id = ?", c.Params("id")).Order("created_at DESC").Find(&requests).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	list := struct {
+		DataRequests []model.ClientDataRequest `json:"data_requests"`
+	}{requests}
+	if list.DataRequests == nil {
+		list.DataRequests = []model.ClientDataRequest{}
+	}
+	if err := lib.ResponseFactory(c).SendDTO(200, &list, &DTO.ClientDataRequestList{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// DownloadClientDataExport downloads a completed data export of a client
+//
+//	@Summary		Download client data export
+//	@Description	Download a completed data export as JSON or CSV, until it expires
+//	@Tags			Client
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Client ID"
+//	@Param			request_id		path		string	true	"Data request ID"
+//	@Param			format			query		string	false	"File format"	Enums(json, csv)	default(json)
+//	@Produce		json
+//	@Produce		text/csv
+//	@Success		200
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Failure		409	{object}	DTO.ErrorResponse
+//	@Failure		410	{object}	DTO.ErrorResponse
+//	@Router			/client/{id}/data/requests/{request_id}/download [get]
+func DownloadClientDataExport(c *fiber.Ctx) error {
+	format := c.Query("format", "json")
+	if format != "json" && format != "csv" {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("format must be json or csv"))
+	}
+
+	requestID, err := uuid.Parse(c.Params("request_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid request_id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	var request model.ClientDataRequest
+	if err := tx.Where("id = ? AND client_id = ? AND kind = ?", requestID, c.Params("id"), model.ClientDataExport).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return lib.Error.Client.DataRequestNotFound
+		}
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	if request.Status != model.ClientDataRequestCompleted {
+		return lib.Error.Client.DataExportNotReady
+	}
+	if !request.Downloadable(time.Now()) {
+		return lib.Error.Client.DataExportExpired
+	}
+
+	data := []byte(request.ExportJSON)
+	if format == "csv" {
+		data = []byte(request.ExportCSV)
+	}
+	c.Type(format)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="client-data-%s.%s"`, request.CreatedAt.UTC().Format("20060102"), format))
+	return c.Status(200).Send(data)
+}
+
+// ClientDataRequest registers the client data request controllers
+func ClientDataRequest(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		RequestClientDataExport,
+		RequestClientDataErasure,
+		GetClientDataRequests,
+		DownloadClientDataExport,
+	})
+}
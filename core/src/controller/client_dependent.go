@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/middleware"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateClientDependent adds a dependent to a client
+//
+//	@Summary		Create client dependent
+//	@Description	Add someone the client books for, like a child or an elderly relative. Appointments booked for them set dependent_id.
+//	@Tags			Client
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string						true	"Client ID"
+//	@Param			dependent		body		DTO.CreateClientDependent	true	"Dependent"
+//	@Success		200				{object}	DTO.ClientDependent
+//	@Failure		400				{object}	DTO.ErrorResponse
+//	@Router			/client/{id}/dependent [post]
+func CreateClientDependent(c *fiber.Ctx) error {
+	clientID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid id"))
+	}
+	var body DTO.CreateClientDependent
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	dependent := model.ClientDependent{
+		ClientID:  clientID,
+		Name:      body.Name,
+		BirthDate: dateOnly(body.BirthDate),
+		Notes:     body.Notes,
+	}
+	if err := dependent.Validate(); err != nil {
+		return err
+	}
+	if err := tx.Create(&dependent).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).SendDTO(200, &dependent, &DTO.ClientDependent{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetClientDependents lists the dependents of a client
+//
+//	@Summary		List client dependents
+//	@Description	People the client books for
+//	@Tags			Client
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Client ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.ClientDependentList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/client/{id}/dependents [get]
+func GetClientDependents(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	list := struct {
+		Dependents []model.ClientDependent `json:"dependents"`
+	}{[]model.ClientDependent{}}
+	if err := tx.Where("client_id = ?", c.Params("id")).Order("name").Find(&list.Dependents).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	if err := lib.ResponseFactory(c).SendDTO(200, &list, &DTO.ClientDependentList{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// UpdateClientDependent updates a dependent of a client
+//
+//	@Summary		Update client dependent
+//	@Description	Update the name, birth date or notes of a dependent
+//	@Tags			Client
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string						true	"Client ID"
+//	@Param			dependent_id	path		string						true	"Dependent ID"
+//	@Param			dependent		body		DTO.UpdateClientDependent	true	"Dependent"
+//	@Success		200				{object}	DTO.ClientDependent
+//	@Failure		400				{object}	DTO.ErrorResponse
+//	@Router			/client/{id}/dependent/{dependent_id} [patch]
+func UpdateClientDependent(c *fiber.Ctx) error {
+	var body DTO.UpdateClientDependent
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	dependent, err := loadClientDependent(c, tx)
+	if err != nil {
+		return err
+	}
+	// Applied through a map so that empty notes are not skipped as a zero value.
+	changes := map[string]any{}
+	if body.Name != nil {
+		dependent.Name = *body.Name
+		changes["name"] = dependent.Name
+	}
+	if body.BirthDate != nil {
+		dependent.BirthDate = dateOnly(body.BirthDate)
+		changes["birth_date"] = dependent.BirthDate
+	}
+	if body.Notes != nil {
+		dependent.Notes = *body.Notes
+		changes["notes"] = dependent.Notes
+	}
+	if len(changes) > 0 {
+		if err := dependent.Validate(); err != nil {
+			return err
+		}
+		if err := tx.Model(dependent).Updates(changes).Error; err != nil {
+			return lib.Error.General.UpdatedError.WithError(err)
+		}
+	}
+
+	if err := lib.ResponseFactory(c).SendDTO(200, dependent, &DTO.ClientDependent{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// DeleteClientDependent removes a dependent of a client
+//
+//	@Summary		Delete client dependent
+//	@Description	Remove a dependent. The appointments booked for them are kept.
+//	@Tags			Client
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header	string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			id				path	string	true	"Client ID"
+//	@Param			dependent_id	path	string	true	"Dependent ID"
+//	@Success		200
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/client/{id}/dependent/{dependent_id} [delete]
+func DeleteClientDependent(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	dependent, err := loadClientDependent(c, tx)
+	if err != nil {
+		return err
+	}
+	if err := tx.Delete(dependent).Error; err != nil {
+		return lib.Error.General.DeletedError.WithError(err)
+	}
+	return nil
+}
+
+// loadClientDependent loads the dependent of the path, if it belongs to the client of the path.
+func loadClientDependent(c *fiber.Ctx, tx *gorm.DB) (*model.ClientDependent, error) {
+	dependentID, err := uuid.Parse(c.Params("dependent_id"))
+	if err != nil {
+		return nil, lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid dependent_id"))
+	}
+	var dependent model.ClientDependent
+	if err := tx.Where("id = ? AND client_id = ?", dependentID, c.Params("id")).First(&dependent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, lib.Error.Client.DependentNotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	return &dependent, nil
+}
+
+// dateOnly drops the time of day of a date sent as a timestamp.
+func dateOnly(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return &d
+}
+
+// ClientDependent registers the client dependent controllers
+func ClientDependent(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		CreateClientDependent,
+		GetClientDependents,
+		UpdateClientDependent,
+		DeleteClientDependent,
+	})
+}
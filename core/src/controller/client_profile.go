@@ -0,0 +1,327 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/middleware"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetClientProfile returns what the company keeps about a client
+//
+//	@Summary		Get client profile
+//	@Description	Get the tags, private notes, birthday, preferences, marketing consent and lifetime stats of a client at the company
+//	@Tags			Client
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			client_id		path		string	true	"Client ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.ClientProfile
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/client/{client_id}/profile [get]
+func GetClientProfile(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	client, profile, err := loadClientProfile(c, tx)
+	if err != nil {
+		return err
+	}
+
+	dto, err := clientProfileDTO(tx, profile, client)
+	if err != nil {
+		return err
+	}
+	if err := lib.ResponseFactory(c).Send(200, dto); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// UpdateClientProfile updates what the company keeps about a client
+//
+//	@Summary		Update client profile
+//	@Description	Update the tags, private notes, birthday, preferences or marketing consent of a client at the company
+//	@Tags			Client
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id		path		string					true	"Client ID"
+//	@Param			profile			body		DTO.UpdateClientProfile	true	"Profile"
+//	@Success		200				{object}	DTO.ClientProfile
+//	@Failure		400				{object}	DTO.ErrorResponse
+//	@Router			/client/{client_id}/profile [patch]
+func UpdateClientProfile(c *fiber.Ctx) error {
+	var body DTO.UpdateClientProfile
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	client, profile, err := loadClientProfile(c, tx)
+	if err != nil {
+		return err
+	}
+
+	// Applied through a map so that false and empty values are not skipped as zero values.
+	changes := map[string]any{}
+	for column, field := range map[string]struct{ from, to *string }{
+		"name":    {body.Name, &profile.Name},
+		"surname": {body.Surname, &profile.Surname},
+		"phone":   {body.Phone, &profile.Phone},
+	} {
+		if field.from != nil {
+			*field.to = strings.TrimSpace(*field.from)
+			changes[column] = *field.to
+		}
+	}
+	if body.Tags != nil {
+		profile.Tags = model.NormalizeTags(*body.Tags)
+		changes["tags"] = profile.Tags
+	}
+	if body.Notes != nil {
+		profile.Notes = *body.Notes
+		changes["notes"] = profile.Notes
+	}
+	if body.Preferences != nil {
+		profile.Preferences = *body.Preferences
+		changes["preferences"] = profile.Preferences
+	}
+	if body.Birthday != nil {
+		birthday := time.Date(body.Birthday.Year(), body.Birthday.Month(), body.Birthday.Day(), 0, 0, 0, 0, time.UTC)
+		profile.Birthday = &birthday
+		changes["birthday"] = profile.Birthday
+	}
+	if body.MarketingConsent != nil && *body.MarketingConsent != profile.MarketingConsent {
+		now := time.Now()
+		profile.MarketingConsent = *body.MarketingConsent
+		profile.MarketingConsentAt = &now
+		changes["marketing_consent"] = profile.MarketingConsent
+		changes["marketing_consent_at"] = profile.MarketingConsentAt
+	}
+	if len(changes) > 0 {
+		if err := profile.Validate(); err != nil {
+			return err
+		}
+		if err := tx.Model(profile).Updates(changes).Error; err != nil {
+			return lib.Error.General.UpdatedError.WithError(err)
+		}
+	}
+
+	dto, err := clientProfileDTO(tx, profile, client)
+	if err != nil {
+		return err
+	}
+	if err := lib.ResponseFactory(c).Send(200, dto); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetCompanyClients searches the clients of a company
+//
+//	@Summary		List company clients
+//	@Description	Paginated clients of the company, searchable by name, email or phone and filterable by tag and marketing consent
+//	@Tags			Client
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token		header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID		header		string	true	"X-Company-ID"
+//	@Failure		401					{object}	nil
+//	@Param			company_id			path		string	true	"Company ID"
+//	@Param			search				query		string	false	"Part of the name, surname, email or phone, the ones the company keeps included"
+//	@Param			tag					query		string	false	"Only clients with every tag, comma separated"
+//	@Param			marketing_consent	query		bool	false	"Only clients that did or did not consent to marketing"
+//	@Param			page				query		int		false	"Page number"				default(1)
+//	@Param			page_size			query		int		false	"Number of items per page"	default(10)
+//	@Produce		json
+//	@Success		200	{object}	DTO.ClientProfileList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/clients [get]
+func GetCompanyClients(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	page := c.QueryInt("page", 1)
+	pageSize := c.QueryInt("page_size", 10)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	clientsTable := model.Client{}.TableName()
+	query := tx.Model(&model.ClientProfile{}).
+		Joins(fmt.Sprintf("JOIN %s cl ON cl.id = %s.client_id AND cl.deleted_at IS NULL", clientsTable, model.ClientProfileTableName)).
+		Where(model.ClientProfileTableName+".company_id = ?", companyID)
+	if search := strings.TrimSpace(c.Query("search")); search != "" {
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(search) + "%"
+		query = query.Where(fmt.Sprintf("((cl.name || ' ' || cl.surname) ILIKE @pattern OR cl.email ILIKE @pattern OR cl.phone ILIKE @pattern OR (%[1]s.name || ' ' || %[1]s.surname) ILIKE @pattern OR %[1]s.phone ILIKE @pattern)", model.ClientProfileTableName), map[string]any{"pattern": pattern})
+	}
+	if tags := model.NormalizeTags(strings.Split(c.Query("tag"), ",")); len(tags) > 0 {
+		filter, err := json.Marshal(tags)
+		if err != nil {
+			return lib.Error.General.InternalError.WithError(err)
+		}
+		query = query.Where(model.ClientProfileTableName+".tags @> ?::jsonb", string(filter))
+	}
+	if consent := c.Query("marketing_consent"); consent != "" {
+		query = query.Where(model.ClientProfileTableName+".marketing_consent = ?", c.QueryBool("marketing_consent"))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	var profiles []model.ClientProfile
+	if err := query.Select(model.ClientProfileTableName + ".*").Order("cl.name, cl.surname").Offset((page - 1) * pageSize).Limit(pageSize).Find(&profiles).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	clientIDs := make([]uuid.UUID, len(profiles))
+	for i := range profiles {
+		clientIDs[i] = profiles[i].ClientID
+	}
+	var clients []model.ClientMeta
+	if len(clientIDs) > 0 {
+		if err := tx.Model(&model.Client{}).Where("id IN ?", clientIDs).Find(&clients).Error; err != nil {
+			return lib.Error.General.InternalError.WithError(err)
+		}
+	}
+	byID := make(map[uuid.UUID]*model.ClientMeta, len(clients))
+	for i := range clients {
+		byID[clients[i].ID] = &clients[i]
+	}
+	stats, err := model.LoadClientStats(tx, companyID, clientIDs, time.Now())
+	if err != nil {
+		return err
+	}
+
+	list := DTO.ClientProfileList{
+		Clients:    make([]DTO.ClientProfile, 0, len(profiles)),
+		TotalCount: int(total),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	for i := range profiles {
+		client, ok := byID[profiles[i].ClientID]
+		if !ok {
+			continue
+		}
+		list.Clients = append(list.Clients, *buildClientProfileDTO(&profiles[i], client, stats[profiles[i].ClientID]))
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, &list); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// loadClientProfile loads the client of the path and its profile at the company of the
+// request, creating the profile when the company has none yet.
+func loadClientProfile(c *fiber.Ctx, tx *gorm.DB) (*model.ClientMeta, *model.ClientProfile, error) {
+	companyID, err := uuid.Parse(c.Get(namespace.HeadersKey.Company))
+	if err != nil {
+		return nil, nil, lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid X-Company-ID"))
+	}
+	clientID, err := uuid.Parse(c.Params("client_id"))
+	if err != nil {
+		return nil, nil, lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid client_id"))
+	}
+
+	var client model.ClientMeta
+	if err := tx.Model(&model.Client{}).Where("id = ?", clientID).First(&client).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, lib.Error.Client.NotFound
+		}
+		return nil, nil, lib.Error.General.InternalError.WithError(err)
+	}
+	profile, err := model.EnsureClientProfile(tx, companyID, clientID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &client, profile, nil
+}
+
+func clientProfileDTO(tx *gorm.DB, profile *model.ClientProfile, client *model.ClientMeta) (*DTO.ClientProfile, error) {
+	stats, err := model.LoadClientStats(tx, profile.CompanyID, []uuid.UUID{profile.ClientID}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return buildClientProfileDTO(profile, client, stats[profile.ClientID]), nil
+}
+
+func buildClientProfileDTO(p *model.ClientProfile, client *model.ClientMeta, stats model.ClientStats) *DTO.ClientProfile {
+	tags := []string(p.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+	return &DTO.ClientProfile{
+		ID: p.ID,
+		Client: DTO.ClientBasicInfo{
+			ID:      client.ID,
+			Name:    client.Name,
+			Surname: client.Surname,
+			Email:   client.Email,
+			Phone:   client.Phone,
+		},
+		Name:               p.Name,
+		Surname:            p.Surname,
+		Phone:              p.Phone,
+		Tags:               tags,
+		Notes:              p.Notes,
+		Preferences:        p.Preferences,
+		Birthday:           p.Birthday,
+		MarketingConsent:   p.MarketingConsent,
+		MarketingConsentAt: p.MarketingConsentAt,
+		Stats: DTO.ClientStats{
+			Appointments:      stats.Appointments,
+			Fulfilled:         stats.Fulfilled,
+			Cancelled:         stats.Cancelled,
+			TotalSpent:        stats.TotalSpent,
+			FirstVisitAt:      stats.FirstVisitAt,
+			LastVisitAt:       stats.LastVisitAt,
+			NextAppointmentAt: stats.NextAppointmentAt,
+		},
+	}
+}
+
+// ClientProfile registers the client profile controllers
+func ClientProfile(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		GetClientProfile,
+		UpdateClientProfile,
+		GetCompanyClients,
+	})
+}
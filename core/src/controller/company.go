@@ -322,6 +322,7 @@ func UpdateCompanyById(c *fiber.Ctx) error {
 	if err := UpdateOneById(c, &company, nil); err != nil {
 		return err
 	}
+	invalidateAvailability(c)
 
 	tx, err := lib.Session(c)
 	if err != nil {
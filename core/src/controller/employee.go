@@ -122,9 +122,12 @@ func GetEmployeeByEmail(c *fiber.Ctx) error {
 //	@Router			/employee/{id} [patch]
 func UpdateEmployeeById(c *fiber.Ctx) error {
 	var employee model.Employee
-	if err := UpdateOneById(c, &employee, nil); err != nil {
+	if err := UpdateOneByIdThen(c, &employee, nil, func(tx *gorm.DB) error {
+		return enqueueWebhookEvent(tx, employee.CompanyID, model.WebhookEventEmployeeUpdated, &employee, &DTO.EmployeeFull{})
+	}); err != nil {
 		return err
 	}
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &employee, &DTO.EmployeeFull{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -146,7 +149,11 @@ func UpdateEmployeeById(c *fiber.Ctx) error {
 //	@Failure		400	{object}	DTO.ErrorResponse
 //	@Router			/employee/{id} [delete]
 func DeleteEmployeeById(c *fiber.Ctx) error {
-	return DeleteOneById(c, &model.Employee{})
+	if err := DeleteOneById(c, &model.Employee{}); err != nil {
+		return err
+	}
+	invalidateAvailability(c)
+	return nil
 }
 
 // LoginEmployeeByPassword logs an employee in
@@ -372,6 +379,7 @@ func CreateEmployeeWorkSchedule(c *fiber.Ctx) error {
 		WorkRanges: ewr,
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &ews, &DTO.EmployeeWorkSchedule{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -518,6 +526,7 @@ func DeleteEmployeeWorkRange(c *fiber.Ctx) error {
 		WorkRanges: ewr,
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &ews, &DTO.EmployeeWorkSchedule{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -610,6 +619,7 @@ func UpdateEmployeeWorkRange(c *fiber.Ctx) error {
 		WorkRanges: ewr,
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &ews, &DTO.EmployeeWorkSchedule{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -680,6 +690,7 @@ func AddEmployeeWorkRangeServices(c *fiber.Ctx) error {
 		WorkRanges: ewr,
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &ews, &DTO.EmployeeWorkSchedule{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -745,6 +756,7 @@ func DeleteEmployeeWorkRangeService(c *fiber.Ctx) error {
 		WorkRanges: ewr,
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &ews, &DTO.EmployeeWorkSchedule{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -792,6 +804,7 @@ func AddServiceToEmployee(c *fiber.Ctx) error {
 		return err
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &employee, &DTO.EmployeeFull{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -837,6 +850,7 @@ func RemoveServiceFromEmployee(c *fiber.Ctx) error {
 		return err
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &employee, &DTO.EmployeeFull{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -882,6 +896,7 @@ func AddBranchToEmployee(c *fiber.Ctx) error {
 		return err
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &employee, &DTO.EmployeeFull{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -927,6 +942,7 @@ func RemoveBranchFromEmployee(c *fiber.Ctx) error {
 		return err
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &employee, &DTO.EmployeeFull{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -0,0 +1,375 @@
+package controller
+
+import (
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	database "mynute-go/core/src/config/db"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/lib/invitation"
+	"mynute-go/core/src/middleware"
+	"mynute-go/core/src/service/availability"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InviteEmployee invites an employee to join the company
+//
+//	@Summary		Invite employee
+//	@Description	Create the employee invited, with the roles, branches and services given, and email them a link to set their password. Invited employees can not log in until they accept. Inviting again an employee still invited sends a new link.
+//	@Tags			EmployeeInvitation
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			company_id		path		string				true	"Company ID"
+//	@Param			email_language	query		string				false	"Language of the email"	default(en)
+//	@Param			invitation		body		DTO.InviteEmployee	true	"Invitation"
+//	@Success		200				{object}	DTO.EmployeeInvitation
+//	@Failure		400				{object}	DTO.ErrorResponse
+//	@Failure		409				{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/employee_invitation [post]
+func InviteEmployee(c *fiber.Ctx) (err error) {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+	var body DTO.InviteEmployee
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	invitee := invitation.Invitee{
+		Email:      body.Email,
+		Name:       body.Name,
+		Surname:    body.Surname,
+		Phone:      body.Phone,
+		TimeZone:   body.TimeZone,
+		RoleIDs:    body.RoleIDs,
+		BranchIDs:  body.BranchIDs,
+		ServiceIDs: body.ServiceIDs,
+		Language:   c.Query("email_language", "en"),
+	}
+	_, inv, err := invitation.Invite(tx, companyID, invitee, auditFromRequest(c).ActorID, invitationBaseURL(c), time.Now())
+	if err != nil {
+		return err
+	}
+	database.AfterCommit(tx, func() { availability.Invalidate(companyID) })
+
+	if err = lib.ResponseFactory(c).Send(200, employeeInvitationDTO(inv)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetEmployeeInvitation gets the last invitation of an employee
+//
+//	@Summary		Get employee invitation
+//	@Description	The last invitation sent to the employee. Pending invitations past their expiry are shown as EXPIRED.
+//	@Tags			EmployeeInvitation
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			employee_id		path		string	true	"Employee ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.EmployeeInvitation
+//	@Failure		404	{object}	DTO.ErrorResponse
+//	@Router			/employee/{employee_id}/invitation [get]
+func GetEmployeeInvitation(c *fiber.Ctx) error {
+	employeeID, err := uuid.Parse(c.Params("employee_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid employee_id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	var inv model.EmployeeInvitation
+	if err := tx.Where("employee_id = ?", employeeID).Order("created_at DESC").First(&inv).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return lib.Error.Invitation.NotFound
+		}
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	if err := lib.ResponseFactory(c).Send(200, employeeInvitationDTO(&inv)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// ResendEmployeeInvitation emails the pending invitation of an employee again
+//
+//	@Summary		Resend employee invitation
+//	@Description	Email the pending invitation of the employee again with a new link, valid for another 7 days. The previous link stops working.
+//	@Tags			EmployeeInvitation
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			employee_id		path		string	true	"Employee ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.EmployeeInvitation
+//	@Failure		409	{object}	DTO.ErrorResponse
+//	@Failure		429	{object}	DTO.ErrorResponse
+//	@Router			/employee/{employee_id}/invitation/resend [post]
+func ResendEmployeeInvitation(c *fiber.Ctx) (err error) {
+	employeeID, err := uuid.Parse(c.Params("employee_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid employee_id"))
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	inv, err := invitation.Resend(tx, employeeID, auditFromRequest(c).ActorID, invitationBaseURL(c), time.Now())
+	if err != nil {
+		return err
+	}
+	if err = lib.ResponseFactory(c).Send(200, employeeInvitationDTO(inv)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// RevokeEmployeeInvitation revokes the pending invitation of an employee
+//
+//	@Summary		Revoke employee invitation
+//	@Description	Revoke the pending invitation of the employee, whose link stops working. The employee stays invited until invited again or deleted.
+//	@Tags			EmployeeInvitation
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			employee_id		path		string	true	"Employee ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.EmployeeInvitation
+//	@Failure		409	{object}	DTO.ErrorResponse
+//	@Router			/employee/{employee_id}/invitation [delete]
+func RevokeEmployeeInvitation(c *fiber.Ctx) (err error) {
+	employeeID, err := uuid.Parse(c.Params("employee_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid employee_id"))
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	inv, err := invitation.Revoke(tx, employeeID, time.Now())
+	if err != nil {
+		return err
+	}
+	if err = lib.ResponseFactory(c).Send(200, employeeInvitationDTO(inv)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// UpdateEmployeeStatus suspends or reactivates an employee
+//
+//	@Summary		Update employee status
+//	@Description	Suspend an active employee, who can no longer log in nor be booked, or reactivate a suspended one. Invited employees become active by accepting their invitation.
+//	@Tags			EmployeeInvitation
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			employee_id		path		string						true	"Employee ID"
+//	@Param			status			body		DTO.UpdateEmployeeStatus	true	"Status"
+//	@Success		200				{object}	DTO.EmployeeFull
+//	@Failure		400				{object}	DTO.ErrorResponse
+//	@Failure		403				{object}	DTO.ErrorResponse
+//	@Router			/employee/{employee_id}/status [patch]
+func UpdateEmployeeStatus(c *fiber.Ctx) (err error) {
+	employeeID, err := uuid.Parse(c.Params("employee_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid employee_id"))
+	}
+	var body DTO.UpdateEmployeeStatus
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+	if actorID := auditFromRequest(c).ActorID; actorID != nil && *actorID == employeeID {
+		return lib.Error.Employee.InvalidStatus.WithError(fmt.Errorf("employees can not change their own status"))
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	var employee model.Employee
+	if err = database.LockForUpdate(tx, &employee, "id", employeeID.String()); err != nil {
+		return err
+	}
+	if err = invitation.SetStatus(tx, &employee, strings.ToUpper(body.Status)); err != nil {
+		return err
+	}
+	database.AfterCommit(tx, func() { availability.Invalidate(employee.CompanyID) })
+
+	if err = lib.ResponseFactory(c).SendDTO(200, &employee, &DTO.EmployeeFull{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetEmployeeInvitationByToken gets the invitation an employee was emailed a link to
+//
+//	@Summary		Get invitation by token
+//	@Description	The invitation the employee was emailed a link to accept. The link stops working once accepted, revoked or sent again.
+//	@Tags			EmployeeInvitation
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			token			path		string	true	"Token of the emailed link"
+//	@Produce		json
+//	@Success		200	{object}	DTO.EmployeeInvitationDetails
+//	@Failure		404	{object}	DTO.ErrorResponse
+//	@Failure		410	{object}	DTO.ErrorResponse
+//	@Router			/employee_invitation/{token} [get]
+func GetEmployeeInvitationByToken(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	inv, err := invitation.ByToken(tx, c.Params("token"))
+	if err != nil {
+		return err
+	}
+	if inv.Expired(time.Now()) {
+		return lib.Error.Invitation.Expired
+	}
+	res, err := employeeInvitationDetailsDTO(tx, inv)
+	if err != nil {
+		return err
+	}
+	if err := lib.ResponseFactory(c).Send(200, res); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// AcceptEmployeeInvitation accepts an invitation through an emailed link
+//
+//	@Summary		Accept employee invitation
+//	@Description	Set the password of the invited employee, whose account becomes active and verified. The employee can log in with it right after.
+//	@Tags			EmployeeInvitation
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Accept			json
+//	@Produce		json
+//	@Param			token		path		string							true	"Token of the emailed link"
+//	@Param			password	body		DTO.AcceptEmployeeInvitation	true	"Password"
+//	@Success		200			{object}	DTO.EmployeeInvitationDetails
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Failure		404			{object}	DTO.ErrorResponse
+//	@Failure		410			{object}	DTO.ErrorResponse
+//	@Router			/employee_invitation/{token} [post]
+func AcceptEmployeeInvitation(c *fiber.Ctx) (err error) {
+	var body DTO.AcceptEmployeeInvitation
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	inv, err := invitation.ByToken(tx, c.Params("token"))
+	if err != nil {
+		return err
+	}
+	if err = database.LockForUpdate(tx, inv, "id", inv.ID.String()); err != nil {
+		return err
+	}
+	if _, err = invitation.Accept(tx, inv, body.Password, time.Now()); err != nil {
+		return err
+	}
+
+	res, err := employeeInvitationDetailsDTO(tx, inv)
+	if err != nil {
+		return err
+	}
+	if err = lib.ResponseFactory(c).Send(200, res); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// invitationBaseURL is where the frontend accepting invitations is served.
+func invitationBaseURL(c *fiber.Ctx) string {
+	return fmt.Sprintf("%s://%s", c.Protocol(), c.Hostname())
+}
+
+func employeeInvitationDTO(inv *model.EmployeeInvitation) DTO.EmployeeInvitation {
+	return DTO.EmployeeInvitation{
+		ID:          inv.ID,
+		CompanyID:   inv.CompanyID,
+		EmployeeID:  inv.EmployeeID,
+		Email:       inv.Email,
+		Status:      inv.DisplayStatus(time.Now()),
+		ExpiresAt:   inv.ExpiresAt,
+		SentCount:   inv.SentCount,
+		LastSentAt:  inv.LastSentAt,
+		InvitedByID: inv.InvitedByID,
+		AcceptedAt:  inv.AcceptedAt,
+		RevokedAt:   inv.RevokedAt,
+		CreatedAt:   inv.CreatedAt,
+	}
+}
+
+// employeeInvitationDetailsDTO describes the invitation to the employee invited.
+func employeeInvitationDetailsDTO(tx *gorm.DB, inv *model.EmployeeInvitation) (*DTO.EmployeeInvitationDetails, error) {
+	var company model.Company
+	if err := tx.Where("id = ?", inv.CompanyID).First(&company).Error; err != nil {
+		return nil, lib.Error.Company.NotFound.WithError(err)
+	}
+	var employee model.Employee
+	if err := tx.Select("id", "name", "surname").Where("id = ?", inv.EmployeeID).First(&employee).Error; err != nil {
+		return nil, lib.Error.Employee.NotFound.WithError(err)
+	}
+	return &DTO.EmployeeInvitationDetails{
+		CompanyName:  company.TradeName,
+		EmployeeName: strings.TrimSpace(employee.Name + " " + employee.Surname),
+		Email:        inv.Email,
+		ExpiresAt:    inv.ExpiresAt,
+	}, nil
+}
+
+// EmployeeInvitation registers the employee invitation controllers
+func EmployeeInvitation(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		InviteEmployee,
+		GetEmployeeInvitation,
+		ResendEmployeeInvitation,
+		RevokeEmployeeInvitation,
+		UpdateEmployeeStatus,
+		GetEmployeeInvitationByToken,
+		AcceptEmployeeInvitation,
+	})
+}
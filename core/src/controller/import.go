@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	DTO "mynute-go/core/src/config/api/dto"
+	database "mynute-go/core/src/config/db"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/lib/importer"
+	"mynute-go/core/src/middleware"
+	"mynute-go/core/src/service/availability"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ImportRecords imports services, employees or clients from a spreadsheet
+//
+//	@Summary		Import records
+//	@Description	Create or update services, employees or clients from a CSV or XLSX file whose first row holds the column headers.
+//	@Description	Services are matched by name and have the columns name, description, price, duration, currency and branches.
+//	@Description	Employees are matched by email then phone and have the columns name, surname, email, phone, time_zone, password, branches and services.
+//	@Description	Clients are matched by email then phone and have the columns name, surname, email, phone, tags, notes, preferences, birthday and marketing_consent.
+//	@Description	Columns listing several names separate them with "|". Prices are amounts such as 150.00. Invalid rows are skipped and reported, the others are saved unless dry_run is set.
+//	@Tags			Import
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			company_id		path		string	true	"Company ID"
+//	@Param			kind			path		string	true	"services, employees or clients"
+//	@Param			dry_run			query		bool	false	"Validate every row without saving any"
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			file	formData	file	true	"CSV or XLSX file"
+//	@Success		200		{object}	DTO.ImportResult
+//	@Failure		400		{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/import/{kind} [post]
+func ImportRecords(c *fiber.Ctx) (err error) {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+	kind := c.Params("kind")
+	dryRun := c.QueryBool("dry_run")
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return lib.Error.Import.InvalidFile.WithError(fmt.Errorf("the file field is required"))
+	}
+	file, err := header.Open()
+	if err != nil {
+		return lib.Error.Import.InvalidFile.WithError(err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return lib.Error.Import.InvalidFile.WithError(err)
+	}
+	rows, err := importer.Read(header.Filename, data)
+	if err != nil {
+		return lib.Error.Import.InvalidFile.WithError(err)
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	result, err := importer.Run(tx, companyID, kind, rows, dryRun)
+	if err != nil {
+		return err
+	}
+	if !dryRun && kind != importer.KindClients {
+		database.AfterCommit(tx, func() { availability.Invalidate(companyID) })
+	}
+
+	out := DTO.ImportResult{
+		Kind:    result.Kind,
+		DryRun:  result.DryRun,
+		Created: result.Created,
+		Updated: result.Updated,
+		Failed:  result.Failed,
+		Rows:    make([]DTO.ImportRow, 0, len(result.Rows)),
+	}
+	for _, row := range result.Rows {
+		out.Rows = append(out.Rows, DTO.ImportRow{Line: row.Line, Action: row.Action, ID: row.ID, Errors: row.Errors})
+	}
+	if err := lib.ResponseFactory(c).Send(200, &out); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// Import registers the bulk import controllers
+func Import(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		ImportRecords,
+	})
+}
@@ -1,17 +1,19 @@
 package controller
 
 import (
-	"context"
 	"fmt"
-	"log"
 	DTO "mynute-go/core/src/config/api/dto"
 	mJSON "mynute-go/core/src/config/db/model/json"
+	"mynute-go/core/src/config/namespace"
 	"mynute-go/core/src/lib"
 	"mynute-go/core/src/lib/email"
 	"mynute-go/core/src/service"
 	"reflect"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 func Create(c *fiber.Ctx, model any) error {
@@ -24,6 +26,46 @@ func Create(c *fiber.Ctx, model any) error {
 	return nil
 }
 
+// CreateThen works like Create and runs then in the same transaction right after
+// the record is stored. Used to record outbox messages atomically with the record.
+func CreateThen(c *fiber.Ctx, model any, then func(tx *gorm.DB) error) error {
+	var err error
+	Service := service.New(c)
+	defer func() { Service.DeferDB(err) }()
+	if err = Service.SetModel(model).Create().Then(then).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
+	return nil
+}
+
+// CreatePreparedThen works like CreateThen and runs prepare on the model parsed from
+// the body before it is stored.
+func CreatePreparedThen(c *fiber.Ctx, model any, prepare func() error, then func(tx *gorm.DB) error) error {
+	var err error
+	Service := service.New(c)
+	defer func() { Service.DeferDB(err) }()
+	if err = Service.SetModel(model).CreatePrepared(prepare).Then(then).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
+	return nil
+}
+
+// auditFromRequest tells who is making the change and through which request,
+// to be attached to the session with model.WithAudit.
+func auditFromRequest(c *fiber.Ctx) mJSON.Audit {
+	audit := mJSON.Audit{
+		Endpoint: c.Method() + " " + c.Route().Path,
+		IP:       c.IP(),
+		Reason:   c.Query("reason"),
+	}
+	if claims, ok := c.Locals(namespace.RequestKey.Auth_Claims).(*DTO.Claims); ok && claims.ID != uuid.Nil {
+		id := claims.ID
+		audit.ActorID = &id
+		audit.ActorType = claims.Type
+	}
+	return audit
+}
+
 func GetOneBy(param string, c *fiber.Ctx, model any, nested_preload *[]string, do_not_load *[]string) error {
 	var err error
 	Service := service.New(c)
@@ -48,6 +90,18 @@ func UpdateOneById(c *fiber.Ctx, model any, nested_preload *[]string) error {
 	return nil
 }
 
+// UpdateOneByIdThen works like UpdateOneById and runs then in the same transaction
+// right after the record is updated.
+func UpdateOneByIdThen(c *fiber.Ctx, model any, nested_preload *[]string, then func(tx *gorm.DB) error) error {
+	var err error
+	Service := service.New(c)
+	defer func() { Service.DeferDB(err) }()
+	if err = Service.SetModel(model).SetNestedPreload(nested_preload).UpdateOneById().Then(then).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 func DeleteOneById(c *fiber.Ctx, model any) error {
 	var err error
 	Service := service.New(c)
@@ -74,11 +128,20 @@ func LoginByEmailCode(user_type string, model any, c *fiber.Ctx) (string, error)
 	return token, err
 }
 
-func ResetLoginvalidationCode(c *fiber.Ctx, user_email string, model any) (string, error) {
+func LoginByVerificationCode(user_type string, model any, c *fiber.Ctx, email, code string) (string, error) {
+	var err error
+	Service := service.New(c)
+	defer func() { Service.DeferDB(err) }()
+	token, err := Service.SetModel(model).LoginByVerificationCode(user_type, email, code)
+	return token, err
+}
+
+func LoginByValidationCode(user_type string, model any, c *fiber.Ctx, email, code string) (string, error) {
 	var err error
 	Service := service.New(c)
 	defer func() { Service.DeferDB(err) }()
-	return Service.SetModel(model).ResetLoginCodeByEmail(user_email)
+	token, err := Service.SetModel(model).LoginByValidationCode(user_type, email, code)
+	return token, err
 }
 
 func SendLoginValidationCodeByEmail(c *fiber.Ctx, model any) error {
@@ -86,102 +149,69 @@ func SendLoginValidationCodeByEmail(c *fiber.Ctx, model any) error {
 	if user_email == "" {
 		return lib.Error.General.BadRequest.WithError(fmt.Errorf("missing 'email' at params route"))
 	}
-
-	LoginValidationCode, err := ResetLoginvalidationCode(c, user_email, model)
-	if err != nil {
-		return err
-	}
-
-	language := c.Query("language", "en")
-
-	// Initialize renderer
-	renderer := email.NewTemplateRenderer("./static/email", "./translation/email")
-
-	// Render email template
-	renderedEmail, err := renderer.RenderEmail("login_validation_code", language, email.TemplateData{
-		"LoginValidationCode": LoginValidationCode,
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to render email: %w", err)
-	}
-
-	// Initialize email provider
-	provider, err := email.NewProvider(nil)
-	if err != nil {
-		return fmt.Errorf("failed to initialize email provider: %w", err)
-	}
-
-	// Send email
-	err = provider.Send(context.Background(), email.EmailData{
-		To:      []string{user_email},
-		Subject: renderedEmail.Subject,
-		Html:    renderedEmail.HTMLBody,
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to send email: %w", err)
-	}
-
-	log.Printf("Successfully sent login_validation_code email to %s", user_email)
-	return nil
+	return sendLoginValidationCode(c, model, user_email)
 }
 
-func ResetPasswordByEmail(c *fiber.Ctx, user_email string, model any) (*DTO.PasswordReseted, error) {
+// sendLoginValidationCode emails a new login validation code of the user with the email.
+// The email is recorded in the outbox along with the code, so it is only sent once the code is stored.
+func sendLoginValidationCode(c *fiber.Ctx, model any, user_email string) error {
 	var err error
 	Service := service.New(c)
 	defer func() { Service.DeferDB(err) }()
-	return Service.SetModel(model).ResetPasswordByEmail(user_email)
-}
 
-func SendNewPasswordByEmail(c *fiber.Ctx, user_email string, model any) error {
-	password, err := ResetPasswordByEmail(c, user_email, model)
+	LoginValidationCode, err := Service.SetModel(model).ResetLoginCodeByEmail(user_email)
 	if err != nil {
 		return err
 	}
 
-	renderer := email.NewTemplateRenderer("./static/email", "./translation/email")
-
-	language := c.Query("language", "en")
-
-	// Render email template
-	renderedEmail, err := renderer.RenderEmail("new_password", language, email.TemplateData{
-		"NewPassword": password.Password,
+	expiresAt := time.Now().Add(service.CodeValidity)
+	err = email.EnqueueAccountEmail(Service.MyGorm.DB, email.AccountEmailJob{
+		To:       user_email,
+		Template: "login_validation_code",
+		Language: c.Query("language", "en"),
+		Data: email.TemplateData{
+			"LoginValidationCode": LoginValidationCode,
+		},
+		ExpiresAt: &expiresAt,
 	})
+	return err
+}
 
-	if err != nil {
-		return fmt.Errorf("failed to render email: %w", err)
-	}
+// SendNewPasswordByEmail resets the password of the user with the email and emails the new one.
+// The email is recorded in the outbox along with the new password.
+func SendNewPasswordByEmail(c *fiber.Ctx, user_email string, model any) error {
+	var err error
+	Service := service.New(c)
+	defer func() { Service.DeferDB(err) }()
 
-	// Initialize email provider
-	provider, err := email.NewProvider(nil)
+	password, err := Service.SetModel(model).ResetPasswordByEmail(user_email)
 	if err != nil {
-		return fmt.Errorf("failed to initialize email provider: %w", err)
+		return err
 	}
 
-	// Send email
-	err = provider.Send(context.Background(), email.EmailData{
-		To:      []string{user_email},
-		Subject: renderedEmail.Subject,
-		Html:    renderedEmail.HTMLBody,
+	err = email.EnqueueAccountEmail(Service.MyGorm.DB, email.AccountEmailJob{
+		To:       user_email,
+		Template: "new_password",
+		Language: c.Query("language", "en"),
+		Data: email.TemplateData{
+			"NewPassword": password.Password,
+		},
 	})
-
-	if err != nil {
-		return fmt.Errorf("failed to send email: %w", err)
-	}
-
-	log.Printf("Successfully sent new_password email to %s", user_email)
-	return nil
+	return err
 }
 
 func SendVerificationCodeByEmail(c *fiber.Ctx, model any) error {
-	var err error
 	user_email := c.Params("email")
 	if user_email == "" {
 		return lib.Error.General.BadRequest.WithError(fmt.Errorf("missing 'email' at params route"))
 	}
+	return sendVerificationCode(c, model, user_email, c.Params("company_id", ""))
+}
 
-	company_id := c.Params("company_id", "")
+// sendVerificationCode emails a new verification code of the user with the email, an
+// employee of the company when company_id is set and a client otherwise.
+func sendVerificationCode(c *fiber.Ctx, model any, user_email, company_id string) error {
+	var err error
 	if company_id != "" {
 		if err := lib.ChangeToCompanySchemaByContext(c); err != nil {
 			return lib.Error.General.BadRequest.WithError(err)
@@ -212,38 +242,18 @@ func SendVerificationCodeByEmail(c *fiber.Ctx, model any) error {
 		verificationLink = fmt.Sprintf("%s://%s/verify-email?email=%s&type=client&lang=%s&code=%s", protocol, host, user_email, language, code)
 	}
 
-	// Initialize renderer
-	renderer := email.NewTemplateRenderer("./static/email", "./translation/email")
-
-	// Render email template
-	renderedEmail, err := renderer.RenderEmail("email_verification_code", language, email.TemplateData{
-		"VerificationCode": code,
-		"VerificationLink": verificationLink,
+	expiresAt := time.Now().Add(service.CodeValidity)
+	err = email.EnqueueAccountEmail(Service.MyGorm.DB, email.AccountEmailJob{
+		To:       user_email,
+		Template: "email_verification_code",
+		Language: language,
+		Data: email.TemplateData{
+			"VerificationCode": code,
+			"VerificationLink": verificationLink,
+		},
+		ExpiresAt: &expiresAt,
 	})
-
-	if err != nil {
-		return fmt.Errorf("failed to render email: %w", err)
-	}
-
-	// Initialize email provider
-	provider, err := email.NewProvider(nil)
-	if err != nil {
-		return fmt.Errorf("failed to initialize email provider: %w", err)
-	}
-
-	// Send email
-	err = provider.Send(context.Background(), email.EmailData{
-		To:      []string{user_email},
-		Subject: renderedEmail.Subject,
-		Html:    renderedEmail.HTMLBody,
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to send email: %w", err)
-	}
-
-	log.Printf("Successfully sent email_verification_code email to %s", user_email)
-	return nil
+	return err
 }
 
 func VerifyEmail(c *fiber.Ctx, model any) error {
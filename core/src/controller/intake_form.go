@@ -0,0 +1,531 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	database "mynute-go/core/src/config/db"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/lib/intake"
+	"mynute-go/core/src/middleware"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateIntakeForm creates an intake form
+//
+//	@Summary		Create intake form
+//	@Description	Create a questionnaire or consent document asked of whoever attends the attached services before their first appointment
+//	@Tags			IntakeForm
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			intake_form	body		DTO.CreateIntakeForm	true	"Intake form"
+//	@Success		200			{object}	DTO.IntakeForm
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Router			/intake_form [post]
+func CreateIntakeForm(c *fiber.Ctx) (err error) {
+	var body DTO.CreateIntakeForm
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	// The first version is saved along with the form
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	form := model.IntakeForm{
+		CompanyID:   body.CompanyID,
+		Name:        body.Name,
+		Description: body.Description,
+		ServiceIDs:  model.UUIDList(body.ServiceIDs),
+		Fields:      intakeFields(body.Fields),
+		IsConsent:   body.IsConsent,
+		ConsentText: body.ConsentText,
+		IsActive:    true,
+	}
+	if err = tx.Create(&form).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
+
+	if err = lib.ResponseFactory(c).SendDTO(200, &form, &DTO.IntakeForm{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetCompanyIntakeForms lists the intake forms of a company
+//
+//	@Summary		List intake forms
+//	@Description	List the intake forms of a company
+//	@Tags			IntakeForm
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			company_id		path		string	true	"Company ID"
+//	@Param			active			query		bool	false	"Only active forms"
+//	@Produce		json
+//	@Success		200	{object}	DTO.IntakeFormList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/intake_forms [get]
+func GetCompanyIntakeForms(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	query := tx.Where("company_id = ?", companyID)
+	if c.QueryBool("active") {
+		query = query.Where("is_active = ?", true)
+	}
+	list := struct {
+		IntakeForms []model.IntakeForm `json:"intake_forms"`
+	}{[]model.IntakeForm{}}
+	if err := query.Order("name").Find(&list.IntakeForms).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).SendDTO(200, &list, &DTO.IntakeFormList{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetServiceIntakeForms lists the intake forms asked by a service
+//
+//	@Summary		List service intake forms
+//	@Description	The active forms asked when booking the service, to answer them in the booking
+//	@Tags			IntakeForm
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"Service ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.IntakeFormList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/service/{id}/intake_forms [get]
+func GetServiceIntakeForms(c *fiber.Ctx) error {
+	serviceID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	forms, err := intake.FormsFor(tx, serviceID)
+	if err != nil {
+		return err
+	}
+	list := struct {
+		IntakeForms []model.IntakeForm `json:"intake_forms"`
+	}{forms}
+	if list.IntakeForms == nil {
+		list.IntakeForms = []model.IntakeForm{}
+	}
+	if err := lib.ResponseFactory(c).SendDTO(200, &list, &DTO.IntakeFormList{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetIntakeFormById retrieves an intake form by ID
+//
+//	@Summary		Get intake form
+//	@Description	Retrieve an intake form by its ID
+//	@Tags			IntakeForm
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Intake form ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.IntakeForm
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/intake_form/{id} [get]
+func GetIntakeFormById(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	form, err := loadIntakeForm(tx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+	if err := lib.ResponseFactory(c).SendDTO(200, form, &DTO.IntakeForm{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// UpdateIntakeFormById updates an intake form by ID
+//
+//	@Summary		Update intake form
+//	@Description	Update an intake form. Changing its fields or consent text makes a new version, asked again of attendees who answered an older one.
+//	@Tags			IntakeForm
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string					true	"Intake form ID"
+//	@Param			intake_form	body		DTO.UpdateIntakeForm	true	"Intake form"
+//	@Success		200			{object}	DTO.IntakeForm
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Router			/intake_form/{id} [patch]
+func UpdateIntakeFormById(c *fiber.Ctx) (err error) {
+	var body DTO.UpdateIntakeForm
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	form, err := loadIntakeForm(tx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	// Applied through a map so that false and empty values are not skipped as zero values.
+	changes := map[string]any{}
+	if body.Name != nil {
+		form.Name = *body.Name
+		changes["name"] = form.Name
+	}
+	if body.Description != nil {
+		form.Description = *body.Description
+		changes["description"] = form.Description
+	}
+	if body.ServiceIDs != nil {
+		form.ServiceIDs = model.UUIDList(*body.ServiceIDs)
+		changes["service_ids"] = form.ServiceIDs
+	}
+	if body.IsActive != nil {
+		form.IsActive = *body.IsActive
+		changes["is_active"] = form.IsActive
+	}
+	newVersion := false
+	if body.Fields != nil {
+		form.Fields = intakeFields(*body.Fields)
+		changes["fields"] = form.Fields
+		newVersion = true
+	}
+	if body.IsConsent != nil && *body.IsConsent != form.IsConsent {
+		form.IsConsent = *body.IsConsent
+		changes["is_consent"] = form.IsConsent
+		newVersion = true
+	}
+	if body.ConsentText != nil && *body.ConsentText != form.ConsentText {
+		form.ConsentText = *body.ConsentText
+		changes["consent_text"] = form.ConsentText
+		newVersion = true
+	}
+	if len(changes) == 0 {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("no changes provided"))
+	}
+	if err = form.Validate(); err != nil {
+		return err
+	}
+	if newVersion {
+		form.Version++
+		changes["version"] = form.Version
+	}
+	if err = tx.Model(form).Updates(changes).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
+	}
+	if newVersion {
+		if err = form.SaveVersion(tx); err != nil {
+			return err
+		}
+	}
+
+	if err = lib.ResponseFactory(c).SendDTO(200, form, &DTO.IntakeForm{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// DeleteIntakeFormById deletes an intake form by ID
+//
+//	@Summary		Delete intake form
+//	@Description	Delete an intake form by its ID. The answers given to it are kept.
+//	@Tags			IntakeForm
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Intake form ID"
+//	@Produce		json
+//	@Success		200
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/intake_form/{id} [delete]
+func DeleteIntakeFormById(c *fiber.Ctx) error {
+	return DeleteOneById(c, &model.IntakeForm{})
+}
+
+// GetIntakeRequest gets a form asked through an emailed link
+//
+//	@Summary		Get intake request
+//	@Description	The form version the client was emailed a link to answer. The link stops working once answered.
+//	@Tags			IntakeForm
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			token			path		string	true	"Token of the emailed link"
+//	@Produce		json
+//	@Success		200	{object}	DTO.IntakeResponse
+//	@Failure		404	{object}	DTO.ErrorResponse
+//	@Router			/intake/{token} [get]
+func GetIntakeRequest(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := loadIntakeRequest(tx, c.Params("token"))
+	if err != nil {
+		return err
+	}
+	res, err := intakeResponseDTO(tx, response)
+	if err != nil {
+		return err
+	}
+	if err := lib.ResponseFactory(c).Send(200, res); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// SubmitIntakeRequest answers a form asked through an emailed link
+//
+//	@Summary		Answer intake request
+//	@Description	Answer the form the client was emailed a link to. Consent forms must be signed with the full name of the client, their PDF is stored.
+//	@Tags			IntakeForm
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Accept			json
+//	@Produce		json
+//	@Param			token		path		string					true	"Token of the emailed link"
+//	@Param			answers		body		DTO.SubmitIntakeForm	true	"Answers"
+//	@Param			language	query		string					false	"Consent PDF language (en, pt, es)"	default(en)
+//	@Success		200			{object}	DTO.IntakeResponse
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Failure		404			{object}	DTO.ErrorResponse
+//	@Router			/intake/{token} [post]
+func SubmitIntakeRequest(c *fiber.Ctx) (err error) {
+	var body DTO.SubmitIntakeForm
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	response, err := loadIntakeRequest(tx, c.Params("token"))
+	if err != nil {
+		return err
+	}
+	if err = database.LockForUpdate(tx, response, "id", response.ID.String()); err != nil {
+		return err
+	}
+	version, err := intake.LoadVersion(tx, response.FormID, response.Version)
+	if err != nil {
+		return err
+	}
+	sub := intake.Submission{FormID: response.FormID, Answers: body.Answers, SignedName: body.SignedName}
+	if err = intake.Complete(tx, response, version, sub, c.IP(), c.Query("language", "en"), time.Now()); err != nil {
+		return err
+	}
+
+	res, err := intakeResponseDTO(tx, response)
+	if err != nil {
+		return err
+	}
+	if err = lib.ResponseFactory(c).Send(200, res); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetAppointmentIntake gets the intake answers of an appointment
+//
+//	@Summary		Get appointment intake
+//	@Description	The answers of the attendee to every form asked by the service of the appointment, with the form version they answered and the signed consent PDFs. Forms not answered yet are pending.
+//	@Tags			Appointment
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.AppointmentIntake
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/appointment/{id}/intake [get]
+func GetAppointmentIntake(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	var appointment model.Appointment
+	if err := tx.Where("id = ?", c.Params("id")).First(&appointment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return lib.Error.Appointment.NotFound
+		}
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	responses, complete, err := intake.ForAppointment(tx, &appointment)
+	if err != nil {
+		return err
+	}
+
+	res := DTO.AppointmentIntake{AppointmentID: appointment.ID, Complete: complete, Responses: make([]DTO.IntakeResponse, 0, len(responses))}
+	for i := range responses {
+		r, err := intakeResponseDTO(tx, &responses[i])
+		if err != nil {
+			return err
+		}
+		res.Responses = append(res.Responses, *r)
+	}
+	if err := lib.ResponseFactory(c).Send(200, &res); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+func loadIntakeForm(tx *gorm.DB, id string) (*model.IntakeForm, error) {
+	var form model.IntakeForm
+	if err := tx.Where("id = ?", id).First(&form).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, lib.Error.IntakeForm.NotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	return &form, nil
+}
+
+// loadIntakeRequest loads the pending response of an emailed link.
+func loadIntakeRequest(tx *gorm.DB, token string) (*model.IntakeResponse, error) {
+	var response model.IntakeResponse
+	if err := tx.Where("token = ? AND status = ?", token, model.IntakePending).First(&response).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, lib.Error.IntakeForm.ResponseNotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	return &response, nil
+}
+
+func intakeFields(fields []DTO.IntakeField) model.IntakeFields {
+	out := make(model.IntakeFields, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, model.IntakeField{
+			Key:      f.Key,
+			Label:    f.Label,
+			Type:     f.Type,
+			Required: f.Required,
+			Options:  f.Options,
+			Help:     f.Help,
+		})
+	}
+	return out
+}
+
+// intakeBooking is how the intake forms of an appointment booked through the request are answered.
+func intakeBooking(c *fiber.Ctx, submissions []DTO.IntakeSubmission, language string) intake.Booking {
+	booking := intake.Booking{
+		IP:       c.IP(),
+		Language: language,
+		BaseURL:  fmt.Sprintf("%s://%s", c.Protocol(), c.Hostname()),
+	}
+	for _, s := range submissions {
+		booking.Submissions = append(booking.Submissions, intake.Submission{FormID: s.FormID, Answers: s.Answers, SignedName: s.SignedName})
+	}
+	return booking
+}
+
+// intakeResponseDTO maps a response along with the form version it answers.
+func intakeResponseDTO(tx *gorm.DB, response *model.IntakeResponse) (*DTO.IntakeResponse, error) {
+	var version model.IntakeFormVersion
+	if err := tx.Where("id = ?", response.FormVersionID).First(&version).Error; err != nil {
+		return nil, lib.Error.IntakeForm.NotFound.WithError(err)
+	}
+	return &DTO.IntakeResponse{
+		ID:      response.ID,
+		FormID:  response.FormID,
+		Version: response.Version,
+		Form: DTO.IntakeFormVersion{
+			ID:          version.ID,
+			FormID:      version.FormID,
+			Version:     version.Version,
+			Name:        version.Name,
+			Fields:      intakeFieldsDTO(version.Fields),
+			IsConsent:   version.IsConsent,
+			ConsentText: version.ConsentText,
+		},
+		ClientID:      response.ClientID,
+		DependentID:   response.DependentID,
+		AppointmentID: response.AppointmentID,
+		Status:        response.Status,
+		Answers:       response.Answers,
+		SignedName:    response.SignedName,
+		SignedAt:      response.SignedAt,
+		SignatureIP:   response.SignatureIP,
+		ConsentURL:    response.ConsentURL,
+		CompletedAt:   response.CompletedAt,
+	}, nil
+}
+
+func intakeFieldsDTO(fields model.IntakeFields) []DTO.IntakeField {
+	out := make([]DTO.IntakeField, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, DTO.IntakeField{
+			Key:      f.Key,
+			Label:    f.Label,
+			Type:     f.Type,
+			Required: f.Required,
+			Options:  f.Options,
+			Help:     f.Help,
+		})
+	}
+	return out
+}
+
+// IntakeForm registers the intake form controllers
+func IntakeForm(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		CreateIntakeForm,
+		GetCompanyIntakeForms,
+		GetServiceIntakeForms,
+		GetIntakeFormById,
+		UpdateIntakeFormById,
+		DeleteIntakeFormById,
+		GetIntakeRequest,
+		SubmitIntakeRequest,
+		GetAppointmentIntake,
+	})
+}
@@ -0,0 +1,362 @@
+package controller
+
+import (
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	database "mynute-go/core/src/config/db"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/lib/loyalty"
+	"mynute-go/core/src/middleware"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GetLoyaltyProgram retrieves the loyalty program of a company
+//
+//	@Summary		Get loyalty program
+//	@Description	How clients earn and redeem points at the company
+//	@Tags			Loyalty
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			company_id		path		string	true	"Company ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.LoyaltyProgram
+//	@Failure		404	{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/loyalty_program [get]
+func GetLoyaltyProgram(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	program, err := loyalty.Program(tx, companyID)
+	if err != nil {
+		return err
+	}
+	if program == nil {
+		return lib.Error.Loyalty.NotFound
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, loyaltyProgramDTO(program)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// SaveLoyaltyProgram creates or replaces the loyalty program of a company
+//
+//	@Summary		Save loyalty program
+//	@Description	Create the loyalty program of the company, or replace its settings. Points already earned are kept
+//	@Tags			Loyalty
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			company_id	path		string					true	"Company ID"
+//	@Param			program		body		DTO.SaveLoyaltyProgram	true	"Program"
+//	@Success		200			{object}	DTO.LoyaltyProgram
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/loyalty_program [put]
+func SaveLoyaltyProgram(c *fiber.Ctx) (err error) {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+	var body DTO.SaveLoyaltyProgram
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	rules := make(model.LoyaltyRules, 0, len(body.Rules))
+	serviceIDs := make([]uuid.UUID, 0, len(body.Rules))
+	for _, rule := range body.Rules {
+		rules = append(rules, model.LoyaltyRule{
+			ServiceID:      rule.ServiceID,
+			LoyaltyEarning: model.LoyaltyEarning(rule.LoyaltyEarning),
+			Excluded:       rule.Excluded,
+		})
+		serviceIDs = append(serviceIDs, rule.ServiceID)
+	}
+	settings := model.LoyaltyProgram{
+		CompanyID:        companyID,
+		IsActive:         body.IsActive,
+		LoyaltyEarning:   model.LoyaltyEarning(body.LoyaltyEarning),
+		Rules:            rules,
+		PointValue:       body.PointValue,
+		MinRedeemPoints:  body.MinRedeemPoints,
+		MaxRedeemPercent: body.MaxRedeemPercent,
+	}
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	if len(serviceIDs) > 0 {
+		var services int64
+		if err := tx.Model(&model.Service{}).Where("id IN ?", serviceIDs).Count(&services).Error; err != nil {
+			return lib.Error.General.InternalError.WithError(err)
+		}
+		if services != int64(len(serviceIDs)) {
+			return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("rules reference services that do not exist"))
+		}
+	}
+
+	program, err := loyalty.Program(tx, companyID)
+	if err != nil {
+		return err
+	}
+	if program == nil {
+		program = &settings
+		if err := tx.Create(program).Error; err != nil {
+			return lib.Error.General.CreatedError.WithError(err)
+		}
+	} else {
+		if err := tx.Model(program).Updates(map[string]any{
+			"is_active":              settings.IsActive,
+			"points_per_appointment": settings.PointsPerAppointment,
+			"points_per_amount":      settings.PointsPerAmount,
+			"amount_unit":            settings.AmountUnit,
+			"rules":                  settings.Rules,
+			"point_value":            settings.PointValue,
+			"min_redeem_points":      settings.MinRedeemPoints,
+			"max_redeem_percent":     settings.MaxRedeemPercent,
+		}).Error; err != nil {
+			return lib.Error.General.UpdatedError.WithError(err)
+		}
+		settings.ID = program.ID
+		settings.CreatedAt = program.CreatedAt
+		program = &settings
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, loyaltyProgramDTO(program)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetClientLoyalty returns the points balance of a client at a company
+//
+//	@Summary		Get client loyalty points
+//	@Description	Points balance of the client with its ledger, newest entries first
+//	@Tags			Loyalty
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			company_id		path		string	true	"Company ID"
+//	@Param			client_id		path		string	true	"Client ID"
+//	@Param			type			query		string	false	"EARN, REDEEM, REVERSAL, RESTORE or ADJUSTMENT"
+//	@Param			page			query		int		false	"Page number"				default(1)
+//	@Param			page_size		query		int		false	"Number of items per page"	default(10)
+//	@Produce		json
+//	@Success		200	{object}	DTO.LoyaltyBalance
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/client/{client_id}/loyalty [get]
+func GetClientLoyalty(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+	clientID, err := uuid.Parse(c.Params("client_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid client_id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	page := c.QueryInt("page", 1)
+	pageSize := c.QueryInt("page_size", 10)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	// Clients without an account have no points yet, reading must not open one
+	var account model.LoyaltyAccount
+	if err := tx.Where("company_id = ? AND client_id = ?", companyID, clientID).Limit(1).Find(&account).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	query := tx.Model(&model.LoyaltyTransaction{}).Where("company_id = ? AND client_id = ?", companyID, clientID)
+	if entryType := strings.ToUpper(c.Query("type")); entryType != "" {
+		query = query.Where("type = ?", entryType)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	var entries []model.LoyaltyTransaction
+	if err := query.Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&entries).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	balance := DTO.LoyaltyBalance{
+		CompanyID:    companyID,
+		ClientID:     clientID,
+		Balance:      account.Balance,
+		Earned:       account.Earned,
+		Redeemed:     account.Redeemed,
+		Transactions: make([]DTO.LoyaltyTransaction, 0, len(entries)),
+		TotalCount:   int(total),
+		Page:         page,
+		PageSize:     pageSize,
+	}
+	program, err := loyalty.Program(tx, companyID)
+	if err != nil {
+		return err
+	}
+	if program != nil && program.IsActive && account.Balance > 0 {
+		balance.Value = account.Balance * program.PointValue
+	}
+	for i := range entries {
+		balance.Transactions = append(balance.Transactions, loyaltyTransactionDTO(&entries[i]))
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, &balance); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// AdjustClientLoyalty grants points to a client or removes them
+//
+//	@Summary		Adjust client loyalty points
+//	@Description	Record a manual adjustment in the points ledger of the client, negative points remove them
+//	@Tags			Loyalty
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			company_id	path		string					true	"Company ID"
+//	@Param			client_id	path		string					true	"Client ID"
+//	@Param			adjustment	body		DTO.AdjustLoyaltyPoints	true	"Adjustment"
+//	@Success		200			{object}	DTO.LoyaltyTransaction
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/client/{client_id}/loyalty/adjust [post]
+func AdjustClientLoyalty(c *fiber.Ctx) (err error) {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+	clientID, err := uuid.Parse(c.Params("client_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid client_id"))
+	}
+	var body DTO.AdjustLoyaltyPoints
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+	body.Description = strings.TrimSpace(body.Description)
+	if body.Points == 0 {
+		return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("points must not be zero"))
+	}
+	if body.Description == "" {
+		return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("description is required"))
+	}
+	if len([]rune(body.Description)) > 255 {
+		return lib.Error.Loyalty.Invalid.WithError(fmt.Errorf("description must have at most 255 characters"))
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	var clients int64
+	if err := tx.Model(&model.Client{}).Where("id = ?", clientID).Count(&clients).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	if clients == 0 {
+		return lib.Error.Client.NotFound
+	}
+
+	account, err := loyalty.Account(tx, companyID, clientID)
+	if err != nil {
+		return err
+	}
+	if body.Points < 0 && account.Balance+body.Points < 0 {
+		return lib.Error.Loyalty.InsufficientPoints.WithError(fmt.Errorf("the balance is %d points", account.Balance))
+	}
+	entry := model.LoyaltyTransaction{
+		Type:        model.LoyaltyAdjustment,
+		Points:      body.Points,
+		Description: body.Description,
+		CreatedByID: auditFromRequest(c).ActorID,
+	}
+	if err := loyalty.Post(tx, account, &entry); err != nil {
+		return err
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, loyaltyTransactionDTO(&entry)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+func loyaltyProgramDTO(p *model.LoyaltyProgram) *DTO.LoyaltyProgram {
+	rules := make([]DTO.LoyaltyRule, 0, len(p.Rules))
+	for _, rule := range p.Rules {
+		rules = append(rules, DTO.LoyaltyRule{
+			ServiceID:      rule.ServiceID,
+			LoyaltyEarning: DTO.LoyaltyEarning(rule.LoyaltyEarning),
+			Excluded:       rule.Excluded,
+		})
+	}
+	return &DTO.LoyaltyProgram{
+		ID:               p.ID,
+		CompanyID:        p.CompanyID,
+		IsActive:         p.IsActive,
+		LoyaltyEarning:   DTO.LoyaltyEarning(p.LoyaltyEarning),
+		Rules:            rules,
+		PointValue:       p.PointValue,
+		MinRedeemPoints:  p.MinRedeemPoints,
+		MaxRedeemPercent: p.MaxRedeemPercent,
+	}
+}
+
+func loyaltyTransactionDTO(t *model.LoyaltyTransaction) DTO.LoyaltyTransaction {
+	return DTO.LoyaltyTransaction{
+		ID:            t.ID,
+		Type:          t.Type,
+		Points:        t.Points,
+		BalanceAfter:  t.BalanceAfter,
+		AppointmentID: t.AppointmentID,
+		Description:   t.Description,
+		CreatedByID:   t.CreatedByID,
+		CreatedAt:     t.CreatedAt,
+	}
+}
+
+// Loyalty registers the loyalty program controllers
+func Loyalty(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		GetLoyaltyProgram,
+		SaveLoyaltyProgram,
+		GetClientLoyalty,
+		AdjustClientLoyalty,
+	})
+}
@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	database "mynute-go/core/src/config/db"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	myUploader "mynute-go/core/src/lib/cloud_uploader"
+	"mynute-go/core/src/lib/payment"
+	"mynute-go/core/src/lib/pix"
+	"mynute-go/core/src/middleware"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GetPaymentById retrieves a payment by ID
+//
+//	@Summary		Get payment
+//	@Description	Retrieve a payment by its ID
+//	@Tags			Payment
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Payment ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.Payment
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/payment/{id} [get]
+func GetPaymentById(c *fiber.Ctx) error {
+	var p model.Payment
+	if err := GetOneBy("id", c, &p, nil, nil); err != nil {
+		return err
+	}
+	if err := lib.ResponseFactory(c).SendDTO(200, &p, &DTO.Payment{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetPaymentPix returns the Pix code of a payment
+//
+//	@Summary		Get payment Pix code
+//	@Description	Returns the BR Code ("copia e cola") of a Pix payment and the URL of its QR code image, rendered on the first call
+//	@Tags			Payment
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Payment ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.PixCharge
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/payment/{id}/pix [get]
+func GetPaymentPix(c *fiber.Ctx) (err error) {
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	var p model.Payment
+	if err := database.LockForUpdate(tx, &p, "id", c.Params("id")); err != nil {
+		return err
+	}
+	if p.PixPayload == "" {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("payment %s is not a Pix payment", p.ID))
+	}
+
+	if p.PixQRCodeURL == "" {
+		png, err := pix.QRCode(p.PixPayload, pix.DefaultQRSize)
+		if err != nil {
+			return lib.Error.General.InternalError.WithError(err)
+		}
+		up, err := myUploader.FileUploader("payment", p.ID.String())
+		if err != nil {
+			return lib.Error.General.InternalError.WithError(err)
+		}
+		url, err := up.Save("image", png, "pix-qr.png")
+		if err != nil {
+			return lib.Error.General.InternalError.WithError(fmt.Errorf("failed to store Pix QR code: %w", err))
+		}
+		if err := tx.Model(&p).UpdateColumn("pix_qr_code_url", url).Error; err != nil {
+			return lib.Error.General.UpdatedError.WithError(err)
+		}
+		p.PixQRCodeURL = url
+	}
+
+	charge := DTO.PixCharge{
+		PaymentID: p.ID,
+		Amount:    p.Price,
+		Status:    string(p.Status),
+		Payload:   p.PixPayload,
+		QRCodeURL: p.PixQRCodeURL,
+	}
+	if err := lib.ResponseFactory(c).Send(200, &charge); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// RefundPaymentById refunds a payment by ID
+//
+//	@Summary		Refund payment
+//	@Description	Queue a refund of a completed payment. Without amount, everything not refunded yet is refunded.
+//	@Tags			Payment
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"Payment ID"
+//	@Param			refund	body		DTO.RefundPayment	false	"Refund"
+//	@Success		200		{object}	DTO.Payment
+//	@Failure		400		{object}	DTO.ErrorResponse
+//	@Router			/payment/{id}/refund [post]
+func RefundPaymentById(c *fiber.Ctx) (err error) {
+	var body DTO.RefundPayment
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&body); err != nil {
+			return lib.Error.General.BadRequest.WithError(err)
+		}
+	}
+	if body.Amount < 0 {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("refund amount must be positive"))
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	var p model.Payment
+	if err := database.LockForUpdate(tx, &p, "id", c.Params("id")); err != nil {
+		return err
+	}
+	if p.Status != model.StatusCompleted && p.Status != model.StatusRefunded {
+		return lib.Error.Payment.InvalidTransition.WithError(fmt.Errorf("payment is %s", p.Status))
+	}
+	remaining := p.Price - p.RefundedAmount
+	if remaining <= 0 {
+		return lib.Error.Payment.NothingToRefund
+	}
+	amount := body.Amount
+	if amount == 0 || amount > remaining {
+		amount = remaining
+	}
+	if err := payment.ScheduleRefund(tx, &p, amount); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).SendDTO(200, &p, &DTO.Payment{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// PaymentProviderWebhook receives the payment status notifications of a provider
+//
+//	@Summary		Payment provider webhook
+//	@Description	Called by the payment provider when a payment is confirmed, fails or is refunded. The request signature is verified.
+//	@Tags			Payment
+//	@Accept			json
+//	@Param			provider	path	string	true	"Provider name (e.g. fake)"
+//	@Param			company_id	path	string	true	"Company ID"
+//	@Success		200
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/payment/webhook/{provider}/{company_id} [post]
+func PaymentProviderWebhook(c *fiber.Ctx) (err error) {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+	provider, err := payment.NewProvider(c.Params("provider"))
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	for key, values := range c.GetReqHeaders() {
+		for _, v := range values {
+			header.Add(key, v)
+		}
+	}
+	notifications, err := provider.VerifyWebhook(header, c.Body())
+	if err != nil {
+		return lib.Error.Payment.InvalidWebhook.WithError(err)
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	var schemaName string
+	if err := tx.Model(&model.Company{}).Where("id = ?", companyID).Pluck("schema_name", &schemaName).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	if schemaName == "" {
+		return lib.Error.Company.NotFound
+	}
+	if err := lib.ChangeToCompanySchema(tx, schemaName); err != nil {
+		return err
+	}
+	for i := range notifications {
+		if _, err := payment.ApplyNotification(tx, &notifications[i]); err != nil {
+			return err
+		}
+	}
+	return c.SendStatus(200)
+}
+
+// Payment registers the payment controllers
+func Payment(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		GetPaymentById,
+		GetPaymentPix,
+		RefundPaymentById,
+		PaymentProviderWebhook,
+	})
+}
@@ -0,0 +1,343 @@
+package controller
+
+import (
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreatePromoCode creates a promo code
+//
+//	@Summary		Create promo code
+//	@Description	Create a percentage or fixed amount discount code clients can use when booking
+//	@Tags			PromoCode
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			promo_code	body		DTO.CreatePromoCode	true	"Promo code"
+//	@Success		200			{object}	DTO.PromoCode
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Router			/promo_code [post]
+func CreatePromoCode(c *fiber.Ctx) error {
+	var body DTO.CreatePromoCode
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	promo := model.PromoCode{
+		CompanyID:        body.CompanyID,
+		Code:             body.Code,
+		Description:      body.Description,
+		DiscountType:     body.DiscountType,
+		DiscountValue:    body.DiscountValue,
+		StartsAt:         body.StartsAt,
+		EndsAt:           body.EndsAt,
+		MaxUses:          body.MaxUses,
+		MaxUsesPerClient: body.MaxUsesPerClient,
+		FirstVisitOnly:   body.FirstVisitOnly,
+		ServiceIDs:       model.UUIDList(body.ServiceIDs),
+		BranchIDs:        model.UUIDList(body.BranchIDs),
+		EmployeeIDs:      model.UUIDList(body.EmployeeIDs),
+		IsActive:         true,
+	}
+	if err := tx.Create(&promo).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, promoCodeDTO(&promo, 0)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetCompanyPromoCodes lists the promo codes of a company
+//
+//	@Summary		List promo codes
+//	@Description	List the promo codes of a company with how many times they were used
+//	@Tags			PromoCode
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			company_id		path		string	true	"Company ID"
+//	@Param			active			query		bool	false	"Only active codes"
+//	@Produce		json
+//	@Success		200	{object}	DTO.PromoCodeList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/promo_codes [get]
+func GetCompanyPromoCodes(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	query := tx.Where("company_id = ?", companyID)
+	if c.QueryBool("active") {
+		query = query.Where("is_active = ?", true)
+	}
+	var promos []model.PromoCode
+	if err := query.Order("created_at").Find(&promos).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	uses, err := promoCodeUses(tx, promos)
+	if err != nil {
+		return err
+	}
+
+	list := DTO.PromoCodeList{PromoCodes: make([]DTO.PromoCode, 0, len(promos))}
+	for i := range promos {
+		list.PromoCodes = append(list.PromoCodes, *promoCodeDTO(&promos[i], uses[promos[i].ID]))
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, &list); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetPromoCodeById retrieves a promo code by ID
+//
+//	@Summary		Get promo code
+//	@Description	Retrieve a promo code by its ID
+//	@Tags			PromoCode
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Promo code ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.PromoCode
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/promo_code/{id} [get]
+func GetPromoCodeById(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	promo, err := loadPromoCode(tx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+	uses, err := promoCodeUses(tx, []model.PromoCode{*promo})
+	if err != nil {
+		return err
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, promoCodeDTO(promo, uses[promo.ID])); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// UpdatePromoCodeById updates a promo code by ID
+//
+//	@Summary		Update promo code
+//	@Description	Update the discount, validity, limits, restrictions or active state of a promo code. The code itself can not change.
+//	@Tags			PromoCode
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string				true	"Promo code ID"
+//	@Param			promo_code	body		DTO.UpdatePromoCode	true	"Promo code"
+//	@Success		200			{object}	DTO.PromoCode
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Router			/promo_code/{id} [patch]
+func UpdatePromoCodeById(c *fiber.Ctx) error {
+	var body DTO.UpdatePromoCode
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	promo, err := loadPromoCode(tx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	// Applied through a map so that false and 0 are not skipped as zero values.
+	changes := map[string]any{}
+	if body.Description != nil {
+		promo.Description = *body.Description
+		changes["description"] = promo.Description
+	}
+	if body.DiscountType != nil {
+		promo.DiscountType = *body.DiscountType
+		changes["discount_type"] = promo.DiscountType
+	}
+	if body.DiscountValue != nil {
+		promo.DiscountValue = *body.DiscountValue
+		changes["discount_value"] = promo.DiscountValue
+	}
+	if body.StartsAt != nil {
+		promo.StartsAt = body.StartsAt
+		changes["starts_at"] = promo.StartsAt
+	}
+	if body.EndsAt != nil {
+		promo.EndsAt = body.EndsAt
+		changes["ends_at"] = promo.EndsAt
+	}
+	if body.MaxUses != nil {
+		promo.MaxUses = *body.MaxUses
+		changes["max_uses"] = promo.MaxUses
+	}
+	if body.MaxUsesPerClient != nil {
+		promo.MaxUsesPerClient = *body.MaxUsesPerClient
+		changes["max_uses_per_client"] = promo.MaxUsesPerClient
+	}
+	if body.FirstVisitOnly != nil {
+		promo.FirstVisitOnly = *body.FirstVisitOnly
+		changes["first_visit_only"] = promo.FirstVisitOnly
+	}
+	if body.ServiceIDs != nil {
+		promo.ServiceIDs = model.UUIDList(*body.ServiceIDs)
+		changes["service_ids"] = promo.ServiceIDs
+	}
+	if body.BranchIDs != nil {
+		promo.BranchIDs = model.UUIDList(*body.BranchIDs)
+		changes["branch_ids"] = promo.BranchIDs
+	}
+	if body.EmployeeIDs != nil {
+		promo.EmployeeIDs = model.UUIDList(*body.EmployeeIDs)
+		changes["employee_ids"] = promo.EmployeeIDs
+	}
+	if body.IsActive != nil {
+		promo.IsActive = *body.IsActive
+		changes["is_active"] = promo.IsActive
+	}
+	if len(changes) == 0 {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("no changes provided"))
+	}
+	if err := promo.Validate(); err != nil {
+		return err
+	}
+	if err := tx.Model(promo).Updates(changes).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
+	}
+
+	uses, err := promoCodeUses(tx, []model.PromoCode{*promo})
+	if err != nil {
+		return err
+	}
+	if err := lib.ResponseFactory(c).Send(200, promoCodeDTO(promo, uses[promo.ID])); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// DeletePromoCodeById deletes a promo code by ID
+//
+//	@Summary		Delete promo code
+//	@Description	Delete a promo code by its ID. Appointments keep the discount they were booked with.
+//	@Tags			PromoCode
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Promo code ID"
+//	@Produce		json
+//	@Success		200
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/promo_code/{id} [delete]
+func DeletePromoCodeById(c *fiber.Ctx) error {
+	return DeleteOneById(c, &model.PromoCode{})
+}
+
+func loadPromoCode(tx *gorm.DB, id string) (*model.PromoCode, error) {
+	var promo model.PromoCode
+	if err := tx.Where("id = ?", id).First(&promo).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, lib.Error.PromoCode.NotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	return &promo, nil
+}
+
+// promoCodeUses counts the appointments booked with each code that are not cancelled.
+func promoCodeUses(tx *gorm.DB, promos []model.PromoCode) (map[uuid.UUID]int64, error) {
+	uses := make(map[uuid.UUID]int64, len(promos))
+	if len(promos) == 0 {
+		return uses, nil
+	}
+	ids := make([]uuid.UUID, len(promos))
+	for i := range promos {
+		ids[i] = promos[i].ID
+	}
+	var rows []struct {
+		PromoCodeID uuid.UUID
+		Uses        int64
+	}
+	if err := tx.Model(&model.Appointment{}).
+		Select("promo_code_id, COUNT(*) AS uses").
+		Where("promo_code_id IN ? AND is_cancelled = ?", ids, false).
+		Group("promo_code_id").
+		Scan(&rows).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error counting promo code uses: %w", err))
+	}
+	for _, row := range rows {
+		uses[row.PromoCodeID] = row.Uses
+	}
+	return uses, nil
+}
+
+func promoCodeDTO(p *model.PromoCode, uses int64) *DTO.PromoCode {
+	return &DTO.PromoCode{
+		ID:               p.ID,
+		CompanyID:        p.CompanyID,
+		Code:             p.Code,
+		Description:      p.Description,
+		DiscountType:     p.DiscountType,
+		DiscountValue:    p.DiscountValue,
+		StartsAt:         p.StartsAt,
+		EndsAt:           p.EndsAt,
+		MaxUses:          p.MaxUses,
+		MaxUsesPerClient: p.MaxUsesPerClient,
+		FirstVisitOnly:   p.FirstVisitOnly,
+		ServiceIDs:       p.ServiceIDs,
+		BranchIDs:        p.BranchIDs,
+		EmployeeIDs:      p.EmployeeIDs,
+		IsActive:         p.IsActive,
+		Uses:             uses,
+	}
+}
+
+// PromoCode registers the promo code controllers
+func PromoCode(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		CreatePromoCode,
+		GetCompanyPromoCodes,
+		GetPromoCodeById,
+		UpdatePromoCodeById,
+		DeletePromoCodeById,
+	})
+}
@@ -0,0 +1,338 @@
+package controller
+
+import (
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	database "mynute-go/core/src/config/db"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/lib/review"
+	"mynute-go/core/src/middleware"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetCompanyReviews lists the reviews of a company
+//
+//	@Summary		List reviews
+//	@Description	Paginated reviews of the company, newest first, filterable by status, employee, service and branch
+//	@Tags			Review
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			company_id		path		string	true	"Company ID"
+//	@Param			status			query		string	false	"INVITED, PENDING, PUBLISHED or REJECTED"
+//	@Param			employee_id		query		string	false	"Employee ID"
+//	@Param			service_id		query		string	false	"Service ID"
+//	@Param			branch_id		query		string	false	"Branch ID"
+//	@Param			page			query		int		false	"Page number"				default(1)
+//	@Param			page_size		query		int		false	"Number of items per page"	default(10)
+//	@Produce		json
+//	@Success		200	{object}	DTO.ReviewList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/reviews [get]
+func GetCompanyReviews(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	page := c.QueryInt("page", 1)
+	pageSize := c.QueryInt("page_size", 10)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	query := tx.Model(&model.Review{}).Where("company_id = ?", companyID)
+	if status := strings.ToUpper(c.Query("status")); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	for _, column := range []string{"employee_id", "service_id", "branch_id"} {
+		value := c.Query(column)
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid %s", column))
+		}
+		query = query.Where(column+" = ?", id)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	var reviews []model.Review
+	if err := query.Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&reviews).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	list := DTO.ReviewList{
+		Reviews:    make([]DTO.Review, 0, len(reviews)),
+		TotalCount: int(total),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	for i := range reviews {
+		list.Reviews = append(list.Reviews, reviewDTO(&reviews[i]))
+	}
+	if err := lib.ResponseFactory(c).Send(200, &list); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetReviewById retrieves a review by ID
+//
+//	@Summary		Get review
+//	@Description	Retrieve a review by its ID
+//	@Tags			Review
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Review ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.Review
+//	@Failure		404	{object}	DTO.ErrorResponse
+//	@Router			/review/{id} [get]
+func GetReviewById(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	r, err := loadReview(tx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+	if err := lib.ResponseFactory(c).Send(200, reviewDTO(r)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// ModerateReviewById publishes or rejects a review
+//
+//	@Summary		Moderate review
+//	@Description	Publish or reject a review answered by the client. Only published reviews count in the ratings of the employee, service and branch.
+//	@Tags			Review
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string				true	"Review ID"
+//	@Param			moderation	body		DTO.ModerateReview	true	"Moderation"
+//	@Success		200			{object}	DTO.Review
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Failure		409			{object}	DTO.ErrorResponse
+//	@Router			/review/{id}/moderate [patch]
+func ModerateReviewById(c *fiber.Ctx) (err error) {
+	var body DTO.ModerateReview
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	r, err := loadReview(tx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+	if err = database.LockForUpdate(tx, r, "id", r.ID.String()); err != nil {
+		return err
+	}
+	if err = review.Moderate(tx, r, strings.ToUpper(body.Status), body.ModerationNote, auditFromRequest(c).ActorID, time.Now()); err != nil {
+		return err
+	}
+
+	if err = lib.ResponseFactory(c).Send(200, reviewDTO(r)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetReviewInvitation gets the appointment a client was emailed a link to review
+//
+//	@Summary		Get review invitation
+//	@Description	The appointment the client was emailed a link to review. The link stops working once answered.
+//	@Tags			Review
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			token			path		string	true	"Token of the emailed link"
+//	@Produce		json
+//	@Success		200	{object}	DTO.ReviewInvitation
+//	@Failure		404	{object}	DTO.ErrorResponse
+//	@Router			/review_invitation/{token} [get]
+func GetReviewInvitation(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	r, err := loadReviewInvitation(tx, c.Params("token"))
+	if err != nil {
+		return err
+	}
+	res, err := reviewInvitationDTO(tx, r)
+	if err != nil {
+		return err
+	}
+	if err := lib.ResponseFactory(c).Send(200, res); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// SubmitReviewInvitation answers a review through an emailed link
+//
+//	@Summary		Answer review invitation
+//	@Description	Rate the appointment from 1 to 5, optionally rating the employee and the service apart. The review is published once approved by a manager.
+//	@Tags			Review
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	path		string				true	"Token of the emailed link"
+//	@Param			review	body		DTO.SubmitReview	true	"Review"
+//	@Success		200		{object}	DTO.ReviewInvitation
+//	@Failure		400		{object}	DTO.ErrorResponse
+//	@Failure		404		{object}	DTO.ErrorResponse
+//	@Router			/review_invitation/{token} [post]
+func SubmitReviewInvitation(c *fiber.Ctx) (err error) {
+	var body DTO.SubmitReview
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	r, err := loadReviewInvitation(tx, c.Params("token"))
+	if err != nil {
+		return err
+	}
+	if err = database.LockForUpdate(tx, r, "id", r.ID.String()); err != nil {
+		return err
+	}
+	answer := review.Answer{Rating: body.Rating, EmployeeRating: body.EmployeeRating, ServiceRating: body.ServiceRating, Comment: body.Comment}
+	if err = review.Submit(tx, r, answer, time.Now()); err != nil {
+		return err
+	}
+
+	res, err := reviewInvitationDTO(tx, r)
+	if err != nil {
+		return err
+	}
+	if err = lib.ResponseFactory(c).Send(200, res); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+func loadReview(tx *gorm.DB, id string) (*model.Review, error) {
+	var r model.Review
+	if err := tx.Where("id = ?", id).First(&r).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, lib.Error.Review.NotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	return &r, nil
+}
+
+func loadReviewInvitation(tx *gorm.DB, token string) (*model.Review, error) {
+	var r model.Review
+	if err := tx.Where("token = ? AND status = ?", token, model.ReviewInvited).First(&r).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, lib.Error.Review.NotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	return &r, nil
+}
+
+func reviewDTO(r *model.Review) DTO.Review {
+	return DTO.Review{
+		ID:             r.ID,
+		CompanyID:      r.CompanyID,
+		AppointmentID:  r.AppointmentID,
+		ClientID:       r.ClientID,
+		BranchID:       r.BranchID,
+		EmployeeID:     r.EmployeeID,
+		ServiceID:      r.ServiceID,
+		Status:         r.Status,
+		Rating:         r.Rating,
+		EmployeeRating: r.EmployeeRating,
+		ServiceRating:  r.ServiceRating,
+		Comment:        r.Comment,
+		SubmittedAt:    r.SubmittedAt,
+		ModeratedByID:  r.ModeratedByID,
+		ModeratedAt:    r.ModeratedAt,
+		ModerationNote: r.ModerationNote,
+		CreatedAt:      r.CreatedAt,
+	}
+}
+
+// reviewInvitationDTO describes the reviewed appointment to its client.
+func reviewInvitationDTO(tx *gorm.DB, r *model.Review) (*DTO.ReviewInvitation, error) {
+	var appointment model.Appointment
+	if err := tx.Where("id = ?", r.AppointmentID).First(&appointment).Error; err != nil {
+		return nil, lib.Error.Appointment.NotFound.WithError(err)
+	}
+	var company model.Company
+	if err := tx.Where("id = ?", r.CompanyID).First(&company).Error; err != nil {
+		return nil, lib.Error.Company.NotFound.WithError(err)
+	}
+	var service model.Service
+	if err := tx.Select("id", "name").Where("id = ?", r.ServiceID).First(&service).Error; err != nil {
+		return nil, lib.Error.General.RecordNotFound.WithError(err)
+	}
+	var employee model.Employee
+	if err := tx.Select("id", "name", "surname").Where("id = ?", r.EmployeeID).First(&employee).Error; err != nil {
+		return nil, lib.Error.Employee.NotFound.WithError(err)
+	}
+	return &DTO.ReviewInvitation{
+		ID:           r.ID,
+		CompanyName:  company.TradeName,
+		ServiceName:  service.Name,
+		EmployeeName: strings.TrimSpace(employee.Name + " " + employee.Surname),
+		StartTime:    appointment.StartTime,
+		TimeZone:     appointment.TimeZone,
+		Status:       r.Status,
+	}, nil
+}
+
+// Review registers the review controllers
+func Review(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		GetCompanyReviews,
+		GetReviewById,
+		ModerateReviewById,
+		GetReviewInvitation,
+		SubmitReviewInvitation,
+	})
+}
@@ -0,0 +1,546 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	database "mynute-go/core/src/config/db"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/middleware"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateScheduleTemplate creates a schedule template
+//
+//	@Summary		Create schedule template
+//	@Description	Create a named week of work ranges that can be applied to many employees or branches at once
+//	@Tags			ScheduleTemplate
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			schedule_template	body		DTO.CreateScheduleTemplate	true	"Schedule template"
+//	@Success		200					{object}	DTO.ScheduleTemplate
+//	@Failure		400					{object}	DTO.ErrorResponse
+//	@Router			/schedule_template [post]
+func CreateScheduleTemplate(c *fiber.Ctx) error {
+	var body DTO.CreateScheduleTemplate
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	template := model.ScheduleTemplate{
+		CompanyID:   body.CompanyID,
+		Name:        body.Name,
+		Description: body.Description,
+		Ranges:      scheduleTemplateRanges(body.Ranges),
+	}
+	if err := tx.Create(&template).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, scheduleTemplateDTO(&template)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetCompanyScheduleTemplates lists the schedule templates of a company
+//
+//	@Summary		List schedule templates
+//	@Description	List the schedule templates of a company
+//	@Tags			ScheduleTemplate
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			company_id		path		string	true	"Company ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.ScheduleTemplateList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/schedule_templates [get]
+func GetCompanyScheduleTemplates(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	var templates []model.ScheduleTemplate
+	if err := tx.Preload("Ranges", func(db *gorm.DB) *gorm.DB {
+		return db.Order("weekday, start_time")
+	}).Where("company_id = ?", companyID).Order("name").Find(&templates).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	list := DTO.ScheduleTemplateList{ScheduleTemplates: make([]DTO.ScheduleTemplate, 0, len(templates))}
+	for i := range templates {
+		list.ScheduleTemplates = append(list.ScheduleTemplates, *scheduleTemplateDTO(&templates[i]))
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, &list); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetScheduleTemplateById retrieves a schedule template by ID
+//
+//	@Summary		Get schedule template
+//	@Description	Retrieve a schedule template by its ID
+//	@Tags			ScheduleTemplate
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Schedule template ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.ScheduleTemplate
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/schedule_template/{id} [get]
+func GetScheduleTemplateById(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	template, err := loadScheduleTemplate(tx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, scheduleTemplateDTO(template)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// UpdateScheduleTemplateById updates a schedule template by ID
+//
+//	@Summary		Update schedule template
+//	@Description	Update the name, description or ranges of a schedule template. Schedules it was applied to are not changed.
+//	@Tags			ScheduleTemplate
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		string						true	"Schedule template ID"
+//	@Param			schedule_template	body		DTO.UpdateScheduleTemplate	true	"Schedule template"
+//	@Success		200					{object}	DTO.ScheduleTemplate
+//	@Failure		400					{object}	DTO.ErrorResponse
+//	@Router			/schedule_template/{id} [patch]
+func UpdateScheduleTemplateById(c *fiber.Ctx) (err error) {
+	var body DTO.UpdateScheduleTemplate
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	template, err := loadScheduleTemplate(tx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	changes := map[string]any{}
+	if body.Name != nil {
+		template.Name = *body.Name
+		changes["name"] = template.Name
+	}
+	if body.Description != nil {
+		template.Description = *body.Description
+		changes["description"] = template.Description
+	}
+	if len(changes) == 0 && body.Ranges == nil {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("no changes provided"))
+	}
+	if body.Ranges != nil {
+		if err = template.ReplaceRanges(tx, scheduleTemplateRanges(*body.Ranges)); err != nil {
+			return err
+		}
+	}
+	if len(changes) > 0 {
+		if err = tx.Model(template).Updates(changes).Error; err != nil {
+			return lib.Error.General.UpdatedError.WithError(err)
+		}
+	}
+
+	if err = lib.ResponseFactory(c).Send(200, scheduleTemplateDTO(template)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// DeleteScheduleTemplateById deletes a schedule template by ID
+//
+//	@Summary		Delete schedule template
+//	@Description	Delete a schedule template by its ID. Schedules it was applied to are kept.
+//	@Tags			ScheduleTemplate
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Schedule template ID"
+//	@Produce		json
+//	@Success		200
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/schedule_template/{id} [delete]
+func DeleteScheduleTemplateById(c *fiber.Ctx) error {
+	return DeleteOneById(c, &model.ScheduleTemplate{})
+}
+
+// ApplyScheduleTemplate applies a schedule template to employees or branches
+//
+//	@Summary		Apply schedule template
+//	@Description	Create the ranges of the template for each employee at a branch, or as opening hours of each branch. Every range is validated like one created by hand, and nothing is saved when a target fails. With preview the changes are only returned.
+//	@Tags			ScheduleTemplate
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Schedule template ID"
+//	@Param			apply	body		DTO.ApplyScheduleTemplate	true	"Targets"
+//	@Success		200		{object}	DTO.ScheduleDiff
+//	@Failure		400		{object}	DTO.ErrorResponse
+//	@Router			/schedule_template/{id}/apply [post]
+func ApplyScheduleTemplate(c *fiber.Ctx) (err error) {
+	var body DTO.ApplyScheduleTemplate
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+	if len(body.EmployeeIDs) == 0 && len(body.BranchIDs) == 0 {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("employee_ids or branch_ids is required"))
+	}
+	if len(body.EmployeeIDs) > 0 && body.BranchID == uuid.Nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("branch_id is required to apply a template to employees"))
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	template, err := loadScheduleTemplate(tx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	var targets []scheduleTarget
+	for _, employeeID := range body.EmployeeIDs {
+		targets = append(targets, scheduleTarget{
+			EmployeeID: &employeeID,
+			BranchID:   body.BranchID,
+			Apply: func(tx *gorm.DB) ([]model.WorkRangeBase, []model.WorkRangeBase, error) {
+				ranges, err := template.EmployeeRanges(tx, employeeID, body.BranchID)
+				if err != nil {
+					return nil, nil, err
+				}
+				removed, err := model.ReplaceEmployeeSchedule(tx, employeeID, ranges, body.Replace)
+				return employeeRangeBases(ranges), employeeRangeBases(removed), err
+			},
+		})
+	}
+	for _, branchID := range body.BranchIDs {
+		targets = append(targets, scheduleTarget{
+			BranchID: branchID,
+			Apply: func(tx *gorm.DB) ([]model.WorkRangeBase, []model.WorkRangeBase, error) {
+				ranges, err := template.BranchRanges(tx, branchID)
+				if err != nil {
+					return nil, nil, err
+				}
+				removed, err := model.ReplaceBranchSchedule(tx, branchID, ranges, body.Replace)
+				return branchRangeBases(ranges), branchRangeBases(removed), err
+			},
+		})
+	}
+
+	diff, err := applyScheduleTargets(tx, targets, body.Preview)
+	if err != nil {
+		return err
+	}
+
+	if diff.Applied {
+		invalidateAvailability(c)
+	}
+	if err = lib.ResponseFactory(c).Send(200, diff); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// CopyEmployeeWorkSchedule copies the work schedule of another employee
+//
+//	@Summary		Copy work schedule
+//	@Description	Create for the employee the work ranges of another one, keeping the services both offer. Every range is validated like one created by hand. With preview the changes are only returned.
+//	@Tags			Employee
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			employee_id	path		string							true	"Employee ID"
+//	@Param			copy		body		DTO.CopyEmployeeWorkSchedule	true	"Source"
+//	@Success		200			{object}	DTO.ScheduleDiff
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Router			/employee/{employee_id}/work_schedule/copy [post]
+func CopyEmployeeWorkSchedule(c *fiber.Ctx) (err error) {
+	var body DTO.CopyEmployeeWorkSchedule
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+	employeeID, err := uuid.Parse(c.Params("employee_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid employee_id"))
+	}
+	if body.FromEmployeeID == uuid.Nil || body.FromEmployeeID == employeeID {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("from_employee_id must be another employee"))
+	}
+
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	ranges, err := model.CopyEmployeeRanges(tx, body.FromEmployeeID, employeeID, body.BranchID)
+	if err != nil {
+		return err
+	}
+	if len(ranges) == 0 {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("employee %s has no work ranges to copy", body.FromEmployeeID))
+	}
+
+	// One target per branch, so a branch the employee does not work at leaves the others
+	byBranch := map[uuid.UUID][]model.EmployeeWorkRange{}
+	var branchIDs []uuid.UUID
+	for _, wr := range ranges {
+		if _, ok := byBranch[wr.BranchID]; !ok {
+			branchIDs = append(branchIDs, wr.BranchID)
+		}
+		byBranch[wr.BranchID] = append(byBranch[wr.BranchID], wr)
+	}
+	var targets []scheduleTarget
+	for _, branchID := range branchIDs {
+		branchRanges := byBranch[branchID]
+		targets = append(targets, scheduleTarget{
+			EmployeeID: &employeeID,
+			BranchID:   branchID,
+			Apply: func(tx *gorm.DB) ([]model.WorkRangeBase, []model.WorkRangeBase, error) {
+				removed, err := model.ReplaceEmployeeSchedule(tx, employeeID, branchRanges, body.Replace)
+				return employeeRangeBases(branchRanges), employeeRangeBases(removed), err
+			},
+		})
+	}
+
+	diff, err := applyScheduleTargets(tx, targets, body.Preview)
+	if err != nil {
+		return err
+	}
+
+	if diff.Applied {
+		invalidateAvailability(c)
+	}
+	if err = lib.ResponseFactory(c).Send(200, diff); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// scheduleTarget is an employee at a branch, or a branch when EmployeeID is nil, getting
+// new work ranges. Apply creates them, returning the added and removed ranges.
+type scheduleTarget struct {
+	EmployeeID *uuid.UUID
+	BranchID   uuid.UUID
+	Apply      func(tx *gorm.DB) (added, removed []model.WorkRangeBase, err error)
+}
+
+// applyScheduleTargets applies each target in its own savepoint, so the failure of one is
+// reported without hiding the changes of the others. Nothing is kept on preview or when a
+// target fails.
+func applyScheduleTargets(tx *gorm.DB, targets []scheduleTarget, preview bool) (*DTO.ScheduleDiff, error) {
+	const previewSavepoint = "schedule_preview"
+	if preview {
+		if err := tx.SavePoint(previewSavepoint).Error; err != nil {
+			return nil, lib.Error.General.InternalError.WithError(err)
+		}
+	}
+
+	diff := &DTO.ScheduleDiff{Targets: make([]DTO.ScheduleTargetDiff, 0, len(targets))}
+	var failures []error
+	for i, target := range targets {
+		savepoint := fmt.Sprintf("schedule_target_%d", i)
+		if err := tx.SavePoint(savepoint).Error; err != nil {
+			return nil, lib.Error.General.InternalError.WithError(err)
+		}
+
+		targetDiff := DTO.ScheduleTargetDiff{EmployeeID: target.EmployeeID, BranchID: target.BranchID}
+		added, removed, err := target.Apply(tx)
+		if err != nil {
+			if rbErr := tx.RollbackTo(savepoint).Error; rbErr != nil {
+				return nil, lib.Error.General.InternalError.WithError(rbErr)
+			}
+			targetDiff.Error = scheduleErrorMessage(err)
+			failures = append(failures, fmt.Errorf("%s: %s", scheduleTargetName(target), targetDiff.Error))
+		}
+		targetDiff.Added = scheduleDiffRanges(added)
+		targetDiff.Removed = scheduleDiffRanges(removed)
+		diff.Targets = append(diff.Targets, targetDiff)
+	}
+
+	if preview {
+		if err := tx.RollbackTo(previewSavepoint).Error; err != nil {
+			return nil, lib.Error.General.InternalError.WithError(err)
+		}
+		return diff, nil
+	}
+	if len(failures) > 0 {
+		return nil, lib.Error.ScheduleTemplate.ApplyFailed.WithError(errors.Join(failures...))
+	}
+	diff.Applied = true
+	return diff, nil
+}
+
+func scheduleTargetName(target scheduleTarget) string {
+	if target.EmployeeID != nil {
+		return fmt.Sprintf("employee %s at branch %s", target.EmployeeID, target.BranchID)
+	}
+	return fmt.Sprintf("branch %s", target.BranchID)
+}
+
+// scheduleErrorMessage flattens the error of a target, with the messages it wraps.
+func scheduleErrorMessage(err error) string {
+	var e lib.ErrorStruct
+	if !errors.As(err, &e) {
+		return err.Error()
+	}
+	keys := make([]int, 0, len(e.InnerError))
+	for k := range e.InnerError {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	parts := []string{e.DescriptionEn}
+	for _, k := range keys {
+		parts = append(parts, e.InnerError[k])
+	}
+	return strings.Join(parts, ": ")
+}
+
+func scheduleDiffRanges(ranges []model.WorkRangeBase) []DTO.ScheduleDiffRange {
+	out := make([]DTO.ScheduleDiffRange, 0, len(ranges))
+	for _, wr := range ranges {
+		loc, err := time.LoadLocation(wr.TimeZone)
+		if err != nil {
+			loc = time.UTC
+		}
+		out = append(out, DTO.ScheduleDiffRange{
+			BranchID:  wr.BranchID,
+			Weekday:   uint8(wr.Weekday),
+			StartTime: wr.StartTime.In(loc).Format("15:04"),
+			EndTime:   wr.EndTime.In(loc).Format("15:04"),
+			TimeZone:  wr.TimeZone,
+		})
+	}
+	return out
+}
+
+func employeeRangeBases(ranges []model.EmployeeWorkRange) []model.WorkRangeBase {
+	bases := make([]model.WorkRangeBase, len(ranges))
+	for i := range ranges {
+		bases[i] = ranges[i].WorkRangeBase
+	}
+	return bases
+}
+
+func branchRangeBases(ranges []model.BranchWorkRange) []model.WorkRangeBase {
+	bases := make([]model.WorkRangeBase, len(ranges))
+	for i := range ranges {
+		bases[i] = ranges[i].WorkRangeBase
+	}
+	return bases
+}
+
+func loadScheduleTemplate(tx *gorm.DB, id string) (*model.ScheduleTemplate, error) {
+	var template model.ScheduleTemplate
+	if err := tx.Preload("Ranges", func(db *gorm.DB) *gorm.DB {
+		return db.Order("weekday, start_time")
+	}).Where("id = ?", id).First(&template).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, lib.Error.ScheduleTemplate.NotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	return &template, nil
+}
+
+func scheduleTemplateRanges(ranges []DTO.ScheduleTemplateRange) []model.ScheduleTemplateRange {
+	out := make([]model.ScheduleTemplateRange, 0, len(ranges))
+	for _, r := range ranges {
+		out = append(out, model.ScheduleTemplateRange{
+			Weekday:   time.Weekday(r.Weekday),
+			StartTime: r.StartTime,
+			EndTime:   r.EndTime,
+		})
+	}
+	return out
+}
+
+func scheduleTemplateDTO(t *model.ScheduleTemplate) *DTO.ScheduleTemplate {
+	ranges := make([]DTO.ScheduleTemplateRange, 0, len(t.Ranges))
+	for _, r := range t.Ranges {
+		ranges = append(ranges, DTO.ScheduleTemplateRange{
+			Weekday:   uint8(r.Weekday),
+			StartTime: r.StartTime,
+			EndTime:   r.EndTime,
+		})
+	}
+	return &DTO.ScheduleTemplate{
+		ID:          t.ID,
+		CompanyID:   t.CompanyID,
+		Name:        t.Name,
+		Description: t.Description,
+		Ranges:      ranges,
+	}
+}
+
+// ScheduleTemplate registers the schedule template controllers
+func ScheduleTemplate(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		CreateScheduleTemplate,
+		GetCompanyScheduleTemplates,
+		GetScheduleTemplateById,
+		UpdateScheduleTemplateById,
+		DeleteScheduleTemplateById,
+		ApplyScheduleTemplate,
+		CopyEmployeeWorkSchedule,
+	})
+}
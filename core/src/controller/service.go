@@ -6,11 +6,15 @@ import (
 	"fmt"
 	DTO "mynute-go/core/src/config/api/dto"
 	dJSON "mynute-go/core/src/config/api/dto/json"
+	database "mynute-go/core/src/config/db"
 	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/config/namespace"
 	"mynute-go/core/src/handler"
 	"mynute-go/core/src/lib"
 	"mynute-go/core/src/middleware"
+	"mynute-go/core/src/service/availability"
 	"mynute-go/debug"
+	"slices"
 	"strconv"
 	"time"
 
@@ -129,6 +133,7 @@ func UpdateServiceById(c *fiber.Ctx) error {
 		return err
 	}
 
+	invalidateAvailability(c)
 	if err := lib.ResponseFactory(c).SendDTO(200, &service, &DTO.Service{}); err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
@@ -151,7 +156,11 @@ func UpdateServiceById(c *fiber.Ctx) error {
 //	@Failure		404	{object}	nil
 //	@Router			/service/{id} [delete]
 func DeleteServiceById(c *fiber.Ctx) error {
-	return DeleteOneById(c, &model.Service{})
+	if err := DeleteOneById(c, &model.Service{}); err != nil {
+		return err
+	}
+	invalidateAvailability(c)
+	return nil
 }
 
 // UpdateServiceImages updates images of a service
@@ -220,19 +229,17 @@ func DeleteServiceImage(c *fiber.Ctx) error {
 //	@Param			date_forward_start	query	number	true	"The start date for the forward search in number format"
 //	@Param			date_forward_end	query	number	true	"The end date for the forward search in number format"
 //	@Param			client_id			query	string	false	"Client ID to filter out slots where the client already has appointments"
+//	@Param			dependent_id		query	string	false	"With client_id, the dependent of the client attending instead"
+//	@Param			branch_id			query	string	false	"Only slots at this branch"
+//	@Param			employee_id			query	string	false	"Only slots with this employee"
 //	@Produce		json
 //	@Success		200	{object}	DTO.ServiceAvailability
 //	@Failure		400	{object}	DTO.ErrorResponse
 //	@Router			/service/{id}/availability [get]
 func GetServiceAvailability(c *fiber.Ctx) error {
-	// Step 1: Validate and parse query parameters
-	serviceIDStr := c.Params("id")
-	if serviceIDStr == "" {
-		return lib.Error.General.BadRequest.WithError(errors.New("id query parameter is required"))
-	}
-	serviceID, err := uuid.Parse(serviceIDStr)
+	query, err := availabilityQuery(c)
 	if err != nil {
-		return lib.Error.General.BadRequest.WithError(errors.New("invalid id"))
+		return err
 	}
 	date_forward_start := c.Query("date_forward_start")
 	if date_forward_start == "" {
@@ -263,432 +270,313 @@ func GetServiceAvailability(c *fiber.Ctx) error {
 		return lib.Error.General.BadRequest.WithError(errors.New("date_forward_start must not be negative"))
 	}
 
-	companyIDStr := c.Get("X-Company-ID")
-	companyID, err := uuid.Parse(companyIDStr)
-	if err != nil {
-		return lib.Error.General.BadRequest.WithError(errors.New("invalid X-Company-ID"))
-	}
+	now := time.Now().In(query.Location)
+	query.From, query.To = now.AddDate(0, 0, dfs), now.AddDate(0, 0, dfe)
 
-	timezone := c.Query("timezone")
-	if timezone == "" {
-		timezone = "UTC"
+	tx, err := lib.Session(c)
+	if err != nil {
+		return lib.Error.General.InternalError.WithError(err)
 	}
-	loc, err := time.LoadLocation(timezone)
+	result, err := availability.Find(availability.GormStore{DB: tx}, query)
 	if err != nil {
-		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid timezone: %s", timezone))
+		return err
 	}
 
-	tx, err := lib.Session(c)
+	Availability, err := serviceAvailabilityDTO(result)
 	if err != nil {
 		return lib.Error.General.InternalError.WithError(err)
 	}
 
-	// Truncate to midnight in the target timezone to avoid partial day inclusion
-	now := time.Now().In(loc)
-	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
-	startDate := midnight.AddDate(0, 0, dfs)
-	endDate := midnight.AddDate(0, 0, dfe).Add(24 * time.Hour) // Para incluir o dia final inteiro
-
-	// Fetch service duration early - needed for slot validation
-	var serviceDuration uint16
-	if err := tx.Model(&model.Service{}).
-		Where("id = ?", serviceID).
-		Pluck("duration", &serviceDuration).Error; err != nil {
-		return lib.Error.General.InternalError.WithError(err)
-	}
+	debug.Output("controller_GetServiceAvailability", Availability)
 
-	// =========================================================================
-	// Step 2: Fetch all necessary data in fewer, more efficient queries
-	// =========================================================================
+	return lib.ResponseFactory(c).Send(200, Availability)
+}
 
-	// --- 2a. Fetch all employee work ranges for the service, with Employee and Branch info
-	var empRanges []model.EmployeeWorkRange
-	err = tx.
-		Joins("JOIN employee_work_range_services es ON es.employee_work_range_id = employee_work_ranges.id").
-		Joins("JOIN employee_branches eb ON eb.employee_id = employee_work_ranges.employee_id AND eb.branch_id = employee_work_ranges.branch_id").
-		Where("es.service_id = ?", serviceID).
-		Preload("Employee"). // Preload Employee data to get SlotTimeDiff and density
-		Preload("Branch").   // Preload Branch data for the response
-		Find(&empRanges).Error
+// GetServiceNextAvailable retrieves the first free slot of a service
+//
+//	@Summary		Get the next available slot of a service
+//	@Description	Retrieve the first free slot of a service across its employees and branches, with the employee its assignment strategy picks
+//	@Tags			Service
+//	@Security		ApiKeyAuth
+//	@Param			X-Company-ID	header	string	true	"X-Company-ID"
+//	@Param			id				path	string	true	"Service ID"
+//	@Param			timezone		query	string	false	"Client Time Zone (IANA format, e.g., America/New_York)"
+//	@Param			days			query	number	false	"How many days ahead to search, up to 100"	default(31)
+//	@Param			client_id		query	string	false	"Client ID to skip slots where the client already has appointments"
+//	@Param			dependent_id	query	string	false	"With client_id, the dependent of the client attending instead"
+//	@Param			branch_id		query	string	false	"Only slots at this branch"
+//	@Param			employee_id		query	string	false	"Only slots with this employee"
+//	@Produce		json
+//	@Success		200	{object}	DTO.NextAvailableSlot
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Failure		404	{object}	DTO.ErrorResponse
+//	@Router			/service/{id}/next-available [get]
+func GetServiceNextAvailable(c *fiber.Ctx) error {
+	query, err := availabilityQuery(c)
 	if err != nil {
 		return err
 	}
-
-	// --- 2b. Fetch all appointments for the relevant employees and date range in ONE query.
-
-	// Extrai os IDs dos funcionários para filtrar a query de agendamentos.
-	employeeIDSet := make(map[uuid.UUID]struct{})
-	for _, er := range empRanges {
-		employeeIDSet[er.EmployeeID] = struct{}{}
+	days, err := strconv.Atoi(c.Query("days", "31"))
+	if err != nil || days < 1 || days > 100 {
+		return lib.Error.General.BadRequest.WithError(errors.New("days must be a number from 1 to 100"))
 	}
+	now := time.Now().In(query.Location)
+	query.From, query.To = now, now.AddDate(0, 0, days-1)
 
-	// Agora, convertemos o set de volta para um slice para usar na query do GORM.
-	employeeIDs := make([]uuid.UUID, 0, len(employeeIDSet))
-	for id := range employeeIDSet {
-		employeeIDs = append(employeeIDs, id)
+	tx, err := lib.Session(c)
+	if err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	next, err := availability.Next(availability.GormStore{DB: tx}, query)
+	if err != nil {
+		return err
+	}
+	if next == nil || len(next.EmployeeIDs) == 0 {
+		return lib.Error.Service.NoSlotAvailable
 	}
 
-	// TODO: Add pagination
-	// TODO: Add caching
-
-	type appointmentCountResult struct {
-		EmployeeID uuid.UUID
-		StartTime  time.Time
-		Count      int64
+	slot := DTO.NextAvailableSlot{
+		ServiceID:   query.ServiceID,
+		BranchID:    next.BranchID,
+		StartTime:   next.Start.Format(time.RFC3339),
+		TimeZone:    query.Location.String(),
+		EmployeeID:  next.EmployeeIDs[0],
+		EmployeesID: next.EmployeeIDs,
+		Price:       next.Terms.Price,
+		Duration:    next.Terms.Duration,
+	}
+	if err := convertByJSON(next.Branch, &slot.Branch); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
 	}
+	return lib.ResponseFactory(c).Send(200, &slot)
+}
 
-	// Fetch actual appointments with their details for overlap checking
-	var appointments []model.Appointment
-	if len(employeeIDs) > 0 {
-		err = tx.Model(&model.Appointment{}).
-			Where("company_id = ? AND employee_id IN ? AND is_cancelled = false", companyID, employeeIDs).
-			Where("start_time >= ? AND start_time < ?", startDate, endDate).
-			Preload("Service"). // Need service info for duration
-			Find(&appointments).Error
+// availabilityQuery reads the service, company, time zone and filters of an availability request.
+// The days to search are left to the caller.
+func availabilityQuery(c *fiber.Ctx) (availability.Query, error) {
+	var query availability.Query
+	var err error
+	if query.ServiceID, err = uuid.Parse(c.Params("id")); err != nil {
+		return query, lib.Error.General.BadRequest.WithError(errors.New("invalid id"))
+	}
+	if query.CompanyID, err = uuid.Parse(c.Get(namespace.HeadersKey.Company)); err != nil {
+		return query, lib.Error.General.BadRequest.WithError(errors.New("invalid X-Company-ID"))
+	}
+	timezone := c.Query("timezone", "UTC")
+	if query.Location, err = time.LoadLocation(timezone); err != nil {
+		return query, lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid timezone: %s", timezone))
+	}
+	for param, dest := range map[string]*uuid.UUID{"client_id": &query.ClientID, "branch_id": &query.BranchID, "employee_id": &query.EmployeeID} {
+		if value := c.Query(param); value != "" {
+			if *dest, err = uuid.Parse(value); err != nil {
+				return query, lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid %s format: must be a valid UUID", param))
+			}
+		}
+	}
+	if value := c.Query("dependent_id"); value != "" {
+		dependentID, err := uuid.Parse(value)
 		if err != nil {
-			return err
+			return query, lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid dependent_id format: must be a valid UUID"))
 		}
+		query.DependentID = &dependentID
 	}
+	return query, nil
+}
 
-	// Build appointment counts map (for density checking)
-	var appointmentCounts []appointmentCountResult
-	appointmentsByEmployee := make(map[uuid.UUID][]model.Appointment)
-
-	for _, appt := range appointments {
-		appointmentsByEmployee[appt.EmployeeID] = append(appointmentsByEmployee[appt.EmployeeID], appt)
-
-		// Count appointments by start time for density checking
-		found := false
-		for i := range appointmentCounts {
-			if appointmentCounts[i].EmployeeID == appt.EmployeeID &&
-				appointmentCounts[i].StartTime.Equal(appt.StartTime) {
-				appointmentCounts[i].Count++
-				found = true
-				break
-			}
+// serviceAvailabilityDTO groups the slots by date and branch, with the employees,
+// branches and terms they refer to.
+func serviceAvailabilityDTO(result *availability.Result) (*DTO.ServiceAvailability, error) {
+	Availability := &DTO.ServiceAvailability{ServiceID: result.ServiceID}
+
+	dates := map[string]map[uuid.UUID]int{} // date → branch → index in AvailableDates
+	employees := map[uuid.UUID]bool{}
+	branches := map[uuid.UUID]bool{}
+	for _, slot := range result.Slots {
+		date := slot.Start.Format("2006-01-02")
+		if _, ok := dates[date]; !ok {
+			dates[date] = map[uuid.UUID]int{}
 		}
-		if !found {
-			appointmentCounts = append(appointmentCounts, appointmentCountResult{
-				EmployeeID: appt.EmployeeID,
-				StartTime:  appt.StartTime,
-				Count:      1,
+		i, ok := dates[date][slot.BranchID]
+		if !ok {
+			i = len(Availability.AvailableDates)
+			dates[date][slot.BranchID] = i
+			Availability.AvailableDates = append(Availability.AvailableDates, DTO.AvailableDate{
+				Date:           date,
+				BranchID:       slot.BranchID,
+				AvailableTimes: []DTO.AvailableTime{},
 			})
 		}
+		Availability.AvailableDates[i].AvailableTimes = append(Availability.AvailableDates[i].AvailableTimes, DTO.AvailableTime{
+			Time:        slot.Start.Format("15:04"),
+			EmployeesID: slot.EmployeeIDs,
+		})
+
+		for _, empID := range slot.EmployeeIDs {
+			if !slices.ContainsFunc(Availability.Terms, func(t DTO.ServiceTerms) bool { return t.EmployeeID == empID && t.BranchID == slot.BranchID }) {
+				resolved := result.Terms(empID, slot.BranchID)
+				Availability.Terms = append(Availability.Terms, DTO.ServiceTerms{EmployeeID: empID, BranchID: slot.BranchID, Price: resolved.Price, Duration: resolved.Duration})
+			}
+			if employees[empID] {
+				continue
+			}
+			employees[empID] = true
+			var employee DTO.EmployeeBase
+			if err := convertByJSON(result.Employees[empID], &employee); err != nil {
+				return nil, fmt.Errorf("failed to convert employee info: %w", err)
+			}
+			Availability.EmployeeInfo = append(Availability.EmployeeInfo, employee)
+		}
+		if !branches[slot.BranchID] {
+			branches[slot.BranchID] = true
+			var branch DTO.BranchBase
+			if err := convertByJSON(result.Branches[slot.BranchID], &branch); err != nil {
+				return nil, fmt.Errorf("failed to convert branch info: %w", err)
+			}
+			Availability.BranchInfo = append(Availability.BranchInfo, branch)
+		}
 	}
+	return Availability, nil
+}
 
-	// --- 2c. Fetch service-specific densities for employees.
-	var densities []model.EmployeeServiceDensity
-	err = tx.Where("service_id = ? AND employee_id IN ?", serviceID, employeeIDs).Find(&densities).Error
+// convertByJSON copies src into dest through their JSON representation.
+func convertByJSON(src, dest any) error {
+	data, err := json.Marshal(src)
 	if err != nil {
 		return err
 	}
+	return json.Unmarshal(data, dest)
+}
 
-	// =========================================================================
-	// Step 3: Index all fetched data into maps for fast lookups (O(1) access)
-	// =========================================================================
-
-	// --- 3a. Index appointment counts by employee and start time.
-	// Key: "employeeID-2023-10-27T10:30:00Z", Value: count
-	appointmentSlotMap := make(map[string]int64)
-	for _, ac := range appointmentCounts {
-		key := fmt.Sprintf("%s-%s", ac.EmployeeID.String(), ac.StartTime.In(loc).Format(time.RFC3339))
-		appointmentSlotMap[key] = ac.Count
+// CreateServiceOverride sets the price and/or duration of a service for an employee and/or branch
+//
+//	@Summary		Create service override
+//	@Description	Override the price and/or duration of a service for an employee, a branch, or an employee at a branch
+//	@Tags			Service
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"Service ID"
+//	@Accept			json
+//	@Produce		json
+//	@Param			override	body		DTO.CreateServiceOverride	true	"Override"
+//	@Success		200			{object}	DTO.ServiceOverride
+//	@Failure		400			{object}	DTO.ErrorResponse
+//	@Router			/service/{id}/override [post]
+func CreateServiceOverride(c *fiber.Ctx) error {
+	serviceID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(errors.New("invalid service id"))
 	}
-
-	// --- 3b. Index service-specific densities by employee ID.
-	densityMap := make(map[uuid.UUID]uint32)
-	for _, d := range densities {
-		densityMap[d.EmployeeID] = d.Density
+	var body DTO.CreateServiceOverride
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
 	}
 
-	// --- 3c. Index employee work ranges by Weekday and then by BranchID.
-	// This avoids nested loops later.
-	rangesByDayAndBranch := make(map[time.Weekday]map[uuid.UUID][]model.EmployeeWorkRange)
-	for _, r := range empRanges {
-		if _, ok := rangesByDayAndBranch[r.Weekday]; !ok {
-			rangesByDayAndBranch[r.Weekday] = make(map[uuid.UUID][]model.EmployeeWorkRange)
-		}
-		rangesByDayAndBranch[r.Weekday][r.BranchID] = append(rangesByDayAndBranch[r.Weekday][r.BranchID], r)
-	}
-
-	// =========================================================================
-	// Step 4: Process availability using the in-memory maps (NO DB QUERIES HERE)
-	// =========================================================================
-	employeeInfoMap := map[uuid.UUID]DTO.EmployeeBase{}
-	branchInfoMap := map[uuid.UUID]DTO.BranchBase{}
-	availabilityMap := map[string]map[uuid.UUID]map[string][]uuid.UUID{} // date → branch → time → []employeeID
-
-	// Reset endDate to not have the extra 24h for the loop
-	endDate = endDate.Add(-24 * time.Hour)
-	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
-		weekday := d.Weekday()
-
-		// Directly get the branches and employees working on this weekday
-		if branchesForDay, ok := rangesByDayAndBranch[weekday]; ok {
-			for branchID, empRangesInBranch := range branchesForDay {
-				for _, empRange := range empRangesInBranch {
-					emp := empRange.Employee // Already preloaded
-					if emp.SlotTimeDiff <= 0 {
-						continue // Use continue to proceed with the next range
-					}
-
-					// --- Timezone-Correct Shift Calculation ---
-					// 1. Load the branch's specific timezone. Default to UTC if invalid or missing.
-					branchLoc, err := time.LoadLocation(empRange.Branch.TimeZone)
-					if err != nil {
-						branchLoc = time.UTC // Fallback to UTC
-					}
-
-					// 2. Construct the shift start and end times in the branch's actual timezone.
-					shiftStartInBranchTZ := time.Date(d.Year(), d.Month(), d.Day(), empRange.StartTime.Hour(), empRange.StartTime.Minute(), 0, 0, branchLoc)
-					shiftEndInBranchTZ := time.Date(d.Year(), d.Month(), d.Day(), empRange.EndTime.Hour(), empRange.EndTime.Minute(), 0, 0, branchLoc)
-
-					// 3. Convert the branch's shift times to the user's requested timezone for the loop.
-					slot := shiftStartInBranchTZ.In(loc)
-					endOfDay := shiftEndInBranchTZ.In(loc)
-
-					// 4. For today's date, ensure we don't show slots from the past.
-					if dfs == 0 && d.Year() == now.Year() && d.Month() == now.Month() && d.Day() == now.Day() {
-						if slot.Before(now) {
-							slot = now
-						}
-					}
-
-					// 5. Align the final start time to the next valid slot boundary.
-					if emp.SlotTimeDiff > 0 {
-						// Calculate alignment based on the user's timezone view of the slot.
-						minutesSinceMidnight := slot.Hour()*60 + slot.Minute()
-						slotDiff := int(emp.SlotTimeDiff)
-						if remainder := minutesSinceMidnight % slotDiff; remainder != 0 {
-							minutesToAdd := slotDiff - remainder
-							slot = slot.Add(time.Duration(minutesToAdd) * time.Minute)
-						}
-					}
-
-					for slot.Before(endOfDay) {
-
-						// Check availability using the map - THIS IS THE KEY
-						lookupKey := fmt.Sprintf("%s-%s", emp.ID.String(), slot.Format(time.RFC3339))
-						currentBookings := appointmentSlotMap[lookupKey]
-
-						// Determine max capacity for this employee and service
-						maxCapacity := emp.TotalServiceDensity
-						if specificDensity, hasSpecific := densityMap[emp.ID]; hasSpecific {
-							maxCapacity = specificDensity
-						}
-
-						// Check if the current slot has capacity
-						if uint32(currentBookings) < maxCapacity {
-							// Check if there's enough time for the service to complete before the work shift ends
-							slotEndTime := slot.Add(time.Duration(serviceDuration) * time.Minute)
-
-							// Only show this slot if the service can be completed within the work shift
-							if !slotEndTime.After(endOfDay) {
-								// Check for overlaps with existing appointments
-								// A new appointment at [slot, slotEndTime) would overlap with existing appointment at [apptStart, apptEnd) if:
-								// slot < apptEnd AND slotEndTime > apptStart
-								hasOverlap := false
-								empAppointments := appointmentsByEmployee[emp.ID]
-
-								for _, appt := range empAppointments {
-									apptStart := appt.StartTime.In(loc)
-									var apptEnd time.Time
-									if !appt.EndTime.IsZero() {
-										apptEnd = appt.EndTime.In(loc)
-									} else if appt.Service != nil {
-										apptEnd = apptStart.Add(time.Duration(appt.Service.Duration) * time.Minute)
-									} else {
-										// Fallback: assume service duration
-										apptEnd = apptStart.Add(time.Duration(serviceDuration) * time.Minute)
-									}
-
-									// Check if [slot, slotEndTime) overlaps with [apptStart, apptEnd)
-									if slot.Before(apptEnd) && slotEndTime.After(apptStart) {
-										// There's an overlap, but we need to check if density allows it
-										// Count how many appointments at this start time
-										overlapCount := int64(0)
-										for _, a := range empAppointments {
-											aStart := a.StartTime.In(loc)
-											// Count appointments that would overlap with our proposed slot
-											var aEnd time.Time
-											if !a.EndTime.IsZero() {
-												aEnd = a.EndTime.In(loc)
-											} else if a.Service != nil {
-												aEnd = aStart.Add(time.Duration(a.Service.Duration) * time.Minute)
-											} else {
-												aEnd = aStart.Add(time.Duration(serviceDuration) * time.Minute)
-											}
-
-											if slot.Before(aEnd) && slotEndTime.After(aStart) {
-												overlapCount++
-											}
-										}
-
-										if uint32(overlapCount) >= maxCapacity {
-											hasOverlap = true
-											break
-										}
-									}
-								}
-
-								if !hasOverlap {
-									// This slot is available and has enough time, add it to the results
-									dateStr := d.Format("2006-01-02")
-									timeStr := slot.Format("15:04")
-
-									if _, ok := availabilityMap[dateStr]; !ok {
-										availabilityMap[dateStr] = map[uuid.UUID]map[string][]uuid.UUID{}
-									}
-									if _, ok := availabilityMap[dateStr][branchID]; !ok {
-										availabilityMap[dateStr][branchID] = map[string][]uuid.UUID{}
-									}
-									availabilityMap[dateStr][branchID][timeStr] = append(availabilityMap[dateStr][branchID][timeStr], emp.ID)
-
-									// Populate info maps if not already present
-									if _, ok := branchInfoMap[branchID]; !ok {
-										empRangeBranchBytes, err := json.Marshal(empRange.Branch)
-										if err != nil {
-											return fmt.Errorf("failed to marshal branch info: %w", err)
-										}
-										var dtoBranchBase DTO.BranchBase
-										if err := json.Unmarshal(empRangeBranchBytes, &dtoBranchBase); err != nil {
-											return fmt.Errorf("failed to unmarshal branch info: %w", err)
-										}
-										branchInfoMap[branchID] = dtoBranchBase
-									}
-									if _, ok := employeeInfoMap[emp.ID]; !ok {
-										empBytes, err := json.Marshal(emp)
-										if err != nil {
-											return fmt.Errorf("failed to marshal employee info: %w", err)
-										}
-										var dtoEmployeeBase DTO.EmployeeBase
-										if err := json.Unmarshal(empBytes, &dtoEmployeeBase); err != nil {
-											return fmt.Errorf("failed to unmarshal employee info: %w", err)
-										}
-										employeeInfoMap[emp.ID] = dtoEmployeeBase
-									}
-								}
-							}
-						}
-
-						slot = slot.Add(time.Minute * time.Duration(emp.SlotTimeDiff))
-					}
-				}
-			}
-		}
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
 	}
 
-	availableDateMap := map[string]map[uuid.UUID]*DTO.AvailableDate{}
-
-	clientID := c.Query("client_id")
-	var clientAppointments []model.ClientAppointment
-	if clientID != "" {
-		// Validate client_id is a valid UUID
-		if _, err := uuid.Parse(clientID); err != nil {
-			return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid client_id format: must be a valid UUID"))
-		}
-
-		// Calculate the proper end date for fetching client appointments
-		// The endDate variable has an extra 24h added for the availability loop,
-		// but for querying appointments we need to cover the actual requested range:
-		// from startDate (midnight + dfs days) to end of last day (midnight + dfe days)
-		clientQueryEndDate := midnight.AddDate(0, 0, dfe).Add(24 * time.Hour)
+	override := model.ServiceOverride{
+		ServiceID:  serviceID,
+		EmployeeID: body.EmployeeID,
+		BranchID:   body.BranchID,
+		Price:      body.Price,
+		Duration:   body.Duration,
+	}
+	if err := tx.Create(&override).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
 
-		if err := lib.ChangeToPublicSchemaByContext(c); err != nil {
-			return err
-		}
-		// Query without company_id to get ALL client appointments across all companies
-		// This ensures we don't double-book clients who have appointments with other companies
-		if err := tx.Model(&model.ClientAppointment{}).
-			Where("client_id = ? AND is_cancelled = ?", clientID, false).
-			Where("start_time >= ? AND start_time < ?", startDate, clientQueryEndDate).
-			Find(&clientAppointments).Error; err != nil {
-			return lib.Error.General.InternalError.WithError(err)
-		}
-		if err := lib.ChangeToCompanySchemaByContext(c); err != nil {
-			return err
-		}
+	invalidateAvailability(c)
+	if err := lib.ResponseFactory(c).SendDTO(200, &override, &DTO.ServiceOverride{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
 	}
+	return nil
+}
 
-	// serviceDuration is already fetched earlier in the function
+// GetServiceOverrides lists the price and duration overrides of a service
+//
+//	@Summary		List service overrides
+//	@Description	List the price and duration overrides of a service
+//	@Tags			Service
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"Service ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.ServiceOverrideList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/service/{id}/overrides [get]
+func GetServiceOverrides(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
 
-	for date, branches := range availabilityMap {
-		if _, ok := availableDateMap[date]; !ok {
-			availableDateMap[date] = map[uuid.UUID]*DTO.AvailableDate{}
-		}
+	var overrides []model.ServiceOverride
+	if err := tx.Where("service_id = ?", c.Params("id")).Order("created_at").Find(&overrides).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
 
-		for branchID, slots := range branches {
-			if _, ok := availableDateMap[date][branchID]; !ok {
-				availableDateMap[date][branchID] = &DTO.AvailableDate{
-					Date:           date,
-					BranchID:       branchID,
-					AvailableTimes: []DTO.AvailableTime{},
-				}
-			}
+	list := DTO.ServiceOverrideList{Overrides: make([]DTO.ServiceOverride, 0, len(overrides))}
+	for _, o := range overrides {
+		list.Overrides = append(list.Overrides, DTO.ServiceOverride{
+			ID:         o.ID,
+			ServiceID:  o.ServiceID,
+			EmployeeID: o.EmployeeID,
+			BranchID:   o.BranchID,
+			Price:      o.Price,
+			Duration:   o.Duration,
+		})
+	}
 
-		slotLoop:
-			for timeStr, empIDs := range slots {
-				// Filter out times where the client already has an appointment
-				if clientID != "" {
-					// Parse the date and time strings directly into the requested timezone
-					slotTime_RFC3339 := fmt.Sprintf("%sT%s:00", date, timeStr)
-					slotTime, err := time.ParseInLocation("2006-01-02T15:04:05", slotTime_RFC3339, loc)
-					if err != nil {
-						return lib.Error.General.InternalError.WithError(fmt.Errorf("failed to parse slot time: %w", err))
-					}
-					slotEndTime := slotTime.Add(time.Minute * time.Duration(serviceDuration))
-
-					for _, appt := range clientAppointments {
-						// Convert appointment times to the client's requested timezone for comparison
-						apptStart := appt.StartTime.In(loc)
-						apptEnd := appt.EndTime.In(loc)
-
-						// Check for any overlap between [slotTime, slotEndTime) and [apptStart, apptEnd)
-						// Overlap occurs if: slotTime < apptEnd AND slotEndTime > apptStart
-						if slotTime.Before(apptEnd) && slotEndTime.After(apptStart) {
-							continue slotLoop
-						}
-					}
-				}
-				availableDateMap[date][branchID].AvailableTimes = append(
-					availableDateMap[date][branchID].AvailableTimes,
-					DTO.AvailableTime{
-						Time:        timeStr,
-						EmployeesID: empIDs,
-					},
-				)
-			}
-		}
+	if err := lib.ResponseFactory(c).Send(200, &list); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
 	}
+	return nil
+}
 
-	// Flatten into slice
-	var availableDates []DTO.AvailableDate
-	for _, branches := range availableDateMap {
-		for _, ad := range branches {
-			availableDates = append(availableDates, *ad)
-		}
+// DeleteServiceOverride removes a price and duration override of a service
+//
+//	@Summary		Delete service override
+//	@Description	Remove a price and duration override, the service falls back to the less specific terms
+//	@Tags			Service
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Failure		401				{object}	nil
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Param			id				path		string	true	"Service ID"
+//	@Param			override_id		path		string	true	"Override ID"
+//	@Success		200
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/service/{id}/override/{override_id} [delete]
+func DeleteServiceOverride(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
 	}
 
-	// Convert maps to slices
-	var employeeInfo []DTO.EmployeeBase
-	for _, e := range employeeInfoMap {
-		employeeInfo = append(employeeInfo, e)
+	result := tx.Where("id = ? AND service_id = ?", c.Params("override_id"), c.Params("id")).Delete(&model.ServiceOverride{})
+	if result.Error != nil {
+		return lib.Error.General.DeletedError.WithError(result.Error)
 	}
-	var branchInfo []DTO.BranchBase
-	for _, b := range branchInfoMap {
-		branchInfo = append(branchInfo, b)
+	if result.RowsAffected == 0 {
+		return lib.Error.Service.OverrideNotFound
 	}
+	invalidateAvailability(c)
+	return c.SendStatus(200)
+}
 
-	Availability := DTO.ServiceAvailability{
-		ServiceID:      serviceID,
-		AvailableDates: availableDates,
-		EmployeeInfo:   employeeInfo,
-		BranchInfo:     branchInfo,
+// invalidateAvailability drops the cached availability of the company of the request,
+// after a change to its appointments, work ranges, densities or service terms.
+// Inside the request transaction it waits for the commit, the change is dropped on rollback.
+func invalidateAvailability(c *fiber.Ctx) {
+	if companyID, err := uuid.Parse(c.Get(namespace.HeadersKey.Company)); err == nil {
+		database.AfterContextCommit(c, func() { availability.Invalidate(companyID) })
 	}
-
-	debug.Output("controller_GetServiceAvailability", Availability)
-
-	return lib.ResponseFactory(c).Send(200, Availability)
 }
 
 // Service returns a service_controller
@@ -703,5 +591,9 @@ func Service(Gorm *handler.Gorm) {
 		UpdateServiceImages,
 		DeleteServiceImage,
 		GetServiceAvailability,
+		GetServiceNextAvailable,
+		CreateServiceOverride,
+		GetServiceOverrides,
+		DeleteServiceOverride,
 	})
 }
@@ -0,0 +1,422 @@
+package controller
+
+import (
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	database "mynute-go/core/src/config/db"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/lib/payment"
+	"mynute-go/core/src/middleware"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateServicePackage creates a package or membership
+//
+//	@Summary		Create package
+//	@Description	Create a package of credits or a membership with unlimited credits that clients can buy
+//	@Tags			Package
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			package	body		DTO.CreateServicePackage	true	"Package"
+//	@Success		200		{object}	DTO.ServicePackage
+//	@Failure		400		{object}	DTO.ErrorResponse
+//	@Router			/package [post]
+func CreateServicePackage(c *fiber.Ctx) error {
+	var body DTO.CreateServicePackage
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	pkg := model.ServicePackage{
+		CompanyID:    body.CompanyID,
+		Name:         body.Name,
+		Description:  body.Description,
+		Type:         body.Type,
+		Price:        body.Price,
+		Currency:     body.Currency,
+		Credits:      body.Credits,
+		ValidityDays: body.ValidityDays,
+		ServiceIDs:   model.UUIDList(body.ServiceIDs),
+		IsActive:     true,
+	}
+	if pkg.Currency == "" {
+		pkg.Currency = "BRL"
+	}
+	if err := tx.Create(&pkg).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).SendDTO(200, &pkg, &DTO.ServicePackage{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetCompanyServicePackages lists the packages and memberships of a company
+//
+//	@Summary		List packages
+//	@Description	List the packages and memberships of a company
+//	@Tags			Package
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			company_id		path		string	true	"Company ID"
+//	@Param			active			query		bool	false	"Only packages still sold"
+//	@Produce		json
+//	@Success		200	{object}	DTO.ServicePackageList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/packages [get]
+func GetCompanyServicePackages(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	query := tx.Where("company_id = ?", companyID)
+	if c.QueryBool("active") {
+		query = query.Where("is_active = ?", true)
+	}
+	var packages []model.ServicePackage
+	if err := query.Order("created_at").Find(&packages).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	list := DTO.ServicePackageList{Packages: make([]DTO.ServicePackage, 0, len(packages))}
+	for _, p := range packages {
+		list.Packages = append(list.Packages, DTO.ServicePackage{
+			ID:           p.ID,
+			CompanyID:    p.CompanyID,
+			Name:         p.Name,
+			Description:  p.Description,
+			Type:         p.Type,
+			Price:        p.Price,
+			Currency:     p.Currency,
+			Credits:      p.Credits,
+			ValidityDays: p.ValidityDays,
+			ServiceIDs:   p.ServiceIDs,
+			IsActive:     p.IsActive,
+		})
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, &list); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetServicePackageById retrieves a package by ID
+//
+//	@Summary		Get package
+//	@Description	Retrieve a package or membership by its ID
+//	@Tags			Package
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Package ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.ServicePackage
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/package/{id} [get]
+func GetServicePackageById(c *fiber.Ctx) error {
+	var pkg model.ServicePackage
+	if err := GetOneBy("id", c, &pkg, nil, nil); err != nil {
+		return err
+	}
+	if err := lib.ResponseFactory(c).SendDTO(200, &pkg, &DTO.ServicePackage{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// UpdateServicePackageById updates a package by ID
+//
+//	@Summary		Update package
+//	@Description	Update a package or membership. Packages already sold keep the terms they were bought with.
+//	@Tags			Package
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Package ID"
+//	@Param			package	body		DTO.UpdateServicePackage	true	"Package"
+//	@Success		200		{object}	DTO.ServicePackage
+//	@Failure		400		{object}	DTO.ErrorResponse
+//	@Router			/package/{id} [patch]
+func UpdateServicePackageById(c *fiber.Ctx) error {
+	var body DTO.UpdateServicePackage
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	pkg, err := loadServicePackage(tx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	// Applied through a map so that false and 0 are not skipped as zero values.
+	changes := map[string]any{}
+	if body.Name != nil {
+		pkg.Name = *body.Name
+		changes["name"] = pkg.Name
+	}
+	if body.Description != nil {
+		pkg.Description = *body.Description
+		changes["description"] = pkg.Description
+	}
+	if body.Price != nil {
+		pkg.Price = *body.Price
+		changes["price"] = pkg.Price
+	}
+	if body.Credits != nil {
+		pkg.Credits = *body.Credits
+		changes["credits"] = pkg.Credits
+	}
+	if body.ValidityDays != nil {
+		pkg.ValidityDays = *body.ValidityDays
+		changes["validity_days"] = pkg.ValidityDays
+	}
+	if body.ServiceIDs != nil {
+		pkg.ServiceIDs = model.UUIDList(*body.ServiceIDs)
+		changes["service_ids"] = pkg.ServiceIDs
+	}
+	if body.IsActive != nil {
+		pkg.IsActive = *body.IsActive
+		changes["is_active"] = pkg.IsActive
+	}
+	if len(changes) == 0 {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("no changes provided"))
+	}
+	if err := pkg.Validate(); err != nil {
+		return err
+	}
+	if err := tx.Model(pkg).Updates(changes).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).SendDTO(200, pkg, &DTO.ServicePackage{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// DeleteServicePackageById deletes a package by ID
+//
+//	@Summary		Delete package
+//	@Description	Delete a package or membership. Clients who bought it keep their credits.
+//	@Tags			Package
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Package ID"
+//	@Produce		json
+//	@Success		200
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/package/{id} [delete]
+func DeleteServicePackageById(c *fiber.Ctx) error {
+	return DeleteOneById(c, &model.ServicePackage{})
+}
+
+// SellServicePackage records the sale of a package or membership to a client
+//
+//	@Summary		Sell package
+//	@Description	Grant the credits of a package or membership to a client who paid for it at the company, recording the payment
+//	@Tags			Package
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Package ID"
+//	@Param			sale	body		DTO.SellServicePackage	true	"Sale"
+//	@Success		200		{object}	DTO.ClientPackage
+//	@Failure		400		{object}	DTO.ErrorResponse
+//	@Router			/package/{id}/sell [post]
+func SellServicePackage(c *fiber.Ctx) (err error) {
+	var body DTO.SellServicePackage
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+	if body.ClientID == uuid.Nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("client_id is required"))
+	}
+
+	// The credits are only granted together with the payment of the sale
+	tx, end, err := database.ContextTransaction(c)
+	if err != nil {
+		return err
+	}
+	defer func() { end(err) }()
+
+	pkg, err := loadServicePackage(tx, c.Params("id"))
+	if err != nil {
+		return err
+	}
+	if !pkg.IsActive {
+		return lib.Error.Package.Inactive
+	}
+
+	var clients int64
+	if err := tx.Model(&model.Client{}).Where("id = ?", body.ClientID).Count(&clients).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	if clients == 0 {
+		return lib.Error.Client.NotFound
+	}
+
+	sold := pkg.Sell(body.ClientID, time.Now())
+	if pkg.Price > 0 {
+		paid, err := payment.RecordOffline(tx, pkg.CompanyID, body.ClientID, pkg.Price, pkg.Currency, body.PaymentMethod)
+		if err != nil {
+			return err
+		}
+		sold.PaymentID = &paid.ID
+	}
+	if err := tx.Create(sold).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, clientPackageDTO(sold, time.Now())); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetClientCredits returns the credit balance of a client at a company
+//
+//	@Summary		Get client credits
+//	@Description	Credits left in the packages and memberships the client bought, the ones expiring first are used first
+//	@Tags			Package
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			company_id		path		string	true	"Company ID"
+//	@Param			client_id		path		string	true	"Client ID"
+//	@Param			include_expired	query		bool	false	"Also list expired and used up packages"
+//	@Produce		json
+//	@Success		200	{object}	DTO.ClientCreditBalance
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/client/{client_id}/credits [get]
+func GetClientCredits(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+	clientID, err := uuid.Parse(c.Params("client_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid client_id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	query := tx.Where("company_id = ? AND client_id = ?", companyID, clientID)
+	if !c.QueryBool("include_expired") {
+		query = query.Where("expires_at > ?", now)
+	}
+	var packages []model.ClientPackage
+	if err := query.Order("expires_at").Find(&packages).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	balance := DTO.ClientCreditBalance{ClientID: clientID, Packages: make([]DTO.ClientPackage, 0, len(packages))}
+	for i := range packages {
+		p := clientPackageDTO(&packages[i], now)
+		if !p.Expired {
+			if p.Remaining < 0 {
+				balance.Unlimited = true
+			} else {
+				balance.Credits += p.Remaining
+			}
+		}
+		if !p.Expired && p.Remaining == 0 && !c.QueryBool("include_expired") {
+			continue // Used up
+		}
+		balance.Packages = append(balance.Packages, *p)
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, &balance); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+func loadServicePackage(tx *gorm.DB, id string) (*model.ServicePackage, error) {
+	var pkg model.ServicePackage
+	if err := tx.Where("id = ?", id).First(&pkg).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, lib.Error.Package.NotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	return &pkg, nil
+}
+
+func clientPackageDTO(p *model.ClientPackage, now time.Time) *DTO.ClientPackage {
+	return &DTO.ClientPackage{
+		ID:          p.ID,
+		CompanyID:   p.CompanyID,
+		ClientID:    p.ClientID,
+		PackageID:   p.PackageID,
+		Name:        p.Name,
+		Type:        p.Type,
+		Price:       p.Price,
+		Currency:    p.Currency,
+		Credits:     p.Credits,
+		CreditsUsed: p.CreditsUsed,
+		Remaining:   p.Remaining(),
+		ServiceIDs:  p.ServiceIDs,
+		PurchasedAt: p.PurchasedAt,
+		ExpiresAt:   p.ExpiresAt,
+		Expired:     !now.Before(p.ExpiresAt),
+		PaymentID:   p.PaymentID,
+	}
+}
+
+// ServicePackage registers the package and membership controllers
+func ServicePackage(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		CreateServicePackage,
+		GetCompanyServicePackages,
+		GetServicePackageById,
+		UpdateServicePackageById,
+		DeleteServicePackageById,
+		SellServicePackage,
+		GetClientCredits,
+	})
+}
@@ -0,0 +1,457 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/core/src/handler"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/lib/webhook"
+	"mynute-go/core/src/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateWebhook creates a webhook subscription
+//
+//	@Summary		Create webhook
+//	@Description	Subscribe an URL to company events. The signing secret is only returned by this call.
+//	@Tags			Webhook
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			webhook	body		DTO.CreateWebhook	true	"Webhook"
+//	@Success		200		{object}	DTO.WebhookWithSecret
+//	@Failure		400		{object}	DTO.ErrorResponse
+//	@Router			/webhook [post]
+func CreateWebhook(c *fiber.Ctx) error {
+	var body DTO.CreateWebhook
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	// The subscription belongs to the company of the request, never to the one of the body
+	companyID, err := uuid.Parse(c.Get(namespace.HeadersKey.Company))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid X-Company-ID"))
+	}
+	if body.CompanyID != uuid.Nil && body.CompanyID != companyID {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("company ID mismatch"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	subscription := model.WebhookSubscription{
+		CompanyID:   companyID,
+		URL:         body.URL,
+		Description: body.Description,
+		Events:      model.WebhookEventList(body.Events),
+		IsActive:    true,
+	}
+	if err := tx.Create(&subscription).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, webhookWithSecret(&subscription)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetCompanyWebhooks lists the webhook subscriptions of a company
+//
+//	@Summary		List webhooks
+//	@Description	List the webhook subscriptions of a company
+//	@Tags			Webhook
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			company_id		path		string	true	"Company ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.WebhookList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/company/{company_id}/webhooks [get]
+func GetCompanyWebhooks(c *fiber.Ctx) error {
+	companyID, err := uuid.Parse(c.Params("company_id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid company_id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	var subscriptions []model.WebhookSubscription
+	if err := tx.Where("company_id = ?", companyID).Order("created_at").Find(&subscriptions).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	list := DTO.WebhookList{Webhooks: make([]DTO.Webhook, 0, len(subscriptions))}
+	for i := range subscriptions {
+		list.Webhooks = append(list.Webhooks, webhookDTO(&subscriptions[i]))
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, &list); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// GetWebhookById retrieves a webhook subscription by ID
+//
+//	@Summary		Get webhook
+//	@Description	Retrieve a webhook subscription by its ID
+//	@Tags			Webhook
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Webhook ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.Webhook
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/webhook/{id} [get]
+func GetWebhookById(c *fiber.Ctx) error {
+	var subscription model.WebhookSubscription
+	if err := GetOneBy("id", c, &subscription, nil, nil); err != nil {
+		return err
+	}
+	if err := lib.ResponseFactory(c).SendDTO(200, &subscription, &DTO.Webhook{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// UpdateWebhookById updates a webhook subscription by ID
+//
+//	@Summary		Update webhook
+//	@Description	Update the URL, events or active state of a webhook subscription
+//	@Tags			Webhook
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"Webhook ID"
+//	@Param			webhook	body		DTO.UpdateWebhook	true	"Webhook"
+//	@Success		200		{object}	DTO.Webhook
+//	@Failure		400		{object}	DTO.ErrorResponse
+//	@Router			/webhook/{id} [patch]
+func UpdateWebhookById(c *fiber.Ctx) error {
+	var body DTO.UpdateWebhook
+	if err := c.BodyParser(&body); err != nil {
+		return lib.Error.General.BadRequest.WithError(err)
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	var subscription model.WebhookSubscription
+	if err := tx.Where("id = ?", c.Params("id")).First(&subscription).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return lib.Error.Webhook.NotFound
+		}
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	// Booleans are applied through a map so that is_active=false is not skipped as a zero value.
+	changes := map[string]any{}
+	if body.URL != nil {
+		subscription.URL = *body.URL
+		changes["url"] = subscription.URL
+	}
+	if body.Description != nil {
+		subscription.Description = *body.Description
+		changes["description"] = subscription.Description
+	}
+	if body.Events != nil {
+		subscription.Events = model.WebhookEventList(*body.Events)
+		changes["events"] = subscription.Events
+	}
+	if body.IsActive != nil {
+		subscription.IsActive = *body.IsActive
+		changes["is_active"] = subscription.IsActive
+	}
+	if len(changes) == 0 {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("no changes provided"))
+	}
+	if err := subscription.Validate(); err != nil {
+		return err
+	}
+	if err := tx.Model(&subscription).Updates(changes).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).SendDTO(200, &subscription, &DTO.Webhook{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// DeleteWebhookById deletes a webhook subscription by ID
+//
+//	@Summary		Delete webhook
+//	@Description	Delete a webhook subscription by its ID
+//	@Tags			Webhook
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Webhook ID"
+//	@Produce		json
+//	@Success		200
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/webhook/{id} [delete]
+func DeleteWebhookById(c *fiber.Ctx) error {
+	return DeleteOneById(c, &model.WebhookSubscription{})
+}
+
+// GetWebhookDeliveries lists the delivery log of a webhook subscription
+//
+//	@Summary		List webhook deliveries
+//	@Description	Paginated delivery log of a webhook subscription, newest first
+//	@Tags			Webhook
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Webhook ID"
+//	@Param			status			query		string	false	"Filter by status (PENDING, SUCCEEDED, FAILED)"
+//	@Param			event			query		string	false	"Filter by event"
+//	@Param			page			query		int		false	"Page number"				default(1)
+//	@Param			page_size		query		int		false	"Number of items per page"	default(10)
+//	@Produce		json
+//	@Success		200	{object}	DTO.WebhookDeliveryList
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/webhook/{id}/deliveries [get]
+func GetWebhookDeliveries(c *fiber.Ctx) error {
+	subscriptionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("invalid webhook id"))
+	}
+
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	page := c.QueryInt("page", 1)
+	pageSize := c.QueryInt("page_size", 10)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	query := tx.Model(&model.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if event := c.Query("event"); event != "" {
+		query = query.Where("event = ?", event)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	var deliveries []model.WebhookDelivery
+	if err := query.Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&deliveries).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	list := DTO.WebhookDeliveryList{
+		Deliveries: make([]DTO.WebhookDelivery, 0, len(deliveries)),
+		TotalCount: int(total),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	for _, d := range deliveries {
+		list.Deliveries = append(list.Deliveries, DTO.WebhookDelivery{
+			ID:             d.ID,
+			SubscriptionID: d.SubscriptionID,
+			Event:          d.Event,
+			Payload:        json.RawMessage(d.Payload),
+			Status:         string(d.Status),
+			Attempts:       d.Attempts,
+			ResponseStatus: d.ResponseStatus,
+			LastError:      d.LastError,
+			NextAttemptAt:  d.NextAttemptAt,
+			DeliveredAt:    d.DeliveredAt,
+			ReplayOfID:     d.ReplayOfID,
+			CreatedAt:      d.CreatedAt,
+		})
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, &list); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// ReplayWebhookDelivery sends a previous delivery again
+//
+//	@Summary		Replay webhook delivery
+//	@Description	Create a new delivery with the same payload as a previous one and queue it for sending
+//	@Tags			Webhook
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Webhook ID"
+//	@Param			delivery_id		path		string	true	"Delivery ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.WebhookDelivery
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/webhook/{id}/delivery/{delivery_id}/replay [post]
+func ReplayWebhookDelivery(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+	schemaName, err := lib.GetCompanySchemaName(c)
+	if err != nil {
+		return err
+	}
+
+	var original model.WebhookDelivery
+	if err := tx.Where("id = ? AND subscription_id = ?", c.Params("delivery_id"), c.Params("id")).First(&original).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return lib.Error.Webhook.DeliveryNotFound
+		}
+		return lib.Error.General.InternalError.WithError(err)
+	}
+
+	var replay *model.WebhookDelivery
+	if err := tx.Transaction(func(tx *gorm.DB) error {
+		if err := lib.ChangeToCompanySchema(tx, schemaName); err != nil {
+			return err
+		}
+		var err error
+		if replay, err = webhook.Replay(tx, &original); err != nil {
+			return err
+		}
+		return webhook.EnqueueDelivery(tx, replay)
+	}); err != nil {
+		return lib.Error.General.CreatedError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).SendDTO(200, replay, &DTO.WebhookDelivery{}); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// RotateWebhookSecret replaces the signing secret of a webhook subscription
+//
+//	@Summary		Rotate webhook secret
+//	@Description	Replace the signing secret of a webhook subscription. The new secret is only returned by this call.
+//	@Tags			Webhook
+//	@Security		ApiKeyAuth
+//	@Param			X-Auth-Token	header		string	true	"X-Auth-Token"
+//	@Param			X-Company-ID	header		string	true	"X-Company-ID"
+//	@Failure		401				{object}	nil
+//	@Param			id				path		string	true	"Webhook ID"
+//	@Produce		json
+//	@Success		200	{object}	DTO.WebhookWithSecret
+//	@Failure		400	{object}	DTO.ErrorResponse
+//	@Router			/webhook/{id}/secret [post]
+func RotateWebhookSecret(c *fiber.Ctx) error {
+	tx, err := lib.Session(c)
+	if err != nil {
+		return err
+	}
+
+	var subscription model.WebhookSubscription
+	if err := tx.Where("id = ?", c.Params("id")).First(&subscription).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return lib.Error.Webhook.NotFound
+		}
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	if err := subscription.NewSecret(); err != nil {
+		return err
+	}
+	if err := tx.Model(&subscription).Update("secret", subscription.Secret).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
+	}
+
+	if err := lib.ResponseFactory(c).Send(200, webhookWithSecret(&subscription)); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// webhookDTO renders a subscription without its secret.
+func webhookDTO(s *model.WebhookSubscription) DTO.Webhook {
+	return DTO.Webhook{
+		ID:          s.ID,
+		CompanyID:   s.CompanyID,
+		URL:         s.URL,
+		Description: s.Description,
+		Events:      s.Events,
+		IsActive:    s.IsActive,
+	}
+}
+
+// webhookWithSecret renders a subscription with its secret, only right after it is generated.
+func webhookWithSecret(s *model.WebhookSubscription) *DTO.WebhookWithSecret {
+	return &DTO.WebhookWithSecret{Webhook: webhookDTO(s), Secret: s.Secret}
+}
+
+// enqueueWebhookEvent records the event in the outbox within the caller's transaction.
+// The source is rendered through the given DTO so subscribers get the public representation.
+func enqueueWebhookEvent(tx *gorm.DB, companyID uuid.UUID, event string, source any, dto any) error {
+	data, err := webhookData(source, dto)
+	if err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("failed to render webhook %s payload: %w", event, err))
+	}
+	if err := webhook.EnqueueEvent(tx, companyID, event, data); err != nil {
+		return lib.Error.General.InternalError.WithError(err)
+	}
+	return nil
+}
+
+// webhookData renders the source through the DTO the same way SendDTO does.
+func webhookData(source any, dto any) (any, error) {
+	b, err := json.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, dto); err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+// Webhook registers the webhook controllers
+func Webhook(Gorm *handler.Gorm) {
+	endpoint := &middleware.Endpoint{DB: Gorm}
+	endpoint.BulkRegisterHandler([]fiber.Handler{
+		CreateWebhook,
+		GetCompanyWebhooks,
+		GetWebhookById,
+		UpdateWebhookById,
+		DeleteWebhookById,
+		GetWebhookDeliveries,
+		ReplayWebhookDelivery,
+		RotateWebhookSecret,
+	})
+}
@@ -42,6 +42,15 @@ func (c *cloudUploader) Save(fileType string, file []byte, originalFilename stri
 	return strategy(file, scopedPath)
 }
 
+func (c *cloudUploader) SaveAs(fileType string, file []byte, filename string) (string, error) {
+	scopedPath := path.Join(c.Entity, c.EntityID, filepath.Base(filename))
+	strategy, err := getStrategy(c, fileType)
+	if err != nil {
+		return "", lib.Error.General.InternalError.WithError(err)
+	}
+	return strategy(file, scopedPath)
+}
+
 func (c *cloudUploader) Delete(fileURL string) error {
 	filename := ExtractFilenameFromURL(fileURL)
 	if filename == "" {
@@ -78,3 +78,24 @@ func TestCloudUploader_Replace(t *testing.T) {
 		t.Errorf("expected new URL to contain base filename '%s', got: %s", newName[:3], url)
 	}
 }
+
+func TestCloudUploader_SaveAs(t *testing.T) {
+	id := uuid.New().String()
+	mockClient := &mockS3Client{}
+	uploader := NewCloudUploader("appointment", id, mockClient, "my-bucket", "https://cdn.test.com")
+
+	url, err := uploader.SaveAs("image", FileBytes.PNG_FILE_1, "receipt.png")
+	if err != nil {
+		t.Fatalf("SaveAs failed: %v", err)
+	}
+	if want := "appointment/" + id + "/receipt.png"; mockClient.LastKey != want {
+		t.Errorf("expected key %s, got %s", want, mockClient.LastKey)
+	}
+	again, err := uploader.SaveAs("image", FileBytes.PNG_FILE_2, "receipt.png")
+	if err != nil {
+		t.Fatalf("SaveAs failed: %v", err)
+	}
+	if again != url {
+		t.Errorf("expected the same URL when saving again, got %s and %s", url, again)
+	}
+}
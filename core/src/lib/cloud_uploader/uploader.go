@@ -7,6 +7,8 @@ import (
 
 type Uploader interface {
 	Save(fileType string, file []byte, filename string) (string, error)
+	// SaveAs stores the file under the given name as is, overwriting the file with that name if any.
+	SaveAs(fileType string, file []byte, filename string) (string, error)
 	Delete(filename string) error
 	Replace(fileType string, oldURL string, newFile []byte, newFilename string) (string, error)
 }
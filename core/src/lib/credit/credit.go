@@ -0,0 +1,75 @@
+package credit
+
+import (
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Consume pays the freshly created appointment with a credit of the client,
+// within the booking transaction. The package expiring first is used, so that
+// credits are not lost. The packages are locked against concurrent bookings.
+func Consume(tx *gorm.DB, appointment *model.Appointment) error {
+	var packages []model.ClientPackage
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("company_id = ? AND client_id = ? AND expires_at > ?", appointment.CompanyID, appointment.ClientID, appointment.StartTime).
+		Order("expires_at").
+		Find(&packages).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading client packages: %w", err))
+	}
+	pkg := Pick(packages, appointment)
+	if pkg == nil {
+		return lib.Error.Package.NoCredit
+	}
+
+	if err := tx.Model(pkg).UpdateColumn("credits_used", gorm.Expr("credits_used + 1")).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error consuming credit: %w", err))
+	}
+	appointment.ClientPackageID = &pkg.ID
+	// The appointment hooks forbid changing the credit, it is only set here
+	if err := tx.Model(appointment).UpdateColumn("client_package_id", appointment.ClientPackageID).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error recording credit on appointment: %w", err))
+	}
+	return nil
+}
+
+// Pick returns the package whose credit pays the appointment, nil if none covers it.
+// packages must be sorted by expiry.
+func Pick(packages []model.ClientPackage, appointment *model.Appointment) *model.ClientPackage {
+	for i := range packages {
+		if packages[i].Covers(appointment.ServiceID, appointment.StartTime) {
+			return &packages[i]
+		}
+	}
+	return nil
+}
+
+// RestoreOnCancel gives the credit back when the cancelled appointment was paid
+// with one and the cancellation is within the free cancellation window of the service.
+// Late cancellations lose the credit.
+func RestoreOnCancel(tx *gorm.DB, appointment *model.Appointment) error {
+	if appointment.ClientPackageID == nil {
+		return nil
+	}
+	var service model.Service
+	if err := tx.Where("id = ?", appointment.ServiceID).First(&service).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("loading service: %w", err))
+	}
+	cancelledAt := appointment.CancelTime
+	if cancelledAt.IsZero() {
+		cancelledAt = time.Now()
+	}
+	if !service.FreeCancellation(appointment.StartTime, cancelledAt) {
+		return nil
+	}
+	if err := tx.Model(&model.ClientPackage{}).
+		Where("id = ? AND credits_used > 0", *appointment.ClientPackageID).
+		UpdateColumn("credits_used", gorm.Expr("credits_used - 1")).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error restoring credit: %w", err))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package credit
+
+import (
+	"mynute-go/core/src/config/db/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSell(t *testing.T) {
+	purchased := time.Date(2030, 1, 31, 10, 0, 0, 0, time.UTC)
+	pkg := &model.ServicePackage{CompanyID: uuid.New(), Name: "10 massages", Type: model.PackageTypePackage, Price: 90000, Credits: 10, ValidityDays: 30}
+	assert.NoError(t, pkg.Validate())
+
+	sold := pkg.Sell(uuid.New(), purchased)
+	assert.Equal(t, int64(10), sold.Remaining())
+	assert.Equal(t, time.Date(2030, 3, 2, 10, 0, 0, 0, time.UTC), sold.ExpiresAt)
+
+	pkg.Credits = 0
+	assert.Error(t, pkg.Validate())
+	pkg.Type = model.PackageTypeMembership
+	assert.NoError(t, pkg.Validate())
+	pkg.ValidityDays = 0
+	assert.Error(t, pkg.Validate())
+}
+
+func TestPick(t *testing.T) {
+	massage, coloring := uuid.New(), uuid.New()
+	start := time.Date(2030, 1, 10, 10, 0, 0, 0, time.UTC)
+	a := &model.Appointment{}
+	a.ServiceID, a.StartTime = massage, start
+
+	usedUp := model.ClientPackage{Type: model.PackageTypePackage, Credits: 2, CreditsUsed: 2, ExpiresAt: start.Add(24 * time.Hour)}
+	otherService := model.ClientPackage{Type: model.PackageTypePackage, Credits: 5, ServiceIDs: model.UUIDList{coloring}, ExpiresAt: start.Add(48 * time.Hour)}
+	expiring := model.ClientPackage{Type: model.PackageTypePackage, Credits: 5, CreditsUsed: 1, ServiceIDs: model.UUIDList{massage}, ExpiresAt: start.Add(72 * time.Hour)}
+	membership := model.ClientPackage{Type: model.PackageTypeMembership, CreditsUsed: 40, ExpiresAt: start.Add(720 * time.Hour)}
+
+	assert.Equal(t, int64(0), usedUp.Remaining())
+	assert.Equal(t, int64(-1), membership.Remaining())
+
+	packages := []model.ClientPackage{usedUp, otherService, expiring, membership}
+	assert.Equal(t, &packages[2], Pick(packages, a))
+	assert.Equal(t, &packages[3], Pick(packages[3:], a))
+	assert.Nil(t, Pick(packages[:2], a))
+
+	// The credit must still be valid when the appointment starts
+	a.StartTime = expiring.ExpiresAt
+	assert.Equal(t, &packages[3], Pick(packages, a))
+}
+
+func TestFreeCancellation(t *testing.T) {
+	s := &model.Service{}
+	s.FreeCancellationHours = 24
+	start := time.Date(2030, 1, 10, 10, 0, 0, 0, time.UTC)
+	assert.True(t, s.FreeCancellation(start, start.Add(-24*time.Hour)))
+	assert.False(t, s.FreeCancellation(start, start.Add(-23*time.Hour)))
+}
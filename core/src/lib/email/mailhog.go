@@ -2,13 +2,16 @@ package email
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"mime"
 	"net/http"
 	"net/smtp"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -113,6 +116,28 @@ func (m *MailHogAdapter) buildMessage(from string, data EmailData) string {
 	// MIME headers for HTML email
 	builder.WriteString("MIME-Version: 1.0\r\n")
 
+	if len(data.Attachments) > 0 {
+		// The body and the attachments go in separate parts
+		boundary := fmt.Sprintf("mynute-%d", time.Now().UnixNano())
+		builder.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%q\r\n", boundary))
+		builder.WriteString("\r\n")
+		builder.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+		writeBody(&builder, data)
+		builder.WriteString("\r\n")
+		for _, attachment := range data.Attachments {
+			builder.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+			writeAttachment(&builder, attachment)
+		}
+		builder.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
+	} else {
+		writeBody(&builder, data)
+	}
+
+	return builder.String()
+}
+
+// writeBody writes the Content-Type header and the HTML or plain text body.
+func writeBody(builder *strings.Builder, data EmailData) {
 	if data.Html != "" {
 		builder.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
 		builder.WriteString("\r\n")
@@ -125,8 +150,35 @@ func (m *MailHogAdapter) buildMessage(from string, data EmailData) string {
 		builder.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
 		builder.WriteString("\r\n")
 	}
+}
 
-	return builder.String()
+// writeAttachment writes a MIME part with the base64 encoded attachment.
+func writeAttachment(builder *strings.Builder, attachment *Attachment) {
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(attachment.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	disposition := "attachment"
+	if attachment.ContentId != "" {
+		disposition = "inline"
+	}
+	builder.WriteString(fmt.Sprintf("Content-Type: %s; name=%q\r\n", contentType, attachment.Filename))
+	builder.WriteString("Content-Transfer-Encoding: base64\r\n")
+	builder.WriteString(fmt.Sprintf("Content-Disposition: %s; filename=%q\r\n", disposition, attachment.Filename))
+	if attachment.ContentId != "" {
+		builder.WriteString(fmt.Sprintf("Content-ID: <%s>\r\n", attachment.ContentId))
+	}
+	builder.WriteString("\r\n")
+	// Lines of base64 must not exceed 76 characters
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	for len(encoded) > 76 {
+		builder.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	builder.WriteString(encoded + "\r\n")
 }
 
 // --- MailHog API Client for E2E Testing ---
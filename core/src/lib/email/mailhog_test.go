@@ -279,6 +279,28 @@ func TestMailHogAdapter_BuildMessage(t *testing.T) {
 		// Check that there's a blank line (CRLF CRLF) between headers and body
 		assert.Contains(t, message, "\r\n\r\n")
 	})
+
+	t.Run("should attach files in a multipart message", func(t *testing.T) {
+		data := EmailData{
+			From:    "sender@example.com",
+			To:      []string{"recipient@example.com"},
+			Subject: "Your receipt",
+			Html:    "<h1>Receipt</h1>",
+			Attachments: []*Attachment{{
+				Content:  []byte("%PDF-1.3 receipt"),
+				Filename: "receipt.pdf",
+			}},
+		}
+
+		message := adapter.buildMessage(data.From, data)
+
+		assert.Contains(t, message, "Content-Type: multipart/mixed; boundary=")
+		assert.Contains(t, message, "Content-Type: text/html; charset=UTF-8")
+		assert.Contains(t, message, "<h1>Receipt</h1>")
+		assert.Contains(t, message, `Content-Type: application/pdf; name="receipt.pdf"`)
+		assert.Contains(t, message, `Content-Disposition: attachment; filename="receipt.pdf"`)
+		assert.Contains(t, message, "JVBERi0xLjMgcmVjZWlwdA==")
+	})
 }
 
 // --- MailHog API Tests ---
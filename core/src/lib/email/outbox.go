@@ -0,0 +1,126 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib/outbox"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Recipients of an appointment email.
+const (
+	RecipientClient   = "client"
+	RecipientEmployee = "employee"
+)
+
+// AppointmentEmailJob is the outbox payload for one appointment email.
+// Each recipient gets its own job so a retry never resends to the other one.
+type AppointmentEmailJob struct {
+	AppointmentID uuid.UUID `json:"appointment_id"`
+	Template      string    `json:"template"`
+	Recipient     string    `json:"recipient"`
+	Language      string    `json:"language"`
+}
+
+// EnqueueAppointmentEmails records the client and employee emails for an appointment
+// in the outbox. template is one of appointment_created, appointment_updated or appointment_cancelled.
+func EnqueueAppointmentEmails(tx *gorm.DB, appointment *model.Appointment, template, language string) error {
+	for _, recipient := range []string{RecipientClient, RecipientEmployee} {
+		job := AppointmentEmailJob{
+			AppointmentID: appointment.ID,
+			Template:      template,
+			Recipient:     recipient,
+			Language:      language,
+		}
+		if err := outbox.Enqueue(tx, &appointment.CompanyID, model.OutboxTopicAppointmentEmail, job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleAppointmentEmail is the outbox handler for model.OutboxTopicAppointmentEmail.
+func HandleAppointmentEmail(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+	var job AppointmentEmailJob
+	if err := outbox.Decode(msg, &job); err != nil {
+		return err
+	}
+
+	var appointment model.Appointment
+	if err := tx.Where("id = ?", job.AppointmentID).First(&appointment).Error; err != nil {
+		return fmt.Errorf("failed to load appointment %s: %w", job.AppointmentID, err)
+	}
+
+	sender, err := NewProvider(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create email provider: %w", err)
+	}
+	service := NewAppointmentEmailService(sender, filepath.Join("static", "email"), filepath.Join("translation", "email"))
+
+	data, err := service.LoadAppointmentData(tx, &appointment, job.Language)
+	if err != nil {
+		return fmt.Errorf("failed to load appointment data: %w", err)
+	}
+
+	switch job.Recipient {
+	case RecipientClient:
+		return service.sendEmail(ctx, job.Template, data.ClientEmail, data.ClientName, data)
+	case RecipientEmployee:
+		return service.sendEmail(ctx, job.Template, data.EmployeeEmail, data.EmployeeName, data)
+	default:
+		return fmt.Errorf("unknown appointment email recipient %q", job.Recipient)
+	}
+}
+
+// AccountEmailJob is the outbox payload of an email with a login code, a verification
+// code or a new password. It is rendered from the template when it is sent.
+type AccountEmailJob struct {
+	To       string       `json:"to"`
+	Template string       `json:"template"`
+	Language string       `json:"language"`
+	Data     TemplateData `json:"data"`
+	// ExpiresAt is when what the email carries can no longer be used, if ever.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// EnqueueAccountEmail records the email in the outbox, within the caller's transaction.
+func EnqueueAccountEmail(tx *gorm.DB, job AccountEmailJob) error {
+	return outbox.Enqueue(tx, nil, model.OutboxTopicAccountEmail, job)
+}
+
+// HandleAccountEmail is the outbox handler for model.OutboxTopicAccountEmail.
+// Codes that expired before they could be delivered are not sent.
+func HandleAccountEmail(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+	var job AccountEmailJob
+	if err := outbox.Decode(msg, &job); err != nil {
+		return err
+	}
+	if job.ExpiresAt != nil && time.Now().After(*job.ExpiresAt) {
+		return nil
+	}
+	language := job.Language
+	if language == "" {
+		language = "en"
+	}
+
+	renderer := NewTemplateRenderer(filepath.Join("static", "email"), filepath.Join("translation", "email"))
+	rendered, err := renderer.RenderEmail(job.Template, language, job.Data)
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+
+	sender, err := NewProvider(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create email provider: %w", err)
+	}
+	return sender.Send(ctx, EmailData{
+		To:      []string{job.To},
+		Subject: rendered.Subject,
+		Html:    rendered.HTMLBody,
+	})
+}
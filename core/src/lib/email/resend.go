@@ -71,6 +71,15 @@ func (r *ResendAdapter) Send(ctx context.Context, data EmailData) error {
 		Subject: data.Subject,
 		Html:    data.Html,
 	}
+	for _, a := range data.Attachments {
+		params.Attachments = append(params.Attachments, &resend.Attachment{
+			Content:     a.Content,
+			Filename:    a.Filename,
+			Path:        a.Path,
+			ContentType: a.ContentType,
+			ContentId:   a.ContentId,
+		})
+	}
 
 	_, err := r.client.Emails.SendWithContext(ctx, params)
 	if err != nil {
@@ -107,6 +107,18 @@ type ErrorCategory struct {
 	General            GeneralErrors
 	Role               RoleErrors
 	Validation         ValidationErrors
+	Webhook            WebhookErrors
+	Payment            PaymentErrors
+	Service            ServiceErrors
+	PromoCode          PromoCodeErrors
+	Package            PackageErrors
+	BookableResource   BookableResourceErrors
+	ScheduleTemplate   ScheduleTemplateErrors
+	IntakeForm         IntakeFormErrors
+	Review             ReviewErrors
+	Loyalty            LoyaltyErrors
+	Import             ImportErrors
+	Invitation         InvitationErrors
 }
 
 type AppointmentErrors struct {
@@ -122,6 +134,12 @@ type AppointmentErrors struct {
 	HistoryLoggingFailed         ErrorStruct // New: Failure during history log save
 	HistoryManualUpdateForbidden ErrorStruct // New: Manual update of history log not allowed
 	CancelledAppointmentUpdate   ErrorStruct // New: Attempt to modify a cancelled appointment
+	ReceiptUnavailable           ErrorStruct // Receipt asked for an appointment neither fulfilled nor paid
+	NoEmployeeAvailable          ErrorStruct // Any employee asked but none can take the appointment
+	BookingTooSoon               ErrorStruct // Start within the minimum advance notice
+	BookingTooFarAhead           ErrorStruct // Start after the last day that can be booked
+	BookingClosedToday           ErrorStruct // Same day start after the same day cutoff
+	StartTimeNotOffered          ErrorStruct // Start off the slots laid out by the service
 }
 
 type AppointmentArchiveErrors struct {
@@ -147,18 +165,24 @@ type BranchErrors struct {
 	MaxConcurrentAppointments ErrorStruct
 	MaxCapacityReached        ErrorStruct
 	MaxServiceCapacityReached ErrorStruct
+	Closed                    ErrorStruct
 }
 
 type ClientErrors struct {
-	NotVerified       ErrorStruct
-	EmailExists       ErrorStruct
-	InvalidClientName ErrorStruct
-	InvalidEmail      ErrorStruct
-	NotFound          ErrorStruct
-	CompanyLimit      ErrorStruct
-	CompanyNotFound   ErrorStruct
-	ScheduleConflict  ErrorStruct
-	CompanyIdNotFound ErrorStruct
+	NotVerified         ErrorStruct
+	EmailExists         ErrorStruct
+	InvalidClientName   ErrorStruct
+	InvalidEmail        ErrorStruct
+	NotFound            ErrorStruct
+	CompanyLimit        ErrorStruct
+	CompanyNotFound     ErrorStruct
+	ScheduleConflict    ErrorStruct
+	CompanyIdNotFound   ErrorStruct
+	NotGuest            ErrorStruct
+	DataRequestNotFound ErrorStruct
+	DependentNotFound   ErrorStruct
+	DataExportNotReady  ErrorStruct
+	DataExportExpired   ErrorStruct
 }
 
 type CompanyErrors struct {
@@ -183,6 +207,10 @@ type EmployeeErrors struct {
 	ScheduleConflict         ErrorStruct
 	LacksService             ErrorStruct // New (More specific than ServiceDoesNotBelong)
 	NotAvailableWorkSchedule ErrorStruct // New (More specific than NotAvailableOnDate)
+	InvitationPending        ErrorStruct
+	Suspended                ErrorStruct
+	InvalidStatus            ErrorStruct
+	NotBookable              ErrorStruct
 }
 
 type GeneralErrors struct {
@@ -209,6 +237,105 @@ type ValidationErrors struct {
 	Failed ErrorStruct // New: General validation failure bucket
 }
 
+type WebhookErrors struct {
+	NotFound            ErrorStruct
+	DeliveryNotFound    ErrorStruct
+	InvalidURL          ErrorStruct
+	InvalidEvent        ErrorStruct
+	InvalidSubscription ErrorStruct
+}
+
+type ServiceErrors struct {
+	InvalidOverride   ErrorStruct
+	OverrideNotFound  ErrorStruct
+	InvalidAssignment ErrorStruct
+	NoSlotAvailable   ErrorStruct
+	InvalidSlotRules  ErrorStruct
+}
+
+type PromoCodeErrors struct {
+	NotFound           ErrorStruct
+	Invalid            ErrorStruct
+	AlreadyExists      ErrorStruct
+	Inactive           ErrorStruct
+	NotApplicable      ErrorStruct
+	UsageLimitReached  ErrorStruct
+	ClientLimitReached ErrorStruct
+	FirstVisitOnly     ErrorStruct
+}
+
+type BookableResourceErrors struct {
+	NotFound    ErrorStruct
+	Invalid     ErrorStruct
+	Unavailable ErrorStruct
+}
+
+type ScheduleTemplateErrors struct {
+	NotFound      ErrorStruct
+	Invalid       ErrorStruct
+	AlreadyExists ErrorStruct
+	ApplyFailed   ErrorStruct
+}
+
+type IntakeFormErrors struct {
+	NotFound          ErrorStruct
+	Invalid           ErrorStruct
+	InvalidAnswers    ErrorStruct
+	SignatureRequired ErrorStruct
+	ResponseNotFound  ErrorStruct
+	AlreadyAnswered   ErrorStruct
+}
+
+type ReviewErrors struct {
+	NotFound        ErrorStruct
+	Invalid         ErrorStruct
+	AlreadyReviewed ErrorStruct
+	NotModeratable  ErrorStruct
+}
+
+type LoyaltyErrors struct {
+	NotFound           ErrorStruct
+	Invalid            ErrorStruct
+	Inactive           ErrorStruct
+	InsufficientPoints ErrorStruct
+	BelowMinimum       ErrorStruct
+}
+
+type ImportErrors struct {
+	InvalidFile ErrorStruct
+	UnknownKind ErrorStruct
+	InvalidRow  ErrorStruct
+}
+
+type InvitationErrors struct {
+	NotFound   ErrorStruct
+	Expired    ErrorStruct
+	NotPending ErrorStruct
+	EmailInUse ErrorStruct
+	Invalid    ErrorStruct
+}
+
+type PackageErrors struct {
+	NotFound       ErrorStruct
+	Invalid        ErrorStruct
+	Inactive       ErrorStruct
+	NoCredit       ErrorStruct
+	CreditNotFound ErrorStruct
+}
+
+type PaymentErrors struct {
+	NotFound             ErrorStruct
+	Declined             ErrorStruct
+	MissingMethod        ErrorStruct
+	InvalidPrepayment    ErrorStruct
+	InvalidTransition    ErrorStruct
+	InvalidWebhook       ErrorStruct
+	ProviderNotSupported ErrorStruct
+	NothingToRefund      ErrorStruct
+	AmountMismatch       ErrorStruct
+	PixNotConfigured     ErrorStruct
+}
+
 // Global error instances
 var Error = ErrorCategory{
 	Auth: AuthErrors{
@@ -233,6 +360,12 @@ var Error = ErrorCategory{
 		HistoryLoggingFailed:         NewError("Failed to save appointment history log", "Falha ao salvar histórico do compromisso", fiber.StatusInternalServerError),
 		CancelledAppointmentUpdate:   NewError("Cannot modify a cancelled appointment", "Não é possível modificar um compromisso cancelado", fiber.StatusForbidden),
 		HistoryManualUpdateForbidden: NewError("Manual update of appointment log is not allowed", "Atualização manual do histórico não é permitida", fiber.StatusForbidden),
+		ReceiptUnavailable:           NewError("Receipts are only issued for fulfilled or paid appointments", "Recibos são emitidos apenas para compromissos realizados ou pagos", fiber.StatusBadRequest),
+		NoEmployeeAvailable:          NewError("No employee is available for the service at this time", "Nenhum funcionário está disponível para o serviço neste horário", fiber.StatusConflict),
+		BookingTooSoon:               NewError("The appointment is too soon to be booked", "O agendamento está próximo demais para ser feito", fiber.StatusBadRequest),
+		BookingTooFarAhead:           NewError("The appointment is too far ahead to be booked", "O agendamento está distante demais para ser feito", fiber.StatusBadRequest),
+		BookingClosedToday:           NewError("Same day appointments can no longer be booked today", "Agendamentos para o mesmo dia não podem mais ser feitos hoje", fiber.StatusBadRequest),
+		StartTimeNotOffered:          NewError("The service is not offered at this start time", "O serviço não é oferecido neste horário de início", fiber.StatusBadRequest),
 	},
 	AppointmentArchive: AppointmentArchiveErrors{
 		IdNotSet:        NewError("Appointment archive ID cannot be nil", "ID do arquivo de compromisso não pode ser nulo", fiber.StatusBadRequest),
@@ -244,16 +377,22 @@ var Error = ErrorCategory{
 		ServiceDoesNotBelong:      NewError("The selected service is not offered by this branch", "O serviço selecionado não é oferecido por esta filial", fiber.StatusBadRequest),
 		MaxCapacityReached:        NewError("Branch maximum concurrent appointment capacity reached", "Capacidade máxima de compromissos simultâneos da filial atingida", fiber.StatusConflict),                                 // 409 Conflict better?
 		MaxServiceCapacityReached: NewError("Branch maximum concurrent capacity for this specific service reached", "Capacidade máxima de compromissos simultâneos da filial para este serviço atingida", fiber.StatusConflict), // 409 Conflict better?
+		Closed:                    NewError("The branch is closed at the selected time", "A filial está fechada no horário selecionado", fiber.StatusBadRequest),
 	},
 	Client: ClientErrors{
-		NotFound:          NewError("Client not found", "Cliente não encontrado", fiber.StatusNotFound),
-		ScheduleConflict:  NewError("Client already has a conflicting appointment", "Cliente já possui um compromisso conflitante", fiber.StatusConflict), // 409 Conflict
-		NotVerified:       NewError("Client not verified", "Usuário não verificado", fiber.StatusUnauthorized),
-		EmailExists:       NewError("Email already exists", "Email já cadastrado", fiber.StatusBadRequest),
-		InvalidClientName: NewError("Invalid client name", "Nome de usuário inválido", fiber.StatusBadRequest),
-		InvalidEmail:      NewError("Invalid email", "Email inválido", fiber.StatusBadRequest),
-		CompanyLimit:      NewError("Client already has a company associated", "Usuário já possui uma empresa associada", fiber.StatusBadRequest),
-		CompanyIdNotFound: NewError("Client company ID not found. This is an internal error", "ID da empresa do usuário não encontrado. Este é um erro interno", fiber.StatusInternalServerError),
+		NotFound:            NewError("Client not found", "Cliente não encontrado", fiber.StatusNotFound),
+		ScheduleConflict:    NewError("Client already has a conflicting appointment", "Cliente já possui um compromisso conflitante", fiber.StatusConflict), // 409 Conflict
+		NotVerified:         NewError("Client not verified", "Usuário não verificado", fiber.StatusUnauthorized),
+		EmailExists:         NewError("Email already exists", "Email já cadastrado", fiber.StatusBadRequest),
+		NotGuest:            NewError("Client already has an account", "Cliente já possui uma conta", fiber.StatusConflict),
+		DependentNotFound:   NewError("Dependent not found", "Dependente não encontrado", fiber.StatusNotFound),
+		DataRequestNotFound: NewError("Data request not found", "Solicitação de dados não encontrada", fiber.StatusNotFound),
+		DataExportNotReady:  NewError("Data export is not ready yet", "Exportação de dados ainda não está pronta", fiber.StatusConflict),
+		DataExportExpired:   NewError("Data export has expired, request a new one", "Exportação de dados expirou, solicite uma nova", fiber.StatusGone),
+		InvalidClientName:   NewError("Invalid client name", "Nome de usuário inválido", fiber.StatusBadRequest),
+		InvalidEmail:        NewError("Invalid email", "Email inválido", fiber.StatusBadRequest),
+		CompanyLimit:        NewError("Client already has a company associated", "Usuário já possui uma empresa associada", fiber.StatusBadRequest),
+		CompanyIdNotFound:   NewError("Client company ID not found. This is an internal error", "ID da empresa do usuário não encontrado. Este é um erro interno", fiber.StatusInternalServerError),
 	},
 	Company: CompanyErrors{
 		NotFound:              NewError("Company not found", "Empresa não encontrada", fiber.StatusNotFound),
@@ -275,6 +414,10 @@ var Error = ErrorCategory{
 		ScheduleConflict:         NewError("Employee already has a conflicting appointment", "Funcionário já possui um compromisso conflitante", fiber.StatusConflict),                                                                        // 409 Conflict
 		LacksService:             NewError("Employee does not provide the specified service", "Funcionário não oferece o serviço especificado", fiber.StatusBadRequest),
 		NotAvailableWorkSchedule: NewError("Employee is not scheduled to work at the requested time/branch", "Funcionário não está escalado para trabalhar no horário/filial solicitados", fiber.StatusBadRequest),
+		InvitationPending:        NewError("The employee has not accepted the invitation yet", "O funcionário ainda não aceitou o convite", fiber.StatusForbidden),
+		Suspended:                NewError("The employee is suspended", "O funcionário está suspenso", fiber.StatusForbidden),
+		NotBookable:              NewError("The employee is not taking appointments", "O funcionário não está recebendo agendamentos", fiber.StatusBadRequest),
+		InvalidStatus:            NewError("Invalid employee status", "Status de funcionário inválido", fiber.StatusBadRequest),
 	},
 	General: GeneralErrors{
 		InternalError:         NewError("Internal server error while processing the request", "Erro interno do servidor ao processar a requisição", fiber.StatusInternalServerError),
@@ -297,4 +440,91 @@ var Error = ErrorCategory{
 	Validation: ValidationErrors{
 		Failed: NewError("Input validation failed", "Falha na validação dos dados de entrada", fiber.StatusBadRequest),
 	},
+	Webhook: WebhookErrors{
+		NotFound:            NewError("Webhook subscription not found", "Assinatura de webhook não encontrada", fiber.StatusNotFound),
+		DeliveryNotFound:    NewError("Webhook delivery not found", "Entrega de webhook não encontrada", fiber.StatusNotFound),
+		InvalidURL:          NewError("Webhook URL must be a valid https URL to a public host", "A URL do webhook deve ser uma URL https válida para um host público", fiber.StatusBadRequest),
+		InvalidEvent:        NewError("Webhook event is not supported", "Evento de webhook não suportado", fiber.StatusBadRequest),
+		InvalidSubscription: NewError("Webhook subscription is invalid", "Assinatura de webhook inválida", fiber.StatusBadRequest),
+	},
+	Payment: PaymentErrors{
+		NotFound:             NewError("Payment not found", "Pagamento não encontrado", fiber.StatusNotFound),
+		Declined:             NewError("Payment was declined", "Pagamento recusado", fiber.StatusPaymentRequired),
+		MissingMethod:        NewError("This service requires a payment when booking", "Este serviço exige pagamento no agendamento", fiber.StatusPaymentRequired),
+		InvalidPrepayment:    NewError("Invalid prepayment configuration", "Configuração de pré-pagamento inválida", fiber.StatusBadRequest),
+		InvalidTransition:    NewError("Invalid payment status change", "Mudança de status de pagamento inválida", fiber.StatusConflict),
+		InvalidWebhook:       NewError("Invalid payment notification", "Notificação de pagamento inválida", fiber.StatusBadRequest),
+		ProviderNotSupported: NewError("Payment provider not supported", "Provedor de pagamento não suportado", fiber.StatusBadRequest),
+		NothingToRefund:      NewError("There is nothing left to refund on this payment", "Não há valor a ser reembolsado neste pagamento", fiber.StatusConflict),
+		AmountMismatch:       NewError("Paid amount does not match the payment", "Valor pago não confere com o pagamento", fiber.StatusConflict),
+		PixNotConfigured:     NewError("The company has no Pix key configured", "A empresa não possui chave Pix configurada", fiber.StatusBadRequest),
+	},
+	Service: ServiceErrors{
+		InvalidOverride:   NewError("Invalid service price or duration override", "Ajuste de preço ou duração do serviço inválido", fiber.StatusBadRequest),
+		OverrideNotFound:  NewError("Service override not found", "Ajuste do serviço não encontrado", fiber.StatusNotFound),
+		InvalidAssignment: NewError("Invalid employee assignment strategy", "Estratégia de atribuição de funcionário inválida", fiber.StatusBadRequest),
+		NoSlotAvailable:   NewError("No available slot was found for the service", "Nenhum horário disponível foi encontrado para o serviço", fiber.StatusNotFound),
+		InvalidSlotRules:  NewError("Invalid service slot interval or alignment", "Intervalo ou alinhamento de horários do serviço inválido", fiber.StatusBadRequest),
+	},
+	PromoCode: PromoCodeErrors{
+		NotFound:           NewError("Promo code not found", "Código promocional não encontrado", fiber.StatusNotFound),
+		Invalid:            NewError("Invalid promo code", "Código promocional inválido", fiber.StatusBadRequest),
+		AlreadyExists:      NewError("Promo code already exists", "Código promocional já existe", fiber.StatusConflict),
+		Inactive:           NewError("Promo code is not active", "Código promocional não está ativo", fiber.StatusBadRequest),
+		NotApplicable:      NewError("Promo code does not apply to this appointment", "Código promocional não se aplica a este agendamento", fiber.StatusBadRequest),
+		UsageLimitReached:  NewError("Promo code usage limit reached", "Limite de uso do código promocional atingido", fiber.StatusConflict),
+		ClientLimitReached: NewError("Promo code already used the maximum number of times by this client", "Código promocional já usado o número máximo de vezes por este cliente", fiber.StatusConflict),
+		FirstVisitOnly:     NewError("Promo code is only valid on the first visit", "Código promocional válido apenas na primeira visita", fiber.StatusBadRequest),
+	},
+	Package: PackageErrors{
+		NotFound:       NewError("Package not found", "Pacote não encontrado", fiber.StatusNotFound),
+		Invalid:        NewError("Invalid package", "Pacote inválido", fiber.StatusBadRequest),
+		Inactive:       NewError("Package is no longer sold", "Pacote não está mais à venda", fiber.StatusBadRequest),
+		NoCredit:       NewError("The client has no credit covering this service", "O cliente não possui crédito que cubra este serviço", fiber.StatusPaymentRequired),
+		CreditNotFound: NewError("Client package not found", "Pacote do cliente não encontrado", fiber.StatusNotFound),
+	},
+	BookableResource: BookableResourceErrors{
+		NotFound:    NewError("Resource not found", "Recurso não encontrado", fiber.StatusNotFound),
+		Invalid:     NewError("Invalid resource", "Recurso inválido", fiber.StatusBadRequest),
+		Unavailable: NewError("A resource the service needs is fully booked at this time", "Um recurso necessário ao serviço está totalmente reservado neste horário", fiber.StatusConflict),
+	},
+	ScheduleTemplate: ScheduleTemplateErrors{
+		NotFound:      NewError("Schedule template not found", "Modelo de escala não encontrado", fiber.StatusNotFound),
+		Invalid:       NewError("Invalid schedule template", "Modelo de escala inválido", fiber.StatusBadRequest),
+		AlreadyExists: NewError("A schedule template with this name already exists", "Já existe um modelo de escala com este nome", fiber.StatusConflict),
+		ApplyFailed:   NewError("The schedule could not be applied to every target", "A escala não pôde ser aplicada a todos os destinos", fiber.StatusBadRequest),
+	},
+	IntakeForm: IntakeFormErrors{
+		NotFound:          NewError("Intake form not found", "Formulário de anamnese não encontrado", fiber.StatusNotFound),
+		Invalid:           NewError("Invalid intake form", "Formulário de anamnese inválido", fiber.StatusBadRequest),
+		InvalidAnswers:    NewError("Invalid intake form answers", "Respostas do formulário de anamnese inválidas", fiber.StatusBadRequest),
+		SignatureRequired: NewError("The consent must be signed with the full name", "O consentimento deve ser assinado com o nome completo", fiber.StatusBadRequest),
+		ResponseNotFound:  NewError("Intake form request not found", "Solicitação de formulário de anamnese não encontrada", fiber.StatusNotFound),
+		AlreadyAnswered:   NewError("Intake form already answered", "Formulário de anamnese já respondido", fiber.StatusConflict),
+	},
+	Review: ReviewErrors{
+		NotFound:        NewError("Review not found", "Avaliação não encontrada", fiber.StatusNotFound),
+		Invalid:         NewError("Invalid review", "Avaliação inválida", fiber.StatusBadRequest),
+		AlreadyReviewed: NewError("Appointment already reviewed", "Agendamento já avaliado", fiber.StatusConflict),
+		NotModeratable:  NewError("Only answered reviews can be moderated", "Apenas avaliações respondidas podem ser moderadas", fiber.StatusConflict),
+	},
+	Loyalty: LoyaltyErrors{
+		NotFound:           NewError("Loyalty program not found", "Programa de fidelidade não encontrado", fiber.StatusNotFound),
+		Invalid:            NewError("Invalid loyalty program", "Programa de fidelidade inválido", fiber.StatusBadRequest),
+		Inactive:           NewError("The loyalty program is not active", "O programa de fidelidade não está ativo", fiber.StatusBadRequest),
+		InsufficientPoints: NewError("Not enough loyalty points", "Pontos de fidelidade insuficientes", fiber.StatusBadRequest),
+		BelowMinimum:       NewError("Too few loyalty points to redeem", "Poucos pontos de fidelidade para resgatar", fiber.StatusBadRequest),
+	},
+	Invitation: InvitationErrors{
+		NotFound:   NewError("Invitation not found", "Convite não encontrado", fiber.StatusNotFound),
+		Expired:    NewError("The invitation has expired, ask for a new one", "O convite expirou, peça um novo", fiber.StatusGone),
+		NotPending: NewError("The employee has no pending invitation", "O funcionário não tem convite pendente", fiber.StatusConflict),
+		EmailInUse: NewError("The email belongs to an employee of the company", "O e-mail pertence a um funcionário da empresa", fiber.StatusConflict),
+		Invalid:    NewError("Invalid invitation", "Convite inválido", fiber.StatusBadRequest),
+	},
+	Import: ImportErrors{
+		InvalidFile: NewError("The import file could not be read", "O arquivo de importação não pôde ser lido", fiber.StatusBadRequest),
+		UnknownKind: NewError("Unknown import type, use services, employees or clients", "Tipo de importação desconhecido, use services, employees ou clients", fiber.StatusBadRequest),
+		InvalidRow:  NewError("Invalid import row", "Linha de importação inválida", fiber.StatusBadRequest),
+	},
 }
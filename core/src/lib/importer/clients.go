@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// importClient finds the client of the row, matched by email, then by phone, and writes
+// the row to its profile at the company. Columns: name, surname, email, phone, and for the
+// profile tags separated by "|", notes, preferences, birthday and marketing_consent.
+// Clients are shared by every company, so the import only creates the missing ones, as
+// guests who sign up later keeping their appointments. The name and phone of the row are
+// kept in the profile, leaving the ones of existing clients as they are.
+func importClient(tx *gorm.DB, companyID uuid.UUID, row Row) (uuid.UUID, string, error) {
+	email := strings.ToLower(row.Get("email"))
+	phone := normalizePhone(row.Get("phone"))
+	if email == "" {
+		return uuid.Nil, "", invalid("email is required")
+	}
+
+	var client model.Client
+	found, err := findOne(tx, &client, "client", email, "LOWER(email) = ?", email)
+	if err == nil && !found && phone != "" {
+		found, err = findOne(tx, &client, "client", phone, "phone = ?", phone)
+	}
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	action := ActionUpdated
+	if !found {
+		action = ActionCreated
+		client = model.Client{ClientMeta: model.ClientMeta{
+			Name:    row.Get("name"),
+			Surname: row.Get("surname"),
+			Email:   email,
+			Phone:   phone,
+			IsGuest: true,
+		}}
+		if err := tx.Create(&client).Error; err != nil {
+			return uuid.Nil, "", lib.Error.General.CreatedError.WithError(err)
+		}
+	}
+
+	if err := importClientProfile(tx, companyID, client.ID, row, phone); err != nil {
+		return uuid.Nil, "", err
+	}
+	return client.ID, action, nil
+}
+
+func importClientProfile(tx *gorm.DB, companyID, clientID uuid.UUID, row Row, phone string) error {
+	profile, err := model.EnsureClientProfile(tx, companyID, clientID)
+	if err != nil {
+		return err
+	}
+
+	// Applied through a map so that false and empty values are not skipped as zero values.
+	changes := map[string]any{}
+	for column, field := range map[string]*string{"name": &profile.Name, "surname": &profile.Surname} {
+		if v := row.Get(column); v != "" {
+			*field = v
+			changes[column] = v
+		}
+	}
+	if phone != "" {
+		profile.Phone = phone
+		changes["phone"] = phone
+	}
+	if tags := row.List("tags"); len(tags) > 0 {
+		profile.Tags = model.NormalizeTags(tags)
+		changes["tags"] = profile.Tags
+	}
+	if v := row.Get("notes"); v != "" {
+		profile.Notes = v
+		changes["notes"] = v
+	}
+	if v := row.Get("preferences"); v != "" {
+		profile.Preferences = v
+		changes["preferences"] = v
+	}
+	if v := row.Get("birthday"); v != "" {
+		birthday, err := parseDate("birthday", v)
+		if err != nil {
+			return err
+		}
+		profile.Birthday = &birthday
+		changes["birthday"] = profile.Birthday
+	}
+	if v := row.Get("marketing_consent"); v != "" {
+		consent, err := parseBool("marketing_consent", v)
+		if err != nil {
+			return err
+		}
+		if consent != profile.MarketingConsent {
+			now := time.Now()
+			profile.MarketingConsent = consent
+			profile.MarketingConsentAt = &now
+			changes["marketing_consent"] = profile.MarketingConsent
+			changes["marketing_consent_at"] = profile.MarketingConsentAt
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+	if err := tx.Model(profile).Updates(changes).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package importer
+
+import (
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// importEmployee creates or updates the employee of the row, matched by email, then by
+// phone. Columns: name, surname, email, phone, time_zone, password, and the names of the
+// branches and services of the employee separated by "|". The time zone defaults to the one
+// of the first branch. Employees created without a password get a random one and reset it
+// by email. Passwords of stored employees are never changed, and branches and services
+// are only added.
+func importEmployee(tx *gorm.DB, companyID uuid.UUID, row Row) (uuid.UUID, string, error) {
+	email := strings.ToLower(row.Get("email"))
+	phone := normalizePhone(row.Get("phone"))
+	if email == "" {
+		return uuid.Nil, "", invalid("email is required")
+	}
+
+	var employee model.Employee
+	found, err := findOne(tx, &employee, "employee", email, "company_id = ? AND LOWER(email) = ?", companyID, email)
+	if err == nil && !found && phone != "" {
+		found, err = findOne(tx, &employee, "employee", phone, "company_id = ? AND phone = ?", companyID, phone)
+	}
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	branches, err := namedBranches(tx, companyID, row.List("branches"))
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	services := make([]*model.Service, 0)
+	for _, name := range row.List("services") {
+		var service model.Service
+		found, err := findOne(tx, &service, "service", name, "company_id = ? AND LOWER(name) = LOWER(?)", companyID, name)
+		if err != nil {
+			return uuid.Nil, "", err
+		}
+		if !found {
+			return uuid.Nil, "", invalid("service %q not found", name)
+		}
+		services = append(services, &service)
+	}
+
+	changes := map[string]any{"email": email}
+	employee.Email = email
+	for column, field := range map[string]*string{"name": &employee.Name, "surname": &employee.Surname, "time_zone": &employee.TimeZone} {
+		if v := row.Get(column); v != "" {
+			*field = v
+			changes[column] = v
+		}
+	}
+	if phone != "" {
+		employee.Phone = phone
+		changes["phone"] = phone
+	}
+	if employee.TimeZone == "" && len(branches) > 0 {
+		employee.TimeZone = branches[0].TimeZone
+	}
+
+	action := ActionUpdated
+	if found {
+		// The stored password is hashed, and the rule of plain passwords would reject it
+		if err := lib.MyCustomStructExceptValidator(&employee, "Password"); err != nil {
+			return uuid.Nil, "", err
+		}
+		// A fresh model keeps BeforeUpdate from checking the password
+		if err := tx.Model(&model.Employee{}).Where("id = ?", employee.ID).Updates(changes).Error; err != nil {
+			return uuid.Nil, "", lib.Error.General.UpdatedError.WithError(err)
+		}
+	} else {
+		action = ActionCreated
+		employee.CompanyID = companyID
+		employee.Password = row.Get("password")
+		if employee.Password == "" {
+			if employee.Password, err = randomPassword(); err != nil {
+				return uuid.Nil, "", lib.Error.General.InternalError.WithError(err)
+			}
+		}
+		if err := tx.Omit(clause.Associations).Create(&employee).Error; err != nil {
+			return uuid.Nil, "", lib.Error.General.CreatedError.WithError(err)
+		}
+	}
+
+	for _, branch := range branches {
+		if err := employee.HasBranch(tx, branch.ID); err == nil {
+			continue
+		}
+		if err := employee.AddBranch(tx, branch); err != nil {
+			return uuid.Nil, "", err
+		}
+	}
+	for _, service := range services {
+		if err := employee.HasService(tx, service); err == nil {
+			continue
+		}
+		if err := employee.AddService(tx, service); err != nil {
+			return uuid.Nil, "", err
+		}
+	}
+	return employee.ID, action, nil
+}
+
+func namedBranches(tx *gorm.DB, companyID uuid.UUID, names []string) ([]*model.Branch, error) {
+	branches := make([]*model.Branch, 0, len(names))
+	for _, name := range names {
+		var branch model.Branch
+		found, err := findOne(tx, &branch, "branch", name, "company_id = ? AND LOWER(name) = LOWER(?)", companyID, name)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, invalid("branch %q not found", name)
+		}
+		branches = append(branches, &branch)
+	}
+	return branches, nil
+}
+
+// randomPassword returns a password meeting the password rule that nobody knows.
+func randomPassword() (string, error) {
+	token, err := lib.GenerateSecureToken(5)
+	if err != nil {
+		return "", fmt.Errorf("error generating password: %w", err)
+	}
+	return "Aa1!" + token, nil
+}
@@ -0,0 +1,194 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"mynute-go/core/src/lib"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Kinds of records that can be imported.
+const (
+	KindServices  = "services"
+	KindEmployees = "employees"
+	KindClients   = "clients"
+)
+
+// Outcomes of an imported row.
+const (
+	ActionCreated = "CREATED"
+	ActionUpdated = "UPDATED"
+	ActionFailed  = "FAILED"
+)
+
+// RowResult is what happened to a row of the file.
+type RowResult struct {
+	Line   int        `json:"line"`
+	Action string     `json:"action"`
+	ID     *uuid.UUID `json:"id"`
+	Errors []string   `json:"errors"`
+}
+
+// Result is the outcome of an import, row by row.
+type Result struct {
+	Kind    string      `json:"kind"`
+	DryRun  bool        `json:"dry_run"`
+	Created int         `json:"created"`
+	Updated int         `json:"updated"`
+	Failed  int         `json:"failed"`
+	Rows    []RowResult `json:"rows"`
+}
+
+// rowImporter creates or updates the record of a row, returning its ID and the action taken.
+type rowImporter func(tx *gorm.DB, companyID uuid.UUID, row Row) (uuid.UUID, string, error)
+
+var importers = map[string]rowImporter{
+	KindServices:  importService,
+	KindEmployees: importEmployee,
+	KindClients:   importClient,
+}
+
+var errDryRun = errors.New("dry run")
+
+// Run imports the rows into the company. Records already stored are updated rather than
+// duplicated, so a file can be imported again once its failed rows are fixed. Each row is
+// written in a savepoint of its own: an invalid row is reported and skipped without undoing
+// the others. A dry run validates and writes every row the same way, then rolls all back.
+func Run(tx *gorm.DB, companyID uuid.UUID, kind string, rows []Row, dryRun bool) (*Result, error) {
+	importRow, ok := importers[kind]
+	if !ok {
+		return nil, lib.Error.Import.UnknownKind
+	}
+	result := &Result{Kind: kind, DryRun: dryRun, Rows: make([]RowResult, 0, len(rows))}
+
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		for i, row := range rows {
+			savepoint := fmt.Sprintf("import_row_%d", i)
+			if err := tx.SavePoint(savepoint).Error; err != nil {
+				return err
+			}
+			out := RowResult{Line: row.Line}
+			id, action, err := importRow(tx, companyID, row)
+			if err != nil {
+				if err := tx.RollbackTo(savepoint).Error; err != nil {
+					return err
+				}
+				out.Action = ActionFailed
+				out.Errors = messages(err)
+				result.Failed++
+			} else {
+				out.Action = action
+				out.ID = &id
+				if action == ActionCreated {
+					result.Created++
+				} else {
+					result.Updated++
+				}
+			}
+			result.Rows = append(result.Rows, out)
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error importing %s: %w", kind, err))
+	}
+	return result, nil
+}
+
+// messages returns the readable reasons of a row error.
+func messages(err error) []string {
+	var e lib.ErrorStruct
+	if !errors.As(err, &e) {
+		return []string{err.Error()}
+	}
+	keys := make([]int, 0, len(e.InnerError))
+	for k := range e.InnerError {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	out := []string{e.DescriptionEn}
+	for _, k := range keys {
+		out = append(out, e.InnerError[k])
+	}
+	return out
+}
+
+func invalid(format string, args ...any) error {
+	return lib.Error.Import.InvalidRow.WithError(fmt.Errorf(format, args...))
+}
+
+// findOne loads the single record matching the query into dest. It reports false when there
+// is none, and fails when several match key.
+func findOne(tx *gorm.DB, dest any, what, key string, query string, args ...any) (bool, error) {
+	result := tx.Where(query, args...).Limit(2).Find(dest)
+	if result.Error != nil {
+		return false, lib.Error.General.InternalError.WithError(result.Error)
+	}
+	if result.RowsAffected > 1 {
+		return false, invalid("more than one %s matches %q", what, key)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+var amountPattern = regexp.MustCompile(`^(\d+)(?:[.,](\d{1,2}))?$`)
+
+// parseAmount reads an amount such as "150", "150.5" or "150,50" in cents.
+func parseAmount(column, value string) (int64, error) {
+	m := amountPattern.FindStringSubmatch(value)
+	if m == nil {
+		return 0, invalid("%s %q is not an amount such as 150.00", column, value)
+	}
+	units, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, invalid("%s %q is too large", column, value)
+	}
+	cents, _ := strconv.ParseInt((m[2] + "00")[:2], 10, 64)
+	return units*100 + cents, nil
+}
+
+// parseBool reads yes/no columns, in English or Portuguese.
+func parseBool(column, value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "y", "sim", "s", "1", "x":
+		return true, nil
+	case "false", "no", "n", "não", "nao", "0":
+		return false, nil
+	}
+	return false, invalid("%s %q is not yes or no", column, value)
+}
+
+// excelEpoch is day 0 of the dates XLSX files store as numbers.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// parseDate reads dates as YYYY-MM-DD, DD/MM/YYYY or the day numbers XLSX files store.
+func parseDate(column, value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "2/1/2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	if days, err := strconv.Atoi(value); err == nil && days > 0 {
+		return excelEpoch.AddDate(0, 0, days), nil
+	}
+	return time.Time{}, invalid("%s %q is not a date such as 1990-12-31", column, value)
+}
+
+// normalizePhone drops the spaces, dashes and parentheses of phone numbers, adding the
+// leading + of the E.164 format when missing.
+func normalizePhone(phone string) string {
+	phone = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "").Replace(phone)
+	if phone != "" && !strings.HasPrefix(phone, "+") {
+		phone = "+" + phone
+	}
+	return phone
+}
@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"mynute-go/core/src/lib"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSV(t *testing.T) {
+	data := "\ufeffName;E-mail;Time Zone\nAna;ana@example.com;America/Sao_Paulo\n;;\n\"Silva; Bia\";bia@example.com;\n"
+	rows, err := Read("clients.CSV", []byte(data))
+	require.NoError(t, err)
+	require.Len(t, rows, 2, "blank rows are skipped")
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "ana@example.com", rows[0].Get("e_mail"))
+	assert.Equal(t, "America/Sao_Paulo", rows[0].Get("time_zone"))
+	assert.Equal(t, 4, rows[1].Line)
+	assert.Equal(t, "Silva; Bia", rows[1].Get("name"))
+	assert.Equal(t, "", rows[1].Get("missing"))
+}
+
+func TestReadRejects(t *testing.T) {
+	_, err := Read("clients.pdf", []byte("name\nAna"))
+	assert.Error(t, err)
+	_, err = Read("clients.csv", nil)
+	assert.Error(t, err)
+	_, err = Read("clients.xlsx", []byte("name\nAna"))
+	assert.Error(t, err)
+
+	many := "name\n" + strings.Repeat("Ana\n", MaxRows+1)
+	_, err = Read("clients.csv", []byte(many))
+	assert.Error(t, err)
+}
+
+func TestReadXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Clients" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst><si><t>name</t></si><si><t>phone</t></si><si><r><t>An</t></r><r><t>a</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c><c r="D1" t="inlineStr"><is><t>birthday</t></is></c></row>
+			<row r="2"><c r="A2" t="s"><v>2</v></c><c r="C2"><v>5.511999998888E12</v></c><c r="D2"><v>33238</v></c></row>
+		</sheetData></worksheet>`,
+	}
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	rows, err := Read("clients.xlsx", buf.Bytes())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "Ana", rows[0].Get("name"), "rich text runs are joined")
+	assert.Equal(t, "5511999998888", rows[0].Get("phone"), "numbers are written in full")
+	birthday, err := parseDate("birthday", rows[0].Get("birthday"))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(1990, 12, 31, 0, 0, 0, 0, time.UTC), birthday)
+}
+
+func TestList(t *testing.T) {
+	row := Row{Values: map[string]string{"branches": " Centro | | Zona Sul|"}}
+	assert.Equal(t, []string{"Centro", "Zona Sul"}, row.List("branches"))
+	assert.Nil(t, row.List("services"))
+}
+
+func TestParseAmount(t *testing.T) {
+	for value, cents := range map[string]int64{"150": 15000, "150.5": 15050, "150,50": 15050, "0": 0, "0.05": 5} {
+		got, err := parseAmount("price", value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, cents, got, value)
+	}
+	for _, value := range []string{"-1", "1.234,56", "R$ 10", "10.123", "abc"} {
+		_, err := parseAmount("price", value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(1990, 12, 31, 0, 0, 0, 0, time.UTC)
+	for _, value := range []string{"1990-12-31", "31/12/1990", "33238"} {
+		got, err := parseDate("birthday", value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+	_, err := parseDate("birthday", "December 31")
+	assert.Error(t, err)
+}
+
+func TestParseBool(t *testing.T) {
+	for _, value := range []string{"Yes", "sim", "TRUE", "1", "x"} {
+		got, err := parseBool("marketing_consent", value)
+		assert.NoError(t, err)
+		assert.True(t, got, value)
+	}
+	for _, value := range []string{"no", "Não", "false", "0"} {
+		got, err := parseBool("marketing_consent", value)
+		assert.NoError(t, err)
+		assert.False(t, got, value)
+	}
+	_, err := parseBool("marketing_consent", "maybe")
+	assert.Error(t, err)
+}
+
+func TestNormalizePhone(t *testing.T) {
+	assert.Equal(t, "+5511999998888", normalizePhone("+55 (11) 99999-8888"))
+	assert.Equal(t, "+5511999998888", normalizePhone("5511999998888"))
+	assert.Equal(t, "", normalizePhone(""))
+}
+
+func TestMessages(t *testing.T) {
+	assert.Equal(t, []string{"Invalid import row", "email is required"}, messages(invalid("email is required")))
+	assert.Equal(t, []string{"boom"}, messages(fmt.Errorf("boom")))
+	assert.Equal(t, []string{lib.Error.General.BadRequest.DescriptionEn}, messages(lib.Error.General.BadRequest))
+}
+
+func TestRandomPassword(t *testing.T) {
+	password, err := randomPassword()
+	require.NoError(t, err)
+	assert.NoError(t, lib.ValidatorV10.Var(password, "myPasswordValidation"))
+}
+
+func TestRunUnknownKind(t *testing.T) {
+	_, err := Run(nil, uuid.Nil, "branches", nil, true)
+	assert.Equal(t, lib.Error.Import.UnknownKind, err)
+}
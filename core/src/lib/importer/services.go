@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// importService creates or updates the service named in the row, matched by name.
+// Columns: name, description, price, duration (minutes), currency and branches, the names
+// of the branches offering it separated by "|". Empty columns keep the stored values.
+func importService(tx *gorm.DB, companyID uuid.UUID, row Row) (uuid.UUID, string, error) {
+	name := row.Get("name")
+	if name == "" {
+		return uuid.Nil, "", invalid("name is required")
+	}
+	var service model.Service
+	found, err := findOne(tx, &service, "service", name, "company_id = ? AND LOWER(name) = LOWER(?)", companyID, name)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	action := ActionUpdated
+	if !found {
+		service = model.Service{Name: name, CompanyID: companyID}
+		action = ActionCreated
+	}
+
+	changes := map[string]any{}
+	if v := row.Get("description"); v != "" {
+		service.Description = v
+		changes["description"] = v
+	}
+	if v := row.Get("price"); v != "" {
+		price, err := parseAmount("price", v)
+		if err != nil {
+			return uuid.Nil, "", err
+		}
+		service.Price = price
+		changes["price"] = price
+	}
+	if v := row.Get("duration"); v != "" {
+		duration, err := strconv.ParseUint(v, 10, 16)
+		if err != nil || duration == 0 {
+			return uuid.Nil, "", invalid("duration %q is not a number of minutes", v)
+		}
+		service.Duration = uint16(duration)
+		changes["duration"] = service.Duration
+	}
+	if v := row.Get("currency"); v != "" {
+		service.Currency = strings.ToUpper(v)
+		changes["currency"] = service.Currency
+	}
+	if service.Duration == 0 {
+		return uuid.Nil, "", invalid("duration is required")
+	}
+	// Free services are fine, the required rule of the price would reject them
+	if err := lib.MyCustomStructExceptValidator(&service, "Price"); err != nil {
+		return uuid.Nil, "", err
+	}
+
+	if found {
+		if len(changes) > 0 {
+			if err := tx.Model(&service).Updates(changes).Error; err != nil {
+				return uuid.Nil, "", lib.Error.General.UpdatedError.WithError(err)
+			}
+		}
+	} else if err := tx.Omit(clause.Associations).Create(&service).Error; err != nil {
+		return uuid.Nil, "", lib.Error.General.CreatedError.WithError(err)
+	}
+
+	branches, err := namedBranches(tx, companyID, row.List("branches"))
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	for _, branch := range branches {
+		var count int64
+		if err := tx.Raw("SELECT COUNT(*) FROM branch_services WHERE branch_id = ? AND service_id = ?", branch.ID, service.ID).Scan(&count).Error; err != nil {
+			return uuid.Nil, "", lib.Error.General.InternalError.WithError(fmt.Errorf("error checking branch services: %w", err))
+		}
+		if count > 0 {
+			continue
+		}
+		if err := branch.AddService(tx, &service); err != nil {
+			return uuid.Nil, "", err
+		}
+	}
+	return service.ID, action, nil
+}
@@ -0,0 +1,275 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// MaxRows is the most data rows a file can have.
+const MaxRows = 5000
+
+// Row is a data row of an imported file, keyed by the normalized column header.
+type Row struct {
+	Line   int // Line of the row in the file, the header being line 1
+	Values map[string]string
+}
+
+// Get returns the trimmed value of the column, empty when the file has no such column.
+func (r Row) Get(column string) string {
+	return strings.TrimSpace(r.Values[column])
+}
+
+// List splits a column holding several values separated by "|".
+func (r Row) List(column string) []string {
+	var values []string
+	for _, v := range strings.Split(r.Get(column), "|") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Read parses a CSV or XLSX file, by the extension of its name, into rows. The first row
+// holds the column headers. Only the first sheet of XLSX files is read.
+func Read(filename string, data []byte) ([]Row, error) {
+	var records [][]string
+	var err error
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv", ".txt":
+		records, err = readCSV(data)
+	case ".xlsx":
+		records, err = readXLSX(data)
+	default:
+		return nil, fmt.Errorf("unsupported file type %q, upload a .csv or .xlsx file", path.Ext(filename))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toRows(records)
+}
+
+func toRows(records [][]string) ([]Row, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	headers := make([]string, len(records[0]))
+	for i, h := range records[0] {
+		headers[i] = normalizeHeader(h)
+	}
+	var rows []Row
+	for i, record := range records[1:] {
+		row := Row{Line: i + 2, Values: make(map[string]string, len(headers))}
+		blank := true
+		for j, value := range record {
+			if j >= len(headers) || headers[j] == "" {
+				continue
+			}
+			row.Values[headers[j]] = value
+			if strings.TrimSpace(value) != "" {
+				blank = false
+			}
+		}
+		if blank {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) > MaxRows {
+		return nil, fmt.Errorf("the file has %d rows, at most %d can be imported at once", len(rows), MaxRows)
+	}
+	return rows, nil
+}
+
+// normalizeHeader turns "Time Zone" or "time-zone" into "time_zone".
+func normalizeHeader(h string) string {
+	h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(h)
+}
+
+// readCSV reads comma or semicolon separated files, the latter being what spreadsheets
+// export in locales using the comma as decimal separator.
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV file: %w", err)
+	}
+	return records, nil
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX reads the cell values of the first sheet of an XLSX workbook.
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid XLSX file: %w", err)
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeXML(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	var rels xlsxRelationships
+	if err := decodeXML(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, fmt.Errorf("invalid XLSX file: the workbook has no sheets")
+	}
+	sheetPath := ""
+	for _, rel := range rels.Relationships {
+		if rel.ID == workbook.Sheets[0].RelID {
+			sheetPath = rel.Target
+			if strings.HasPrefix(sheetPath, "/") {
+				sheetPath = strings.TrimPrefix(sheetPath, "/")
+			} else {
+				sheetPath = path.Join("xl", sheetPath)
+			}
+		}
+	}
+
+	var shared xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXML(files, "xl/sharedStrings.xml", &shared); err != nil {
+			return nil, err
+		}
+	}
+	var sheet xlsxSheet
+	if err := decodeXML(files, sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+
+	records := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		var record []string
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				column = columnIndex(cell.Ref)
+			}
+			for len(record) <= column {
+				record = append(record, "")
+			}
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(shared.Items) {
+					return nil, fmt.Errorf("invalid XLSX file: unknown shared string in cell %s", cell.Ref)
+				}
+				record[column] = shared.Items[index].String()
+			case "inlineStr":
+				record[column] = cell.Inline.String()
+			case "", "n":
+				record[column] = xlsxNumber(cell.Value)
+			default:
+				record[column] = cell.Value
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func decodeXML(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("invalid XLSX file: %s is missing", name)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("invalid XLSX file: %w", err)
+	}
+	defer r.Close()
+	if err := xml.NewDecoder(io.LimitReader(r, 64<<20)).Decode(v); err != nil {
+		return fmt.Errorf("invalid XLSX file: %s: %w", name, err)
+	}
+	return nil
+}
+
+// columnIndex returns the zero based column of a cell reference such as "AB12".
+func columnIndex(ref string) int {
+	column := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		column = column*26 + int(ch-'A'+1)
+	}
+	return column - 1
+}
+
+// xlsxNumber writes numbers stored in scientific notation, as long phone numbers are,
+// in full.
+func xlsxNumber(value string) string {
+	if !strings.ContainsAny(value, "eE") {
+		return value
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,209 @@
+package intake
+
+import (
+	"bytes"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	myUploader "mynute-go/core/src/lib/cloud_uploader"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Consent is a signed consent form, as drawn on its PDF.
+type Consent struct {
+	ResponseID   uuid.UUID
+	CompanyName  string
+	FormName     string
+	Version      int
+	ConsentText  string
+	Fields       model.IntakeFields
+	Answers      model.IntakeAnswers
+	AttendeeName string // The dependent the client signed for, empty when the client attends
+	SignedName   string
+	SignedAt     time.Time
+	SignatureIP  string
+}
+
+// StoreConsent renders the signed consent of the response and stores it through the cloud
+// uploader, returning its URL.
+func StoreConsent(tx *gorm.DB, response *model.IntakeResponse, version *model.IntakeFormVersion, language string) (string, error) {
+	consent, err := LoadConsent(tx, response, version)
+	if err != nil {
+		return "", err
+	}
+	pdf, err := Render(consent, language)
+	if err != nil {
+		return "", lib.Error.General.InternalError.WithError(err)
+	}
+	up, err := myUploader.FileUploader("intake_response", response.ID.String())
+	if err != nil {
+		return "", lib.Error.General.InternalError.WithError(err)
+	}
+	url, err := up.Save("pdf", pdf, Filename(consent))
+	if err != nil {
+		return "", lib.Error.General.InternalError.WithError(fmt.Errorf("failed to store consent: %w", err))
+	}
+	return url, nil
+}
+
+// LoadConsent gathers what the consent PDF of a signed response shows.
+func LoadConsent(tx *gorm.DB, response *model.IntakeResponse, version *model.IntakeFormVersion) (*Consent, error) {
+	var form model.IntakeForm
+	if err := tx.Select("id", "company_id").Where("id = ?", response.FormID).First(&form).Error; err != nil {
+		return nil, lib.Error.IntakeForm.NotFound.WithError(err)
+	}
+	var company model.Company
+	if err := tx.Where("id = ?", form.CompanyID).First(&company).Error; err != nil {
+		return nil, lib.Error.Company.NotFound.WithError(err)
+	}
+	consent := &Consent{
+		ResponseID:  response.ID,
+		CompanyName: company.LegalName,
+		FormName:    version.Name,
+		Version:     version.Version,
+		ConsentText: version.ConsentText,
+		Fields:      version.Fields,
+		Answers:     response.Answers,
+		SignedName:  response.SignedName,
+		SignatureIP: response.SignatureIP,
+	}
+	if response.SignedAt != nil {
+		consent.SignedAt = *response.SignedAt
+	}
+	if response.DependentID != nil {
+		var dependent model.ClientDependent
+		if err := tx.Where("id = ?", *response.DependentID).First(&dependent).Error; err != nil {
+			return nil, lib.Error.Client.DependentNotFound.WithError(err)
+		}
+		consent.AttendeeName = dependent.Name
+	}
+	return consent, nil
+}
+
+// Filename is the name of the consent file, as stored.
+func Filename(c *Consent) string {
+	return fmt.Sprintf("consent-%s.pdf", c.ResponseID)
+}
+
+// Render draws the signed consent as an A4 PDF in the given language (en, pt or es, defaults to en),
+// with the consent text, the answers given to the form and the signature.
+func Render(c *Consent, language string) ([]byte, error) {
+	l := labelsFor(language)
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("%s - %s", l.title, c.FormName), true)
+	pdf.SetAuthor(c.CompanyName, true)
+	pdf.SetCreationDate(c.SignedAt)
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+	// Core fonts are encoded in cp1252, which covers Portuguese and Spanish accents
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 8, tr(c.CompanyName), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.MultiCell(0, 8, tr(c.FormName), "", "L", false)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(0, 5, tr(fmt.Sprintf("%s %d", l.version, c.Version)), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.MultiCell(0, 5, tr(c.ConsentText), "", "J", false)
+	pdf.Ln(4)
+
+	if len(c.Answers) > 0 {
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.CellFormat(0, 8, tr(l.answers), "", 1, "L", false, 0, "")
+		for _, field := range c.Fields {
+			value, ok := c.Answers[field.Key]
+			if !ok {
+				continue
+			}
+			pdf.SetFont("Helvetica", "B", 10)
+			pdf.MultiCell(0, 6, tr(field.Label), "", "L", false)
+			pdf.SetFont("Helvetica", "", 10)
+			pdf.MultiCell(0, 6, tr(formatAnswer(value, l)), "", "L", false)
+		}
+		pdf.Ln(4)
+	}
+
+	row := func(label, value string) {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(45, 7, tr(label), "T", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 7, tr(value), "T", 1, "L", false, 0, "")
+	}
+	row(l.signedBy, c.SignedName)
+	if c.AttendeeName != "" {
+		row(l.onBehalfOf, c.AttendeeName)
+	}
+	row(l.signedAt, c.SignedAt.UTC().Format(l.dateFormat+" 15:04 MST"))
+	if c.SignatureIP != "" {
+		row(l.ip, c.SignatureIP)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render consent: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatAnswer writes an answer as shown on the PDF.
+func formatAnswer(value any, l labels) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return l.yes
+		}
+		return l.no
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		return strings.Join(v, ", ")
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type labels struct {
+	title, version, answers, signedBy, onBehalfOf, signedAt, ip, yes, no, dateFormat string
+}
+
+var translations = map[string]labels{
+	"en": {
+		title: "Consent", version: "Version", answers: "Answers", signedBy: "Signed by",
+		onBehalfOf: "On behalf of", signedAt: "Signed on", ip: "IP address", yes: "Yes", no: "No",
+		dateFormat: "2006-01-02",
+	},
+	"pt": {
+		title: "Consentimento", version: "Versão", answers: "Respostas", signedBy: "Assinado por",
+		onBehalfOf: "Em nome de", signedAt: "Assinado em", ip: "Endereço IP", yes: "Sim", no: "Não",
+		dateFormat: "02/01/2006",
+	},
+	"es": {
+		title: "Consentimiento", version: "Versión", answers: "Respuestas", signedBy: "Firmado por",
+		onBehalfOf: "En nombre de", signedAt: "Firmado el", ip: "Dirección IP", yes: "Sí", no: "No",
+		dateFormat: "02/01/2006",
+	},
+}
+
+func labelsFor(language string) labels {
+	if l, ok := translations[language]; ok {
+		return l
+	}
+	return translations["en"]
+}
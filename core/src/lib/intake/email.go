@@ -0,0 +1,86 @@
+package intake
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib/email"
+	"mynute-go/core/src/lib/outbox"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailJob is the outbox payload of the email asking the client to answer a pending response.
+type EmailJob struct {
+	ResponseID uuid.UUID `json:"response_id"`
+	Language   string    `json:"language"`
+	Link       string    `json:"link"`
+}
+
+// EnqueueEmail records the email with the link to answer the response in the outbox,
+// within the caller's transaction.
+func EnqueueEmail(tx *gorm.DB, companyID uuid.UUID, response *model.IntakeResponse, language, link string) error {
+	return outbox.Enqueue(tx, &companyID, model.OutboxTopicIntakeEmail, EmailJob{ResponseID: response.ID, Language: language, Link: link})
+}
+
+// HandleEmail is the outbox handler for model.OutboxTopicIntakeEmail.
+// Responses answered meanwhile are not asked for again.
+func HandleEmail(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+	var job EmailJob
+	if err := outbox.Decode(msg, &job); err != nil {
+		return err
+	}
+	language := job.Language
+	if language == "" {
+		language = "en"
+	}
+
+	var response model.IntakeResponse
+	if err := tx.Where("id = ?", job.ResponseID).First(&response).Error; err != nil {
+		return fmt.Errorf("failed to load intake response %s: %w", job.ResponseID, err)
+	}
+	if response.Status != model.IntakePending {
+		return nil
+	}
+	var version model.IntakeFormVersion
+	if err := tx.Where("id = ?", response.FormVersionID).First(&version).Error; err != nil {
+		return fmt.Errorf("failed to load intake form version: %w", err)
+	}
+	var company model.Company
+	if err := tx.Where("id = ?", msg.CompanyID).First(&company).Error; err != nil {
+		return fmt.Errorf("failed to load company: %w", err)
+	}
+	var client model.Client
+	if err := tx.Where("id = ?", response.ClientID).First(&client).Error; err != nil {
+		return fmt.Errorf("failed to load client: %w", err)
+	}
+	if client.Email == "" {
+		log.Printf("intake response %s: client %s has no email, not sent", response.ID, client.ID)
+		return nil
+	}
+
+	renderer := email.NewTemplateRenderer(filepath.Join("static", "email"), filepath.Join("translation", "email"))
+	rendered, err := renderer.RenderEmail("intake_form_request", language, email.TemplateData{
+		"ClientName":  strings.TrimSpace(client.Name + " " + client.Surname),
+		"CompanyName": company.TradeName,
+		"FormName":    version.Name,
+		"IntakeLink":  job.Link,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+
+	sender, err := email.NewProvider(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create email provider: %w", err)
+	}
+	return sender.Send(ctx, email.EmailData{
+		To:      []string{client.Email},
+		Subject: rendered.Subject,
+		Html:    rendered.HTMLBody,
+	})
+}
@@ -0,0 +1,316 @@
+package intake
+
+import (
+	"errors"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Limits of the answers to text fields.
+const (
+	MaxTextLength     = 500
+	MaxLongTextLength = 5000
+)
+
+// Submission is what the client sends for one form, at booking or through the emailed link.
+type Submission struct {
+	FormID     uuid.UUID      `json:"form_id"`
+	Answers    map[string]any `json:"answers"`
+	SignedName string         `json:"signed_name"` // Full name of the client, required by consent forms
+}
+
+// Booking is how the forms asked by a new appointment are answered.
+type Booking struct {
+	Submissions []Submission
+	IP          string
+	Language    string
+	BaseURL     string // Of the emailed links, e.g. https://mynute.app
+}
+
+// FormsFor returns the active forms of the company asked by the service.
+func FormsFor(tx *gorm.DB, serviceID uuid.UUID) ([]model.IntakeForm, error) {
+	var forms []model.IntakeForm
+	if err := tx.Where("is_active = ? AND service_ids @> ?::jsonb", true, fmt.Sprintf("[%q]", serviceID)).
+		Order("name").Find(&forms).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading intake forms: %w", err))
+	}
+	return forms, nil
+}
+
+// Require makes sure the attendee of the appointment answered the current version of every
+// form asked by its service. Forms answered in the booking are completed right away, the
+// others are left pending and the client is emailed a link to answer them.
+func Require(tx *gorm.DB, appointment *model.Appointment, booking Booking) error {
+	forms, err := FormsFor(tx, appointment.ServiceID)
+	if err != nil {
+		return err
+	}
+	for _, sub := range booking.Submissions {
+		if !slices.ContainsFunc(forms, func(f model.IntakeForm) bool { return f.ID == sub.FormID }) {
+			return lib.Error.IntakeForm.InvalidAnswers.WithError(fmt.Errorf("form %s is not asked by the service", sub.FormID))
+		}
+	}
+
+	for i := range forms {
+		form := &forms[i]
+		answered, err := Answered(tx, form, appointment.ClientID, appointment.DependentID)
+		if err != nil {
+			return err
+		}
+		if answered {
+			continue
+		}
+		version, err := LoadVersion(tx, form.ID, form.Version)
+		if err != nil {
+			return err
+		}
+		response := model.IntakeResponse{
+			FormID:        form.ID,
+			FormVersionID: version.ID,
+			Version:       version.Version,
+			ClientID:      appointment.ClientID,
+			DependentID:   appointment.DependentID,
+			AppointmentID: &appointment.ID,
+			Status:        model.IntakePending,
+		}
+
+		idx := slices.IndexFunc(booking.Submissions, func(s Submission) bool { return s.FormID == form.ID })
+		if idx >= 0 {
+			if err := tx.Create(&response).Error; err != nil {
+				return lib.Error.General.CreatedError.WithError(fmt.Errorf("error saving intake response: %w", err))
+			}
+			if err := Complete(tx, &response, version, booking.Submissions[idx], booking.IP, booking.Language, time.Now()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		token, err := lib.GenerateSecureToken(32)
+		if err != nil {
+			return lib.Error.General.InternalError.WithError(fmt.Errorf("error generating intake token: %w", err))
+		}
+		response.Token = &token
+		if err := tx.Create(&response).Error; err != nil {
+			return lib.Error.General.CreatedError.WithError(fmt.Errorf("error saving intake response: %w", err))
+		}
+		if err := EnqueueEmail(tx, appointment.CompanyID, &response, booking.Language, Link(booking.BaseURL, appointment.CompanyID, token, booking.Language)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Answered reports whether the attendee, the client or one of its dependents, completed the
+// current version of the form.
+func Answered(tx *gorm.DB, form *model.IntakeForm, clientID uuid.UUID, dependentID *uuid.UUID) (bool, error) {
+	var count int64
+	if err := model.WhereAttendee(tx.Model(&model.IntakeResponse{}), clientID, dependentID).
+		Where("form_id = ? AND version = ? AND status = ?", form.ID, form.Version, model.IntakeCompleted).
+		Count(&count).Error; err != nil {
+		return false, lib.Error.General.InternalError.WithError(fmt.Errorf("error checking intake responses: %w", err))
+	}
+	return count > 0, nil
+}
+
+// LoadVersion loads a version of a form.
+func LoadVersion(tx *gorm.DB, formID uuid.UUID, version int) (*model.IntakeFormVersion, error) {
+	var v model.IntakeFormVersion
+	if err := tx.Where("form_id = ? AND version = ?", formID, version).First(&v).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, lib.Error.IntakeForm.NotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading intake form version: %w", err))
+	}
+	return &v, nil
+}
+
+// Complete records the answers of a pending response to its form version. Consent forms must
+// be signed, their PDF is rendered and stored through the cloud uploader.
+func Complete(tx *gorm.DB, response *model.IntakeResponse, version *model.IntakeFormVersion, sub Submission, ip, language string, now time.Time) error {
+	if response.Status == model.IntakeCompleted {
+		return lib.Error.IntakeForm.AlreadyAnswered
+	}
+	answers, err := ValidateAnswers(version.Fields, sub.Answers)
+	if err != nil {
+		return err
+	}
+	changes := map[string]any{
+		"status":       model.IntakeCompleted,
+		"answers":      answers,
+		"token":        nil,
+		"completed_at": now,
+	}
+	response.Answers = answers
+	if version.IsConsent {
+		signedName := strings.TrimSpace(sub.SignedName)
+		if len(signedName) < 3 {
+			return lib.Error.IntakeForm.SignatureRequired
+		}
+		response.SignedName = signedName
+		response.SignedAt = &now
+		response.SignatureIP = ip
+		url, err := StoreConsent(tx, response, version, language)
+		if err != nil {
+			return err
+		}
+		changes["signed_name"] = signedName
+		changes["signed_at"] = now
+		changes["signature_ip"] = ip
+		changes["consent_url"] = url
+		response.ConsentURL = url
+	}
+	if err := tx.Model(response).Updates(changes).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error saving intake answers: %w", err))
+	}
+	response.Status = model.IntakeCompleted
+	response.Token = nil
+	response.CompletedAt = &now
+	return nil
+}
+
+// ValidateAnswers checks the answers against the fields of a form version and returns them
+// normalized: dates as YYYY-MM-DD, texts trimmed and unanswered optional fields left out.
+func ValidateAnswers(fields model.IntakeFields, answers map[string]any) (model.IntakeAnswers, error) {
+	out := model.IntakeAnswers{}
+	for key := range answers {
+		if !slices.ContainsFunc(fields, func(f model.IntakeField) bool { return f.Key == key }) {
+			return nil, lib.Error.IntakeForm.InvalidAnswers.WithError(fmt.Errorf("unknown field %q", key))
+		}
+	}
+	for _, field := range fields {
+		value, ok := answers[field.Key]
+		if ok && value != nil {
+			normalized, err := validateAnswer(field, value)
+			if err != nil {
+				return nil, lib.Error.IntakeForm.InvalidAnswers.WithError(fmt.Errorf("field %q: %w", field.Key, err))
+			}
+			value = normalized
+		}
+		if value == nil || value == "" {
+			if field.Required {
+				return nil, lib.Error.IntakeForm.InvalidAnswers.WithError(fmt.Errorf("field %q is required", field.Key))
+			}
+			continue
+		}
+		if list, isList := value.([]string); isList && len(list) == 0 {
+			if field.Required {
+				return nil, lib.Error.IntakeForm.InvalidAnswers.WithError(fmt.Errorf("field %q is required", field.Key))
+			}
+			continue
+		}
+		out[field.Key] = value
+	}
+	return out, nil
+}
+
+func validateAnswer(field model.IntakeField, value any) (any, error) {
+	switch field.Type {
+	case model.IntakeFieldText, model.IntakeFieldLongText:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a text")
+		}
+		s = strings.TrimSpace(s)
+		limit := MaxTextLength
+		if field.Type == model.IntakeFieldLongText {
+			limit = MaxLongTextLength
+		}
+		if len(s) > limit {
+			return nil, fmt.Errorf("must have at most %d characters", limit)
+		}
+		return s, nil
+	case model.IntakeFieldNumber:
+		n, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return n, nil
+	case model.IntakeFieldDate:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a date in the YYYY-MM-DD format")
+		}
+		if s == "" {
+			return s, nil
+		}
+		if _, err := time.Parse(time.DateOnly, s); err != nil {
+			return nil, fmt.Errorf("must be a date in the YYYY-MM-DD format")
+		}
+		return s, nil
+	case model.IntakeFieldBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("must be true or false")
+		}
+		// Required yes/no questions only need an answer, false is one
+		return b, nil
+	case model.IntakeFieldChoice:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be one of the options")
+		}
+		if s != "" && !slices.Contains(field.Options, s) {
+			return nil, fmt.Errorf("%q is not one of the options", s)
+		}
+		return s, nil
+	case model.IntakeFieldMultiChoice:
+		items, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("must be a list of options")
+		}
+		chosen := make([]string, 0, len(items))
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok || !slices.Contains(field.Options, s) {
+				return nil, fmt.Errorf("%v is not one of the options", item)
+			}
+			if !slices.Contains(chosen, s) {
+				chosen = append(chosen, s)
+			}
+		}
+		return chosen, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", field.Type)
+}
+
+// Link is the page the client answers a pending response at.
+func Link(baseURL string, companyID uuid.UUID, token, language string) string {
+	return fmt.Sprintf("%s/intake?token=%s&company_id=%s&lang=%s", baseURL, token, companyID, language)
+}
+
+// ForAppointment returns, for every form asked by the service of the appointment, the latest
+// completed response of its attendee, or else the pending one. complete reports whether every
+// form was answered in its current version.
+func ForAppointment(tx *gorm.DB, appointment *model.Appointment) ([]model.IntakeResponse, bool, error) {
+	forms, err := FormsFor(tx, appointment.ServiceID)
+	if err != nil {
+		return nil, false, err
+	}
+	responses := []model.IntakeResponse{}
+	complete := true
+	for i := range forms {
+		var response model.IntakeResponse
+		err := model.WhereAttendee(tx, appointment.ClientID, appointment.DependentID).
+			Where("form_id = ?", forms[i].ID).
+			Order("completed_at IS NULL, created_at DESC"). // Completed first
+			First(&response).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			complete = false
+			continue
+		} else if err != nil {
+			return nil, false, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading intake responses: %w", err))
+		}
+		if response.Status != model.IntakeCompleted || response.Version != forms[i].Version {
+			complete = false
+		}
+		responses = append(responses, response)
+	}
+	return responses, complete, nil
+}
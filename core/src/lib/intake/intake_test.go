@@ -0,0 +1,88 @@
+package intake
+
+import (
+	"mynute-go/core/src/config/db/model"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fields = model.IntakeFields{
+	{Key: "allergies", Label: "Allergies", Type: model.IntakeFieldLongText, Required: true},
+	{Key: "weight", Label: "Weight (kg)", Type: model.IntakeFieldNumber},
+	{Key: "last_treatment", Label: "Last treatment", Type: model.IntakeFieldDate},
+	{Key: "pregnant", Label: "Pregnant", Type: model.IntakeFieldBoolean, Required: true},
+	{Key: "skin_type", Label: "Skin type", Type: model.IntakeFieldChoice, Options: []string{"dry", "oily", "mixed"}},
+	{Key: "conditions", Label: "Conditions", Type: model.IntakeFieldMultiChoice, Options: []string{"diabetes", "hypertension"}},
+}
+
+func TestValidateAnswers(t *testing.T) {
+	answers, err := ValidateAnswers(fields, map[string]any{
+		"allergies":      "  penicillin ",
+		"weight":         72.5,
+		"last_treatment": "2029-11-30",
+		"pregnant":       false,
+		"skin_type":      "oily",
+		"conditions":     []any{"diabetes", "diabetes"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "penicillin", answers["allergies"])
+	assert.Equal(t, 72.5, answers["weight"])
+	assert.Equal(t, false, answers["pregnant"], "false answers a required yes/no question")
+	assert.Equal(t, []string{"diabetes"}, answers["conditions"])
+
+	answers, err = ValidateAnswers(fields, map[string]any{"allergies": "none", "pregnant": true, "skin_type": ""})
+	require.NoError(t, err)
+	assert.NotContains(t, answers, "skin_type", "unanswered optional fields are left out")
+	assert.NotContains(t, answers, "weight")
+}
+
+func TestValidateAnswersRejects(t *testing.T) {
+	cases := map[string]map[string]any{
+		"missing required":  {"pregnant": true},
+		"blank required":    {"allergies": "   ", "pregnant": true},
+		"unknown field":     {"allergies": "none", "pregnant": true, "height": 180.0},
+		"text as number":    {"allergies": 1.0, "pregnant": true},
+		"number as text":    {"allergies": "none", "pregnant": true, "weight": "72"},
+		"invalid date":      {"allergies": "none", "pregnant": true, "last_treatment": "30/11/2029"},
+		"unknown option":    {"allergies": "none", "pregnant": true, "skin_type": "normal"},
+		"unknown options":   {"allergies": "none", "pregnant": true, "conditions": []any{"asthma"}},
+		"boolean as string": {"allergies": "none", "pregnant": "no"},
+	}
+	for name, answers := range cases {
+		_, err := ValidateAnswers(fields, answers)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRender(t *testing.T) {
+	c := &Consent{
+		ResponseID:   uuid.MustParse("1a2b3c4d-0000-0000-0000-000000000000"),
+		CompanyName:  "Clínica Estética Ltda",
+		FormName:     "Consentimento de peeling",
+		Version:      2,
+		ConsentText:  "Declaro que fui informado sobre os riscos do procedimento e autorizo sua realização.",
+		Fields:       fields,
+		Answers:      model.IntakeAnswers{"allergies": "Penicilina", "pregnant": false, "conditions": []string{"diabetes"}},
+		AttendeeName: "Maria Silva",
+		SignedName:   "João Silva",
+		SignedAt:     time.Date(2030, 1, 10, 11, 0, 0, 0, time.UTC),
+		SignatureIP:  "203.0.113.7",
+	}
+	assert.Equal(t, "consent-1a2b3c4d-0000-0000-0000-000000000000.pdf", Filename(c))
+	for _, language := range []string{"en", "pt", "es", ""} {
+		pdf, err := Render(c, language)
+		require.NoError(t, err)
+		// The cloud uploader pdf strategy accepts it
+		assert.Equal(t, "application/pdf", http.DetectContentType(pdf))
+	}
+}
+
+func TestLink(t *testing.T) {
+	companyID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	assert.Equal(t, "https://mynute.app/intake?token=abc&company_id=00000000-0000-0000-0000-000000000001&lang=pt", Link("https://mynute.app", companyID, "abc", "pt"))
+}
@@ -0,0 +1,77 @@
+package invitation
+
+import (
+	"context"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib/email"
+	"mynute-go/core/src/lib/outbox"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailJob is the outbox payload of the email inviting an employee to join the company.
+type EmailJob struct {
+	InvitationID uuid.UUID `json:"invitation_id"`
+	Language     string    `json:"language"`
+	Link         string    `json:"link"`
+}
+
+// EnqueueEmail records the email with the link to accept the invitation in the outbox,
+// within the caller's transaction.
+func EnqueueEmail(tx *gorm.DB, invitation *model.EmployeeInvitation, link string) error {
+	return outbox.Enqueue(tx, &invitation.CompanyID, model.OutboxTopicInvitationEmail, EmailJob{InvitationID: invitation.ID, Language: invitation.Language, Link: link})
+}
+
+// HandleEmail is the outbox handler for model.OutboxTopicInvitationEmail.
+// Invitations accepted, revoked or sent again meanwhile are not emailed with the stale link.
+func HandleEmail(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+	var job EmailJob
+	if err := outbox.Decode(msg, &job); err != nil {
+		return err
+	}
+	language := job.Language
+	if language == "" {
+		language = "en"
+	}
+
+	var invitation model.EmployeeInvitation
+	if err := tx.Where("id = ?", job.InvitationID).First(&invitation).Error; err != nil {
+		return fmt.Errorf("failed to load invitation %s: %w", job.InvitationID, err)
+	}
+	if invitation.Status != model.InvitationPending || invitation.Token == nil || !strings.Contains(job.Link, "token="+*invitation.Token) {
+		return nil
+	}
+	var company model.Company
+	if err := tx.Where("id = ?", msg.CompanyID).First(&company).Error; err != nil {
+		return fmt.Errorf("failed to load company: %w", err)
+	}
+	var employee model.Employee
+	if err := tx.Select("id", "name", "surname").Where("id = ?", invitation.EmployeeID).First(&employee).Error; err != nil {
+		return fmt.Errorf("failed to load employee: %w", err)
+	}
+
+	renderer := email.NewTemplateRenderer(filepath.Join("static", "email"), filepath.Join("translation", "email"))
+	rendered, err := renderer.RenderEmail("employee_invitation", language, email.TemplateData{
+		"EmployeeName":   strings.TrimSpace(employee.Name + " " + employee.Surname),
+		"CompanyName":    company.TradeName,
+		"InvitationLink": job.Link,
+		"ExpiresAt":      invitation.ExpiresAt.Format("2006-01-02 15:04 MST"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+
+	sender, err := email.NewProvider(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create email provider: %w", err)
+	}
+	return sender.Send(ctx, email.EmailData{
+		To:      []string{invitation.Email},
+		Subject: rendered.Subject,
+		Html:    rendered.HTMLBody,
+	})
+}
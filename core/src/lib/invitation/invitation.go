@@ -0,0 +1,370 @@
+package invitation
+
+import (
+	"errors"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Invitee is who a manager invites, with the roles, branches and services they get.
+type Invitee struct {
+	Email      string
+	Name       string
+	Surname    string
+	Phone      string
+	TimeZone   string // Defaults to the one of the first branch
+	RoleIDs    []uuid.UUID
+	BranchIDs  []uuid.UUID
+	ServiceIDs []uuid.UUID
+	Language   string
+}
+
+// Invite creates the employee invited, unable to log in until they accept, and records the
+// email with the link to accept in the outbox. Employees invited before and still pending
+// are invited again with the new details, their previous link stops working.
+func Invite(tx *gorm.DB, companyID uuid.UUID, invitee Invitee, invitedByID *uuid.UUID, baseURL string, now time.Time) (*model.Employee, *model.EmployeeInvitation, error) {
+	invitee.Email = strings.ToLower(strings.TrimSpace(invitee.Email))
+	if invitee.Email == "" {
+		return nil, nil, lib.Error.Invitation.Invalid.WithError(fmt.Errorf("email is required"))
+	}
+	roles, err := loadRoles(tx, companyID, invitee.RoleIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	var branches []*model.Branch
+	if err := loadAll(tx, &branches, "branch", invitee.BranchIDs); err != nil {
+		return nil, nil, err
+	}
+	var services []*model.Service
+	if err := loadAll(tx, &services, "service", invitee.ServiceIDs); err != nil {
+		return nil, nil, err
+	}
+	if invitee.TimeZone == "" && len(branches) > 0 {
+		invitee.TimeZone = branches[0].TimeZone
+	}
+
+	var employee model.Employee
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("LOWER(email) = ?", invitee.Email).First(&employee).Error
+	switch {
+	case err == nil:
+		if employee.Status != model.EmployeeInvited {
+			return nil, nil, lib.Error.Invitation.EmailInUse
+		}
+		employee.Name, employee.Surname, employee.Phone, employee.TimeZone = invitee.Name, invitee.Surname, invitee.Phone, invitee.TimeZone
+		// The stored password is hashed, and the rule of plain passwords would reject it
+		if err := lib.MyCustomStructExceptValidator(&employee, "Password"); err != nil {
+			return nil, nil, err
+		}
+		if err := tx.Model(&model.Employee{}).Where("id = ?", employee.ID).Updates(map[string]any{
+			"name":      employee.Name,
+			"surname":   employee.Surname,
+			"phone":     employee.Phone,
+			"time_zone": employee.TimeZone,
+		}).Error; err != nil {
+			return nil, nil, lib.Error.General.UpdatedError.WithError(err)
+		}
+		if err := revokePending(tx, employee.ID, now); err != nil {
+			return nil, nil, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// Nobody knows the password until the invitee sets theirs
+		password, err := lib.GenerateSecureToken(8)
+		if err != nil {
+			return nil, nil, lib.Error.General.InternalError.WithError(err)
+		}
+		employee = model.Employee{
+			CompanyID: companyID,
+			Name:      invitee.Name,
+			Surname:   invitee.Surname,
+			Email:     invitee.Email,
+			Phone:     invitee.Phone,
+			TimeZone:  invitee.TimeZone,
+			Password:  "Aa1!" + password[:12],
+			Status:    model.EmployeeInvited,
+		}
+		if err := tx.Omit(clause.Associations).Create(&employee).Error; err != nil {
+			return nil, nil, lib.Error.General.CreatedError.WithError(err)
+		}
+	default:
+		return nil, nil, lib.Error.General.InternalError.WithError(err)
+	}
+
+	if err := assign(tx, &employee, roles, branches, services); err != nil {
+		return nil, nil, err
+	}
+
+	invitation := model.EmployeeInvitation{
+		CompanyID:   companyID,
+		EmployeeID:  employee.ID,
+		Email:       employee.Email,
+		Status:      model.InvitationPending,
+		Language:    language(invitee.Language),
+		InvitedByID: invitedByID,
+	}
+	if err := send(tx, &invitation, baseURL, now); err != nil {
+		return nil, nil, err
+	}
+	return &employee, &invitation, nil
+}
+
+// Resend emails the pending invitation of the employee again with a new link, valid for
+// another InvitationTTL. The previous link stops working.
+func Resend(tx *gorm.DB, employeeID uuid.UUID, resentByID *uuid.UUID, baseURL string, now time.Time) (*model.EmployeeInvitation, error) {
+	invitation, err := Pending(tx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.LastSentAt != nil && now.Sub(*invitation.LastSentAt) < model.InvitationResendInterval {
+		return nil, lib.Error.General.TooManyRequests.WithError(fmt.Errorf("the invitation was sent recently; please wait before sending it again"))
+	}
+	if resentByID != nil {
+		invitation.InvitedByID = resentByID
+	}
+	if err := send(tx, invitation, baseURL, now); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// Revoke cancels the pending invitation of the employee, whose link stops working. The
+// employee stays invited, unable to log in, until invited again or deleted.
+func Revoke(tx *gorm.DB, employeeID uuid.UUID, now time.Time) (*model.EmployeeInvitation, error) {
+	invitation, err := Pending(tx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Model(invitation).Updates(map[string]any{
+		"status":     model.InvitationRevoked,
+		"token":      nil,
+		"revoked_at": now,
+	}).Error; err != nil {
+		return nil, lib.Error.General.UpdatedError.WithError(err)
+	}
+	invitation.Status = model.InvitationRevoked
+	invitation.Token = nil
+	invitation.RevokedAt = &now
+	return invitation, nil
+}
+
+// Accept sets the password of the invited employee, who becomes active and verified, as
+// the invitation proves they own the email.
+func Accept(tx *gorm.DB, invitation *model.EmployeeInvitation, password string, now time.Time) (*model.Employee, error) {
+	if invitation.Status != model.InvitationPending {
+		return nil, lib.Error.Invitation.NotFound
+	}
+	if invitation.Expired(now) {
+		return nil, lib.Error.Invitation.Expired
+	}
+	if err := lib.ValidatorV10.Var(password, "required,myPasswordValidation"); err != nil {
+		return nil, lib.Error.General.BadRequest.WithError(fmt.Errorf("password invalid"))
+	}
+
+	var employee model.Employee
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", invitation.EmployeeID).First(&employee).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, lib.Error.Employee.NotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	if employee.Status != model.EmployeeInvited {
+		return nil, lib.Error.Invitation.NotFound
+	}
+	employee.Password = password
+	if err := employee.HashPassword(); err != nil {
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	// The employee hooks forbid changing the status, it is only set here and by managers
+	if err := tx.Model(&employee).UpdateColumns(map[string]any{
+		"password": employee.Password,
+		"verified": true,
+		"status":   model.EmployeeActive,
+	}).Error; err != nil {
+		return nil, lib.Error.General.UpdatedError.WithError(err)
+	}
+	employee.Verified = true
+	employee.Status = model.EmployeeActive
+
+	if err := tx.Model(invitation).Updates(map[string]any{
+		"status":      model.InvitationAccepted,
+		"token":       nil,
+		"accepted_at": now,
+	}).Error; err != nil {
+		return nil, lib.Error.General.UpdatedError.WithError(err)
+	}
+	invitation.Status = model.InvitationAccepted
+	invitation.Token = nil
+	invitation.AcceptedAt = &now
+	return &employee, nil
+}
+
+// SetStatus suspends an active employee or reactivates a suspended one. Invited employees
+// only become active by accepting their invitation.
+func SetStatus(tx *gorm.DB, employee *model.Employee, status string) error {
+	if status != model.EmployeeActive && status != model.EmployeeSuspended {
+		return lib.Error.Employee.InvalidStatus.WithError(fmt.Errorf("status must be %s or %s", model.EmployeeActive, model.EmployeeSuspended))
+	}
+	if employee.Status == model.EmployeeInvited {
+		return lib.Error.Employee.InvitationPending
+	}
+	if employee.Status == status {
+		return nil
+	}
+	// The employee hooks forbid changing the status, it is only set here and by the invitation
+	if err := tx.Model(employee).UpdateColumn("status", status).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(err)
+	}
+	employee.Status = status
+	return nil
+}
+
+// Pending returns the pending invitation of the employee.
+func Pending(tx *gorm.DB, employeeID uuid.UUID) (*model.EmployeeInvitation, error) {
+	var invitation model.EmployeeInvitation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("employee_id = ? AND status = ?", employeeID, model.InvitationPending).
+		First(&invitation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, lib.Error.Invitation.NotPending
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	return &invitation, nil
+}
+
+// ByToken returns the pending invitation of the emailed link.
+func ByToken(tx *gorm.DB, token string) (*model.EmployeeInvitation, error) {
+	var invitation model.EmployeeInvitation
+	if err := tx.Where("token = ? AND status = ?", token, model.InvitationPending).First(&invitation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, lib.Error.Invitation.NotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	return &invitation, nil
+}
+
+// Link is the page accepting the invitation, on the frontend.
+func Link(baseURL string, companyID uuid.UUID, token, language string) string {
+	return fmt.Sprintf("%s/employee/invitation?token=%s&company_id=%s&lang=%s", baseURL, token, companyID, language)
+}
+
+// send gives the invitation a new token and expiry, saving it, and records its email in
+// the outbox.
+func send(tx *gorm.DB, invitation *model.EmployeeInvitation, baseURL string, now time.Time) error {
+	token, err := lib.GenerateSecureToken(32)
+	if err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error generating invitation token: %w", err))
+	}
+	invitation.Token = &token
+	invitation.ExpiresAt = now.Add(model.InvitationTTL)
+	invitation.SentCount++
+	invitation.LastSentAt = &now
+	if invitation.ID == uuid.Nil {
+		if err := tx.Create(invitation).Error; err != nil {
+			return lib.Error.General.CreatedError.WithError(fmt.Errorf("error saving invitation: %w", err))
+		}
+	} else if err := tx.Model(invitation).Updates(map[string]any{
+		"token":         invitation.Token,
+		"expires_at":    invitation.ExpiresAt,
+		"sent_count":    invitation.SentCount,
+		"last_sent_at":  invitation.LastSentAt,
+		"invited_by_id": invitation.InvitedByID,
+	}).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error saving invitation: %w", err))
+	}
+	return EnqueueEmail(tx, invitation, Link(baseURL, invitation.CompanyID, token, invitation.Language))
+}
+
+func revokePending(tx *gorm.DB, employeeID uuid.UUID, now time.Time) error {
+	if err := tx.Model(&model.EmployeeInvitation{}).
+		Where("employee_id = ? AND status = ?", employeeID, model.InvitationPending).
+		Updates(map[string]any{"status": model.InvitationRevoked, "token": nil, "revoked_at": now}).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error revoking previous invitation: %w", err))
+	}
+	return nil
+}
+
+// loadRoles loads the roles to grant, which must be roles of the company or system roles.
+// Ownership is never granted through an invitation.
+func loadRoles(tx *gorm.DB, companyID uuid.UUID, ids []uuid.UUID) ([]*model.Role, error) {
+	var roles []*model.Role
+	if err := loadAll(tx, &roles, "role", ids); err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.CompanyID != nil && *role.CompanyID != companyID {
+			return nil, lib.Error.Company.NotSame
+		}
+		if role.ID == model.SystemRoleOwner.ID {
+			return nil, lib.Error.Invitation.Invalid.WithError(fmt.Errorf("the owner role can not be granted through an invitation"))
+		}
+	}
+	return roles, nil
+}
+
+// loadAll loads the records with the IDs into dest, failing when one is missing.
+func loadAll(tx *gorm.DB, dest any, what string, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	unique := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+	result := tx.Where("id IN ?", ids).Find(dest)
+	if result.Error != nil {
+		return lib.Error.General.InternalError.WithError(result.Error)
+	}
+	if int(result.RowsAffected) != len(unique) {
+		return lib.Error.Invitation.Invalid.WithError(fmt.Errorf("some %s IDs do not exist", what))
+	}
+	return nil
+}
+
+// assign grants the employee the roles, branches and services it does not have yet.
+func assign(tx *gorm.DB, employee *model.Employee, roles []*model.Role, branches []*model.Branch, services []*model.Service) error {
+	for _, role := range roles {
+		var count int64
+		if err := tx.Raw("SELECT COUNT(*) FROM employee_roles WHERE employee_id = ? AND role_id = ?", employee.ID, role.ID).Scan(&count).Error; err != nil {
+			return lib.Error.General.InternalError.WithError(err)
+		}
+		if count > 0 {
+			continue
+		}
+		if err := employee.AddRole(tx, role); err != nil {
+			return err
+		}
+	}
+	for _, branch := range branches {
+		if err := employee.HasBranch(tx, branch.ID); err == nil {
+			continue
+		}
+		if err := employee.AddBranch(tx, branch); err != nil {
+			return err
+		}
+	}
+	for _, service := range services {
+		if err := employee.HasService(tx, service); err == nil {
+			continue
+		}
+		if err := employee.AddService(tx, service); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func language(l string) string {
+	switch l {
+	case "en", "pt", "es":
+		return l
+	}
+	return "en"
+}
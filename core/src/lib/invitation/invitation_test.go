@@ -0,0 +1,76 @@
+package invitation
+
+import (
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpired(t *testing.T) {
+	now := time.Date(2028, 1, 8, 9, 0, 0, 0, time.UTC)
+	pending := &model.EmployeeInvitation{Status: model.InvitationPending, ExpiresAt: now.Add(time.Minute)}
+	assert.False(t, pending.Expired(now))
+	assert.Equal(t, model.InvitationPending, pending.DisplayStatus(now))
+
+	pending.ExpiresAt = now
+	assert.True(t, pending.Expired(now), "invitations expire at ExpiresAt")
+	assert.Equal(t, "EXPIRED", pending.DisplayStatus(now))
+
+	accepted := &model.EmployeeInvitation{Status: model.InvitationAccepted, ExpiresAt: now.Add(-time.Hour)}
+	assert.False(t, accepted.Expired(now), "only pending invitations expire")
+	assert.Equal(t, model.InvitationAccepted, accepted.DisplayStatus(now))
+}
+
+func TestAcceptRejects(t *testing.T) {
+	now := time.Now()
+	for _, status := range []string{model.InvitationAccepted, model.InvitationRevoked} {
+		_, err := Accept(nil, &model.EmployeeInvitation{Status: status, ExpiresAt: now.Add(time.Hour)}, "1SecurePswd!", now)
+		assert.Equal(t, lib.Error.Invitation.NotFound, err, status)
+	}
+
+	expired := &model.EmployeeInvitation{Status: model.InvitationPending, ExpiresAt: now.Add(-time.Hour)}
+	_, err := Accept(nil, expired, "1SecurePswd!", now)
+	assert.Equal(t, lib.Error.Invitation.Expired, err)
+
+	pending := &model.EmployeeInvitation{Status: model.InvitationPending, ExpiresAt: now.Add(time.Hour)}
+	for _, password := range []string{"", "short", "nouppercase1!", "NoSpecial123"} {
+		_, err := Accept(nil, pending, password, now)
+		assert.Error(t, err, password)
+	}
+	assert.Equal(t, model.InvitationPending, pending.Status)
+}
+
+func TestSetStatusRejects(t *testing.T) {
+	err := SetStatus(nil, &model.Employee{Status: model.EmployeeActive}, model.EmployeeInvited)
+	assert.Error(t, err, "employees can not be set back to invited")
+
+	err = SetStatus(nil, &model.Employee{Status: model.EmployeeActive}, "FIRED")
+	assert.Error(t, err)
+
+	err = SetStatus(nil, &model.Employee{Status: model.EmployeeInvited}, model.EmployeeActive)
+	assert.Equal(t, lib.Error.Employee.InvitationPending, err, "invited employees only become active by accepting")
+
+	employee := &model.Employee{Status: model.EmployeeSuspended}
+	assert.NoError(t, SetStatus(nil, employee, model.EmployeeSuspended), "setting the current status changes nothing")
+}
+
+func TestCanLogin(t *testing.T) {
+	assert.NoError(t, (&model.Employee{Status: model.EmployeeActive}).CanLogin())
+	assert.Equal(t, lib.Error.Employee.InvitationPending, (&model.Employee{Status: model.EmployeeInvited}).CanLogin())
+	assert.Equal(t, lib.Error.Employee.Suspended, (&model.Employee{Status: model.EmployeeSuspended}).CanLogin())
+}
+
+func TestLink(t *testing.T) {
+	companyID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	assert.Equal(t, "https://mynute.app/employee/invitation?token=abc&company_id=00000000-0000-0000-0000-000000000001&lang=pt", Link("https://mynute.app", companyID, "abc", "pt"))
+}
+
+func TestLanguage(t *testing.T) {
+	assert.Equal(t, "es", language("es"))
+	assert.Equal(t, "en", language(""))
+	assert.Equal(t, "en", language("fr"))
+}
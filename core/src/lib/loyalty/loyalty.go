@@ -0,0 +1,249 @@
+package loyalty
+
+import (
+	"errors"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Program returns the loyalty program of the company, nil when it has none.
+func Program(tx *gorm.DB, companyID uuid.UUID) (*model.LoyaltyProgram, error) {
+	var program model.LoyaltyProgram
+	if err := tx.Where("company_id = ?", companyID).First(&program).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading loyalty program: %w", err))
+	}
+	return &program, nil
+}
+
+// Account returns the points account of the client at the company, locked for update so that
+// concurrent entries can not overdraw it. It is created when the client has none.
+func Account(tx *gorm.DB, companyID, clientID uuid.UUID) (*model.LoyaltyAccount, error) {
+	account := model.LoyaltyAccount{CompanyID: companyID, ClientID: clientID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&account).Error; err != nil {
+		return nil, lib.Error.General.CreatedError.WithError(fmt.Errorf("error creating loyalty account: %w", err))
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("company_id = ? AND client_id = ?", companyID, clientID).
+		First(&account).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("error loading loyalty account: %w", err))
+	}
+	return &account, nil
+}
+
+// Post records the entry in the ledger of the locked account and moves its balance.
+func Post(tx *gorm.DB, account *model.LoyaltyAccount, entry *model.LoyaltyTransaction) error {
+	account.Balance += entry.Points
+	switch entry.Type {
+	case model.LoyaltyEarn, model.LoyaltyReversal:
+		account.Earned += entry.Points
+	case model.LoyaltyRedeem, model.LoyaltyRestore:
+		account.Redeemed -= entry.Points
+	}
+	entry.AccountID = account.ID
+	entry.CompanyID = account.CompanyID
+	entry.ClientID = account.ClientID
+	entry.BalanceAfter = account.Balance
+	if err := tx.Create(entry).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(fmt.Errorf("error saving loyalty transaction: %w", err))
+	}
+	if err := tx.Model(account).UpdateColumns(map[string]any{
+		"balance":  account.Balance,
+		"earned":   account.Earned,
+		"redeemed": account.Redeemed,
+	}).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error updating loyalty balance: %w", err))
+	}
+	return nil
+}
+
+// Earn credits the client with the points the fulfilled appointment earns under the program
+// of the company. Appointments paid with a package credit only earn the points per appointment.
+// Points are only earned once per appointment.
+func Earn(tx *gorm.DB, appointment *model.Appointment) error {
+	program, err := Program(tx, appointment.CompanyID)
+	if err != nil || program == nil || !program.IsActive {
+		return err
+	}
+	earning, ok := program.EarningFor(appointment.ServiceID)
+	if !ok {
+		return nil
+	}
+	earned, err := pointsOf(tx, appointment.ID, model.LoyaltyEarn)
+	if err != nil || earned != 0 {
+		return err
+	}
+	var amount int64
+	if appointment.ClientPackageID == nil {
+		amount = appointment.Price - appointment.Discount - appointment.LoyaltyDiscount
+	}
+	points := earning.Points(amount)
+	if points <= 0 {
+		return nil
+	}
+	account, err := Account(tx, appointment.CompanyID, appointment.ClientID)
+	if err != nil {
+		return err
+	}
+	return Post(tx, account, &model.LoyaltyTransaction{
+		Type:          model.LoyaltyEarn,
+		Points:        points,
+		AppointmentID: &appointment.ID,
+		Description:   "Fulfilled appointment",
+	})
+}
+
+// Redeem pays part of the freshly created appointment with points of the client, within the
+// booking transaction. Only the points the program allows on the price left after the promo
+// code discount are used, the others stay in the balance.
+func Redeem(tx *gorm.DB, appointment *model.Appointment, requested int64) error {
+	if requested == 0 {
+		return nil
+	}
+	if requested < 0 {
+		return lib.Error.General.BadRequest.WithError(fmt.Errorf("redeem_points must be positive"))
+	}
+	program, err := Program(tx, appointment.CompanyID)
+	if err != nil {
+		return err
+	}
+	if program == nil {
+		return lib.Error.Loyalty.NotFound
+	}
+	if !program.IsActive {
+		return lib.Error.Loyalty.Inactive
+	}
+	account, err := Account(tx, appointment.CompanyID, appointment.ClientID)
+	if err != nil {
+		return err
+	}
+	if account.Balance < requested {
+		return lib.Error.Loyalty.InsufficientPoints.WithError(fmt.Errorf("the balance is %d points", account.Balance))
+	}
+	points, discount := program.RedeemablePoints(requested, appointment.Price-appointment.Discount)
+	if points <= 0 || points < program.MinRedeemPoints {
+		return lib.Error.Loyalty.BelowMinimum.WithError(fmt.Errorf("at least %d points must be redeemed at once", max(program.MinRedeemPoints, 1)))
+	}
+
+	if err := Post(tx, account, &model.LoyaltyTransaction{
+		Type:          model.LoyaltyRedeem,
+		Points:        -points,
+		AppointmentID: &appointment.ID,
+		Description:   "Redeemed when booking",
+	}); err != nil {
+		return err
+	}
+	appointment.LoyaltyDiscount = discount
+	// The appointment hooks forbid changing the loyalty discount, it is only set here
+	if err := tx.Model(appointment).UpdateColumn("loyalty_discount", discount).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error applying loyalty discount: %w", err))
+	}
+	return nil
+}
+
+// ReverseOnCancel takes back the points earned on the cancelled appointment, and gives back
+// the points redeemed on it when the cancellation is within the free cancellation window of
+// the service, as package credits. Late cancellations lose the redeemed points.
+func ReverseOnCancel(tx *gorm.DB, appointment *model.Appointment) error {
+	if err := ReverseEarned(tx, appointment.ID, 1, 1); err != nil {
+		return err
+	}
+	redeemed, err := pointsOf(tx, appointment.ID, model.LoyaltyRedeem)
+	if err != nil || redeemed == 0 {
+		return err
+	}
+	restored, err := pointsOf(tx, appointment.ID, model.LoyaltyRestore)
+	if err != nil {
+		return err
+	}
+	due := -redeemed - restored
+	if due <= 0 {
+		return nil
+	}
+	var service model.Service
+	if err := tx.Where("id = ?", appointment.ServiceID).First(&service).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("loading service: %w", err))
+	}
+	cancelledAt := appointment.CancelTime
+	if cancelledAt.IsZero() {
+		cancelledAt = time.Now()
+	}
+	if !service.FreeCancellation(appointment.StartTime, cancelledAt) {
+		return nil
+	}
+	account, err := Account(tx, appointment.CompanyID, appointment.ClientID)
+	if err != nil {
+		return err
+	}
+	return Post(tx, account, &model.LoyaltyTransaction{
+		Type:          model.LoyaltyRestore,
+		Points:        due,
+		AppointmentID: &appointment.ID,
+		Description:   "Appointment cancelled",
+	})
+}
+
+// ReverseEarned takes back the points earned on the appointment in proportion to the share
+// of its payment refunded, refunded out of total. Earlier reversals are accounted for, so it
+// can be called again on every refund of the payment.
+func ReverseEarned(tx *gorm.DB, appointmentID uuid.UUID, refunded, total int64) error {
+	var earn model.LoyaltyTransaction
+	if err := tx.Where("appointment_id = ? AND type = ?", appointmentID, model.LoyaltyEarn).First(&earn).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading earned points: %w", err))
+	}
+	reversed, err := pointsOf(tx, appointmentID, model.LoyaltyReversal)
+	if err != nil {
+		return err
+	}
+	due := ReversalDue(earn.Points, -reversed, refunded, total)
+	if due <= 0 {
+		return nil
+	}
+	account, err := Account(tx, earn.CompanyID, earn.ClientID)
+	if err != nil {
+		return err
+	}
+	description := "Appointment refunded"
+	if refunded >= total {
+		description = "Appointment cancelled or refunded"
+	}
+	return Post(tx, account, &model.LoyaltyTransaction{
+		Type:          model.LoyaltyReversal,
+		Points:        -due,
+		AppointmentID: &appointmentID,
+		Description:   description,
+	})
+}
+
+// ReversalDue returns the points still to take back of the earned ones, when refunded out of
+// total was refunded and reversed points were already taken back.
+func ReversalDue(earned, reversed, refunded, total int64) int64 {
+	target := earned
+	if total > 0 && refunded < total {
+		target = earned * max(refunded, 0) / total
+	}
+	return max(target-reversed, 0)
+}
+
+// pointsOf sums the entries of a type recorded for the appointment.
+func pointsOf(tx *gorm.DB, appointmentID uuid.UUID, entryType string) (int64, error) {
+	var points int64
+	if err := tx.Model(&model.LoyaltyTransaction{}).
+		Where("appointment_id = ? AND type = ?", appointmentID, entryType).
+		Select("COALESCE(SUM(points), 0)").
+		Scan(&points).Error; err != nil {
+		return 0, lib.Error.General.InternalError.WithError(fmt.Errorf("error summing loyalty points: %w", err))
+	}
+	return points, nil
+}
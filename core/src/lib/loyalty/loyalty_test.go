@@ -0,0 +1,104 @@
+package loyalty
+
+import (
+	"mynute-go/core/src/config/db/model"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func program() *model.LoyaltyProgram {
+	return &model.LoyaltyProgram{
+		CompanyID:        uuid.New(),
+		IsActive:         true,
+		LoyaltyEarning:   model.LoyaltyEarning{PointsPerAppointment: 10, PointsPerAmount: 1, AmountUnit: 1000},
+		PointValue:       5,
+		MaxRedeemPercent: 50,
+	}
+}
+
+func TestPoints(t *testing.T) {
+	earning := model.LoyaltyEarning{PointsPerAppointment: 10, PointsPerAmount: 2, AmountUnit: 1000}
+	assert.Equal(t, int64(10), earning.Points(0), "package credits only earn the points per appointment")
+	assert.Equal(t, int64(10), earning.Points(999), "partial units earn nothing")
+	assert.Equal(t, int64(18), earning.Points(4500))
+	assert.Equal(t, int64(0), model.LoyaltyEarning{}.Points(5000))
+}
+
+func TestEarningFor(t *testing.T) {
+	p := program()
+	excluded, custom := uuid.New(), uuid.New()
+	p.Rules = model.LoyaltyRules{
+		{ServiceID: excluded, Excluded: true},
+		{ServiceID: custom, LoyaltyEarning: model.LoyaltyEarning{PointsPerAppointment: 50}},
+	}
+
+	_, ok := p.EarningFor(excluded)
+	assert.False(t, ok)
+	earning, ok := p.EarningFor(custom)
+	assert.True(t, ok)
+	assert.Equal(t, int64(50), earning.Points(10000))
+	earning, ok = p.EarningFor(uuid.New())
+	assert.True(t, ok)
+	assert.Equal(t, p.LoyaltyEarning, earning)
+}
+
+func TestRedeemablePoints(t *testing.T) {
+	p := program()
+	points, discount := p.RedeemablePoints(100, 10000)
+	assert.Equal(t, int64(100), points)
+	assert.Equal(t, int64(500), discount)
+
+	// At most half of 5000 cents, worth 500 points
+	points, discount = p.RedeemablePoints(2000, 5000)
+	assert.Equal(t, int64(500), points)
+	assert.Equal(t, int64(2500), discount)
+
+	points, _ = p.RedeemablePoints(100, 0)
+	assert.Equal(t, int64(0), points, "free appointments can not be paid with points")
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, program().Validate())
+
+	service := uuid.New()
+	cases := map[string]func(p *model.LoyaltyProgram){
+		"no company":          func(p *model.LoyaltyProgram) { p.CompanyID = uuid.Nil },
+		"negative points":     func(p *model.LoyaltyProgram) { p.PointsPerAppointment = -1 },
+		"no amount unit":      func(p *model.LoyaltyProgram) { p.AmountUnit = 0 },
+		"no point value":      func(p *model.LoyaltyProgram) { p.PointValue = 0 },
+		"negative minimum":    func(p *model.LoyaltyProgram) { p.MinRedeemPoints = -1 },
+		"no redeem percent":   func(p *model.LoyaltyProgram) { p.MaxRedeemPercent = 0 },
+		"redeem percent >100": func(p *model.LoyaltyProgram) { p.MaxRedeemPercent = 101 },
+		"rule without service": func(p *model.LoyaltyProgram) {
+			p.Rules = model.LoyaltyRules{{}}
+		},
+		"duplicate rule": func(p *model.LoyaltyProgram) {
+			p.Rules = model.LoyaltyRules{{ServiceID: service}, {ServiceID: service, Excluded: true}}
+		},
+		"invalid rule earning": func(p *model.LoyaltyProgram) {
+			p.Rules = model.LoyaltyRules{{ServiceID: service, LoyaltyEarning: model.LoyaltyEarning{PointsPerAmount: 1}}}
+		},
+	}
+	for name, change := range cases {
+		p := program()
+		change(p)
+		assert.Error(t, p.Validate(), name)
+	}
+}
+
+func TestReversalDue(t *testing.T) {
+	assert.Equal(t, int64(40), ReversalDue(40, 0, 1, 1), "cancellations take back every point")
+	assert.Equal(t, int64(10), ReversalDue(40, 0, 2500, 10000))
+	assert.Equal(t, int64(10), ReversalDue(40, 10, 5000, 10000), "earlier reversals are accounted for")
+	assert.Equal(t, int64(0), ReversalDue(40, 20, 5000, 10000), "refunding the same share twice takes nothing more")
+	assert.Equal(t, int64(30), ReversalDue(40, 10, 12000, 10000), "refunds above the payment take back the rest")
+	assert.Equal(t, int64(0), ReversalDue(40, 40, 10000, 10000))
+}
+
+func TestRedeemRejectsNegative(t *testing.T) {
+	err := Redeem(nil, &model.Appointment{}, -5)
+	assert.Error(t, err)
+	assert.NoError(t, Redeem(nil, &model.Appointment{}, 0))
+}
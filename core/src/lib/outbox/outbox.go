@@ -0,0 +1,213 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// MaxAttempts is how many times a message is tried before it is dead-lettered.
+	MaxAttempts = 10
+	// BaseDelay is the wait before the first retry. Each following retry doubles it.
+	BaseDelay = 15 * time.Second
+	// MaxDelay caps the wait between two attempts.
+	MaxDelay = time.Hour
+	// claimLease is how long a message being handled is hidden from other workers.
+	// If the process dies mid-handling, the message becomes available again once it expires.
+	claimLease = 5 * time.Minute
+)
+
+// Handler delivers one message. It runs inside a transaction that also marks the
+// message as processed, so anything it writes commits only when it succeeds.
+// When the message has a company, tx is already pointed at the company schema.
+type Handler func(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error
+
+var (
+	handlersMu sync.RWMutex
+	handlers   = map[string]Handler{}
+)
+
+// Register sets the handler for a topic, replacing any previous one.
+func Register(topic string, handler Handler) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	handlers[topic] = handler
+}
+
+func handlerFor(topic string) (Handler, bool) {
+	handlersMu.RLock()
+	defer handlersMu.RUnlock()
+	h, ok := handlers[topic]
+	return h, ok
+}
+
+// Enqueue records a message in the given transaction. Nothing is sent until the
+// transaction commits and the worker picks the message up.
+func Enqueue(tx *gorm.DB, companyID *uuid.UUID, topic string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("failed to marshal outbox payload: %w", err))
+	}
+	msg := &model.OutboxMessage{
+		CompanyID:   companyID,
+		Topic:       topic,
+		Payload:     datatypes.JSON(data),
+		Status:      model.OutboxPending,
+		AvailableAt: time.Now().UTC(),
+	}
+	if err := tx.Create(msg).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(fmt.Errorf("failed to enqueue outbox message: %w", err))
+	}
+	return nil
+}
+
+// Decode unmarshals the message payload into v.
+func Decode(msg *model.OutboxMessage, v any) error {
+	if err := json.Unmarshal(msg.Payload, v); err != nil {
+		return fmt.Errorf("failed to decode %s outbox payload: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// Backoff returns how long to wait after the given number of failed attempts.
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		return 0
+	}
+	delay := BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= MaxDelay {
+			return MaxDelay
+		}
+	}
+	return delay
+}
+
+// Drain claims up to limit due messages and handles them one by one.
+// It returns how many messages were claimed.
+func Drain(ctx context.Context, db *gorm.DB, limit int) (int, error) {
+	var due []*model.OutboxMessage
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND available_at <= ?", model.OutboxPending, now).
+			Order("available_at").
+			Limit(limit).
+			Find(&due).Error; err != nil {
+			return err
+		}
+		if len(due) == 0 {
+			return nil
+		}
+		ids := make([]uuid.UUID, len(due))
+		for i, m := range due {
+			ids[i] = m.ID
+		}
+		return tx.Model(&model.OutboxMessage{}).
+			Where("id IN ?", ids).
+			Update("available_at", now.Add(claimLease)).Error
+	}); err != nil {
+		return 0, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+	for _, msg := range due {
+		if err := process(ctx, db, msg); err != nil {
+			log.Printf("Outbox message %s (%s) failed: %v", msg.ID, msg.Topic, err)
+		}
+	}
+	return len(due), nil
+}
+
+// process runs the handler and marks the message as processed in one transaction.
+// When the handler fails its writes are rolled back and the failure is recorded apart.
+func process(ctx context.Context, db *gorm.DB, msg *model.OutboxMessage) error {
+	handler, ok := handlerFor(msg.Topic)
+	if !ok {
+		return fail(ctx, db, msg, fmt.Errorf("no handler registered for topic %s", msg.Topic))
+	}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if msg.CompanyID != nil {
+			var schemaName string
+			if err := tx.Model(&model.Company{}).Where("id = ?", *msg.CompanyID).Pluck("schema_name", &schemaName).Error; err != nil {
+				return err
+			}
+			if err := lib.ChangeToCompanySchema(tx, schemaName); err != nil {
+				return err
+			}
+		}
+		if err := handler(ctx, tx, msg); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		return tx.Model(&model.OutboxMessage{}).Where("id = ?", msg.ID).Updates(map[string]any{
+			"status":       model.OutboxProcessed,
+			"attempts":     msg.Attempts + 1,
+			"last_error":   "",
+			"processed_at": now,
+		}).Error
+	})
+	if err != nil {
+		return fail(ctx, db, msg, err)
+	}
+	return nil
+}
+
+// fail records a failed attempt and schedules a retry, or dead-letters the
+// message once MaxAttempts is reached.
+func fail(ctx context.Context, db *gorm.DB, msg *model.OutboxMessage, cause error) error {
+	msg.Attempts++
+	msg.LastError = cause.Error()
+	updates := map[string]any{
+		"attempts":   msg.Attempts,
+		"last_error": msg.LastError,
+	}
+	if msg.Attempts >= MaxAttempts {
+		msg.Status = model.OutboxDead
+		updates["status"] = model.OutboxDead
+	} else {
+		updates["available_at"] = time.Now().UTC().Add(Backoff(msg.Attempts))
+	}
+	if err := db.WithContext(ctx).Model(&model.OutboxMessage{}).Where("id = ?", msg.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("%w (and failed to record the attempt: %v)", cause, err)
+	}
+	return cause
+}
+
+// StartWorker drains the outbox every interval until the returned stop function is called.
+func StartWorker(db *gorm.DB, interval time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// Keep draining while full batches come back so bursts do not wait a tick each.
+				for {
+					n, err := Drain(ctx, db, 100)
+					if err != nil {
+						log.Printf("Outbox worker failed: %v", err)
+						break
+					}
+					if n < 100 || ctx.Err() != nil {
+						break
+					}
+				}
+			}
+		}
+	}()
+	return cancel
+}
@@ -0,0 +1,233 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"mynute-go/core/src/config/db/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Duration(0), Backoff(0))
+	assert.Equal(t, BaseDelay, Backoff(1))
+	assert.Equal(t, 2*BaseDelay, Backoff(2))
+	assert.Equal(t, MaxDelay, Backoff(MaxAttempts*4))
+
+	for attempt := 1; attempt < MaxAttempts; attempt++ {
+		assert.LessOrEqual(t, Backoff(attempt), Backoff(attempt+1))
+	}
+}
+
+func TestRegister(t *testing.T) {
+	_, ok := handlerFor("test.unknown")
+	assert.False(t, ok)
+
+	called := false
+	Register("test.topic", func(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+		called = true
+		return nil
+	})
+	h, ok := handlerFor("test.topic")
+	assert.True(t, ok)
+	assert.NoError(t, h(context.Background(), nil, &model.OutboxMessage{}))
+	assert.True(t, called)
+}
+
+func TestDecode(t *testing.T) {
+	var payload struct {
+		Name string `json:"name"`
+	}
+	msg := &model.OutboxMessage{Topic: "test.topic", Payload: datatypes.JSON(`{"name":"mynute"}`)}
+	assert.NoError(t, Decode(msg, &payload))
+	assert.Equal(t, "mynute", payload.Name)
+
+	msg.Payload = datatypes.JSON(`not json`)
+	assert.Error(t, Decode(msg, &payload))
+}
+
+// testDB opens an in memory database with the outbox table, and a notes table
+// handlers write to. SQLite ignores the row locks taken by Drain.
+func testDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// Every connection opens its own in memory database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	require.NoError(t, db.Exec("ATTACH DATABASE ':memory:' AS public").Error)
+	require.NoError(t, db.Exec(`CREATE TABLE public.outbox_messages (
+		id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-a' || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME,
+		company_id TEXT,
+		topic TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'PENDING',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT,
+		available_at DATETIME NOT NULL,
+		processed_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec("CREATE TABLE public.notes (body TEXT NOT NULL)").Error)
+	return db
+}
+
+func enqueue(t *testing.T, db *gorm.DB, topic string) *model.OutboxMessage {
+	require.NoError(t, Enqueue(db, nil, topic, map[string]string{"note": topic}))
+	var msg model.OutboxMessage
+	require.NoError(t, db.Where("topic = ?", topic).Order("created_at DESC").First(&msg).Error)
+	return &msg
+}
+
+func reload(t *testing.T, db *gorm.DB, msg *model.OutboxMessage) *model.OutboxMessage {
+	var fresh model.OutboxMessage
+	require.NoError(t, db.Where("id = ?", msg.ID).First(&fresh).Error)
+	return &fresh
+}
+
+func notes(t *testing.T, db *gorm.DB) int64 {
+	var n int64
+	require.NoError(t, db.Table("public.notes").Count(&n).Error)
+	return n
+}
+
+// writeNote is a handler that writes a note and then fails with err, if any.
+func writeNote(err error) Handler {
+	return func(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+		if e := tx.Exec("INSERT INTO public.notes (body) VALUES (?)", msg.Topic).Error; e != nil {
+			return e
+		}
+		return err
+	}
+}
+
+func TestDrain(t *testing.T) {
+	db := testDB(t)
+	Register("test.drain", writeNote(nil))
+
+	first := enqueue(t, db, "test.drain")
+	second := enqueue(t, db, "test.drain")
+	later := enqueue(t, db, "test.drain")
+	require.NoError(t, db.Model(&model.OutboxMessage{}).Where("id = ?", later.ID).Update("available_at", time.Now().UTC().Add(time.Hour)).Error)
+
+	n, err := Drain(context.Background(), db, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n, "messages not due yet are left alone")
+	assert.Equal(t, int64(2), notes(t, db))
+	for _, msg := range []*model.OutboxMessage{first, second} {
+		msg = reload(t, db, msg)
+		assert.Equal(t, model.OutboxProcessed, msg.Status)
+		assert.Equal(t, 1, msg.Attempts)
+		assert.NotNil(t, msg.ProcessedAt)
+	}
+	assert.Equal(t, model.OutboxPending, reload(t, db, later).Status)
+
+	n, err = Drain(context.Background(), db, 10)
+	require.NoError(t, err)
+	assert.Zero(t, n, "processed messages are not claimed again")
+}
+
+func TestDrainLimit(t *testing.T) {
+	db := testDB(t)
+	Register("test.limit", writeNote(nil))
+	for range 3 {
+		enqueue(t, db, "test.limit")
+	}
+
+	n, err := Drain(context.Background(), db, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	n, err = Drain(context.Background(), db, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestProcessFailureRollsBack(t *testing.T) {
+	db := testDB(t)
+	msg := enqueue(t, db, "test.failing")
+	handlerErr := errors.New("provider unavailable")
+	Register("test.failing", writeNote(handlerErr))
+
+	before := time.Now().UTC()
+	err := process(context.Background(), db, msg)
+	assert.ErrorIs(t, err, handlerErr)
+
+	assert.Zero(t, notes(t, db), "the writes of a failing handler are rolled back")
+	msg = reload(t, db, msg)
+	assert.Equal(t, model.OutboxPending, msg.Status)
+	assert.Equal(t, 1, msg.Attempts)
+	assert.Equal(t, handlerErr.Error(), msg.LastError)
+	assert.Nil(t, msg.ProcessedAt)
+	assert.WithinRange(t, msg.AvailableAt, before.Add(Backoff(1)), time.Now().UTC().Add(Backoff(1)), "the retry waits for the backoff")
+}
+
+func TestProcessSuccessCommits(t *testing.T) {
+	db := testDB(t)
+	msg := enqueue(t, db, "test.ok")
+	Register("test.ok", writeNote(nil))
+	msg.Attempts = 2
+	msg.LastError = "previous failure"
+
+	require.NoError(t, process(context.Background(), db, msg))
+	assert.Equal(t, int64(1), notes(t, db))
+	msg = reload(t, db, msg)
+	assert.Equal(t, model.OutboxProcessed, msg.Status)
+	assert.Equal(t, 3, msg.Attempts)
+	assert.Empty(t, msg.LastError)
+}
+
+func TestProcessUnknownTopic(t *testing.T) {
+	db := testDB(t)
+	msg := enqueue(t, db, "test.unregistered")
+
+	assert.Error(t, process(context.Background(), db, msg))
+	msg = reload(t, db, msg)
+	assert.Equal(t, model.OutboxPending, msg.Status)
+	assert.Equal(t, 1, msg.Attempts)
+	assert.Contains(t, msg.LastError, "no handler registered")
+}
+
+func TestFail(t *testing.T) {
+	db := testDB(t)
+	cause := errors.New("boom")
+
+	t.Run("should schedule the retry after the backoff of the attempt", func(t *testing.T) {
+		msg := enqueue(t, db, "test.retry")
+		msg.Attempts = 3
+		before := time.Now().UTC()
+
+		assert.ErrorIs(t, fail(context.Background(), db, msg, cause), cause)
+		msg = reload(t, db, msg)
+		assert.Equal(t, 4, msg.Attempts)
+		assert.Equal(t, model.OutboxPending, msg.Status)
+		assert.WithinRange(t, msg.AvailableAt, before.Add(Backoff(4)), time.Now().UTC().Add(Backoff(4)))
+	})
+
+	t.Run("should dead-letter the message on the last attempt", func(t *testing.T) {
+		msg := enqueue(t, db, "test.dead")
+		msg.Attempts = MaxAttempts - 1
+		availableAt := reload(t, db, msg).AvailableAt
+
+		assert.ErrorIs(t, fail(context.Background(), db, msg, cause), cause)
+		msg = reload(t, db, msg)
+		assert.Equal(t, MaxAttempts, msg.Attempts)
+		assert.Equal(t, model.OutboxDead, msg.Status)
+		assert.Equal(t, cause.Error(), msg.LastError)
+		assert.True(t, availableAt.Equal(msg.AvailableAt), "dead messages are not rescheduled")
+
+		n, err := Drain(context.Background(), db, 10)
+		require.NoError(t, err)
+		assert.Zero(t, n, "dead messages are not claimed")
+	})
+}
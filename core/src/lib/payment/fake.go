@@ -0,0 +1,127 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FakeProviderName = "fake"
+	// FakeSignatureHeader carries the hex HMAC-SHA256 of the webhook body.
+	FakeSignatureHeader = "X-Fake-Signature"
+	// Tokens changing the fake provider behavior, any other token is authorized.
+	FakeTokenDeclined = "tok_declined"
+	FakeTokenPending  = "tok_pending"
+)
+
+// Fake is a local provider for development and tests. It never moves money:
+// charges are authorized unless a special token is used and webhooks are signed
+// with a shared secret. What would have been charged is kept per token, see FakeCharged.
+type Fake struct {
+	secret string
+}
+
+// fakeLedger keeps the amount captured and not refunded for each card token.
+var fakeLedger = struct {
+	sync.Mutex
+	tokens  map[string]string // transaction id -> token
+	charged map[string]int64  // token -> amount
+}{tokens: map[string]string{}, charged: map[string]int64{}}
+
+// FakeCharged returns the amount captured and not refunded on token by the fake provider.
+func FakeCharged(token string) int64 {
+	fakeLedger.Lock()
+	defer fakeLedger.Unlock()
+	return fakeLedger.charged[token]
+}
+
+func fakeRecord(transactionID string, amount int64) {
+	fakeLedger.Lock()
+	defer fakeLedger.Unlock()
+	if token, ok := fakeLedger.tokens[transactionID]; ok {
+		fakeLedger.charged[token] += amount
+	}
+}
+
+func NewFake(secret string) *Fake {
+	if secret == "" {
+		secret = "fake-payment-secret"
+	}
+	return &Fake{secret: secret}
+}
+
+func (f *Fake) Name() string { return FakeProviderName }
+
+func (f *Fake) Charge(ctx context.Context, req ChargeRequest) (*ChargeResult, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("charge amount must be positive")
+	}
+	result := &ChargeResult{TransactionID: "fake_" + uuid.NewString()}
+	switch req.Token {
+	case FakeTokenDeclined:
+		result.Status = ChargeDeclined
+		result.Reason = "card declined"
+	case FakeTokenPending:
+		result.Status = ChargePending
+	default:
+		result.Status = ChargeAuthorized
+	}
+	fakeLedger.Lock()
+	fakeLedger.tokens[result.TransactionID] = req.Token
+	fakeLedger.Unlock()
+	return result, nil
+}
+
+func (f *Fake) Capture(ctx context.Context, transactionID string, amount int64) error {
+	if transactionID == "" {
+		return fmt.Errorf("missing transaction id")
+	}
+	fakeRecord(transactionID, amount)
+	return nil
+}
+
+func (f *Fake) Refund(ctx context.Context, transactionID string, amount int64, idempotencyKey string) error {
+	if transactionID == "" {
+		return fmt.Errorf("missing transaction id")
+	}
+	if amount <= 0 {
+		return fmt.Errorf("refund amount must be positive")
+	}
+	fakeRecord(transactionID, -amount)
+	return nil
+}
+
+// Sign returns the signature the fake provider puts on a webhook body.
+func (f *Fake) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(f.secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (f *Fake) VerifyWebhook(header http.Header, body []byte) ([]Notification, error) {
+	signature, err := hex.DecodeString(header.Get(FakeSignatureHeader))
+	if err != nil || len(signature) == 0 {
+		return nil, errors.New("missing or malformed signature")
+	}
+	expected, _ := hex.DecodeString(f.Sign(body))
+	if !hmac.Equal(signature, expected) {
+		return nil, errors.New("signature mismatch")
+	}
+	var n Notification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, fmt.Errorf("invalid notification body: %w", err)
+	}
+	if n.TransactionID == "" {
+		return nil, errors.New("notification without transaction id")
+	}
+	return []Notification{n}, nil
+}
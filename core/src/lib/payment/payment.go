@@ -0,0 +1,256 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	database "mynute-go/core/src/config/db"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/lib/loyalty"
+	"mynute-go/core/src/lib/outbox"
+	"mynute-go/core/src/lib/receipt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Collect charges the prepayment required by the service when booking the appointment
+// and links the payment to it. It returns nil when the service has no prepayment.
+// req only needs the payment method (Method, Token or Pix), the rest comes from the service.
+// A declined charge fails, so the booking transaction is rolled back.
+// A captured charge is refunded if the booking transaction does not commit.
+func Collect(ctx context.Context, tx *gorm.DB, provider Provider, appointment *model.Appointment, service *model.Service, req ChargeRequest) (*model.Payment, error) {
+	amount, paymentType, ok := service.PrepaymentAmount()
+	if !ok {
+		return nil, nil
+	}
+	if req.Token == "" && req.Method != PixMethod {
+		return nil, lib.Error.Payment.MissingMethod
+	}
+
+	req.Amount = amount
+	req.Currency = service.Currency
+	req.Description = service.Name
+	req.Reference = appointment.ID.String()
+	result, err := provider.Charge(ctx, req)
+	if err != nil {
+		var appErr lib.ErrorStruct
+		if errors.As(err, &appErr) {
+			return nil, err
+		}
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("payment provider charge failed: %w", err))
+	}
+	if result.Status == ChargeDeclined {
+		return nil, lib.Error.Payment.Declined.WithError(errors.New(result.Reason))
+	}
+
+	payment := &model.Payment{
+		Price:         amount,
+		Currency:      service.Currency,
+		Status:        model.StatusPending,
+		Type:          paymentType,
+		PaymentMethod: req.Method,
+		TransactionID: &result.TransactionID,
+		PixPayload:    result.PixPayload,
+		Provider:      provider.Name(),
+		CompanyID:     appointment.CompanyID,
+		ClientID:      appointment.ClientID,
+		AppointmentID: &appointment.ID,
+	}
+	if result.Status == ChargeAuthorized {
+		if err := provider.Capture(ctx, result.TransactionID, amount); err != nil {
+			return nil, lib.Error.Payment.Declined.WithError(fmt.Errorf("capture failed: %w", err))
+		}
+		if err := payment.MoveTo(model.StatusCompleted, time.Now()); err != nil {
+			return nil, err
+		}
+		transactionID := result.TransactionID
+		database.AfterRollback(tx, func() {
+			voidCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+			defer cancel()
+			if err := provider.Refund(voidCtx, transactionID, amount, "void_"+req.Reference); err != nil {
+				log.Printf("payment: failed to refund charge %s of a booking rolled back: %v", transactionID, err)
+			}
+		})
+	}
+
+	if err := tx.Create(payment).Error; err != nil {
+		return nil, lib.Error.General.CreatedError.WithError(err)
+	}
+	if err := tx.Model(appointment).UpdateColumn("payment_id", payment.ID).Error; err != nil {
+		return nil, lib.Error.General.UpdatedError.WithError(err)
+	}
+	appointment.PaymentID = &payment.ID
+	return payment, nil
+}
+
+// OfflineProviderName marks payments the company received by itself, e.g. cash or a card machine at the counter.
+const OfflineProviderName = "offline"
+
+// RecordOffline records a payment the company already received outside of any provider.
+// It is stored as completed and has no transaction, so it is never refunded through a provider.
+func RecordOffline(tx *gorm.DB, companyID, clientID uuid.UUID, amount int64, currency, method string) (*model.Payment, error) {
+	if method == "" {
+		return nil, lib.Error.General.BadRequest.WithError(fmt.Errorf("payment_method is required"))
+	}
+	payment := &model.Payment{
+		Price:         amount,
+		Currency:      currency,
+		Status:        model.StatusPending,
+		Type:          model.PaymentTypeFull,
+		PaymentMethod: method,
+		Provider:      OfflineProviderName,
+		CompanyID:     companyID,
+		ClientID:      clientID,
+	}
+	if err := payment.MoveTo(model.StatusCompleted, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := tx.Create(payment).Error; err != nil {
+		return nil, lib.Error.General.CreatedError.WithError(err)
+	}
+	return payment, nil
+}
+
+// RefundJob is the outbox payload of a refund.
+type RefundJob struct {
+	PaymentID uuid.UUID `json:"payment_id"`
+	Amount    int64     `json:"amount"`
+}
+
+// ScheduleRefund records a refund of amount in the outbox. It is sent to the provider after commit.
+func ScheduleRefund(tx *gorm.DB, payment *model.Payment, amount int64) error {
+	return outbox.Enqueue(tx, &payment.CompanyID, model.OutboxTopicPaymentRefund, RefundJob{PaymentID: payment.ID, Amount: amount})
+}
+
+// RefundOnCancel schedules the refund the service cancellation policy grants for
+// a cancelled appointment. Payments not completed yet are refunded once they complete.
+func RefundOnCancel(tx *gorm.DB, appointment *model.Appointment) error {
+	if appointment.PaymentID == nil {
+		return nil
+	}
+	var payment model.Payment
+	if err := tx.Where("id = ?", *appointment.PaymentID).First(&payment).Error; err != nil {
+		return lib.Error.Payment.NotFound.WithError(err)
+	}
+	if payment.Status != model.StatusCompleted {
+		return nil
+	}
+	var service model.Service
+	if err := tx.Where("id = ?", appointment.ServiceID).First(&service).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("loading service: %w", err))
+	}
+	amount := service.RefundAmount(payment.Price-payment.RefundedAmount, appointment.StartTime, appointment.CancelTime)
+	if amount <= 0 {
+		return nil
+	}
+	return ScheduleRefund(tx, &payment, amount)
+}
+
+// HandleRefund is the outbox handler for model.OutboxTopicPaymentRefund.
+func HandleRefund(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+	var job RefundJob
+	if err := outbox.Decode(msg, &job); err != nil {
+		return err
+	}
+	var payment model.Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", job.PaymentID).First(&payment).Error; err != nil {
+		return fmt.Errorf("failed to load payment %s: %w", job.PaymentID, err)
+	}
+	amount := min(job.Amount, payment.Price-payment.RefundedAmount)
+	if amount <= 0 || payment.TransactionID == nil {
+		return nil
+	}
+	provider, err := NewProvider(payment.Provider)
+	if err != nil {
+		return err
+	}
+	if err := provider.Refund(ctx, *payment.TransactionID, amount, msg.ID.String()); err != nil {
+		if errors.Is(err, ErrRefundNotSupported) {
+			// The company refunds it through its bank, the provider webhook then marks it refunded
+			log.Printf("payment %s: refund of %d must be made by the company through %s", payment.ID, amount, payment.Provider)
+			return nil
+		}
+		return fmt.Errorf("provider refund failed: %w", err)
+	}
+	if err := payment.RecordRefund(payment.RefundedAmount+amount, time.Now()); err != nil {
+		return err
+	}
+	if err := tx.Save(&payment).Error; err != nil {
+		return err
+	}
+	if payment.AppointmentID != nil {
+		return loyalty.ReverseEarned(tx, *payment.AppointmentID, payment.RefundedAmount, payment.Price)
+	}
+	return nil
+}
+
+// ApplyNotification moves the payment to the status reported by the provider.
+func ApplyNotification(tx *gorm.DB, n *Notification) (*model.Payment, error) {
+	var payment model.Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("transaction_id = ?", n.TransactionID).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, lib.Error.Payment.NotFound
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	if n.Status == model.StatusCompleted && n.Amount > 0 && n.Amount != payment.Price {
+		return nil, lib.Error.Payment.AmountMismatch.WithError(fmt.Errorf("payment %s expects %d, received %d", payment.ID, payment.Price, n.Amount))
+	}
+	if n.Status == model.StatusCompleted && payment.Status == model.StatusRefunded {
+		return &payment, nil // Late redelivery of the confirmation
+	}
+	if n.Status == model.StatusRefunded && payment.Status == model.StatusPending {
+		// Paid and refunded before we heard about the payment
+		if err := payment.MoveTo(model.StatusCompleted, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	completed := n.Status == model.StatusCompleted && payment.Status != model.StatusCompleted
+	if n.Status == model.StatusRefunded {
+		refunded := n.RefundedAmount
+		if refunded <= 0 {
+			refunded = payment.Price
+		}
+		if err := payment.RecordRefund(refunded, time.Now()); err != nil {
+			return nil, err
+		}
+	} else if err := payment.MoveTo(n.Status, time.Now()); err != nil {
+		return nil, err
+	}
+	if n.Status == model.StatusFailed {
+		payment.FailureReason = n.Reason
+	}
+	if err := tx.Save(&payment).Error; err != nil {
+		return nil, lib.Error.General.UpdatedError.WithError(err)
+	}
+	// Points earned on the appointment are taken back as much as it was refunded
+	if n.Status == model.StatusRefunded && payment.AppointmentID != nil {
+		if err := loyalty.ReverseEarned(tx, *payment.AppointmentID, payment.RefundedAmount, payment.Price); err != nil {
+			return nil, err
+		}
+	}
+
+	// A payment confirmed after its appointment was cancelled follows the cancellation policy,
+	// otherwise the client gets the receipt.
+	if n.Status == model.StatusCompleted && payment.AppointmentID != nil {
+		var appointment model.Appointment
+		if err := tx.Where("id = ?", *payment.AppointmentID).First(&appointment).Error; err != nil {
+			return nil, lib.Error.General.InternalError.WithError(err)
+		}
+		if appointment.IsCancelled {
+			if err := RefundOnCancel(tx, &appointment); err != nil {
+				return nil, err
+			}
+		} else if completed && appointment.ReceiptURL == "" {
+			if err := receipt.Enqueue(tx, &appointment, ""); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &payment, nil
+}
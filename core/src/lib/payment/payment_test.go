@@ -0,0 +1,236 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib/pix"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeCharge(t *testing.T) {
+	fake := NewFake("")
+	ctx := context.Background()
+
+	result, err := fake.Charge(ctx, ChargeRequest{Amount: 100, Token: "tok_visa"})
+	assert.NoError(t, err)
+	assert.Equal(t, ChargeAuthorized, result.Status)
+	assert.NotEmpty(t, result.TransactionID)
+
+	result, err = fake.Charge(ctx, ChargeRequest{Amount: 100, Token: FakeTokenDeclined})
+	assert.NoError(t, err)
+	assert.Equal(t, ChargeDeclined, result.Status)
+
+	result, err = fake.Charge(ctx, ChargeRequest{Amount: 100, Token: FakeTokenPending})
+	assert.NoError(t, err)
+	assert.Equal(t, ChargePending, result.Status)
+
+	_, err = fake.Charge(ctx, ChargeRequest{Amount: 0, Token: "tok_visa"})
+	assert.Error(t, err)
+}
+
+func TestFakeCharged(t *testing.T) {
+	fake := NewFake("")
+	ctx := context.Background()
+	token := "tok_" + uuid.NewString()
+
+	result, err := fake.Charge(ctx, ChargeRequest{Amount: 500, Token: token})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), FakeCharged(token), "an authorization alone charges nothing")
+
+	assert.NoError(t, fake.Capture(ctx, result.TransactionID, 500))
+	assert.Equal(t, int64(500), FakeCharged(token))
+
+	assert.NoError(t, fake.Refund(ctx, result.TransactionID, 200, "key"))
+	assert.Equal(t, int64(300), FakeCharged(token))
+}
+
+func TestFakeVerifyWebhook(t *testing.T) {
+	fake := NewFake("secret")
+	body := []byte(`{"transaction_id":"fake_1","status":"COMPLETED"}`)
+
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, fake.Sign(body))
+	n, err := fake.VerifyWebhook(header, body)
+	assert.NoError(t, err)
+	assert.Len(t, n, 1)
+	assert.Equal(t, "fake_1", n[0].TransactionID)
+	assert.Equal(t, model.StatusCompleted, n[0].Status)
+
+	header.Set(FakeSignatureHeader, NewFake("other").Sign(body))
+	_, err = fake.VerifyWebhook(header, body)
+	assert.Error(t, err)
+
+	_, err = fake.VerifyWebhook(http.Header{}, body)
+	assert.Error(t, err)
+}
+
+func TestPixCharge(t *testing.T) {
+	ctx := context.Background()
+	receiver := &PixReceiver{Key: "pix@example.com", Name: "Salão Bela", City: "São Paulo"}
+
+	result, err := NewPix("secret", "").Charge(ctx, ChargeRequest{Amount: 2500, Currency: "BRL", Pix: receiver})
+	assert.NoError(t, err)
+	assert.Equal(t, ChargePending, result.Status)
+	assert.Len(t, result.TransactionID, 25)
+	assert.True(t, pix.Valid(result.PixPayload))
+	assert.Contains(t, result.PixPayload, "pix@example.com")
+	assert.Contains(t, result.PixPayload, "540525.00")
+	assert.Contains(t, result.PixPayload, result.TransactionID)
+
+	result, err = NewPix("secret", "pix.example.com/qr/v2/").Charge(ctx, ChargeRequest{Amount: 2500, Pix: &PixReceiver{Name: "Mynute", City: "Curitiba"}})
+	assert.NoError(t, err)
+	assert.Len(t, result.TransactionID, 32)
+	assert.Contains(t, result.PixPayload, "pix.example.com/qr/v2/"+result.TransactionID)
+
+	_, err = NewPix("secret", "").Charge(ctx, ChargeRequest{Amount: 2500, Pix: &PixReceiver{Name: "Mynute", City: "Curitiba"}})
+	assert.Error(t, err)
+	_, err = NewPix("secret", "").Charge(ctx, ChargeRequest{Amount: 2500, Currency: "USD", Pix: receiver})
+	assert.Error(t, err)
+	assert.ErrorIs(t, NewPix("secret", "").Refund(ctx, "tx", 100, "key"), ErrRefundNotSupported)
+}
+
+func TestPixVerifyWebhook(t *testing.T) {
+	provider := NewPix("secret", "")
+	body := []byte(`{"pix":[
+		{"endToEndId":"E1","txid":"abc","valor":"25.00","horario":"2030-01-01T10:00:00Z"},
+		{"endToEndId":"E2","txid":"def","valor":"10.5","devolucoes":[{"valor":"4.00","status":"DEVOLVIDO"},{"valor":"1.00","status":"EM_PROCESSAMENTO"}]},
+		{"endToEndId":"E3","valor":"3.00"}
+	]}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	header := http.Header{}
+	header.Set(PixSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+
+	notifications, err := provider.VerifyWebhook(header, body)
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 2)
+	assert.Equal(t, Notification{TransactionID: "abc", Status: model.StatusCompleted, Amount: 2500}, notifications[0])
+	assert.Equal(t, Notification{TransactionID: "def", Status: model.StatusRefunded, Amount: 1050, RefundedAmount: 400}, notifications[1])
+
+	header.Set(PixSignatureHeader, "00")
+	_, err = provider.VerifyWebhook(header, body)
+	assert.Error(t, err)
+
+	_, err = NewPix("", "").VerifyWebhook(header, body)
+	assert.Error(t, err)
+}
+
+func TestParseCents(t *testing.T) {
+	for value, want := range map[string]int64{"10.50": 1050, "10.5": 1050, "10": 1000, "0.01": 1} {
+		got, err := parseCents(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+	for _, value := range []string{"", "1.234", "abc", "-1.00", ".50"} {
+		_, err := parseCents(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestPaymentMoveTo(t *testing.T) {
+	now := time.Now()
+	p := &model.Payment{Status: model.StatusPending}
+
+	assert.NoError(t, p.MoveTo(model.StatusCompleted, now))
+	assert.Equal(t, model.StatusCompleted, p.Status)
+	assert.NotNil(t, p.CompletedAt)
+
+	assert.Error(t, p.MoveTo(model.StatusFailed, now))
+	assert.NoError(t, p.MoveTo(model.StatusRefunded, now))
+	assert.NotNil(t, p.RefundedAt)
+	assert.Error(t, p.MoveTo(model.StatusCompleted, now))
+
+	failed := &model.Payment{Status: model.StatusFailed}
+	assert.Error(t, failed.MoveTo(model.StatusCompleted, now))
+}
+
+func TestPaymentRecordRefund(t *testing.T) {
+	now := time.Now()
+	p := &model.Payment{Price: 100, Status: model.StatusCompleted}
+
+	assert.NoError(t, p.RecordRefund(40, now))
+	assert.Equal(t, model.StatusCompleted, p.Status)
+	assert.Equal(t, int64(40), p.RefundedAmount)
+	assert.Nil(t, p.RefundedAt)
+
+	// A total lower than the one recorded is a late notification
+	assert.NoError(t, p.RecordRefund(30, now))
+	assert.Equal(t, int64(40), p.RefundedAmount)
+
+	assert.NoError(t, p.RecordRefund(150, now))
+	assert.Equal(t, model.StatusRefunded, p.Status)
+	assert.Equal(t, int64(100), p.RefundedAmount)
+	assert.NotNil(t, p.RefundedAt)
+
+	pending := &model.Payment{Price: 100, Status: model.StatusPending}
+	assert.Error(t, pending.RecordRefund(100, now))
+}
+
+func TestServicePrepayment(t *testing.T) {
+	s := &model.Service{Price: 200}
+	_, _, ok := s.PrepaymentAmount()
+	assert.False(t, ok)
+
+	s.PrepaymentType = model.PrepaymentDeposit
+	s.DepositAmount = 50
+	amount, paymentType, ok := s.PrepaymentAmount()
+	assert.True(t, ok)
+	assert.Equal(t, int64(50), amount)
+	assert.Equal(t, model.PaymentTypeDeposit, paymentType)
+	assert.NoError(t, s.ValidatePayment())
+
+	s.DepositAmount = 300
+	assert.Error(t, s.ValidatePayment())
+
+	s.PrepaymentType = model.PrepaymentFull
+	amount, paymentType, ok = s.PrepaymentAmount()
+	assert.True(t, ok)
+	assert.Equal(t, int64(200), amount)
+	assert.Equal(t, model.PaymentTypeFull, paymentType)
+
+	s.PrepaymentType = "LATER"
+	assert.Error(t, s.ValidatePayment())
+}
+
+func TestServiceRefundAmount(t *testing.T) {
+	s := &model.Service{}
+	s.FreeCancellationHours = 24
+	s.LateCancellationRefundPercent = 50
+	start := time.Date(2030, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, int64(100), s.RefundAmount(100, start, start.Add(-48*time.Hour)))
+	assert.Equal(t, int64(100), s.RefundAmount(100, start, start.Add(-24*time.Hour)))
+	assert.Equal(t, int64(50), s.RefundAmount(100, start, start.Add(-time.Hour)))
+	assert.Equal(t, int64(0), s.RefundAmount(0, start, start.Add(-time.Hour)))
+
+	s.LateCancellationRefundPercent = 0
+	assert.Equal(t, int64(0), s.RefundAmount(100, start, start.Add(-time.Hour)))
+}
+
+func TestServiceTermsFor(t *testing.T) {
+	s := &model.Service{Price: 100, Duration: 30}
+	s.ID = uuid.New()
+	employee, branch, other := uuid.New(), uuid.New(), uuid.New()
+	price := func(v int64) *int64 { return &v }
+	duration := func(v uint16) *uint16 { return &v }
+	overrides := []model.ServiceOverride{
+		{ServiceID: s.ID, BranchID: &branch, Price: price(120), Duration: duration(45)},
+		{ServiceID: s.ID, EmployeeID: &employee, Price: price(150)},
+		{ServiceID: s.ID, EmployeeID: &employee, BranchID: &branch, Duration: duration(60)},
+		{ServiceID: s.ID, EmployeeID: &other, Price: price(999)},
+	}
+
+	assert.Equal(t, model.ServiceTerms{Price: 100, Duration: 30}, s.TermsFor(overrides, uuid.New(), other))
+	assert.Equal(t, model.ServiceTerms{Price: 120, Duration: 45}, s.TermsFor(overrides, uuid.New(), branch))
+	assert.Equal(t, model.ServiceTerms{Price: 150, Duration: 30}, s.TermsFor(overrides, employee, other))
+	assert.Equal(t, model.ServiceTerms{Price: 150, Duration: 60}, s.TermsFor(overrides, employee, branch))
+	assert.Equal(t, model.ServiceTerms{Price: 999, Duration: 45}, s.TermsFor(overrides, other, branch))
+}
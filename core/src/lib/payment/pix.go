@@ -0,0 +1,163 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/lib/pix"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PixProviderName = "pix"
+	PixMethod       = "PIX"
+	// PixSignatureHeader carries the hex HMAC-SHA256 of the webhook body, signed with PIX_WEBHOOK_SECRET.
+	PixSignatureHeader = "X-Pix-Signature"
+)
+
+// ErrRefundNotSupported is returned by providers that cannot send money back by
+// themselves. The refund has to be made by the company and is then reported by webhook.
+var ErrRefundNotSupported = errors.New("refunds are not supported by the provider")
+
+// PixReceiver is the company receiving a Pix charge.
+type PixReceiver struct {
+	Key  string
+	Name string
+	City string
+}
+
+// Pix charges through BR Codes. With a PSP location URL the codes are dynamic and
+// point to the charge at the PSP, otherwise they are static codes with the company key.
+// Payments are confirmed by the PSP webhook.
+type Pix struct {
+	secret      string
+	locationURL string
+}
+
+func NewPix(secret, locationURL string) *Pix {
+	return &Pix{secret: secret, locationURL: strings.TrimSuffix(locationURL, "/")}
+}
+
+func (p *Pix) Name() string { return PixProviderName }
+
+func (p *Pix) Charge(ctx context.Context, req ChargeRequest) (*ChargeResult, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("charge amount must be positive")
+	}
+	if req.Currency != "" && req.Currency != "BRL" {
+		return nil, fmt.Errorf("pix only accepts BRL, got %s", req.Currency)
+	}
+	if req.Pix == nil {
+		return nil, lib.Error.Payment.PixNotConfigured
+	}
+	txID := strings.ReplaceAll(uuid.NewString(), "-", "")
+	merchant := pix.Merchant{Name: req.Pix.Name, City: req.Pix.City}
+
+	var payload string
+	var err error
+	if p.locationURL != "" {
+		payload, err = pix.Dynamic{Merchant: merchant, Location: p.locationURL + "/" + txID, Amount: req.Amount}.Payload()
+	} else {
+		if req.Pix.Key == "" {
+			return nil, lib.Error.Payment.PixNotConfigured
+		}
+		txID = txID[:25] // Static codes take at most 25 characters
+		payload, err = pix.Static{Merchant: merchant, Key: req.Pix.Key, Amount: req.Amount, TxID: txID}.Payload()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to build BR Code: %w", err)
+	}
+	return &ChargeResult{TransactionID: txID, Status: ChargePending, PixPayload: payload}, nil
+}
+
+// Capture does nothing: a Pix is settled as soon as it is paid.
+func (p *Pix) Capture(ctx context.Context, transactionID string, amount int64) error {
+	return nil
+}
+
+// Refund is not possible from here: devolutions are requested to the receiving bank.
+func (p *Pix) Refund(ctx context.Context, transactionID string, amount int64, idempotencyKey string) error {
+	return ErrRefundNotSupported
+}
+
+// pixWebhook is the body of the Pix webhook defined by the Bacen API, used by PSPs.
+type pixWebhook struct {
+	Pix []struct {
+		EndToEndID string `json:"endToEndId"`
+		TxID       string `json:"txid"`
+		Valor      string `json:"valor"`
+		Horario    string `json:"horario"`
+		Devolucoes []struct {
+			Valor  string `json:"valor"`
+			Status string `json:"status"`
+		} `json:"devolucoes"`
+	} `json:"pix"`
+}
+
+func (p *Pix) VerifyWebhook(header http.Header, body []byte) ([]Notification, error) {
+	if p.secret == "" {
+		return nil, errors.New("PIX_WEBHOOK_SECRET is not set")
+	}
+	signature, err := hex.DecodeString(header.Get(PixSignatureHeader))
+	if err != nil || len(signature) == 0 {
+		return nil, errors.New("missing or malformed signature")
+	}
+	mac := hmac.New(sha256.New, []byte(p.secret))
+	mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, errors.New("signature mismatch")
+	}
+
+	var hook pixWebhook
+	if err := json.Unmarshal(body, &hook); err != nil {
+		return nil, fmt.Errorf("invalid notification body: %w", err)
+	}
+	notifications := make([]Notification, 0, len(hook.Pix))
+	for _, received := range hook.Pix {
+		if received.TxID == "" {
+			continue // Pix sent to the key without a charge, nothing to reconcile
+		}
+		amount, err := parseCents(received.Valor)
+		if err != nil {
+			return nil, fmt.Errorf("pix %s: %w", received.EndToEndID, err)
+		}
+		n := Notification{TransactionID: received.TxID, Status: model.StatusCompleted, Amount: amount}
+		for _, devolution := range received.Devolucoes {
+			if devolution.Status != "DEVOLVIDO" {
+				continue
+			}
+			refunded, err := parseCents(devolution.Valor)
+			if err != nil {
+				return nil, fmt.Errorf("pix %s devolution: %w", received.EndToEndID, err)
+			}
+			n.Status = model.StatusRefunded
+			n.RefundedAmount += refunded
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, nil
+}
+
+// parseCents reads a decimal amount such as "10.50" as cents.
+func parseCents(value string) (int64, error) {
+	units, cents, _ := strings.Cut(value, ".")
+	if units == "" || len(cents) > 2 {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	cents += strings.Repeat("0", 2-len(cents))
+	n, err := strconv.ParseInt(units+cents, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	return n, nil
+}
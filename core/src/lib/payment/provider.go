@@ -0,0 +1,80 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"net/http"
+	"os"
+)
+
+// ChargeStatus is the provider answer to a charge.
+type ChargeStatus string
+
+const (
+	ChargeAuthorized ChargeStatus = "AUTHORIZED" // Funds are held, Capture settles them
+	ChargePending    ChargeStatus = "PENDING"    // Asynchronous method, the outcome comes through a webhook
+	ChargeDeclined   ChargeStatus = "DECLINED"
+)
+
+type ChargeRequest struct {
+	Amount      int64
+	Currency    string
+	Method      string // e.g. "CREDIT_CARD"
+	Token       string // Tokenized payment method from the provider's client SDK
+	Description string
+	Reference   string       // Our own reference, used by providers as idempotency key
+	Pix         *PixReceiver // Who receives Pix charges
+}
+
+type ChargeResult struct {
+	TransactionID string
+	Status        ChargeStatus
+	Reason        string // Why the charge was declined, when it was
+	PixPayload    string // BR Code to be paid, for Pix charges
+}
+
+// Notification is a payment status change reported by the provider through a webhook.
+type Notification struct {
+	TransactionID  string              `json:"transaction_id"`
+	Status         model.PaymentStatus `json:"status"`
+	Amount         int64               `json:"amount"` // Amount received, when the provider reports it
+	RefundedAmount int64               `json:"refunded_amount"`
+	Reason         string              `json:"reason"`
+}
+
+// Provider is a payment gateway. Amounts are in the currency minor unit (cents).
+type Provider interface {
+	Name() string
+	Charge(ctx context.Context, req ChargeRequest) (*ChargeResult, error)
+	Capture(ctx context.Context, transactionID string, amount int64) error
+	// Refund gives amount back to the client. idempotencyKey must be the same on retries.
+	Refund(ctx context.Context, transactionID string, amount int64, idempotencyKey string) error
+	// VerifyWebhook checks the request signature and parses the notifications it carries.
+	VerifyWebhook(header http.Header, body []byte) ([]Notification, error)
+}
+
+// NewProvider returns the provider with the given name. An empty name uses
+// PAYMENT_PROVIDER, falling back to the fake provider in dev and test.
+func NewProvider(name string) (Provider, error) {
+	if name == "" {
+		name = os.Getenv("PAYMENT_PROVIDER")
+	}
+	if name == "" {
+		switch os.Getenv("APP_ENV") {
+		case "dev", "test":
+			name = FakeProviderName
+		default:
+			return nil, lib.Error.Payment.ProviderNotSupported.WithError(fmt.Errorf("PAYMENT_PROVIDER is not set"))
+		}
+	}
+	switch name {
+	case FakeProviderName:
+		return NewFake(os.Getenv("PAYMENT_FAKE_SECRET")), nil
+	case PixProviderName:
+		return NewPix(os.Getenv("PIX_WEBHOOK_SECRET"), os.Getenv("PIX_LOCATION_URL")), nil
+	default:
+		return nil, lib.Error.Payment.ProviderNotSupported.WithError(fmt.Errorf("payment provider (%s) not implemented", name))
+	}
+}
@@ -0,0 +1,203 @@
+package pix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// EMV field IDs used by the BR Code (Pix QR code) payload.
+const (
+	idPayloadFormat        = "00"
+	idPointOfInitiation    = "01"
+	idMerchantAccount      = "26"
+	idMerchantCategoryCode = "52"
+	idCurrency             = "53"
+	idAmount               = "54"
+	idCountryCode          = "58"
+	idMerchantName         = "59"
+	idMerchantCity         = "60"
+	idAdditionalData       = "62"
+	idCRC16                = "63"
+
+	idAccountGUI         = "00"
+	idAccountKey         = "01"
+	idAccountDescription = "02"
+	idAccountURL         = "25"
+	idAdditionalTxID     = "05"
+)
+
+const (
+	gui            = "br.gov.bcb.pix"
+	currencyBRL    = "986"
+	maxNameLength  = 25
+	maxCityLength  = 15
+	maxStaticTxID  = 25
+	noTxID         = "***" // Used when the static code has no transaction id
+	singleUseCode  = "12"  // Point of initiation of dynamic codes, paid only once
+	maxFieldLength = 99
+)
+
+// Merchant is who receives the Pix.
+type Merchant struct {
+	Name string // Up to 25 characters, accents are removed
+	City string // Up to 15 characters, accents are removed
+}
+
+// Static is a BR Code carrying the receiver Pix key. It may be paid any number of
+// times; Amount and TxID are optional.
+type Static struct {
+	Merchant
+	Key         string // Pix key: e-mail, phone, CPF/CNPJ or random key
+	Description string
+	Amount      int64  // In cents, 0 lets the payer type the amount
+	TxID        string // Up to 25 alphanumeric characters
+}
+
+// Dynamic is a BR Code pointing at the location where the PSP serves the charge.
+type Dynamic struct {
+	Merchant
+	Location string // URL of the charge without the scheme, e.g. "pix.example.com/qr/v2/9d36b84f"
+	Amount   int64  // In cents
+}
+
+// Payload returns the "copia e cola" text of the static code.
+func (s Static) Payload() (string, error) {
+	if s.Key == "" {
+		return "", errors.New("pix key is required")
+	}
+	if s.Amount < 0 {
+		return "", errors.New("amount must not be negative")
+	}
+	txID := s.TxID
+	if txID == "" {
+		txID = noTxID
+	} else if len(txID) > maxStaticTxID || !isAlphanumeric(txID) {
+		return "", fmt.Errorf("txid must have up to %d alphanumeric characters", maxStaticTxID)
+	}
+	account := field(idAccountGUI, gui) + field(idAccountKey, s.Key)
+	if s.Description != "" {
+		account += field(idAccountDescription, ascii(s.Description))
+	}
+	return build("", account, s.Amount, s.Merchant, txID)
+}
+
+// Payload returns the "copia e cola" text of the dynamic code.
+func (d Dynamic) Payload() (string, error) {
+	if d.Location == "" {
+		return "", errors.New("location is required")
+	}
+	if strings.Contains(d.Location, "://") {
+		return "", errors.New("location must not include the URL scheme")
+	}
+	if d.Amount < 0 {
+		return "", errors.New("amount must not be negative")
+	}
+	account := field(idAccountGUI, gui) + field(idAccountURL, d.Location)
+	return build(singleUseCode, account, d.Amount, d.Merchant, noTxID)
+}
+
+func build(initiation, account string, amount int64, m Merchant, txID string) (string, error) {
+	name := truncate(ascii(m.Name), maxNameLength)
+	city := truncate(ascii(m.City), maxCityLength)
+	if name == "" || city == "" {
+		return "", errors.New("merchant name and city are required")
+	}
+	if len(account) > maxFieldLength {
+		return "", errors.New("merchant account information is too long")
+	}
+
+	var b strings.Builder
+	b.WriteString(field(idPayloadFormat, "01"))
+	if initiation != "" {
+		b.WriteString(field(idPointOfInitiation, initiation))
+	}
+	b.WriteString(field(idMerchantAccount, account))
+	b.WriteString(field(idMerchantCategoryCode, "0000"))
+	b.WriteString(field(idCurrency, currencyBRL))
+	if amount > 0 {
+		b.WriteString(field(idAmount, fmt.Sprintf("%d.%02d", amount/100, amount%100)))
+	}
+	b.WriteString(field(idCountryCode, "BR"))
+	b.WriteString(field(idMerchantName, name))
+	b.WriteString(field(idMerchantCity, city))
+	b.WriteString(field(idAdditionalData, field(idAdditionalTxID, txID)))
+	b.WriteString(idCRC16 + "04")
+	payload := b.String()
+	return payload + CRC16(payload), nil
+}
+
+// field encodes an EMV ID + length + value.
+func field(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// CRC16 returns the CRC16-CCITT (polynomial 0x1021, initial value 0xFFFF) of data as
+// four uppercase hex digits, the checksum ending every BR Code.
+func CRC16(data string) string {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%04X", crc)
+}
+
+// Valid tells whether the payload ends with a correct CRC16.
+func Valid(payload string) bool {
+	if len(payload) < 8 || payload[len(payload)-8:len(payload)-4] != idCRC16+"04" {
+		return false
+	}
+	return CRC16(payload[:len(payload)-4]) == payload[len(payload)-4:]
+}
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+)
+
+// ascii removes accents and drops any other non printable ASCII character,
+// as payment apps do not read them reliably.
+func ascii(s string) string {
+	s = accents.Replace(strings.TrimSpace(s))
+	var b strings.Builder
+	for _, r := range s {
+		if r >= 0x20 && r < utf8.RuneSelf {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return strings.TrimSpace(s[:n])
+	}
+	return s
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package pix
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRC16(t *testing.T) {
+	assert.Equal(t, "29B1", CRC16("123456789"))
+}
+
+func TestStaticPayload(t *testing.T) {
+	// Example of the Pix BR Code manual
+	payload, err := Static{
+		Merchant: Merchant{Name: "Fulano de Tal", City: "BRASILIA"},
+		Key:      "123e4567-e12b-12d1-a456-426655440000",
+	}.Payload()
+	assert.NoError(t, err)
+	assert.Equal(t, "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D", payload)
+	assert.True(t, Valid(payload))
+
+	payload, err = Static{
+		Merchant: Merchant{Name: "Salão da Conceição", City: "São Paulo"},
+		Key:      "pix@example.com",
+		Amount:   1050,
+		TxID:     "abc123",
+	}.Payload()
+	assert.NoError(t, err)
+	assert.Contains(t, payload, "540510.50")
+	assert.Contains(t, payload, "5918Salao da Conceicao")
+	assert.Contains(t, payload, "6009Sao Paulo")
+	assert.Contains(t, payload, "62100506abc123")
+	assert.True(t, Valid(payload))
+
+	_, err = Static{Merchant: Merchant{Name: "A", City: "B"}, Key: "k", TxID: "with-dash"}.Payload()
+	assert.Error(t, err)
+	_, err = Static{Merchant: Merchant{Name: "A", City: "B"}}.Payload()
+	assert.Error(t, err)
+}
+
+func TestDynamicPayload(t *testing.T) {
+	payload, err := Dynamic{
+		Merchant: Merchant{Name: "Mynute", City: "Curitiba"},
+		Location: "pix.example.com/qr/v2/9d36b84fc70b478fb95c12729b90ca25",
+		Amount:   5000,
+	}.Payload()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(payload, "000201010212"))
+	assert.Contains(t, payload, "2554pix.example.com/qr/v2/9d36b84fc70b478fb95c12729b90ca25")
+	assert.True(t, Valid(payload))
+
+	_, err = Dynamic{Merchant: Merchant{Name: "A", City: "B"}, Location: "https://pix.example.com"}.Payload()
+	assert.Error(t, err)
+}
+
+func TestValid(t *testing.T) {
+	payload, err := Static{Merchant: Merchant{Name: "A", City: "B"}, Key: "k"}.Payload()
+	assert.NoError(t, err)
+	assert.True(t, Valid(payload))
+	assert.False(t, Valid(payload[:len(payload)-1]+"0"))
+	assert.False(t, Valid("short"))
+}
+
+func TestQRCode(t *testing.T) {
+	payload, err := Static{Merchant: Merchant{Name: "A", City: "B"}, Key: "k"}.Payload()
+	assert.NoError(t, err)
+	png, err := QRCode(payload, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", http.DetectContentType(png))
+
+	_, err = QRCode("not a br code", 0)
+	assert.Error(t, err)
+}
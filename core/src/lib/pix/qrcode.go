@@ -0,0 +1,21 @@
+package pix
+
+import (
+	"errors"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// DefaultQRSize is the side in pixels of the rendered QR codes.
+const DefaultQRSize = 512
+
+// QRCode renders the payload as a PNG, ready to be stored with the "image" upload strategy.
+func QRCode(payload string, size int) ([]byte, error) {
+	if !Valid(payload) {
+		return nil, errors.New("invalid BR Code payload")
+	}
+	if size <= 0 {
+		size = DefaultQRSize
+	}
+	return qrcode.Encode(payload, qrcode.Medium, size)
+}
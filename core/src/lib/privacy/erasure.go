@@ -0,0 +1,306 @@
+package privacy
+
+import (
+	"encoding/json"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	mJSON "mynute-go/core/src/config/db/model/json"
+	"mynute-go/core/src/lib"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ErasedName replaces the name of an erased client.
+const ErasedName = "Erased"
+
+// ErasedComment replaces the text of the comments on the appointments of an erased client.
+const ErasedComment = "[erased]"
+
+// Erase anonymizes the client in the public schema and in the schema of every company the
+// client has appointments at. Appointments, payments and receipts are kept for accounting,
+// without the comments and request IPs that could identify the client. Intake answers are
+// dropped. The details of the client are redacted from the payloads of the outbox messages
+// and of the webhook deliveries, which also reaches the companies the client was only
+// notified to. The client is
+// soft deleted, so it can no longer log in, along with its dependents, and its exports
+// are dropped.
+// It returns the companies reached. tx ends pointed at the public schema.
+func Erase(tx *gorm.DB, clientID uuid.UUID, now time.Time) ([]uuid.UUID, error) {
+	if err := lib.ChangeToPublicSchema(tx); err != nil {
+		return nil, err
+	}
+	var client model.Client
+	if err := tx.Where("id = ?", clientID).First(&client).Error; err != nil {
+		return nil, fmt.Errorf("failed to load client %s: %w", clientID, err)
+	}
+	contacts := clientContacts(&client)
+	notified, err := erasePayloads(tx, model.OutboxMessage{}.TableName(), clientID, contacts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to erase outbox messages of client %s: %w", clientID, err)
+	}
+	companies, err := erasureCompanies(tx, clientID, notified)
+	if err != nil {
+		return nil, err
+	}
+
+	companyIDs := make([]uuid.UUID, 0, len(companies))
+	err = inCompanies(tx, companies, func(company *model.Company) error {
+		if err := tx.Model(&model.ClientProfile{}).Where("client_id = ?", clientID).Updates(map[string]any{
+			"tags":                 model.TagList{},
+			"notes":                "",
+			"preferences":          "",
+			"birthday":             nil,
+			"marketing_consent":    false,
+			"marketing_consent_at": now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to erase client profile at company %s: %w", company.ID, err)
+		}
+		// Signed consent PDFs are kept as the record of the consent given
+		if err := tx.Model(&model.IntakeResponse{}).Where("client_id = ?", clientID).UpdateColumns(map[string]any{
+			"answers":      model.IntakeAnswers{},
+			"signed_name":  ErasedName,
+			"signature_ip": "",
+			"token":        nil,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to erase intake responses at company %s: %w", company.ID, err)
+		}
+		// Ratings are kept, they no longer identify the client once the comment is gone
+		if err := tx.Model(&model.Review{}).Where("client_id = ?", clientID).UpdateColumns(map[string]any{
+			"comment": "",
+			"token":   nil,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to erase reviews at company %s: %w", company.ID, err)
+		}
+		for _, table := range []string{model.AppointmentTableName, model.AppointmentArchiveTableName} {
+			if err := eraseAppointments(tx, table, clientID); err != nil {
+				return fmt.Errorf("failed to erase %s at company %s: %w", table, company.ID, err)
+			}
+		}
+		if _, err := erasePayloads(tx, model.WebhookDeliveryTableName, clientID, contacts); err != nil {
+			return fmt.Errorf("failed to erase webhook deliveries at company %s: %w", company.ID, err)
+		}
+		companyIDs = append(companyIDs, company.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if client.Meta.Design.Images.Profile.URL != "" {
+		_ = client.Meta.Design.Images.Delete("profile", client.TableName(), client.ID.String())
+	}
+	// A fresh model keeps BeforeUpdate from checking the password. Email and phone are
+	// unique, so they are cleared rather than replaced.
+	if err := tx.Model(&model.Client{}).Where("id = ?", clientID).Updates(map[string]any{
+		"name":            ErasedName,
+		"surname":         ErasedName,
+		"email":           nil,
+		"phone":           nil,
+		"password":        "",
+		"verified":        false,
+		"meta":            mJSON.UserMeta{},
+		"pending_details": nil,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to erase client %s: %w", clientID, err)
+	}
+	if err := tx.Model(&model.ClientDependent{}).Where("client_id = ?", clientID).UpdateColumns(map[string]any{
+		"name":       ErasedName,
+		"birth_date": nil,
+		"notes":      "",
+		"deleted_at": now,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to erase dependents of client %s: %w", clientID, err)
+	}
+	if err := tx.Model(&model.ClientDataRequest{}).
+		Where("client_id = ? AND kind = ?", clientID, model.ClientDataExport).
+		Updates(map[string]any{"export_json": nil, "export_csv": "", "expires_at": now}).Error; err != nil {
+		return nil, fmt.Errorf("failed to drop exports of client %s: %w", clientID, err)
+	}
+	if err := tx.Where("id = ?", clientID).Delete(&model.Client{}).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete client %s: %w", clientID, err)
+	}
+	return companyIDs, nil
+}
+
+// eraseAppointments redacts the comments and the IPs of the client in the history of its
+// appointments in table. The table is updated directly as archived appointments are
+// read-only through their model.
+func eraseAppointments(tx *gorm.DB, table string, clientID uuid.UUID) error {
+	var rows []struct {
+		ID uuid.UUID
+		model.AppointmentJson
+	}
+	if err := tx.Table(table).Select("id, history, comments").Where("client_id = ?", clientID).Find(&rows).Error; err != nil {
+		return err
+	}
+	for i := range rows {
+		comments := RedactComments(rows[i].Comments)
+		history := ScrubHistory(rows[i].History, clientID)
+		if err := tx.Table(table).Where("id = ?", rows[i].ID).Updates(map[string]any{
+			"comments": &comments,
+			"history":  &history,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// erasureCompanies loads the companies the client has appointments at and the ones in
+// notified, ordered by trade name.
+func erasureCompanies(tx *gorm.DB, clientID uuid.UUID, notified []uuid.UUID) ([]model.Company, error) {
+	if len(notified) == 0 {
+		return clientCompanies(tx, clientID)
+	}
+	var companies []model.Company
+	if err := tx.Model(&model.Company{}).
+		Where("id IN (?) OR id IN ?", tx.Model(&model.ClientAppointment{}).Distinct("company_id").Where("client_id = ?", clientID), notified).
+		Order("trade_name").
+		Find(&companies).Error; err != nil {
+		return nil, fmt.Errorf("failed to load companies of client %s: %w", clientID, err)
+	}
+	return companies, nil
+}
+
+// clientContacts lists the email and phones of the client, including the phone a guest
+// booked with but did not confirm yet.
+func clientContacts(client *model.Client) []string {
+	var contacts []string
+	for _, contact := range []string{client.Email, client.Phone} {
+		if contact != "" {
+			contacts = append(contacts, contact)
+		}
+	}
+	if client.PendingDetails != nil && client.PendingDetails.Phone != "" {
+		contacts = append(contacts, client.PendingDetails.Phone)
+	}
+	return contacts
+}
+
+// erasePayloads redacts the client in the JSON payload of the rows of table that mention
+// its id or one of its contacts. It returns the companies of the rows changed.
+func erasePayloads(tx *gorm.DB, table string, clientID uuid.UUID, contacts []string) ([]uuid.UUID, error) {
+	conditions := []string{"payload::text LIKE ?"}
+	args := []any{"%" + clientID.String() + "%"}
+	for _, contact := range contacts {
+		conditions = append(conditions, "LOWER(payload::text) LIKE ?")
+		args = append(args, "%"+strings.ToLower(contact)+"%")
+	}
+	var rows []struct {
+		ID        uuid.UUID
+		CompanyID *uuid.UUID
+		Payload   datatypes.JSON
+	}
+	if err := tx.Table(table).Select("id, company_id, payload").
+		Where("("+strings.Join(conditions, " OR ")+")", args...).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	var companyIDs []uuid.UUID
+	for i := range rows {
+		payload, changed, err := RedactPayload(rows[i].Payload, clientID, contacts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to redact payload %s: %w", rows[i].ID, err)
+		}
+		if !changed {
+			continue
+		}
+		if err := tx.Table(table).Where("id = ?", rows[i].ID).Update("payload", datatypes.JSON(payload)).Error; err != nil {
+			return nil, err
+		}
+		if rows[i].CompanyID != nil && !slices.Contains(companyIDs, *rows[i].CompanyID) {
+			companyIDs = append(companyIDs, *rows[i].CompanyID)
+		}
+	}
+	return companyIDs, nil
+}
+
+// RedactPayload replaces the details of the client in a JSON payload: the name and
+// contacts of the objects with the id of the client, and any other string equal to one
+// of its contacts. It reports whether anything changed.
+func RedactPayload(payload []byte, clientID uuid.UUID, contacts []string) ([]byte, bool, error) {
+	var v any
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return nil, false, err
+	}
+	v, changed := redactValue(v, clientID.String(), contacts)
+	if !changed {
+		return payload, false, nil
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return nil, false, err
+	}
+	return out, true, nil
+}
+
+func redactValue(v any, clientID string, contacts []string) (any, bool) {
+	switch t := v.(type) {
+	case map[string]any:
+		changed := false
+		isClient := t["id"] == clientID
+		for key, value := range t {
+			if isClient {
+				switch key {
+				case "name", "surname":
+					if s, ok := value.(string); ok && s != ErasedName {
+						t[key], changed = ErasedName, true
+					}
+					continue
+				case "email", "phone":
+					if s, ok := value.(string); ok && s != "" {
+						t[key], changed = "", true
+					}
+					continue
+				}
+			}
+			if redacted, ok := redactValue(value, clientID, contacts); ok {
+				t[key], changed = redacted, true
+			}
+		}
+		return t, changed
+	case []any:
+		changed := false
+		for i, value := range t {
+			if redacted, ok := redactValue(value, clientID, contacts); ok {
+				t[i], changed = redacted, true
+			}
+		}
+		return t, changed
+	case string:
+		for _, contact := range contacts {
+			if strings.EqualFold(strings.TrimSpace(t), contact) {
+				return "", true
+			}
+		}
+	}
+	return v, false
+}
+
+// RedactComments replaces the text of every comment and drops their old versions.
+func RedactComments(comments mJSON.Comments) mJSON.Comments {
+	out := make(mJSON.Comments, len(comments))
+	for i, c := range comments {
+		c.Comment = ErasedComment
+		c.OldVersions = nil
+		out[i] = c
+	}
+	return out
+}
+
+// ScrubHistory drops the IP of the changes made by the client.
+func ScrubHistory(history mJSON.AppointmentHistory, clientID uuid.UUID) mJSON.AppointmentHistory {
+	out := mJSON.AppointmentHistory{FieldChanges: make([]mJSON.FieldChange, len(history.FieldChanges))}
+	for i, change := range history.FieldChanges {
+		if change.ActorID != nil && *change.ActorID == clientID {
+			change.IP = ""
+		}
+		out.FieldChanges[i] = change
+	}
+	return out
+}
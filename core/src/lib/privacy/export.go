@@ -0,0 +1,280 @@
+package privacy
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	mJSON "mynute-go/core/src/config/db/model/json"
+	"mynute-go/core/src/lib"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Export is everything the platform keeps about a client.
+type Export struct {
+	GeneratedAt        time.Time                 `json:"generated_at"`
+	Client             ExportClient              `json:"client"`
+	Dependents         []model.ClientDependent   `json:"dependents"`
+	ClientAppointments []model.ClientAppointment `json:"client_appointments"`
+	Companies          []ExportCompany           `json:"companies"`
+}
+
+type ExportClient struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Surname   string    `json:"surname"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	Verified  bool      `json:"verified"`
+	IsGuest   bool      `json:"is_guest"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportCompany is what one company keeps about the client.
+type ExportCompany struct {
+	CompanyID       uuid.UUID                  `json:"company_id"`
+	TradeName       string                     `json:"trade_name"`
+	Profile         *ExportProfile             `json:"profile"`
+	Appointments    []ExportAppointment        `json:"appointments"`
+	IntakeResponses []model.IntakeResponse     `json:"intake_responses"`
+	Reviews         []model.Review             `json:"reviews"`
+	Loyalty         *model.LoyaltyAccount      `json:"loyalty"`
+	LoyaltyLedger   []model.LoyaltyTransaction `json:"loyalty_ledger"`
+}
+
+type ExportProfile struct {
+	Tags               []string   `json:"tags"`
+	Notes              string     `json:"notes"`
+	Preferences        string     `json:"preferences"`
+	Birthday           *time.Time `json:"birthday"`
+	MarketingConsent   bool       `json:"marketing_consent"`
+	MarketingConsentAt *time.Time `json:"marketing_consent_at"`
+}
+
+type ExportAppointment struct {
+	ID          uuid.UUID       `json:"id"`
+	DependentID *uuid.UUID      `json:"dependent_id"` // Dependent of the client attending, the client itself when nil
+	ServiceID   uuid.UUID       `json:"service_id"`
+	BranchID    uuid.UUID       `json:"branch_id"`
+	EmployeeID  uuid.UUID       `json:"employee_id"`
+	StartTime   time.Time       `json:"start_time"`
+	EndTime     time.Time       `json:"end_time"`
+	TimeZone    string          `json:"time_zone"`
+	Price       int64           `json:"price"`    // In cents
+	Discount    int64           `json:"discount"` // In cents
+	IsFulfilled bool            `json:"is_fulfilled"`
+	IsCancelled bool            `json:"is_cancelled"`
+	Archived    bool            `json:"archived"`
+	Comments    []ExportComment `json:"comments"`
+}
+
+type ExportComment struct {
+	CreatedAt    time.Time `json:"created_at"`
+	Comment      string    `json:"comment"`
+	FromClient   bool      `json:"from_client"`
+	FromEmployee bool      `json:"from_employee"`
+}
+
+// BuildExport gathers the data of the client from the public schema and from the schema
+// of every company the client has appointments at. tx ends pointed at the public schema.
+func BuildExport(tx *gorm.DB, clientID uuid.UUID, now time.Time) (*Export, error) {
+	if err := lib.ChangeToPublicSchema(tx); err != nil {
+		return nil, err
+	}
+	var client model.Client
+	if err := tx.Where("id = ?", clientID).First(&client).Error; err != nil {
+		return nil, fmt.Errorf("failed to load client %s: %w", clientID, err)
+	}
+	export := &Export{
+		GeneratedAt: now,
+		Client: ExportClient{
+			ID:        client.ID,
+			Name:      client.Name,
+			Surname:   client.Surname,
+			Email:     client.Email,
+			Phone:     client.Phone,
+			Verified:  client.Verified,
+			IsGuest:   client.IsGuest,
+			CreatedAt: client.CreatedAt,
+		},
+		Dependents:         []model.ClientDependent{},
+		ClientAppointments: []model.ClientAppointment{},
+		Companies:          []ExportCompany{},
+	}
+	if err := tx.Where("client_id = ?", clientID).Order("name").Find(&export.Dependents).Error; err != nil {
+		return nil, fmt.Errorf("failed to load client dependents: %w", err)
+	}
+	if err := tx.Where("client_id = ?", clientID).Order("start_time").Find(&export.ClientAppointments).Error; err != nil {
+		return nil, fmt.Errorf("failed to load client appointments: %w", err)
+	}
+	companies, err := clientCompanies(tx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = inCompanies(tx, companies, func(company *model.Company) error {
+		entry := ExportCompany{CompanyID: company.ID, TradeName: company.TradeName, Appointments: []ExportAppointment{}, IntakeResponses: []model.IntakeResponse{}, Reviews: []model.Review{}, LoyaltyLedger: []model.LoyaltyTransaction{}}
+
+		var profile model.ClientProfile
+		err := tx.Where("company_id = ? AND client_id = ?", company.ID, clientID).First(&profile).Error
+		if err == nil {
+			entry.Profile = &ExportProfile{
+				Tags:               []string(model.NormalizeTags(profile.Tags)),
+				Notes:              profile.Notes,
+				Preferences:        profile.Preferences,
+				Birthday:           profile.Birthday,
+				MarketingConsent:   profile.MarketingConsent,
+				MarketingConsentAt: profile.MarketingConsentAt,
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to load client profile at company %s: %w", company.ID, err)
+		}
+
+		var appointments []model.Appointment
+		if err := tx.Where("client_id = ?", clientID).Order("start_time").Find(&appointments).Error; err != nil {
+			return fmt.Errorf("failed to load appointments at company %s: %w", company.ID, err)
+		}
+		for i := range appointments {
+			entry.Appointments = append(entry.Appointments, exportAppointment(&appointments[i].AppointmentBase, appointments[i].ID, appointments[i].Comments, false))
+		}
+		var archived []model.AppointmentArchive
+		if err := tx.Where("client_id = ?", clientID).Order("start_time").Find(&archived).Error; err != nil {
+			return fmt.Errorf("failed to load archived appointments at company %s: %w", company.ID, err)
+		}
+		for i := range archived {
+			entry.Appointments = append(entry.Appointments, exportAppointment(&archived[i].AppointmentBase, archived[i].ID, archived[i].Comments, true))
+		}
+		if err := tx.Where("client_id = ?", clientID).Order("created_at").Find(&entry.IntakeResponses).Error; err != nil {
+			return fmt.Errorf("failed to load intake responses at company %s: %w", company.ID, err)
+		}
+		if err := tx.Where("client_id = ?", clientID).Order("created_at").Find(&entry.Reviews).Error; err != nil {
+			return fmt.Errorf("failed to load reviews at company %s: %w", company.ID, err)
+		}
+		var account model.LoyaltyAccount
+		err = tx.Where("company_id = ? AND client_id = ?", company.ID, clientID).First(&account).Error
+		if err == nil {
+			entry.Loyalty = &account
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to load loyalty account at company %s: %w", company.ID, err)
+		}
+		if err := tx.Where("client_id = ?", clientID).Order("created_at").Find(&entry.LoyaltyLedger).Error; err != nil {
+			return fmt.Errorf("failed to load loyalty ledger at company %s: %w", company.ID, err)
+		}
+
+		export.Companies = append(export.Companies, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func exportAppointment(a *model.AppointmentBase, id uuid.UUID, comments mJSON.Comments, archived bool) ExportAppointment {
+	out := ExportAppointment{
+		ID:          id,
+		DependentID: a.DependentID,
+		ServiceID:   a.ServiceID,
+		BranchID:    a.BranchID,
+		EmployeeID:  a.EmployeeID,
+		StartTime:   a.StartTime,
+		EndTime:     a.EndTime,
+		TimeZone:    a.TimeZone,
+		Price:       a.Price,
+		Discount:    a.Discount,
+		IsFulfilled: a.IsFulfilled,
+		IsCancelled: a.IsCancelled,
+		Archived:    archived,
+		Comments:    []ExportComment{},
+	}
+	for _, c := range comments {
+		if c.DeletedAt.Valid {
+			continue
+		}
+		out.Comments = append(out.Comments, ExportComment{
+			CreatedAt:    c.CreatedAt,
+			Comment:      c.Comment,
+			FromClient:   c.FromClient,
+			FromEmployee: c.FromEmployee,
+		})
+	}
+	return out
+}
+
+// CompanyIDs returns the companies the export reached.
+func (e *Export) CompanyIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, len(e.Companies))
+	for i, c := range e.Companies {
+		ids[i] = c.CompanyID
+	}
+	return ids
+}
+
+// JSON encodes the whole export.
+func (e *Export) JSON() ([]byte, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode client export: %w", err)
+	}
+	return data, nil
+}
+
+// csvHeader is the first row of the CSV export, one appointment per row.
+var csvHeader = []string{
+	"company_id", "trade_name", "appointment_id", "dependent_id", "service_id", "branch_id", "employee_id",
+	"start_time", "end_time", "time_zone", "price", "discount", "is_fulfilled", "is_cancelled", "archived", "comments",
+}
+
+// CSV lists the appointments of the client at every company, one per row. The client
+// itself and the company profiles are only in the JSON export.
+func (e *Export) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, fmt.Errorf("failed to write client export CSV: %w", err)
+	}
+	for _, company := range e.Companies {
+		for _, a := range company.Appointments {
+			dependentID := ""
+			if a.DependentID != nil {
+				dependentID = a.DependentID.String()
+			}
+			comments := make([]string, len(a.Comments))
+			for i, c := range a.Comments {
+				comments[i] = c.Comment
+			}
+			if err := w.Write([]string{
+				company.CompanyID.String(),
+				company.TradeName,
+				a.ID.String(),
+				dependentID,
+				a.ServiceID.String(),
+				a.BranchID.String(),
+				a.EmployeeID.String(),
+				a.StartTime.UTC().Format(time.RFC3339),
+				a.EndTime.UTC().Format(time.RFC3339),
+				a.TimeZone,
+				strconv.FormatInt(a.Price, 10),
+				strconv.FormatInt(a.Discount, 10),
+				strconv.FormatBool(a.IsFulfilled),
+				strconv.FormatBool(a.IsCancelled),
+				strconv.FormatBool(a.Archived),
+				strings.Join(comments, " | "),
+			}); err != nil {
+				return nil, fmt.Errorf("failed to write client export CSV: %w", err)
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write client export CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
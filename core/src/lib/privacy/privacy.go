@@ -0,0 +1,141 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	mJSON "mynute-go/core/src/config/db/model/json"
+	"mynute-go/core/src/lib"
+	"mynute-go/core/src/lib/outbox"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Job is the outbox payload of a client data request.
+type Job struct {
+	RequestID uuid.UUID `json:"request_id"`
+}
+
+// Request records an export or erasure request of the client, audited with who asked,
+// and queues it in the outbox within the caller's transaction. While a request of the
+// same kind is pending it is returned instead of a new one.
+func Request(tx *gorm.DB, clientID uuid.UUID, kind model.ClientDataRequestKind, audit mJSON.Audit) (*model.ClientDataRequest, error) {
+	var pending model.ClientDataRequest
+	err := tx.Where("client_id = ? AND kind = ? AND status = ?", clientID, kind, model.ClientDataRequestPending).First(&pending).Error
+	if err == nil {
+		return &pending, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+
+	request := model.NewClientDataRequest(clientID, kind, audit)
+	if err := tx.Create(request).Error; err != nil {
+		return nil, lib.Error.General.CreatedError.WithError(fmt.Errorf("failed to create client data request: %w", err))
+	}
+	topic := model.OutboxTopicClientExport
+	if kind == model.ClientDataErasure {
+		topic = model.OutboxTopicClientErasure
+	}
+	if err := outbox.Enqueue(tx, nil, topic, Job{RequestID: request.ID}); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// HandleExport is the outbox handler for model.OutboxTopicClientExport. It bundles the
+// data of the client into JSON and CSV and keeps both on the request until it expires.
+func HandleExport(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+	request, err := loadPending(tx, msg)
+	if err != nil || request == nil {
+		return err
+	}
+	now := time.Now().UTC()
+	export, err := BuildExport(tx, request.ClientID, now)
+	if err != nil {
+		return err
+	}
+	data, err := export.JSON()
+	if err != nil {
+		return err
+	}
+	csv, err := export.CSV()
+	if err != nil {
+		return err
+	}
+	expiresAt := now.Add(model.ClientDataExportTTL)
+	return tx.Model(&model.ClientDataRequest{}).Where("id = ?", request.ID).Updates(map[string]any{
+		"status":       model.ClientDataRequestCompleted,
+		"company_ids":  model.UUIDList(export.CompanyIDs()),
+		"completed_at": now,
+		"expires_at":   expiresAt,
+		"export_json":  data,
+		"export_csv":   string(csv),
+	}).Error
+}
+
+// HandleErasure is the outbox handler for model.OutboxTopicClientErasure. It anonymizes
+// the client everywhere, keeping the appointment records.
+func HandleErasure(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+	request, err := loadPending(tx, msg)
+	if err != nil || request == nil {
+		return err
+	}
+	now := time.Now().UTC()
+	companyIDs, err := Erase(tx, request.ClientID, now)
+	if err != nil {
+		return err
+	}
+	return tx.Model(&model.ClientDataRequest{}).Where("id = ?", request.ID).Updates(map[string]any{
+		"status":       model.ClientDataRequestCompleted,
+		"company_ids":  model.UUIDList(companyIDs),
+		"completed_at": now,
+	}).Error
+}
+
+// loadPending loads the request of the message. It returns nil when the request was
+// already completed, so a message delivered twice does nothing.
+func loadPending(tx *gorm.DB, msg *model.OutboxMessage) (*model.ClientDataRequest, error) {
+	var job Job
+	if err := outbox.Decode(msg, &job); err != nil {
+		return nil, err
+	}
+	var request model.ClientDataRequest
+	if err := tx.Where("id = ?", job.RequestID).First(&request).Error; err != nil {
+		return nil, fmt.Errorf("failed to load client data request %s: %w", job.RequestID, err)
+	}
+	if request.Status != model.ClientDataRequestPending {
+		return nil, nil
+	}
+	return &request, nil
+}
+
+// clientCompanies returns the companies the client has appointments at, from the
+// ClientAppointment index in the public schema.
+func clientCompanies(tx *gorm.DB, clientID uuid.UUID) ([]model.Company, error) {
+	var companies []model.Company
+	if err := tx.Model(&model.Company{}).
+		Where("id IN (?)", tx.Model(&model.ClientAppointment{}).Distinct("company_id").Where("client_id = ?", clientID)).
+		Order("trade_name").
+		Find(&companies).Error; err != nil {
+		return nil, fmt.Errorf("failed to load companies of client %s: %w", clientID, err)
+	}
+	return companies, nil
+}
+
+// inCompanies runs fn with tx pointed at the schema of each company, then points it back
+// at the public schema.
+func inCompanies(tx *gorm.DB, companies []model.Company, fn func(company *model.Company) error) error {
+	for i := range companies {
+		if err := lib.ChangeToCompanySchema(tx, companies[i].SchemaName); err != nil {
+			return err
+		}
+		if err := fn(&companies[i]); err != nil {
+			return err
+		}
+	}
+	return lib.ChangeToPublicSchema(tx)
+}
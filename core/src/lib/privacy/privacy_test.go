@@ -0,0 +1,133 @@
+package privacy
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"mynute-go/core/src/config/db/model"
+	mJSON "mynute-go/core/src/config/db/model/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportCSV(t *testing.T) {
+	companyID := uuid.New()
+	appointmentID, dependentID := uuid.New(), uuid.New()
+	start := time.Date(2030, 1, 10, 13, 0, 0, 0, time.UTC)
+	e := &Export{Companies: []ExportCompany{{
+		CompanyID: companyID,
+		TradeName: "Barber, Inc",
+		Appointments: []ExportAppointment{{
+			ID:          appointmentID,
+			DependentID: &dependentID,
+			StartTime:   start,
+			EndTime:     start.Add(time.Hour),
+			TimeZone:    "America/Sao_Paulo",
+			Price:       18000,
+			Discount:    3600,
+			IsFulfilled: true,
+			Archived:    true,
+			Comments:    []ExportComment{{Comment: "first"}, {Comment: "second"}},
+		}},
+	}}}
+
+	data, err := e.CSV()
+	require.NoError(t, err)
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, companyID.String(), rows[1][0])
+	assert.Equal(t, "Barber, Inc", rows[1][1])
+	assert.Equal(t, appointmentID.String(), rows[1][2])
+	assert.Equal(t, dependentID.String(), rows[1][3])
+	assert.Equal(t, "2030-01-10T13:00:00Z", rows[1][7])
+	assert.Equal(t, "18000", rows[1][10])
+	assert.Equal(t, "3600", rows[1][11])
+	assert.Equal(t, "true", rows[1][12])
+	assert.Equal(t, "false", rows[1][13])
+	assert.Equal(t, "true", rows[1][14])
+	assert.Equal(t, "first | second", rows[1][15])
+	assert.Equal(t, []uuid.UUID{companyID}, e.CompanyIDs())
+}
+
+func TestRedactComments(t *testing.T) {
+	comments := mJSON.Comments{{
+		Comment:     "Call me at +5511999999999",
+		OldVersions: mJSON.CommentVersions{{Comment: "Call me"}},
+		FromClient:  true,
+	}}
+	redacted := RedactComments(comments)
+	require.Len(t, redacted, 1)
+	assert.Equal(t, ErasedComment, redacted[0].Comment)
+	assert.Empty(t, redacted[0].OldVersions)
+	assert.True(t, redacted[0].FromClient)
+	assert.Equal(t, "Call me at +5511999999999", comments[0].Comment, "input left untouched")
+}
+
+func TestScrubHistory(t *testing.T) {
+	clientID, employeeID := uuid.New(), uuid.New()
+	history := mJSON.AppointmentHistory{FieldChanges: []mJSON.FieldChange{
+		{Field: "start_time", ActorID: &clientID, IP: "10.0.0.1"},
+		{Field: "is_cancelled", ActorID: &employeeID, IP: "10.0.0.2"},
+		{Field: "price", IP: "10.0.0.3"},
+	}}
+	scrubbed := ScrubHistory(history, clientID)
+	assert.Equal(t, "", scrubbed.FieldChanges[0].IP)
+	assert.Equal(t, "10.0.0.2", scrubbed.FieldChanges[1].IP)
+	assert.Equal(t, "10.0.0.3", scrubbed.FieldChanges[2].IP)
+	assert.Equal(t, "10.0.0.1", history.FieldChanges[0].IP, "input left untouched")
+}
+
+func TestRedactPayload(t *testing.T) {
+	clientID, appointmentID := uuid.New(), uuid.New()
+	contacts := []string{"jane@example.com", "+5511999999999"}
+
+	created := []byte(`{"event":"client.created","data":{"id":"` + clientID.String() + `","name":"Jane","surname":"Doe","email":"jane@example.com","phone":"+5511999999999","verified":true}}`)
+	redacted, changed, err := RedactPayload(created, clientID, contacts)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	var envelope struct {
+		Event string         `json:"event"`
+		Data  map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(redacted, &envelope))
+	assert.Equal(t, "client.created", envelope.Event)
+	assert.Equal(t, ErasedName, envelope.Data["name"])
+	assert.Equal(t, ErasedName, envelope.Data["surname"])
+	assert.Equal(t, "", envelope.Data["email"])
+	assert.Equal(t, "", envelope.Data["phone"])
+	assert.Equal(t, true, envelope.Data["verified"])
+
+	email := []byte(`{"appointment_id":"` + appointmentID.String() + `","template":"appointment_created","recipient":"Jane@Example.com","language":"en"}`)
+	redacted, changed, err = RedactPayload(email, clientID, contacts)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	var job map[string]any
+	require.NoError(t, json.Unmarshal(redacted, &job))
+	assert.Equal(t, "", job["recipient"])
+	assert.Equal(t, appointmentID.String(), job["appointment_id"])
+
+	other := []byte(`{"id":"` + uuid.NewString() + `","name":"John","email":"john@example.com"}`)
+	redacted, changed, err = RedactPayload(other, clientID, contacts)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, other, redacted)
+}
+
+func TestDownloadable(t *testing.T) {
+	now := time.Now()
+	expires := now.Add(time.Hour)
+	r := &model.ClientDataRequest{Kind: model.ClientDataExport, Status: model.ClientDataRequestPending}
+	assert.False(t, r.Downloadable(now), "pending")
+	r.Status = model.ClientDataRequestCompleted
+	r.ExpiresAt = &expires
+	assert.True(t, r.Downloadable(now))
+	assert.False(t, r.Downloadable(expires), "expired")
+	r.Kind = model.ClientDataErasure
+	assert.False(t, r.Downloadable(now), "erasures have nothing to download")
+}
@@ -0,0 +1,76 @@
+package promo
+
+import (
+	"errors"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Apply validates the promo code for the freshly created appointment and records
+// the discount on it, within the booking transaction.
+// The code is locked so that concurrent bookings can not exceed its usage limits.
+func Apply(tx *gorm.DB, appointment *model.Appointment, code string) error {
+	var promo model.PromoCode
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("company_id = ? AND code = ?", appointment.CompanyID, model.NormalizePromoCode(code)).
+		First(&promo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return lib.Error.PromoCode.NotFound
+		}
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error loading promo code: %w", err))
+	}
+	if err := promo.AppliesTo(appointment, time.Now()); err != nil {
+		return err
+	}
+
+	// Uses are the appointments booked with the code that are not cancelled
+	uses := func() *gorm.DB {
+		return tx.Model(&model.Appointment{}).
+			Where("promo_code_id = ? AND is_cancelled = ? AND id != ?", promo.ID, false, appointment.ID)
+	}
+	if promo.MaxUses > 0 {
+		var used int64
+		if err := uses().Count(&used).Error; err != nil {
+			return lib.Error.General.InternalError.WithError(fmt.Errorf("error counting promo code uses: %w", err))
+		}
+		if used >= promo.MaxUses {
+			return lib.Error.PromoCode.UsageLimitReached
+		}
+	}
+	if promo.MaxUsesPerClient > 0 {
+		var used int64
+		if err := uses().Where("client_id = ?", appointment.ClientID).Count(&used).Error; err != nil {
+			return lib.Error.General.InternalError.WithError(fmt.Errorf("error counting promo code uses: %w", err))
+		}
+		if used >= promo.MaxUsesPerClient {
+			return lib.Error.PromoCode.ClientLimitReached
+		}
+	}
+	if promo.FirstVisitOnly {
+		var visits int64
+		if err := tx.Model(&model.Appointment{}).
+			Where("client_id = ? AND is_cancelled = ? AND id != ?", appointment.ClientID, false, appointment.ID).
+			Count(&visits).Error; err != nil {
+			return lib.Error.General.InternalError.WithError(fmt.Errorf("error counting client appointments: %w", err))
+		}
+		if visits > 0 {
+			return lib.Error.PromoCode.FirstVisitOnly
+		}
+	}
+
+	appointment.Discount = promo.DiscountOn(appointment.Price)
+	appointment.PromoCodeID = &promo.ID
+	// The appointment hooks forbid changing the discount, it is only set here
+	if err := tx.Model(appointment).UpdateColumns(map[string]any{
+		"discount":      appointment.Discount,
+		"promo_code_id": appointment.PromoCodeID,
+	}).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error applying promo code: %w", err))
+	}
+	return nil
+}
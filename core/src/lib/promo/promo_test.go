@@ -0,0 +1,73 @@
+package promo
+
+import (
+	"mynute-go/core/src/config/db/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscountOn(t *testing.T) {
+	percentage := &model.PromoCode{DiscountType: model.DiscountPercentage, DiscountValue: 20}
+	assert.Equal(t, int64(2000), percentage.DiscountOn(10000))
+	assert.Equal(t, int64(19), percentage.DiscountOn(99))
+	assert.Equal(t, int64(0), percentage.DiscountOn(0))
+
+	fixed := &model.PromoCode{DiscountType: model.DiscountFixed, DiscountValue: 3000}
+	assert.Equal(t, int64(3000), fixed.DiscountOn(10000))
+	assert.Equal(t, int64(2500), fixed.DiscountOn(2500))
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	valid := func() *model.PromoCode {
+		return &model.PromoCode{CompanyID: uuid.New(), Code: "MARCH-30", DiscountType: model.DiscountFixed, DiscountValue: 3000}
+	}
+	assert.NoError(t, valid().Validate())
+
+	for name, mutate := range map[string]func(p *model.PromoCode){
+		"missing company": func(p *model.PromoCode) { p.CompanyID = uuid.Nil },
+		"short code":      func(p *model.PromoCode) { p.Code = "AB" },
+		"lowercase code":  func(p *model.PromoCode) { p.Code = "march" },
+		"unknown type":    func(p *model.PromoCode) { p.DiscountType = "FREE" },
+		"zero fixed":      func(p *model.PromoCode) { p.DiscountValue = 0 },
+		"percentage 101":  func(p *model.PromoCode) { p.DiscountType, p.DiscountValue = model.DiscountPercentage, 101 },
+		"window reversed": func(p *model.PromoCode) { p.StartsAt, p.EndsAt = &later, &now },
+		"negative limit":  func(p *model.PromoCode) { p.MaxUses = -1 },
+	} {
+		p := valid()
+		mutate(p)
+		assert.Error(t, p.Validate(), name)
+	}
+	assert.Equal(t, "MARCH-30", model.NormalizePromoCode(" march-30 "))
+}
+
+func TestAppliesTo(t *testing.T) {
+	now := time.Date(2030, 3, 10, 12, 0, 0, 0, time.UTC)
+	start := time.Date(2030, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC)
+	coloring, haircut := uuid.New(), uuid.New()
+	a := &model.Appointment{}
+	a.ServiceID, a.BranchID, a.EmployeeID = coloring, uuid.New(), uuid.New()
+
+	promo := &model.PromoCode{IsActive: true, StartsAt: &start, EndsAt: &end, ServiceIDs: model.UUIDList{coloring}}
+	assert.NoError(t, promo.AppliesTo(a, now))
+	assert.Error(t, promo.AppliesTo(a, start.Add(-time.Second)))
+	assert.Error(t, promo.AppliesTo(a, end))
+
+	a.ServiceID = haircut
+	assert.Error(t, promo.AppliesTo(a, now))
+	a.ServiceID = coloring
+
+	promo.BranchIDs = model.UUIDList{uuid.New()}
+	assert.Error(t, promo.AppliesTo(a, now))
+	promo.BranchIDs = model.UUIDList{a.BranchID}
+	promo.EmployeeIDs = model.UUIDList{a.EmployeeID}
+	assert.NoError(t, promo.AppliesTo(a, now))
+
+	promo.IsActive = false
+	assert.Error(t, promo.AppliesTo(a, now))
+}
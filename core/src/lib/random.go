@@ -1,6 +1,8 @@
 package lib
 
 import (
+	cryptoRand "crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"math"
 	"math/rand"
@@ -48,6 +50,16 @@ func GenerateRandomString(length int) string {
 	return string(randomString)
 }
 
+// GenerateSecureToken returns a hex encoded token built from n bytes of
+// cryptographically secure randomness. Use it for secrets and one-time links.
+func GenerateSecureToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := cryptoRand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
 // Creates a random email address based on the provided name.
 // The email will be in the format: test_<name>_email_<random_number>@gmail.com
 func GenerateRandomEmail(name string) string {
@@ -0,0 +1,86 @@
+package receipt
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib/email"
+	"mynute-go/core/src/lib/outbox"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailJob is the outbox payload of a receipt email to the client.
+type EmailJob struct {
+	AppointmentID uuid.UUID `json:"appointment_id"`
+	Language      string    `json:"language"`
+}
+
+// Enqueue records the receipt email of the appointment in the outbox, within the caller's
+// transaction. The receipt is issued when the email is sent.
+func Enqueue(tx *gorm.DB, appointment *model.Appointment, language string) error {
+	return outbox.Enqueue(tx, &appointment.CompanyID, model.OutboxTopicReceiptEmail, EmailJob{AppointmentID: appointment.ID, Language: language})
+}
+
+// HandleEmail is the outbox handler for model.OutboxTopicReceiptEmail.
+// It issues the receipt if needed and emails it to the client as a PDF attachment.
+func HandleEmail(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+	var job EmailJob
+	if err := outbox.Decode(msg, &job); err != nil {
+		return err
+	}
+	language := job.Language
+	if language == "" {
+		language = "en"
+	}
+
+	var appointment model.Appointment
+	if err := tx.Where("id = ?", job.AppointmentID).First(&appointment).Error; err != nil {
+		return fmt.Errorf("failed to load appointment %s: %w", job.AppointmentID, err)
+	}
+	r, pdf, err := Issue(tx, &appointment, language)
+	if err != nil {
+		return fmt.Errorf("failed to issue receipt of appointment %s: %w", appointment.ID, err)
+	}
+
+	var client model.Client
+	if err := tx.Where("id = ?", appointment.ClientID).First(&client).Error; err != nil {
+		return fmt.Errorf("failed to load client: %w", err)
+	}
+	if client.Email == "" {
+		log.Printf("receipt %s: client %s has no email, not sent", r.Number, client.ID)
+		return nil
+	}
+
+	renderer := email.NewTemplateRenderer(filepath.Join("static", "email"), filepath.Join("translation", "email"))
+	rendered, err := renderer.RenderEmail("appointment_receipt", language, email.TemplateData{
+		"ClientName":    r.ClientName,
+		"CompanyName":   r.CompanyName,
+		"ServiceName":   r.ServiceName,
+		"Number":        r.Number,
+		"Total":         Money(r.Total(), r.Currency, language),
+		"ReceiptURL":    appointment.ReceiptURL,
+		"BranchAddress": r.BranchAddress,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+
+	sender, err := email.NewProvider(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create email provider: %w", err)
+	}
+	return sender.Send(ctx, email.EmailData{
+		To:      []string{client.Email},
+		Subject: rendered.Subject,
+		Html:    rendered.HTMLBody,
+		Attachments: []*email.Attachment{{
+			Content:     pdf,
+			Filename:    Filename(r),
+			ContentType: "application/pdf",
+		}},
+	})
+}
@@ -0,0 +1,295 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	myUploader "mynute-go/core/src/lib/cloud_uploader"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"gorm.io/gorm"
+)
+
+// Receipt holds what the receipt of an appointment shows.
+type Receipt struct {
+	Number        string
+	IssuedAt      time.Time
+	CompanyName   string // Legal name of the company
+	TaxID         string
+	BranchName    string
+	BranchAddress string
+	ClientName    string
+	EmployeeName  string
+	ServiceName   string
+	StartTime     time.Time // In the appointment time zone
+	Price         int64     // In cents, as booked
+	Discount      int64     // In cents
+	Paid          int64     // In cents, paid online when booking
+	PaymentMethod string
+	PackageName   string // Package or membership whose credit paid the appointment
+	Currency      string
+}
+
+// Total is the price less the discount.
+func (r *Receipt) Total() int64 {
+	return r.Price - r.Discount
+}
+
+// Eligible reports whether the receipt can be issued: the appointment must
+// have been fulfilled or paid.
+func (r *Receipt) Eligible(appointment *model.Appointment) bool {
+	return appointment.IsFulfilled || r.Paid > 0
+}
+
+// Number derives the receipt number from the appointment, so it is stable across renders.
+func Number(appointment *model.Appointment) string {
+	id := strings.ToUpper(strings.ReplaceAll(appointment.ID.String(), "-", ""))
+	return fmt.Sprintf("%s-%s", appointment.StartTime.UTC().Format("20060102"), id[:8])
+}
+
+// Load gathers the receipt of the appointment from the company schema.
+// issuedAt is the date printed on it.
+func Load(tx *gorm.DB, appointment *model.Appointment, issuedAt time.Time) (*Receipt, error) {
+	var company model.Company
+	if err := tx.Where("id = ?", appointment.CompanyID).First(&company).Error; err != nil {
+		return nil, lib.Error.Company.NotFound.WithError(err)
+	}
+	var branch model.Branch
+	if err := tx.Where("id = ?", appointment.BranchID).First(&branch).Error; err != nil {
+		return nil, lib.Error.Branch.NotFound.WithError(err)
+	}
+	var service model.Service
+	if err := tx.Where("id = ?", appointment.ServiceID).First(&service).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("loading service: %w", err))
+	}
+	var employee model.Employee
+	if err := tx.Where("id = ?", appointment.EmployeeID).First(&employee).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(fmt.Errorf("loading employee: %w", err))
+	}
+	var client model.Client
+	if err := tx.Where("id = ?", appointment.ClientID).First(&client).Error; err != nil {
+		return nil, lib.Error.Client.NotFound.WithError(err)
+	}
+
+	start := appointment.StartTime
+	if loc, err := time.LoadLocation(appointment.TimeZone); err == nil {
+		start = start.In(loc)
+		issuedAt = issuedAt.In(loc)
+	}
+	r := &Receipt{
+		Number:        Number(appointment),
+		IssuedAt:      issuedAt,
+		CompanyName:   company.LegalName,
+		TaxID:         company.TaxID,
+		BranchName:    branch.Name,
+		BranchAddress: branch.GetAddress(),
+		ClientName:    strings.TrimSpace(client.Name + " " + client.Surname),
+		EmployeeName:  strings.TrimSpace(employee.Name + " " + employee.Surname),
+		ServiceName:   service.Name,
+		StartTime:     start,
+		Price:         appointment.Price,
+		Discount:      appointment.Discount + appointment.LoyaltyDiscount,
+		Currency:      service.Currency,
+	}
+
+	if appointment.PaymentID != nil {
+		var payment model.Payment
+		if err := tx.Where("id = ?", *appointment.PaymentID).First(&payment).Error; err != nil {
+			return nil, lib.Error.Payment.NotFound.WithError(err)
+		}
+		if payment.Status == model.StatusCompleted {
+			r.Paid = payment.Price
+			r.PaymentMethod = payment.PaymentMethod
+		}
+		r.Currency = payment.Currency
+	}
+	if appointment.ClientPackageID != nil {
+		var pkg model.ClientPackage
+		if err := tx.Where("id = ?", *appointment.ClientPackageID).First(&pkg).Error; err != nil {
+			return nil, lib.Error.Package.CreditNotFound.WithError(err)
+		}
+		r.PackageName = pkg.Name
+	}
+	return r, nil
+}
+
+// Issue renders the receipt of the appointment and returns it with its PDF.
+// The first time, the PDF is stored through the cloud uploader and its URL recorded
+// on the appointment. It is stored under a name derived from the receipt number, so
+// when the transaction rolls back the next issue overwrites it rather than leaving
+// a file no appointment points to. It fails with lib.Error.Appointment.ReceiptUnavailable when
+// the appointment was neither fulfilled nor paid.
+func Issue(tx *gorm.DB, appointment *model.Appointment, language string) (*Receipt, []byte, error) {
+	issuedAt := time.Now()
+	if appointment.ReceiptIssuedAt != nil {
+		issuedAt = *appointment.ReceiptIssuedAt
+	}
+	r, err := Load(tx, appointment, issuedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !r.Eligible(appointment) {
+		return nil, nil, lib.Error.Appointment.ReceiptUnavailable
+	}
+	pdf, err := Render(r, language)
+	if err != nil {
+		return nil, nil, lib.Error.General.InternalError.WithError(err)
+	}
+	if appointment.ReceiptURL != "" {
+		return r, pdf, nil
+	}
+
+	up, err := myUploader.FileUploader("appointment", appointment.ID.String())
+	if err != nil {
+		return nil, nil, lib.Error.General.InternalError.WithError(err)
+	}
+	url, err := up.SaveAs("pdf", pdf, Filename(r))
+	if err != nil {
+		return nil, nil, lib.Error.General.InternalError.WithError(fmt.Errorf("failed to store receipt: %w", err))
+	}
+	// The appointment hooks forbid changing the receipt, it is only set here
+	if err := tx.Model(appointment).UpdateColumns(map[string]any{
+		"receipt_url":       url,
+		"receipt_issued_at": issuedAt,
+	}).Error; err != nil {
+		return nil, nil, lib.Error.General.UpdatedError.WithError(fmt.Errorf("error recording receipt: %w", err))
+	}
+	appointment.ReceiptURL = url
+	appointment.ReceiptIssuedAt = &issuedAt
+	return r, pdf, nil
+}
+
+// Filename is the name of the receipt file, as stored and attached to emails.
+func Filename(r *Receipt) string {
+	return fmt.Sprintf("receipt-%s.pdf", r.Number)
+}
+
+// Render draws the receipt as an A4 PDF in the given language (en, pt or es, defaults to en).
+func Render(r *Receipt, language string) ([]byte, error) {
+	l := labelsFor(language)
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("%s %s", l.title, r.Number), true)
+	pdf.SetAuthor(r.CompanyName, true)
+	pdf.SetCreationDate(r.IssuedAt)
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+	// Core fonts are encoded in cp1252, which covers Portuguese and Spanish accents
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 8, tr(r.CompanyName), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 5, tr(fmt.Sprintf("%s: %s", l.taxID, r.TaxID)), "", 1, "L", false, 0, "")
+	pdf.MultiCell(0, 5, tr(fmt.Sprintf("%s - %s", r.BranchName, r.BranchAddress)), "", "L", false)
+	pdf.Ln(8)
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 8, tr(l.title), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 5, tr(fmt.Sprintf("%s: %s", l.number, r.Number)), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, tr(fmt.Sprintf("%s: %s", l.issuedAt, r.IssuedAt.Format(l.dateFormat))), "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	row := func(label, value string) {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(45, 7, tr(label), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(0, 7, tr(value), "", "L", false)
+	}
+	row(l.client, r.ClientName)
+	row(l.service, r.ServiceName)
+	row(l.professional, r.EmployeeName)
+	row(l.date, r.StartTime.Format(l.dateFormat+" 15:04"))
+	pdf.Ln(4)
+
+	amount := func(label string, value int64, bold bool) {
+		style := ""
+		if bold {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 10)
+		pdf.CellFormat(120, 7, tr(label), "T", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 7, tr(Money(value, r.Currency, language)), "T", 1, "R", false, 0, "")
+	}
+	amount(l.price, r.Price, false)
+	if r.Discount > 0 {
+		amount(l.discount, -r.Discount, false)
+	}
+	amount(l.total, r.Total(), true)
+	if r.Paid > 0 {
+		label := l.paid
+		if r.PaymentMethod != "" {
+			label = fmt.Sprintf("%s (%s)", l.paid, r.PaymentMethod)
+		}
+		amount(label, r.Paid, false)
+	}
+	if r.PackageName != "" {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 7, tr(fmt.Sprintf("%s: %s", l.paidWithCredit, r.PackageName)), "T", 1, "L", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render receipt: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Money formats an amount in cents, e.g. "BRL 1.234,56" in Portuguese and "BRL 1,234.56" in English.
+func Money(cents int64, currency, language string) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	thousands, decimal := ",", "."
+	if language == "pt" || language == "es" {
+		thousands, decimal = ".", ","
+	}
+	units := fmt.Sprintf("%d", cents/100)
+	var grouped strings.Builder
+	for i, digit := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			grouped.WriteString(thousands)
+		}
+		grouped.WriteRune(digit)
+	}
+	return fmt.Sprintf("%s %s%s%s%02d", currency, sign, grouped.String(), decimal, cents%100)
+}
+
+type labels struct {
+	title, taxID, number, issuedAt, client, service, professional, date string
+	price, discount, total, paid, paidWithCredit, dateFormat            string
+}
+
+var translations = map[string]labels{
+	"en": {
+		title: "Receipt", taxID: "Tax ID", number: "Number", issuedAt: "Issued on",
+		client: "Client", service: "Service", professional: "Professional", date: "Date",
+		price: "Price", discount: "Discount", total: "Total", paid: "Paid",
+		paidWithCredit: "Paid with a credit of", dateFormat: "2006-01-02",
+	},
+	"pt": {
+		title: "Recibo", taxID: "CNPJ/CPF", number: "Número", issuedAt: "Emitido em",
+		client: "Cliente", service: "Serviço", professional: "Profissional", date: "Data",
+		price: "Preço", discount: "Desconto", total: "Total", paid: "Pago",
+		paidWithCredit: "Pago com crédito de", dateFormat: "02/01/2006",
+	},
+	"es": {
+		title: "Recibo", taxID: "NIF", number: "Número", issuedAt: "Emitido el",
+		client: "Cliente", service: "Servicio", professional: "Profesional", date: "Fecha",
+		price: "Precio", discount: "Descuento", total: "Total", paid: "Pagado",
+		paidWithCredit: "Pagado con crédito de", dateFormat: "02/01/2006",
+	},
+}
+
+func labelsFor(language string) labels {
+	if l, ok := translations[language]; ok {
+		return l
+	}
+	return translations["en"]
+}
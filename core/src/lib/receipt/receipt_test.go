@@ -0,0 +1,63 @@
+package receipt
+
+import (
+	"mynute-go/core/src/config/db/model"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney(t *testing.T) {
+	assert.Equal(t, "BRL 1,234.56", Money(123456, "BRL", "en"))
+	assert.Equal(t, "BRL 1.234,56", Money(123456, "BRL", "pt"))
+	assert.Equal(t, "USD 0.05", Money(5, "USD", "en"))
+	assert.Equal(t, "BRL -36,00", Money(-3600, "BRL", "pt"))
+	assert.Equal(t, "BRL 1.000.000,00", Money(100000000, "BRL", "es"))
+}
+
+func TestNumberAndEligible(t *testing.T) {
+	a := &model.Appointment{}
+	a.ID = uuid.MustParse("1a2b3c4d-0000-0000-0000-000000000000")
+	a.StartTime = time.Date(2030, 1, 10, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, "20300110-1A2B3C4D", Number(a))
+
+	r := &Receipt{Price: 18000, Discount: 3600}
+	assert.Equal(t, int64(14400), r.Total())
+	assert.False(t, r.Eligible(a), "neither fulfilled nor paid")
+	r.Paid = 7200
+	assert.True(t, r.Eligible(a), "deposit paid")
+	r.Paid = 0
+	a.IsFulfilled = true
+	assert.True(t, r.Eligible(a), "fulfilled")
+}
+
+func TestRender(t *testing.T) {
+	r := &Receipt{
+		Number:        "20300110-1A2B3C4D",
+		IssuedAt:      time.Date(2030, 1, 10, 11, 0, 0, 0, time.UTC),
+		CompanyName:   "Salão Beleza Ltda",
+		TaxID:         "12.345.678/0001-90",
+		BranchName:    "Centro",
+		BranchAddress: "Rua das Flores, 100 - São Paulo, SP",
+		ClientName:    "João Silva",
+		EmployeeName:  "Ana Souza",
+		ServiceName:   "Coloração",
+		StartTime:     time.Date(2030, 1, 10, 10, 0, 0, 0, time.UTC),
+		Price:         18000,
+		Discount:      3600,
+		Paid:          14400,
+		PaymentMethod: "pix",
+		Currency:      "BRL",
+	}
+	for _, language := range []string{"en", "pt", "es", ""} {
+		pdf, err := Render(r, language)
+		require.NoError(t, err)
+		// The cloud uploader pdf strategy accepts it
+		assert.Equal(t, "application/pdf", http.DetectContentType(pdf))
+	}
+	assert.Equal(t, "receipt-20300110-1A2B3C4D.pdf", Filename(r))
+}
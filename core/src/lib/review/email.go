@@ -0,0 +1,91 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib/email"
+	"mynute-go/core/src/lib/outbox"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailJob is the outbox payload of the email inviting the client to review an appointment.
+type EmailJob struct {
+	ReviewID uuid.UUID `json:"review_id"`
+	Language string    `json:"language"`
+	Link     string    `json:"link"`
+}
+
+// EnqueueEmail records the email with the link to answer the review in the outbox,
+// within the caller's transaction.
+func EnqueueEmail(tx *gorm.DB, companyID uuid.UUID, review *model.Review, language, link string) error {
+	return outbox.Enqueue(tx, &companyID, model.OutboxTopicReviewEmail, EmailJob{ReviewID: review.ID, Language: language, Link: link})
+}
+
+// HandleEmail is the outbox handler for model.OutboxTopicReviewEmail.
+// Reviews answered meanwhile are not asked for again.
+func HandleEmail(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+	var job EmailJob
+	if err := outbox.Decode(msg, &job); err != nil {
+		return err
+	}
+	language := job.Language
+	if language == "" {
+		language = "en"
+	}
+
+	var review model.Review
+	if err := tx.Where("id = ?", job.ReviewID).First(&review).Error; err != nil {
+		return fmt.Errorf("failed to load review %s: %w", job.ReviewID, err)
+	}
+	if review.Status != model.ReviewInvited {
+		return nil
+	}
+	var company model.Company
+	if err := tx.Where("id = ?", msg.CompanyID).First(&company).Error; err != nil {
+		return fmt.Errorf("failed to load company: %w", err)
+	}
+	var client model.Client
+	if err := tx.Where("id = ?", review.ClientID).First(&client).Error; err != nil {
+		return fmt.Errorf("failed to load client: %w", err)
+	}
+	if client.Email == "" {
+		log.Printf("review %s: client %s has no email, not sent", review.ID, client.ID)
+		return nil
+	}
+	var service model.Service
+	if err := tx.Select("id", "name").Where("id = ?", review.ServiceID).First(&service).Error; err != nil {
+		return fmt.Errorf("failed to load service: %w", err)
+	}
+	var employee model.Employee
+	if err := tx.Select("id", "name", "surname").Where("id = ?", review.EmployeeID).First(&employee).Error; err != nil {
+		return fmt.Errorf("failed to load employee: %w", err)
+	}
+
+	renderer := email.NewTemplateRenderer(filepath.Join("static", "email"), filepath.Join("translation", "email"))
+	rendered, err := renderer.RenderEmail("review_invitation", language, email.TemplateData{
+		"ClientName":   strings.TrimSpace(client.Name + " " + client.Surname),
+		"CompanyName":  company.TradeName,
+		"ServiceName":  service.Name,
+		"EmployeeName": strings.TrimSpace(employee.Name + " " + employee.Surname),
+		"ReviewLink":   job.Link,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+
+	sender, err := email.NewProvider(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create email provider: %w", err)
+	}
+	return sender.Send(ctx, email.EmailData{
+		To:      []string{client.Email},
+		Subject: rendered.Subject,
+		Html:    rendered.HTMLBody,
+	})
+}
@@ -0,0 +1,118 @@
+package review
+
+import (
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Answer is what the client sends through the emailed link.
+type Answer struct {
+	Rating         int    `json:"rating"`
+	EmployeeRating *int   `json:"employee_rating"`
+	ServiceRating  *int   `json:"service_rating"`
+	Comment        string `json:"comment"`
+}
+
+// Invite creates the review of a fulfilled appointment and records the email inviting its
+// client to answer it in the outbox. Appointments fulfilled again are not invited twice.
+func Invite(tx *gorm.DB, appointment *model.Appointment, language, baseURL string) error {
+	var count int64
+	if err := tx.Model(&model.Review{}).Where("appointment_id = ?", appointment.ID).Count(&count).Error; err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error checking appointment review: %w", err))
+	}
+	if count > 0 {
+		return nil
+	}
+	token, err := lib.GenerateSecureToken(32)
+	if err != nil {
+		return lib.Error.General.InternalError.WithError(fmt.Errorf("error generating review token: %w", err))
+	}
+	review := model.Review{
+		CompanyID:     appointment.CompanyID,
+		AppointmentID: appointment.ID,
+		ClientID:      appointment.ClientID,
+		BranchID:      appointment.BranchID,
+		EmployeeID:    appointment.EmployeeID,
+		ServiceID:     appointment.ServiceID,
+		Status:        model.ReviewInvited,
+		Token:         &token,
+	}
+	if err := tx.Create(&review).Error; err != nil {
+		return lib.Error.General.CreatedError.WithError(fmt.Errorf("error saving review: %w", err))
+	}
+	return EnqueueEmail(tx, appointment.CompanyID, &review, language, Link(baseURL, appointment.CompanyID, token, language))
+}
+
+// Submit saves the answer of the client, leaving the review pending moderation.
+func Submit(tx *gorm.DB, review *model.Review, answer Answer, now time.Time) error {
+	if review.Status != model.ReviewInvited {
+		return lib.Error.Review.AlreadyReviewed
+	}
+	review.Rating = answer.Rating
+	review.EmployeeRating = answer.EmployeeRating
+	review.ServiceRating = answer.ServiceRating
+	review.Comment = answer.Comment
+	if err := review.Validate(); err != nil {
+		return err
+	}
+	if err := tx.Model(review).Updates(map[string]any{
+		"status":          model.ReviewPending,
+		"rating":          review.Rating,
+		"employee_rating": review.EmployeeRating,
+		"service_rating":  review.ServiceRating,
+		"comment":         review.Comment,
+		"token":           nil,
+		"submitted_at":    now,
+	}).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error saving review: %w", err))
+	}
+	review.Status = model.ReviewPending
+	review.Token = nil
+	review.SubmittedAt = &now
+	return nil
+}
+
+// Moderate publishes or rejects an answered review and refreshes the ratings it counts in.
+// Published reviews can still be rejected later, and rejected ones published.
+func Moderate(tx *gorm.DB, review *model.Review, status, note string, moderatorID *uuid.UUID, now time.Time) error {
+	if status != model.ReviewPublished && status != model.ReviewRejected {
+		return lib.Error.Review.Invalid.WithError(fmt.Errorf("status must be %s or %s", model.ReviewPublished, model.ReviewRejected))
+	}
+	if review.Status == model.ReviewInvited {
+		return lib.Error.Review.NotModeratable
+	}
+	note = strings.TrimSpace(note)
+	if len([]rune(note)) > model.MaxModerationNote {
+		return lib.Error.Review.Invalid.WithError(fmt.Errorf("moderation_note must have at most %d characters", model.MaxModerationNote))
+	}
+	wasPublished := review.Status == model.ReviewPublished
+	if err := tx.Model(review).Updates(map[string]any{
+		"status":          status,
+		"moderation_note": note,
+		"moderated_by_id": moderatorID,
+		"moderated_at":    now,
+	}).Error; err != nil {
+		return lib.Error.General.UpdatedError.WithError(fmt.Errorf("error moderating review: %w", err))
+	}
+	review.Status = status
+	review.ModerationNote = note
+	review.ModeratedByID = moderatorID
+	review.ModeratedAt = &now
+	if wasPublished || status == model.ReviewPublished {
+		if err := model.RefreshRatings(tx, review); err != nil {
+			return lib.Error.General.InternalError.WithError(err)
+		}
+	}
+	return nil
+}
+
+// Link is the page answering the review, on the frontend.
+func Link(baseURL string, companyID uuid.UUID, token, language string) string {
+	return fmt.Sprintf("%s/review?token=%s&company_id=%s&lang=%s", baseURL, token, companyID, language)
+}
@@ -0,0 +1,57 @@
+package review
+
+import (
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr(v int) *int { return &v }
+
+func TestSubmitRejects(t *testing.T) {
+	cases := map[string]Answer{
+		"no rating":           {},
+		"rating too low":      {Rating: 0},
+		"rating too high":     {Rating: 6},
+		"employee rating":     {Rating: 5, EmployeeRating: ptr(0)},
+		"service rating":      {Rating: 5, ServiceRating: ptr(7)},
+		"comment too long":    {Rating: 4, Comment: strings.Repeat("a", model.MaxReviewComment+1)},
+		"negative employee":   {Rating: 3, EmployeeRating: ptr(-1)},
+		"service rating zero": {Rating: 3, ServiceRating: ptr(0)},
+	}
+	for name, answer := range cases {
+		r := &model.Review{Status: model.ReviewInvited}
+		err := Submit(nil, r, answer, time.Now())
+		assert.Error(t, err, name)
+		assert.Equal(t, model.ReviewInvited, r.Status, name)
+	}
+}
+
+func TestSubmitAnswered(t *testing.T) {
+	for _, status := range []string{model.ReviewPending, model.ReviewPublished, model.ReviewRejected} {
+		err := Submit(nil, &model.Review{Status: status}, Answer{Rating: 5}, time.Now())
+		assert.Equal(t, lib.Error.Review.AlreadyReviewed, err, status)
+	}
+}
+
+func TestModerateRejects(t *testing.T) {
+	err := Moderate(nil, &model.Review{Status: model.ReviewPending}, model.ReviewInvited, "", nil, time.Now())
+	assert.Error(t, err, "reviews can only be published or rejected")
+
+	err = Moderate(nil, &model.Review{Status: model.ReviewInvited}, model.ReviewPublished, "", nil, time.Now())
+	assert.Equal(t, lib.Error.Review.NotModeratable, err, "reviews not answered yet can not be moderated")
+
+	note := strings.Repeat("a", model.MaxModerationNote+1)
+	err = Moderate(nil, &model.Review{Status: model.ReviewPending}, model.ReviewRejected, note, nil, time.Now())
+	assert.Error(t, err)
+}
+
+func TestLink(t *testing.T) {
+	companyID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	assert.Equal(t, "https://mynute.app/review?token=abc&company_id=00000000-0000-0000-0000-000000000001&lang=es", Link("https://mynute.app", companyID, "abc", "es"))
+}
@@ -154,6 +154,15 @@ func OnlyTime(t time.Time) time.Duration {
 		time.Duration(t.Second())*time.Second
 }
 
+// OvernightEnd returns end on the day after start when the range ends at or before it
+// starts, as work ranges spanning midnight do.
+func OvernightEnd(start, end time.Time) time.Time {
+	if !end.After(start) {
+		return end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
 // TimeRangeOverlaps correctly checks if two time ranges, A and B, have any inclusive overlap.
 // "Inclusive" means that intervals touching at the edges (e.g., [8-10] and [10-12])
 // are considered to be overlapping.
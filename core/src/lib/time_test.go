@@ -0,0 +1,51 @@
+package lib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOvernightEnd(t *testing.T) {
+	day := func(d, hour int) time.Time { return time.Date(2020, 1, d, hour, 0, 0, 0, time.UTC) }
+
+	assert.Equal(t, day(1, 12), OvernightEnd(day(1, 8), day(1, 12)), "a range within the day keeps its end")
+	assert.Equal(t, day(2, 4), OvernightEnd(day(1, 20), day(1, 4)), "a range ending before its start ends the next day")
+	assert.Equal(t, day(2, 0), OvernightEnd(day(1, 20), day(1, 0)), "a range ending at midnight ends the next day")
+}
+
+func TestTimeRangeOverlaps(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2020, 1, 1, hour, 0, 0, 0, time.UTC) }
+
+	assert.True(t, TimeRangeOverlaps(at(8), at(12), nil, at(10), at(14), nil))
+	assert.True(t, TimeRangeOverlaps(at(8), at(10), nil, at(10), at(12), nil), "ranges touching at the edges overlap")
+	assert.False(t, TimeRangeOverlaps(at(8), at(10), nil, at(11), at(12), nil))
+
+	// The range is taken as given, callers roll overnight ranges over with OvernightEnd
+	assert.False(t, TimeRangeOverlaps(at(20), at(4), nil, at(22), at(23), nil))
+	assert.True(t, TimeRangeOverlaps(at(20), OvernightEnd(at(20), at(4)), nil, at(22), at(23), nil))
+
+	sp, err := time.LoadLocation("America/Sao_Paulo")
+	assert.NoError(t, err)
+	// 08:00 in São Paulo is 11:00 UTC
+	assert.True(t, TimeRangeOverlaps(time.Date(2020, 1, 1, 8, 0, 0, 0, sp), time.Date(2020, 1, 1, 9, 0, 0, 0, sp), sp, at(10), at(11), time.UTC))
+	assert.False(t, TimeRangeOverlaps(time.Date(2020, 1, 1, 8, 0, 0, 0, sp), time.Date(2020, 1, 1, 9, 0, 0, 0, sp), sp, at(8), at(9), time.UTC))
+}
+
+func TestTimeRangeFullyContained(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2020, 1, 1, hour, 0, 0, 0, time.UTC) }
+
+	assert.True(t, TimeRangeFullyContained(at(8), at(20), nil, at(9), at(10), nil))
+	assert.True(t, TimeRangeFullyContained(at(8), at(20), nil, at(8), at(20), nil), "edges are contained")
+	assert.False(t, TimeRangeFullyContained(at(8), at(20), nil, at(19), at(21), nil))
+
+	// The range is taken as given, callers roll overnight ranges over with OvernightEnd
+	assert.False(t, TimeRangeFullyContained(at(20), at(4), nil, at(21), at(22), nil))
+	assert.True(t, TimeRangeFullyContained(at(20), OvernightEnd(at(20), at(4)), nil, at(22), OvernightEnd(at(22), at(2)), nil))
+
+	// Times of day only have always wrapped around midnight
+	tod := func(hour int) time.Time { return time.Date(1, 1, 1, hour, 0, 0, 0, time.UTC) }
+	assert.True(t, TimeRangeFullyContained(tod(22), tod(6), nil, tod(23), tod(1), nil))
+	assert.False(t, TimeRangeFullyContained(tod(22), tod(6), nil, tod(5), tod(7), nil))
+}
@@ -99,21 +99,31 @@ func init() {
 // MyCustomStructValidator validates any struct and returns a formatted error if validation fails.
 // It uses the global ValidatorV10 instance and my custom error-wrapping logic.
 func MyCustomStructValidator(s any) error {
-	if err := ValidatorV10.Struct(s); err != nil {
-		// Check if the error is a set of validation errors.
-		if validationErrors, ok := err.(validator.ValidationErrors); ok {
-			badReqErr := Error.General.BadRequest
-			for _, fieldErr := range validationErrors {
-				badReqErr = badReqErr.WithError(
-					fmt.Errorf("field '%s' failed on the '%s' rule", fieldErr.Field(), fieldErr.Tag()),
-				)
-			}
-			return badReqErr
-		}
+	return structValidationError(ValidatorV10.Struct(s))
+}
+
+// MyCustomStructExceptValidator works like MyCustomStructValidator skipping the given fields,
+// e.g. the password of a stored user, which is hashed and so fails its own rule.
+func MyCustomStructExceptValidator(s any, fields ...string) error {
+	return structValidationError(ValidatorV10.StructExcept(s, fields...))
+}
 
-		// If it's a different kind of error (e.g., an invalid type was passed),
-		// wrap it as a general internal error.
-		return Error.General.InternalError.WithError(err)
+func structValidationError(err error) error {
+	if err == nil {
+		return nil
 	}
-	return nil
+	// Check if the error is a set of validation errors.
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		badReqErr := Error.General.BadRequest
+		for _, fieldErr := range validationErrors {
+			badReqErr = badReqErr.WithError(
+				fmt.Errorf("field '%s' failed on the '%s' rule", fieldErr.Field(), fieldErr.Tag()),
+			)
+		}
+		return badReqErr
+	}
+
+	// If it's a different kind of error (e.g., an invalid type was passed),
+	// wrap it as a general internal error.
+	return Error.General.InternalError.WithError(err)
 }
@@ -0,0 +1,28 @@
+package webhook
+
+import "time"
+
+const (
+	// MaxAttempts is how many times a delivery is tried before it is marked as failed.
+	MaxAttempts = 8
+	// BaseDelay is the wait before the first retry. Each following retry doubles it.
+	BaseDelay = 30 * time.Second
+	// MaxDelay caps the wait between two attempts.
+	MaxDelay = 6 * time.Hour
+)
+
+// Backoff returns how long to wait after the given number of failed attempts.
+// Attempt 1 waits BaseDelay, attempt 2 waits 2*BaseDelay and so on, up to MaxDelay.
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		return 0
+	}
+	delay := BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= MaxDelay {
+			return MaxDelay
+		}
+	}
+	return delay
+}
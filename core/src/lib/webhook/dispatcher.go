@@ -0,0 +1,277 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HTTPClient is used for every delivery. Tests may replace it.
+// Its dialer refuses the addresses model.ValidateWebhookAddress rejects, so a host that
+// resolves to an internal address after the subscription was saved is not reached.
+var HTTPClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         (&net.Dialer{Timeout: 5 * time.Second, Control: dialControl}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+	},
+}
+
+// dialControl checks the address a delivery is about to connect to, after resolution.
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	return model.ValidateWebhookAddress(net.ParseIP(host))
+}
+
+// claimLease is how long a delivery being sent is hidden from the retry worker.
+// If the process dies mid-send, the worker picks the delivery up once it expires.
+const claimLease = 5 * time.Minute
+
+// Envelope is the JSON body posted to subscribers.
+type Envelope struct {
+	ID        uuid.UUID `json:"id"`
+	Event     string    `json:"event"`
+	CompanyID uuid.UUID `json:"company_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// Enqueue records one pending delivery for each active subscription listening to the event.
+// It does not send anything; call Attempt afterwards or let the retry worker pick them up.
+func Enqueue(tx *gorm.DB, companyID uuid.UUID, event string, data any) ([]*model.WebhookDelivery, error) {
+	var subscriptions []*model.WebhookSubscription
+	if err := tx.Model(&model.WebhookSubscription{}).
+		Where("company_id = ? AND is_active = ?", companyID, true).
+		Find(&subscriptions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load webhook subscriptions: %w", err)
+	}
+
+	var listening []*model.WebhookSubscription
+	for _, s := range subscriptions {
+		if s.ListensTo(event) {
+			listening = append(listening, s)
+		}
+	}
+	if len(listening) == 0 {
+		return nil, nil
+	}
+
+	payload, err := json.Marshal(Envelope{
+		ID:        uuid.New(),
+		Event:     event,
+		CompanyID: companyID,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	claimedUntil := time.Now().UTC().Add(claimLease)
+	deliveries := make([]*model.WebhookDelivery, 0, len(listening))
+	for _, s := range listening {
+		deliveries = append(deliveries, &model.WebhookDelivery{
+			SubscriptionID: s.ID,
+			CompanyID:      companyID,
+			Event:          event,
+			Payload:        datatypes.JSON(payload),
+			Status:         model.WebhookDeliveryPending,
+			NextAttemptAt:  &claimedUntil,
+		})
+	}
+	if err := tx.Create(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to create webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// Replay creates a new pending delivery carrying the same payload as the original one.
+func Replay(tx *gorm.DB, original *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	claimedUntil := time.Now().UTC().Add(claimLease)
+	replay := &model.WebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		CompanyID:      original.CompanyID,
+		Event:          original.Event,
+		Payload:        original.Payload,
+		Status:         model.WebhookDeliveryPending,
+		NextAttemptAt:  &claimedUntil,
+		ReplayOfID:     &original.ID,
+	}
+	if err := tx.Create(replay).Error; err != nil {
+		return nil, fmt.Errorf("failed to create webhook replay: %w", err)
+	}
+	return replay, nil
+}
+
+// Attempt sends a delivery once and stores the outcome. When it fails the next
+// attempt is scheduled with exponential backoff until MaxAttempts is reached.
+// A failed send is kept in delivery.LastError rather than returned; the returned
+// error is only about loading or saving the delivery.
+func Attempt(ctx context.Context, tx *gorm.DB, delivery *model.WebhookDelivery) error {
+	var subscription model.WebhookSubscription
+	if err := tx.Where("id = ?", delivery.SubscriptionID).First(&subscription).Error; err != nil {
+		return fmt.Errorf("failed to load webhook subscription %s: %w", delivery.SubscriptionID, err)
+	}
+
+	var status int
+	var sendErr error
+	if !subscription.IsActive {
+		sendErr = fmt.Errorf("webhook subscription %s is inactive", subscription.ID)
+	} else if sendErr = model.ValidateWebhookURL(subscription.URL); sendErr == nil {
+		status, sendErr = post(ctx, &subscription, delivery)
+	}
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	if sendErr == nil {
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	} else {
+		delivery.LastError = sendErr.Error()
+		if delivery.Attempts >= MaxAttempts || !subscription.IsActive {
+			delivery.Status = model.WebhookDeliveryFailed
+			delivery.NextAttemptAt = nil
+		} else {
+			next := now.Add(Backoff(delivery.Attempts))
+			delivery.Status = model.WebhookDeliveryPending
+			delivery.NextAttemptAt = &next
+		}
+	}
+
+	if err := tx.Model(delivery).Select(
+		"Attempts", "ResponseStatus", "Status", "LastError", "DeliveredAt", "NextAttemptAt",
+	).Updates(delivery).Error; err != nil {
+		return fmt.Errorf("failed to save webhook delivery %s: %w", delivery.ID, err)
+	}
+	return nil
+}
+
+func post(ctx context.Context, subscription *model.WebhookSubscription, delivery *model.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mynute-webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(SignatureHeader, BuildSignatureHeader(subscription.Secret, time.Now().Unix(), body))
+
+	resp, err := HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("subscriber responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Dispatch attempts the given deliveries on a fresh connection, one transaction each.
+func Dispatch(db *gorm.DB, schemaName string, deliveries []*model.WebhookDelivery) {
+	if len(deliveries) == 0 {
+		return
+	}
+	ctx := context.Background()
+	for _, delivery := range deliveries {
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := lib.ChangeToCompanySchema(tx, schemaName); err != nil {
+				return err
+			}
+			return Attempt(ctx, tx, delivery)
+		})
+		if err != nil {
+			log.Printf("Webhook delivery %s (%s) failed: %v", delivery.ID, delivery.Event, err)
+		} else if delivery.Status != model.WebhookDeliverySucceeded {
+			log.Printf("Webhook delivery %s (%s) failed: %s", delivery.ID, delivery.Event, delivery.LastError)
+		}
+	}
+}
+
+// RetryDue attempts every pending delivery whose next attempt is due in the given company schema.
+func RetryDue(ctx context.Context, db *gorm.DB, schemaName string, limit int) error {
+	var due []*model.WebhookDelivery
+	if err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lib.ChangeToCompanySchema(tx, schemaName); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&due).Error; err != nil {
+			return err
+		}
+		if len(due) == 0 {
+			return nil
+		}
+		ids := make([]uuid.UUID, len(due))
+		for i, d := range due {
+			ids[i] = d.ID
+		}
+		// Claim the rows so that other workers skip them while they are being sent.
+		return tx.Model(&model.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(claimLease)).Error
+	}); err != nil {
+		return err
+	}
+	Dispatch(db, schemaName, due)
+	return nil
+}
+
+// StartRetryWorker polls every company schema for due deliveries until the returned stop function is called.
+func StartRetryWorker(db *gorm.DB, interval time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				var schemas []string
+				if err := db.WithContext(ctx).Model(&model.Company{}).Pluck("schema_name", &schemas).Error; err != nil {
+					log.Printf("Webhook retry worker failed to load companies: %v", err)
+					continue
+				}
+				for _, schema := range schemas {
+					if schema == "" {
+						continue
+					}
+					if err := RetryDue(ctx, db, schema, 100); err != nil {
+						log.Printf("Webhook retry worker failed for schema %s: %v", schema, err)
+					}
+				}
+			}
+		}
+	}()
+	return cancel
+}
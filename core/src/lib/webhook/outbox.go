@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib/outbox"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EventJob is the outbox payload of a domain event to fan out to subscribers.
+type EventJob struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// DeliveryJob is the outbox payload asking for one more attempt of an existing delivery.
+type DeliveryJob struct {
+	DeliveryID uuid.UUID `json:"delivery_id"`
+}
+
+// EnqueueEvent records the event in the outbox in the given transaction.
+// data must already be the public representation (usually a DTO) of the resource.
+func EnqueueEvent(tx *gorm.DB, companyID uuid.UUID, event string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook data: %w", err)
+	}
+	return outbox.Enqueue(tx, &companyID, model.OutboxTopicWebhookEvent, EventJob{Event: event, Data: raw})
+}
+
+// EnqueueDelivery records in the outbox that the delivery must be attempted.
+func EnqueueDelivery(tx *gorm.DB, delivery *model.WebhookDelivery) error {
+	return outbox.Enqueue(tx, &delivery.CompanyID, model.OutboxTopicWebhookDelivery, DeliveryJob{DeliveryID: delivery.ID})
+}
+
+// HandleEvent is the outbox handler for model.OutboxTopicWebhookEvent. It creates the
+// deliveries and attempts each of them once; failed ones are retried by the retry worker.
+func HandleEvent(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+	var job EventJob
+	if err := outbox.Decode(msg, &job); err != nil {
+		return err
+	}
+	if msg.CompanyID == nil {
+		return fmt.Errorf("webhook event %s has no company", job.Event)
+	}
+	deliveries, err := Enqueue(tx, *msg.CompanyID, job.Event, job.Data)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		if err := Attempt(ctx, tx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleDelivery is the outbox handler for model.OutboxTopicWebhookDelivery.
+func HandleDelivery(ctx context.Context, tx *gorm.DB, msg *model.OutboxMessage) error {
+	var job DeliveryJob
+	if err := outbox.Decode(msg, &job); err != nil {
+		return err
+	}
+	var delivery model.WebhookDelivery
+	if err := tx.Where("id = ?", job.DeliveryID).First(&delivery).Error; err != nil {
+		return fmt.Errorf("failed to load webhook delivery %s: %w", job.DeliveryID, err)
+	}
+	if delivery.Status != model.WebhookDeliveryPending {
+		return nil
+	}
+	return Attempt(ctx, tx, &delivery)
+}
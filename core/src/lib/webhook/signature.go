@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Mynute-Signature"
+	EventHeader     = "X-Mynute-Event"
+	DeliveryHeader  = "X-Mynute-Delivery"
+)
+
+var (
+	ErrInvalidSignatureHeader = errors.New("invalid webhook signature header")
+	ErrSignatureMismatch      = errors.New("webhook signature mismatch")
+	ErrSignatureExpired       = errors.New("webhook signature timestamp outside tolerance")
+)
+
+// Sign computes the hex encoded HMAC-SHA256 of "<timestamp>.<body>" using the subscription secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// BuildSignatureHeader returns the value sent in the X-Mynute-Signature header,
+// e.g. "t=1700000000,v1=5257a869e7...".
+func BuildSignatureHeader(secret string, timestamp int64, body []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, Sign(secret, timestamp, body))
+}
+
+// Verify checks a X-Mynute-Signature header against the body. A zero tolerance
+// disables the timestamp check. Receivers can use it as a reference implementation.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrInvalidSignatureHeader
+		}
+		switch key {
+		case "t":
+			ts, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidSignatureHeader
+			}
+			timestamp = ts
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrInvalidSignatureHeader
+	}
+	if tolerance > 0 {
+		diff := now.Sub(time.Unix(timestamp, 0))
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > tolerance {
+			return ErrSignatureExpired
+		}
+	}
+	expected := Sign(secret, timestamp, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrSignatureMismatch
+}
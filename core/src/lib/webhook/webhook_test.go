@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"mynute-go/core/src/config/db/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := "my-secret"
+	body := []byte(`{"event":"appointment.created"}`)
+	now := time.Unix(1700000000, 0)
+
+	header := BuildSignatureHeader(secret, now.Unix(), body)
+	assert.Contains(t, header, "t=1700000000,v1=")
+
+	assert.NoError(t, Verify(secret, header, body, 5*time.Minute, now))
+	assert.ErrorIs(t, Verify("other-secret", header, body, 5*time.Minute, now), ErrSignatureMismatch)
+	assert.ErrorIs(t, Verify(secret, header, []byte(`{}`), 5*time.Minute, now), ErrSignatureMismatch)
+	assert.ErrorIs(t, Verify(secret, header, body, 5*time.Minute, now.Add(10*time.Minute)), ErrSignatureExpired)
+	assert.NoError(t, Verify(secret, header, body, 0, now.Add(10*time.Minute)))
+}
+
+func TestVerify_InvalidHeader(t *testing.T) {
+	for _, header := range []string{"", "t=abc,v1=00", "v1=00", "t=1700000000", "garbage"} {
+		assert.ErrorIs(t, Verify("s", header, nil, 0, time.Now()), ErrInvalidSignatureHeader, header)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Duration(0), Backoff(0))
+	assert.Equal(t, BaseDelay, Backoff(1))
+	assert.Equal(t, 2*BaseDelay, Backoff(2))
+	assert.Equal(t, 8*BaseDelay, Backoff(4))
+	assert.Equal(t, MaxDelay, Backoff(MaxAttempts*4))
+
+	for attempt := 1; attempt < MaxAttempts; attempt++ {
+		assert.LessOrEqual(t, Backoff(attempt), Backoff(attempt+1))
+	}
+}
+
+func TestPost_SendsSignedRequest(t *testing.T) {
+	subscription := &model.WebhookSubscription{URL: "", Secret: "shh", IsActive: true}
+	delivery := &model.WebhookDelivery{
+		BaseModel: model.BaseModel{ID: uuid.New()},
+		Event:     model.WebhookEventAppointmentCreated,
+		Payload:   datatypes.JSON(`{"event":"appointment.created"}`),
+	}
+
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	useClient(t, server.Client())
+	subscription.URL = server.URL
+
+	status, err := post(context.Background(), subscription, delivery)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Equal(t, model.WebhookEventAppointmentCreated, received.Header.Get(EventHeader))
+	assert.Equal(t, delivery.ID.String(), received.Header.Get(DeliveryHeader))
+	assert.Equal(t, []byte(delivery.Payload), receivedBody)
+	assert.NoError(t, Verify("shh", received.Header.Get(SignatureHeader), receivedBody, time.Minute, time.Now()))
+}
+
+func TestPost_NonSuccessStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	useClient(t, server.Client())
+
+	subscription := &model.WebhookSubscription{URL: server.URL, Secret: "shh", IsActive: true}
+	delivery := &model.WebhookDelivery{Event: model.WebhookEventClientCreated, Payload: datatypes.JSON(`{}`)}
+
+	status, err := post(context.Background(), subscription, delivery)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadGateway, status)
+}
+
+func TestWebhookEventListValidate(t *testing.T) {
+	assert.NoError(t, model.WebhookEventList{model.WebhookEventAppointmentCreated, model.WebhookEventEmployeeUpdated}.Validate())
+	assert.Error(t, model.WebhookEventList{}.Validate())
+	assert.Error(t, model.WebhookEventList{"appointment.deleted"}.Validate())
+}
+
+func TestPost_RefusesInternalAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	subscription := &model.WebhookSubscription{URL: server.URL, Secret: "shh", IsActive: true}
+	delivery := &model.WebhookDelivery{Event: model.WebhookEventClientCreated, Payload: datatypes.JSON(`{}`)}
+
+	status, err := post(context.Background(), subscription, delivery)
+	assert.Error(t, err, "the default client does not dial loopback addresses")
+	assert.Equal(t, 0, status)
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	assert.NoError(t, model.ValidateWebhookURL("https://93.184.216.34/hooks"))
+	for _, raw := range []string{
+		"http://93.184.216.34/hooks",
+		"ftp://93.184.216.34/hooks",
+		"https:///hooks",
+		"https://localhost/hooks",
+		"https://api.localhost/hooks",
+		"https://127.0.0.1/hooks",
+		"https://[::1]/hooks",
+		"https://10.0.0.5/hooks",
+		"https://192.168.1.10:8443/hooks",
+		"https://172.16.0.1/hooks",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[fe80::1]/hooks",
+		"https://0.0.0.0/hooks",
+	} {
+		assert.Error(t, model.ValidateWebhookURL(raw), raw)
+	}
+}
+
+// useClient makes deliveries go through client until the test ends.
+func useClient(t *testing.T, client *http.Client) {
+	previous := HTTPClient
+	HTTPClient = client
+	t.Cleanup(func() { HTTPClient = previous })
+}
@@ -121,6 +121,13 @@ func DenyUnauthorized(c *fiber.Ctx) error {
 		return lib.Error.Auth.InvalidToken.WithError(fmt.Errorf("token possibly has outdated password for %s with ID '%s'", userIs, claim.ID))
 	}
 
+	// Suspended employees lose the sessions they already have
+	if gate, ok := user.(interface{ CanLogin() error }); ok {
+		if err := gate.CanLogin(); err != nil {
+			return err
+		}
+	}
+
 	jsonDataSubject, errSub := json.Marshal(user)
 	if errSub != nil {
 		log.Printf("Error marshaling subject: %v", errSub)
@@ -0,0 +1,108 @@
+package availability
+
+import (
+	"mynute-go/core/src/config/db/model"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Candidate is an employee free for a slot, with what the assignment strategies weigh.
+type Candidate struct {
+	EmployeeID    uuid.UUID
+	BookedThatDay int       // Appointments not cancelled on the day of the slot
+	LastAssigned  time.Time // Creation of the last appointment of the service with the employee, zero if none
+	LastServed    time.Time // Start of the last appointment of the client with the employee, zero if none
+}
+
+// Rank orders the candidates by the employee assignment strategy of a service, best first.
+// Ties keep the order of the candidates.
+func Rank(strategy string, candidates []Candidate) []uuid.UUID {
+	sorted := slices.Clone(candidates)
+	switch strategy {
+	case model.AssignRoundRobin:
+		slices.SortStableFunc(sorted, func(a, b Candidate) int { return a.LastAssigned.Compare(b.LastAssigned) })
+	case model.AssignPreferred:
+		slices.SortStableFunc(sorted, func(a, b Candidate) int {
+			if c := b.LastServed.Compare(a.LastServed); c != 0 {
+				return c
+			}
+			return a.BookedThatDay - b.BookedThatDay
+		})
+	default:
+		slices.SortStableFunc(sorted, func(a, b Candidate) int { return a.BookedThatDay - b.BookedThatDay })
+	}
+	ids := make([]uuid.UUID, len(sorted))
+	for i, c := range sorted {
+		ids[i] = c.EmployeeID
+	}
+	return ids
+}
+
+// Suggestion is a free slot with its employees ranked by the assignment strategy of the service.
+type Suggestion struct {
+	Slot
+	Branch model.Branch
+	Terms  model.ServiceTerms // Price and duration with the first employee
+}
+
+// Ranked returns the employees free for the slot starting at start at the branch, best
+// first for the assignment strategy of the service, or none when the slot is not free.
+// The slot is looked up on its day in the time zone of the query.
+func (e *Engine) Ranked(store Store, q Query, branchID uuid.UUID, start time.Time) ([]uuid.UUID, error) {
+	q.From, q.To, q.BranchID = start, start, branchID
+	res, err := e.Find(store, q)
+	if err != nil {
+		return nil, err
+	}
+	for _, slot := range res.Slots {
+		if slot.Start.Equal(start) && slot.BranchID == branchID {
+			suggestion, err := rank(store, q, res, slot)
+			if err != nil {
+				return nil, err
+			}
+			return suggestion.EmployeeIDs, nil
+		}
+	}
+	return nil, nil
+}
+
+// Next returns the first free slot of the query, nil when there is none. Days are
+// searched a week at a time so the search stops early.
+func (e *Engine) Next(store Store, q Query) (*Suggestion, error) {
+	start, end := q.Span()
+	last := end.AddDate(0, 0, -1)
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 7) {
+		week := q
+		week.From, week.To = day, day.AddDate(0, 0, 6)
+		if week.To.After(last) {
+			week.To = last
+		}
+		res, err := e.Find(store, week)
+		if err != nil {
+			return nil, err
+		}
+		if len(res.Slots) > 0 {
+			return rank(store, week, res, res.Slots[0])
+		}
+	}
+	return nil, nil
+}
+
+// rank orders the employees of the slot with the statistics of the day of the slot.
+func rank(store Store, q Query, res *Result, slot Slot) (*Suggestion, error) {
+	loc := q.location()
+	local := slot.Start.In(loc)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	candidates, err := store.Candidates(q.ServiceID, q.ClientID, slot.EmployeeIDs, dayStart, dayStart.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	slot.EmployeeIDs = Rank(res.service.EmployeeAssignment, candidates)
+	suggestion := &Suggestion{Slot: slot, Branch: res.Branches[slot.BranchID]}
+	if len(slot.EmployeeIDs) > 0 {
+		suggestion.Terms = res.Terms(slot.EmployeeIDs[0], slot.BranchID)
+	}
+	return suggestion, nil
+}
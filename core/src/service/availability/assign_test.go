@@ -0,0 +1,91 @@
+package availability
+
+import (
+	"mynute-go/core/src/config/db/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRank(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	candidates := []Candidate{
+		{EmployeeID: a, BookedThatDay: 3, LastAssigned: at(9, 0)},
+		{EmployeeID: b, BookedThatDay: 1, LastAssigned: at(10, 0), LastServed: at(8, 0)},
+		{EmployeeID: c, BookedThatDay: 1},
+	}
+
+	assert.Equal(t, []uuid.UUID{b, c, a}, Rank(model.AssignLeastBooked, candidates))
+	assert.Equal(t, []uuid.UUID{b, c, a}, Rank("", candidates), "least booked by default")
+	assert.Equal(t, []uuid.UUID{c, a, b}, Rank(model.AssignRoundRobin, candidates), "never assigned first")
+	assert.Equal(t, []uuid.UUID{b, c, a}, Rank(model.AssignPreferred, candidates))
+
+	candidates[0].LastServed = at(9, 0)
+	assert.Equal(t, []uuid.UUID{a, b, c}, Rank(model.AssignPreferred, candidates), "last served first")
+	assert.Equal(t, a, candidates[0].EmployeeID, "the candidates are not reordered")
+}
+
+func TestRankedAndNext(t *testing.T) {
+	now := at(0, 0)
+	engine := &Engine{Now: func() time.Time { return now }}
+
+	// Employees A and B at the same branch, both free at 11:00, B less booked
+	sharedBranch := func() *fakeStore {
+		return &fakeStore{candidates: map[uuid.UUID]Candidate{employeeA: {BookedThatDay: 2}, employeeB: {BookedThatDay: 0}}}
+	}
+
+	t.Run("should rank the employees free for the slot", func(t *testing.T) {
+		store := &sharedStore{fakeStore: sharedBranch()}
+		ranked, err := engine.Ranked(store, query(), branchX, at(11, 0))
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{employeeB, employeeA}, ranked)
+
+		ranked, err = engine.Ranked(store, query(), branchX, at(10, 0))
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{employeeB}, ranked, "employee A is busy")
+
+		ranked, err = engine.Ranked(store, query(), branchX, at(10, 15))
+		require.NoError(t, err)
+		assert.Empty(t, ranked, "not a slot")
+	})
+
+	t.Run("should find the first free slot", func(t *testing.T) {
+		q := query()
+		q.From, q.To = monday.AddDate(0, 0, -3), monday.AddDate(0, 0, 30)
+		next, err := engine.Next(&fakeStore{}, q)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.Equal(t, at(9, 0), next.Start)
+		assert.Equal(t, []uuid.UUID{employeeA}, next.EmployeeIDs)
+		assert.Equal(t, uint16(60), next.Terms.Duration)
+
+		now = at(11, 30)
+		defer func() { now = at(0, 0) }()
+		next, err = engine.Next(&fakeStore{}, q)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.Equal(t, at(9, 0).AddDate(0, 0, 7), next.Start, "on the next monday")
+	})
+
+	t.Run("should return nil without free slots", func(t *testing.T) {
+		q := query()
+		q.From, q.To = monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 6)
+		next, err := engine.Next(&fakeStore{}, q)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+	})
+}
+
+// sharedStore moves employee B to branch X.
+type sharedStore struct {
+	*fakeStore
+}
+
+func (s *sharedStore) Schedule(serviceID uuid.UUID, from, to time.Time) (*Schedule, error) {
+	schedule, err := s.fakeStore.Schedule(serviceID, from, to)
+	schedule.Ranges[1].BranchID, schedule.Ranges[1].Branch.ID = branchX, branchX
+	return schedule, err
+}
@@ -0,0 +1,268 @@
+// Package availability computes the free slots of a service from the work ranges,
+// densities and appointments of the employees offering it.
+//
+// The data comes from a Store, so the computation runs without HTTP or a database.
+// Results are cached per company until Invalidate is called for it or they expire.
+package availability
+
+import (
+	"fmt"
+	"mynute-go/core/src/config/db/model"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Query selects the slots to compute.
+type Query struct {
+	CompanyID   uuid.UUID
+	ServiceID   uuid.UUID
+	From        time.Time      // First day, any time of it in Location
+	To          time.Time      // Last day, included
+	Location    *time.Location // Time zone of the slots and days, UTC when nil
+	BranchID    uuid.UUID      // Only slots at this branch, uuid.Nil for every branch
+	EmployeeID  uuid.UUID      // Only slots with this employee, uuid.Nil for every employee
+	ClientID    uuid.UUID      // Hides the employees of slots overlapping the client's appointments, in any company
+	DependentID *uuid.UUID     // With ClientID, the appointments of this dependent of the client instead
+}
+
+// location returns the time zone of the query.
+func (q *Query) location() *time.Location {
+	if q.Location == nil {
+		return time.UTC
+	}
+	return q.Location
+}
+
+// Span returns the start of the first day and the end of the last day of the query.
+func (q *Query) Span() (time.Time, time.Time) {
+	loc := q.location()
+	from, to := q.From.In(loc), q.To.In(loc)
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	return start, end
+}
+
+func (q *Query) key() string {
+	start, end := q.Span()
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s", q.ServiceID, start.Format(time.RFC3339), end.Format(time.RFC3339), q.location(), q.BranchID, q.EmployeeID)
+}
+
+// Interval is a time an employee or client is busy, [Start, End).
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (i Interval) overlaps(start, end time.Time) bool {
+	return start.Before(i.End) && end.After(i.Start)
+}
+
+// Schedule is what the slots of a service are computed from.
+type Schedule struct {
+	Service   model.Service
+	Overrides []model.ServiceOverride
+	Ranges    []model.EmployeeWorkRange // Work ranges offering the service, with Employee and Branch loaded
+	Densities map[uuid.UUID]uint32      // Density of the service per employee, instead of Employee.TotalServiceDensity
+	Busy      map[uuid.UUID][]Interval  // Appointments not cancelled, per employee
+	Needs     []model.ResourceNeed      // Resources the service holds, at every branch
+	Uses      []model.ResourceUse       // What appointments not cancelled hold of those resources
+	Company   model.BookingWindow       // Booking window of the company, the service and branches override it
+
+	// Work ranges per branch, slots must fit in one of their branch. Nil skips the check
+	Opening map[uuid.UUID][]model.BranchWorkRange
+}
+
+// Slot is a start time at a branch with the employees free to take it.
+type Slot struct {
+	Start       time.Time // In the query time zone
+	BranchID    uuid.UUID
+	EmployeeIDs []uuid.UUID
+}
+
+// Result is the availability of a service.
+type Result struct {
+	ServiceID uuid.UUID
+	Slots     []Slot                       // Sorted by start time, then branch
+	Employees map[uuid.UUID]model.Employee // Employees with at least one slot
+	Branches  map[uuid.UUID]model.Branch   // Branches with at least one slot
+
+	service   model.Service
+	overrides []model.ServiceOverride
+	terms     map[[2]uuid.UUID]model.ServiceTerms
+	company   model.BookingWindow
+}
+
+// Terms returns the price and duration of the service for the employee at the branch.
+func (r *Result) Terms(employeeID, branchID uuid.UUID) model.ServiceTerms {
+	key := [2]uuid.UUID{employeeID, branchID}
+	if t, ok := r.terms[key]; ok {
+		return t
+	}
+	return r.service.TermsFor(r.overrides, employeeID, branchID)
+}
+
+// Compute lists every slot of the schedule within the query, past ones included.
+// Slots are laid out by the slot rules of the service, by default on the slot interval
+// of each employee counted from midnight in the time zone of the branch, and end within
+// the work range. A slot is free while the appointments overlapping it are fewer than
+// the density of the employee and the resources the service holds at the branch are
+// not fully held.
+func Compute(q Query, s *Schedule) *Result {
+	loc := q.location()
+	start, end := q.Span()
+	res := &Result{
+		ServiceID: s.Service.ID,
+		Employees: map[uuid.UUID]model.Employee{},
+		Branches:  map[uuid.UUID]model.Branch{},
+		service:   s.Service,
+		overrides: s.Overrides,
+		terms:     map[[2]uuid.UUID]model.ServiceTerms{},
+		company:   s.Company,
+	}
+	type slotKey struct {
+		start    int64
+		branchID uuid.UUID
+	}
+	slots := map[slotKey]*Slot{}
+	needs := map[uuid.UUID][]model.ResourceNeed{}
+	for _, n := range s.Needs {
+		needs[n.BranchID] = append(needs[n.BranchID], n)
+	}
+	busy := map[uuid.UUID][]model.TimeSpan{}
+	for employeeID, intervals := range s.Busy {
+		for _, i := range intervals {
+			busy[employeeID] = append(busy[employeeID], model.TimeSpan{Start: i.Start, End: i.End})
+		}
+	}
+
+	// Ranges spanning midnight started the day before may reach the first day
+	for d := start.AddDate(0, 0, -1); d.Before(end); d = d.AddDate(0, 0, 1) {
+		for _, wr := range s.Ranges {
+			if wr.Weekday != d.Weekday() {
+				continue
+			}
+			if (q.BranchID != uuid.Nil && wr.BranchID != q.BranchID) || (q.EmployeeID != uuid.Nil && wr.EmployeeID != q.EmployeeID) {
+				continue
+			}
+			emp := wr.Employee
+			step := s.Service.SlotRules.Step(&emp)
+			if step <= 0 {
+				continue
+			}
+			terms := res.Terms(emp.ID, wr.BranchID)
+			res.terms[[2]uuid.UUID{emp.ID, wr.BranchID}] = terms
+			duration := time.Duration(terms.Duration) * time.Minute
+
+			capacity := emp.TotalServiceDensity
+			if density, ok := s.Densities[emp.ID]; ok {
+				capacity = density
+			}
+
+			// The work range is in the time zone of its branch
+			branchLoc, err := time.LoadLocation(wr.Branch.TimeZone)
+			if err != nil {
+				branchLoc = time.UTC
+			}
+			shift := model.Shift{Capacity: capacity, Busy: busy[emp.ID]}
+			shift.Start, shift.End = wr.On(d, branchLoc)
+			shift.Free = func(start, end time.Time) bool {
+				if s.Opening != nil && !slices.ContainsFunc(s.Opening[wr.BranchID], func(br model.BranchWorkRange) bool {
+					_, _, ok := br.ShiftContaining(start, end)
+					return ok
+				}) {
+					return false
+				}
+				return !slices.ContainsFunc(needs[wr.BranchID], func(n model.ResourceNeed) bool { return !n.FreeBetween(s.Uses, start, end) })
+			}
+
+			for _, slot := range s.Service.SlotRules.Starts(shift, step, duration) {
+				slot = slot.In(loc)
+				if slot.Before(start) || !slot.Before(end) {
+					continue
+				}
+
+				key := slotKey{slot.Unix(), wr.BranchID}
+				found, ok := slots[key]
+				if !ok {
+					found = &Slot{Start: slot, BranchID: wr.BranchID}
+					slots[key] = found
+				}
+				if !slices.Contains(found.EmployeeIDs, emp.ID) {
+					found.EmployeeIDs = append(found.EmployeeIDs, emp.ID)
+				}
+				res.Employees[emp.ID] = emp
+				res.Branches[wr.BranchID] = wr.Branch
+			}
+		}
+	}
+
+	for _, slot := range slots {
+		slices.SortFunc(slot.EmployeeIDs, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+		res.Slots = append(res.Slots, *slot)
+	}
+	slices.SortFunc(res.Slots, func(a, b Slot) int {
+		if c := a.Start.Compare(b.Start); c != 0 {
+			return c
+		}
+		return strings.Compare(a.BranchID.String(), b.BranchID.String())
+	})
+	return res
+}
+
+// After returns a copy of the result without the slots starting before t.
+func (r *Result) After(t time.Time) *Result {
+	out := *r
+	out.Slots = nil
+	for _, slot := range r.Slots {
+		if !slot.Start.Before(t) {
+			out.Slots = append(out.Slots, slot)
+		}
+	}
+	return &out
+}
+
+// Bookable returns a copy of the result without the slots outside the booking window
+// of the service at their branch at now.
+func (r *Result) Bookable(now time.Time) *Result {
+	out := *r
+	out.Slots = nil
+	for _, slot := range r.Slots {
+		branch := r.Branches[slot.BranchID]
+		loc, err := time.LoadLocation(branch.TimeZone)
+		if err != nil {
+			loc = time.UTC
+		}
+		window := model.ResolveBookingWindow(r.service.BookingWindow, branch.BookingWindow, r.company)
+		if window.Check(slot.Start, now, loc) == nil {
+			out.Slots = append(out.Slots, slot)
+		}
+	}
+	return &out
+}
+
+// WithoutClient returns a copy of the result without the employees whose slot
+// would overlap an appointment of the client. Slots left without employees are dropped.
+func (r *Result) WithoutClient(busy []Interval) *Result {
+	if len(busy) == 0 {
+		return r
+	}
+	out := *r
+	out.Slots = nil
+	for _, slot := range r.Slots {
+		var free []uuid.UUID
+		for _, employeeID := range slot.EmployeeIDs {
+			end := slot.Start.Add(time.Duration(r.Terms(employeeID, slot.BranchID).Duration) * time.Minute)
+			if !slices.ContainsFunc(busy, func(i Interval) bool { return i.overlaps(slot.Start, end) }) {
+				free = append(free, employeeID)
+			}
+		}
+		if len(free) > 0 {
+			slot.EmployeeIDs = free
+			out.Slots = append(out.Slots, slot)
+		}
+	}
+	return &out
+}
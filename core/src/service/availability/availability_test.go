@@ -0,0 +1,377 @@
+package availability
+
+import (
+	"mynute-go/core/src/config/db/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	companyID = uuid.MustParse("00000000-0000-0000-0000-0000000000c1")
+	serviceID = uuid.MustParse("00000000-0000-0000-0000-0000000000f1")
+	branchX   = uuid.MustParse("00000000-0000-0000-0000-0000000000b1")
+	branchY   = uuid.MustParse("00000000-0000-0000-0000-0000000000b2")
+	employeeA = uuid.MustParse("00000000-0000-0000-0000-0000000000e1")
+	employeeB = uuid.MustParse("00000000-0000-0000-0000-0000000000e2")
+	clientID  = uuid.MustParse("00000000-0000-0000-0000-0000000000a1")
+	monday    = time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+)
+
+func at(hour, minute int) time.Time {
+	return monday.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func workRange(employee model.Employee, branchID uuid.UUID, start, end time.Time) model.EmployeeWorkRange {
+	wr := model.EmployeeWorkRange{EmployeeID: employee.ID, Employee: employee}
+	wr.Weekday = time.Monday
+	wr.StartTime, wr.EndTime = start, end
+	wr.BranchID = branchID
+	wr.Branch.ID = branchID
+	wr.Branch.TimeZone = "UTC"
+	return wr
+}
+
+// schedule has employee A at branch X from 9:00 to 12:00 every 30 minutes, busy from
+// 10:00 to 11:00, and employee B at branch Y from 10:00 to 12:00 every hour, where
+// the 60 minutes service lasts 30.
+func schedule() *Schedule {
+	a := model.Employee{SlotTimeDiff: 30, TotalServiceDensity: 1}
+	a.ID = employeeA
+	b := model.Employee{SlotTimeDiff: 60, TotalServiceDensity: 1}
+	b.ID = employeeB
+
+	s := &Schedule{
+		Service:   model.Service{Duration: 60},
+		Densities: map[uuid.UUID]uint32{},
+		Busy:      map[uuid.UUID][]Interval{employeeA: {{Start: at(10, 0), End: at(11, 0)}}},
+	}
+	s.Service.ID = serviceID
+	thirty := uint16(30)
+	s.Overrides = []model.ServiceOverride{{ServiceID: serviceID, EmployeeID: &employeeB, Duration: &thirty}}
+	s.Ranges = []model.EmployeeWorkRange{
+		workRange(a, branchX, at(9, 0), at(12, 0)),
+		workRange(b, branchY, at(10, 0), at(12, 0)),
+	}
+	return s
+}
+
+func query() Query {
+	return Query{CompanyID: companyID, ServiceID: serviceID, From: monday, To: monday}
+}
+
+type slotView struct {
+	Start     string
+	Branch    uuid.UUID
+	Employees []uuid.UUID
+}
+
+func view(r *Result) []slotView {
+	var out []slotView
+	for _, s := range r.Slots {
+		out = append(out, slotView{s.Start.Format("15:04"), s.BranchID, s.EmployeeIDs})
+	}
+	return out
+}
+
+func TestCompute(t *testing.T) {
+	t.Run("should list the free slots of every work range", func(t *testing.T) {
+		res := Compute(query(), schedule())
+		assert.Equal(t, []slotView{
+			{"09:00", branchX, []uuid.UUID{employeeA}},
+			{"10:00", branchY, []uuid.UUID{employeeB}},
+			{"11:00", branchX, []uuid.UUID{employeeA}},
+			{"11:00", branchY, []uuid.UUID{employeeB}},
+		}, view(res))
+		assert.Len(t, res.Employees, 2)
+		assert.Len(t, res.Branches, 2)
+		assert.Equal(t, uint16(30), res.Terms(employeeB, branchY).Duration)
+		assert.Equal(t, uint16(60), res.Terms(employeeA, branchX).Duration)
+	})
+
+	t.Run("should keep slots below the density of the employee", func(t *testing.T) {
+		s := schedule()
+		s.Densities[employeeA] = 2
+		res := Compute(Query{CompanyID: companyID, ServiceID: serviceID, From: monday, To: monday, EmployeeID: employeeA}, s)
+		assert.Equal(t, []slotView{
+			{"09:00", branchX, []uuid.UUID{employeeA}},
+			{"09:30", branchX, []uuid.UUID{employeeA}},
+			{"10:00", branchX, []uuid.UUID{employeeA}},
+			{"10:30", branchX, []uuid.UUID{employeeA}},
+			{"11:00", branchX, []uuid.UUID{employeeA}},
+		}, view(res))
+	})
+
+	t.Run("should group the employees of a slot", func(t *testing.T) {
+		s := schedule()
+		s.Ranges[1].BranchID, s.Ranges[1].Branch.ID = branchX, branchX
+		res := Compute(query(), s)
+		assert.Equal(t, slotView{"11:00", branchX, []uuid.UUID{employeeA, employeeB}}, view(res)[2])
+	})
+
+	t.Run("should keep slots within the opening hours of the branch", func(t *testing.T) {
+		s := schedule()
+		open := model.BranchWorkRange{}
+		open.Weekday, open.TimeZone, open.BranchID = time.Monday, "UTC", branchX
+		open.StartTime, open.EndTime = at(9, 0), at(11, 0)
+		s.Opening = map[uuid.UUID][]model.BranchWorkRange{branchX: {open}}
+		res := Compute(query(), s)
+		assert.Equal(t, []slotView{
+			{"09:00", branchX, []uuid.UUID{employeeA}},
+		}, view(res), "branch Y has no opening hours left")
+	})
+
+	t.Run("should skip slots whose resources are fully held", func(t *testing.T) {
+		s := schedule()
+		room := uuid.New()
+		s.Needs = []model.ResourceNeed{{ResourceID: room, BranchID: branchX, Name: "Room", Quantity: 1, Capacity: 2}}
+		s.Uses = []model.ResourceUse{
+			{ResourceID: room, Quantity: 1, StartTime: at(8, 30), EndTime: at(9, 30)},
+			{ResourceID: room, Quantity: 1, StartTime: at(9, 30), EndTime: at(10, 0)},
+			{ResourceID: room, Quantity: 1, StartTime: at(11, 30), EndTime: at(12, 0)},
+		}
+		assert.Len(t, Compute(query(), s).Slots, 4, "one of the two rooms is always left")
+
+		s.Uses = append(s.Uses, model.ResourceUse{ResourceID: room, Quantity: 1, StartTime: at(11, 0), EndTime: at(11, 45)})
+		assert.Equal(t, []slotView{
+			{"09:00", branchX, []uuid.UUID{employeeA}},
+			{"10:00", branchY, []uuid.UUID{employeeB}},
+			{"11:00", branchY, []uuid.UUID{employeeB}},
+		}, view(Compute(query(), s)), "both rooms are held from 11:30, the other branch has none")
+	})
+
+	t.Run("should follow the slot interval and alignment of the service", func(t *testing.T) {
+		s := schedule()
+		s.Service.SlotInterval = 45
+		s.Ranges = s.Ranges[:1]
+		s.Ranges[0].StartTime = at(9, 10)
+		s.Busy = nil
+		assert.Equal(t, []slotView{
+			{"09:45", branchX, []uuid.UUID{employeeA}},
+			{"10:30", branchX, []uuid.UUID{employeeA}},
+		}, view(Compute(query(), s)), "starts are multiples of 45 minutes since midnight")
+
+		s.Service.SlotAlignment = model.AlignShiftStart
+		assert.Equal(t, []slotView{
+			{"09:10", branchX, []uuid.UUID{employeeA}},
+			{"09:55", branchX, []uuid.UUID{employeeA}},
+			{"10:40", branchX, []uuid.UUID{employeeA}},
+		}, view(Compute(query(), s)), "starts are multiples of 45 minutes since the start of the range")
+	})
+
+	t.Run("should prefer slots touching appointments when optimizing gaps", func(t *testing.T) {
+		s := schedule()
+		s.Service.SlotInterval = 15
+		s.Service.OptimizeGaps = true
+		s.Ranges = s.Ranges[:1]
+		assert.Equal(t, []slotView{
+			{"09:00", branchX, []uuid.UUID{employeeA}},
+			{"11:00", branchX, []uuid.UUID{employeeA}},
+		}, view(Compute(query(), s)), "the 9:00 slot ends as the appointment starts")
+
+		s.Busy = map[uuid.UUID][]Interval{employeeA: {{Start: at(8, 50), End: at(9, 5)}, {Start: at(8, 55), End: at(9, 10)}}}
+		s.Ranges[0].EndTime = at(11, 10)
+		s.Densities[employeeA] = 2
+		assert.Equal(t, []slotView{
+			{"09:15", branchX, []uuid.UUID{employeeA}},
+			{"09:30", branchX, []uuid.UUID{employeeA}},
+			{"09:45", branchX, []uuid.UUID{employeeA}},
+			{"10:00", branchX, []uuid.UUID{employeeA}},
+		}, view(Compute(query(), s)), "every slot is kept when none touches")
+	})
+
+	t.Run("should list the slots of ranges spanning midnight", func(t *testing.T) {
+		s := schedule()
+		s.Ranges = s.Ranges[:1]
+		s.Ranges[0].Weekday = time.Sunday
+		s.Ranges[0].StartTime, s.Ranges[0].EndTime = at(22, 0), at(2, 0)
+		assert.Equal(t, []slotView{
+			{"00:00", branchX, []uuid.UUID{employeeA}},
+			{"00:30", branchX, []uuid.UUID{employeeA}},
+			{"01:00", branchX, []uuid.UUID{employeeA}},
+		}, view(Compute(query(), s)), "the range started on Sunday runs into Monday")
+
+		q := query()
+		q.From, q.To = monday.AddDate(0, 0, -1), monday.AddDate(0, 0, -1)
+		assert.Len(t, Compute(q, s).Slots, 4, "Sunday keeps the slots starting before midnight")
+	})
+
+	t.Run("should filter by branch and employee", func(t *testing.T) {
+		q := query()
+		q.BranchID = branchY
+		for _, slot := range Compute(q, schedule()).Slots {
+			assert.Equal(t, branchY, slot.BranchID)
+		}
+		q = query()
+		q.EmployeeID = employeeA
+		res := Compute(q, schedule())
+		assert.Len(t, res.Slots, 2)
+		assert.NotContains(t, res.Employees, employeeB)
+	})
+
+	t.Run("should use the time zone of the query", func(t *testing.T) {
+		loc, err := time.LoadLocation("America/Sao_Paulo")
+		require.NoError(t, err)
+		q := query()
+		q.From, q.To, q.Location = at(12, 0), at(12, 0), loc
+		res := Compute(q, schedule())
+		require.NotEmpty(t, res.Slots)
+		assert.Equal(t, "06:00", res.Slots[0].Start.Format("15:04"))
+		assert.Equal(t, loc, res.Slots[0].Start.Location())
+	})
+
+	t.Run("should skip days without work ranges", func(t *testing.T) {
+		q := query()
+		q.From, q.To = monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 6)
+		assert.Empty(t, Compute(q, schedule()).Slots)
+	})
+}
+
+func TestResultFilters(t *testing.T) {
+	res := Compute(query(), schedule())
+
+	t.Run("should hide past slots", func(t *testing.T) {
+		after := res.After(at(10, 30))
+		assert.Len(t, after.Slots, 2)
+		assert.Len(t, res.Slots, 4, "the cached result is not changed")
+	})
+
+	t.Run("should hide slots outside the booking window", func(t *testing.T) {
+		notice, days, cutoff, later := uint32(90), uint16(1), "08:00", "10:00"
+		s := schedule()
+		s.Service.MinNoticeMinutes = &notice
+		s.Company.SameDayCutoff = &cutoff
+		s.Ranges[1].Branch.SameDayCutoff = &later
+		s.Company.MaxDaysAhead = &days
+		windowed := Compute(query(), s)
+
+		assert.Equal(t, []slotView{
+			{"10:00", branchY, []uuid.UUID{employeeB}},
+			{"11:00", branchX, []uuid.UUID{employeeA}},
+			{"11:00", branchY, []uuid.UUID{employeeB}},
+		}, view(windowed.Bookable(at(7, 45))), "the notice hides 09:00")
+		assert.Equal(t, []slotView{
+			{"11:00", branchY, []uuid.UUID{employeeB}},
+		}, view(windowed.Bookable(at(9, 0))), "branch X is closed for today, branch Y closes at 10:00")
+		assert.Len(t, windowed.Bookable(monday.AddDate(0, 0, -1)).Slots, 4, "tomorrow is within a day ahead")
+		assert.Empty(t, windowed.Bookable(monday.AddDate(0, 0, -2)).Slots, "the day after tomorrow is too far ahead")
+	})
+
+	t.Run("should hide the employees overlapping the client", func(t *testing.T) {
+		// Employee A would end at 12:00, employee B at 11:30
+		free := res.WithoutClient([]Interval{{Start: at(11, 40), End: at(12, 30)}})
+		assert.Equal(t, []slotView{
+			{"09:00", branchX, []uuid.UUID{employeeA}},
+			{"10:00", branchY, []uuid.UUID{employeeB}},
+			{"11:00", branchY, []uuid.UUID{employeeB}},
+		}, view(free))
+	})
+}
+
+type fakeStore struct {
+	schedules  int
+	clients    int
+	busy       []Interval
+	candidates map[uuid.UUID]Candidate
+}
+
+func (f *fakeStore) Schedule(uuid.UUID, time.Time, time.Time) (*Schedule, error) {
+	f.schedules++
+	return schedule(), nil
+}
+
+func (f *fakeStore) ClientBusy(uuid.UUID, *uuid.UUID, time.Time, time.Time) ([]Interval, error) {
+	f.clients++
+	return f.busy, nil
+}
+
+func (f *fakeStore) Candidates(_, _ uuid.UUID, employeeIDs []uuid.UUID, _, _ time.Time) ([]Candidate, error) {
+	var candidates []Candidate
+	for _, id := range employeeIDs {
+		c := f.candidates[id]
+		c.EmployeeID = id
+		candidates = append(candidates, c)
+	}
+	return candidates, nil
+}
+
+func TestEngine(t *testing.T) {
+	now := at(0, 0)
+	clock := func() time.Time { return now }
+	newEngine := func(ttl time.Duration) *Engine {
+		cache := NewCache(ttl)
+		cache.now = clock
+		return &Engine{Cache: cache, Now: clock}
+	}
+
+	t.Run("should cache the schedule per query", func(t *testing.T) {
+		engine, store := newEngine(time.Minute), &fakeStore{}
+		for range 3 {
+			_, err := engine.Find(store, query())
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, store.schedules)
+
+		q := query()
+		q.BranchID = branchX
+		_, err := engine.Find(store, q)
+		require.NoError(t, err)
+		assert.Equal(t, 2, store.schedules)
+	})
+
+	t.Run("should filter past slots and the client on every call", func(t *testing.T) {
+		engine, store := newEngine(24*time.Hour), &fakeStore{busy: []Interval{{Start: at(9, 0), End: at(9, 30)}}}
+		q := query()
+		q.ClientID = clientID
+		res, err := engine.Find(store, q)
+		require.NoError(t, err)
+		assert.Len(t, res.Slots, 3)
+
+		now = at(10, 30)
+		defer func() { now = at(0, 0) }()
+		res, err = engine.Find(store, q)
+		require.NoError(t, err)
+		assert.Len(t, res.Slots, 2)
+		assert.Equal(t, 1, store.schedules)
+		assert.Equal(t, 2, store.clients)
+	})
+
+	t.Run("should recompute after invalidation", func(t *testing.T) {
+		engine, store := newEngine(time.Minute), &fakeStore{}
+		_, _ = engine.Find(store, query())
+		engine.Cache.Invalidate(uuid.New())
+		_, _ = engine.Find(store, query())
+		assert.Equal(t, 1, store.schedules, "other companies keep their cache")
+
+		engine.Cache.Invalidate(companyID)
+		now = now.Add(time.Second)
+		defer func() { now = at(0, 0) }()
+		_, _ = engine.Find(store, query())
+		_, _ = engine.Find(store, query())
+		assert.Equal(t, 2, store.schedules)
+	})
+
+	t.Run("should not cache results computed before the invalidation", func(t *testing.T) {
+		cache := NewCache(time.Minute)
+		cache.now = func() time.Time { return at(10, 0) }
+		cache.Invalidate(companyID)
+
+		cache.Set(companyID, "key", &Result{}, at(9, 59))
+		assert.Nil(t, cache.Get(companyID, "key"))
+
+		cache.Set(companyID, "key", &Result{}, at(10, 1))
+		assert.NotNil(t, cache.Get(companyID, "key"))
+	})
+
+	t.Run("should expire results", func(t *testing.T) {
+		engine, store := newEngine(time.Minute), &fakeStore{}
+		_, _ = engine.Find(store, query())
+		now = now.Add(2 * time.Minute)
+		defer func() { now = at(0, 0) }()
+		_, _ = engine.Find(store, query())
+		assert.Equal(t, 2, store.schedules)
+	})
+}
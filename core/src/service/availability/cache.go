@@ -0,0 +1,81 @@
+package availability
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cache keeps computed results per company. Entries expire after the TTL, which
+// bounds how stale a result can be when another instance of the API changed the data.
+type Cache struct {
+	ttl        time.Duration
+	now        func() time.Time
+	mu         sync.Mutex
+	companies  map[uuid.UUID]map[string]cacheEntry
+	validAfter map[uuid.UUID]time.Time
+}
+
+type cacheEntry struct {
+	result     *Result
+	computedAt time.Time
+}
+
+// NewCache creates a cache whose entries expire after ttl.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:        ttl,
+		now:        time.Now,
+		companies:  map[uuid.UUID]map[string]cacheEntry{},
+		validAfter: map[uuid.UUID]time.Time{},
+	}
+}
+
+// Get returns the result cached for the company under key, nil when missing or stale.
+func (c *Cache) Get(companyID uuid.UUID, key string) *Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.companies[companyID][key]
+	if !ok {
+		return nil
+	}
+	now := c.now()
+	if now.Sub(entry.computedAt) > c.ttl || !entry.computedAt.After(c.validAfter[companyID]) {
+		delete(c.companies[companyID], key)
+		return nil
+	}
+	return entry.result
+}
+
+// Set caches the result computed at computedAt, when the computation started.
+func (c *Cache) Set(companyID uuid.UUID, key string, result *Result, computedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !computedAt.After(c.validAfter[companyID]) {
+		return
+	}
+	entries, ok := c.companies[companyID]
+	if !ok {
+		entries = map[string]cacheEntry{}
+		c.companies[companyID] = entries
+	}
+	// Expired entries are dropped here, as queries are often not repeated
+	now := c.now()
+	for k, entry := range entries {
+		if now.Sub(entry.computedAt) > c.ttl {
+			delete(entries, k)
+		}
+	}
+	entries[key] = cacheEntry{result: result, computedAt: computedAt}
+}
+
+// Invalidate drops the results of the company. Results whose computation started
+// before the call may have read the old data, so they are not cached either.
+// It must be called once the change is committed.
+func (c *Cache) Invalidate(companyID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.companies, companyID)
+	c.validAfter[companyID] = c.now()
+}
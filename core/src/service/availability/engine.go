@@ -0,0 +1,87 @@
+package availability
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store loads what availability is computed from.
+type Store interface {
+	// Schedule loads the service with its overrides, the work ranges offering it and
+	// the densities and appointments between from and to of their employees.
+	Schedule(serviceID uuid.UUID, from, to time.Time) (*Schedule, error)
+	// ClientBusy lists the appointments of the client, or of its dependent when dependentID
+	// is set, between from and to, in every company.
+	ClientBusy(clientID uuid.UUID, dependentID *uuid.UUID, from, to time.Time) ([]Interval, error)
+	// Candidates loads what the assignment strategies weigh for the employees, in their
+	// order, with from and to bounding the day of the slot. clientID may be uuid.Nil.
+	Candidates(serviceID, clientID uuid.UUID, employeeIDs []uuid.UUID, from, to time.Time) ([]Candidate, error)
+}
+
+// Engine answers queries from a store, caching the computed slots per company.
+type Engine struct {
+	Cache *Cache           // nil disables caching
+	Now   func() time.Time // Slots starting before are hidden
+}
+
+// DefaultTTL is how long results are cached by the default engine.
+const DefaultTTL = time.Minute
+
+var defaultEngine = &Engine{Cache: NewCache(DefaultTTL), Now: time.Now}
+
+// Find answers the query with the default engine, shared by the whole process.
+func Find(store Store, q Query) (*Result, error) {
+	return defaultEngine.Find(store, q)
+}
+
+// Ranked ranks the employees free for a slot with the default engine, see Engine.Ranked.
+func Ranked(store Store, q Query, branchID uuid.UUID, start time.Time) ([]uuid.UUID, error) {
+	return defaultEngine.Ranked(store, q, branchID, start)
+}
+
+// Next finds the first free slot with the default engine, see Engine.Next.
+func Next(store Store, q Query) (*Suggestion, error) {
+	return defaultEngine.Next(store, q)
+}
+
+// Invalidate drops the results cached by the default engine for the company.
+// It must be called when its appointments, work ranges, densities or service terms change,
+// after the transaction making the change has committed.
+func Invalidate(companyID uuid.UUID) {
+	defaultEngine.Cache.Invalidate(companyID)
+}
+
+// Find returns the future slots of the query. The slots computed from the schedule
+// are cached; past slots, slots outside the booking window and the client's
+// appointments are filtered on every call.
+func (e *Engine) Find(store Store, q Query) (*Result, error) {
+	now := e.Now()
+	key := q.key()
+	var res *Result
+	if e.Cache != nil {
+		res = e.Cache.Get(q.CompanyID, key)
+	}
+	if res == nil {
+		from, to := q.Span()
+		schedule, err := store.Schedule(q.ServiceID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		res = Compute(q, schedule)
+		if e.Cache != nil {
+			e.Cache.Set(q.CompanyID, key, res, now)
+		}
+	}
+
+	res = res.After(now).Bookable(now)
+	if q.ClientID != uuid.Nil {
+		from, to := q.Span()
+		busy, err := store.ClientBusy(q.ClientID, q.DependentID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		res = res.WithoutClient(busy)
+	}
+	return res, nil
+}
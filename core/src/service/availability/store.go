@@ -0,0 +1,175 @@
+package availability
+
+import (
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GormStore is the Store over a session on the company schema.
+type GormStore struct {
+	DB *gorm.DB
+}
+
+// Schedule implements Store.
+func (s GormStore) Schedule(serviceID uuid.UUID, from, to time.Time) (*Schedule, error) {
+	schedule := &Schedule{Densities: map[uuid.UUID]uint32{}, Busy: map[uuid.UUID][]Interval{}}
+	if err := s.DB.Where("id = ?", serviceID).First(&schedule.Service).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, lib.Error.General.RecordNotFound.WithError(err)
+		}
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	if err := s.DB.Where("service_id = ?", serviceID).Find(&schedule.Overrides).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	var company model.Company
+	if err := s.DB.Select("id", "min_notice_minutes", "max_days_ahead", "same_day_cutoff").
+		Where("id = ?", schedule.Service.CompanyID).First(&company).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	schedule.Company = company.BookingWindow
+
+	// Only the ranges of active employees still working at the branch of the range,
+	// at branches offering the service
+	if err := s.DB.
+		Joins("JOIN employee_work_range_services es ON es.employee_work_range_id = employee_work_ranges.id").
+		Joins("JOIN employee_branches eb ON eb.employee_id = employee_work_ranges.employee_id AND eb.branch_id = employee_work_ranges.branch_id").
+		Joins("JOIN branch_services bs ON bs.branch_id = employee_work_ranges.branch_id AND bs.service_id = es.service_id").
+		Joins("JOIN employees e ON e.id = employee_work_ranges.employee_id").
+		Where("es.service_id = ?", serviceID).
+		Where("e.status = ?", model.EmployeeActive).
+		Preload("Employee").
+		Preload("Branch").
+		Find(&schedule.Ranges).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	employeeIDs := make([]uuid.UUID, 0, len(schedule.Ranges))
+	branchIDs := make([]uuid.UUID, 0, len(schedule.Ranges))
+	for _, wr := range schedule.Ranges {
+		employeeIDs = append(employeeIDs, wr.EmployeeID)
+		branchIDs = append(branchIDs, wr.BranchID)
+	}
+	if len(employeeIDs) == 0 {
+		return schedule, nil
+	}
+
+	var opening []model.BranchWorkRange
+	if err := s.DB.Where("branch_id IN ?", branchIDs).Find(&opening).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	schedule.Opening = map[uuid.UUID][]model.BranchWorkRange{}
+	for _, br := range opening {
+		schedule.Opening[br.BranchID] = append(schedule.Opening[br.BranchID], br)
+	}
+
+	var densities []model.EmployeeServiceDensity
+	if err := s.DB.Where("service_id = ? AND employee_id IN ?", serviceID, employeeIDs).Find(&densities).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	for _, d := range densities {
+		schedule.Densities[d.EmployeeID] = d.Density
+	}
+
+	// Appointments started the day before may still be running, and slots of ranges
+	// spanning midnight may run into the day after
+	var appointments []model.Appointment
+	if err := s.DB.
+		Where("employee_id IN ? AND is_cancelled = ?", employeeIDs, false).
+		Where("start_time >= ? AND start_time < ?", from.Add(-24*time.Hour), to.Add(24*time.Hour)).
+		Preload("Service").
+		Find(&appointments).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	for _, a := range appointments {
+		end := a.EndTime
+		if end.IsZero() && a.Service != nil {
+			end = a.StartTime.Add(time.Duration(a.Service.Duration) * time.Minute)
+		}
+		schedule.Busy[a.EmployeeID] = append(schedule.Busy[a.EmployeeID], Interval{Start: a.StartTime, End: end})
+	}
+
+	needs, err := model.LoadResourceNeeds(s.DB, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	schedule.Needs = needs
+	resourceIDs := make([]uuid.UUID, len(schedule.Needs))
+	for i, n := range schedule.Needs {
+		resourceIDs[i] = n.ResourceID
+	}
+	if schedule.Uses, err = model.LoadResourceUses(s.DB, resourceIDs, from, to.Add(24*time.Hour), uuid.Nil); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// ClientBusy implements Store. Client appointments are kept in the public schema for every company.
+func (s GormStore) ClientBusy(clientID uuid.UUID, dependentID *uuid.UUID, from, to time.Time) ([]Interval, error) {
+	var appointments []model.ClientAppointment
+	if err := model.WhereAttendee(s.DB.Table("public.client_appointments"), clientID, dependentID).
+		Where("is_cancelled = ?", false).
+		Where("start_time < ? AND end_time > ?", to, from).
+		Find(&appointments).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	busy := make([]Interval, 0, len(appointments))
+	for _, a := range appointments {
+		busy = append(busy, Interval{Start: a.StartTime, End: a.EndTime})
+	}
+	return busy, nil
+}
+
+// Candidates implements Store.
+func (s GormStore) Candidates(serviceID, clientID uuid.UUID, employeeIDs []uuid.UUID, from, to time.Time) ([]Candidate, error) {
+	type stat struct {
+		EmployeeID uuid.UUID
+		Count      int
+		Last       time.Time
+	}
+	var booked, assigned, served []stat
+	if err := s.DB.Model(&model.Appointment{}).
+		Select("employee_id, COUNT(*) AS count").
+		Where("employee_id IN ? AND is_cancelled = ?", employeeIDs, false).
+		Where("start_time >= ? AND start_time < ?", from, to).
+		Group("employee_id").
+		Scan(&booked).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	if err := s.DB.Model(&model.Appointment{}).
+		Select("employee_id, MAX(created_at) AS last").
+		Where("employee_id IN ? AND service_id = ?", employeeIDs, serviceID).
+		Group("employee_id").
+		Scan(&assigned).Error; err != nil {
+		return nil, lib.Error.General.InternalError.WithError(err)
+	}
+	if clientID != uuid.Nil {
+		if err := s.DB.Model(&model.Appointment{}).
+			Select("employee_id, MAX(start_time) AS last").
+			Where("employee_id IN ? AND client_id = ? AND is_cancelled = ?", employeeIDs, clientID, false).
+			Group("employee_id").
+			Scan(&served).Error; err != nil {
+			return nil, lib.Error.General.InternalError.WithError(err)
+		}
+	}
+
+	candidates := make([]Candidate, len(employeeIDs))
+	index := make(map[uuid.UUID]int, len(employeeIDs))
+	for i, id := range employeeIDs {
+		candidates[i].EmployeeID = id
+		index[id] = i
+	}
+	for _, b := range booked {
+		candidates[index[b.EmployeeID]].BookedThatDay = b.Count
+	}
+	for _, a := range assigned {
+		candidates[index[a.EmployeeID]].LastAssigned = a.Last
+	}
+	for _, sv := range served {
+		candidates[index[sv.EmployeeID]].LastServed = sv.Last
+	}
+	return candidates, nil
+}
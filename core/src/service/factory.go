@@ -18,6 +18,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// CodeValidity is how long an emailed login or verification code can be used.
+const CodeValidity = 15 * time.Minute
+
 func New(c *fiber.Ctx) *service {
 	var err error
 	tx, end, err := database.ContextTransaction(c)
@@ -129,6 +132,12 @@ func (s *service) ForceGetBy(param string) *service {
 }
 
 func (s *service) Create() *service {
+	return s.CreatePrepared(nil)
+}
+
+// CreatePrepared works like Create and runs prepare, when given, on the model parsed
+// from the body before it is stored. Used to set fields the server decides.
+func (s *service) CreatePrepared(prepare func() error) *service {
 	if s.Error != nil {
 		return s
 	}
@@ -136,6 +145,12 @@ func (s *service) Create() *service {
 		s.Error = lib.Error.General.InternalError.WithError(err)
 		return s
 	}
+	if prepare != nil {
+		if err := prepare(); err != nil {
+			s.Error = err
+			return s
+		}
+	}
 	if err := s.MyGorm.Create(s.Model); err != nil {
 		s.Error = err
 		return s
@@ -163,6 +178,18 @@ func (s *service) Create() *service {
 	return s
 }
 
+// Then runs fn with the service transaction if every previous step succeeded,
+// so that whatever fn writes is committed or rolled back along with the model.
+func (s *service) Then(fn func(tx *gorm.DB) error) *service {
+	if s.Error != nil {
+		return s
+	}
+	if err := fn(s.MyGorm.DB); err != nil {
+		s.Error = err
+	}
+	return s
+}
+
 func (s *service) UpdateOneById() *service {
 	if s.Error != nil {
 		return s
@@ -215,7 +242,6 @@ func (s *service) LoginByPassword(user_type string) (string, error) {
 	if s.Error != nil {
 		return "", s.Error
 	}
-	var err error
 	var body DTO.LoginClient
 	if err := s.Context.BodyParser(&body); err != nil {
 		return "", err
@@ -252,36 +278,31 @@ func (s *service) LoginByPassword(user_type string) (string, error) {
 		return "", lib.Error.Auth.InvalidLogin
 	}
 
-	userBytes, err := json.Marshal(s.Model)
-	if err != nil {
-		return "", lib.Error.General.InternalError.WithError(err)
-	}
-	var claims DTO.Claims
-	if err := json.Unmarshal(userBytes, &claims); err != nil {
-		return "", lib.Error.General.InternalError.WithError(err)
-	}
-	claims.Type = user_type
-	token, err := handler.JWT(s.Context).Encode(&claims)
-	if err != nil {
-		return "", err
-	}
-	return token, nil
+	return s.encodeToken(user_type)
 }
 
 func (s *service) LoginByEmailCode(user_type string) (string, error) {
 	if s.Error != nil {
 		return "", s.Error
 	}
-	var err error
 	var body DTO.LoginByEmailCode
 	if err := s.Context.BodyParser(&body); err != nil {
 		return "", err
 	}
+	return s.LoginByValidationCode(user_type, body.Email, body.Code)
+}
+
+// LoginByValidationCode logs in the user with the email using the login validation code
+// emailed to it, verifying the email.
+func (s *service) LoginByValidationCode(user_type, email, code string) (string, error) {
+	if s.Error != nil {
+		return "", s.Error
+	}
 
 	// Find user by email
 	if err := s.MyGorm.DB.
 		Model(s.Model).
-		Where("email = ?", body.Email).
+		Where("email = ?", email).
 		First(s.Model).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return "", lib.Error.Client.NotFound
@@ -323,7 +344,7 @@ func (s *service) LoginByEmailCode(user_type string) (string, error) {
 	}
 
 	// Validate the code
-	if storedCode != body.Code {
+	if storedCode != code {
 		return "", lib.Error.Auth.InvalidLogin.WithError(fmt.Errorf("invalid validation code"))
 	}
 
@@ -344,7 +365,7 @@ func (s *service) LoginByEmailCode(user_type string) (string, error) {
 		"verified": true, // Always set to true on successful login
 	}
 	if err := s.MyGorm.DB.Model(freshModel).
-		Where("email = ?", body.Email).
+		Where("email = ?", email).
 		Updates(updateData).Error; err != nil {
 		return "", lib.Error.General.InternalError.WithError(err)
 	}
@@ -356,6 +377,17 @@ func (s *service) LoginByEmailCode(user_type string) (string, error) {
 	}
 
 	// Generate JWT token
+	return s.encodeToken(user_type)
+}
+
+// encodeToken issues the JWT of the loaded model.
+func (s *service) encodeToken(user_type string) (string, error) {
+	// Invited and suspended employees can not log in
+	if gate, ok := s.Model.(interface{ CanLogin() error }); ok {
+		if err := gate.CanLogin(); err != nil {
+			return "", err
+		}
+	}
 	userBytes, err := json.Marshal(s.Model)
 	if err != nil {
 		return "", lib.Error.General.InternalError.WithError(err)
@@ -429,7 +461,7 @@ func (s *service) ResetLoginCodeByEmail(user_email string) (string, error) {
 	codeString := fmt.Sprintf("%d", LoginValidationCode)
 
 	// Set code expiration to 15 minutes from now
-	expiryTime := time.Now().Add(15 * time.Minute)
+	expiryTime := time.Now().Add(CodeValidity)
 
 	// Set the validation code and expiry
 	metaValue.Login.ValidationCode = &codeString
@@ -584,7 +616,7 @@ func (s *service) GetVerificationCodeByEmail(email string) (string, error) {
 	codeString := fmt.Sprintf("%d", code)
 
 	// Set code expiration to 15 minutes from now
-	expiryTime := time.Now().Add(15 * time.Minute)
+	expiryTime := time.Now().Add(CodeValidity)
 
 	// Store the code in the database using reflection
 	modelValue := reflect.ValueOf(s.Model)
@@ -719,3 +751,22 @@ func (s *service) VerifyEmail(email, code string) error {
 
 	return nil
 }
+
+// LoginByVerificationCode verifies the email with the code sent by
+// GetVerificationCodeByEmail and logs the user in, for users without a password.
+func (s *service) LoginByVerificationCode(user_type, email, code string) (string, error) {
+	if err := s.VerifyEmail(email, code); err != nil {
+		return "", err
+	}
+	email, err := s.prepare_email(email)
+	if err != nil {
+		return "", err
+	}
+	if err := s.MyGorm.DB.
+		Model(s.Model).
+		Where("email = ?", email).
+		First(s.Model).Error; err != nil {
+		return "", lib.Error.General.InternalError.WithError(err)
+	}
+	return s.encodeToken(user_type)
+}
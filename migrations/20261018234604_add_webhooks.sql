@@ -0,0 +1,53 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Create "webhook_subscriptions" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."webhook_subscriptions" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "company_id" uuid NOT NULL,
+            "url" text NOT NULL,
+            "description" text,
+            "secret" varchar(64) NOT NULL,
+            "events" jsonb NOT NULL,
+            "is_active" boolean NOT NULL DEFAULT true,
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_webhook_subscriptions_company_id" ON %1$I."webhook_subscriptions" ("company_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_webhook_subscriptions_deleted_at" ON %1$I."webhook_subscriptions" ("deleted_at")', schema_name);
+
+        -- Create "webhook_deliveries" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."webhook_deliveries" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "subscription_id" uuid NOT NULL,
+            "company_id" uuid NOT NULL,
+            "event" varchar(100) NOT NULL,
+            "payload" JSONB NOT NULL,
+            "status" varchar(20) NOT NULL DEFAULT ''PENDING'',
+            "attempts" bigint NOT NULL DEFAULT 0,
+            "response_status" bigint,
+            "last_error" text,
+            "next_attempt_at" timestamptz,
+            "delivered_at" timestamptz,
+            "replay_of_id" uuid,
+            PRIMARY KEY ("id"),
+            CONSTRAINT "fk_webhook_subscriptions_deliveries" FOREIGN KEY ("subscription_id") REFERENCES %1$I."webhook_subscriptions"("id") ON DELETE CASCADE
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_company_id" ON %1$I."webhook_deliveries" ("company_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_deleted_at" ON %1$I."webhook_deliveries" ("deleted_at")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_event" ON %1$I."webhook_deliveries" ("event")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_next_attempt_at" ON %1$I."webhook_deliveries" ("next_attempt_at")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_status" ON %1$I."webhook_deliveries" ("status")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_subscription_id" ON %1$I."webhook_deliveries" ("subscription_id")', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,20 @@
+-- Create "outbox_messages" table
+CREATE TABLE IF NOT EXISTS "public"."outbox_messages" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "company_id" uuid,
+    "topic" varchar(100) NOT NULL,
+    "payload" JSONB NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'PENDING',
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "last_error" text,
+    "available_at" timestamptz NOT NULL,
+    "processed_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_outbox_status_available" ON "public"."outbox_messages" ("status","available_at");
+CREATE INDEX IF NOT EXISTS "idx_public_outbox_messages_company_id" ON "public"."outbox_messages" ("company_id");
+CREATE INDEX IF NOT EXISTS "idx_public_outbox_messages_deleted_at" ON "public"."outbox_messages" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_public_outbox_messages_topic" ON "public"."outbox_messages" ("topic");
@@ -0,0 +1,29 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Modify "payments" table
+        EXECUTE format('ALTER TABLE %1$I."payments"
+            ADD COLUMN IF NOT EXISTS "type" varchar(20) NOT NULL DEFAULT ''FULL'',
+            ADD COLUMN IF NOT EXISTS "failure_reason" text,
+            ADD COLUMN IF NOT EXISTS "company_id" uuid,
+            ADD COLUMN IF NOT EXISTS "client_id" uuid,
+            ADD COLUMN IF NOT EXISTS "appointment_id" uuid,
+            ADD COLUMN IF NOT EXISTS "refunded_amount" bigint NOT NULL DEFAULT 0,
+            ADD COLUMN IF NOT EXISTS "refunded_at" timestamptz', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_payments_appointment_id" ON %1$I."payments" ("appointment_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_payments_client_id" ON %1$I."payments" ("client_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_payments_company_id" ON %1$I."payments" ("company_id")', schema_name);
+
+        -- Modify "services" table
+        EXECUTE format('ALTER TABLE %1$I."services"
+            ADD COLUMN IF NOT EXISTS "prepayment_type" varchar(20) NOT NULL DEFAULT ''NONE'',
+            ADD COLUMN IF NOT EXISTS "deposit_amount" bigint NOT NULL DEFAULT 0,
+            ADD COLUMN IF NOT EXISTS "free_cancellation_hours" integer NOT NULL DEFAULT 24,
+            ADD COLUMN IF NOT EXISTS "late_cancellation_refund_percent" smallint NOT NULL DEFAULT 0', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,19 @@
+-- Modify "companies" table
+ALTER TABLE "public"."companies"
+    ADD COLUMN IF NOT EXISTS "pix_key" varchar(77),
+    ADD COLUMN IF NOT EXISTS "pix_city" varchar(15);
+
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Modify "payments" table
+        EXECUTE format('ALTER TABLE %1$I."payments"
+            ADD COLUMN IF NOT EXISTS "pix_payload" text,
+            ADD COLUMN IF NOT EXISTS "pix_qr_code_url" text', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,38 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Create "service_overrides" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."service_overrides" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "service_id" uuid NOT NULL,
+            "employee_id" uuid,
+            "branch_id" uuid,
+            "price" bigint,
+            "duration" integer,
+            PRIMARY KEY ("id"),
+            CONSTRAINT "fk_service_overrides_branch" FOREIGN KEY ("branch_id") REFERENCES %1$I."branches"("id") ON DELETE CASCADE,
+            CONSTRAINT "fk_service_overrides_employee" FOREIGN KEY ("employee_id") REFERENCES %1$I."employees"("id") ON DELETE CASCADE,
+            CONSTRAINT "fk_service_overrides_service" FOREIGN KEY ("service_id") REFERENCES %1$I."services"("id") ON DELETE CASCADE
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_service_overrides_branch_id" ON %1$I."service_overrides" ("branch_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_service_overrides_deleted_at" ON %1$I."service_overrides" ("deleted_at")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_service_overrides_employee_id" ON %1$I."service_overrides" ("employee_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_service_overrides_service_id" ON %1$I."service_overrides" ("service_id")', schema_name);
+
+        -- Modify "appointments" table
+        EXECUTE format('ALTER TABLE %1$I."appointments"
+            ADD COLUMN IF NOT EXISTS "price" bigint NOT NULL DEFAULT 0', schema_name);
+
+        -- Modify "appointments_archive" table
+        EXECUTE format('ALTER TABLE %1$I."appointments_archive"
+            ADD COLUMN IF NOT EXISTS "price" bigint NOT NULL DEFAULT 0', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,46 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Create "promo_codes" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."promo_codes" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "company_id" uuid NOT NULL,
+            "code" varchar(40) NOT NULL,
+            "description" text,
+            "discount_type" varchar(20) NOT NULL,
+            "discount_value" bigint NOT NULL,
+            "starts_at" timestamptz,
+            "ends_at" timestamptz,
+            "max_uses" bigint NOT NULL DEFAULT 0,
+            "max_uses_per_client" bigint NOT NULL DEFAULT 0,
+            "first_visit_only" boolean NOT NULL DEFAULT false,
+            "service_ids" jsonb,
+            "branch_ids" jsonb,
+            "employee_ids" jsonb,
+            "is_active" boolean NOT NULL DEFAULT true,
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_promo_codes_company_id" ON %1$I."promo_codes" ("company_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_promo_codes_deleted_at" ON %1$I."promo_codes" ("deleted_at")', schema_name);
+
+        -- Modify "appointments" table
+        EXECUTE format('ALTER TABLE %1$I."appointments"
+            ADD COLUMN IF NOT EXISTS "discount" bigint NOT NULL DEFAULT 0,
+            ADD COLUMN IF NOT EXISTS "promo_code_id" uuid', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_appointments_promo_code_id" ON %1$I."appointments" ("promo_code_id")', schema_name);
+
+        -- Modify "appointments_archive" table
+        EXECUTE format('ALTER TABLE %1$I."appointments_archive"
+            ADD COLUMN IF NOT EXISTS "discount" bigint NOT NULL DEFAULT 0,
+            ADD COLUMN IF NOT EXISTS "promo_code_id" uuid', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_appointments_archive_promo_code_id" ON %1$I."appointments_archive" ("promo_code_id")', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,67 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Create "service_packages" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."service_packages" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "company_id" uuid NOT NULL,
+            "name" varchar(100) NOT NULL,
+            "description" text,
+            "type" varchar(20) NOT NULL,
+            "price" bigint NOT NULL DEFAULT 0,
+            "currency" varchar(3) DEFAULT ''BRL'',
+            "credits" bigint NOT NULL DEFAULT 0,
+            "validity_days" bigint NOT NULL,
+            "service_ids" jsonb,
+            "is_active" boolean NOT NULL DEFAULT true,
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_service_packages_company_id" ON %1$I."service_packages" ("company_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_service_packages_deleted_at" ON %1$I."service_packages" ("deleted_at")', schema_name);
+
+        -- Create "client_packages" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."client_packages" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "company_id" uuid NOT NULL,
+            "client_id" uuid NOT NULL,
+            "package_id" uuid NOT NULL,
+            "name" varchar(100) NOT NULL,
+            "type" varchar(20) NOT NULL,
+            "price" bigint NOT NULL DEFAULT 0,
+            "currency" varchar(3) DEFAULT ''BRL'',
+            "credits" bigint NOT NULL DEFAULT 0,
+            "credits_used" bigint NOT NULL DEFAULT 0,
+            "service_ids" jsonb,
+            "purchased_at" timestamptz NOT NULL,
+            "expires_at" timestamptz NOT NULL,
+            PRIMARY KEY ("id"),
+            CONSTRAINT "fk_client_packages_package" FOREIGN KEY ("package_id") REFERENCES %1$I."service_packages"("id") ON DELETE RESTRICT
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_client_packages_client_id" ON %1$I."client_packages" ("client_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_client_packages_company_id" ON %1$I."client_packages" ("company_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_client_packages_deleted_at" ON %1$I."client_packages" ("deleted_at")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_client_packages_expires_at" ON %1$I."client_packages" ("expires_at")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_client_packages_package_id" ON %1$I."client_packages" ("package_id")', schema_name);
+
+        -- Modify "appointments" table
+        EXECUTE format('ALTER TABLE %1$I."appointments"
+            ADD COLUMN IF NOT EXISTS "client_package_id" uuid', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_appointments_client_package_id" ON %1$I."appointments" ("client_package_id")', schema_name);
+
+        -- Modify "appointments_archive" table
+        EXECUTE format('ALTER TABLE %1$I."appointments_archive"
+            ADD COLUMN IF NOT EXISTS "client_package_id" uuid', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_appointments_archive_client_package_id" ON %1$I."appointments_archive" ("client_package_id")', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,14 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Modify "appointments" table
+        EXECUTE format('ALTER TABLE %1$I."appointments"
+            ADD COLUMN IF NOT EXISTS "receipt_url" text,
+            ADD COLUMN IF NOT EXISTS "receipt_issued_at" timestamptz', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,13 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Modify "services" table
+        EXECUTE format('ALTER TABLE %1$I."services"
+            ADD COLUMN IF NOT EXISTS "employee_assignment" varchar(20) NOT NULL DEFAULT ''LEAST_BOOKED''', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,59 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Create "bookable_resources" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."bookable_resources" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "branch_id" uuid NOT NULL,
+            "name" varchar(100) NOT NULL,
+            "kind" varchar(20) NOT NULL DEFAULT ''ROOM'',
+            "quantity" bigint NOT NULL DEFAULT 1,
+            PRIMARY KEY ("id"),
+            CONSTRAINT "fk_bookable_resources_branch" FOREIGN KEY ("branch_id") REFERENCES %1$I."branches"("id") ON DELETE CASCADE
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_bookable_resources_branch_id" ON %1$I."bookable_resources" ("branch_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_bookable_resources_deleted_at" ON %1$I."bookable_resources" ("deleted_at")', schema_name);
+
+        -- Create "service_resource_requirements" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."service_resource_requirements" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "service_id" uuid NOT NULL,
+            "resource_id" uuid NOT NULL,
+            "quantity" bigint NOT NULL DEFAULT 1,
+            PRIMARY KEY ("id"),
+            CONSTRAINT "fk_service_resource_requirements_resource" FOREIGN KEY ("resource_id") REFERENCES %1$I."bookable_resources"("id") ON DELETE CASCADE,
+            CONSTRAINT "fk_service_resource_requirements_service" FOREIGN KEY ("service_id") REFERENCES %1$I."services"("id") ON DELETE CASCADE
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_service_resource_requirements_deleted_at" ON %1$I."service_resource_requirements" ("deleted_at")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_service_resource_requirements_resource_id" ON %1$I."service_resource_requirements" ("resource_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_service_resource_requirements_service_id" ON %1$I."service_resource_requirements" ("service_id")', schema_name);
+
+        -- Create "appointment_resource_allocations" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."appointment_resource_allocations" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "appointment_id" uuid NOT NULL,
+            "resource_id" uuid NOT NULL,
+            "quantity" bigint NOT NULL,
+            PRIMARY KEY ("id"),
+            CONSTRAINT "fk_appointment_resource_allocations_appointment" FOREIGN KEY ("appointment_id") REFERENCES %1$I."appointments"("id") ON DELETE CASCADE,
+            CONSTRAINT "fk_appointment_resource_allocations_resource" FOREIGN KEY ("resource_id") REFERENCES %1$I."bookable_resources"("id") ON DELETE CASCADE
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_appointment_resource_allocations_appointment_id" ON %1$I."appointment_resource_allocations" ("appointment_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_appointment_resource_allocations_deleted_at" ON %1$I."appointment_resource_allocations" ("deleted_at")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_appointment_resource_allocations_resource_id" ON %1$I."appointment_resource_allocations" ("resource_id")', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,27 @@
+-- Modify "companies" table
+ALTER TABLE "public"."companies"
+    ADD COLUMN IF NOT EXISTS "min_notice_minutes" bigint,
+    ADD COLUMN IF NOT EXISTS "max_days_ahead" integer,
+    ADD COLUMN IF NOT EXISTS "same_day_cutoff" varchar(5);
+
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Modify "branches" table
+        EXECUTE format('ALTER TABLE %1$I."branches"
+            ADD COLUMN IF NOT EXISTS "min_notice_minutes" bigint,
+            ADD COLUMN IF NOT EXISTS "max_days_ahead" integer,
+            ADD COLUMN IF NOT EXISTS "same_day_cutoff" varchar(5)', schema_name);
+
+        -- Modify "services" table
+        EXECUTE format('ALTER TABLE %1$I."services"
+            ADD COLUMN IF NOT EXISTS "min_notice_minutes" bigint,
+            ADD COLUMN IF NOT EXISTS "max_days_ahead" integer,
+            ADD COLUMN IF NOT EXISTS "same_day_cutoff" varchar(5)', schema_name);
+    END LOOP;
+END $$;
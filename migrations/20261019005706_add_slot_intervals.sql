@@ -0,0 +1,15 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Modify "services" table
+        EXECUTE format('ALTER TABLE %1$I."services"
+            ADD COLUMN IF NOT EXISTS "slot_interval" integer NOT NULL DEFAULT 0,
+            ADD COLUMN IF NOT EXISTS "slot_alignment" varchar(20) NOT NULL DEFAULT ''MIDNIGHT'',
+            ADD COLUMN IF NOT EXISTS "optimize_gaps" boolean NOT NULL DEFAULT false', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,39 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Create "schedule_templates" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."schedule_templates" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "company_id" uuid NOT NULL,
+            "name" varchar(100) NOT NULL,
+            "description" text,
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_schedule_templates_company_id" ON %1$I."schedule_templates" ("company_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_schedule_templates_deleted_at" ON %1$I."schedule_templates" ("deleted_at")', schema_name);
+
+        -- Create "schedule_template_ranges" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."schedule_template_ranges" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "template_id" uuid NOT NULL,
+            "weekday" bigint NOT NULL,
+            "start_time" varchar(5) NOT NULL,
+            "end_time" varchar(5) NOT NULL,
+            PRIMARY KEY ("id"),
+            CONSTRAINT "fk_schedule_templates_ranges" FOREIGN KEY ("template_id") REFERENCES %1$I."schedule_templates"("id") ON DELETE CASCADE
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_schedule_template_ranges_deleted_at" ON %1$I."schedule_template_ranges" ("deleted_at")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_schedule_template_ranges_template_id" ON %1$I."schedule_template_ranges" ("template_id")', schema_name);
+    END LOOP;
+END $$;
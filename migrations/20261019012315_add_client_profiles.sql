@@ -0,0 +1,29 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Create "client_profiles" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."client_profiles" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "company_id" uuid NOT NULL,
+            "client_id" uuid NOT NULL,
+            "tags" jsonb,
+            "notes" text,
+            "preferences" text,
+            "birthday" date,
+            "marketing_consent" boolean NOT NULL DEFAULT false,
+            "marketing_consent_at" timestamptz,
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_client_profiles_client_id" ON %1$I."client_profiles" ("client_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_client_profiles_company_id" ON %1$I."client_profiles" ("company_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_client_profiles_deleted_at" ON %1$I."client_profiles" ("deleted_at")', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,4 @@
+-- Modify "clients" table
+ALTER TABLE "public"."clients"
+    ADD COLUMN IF NOT EXISTS "is_guest" boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS "pending_details" jsonb;
@@ -0,0 +1,22 @@
+-- Create "client_data_requests" table
+CREATE TABLE IF NOT EXISTS "public"."client_data_requests" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "client_id" uuid NOT NULL,
+    "kind" varchar(20) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'PENDING',
+    "requested_by_id" uuid,
+    "requested_by_type" varchar(20),
+    "request_ip" varchar(45),
+    "endpoint" varchar(255),
+    "company_ids" jsonb,
+    "completed_at" timestamptz,
+    "expires_at" timestamptz,
+    "export_json" JSONB,
+    "export_csv" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_public_client_data_requests_client_id" ON "public"."client_data_requests" ("client_id");
+CREATE INDEX IF NOT EXISTS "idx_public_client_data_requests_deleted_at" ON "public"."client_data_requests" ("deleted_at");
@@ -0,0 +1,38 @@
+-- Create "client_dependents" table
+CREATE TABLE IF NOT EXISTS "public"."client_dependents" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "client_id" uuid NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "birth_date" date,
+    "notes" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_public_client_dependents_client_id" ON "public"."client_dependents" ("client_id");
+CREATE INDEX IF NOT EXISTS "idx_public_client_dependents_deleted_at" ON "public"."client_dependents" ("deleted_at");
+
+-- Modify "client_appointments" table
+ALTER TABLE "public"."client_appointments"
+    ADD COLUMN IF NOT EXISTS "dependent_id" uuid;
+
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Modify "appointments" table
+        EXECUTE format('ALTER TABLE %1$I."appointments"
+            ADD COLUMN IF NOT EXISTS "dependent_id" uuid', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_appointments_dependent_id" ON %1$I."appointments" ("dependent_id")', schema_name);
+
+        -- Modify "appointments_archive" table
+        EXECUTE format('ALTER TABLE %1$I."appointments_archive"
+            ADD COLUMN IF NOT EXISTS "dependent_id" uuid', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_appointments_archive_dependent_id" ON %1$I."appointments_archive" ("dependent_id")', schema_name);
+    END LOOP;
+END $$;
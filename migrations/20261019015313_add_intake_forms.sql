@@ -0,0 +1,74 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Create "intake_forms" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."intake_forms" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "company_id" uuid NOT NULL,
+            "name" varchar(100) NOT NULL,
+            "description" text,
+            "service_ids" jsonb,
+            "fields" jsonb,
+            "is_consent" boolean NOT NULL DEFAULT false,
+            "consent_text" text,
+            "version" bigint NOT NULL DEFAULT 1,
+            "is_active" boolean NOT NULL DEFAULT true,
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_intake_forms_company_id" ON %1$I."intake_forms" ("company_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_intake_forms_deleted_at" ON %1$I."intake_forms" ("deleted_at")', schema_name);
+
+        -- Create "intake_form_versions" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."intake_form_versions" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "form_id" uuid NOT NULL,
+            "version" bigint NOT NULL,
+            "name" varchar(100) NOT NULL,
+            "fields" jsonb,
+            "is_consent" boolean NOT NULL DEFAULT false,
+            "consent_text" text,
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE UNIQUE INDEX IF NOT EXISTS "idx_intake_form_version" ON %1$I."intake_form_versions" ("form_id","version")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_intake_form_versions_deleted_at" ON %1$I."intake_form_versions" ("deleted_at")', schema_name);
+
+        -- Create "intake_responses" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."intake_responses" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "form_id" uuid NOT NULL,
+            "form_version_id" uuid NOT NULL,
+            "version" bigint NOT NULL,
+            "client_id" uuid NOT NULL,
+            "dependent_id" uuid,
+            "appointment_id" uuid,
+            "status" varchar(20) NOT NULL DEFAULT ''PENDING'',
+            "token" varchar(64),
+            "answers" jsonb,
+            "signed_name" varchar(200),
+            "signed_at" timestamptz,
+            "signature_ip" varchar(45),
+            "consent_url" text,
+            "completed_at" timestamptz,
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_intake_responses_appointment_id" ON %1$I."intake_responses" ("appointment_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_intake_responses_client_id" ON %1$I."intake_responses" ("client_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_intake_responses_deleted_at" ON %1$I."intake_responses" ("deleted_at")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_intake_responses_form_id" ON %1$I."intake_responses" ("form_id")', schema_name);
+        EXECUTE format('CREATE UNIQUE INDEX IF NOT EXISTS "idx_intake_responses_token" ON %1$I."intake_responses" ("token")', schema_name);
+    END LOOP;
+END $$;
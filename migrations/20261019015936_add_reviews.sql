@@ -0,0 +1,58 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Create "reviews" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."reviews" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "company_id" uuid NOT NULL,
+            "appointment_id" uuid NOT NULL,
+            "client_id" uuid NOT NULL,
+            "branch_id" uuid NOT NULL,
+            "employee_id" uuid NOT NULL,
+            "service_id" uuid NOT NULL,
+            "status" varchar(20) NOT NULL DEFAULT ''INVITED'',
+            "token" varchar(64),
+            "rating" bigint NOT NULL DEFAULT 0,
+            "employee_rating" bigint,
+            "service_rating" bigint,
+            "comment" text,
+            "submitted_at" timestamptz,
+            "moderated_by_id" uuid,
+            "moderated_at" timestamptz,
+            "moderation_note" varchar(500),
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE UNIQUE INDEX IF NOT EXISTS "idx_reviews_appointment_id" ON %1$I."reviews" ("appointment_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_reviews_branch_id" ON %1$I."reviews" ("branch_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_reviews_client_id" ON %1$I."reviews" ("client_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_reviews_company_id" ON %1$I."reviews" ("company_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_reviews_deleted_at" ON %1$I."reviews" ("deleted_at")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_reviews_employee_id" ON %1$I."reviews" ("employee_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_reviews_service_id" ON %1$I."reviews" ("service_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_reviews_status" ON %1$I."reviews" ("status")', schema_name);
+        EXECUTE format('CREATE UNIQUE INDEX IF NOT EXISTS "idx_reviews_token" ON %1$I."reviews" ("token")', schema_name);
+
+        -- Modify "branches" table
+        EXECUTE format('ALTER TABLE %1$I."branches"
+            ADD COLUMN IF NOT EXISTS "rating_average" decimal NOT NULL DEFAULT 0,
+            ADD COLUMN IF NOT EXISTS "rating_count" bigint NOT NULL DEFAULT 0', schema_name);
+
+        -- Modify "employees" table
+        EXECUTE format('ALTER TABLE %1$I."employees"
+            ADD COLUMN IF NOT EXISTS "rating_average" decimal NOT NULL DEFAULT 0,
+            ADD COLUMN IF NOT EXISTS "rating_count" bigint NOT NULL DEFAULT 0', schema_name);
+
+        -- Modify "services" table
+        EXECUTE format('ALTER TABLE %1$I."services"
+            ADD COLUMN IF NOT EXISTS "rating_average" decimal NOT NULL DEFAULT 0,
+            ADD COLUMN IF NOT EXISTS "rating_count" bigint NOT NULL DEFAULT 0', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,76 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Create "loyalty_programs" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."loyalty_programs" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "company_id" uuid NOT NULL,
+            "is_active" boolean NOT NULL DEFAULT true,
+            "points_per_appointment" bigint,
+            "points_per_amount" bigint,
+            "amount_unit" bigint,
+            "rules" jsonb,
+            "point_value" bigint NOT NULL,
+            "min_redeem_points" bigint NOT NULL DEFAULT 0,
+            "max_redeem_percent" bigint NOT NULL DEFAULT 100,
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE UNIQUE INDEX IF NOT EXISTS "idx_loyalty_programs_company_id" ON %1$I."loyalty_programs" ("company_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_loyalty_programs_deleted_at" ON %1$I."loyalty_programs" ("deleted_at")', schema_name);
+
+        -- Create "loyalty_accounts" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."loyalty_accounts" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "company_id" uuid NOT NULL,
+            "client_id" uuid NOT NULL,
+            "balance" bigint NOT NULL DEFAULT 0,
+            "earned" bigint NOT NULL DEFAULT 0,
+            "redeemed" bigint NOT NULL DEFAULT 0,
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE UNIQUE INDEX IF NOT EXISTS "idx_loyalty_account_client" ON %1$I."loyalty_accounts" ("company_id","client_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_loyalty_accounts_deleted_at" ON %1$I."loyalty_accounts" ("deleted_at")', schema_name);
+
+        -- Create "loyalty_transactions" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."loyalty_transactions" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "account_id" uuid NOT NULL,
+            "company_id" uuid NOT NULL,
+            "client_id" uuid NOT NULL,
+            "type" varchar(20) NOT NULL,
+            "points" bigint NOT NULL,
+            "balance_after" bigint NOT NULL,
+            "appointment_id" uuid,
+            "description" varchar(255),
+            "created_by_id" uuid,
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_loyalty_transactions_account_id" ON %1$I."loyalty_transactions" ("account_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_loyalty_transactions_appointment_id" ON %1$I."loyalty_transactions" ("appointment_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_loyalty_transactions_client_id" ON %1$I."loyalty_transactions" ("client_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_loyalty_transactions_company_id" ON %1$I."loyalty_transactions" ("company_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_loyalty_transactions_deleted_at" ON %1$I."loyalty_transactions" ("deleted_at")', schema_name);
+
+        -- Modify "appointments" table
+        EXECUTE format('ALTER TABLE %1$I."appointments"
+            ADD COLUMN IF NOT EXISTS "loyalty_discount" bigint NOT NULL DEFAULT 0', schema_name);
+
+        -- Modify "appointments_archive" table
+        EXECUTE format('ALTER TABLE %1$I."appointments_archive"
+            ADD COLUMN IF NOT EXISTS "loyalty_discount" bigint NOT NULL DEFAULT 0', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,38 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Create "employee_invitations" table
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %1$I."employee_invitations" (
+            "id" uuid DEFAULT gen_random_uuid(),
+            "created_at" timestamptz,
+            "updated_at" timestamptz,
+            "deleted_at" timestamptz,
+            "company_id" uuid NOT NULL,
+            "employee_id" uuid NOT NULL,
+            "email" varchar(100) NOT NULL,
+            "status" varchar(20) NOT NULL DEFAULT ''PENDING'',
+            "token" varchar(64),
+            "language" varchar(5) NOT NULL DEFAULT ''en'',
+            "expires_at" timestamptz NOT NULL,
+            "sent_count" bigint NOT NULL DEFAULT 0,
+            "last_sent_at" timestamptz,
+            "invited_by_id" uuid,
+            "accepted_at" timestamptz,
+            "revoked_at" timestamptz,
+            PRIMARY KEY ("id")
+        )', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_employee_invitations_company_id" ON %1$I."employee_invitations" ("company_id")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_employee_invitations_deleted_at" ON %1$I."employee_invitations" ("deleted_at")', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_employee_invitations_employee_id" ON %1$I."employee_invitations" ("employee_id")', schema_name);
+        EXECUTE format('CREATE UNIQUE INDEX IF NOT EXISTS "idx_employee_invitations_token" ON %1$I."employee_invitations" ("token")', schema_name);
+
+        -- Modify "employees" table
+        EXECUTE format('ALTER TABLE %1$I."employees"
+            ADD COLUMN IF NOT EXISTS "status" varchar(20) NOT NULL DEFAULT ''ACTIVE''', schema_name);
+    END LOOP;
+END $$;
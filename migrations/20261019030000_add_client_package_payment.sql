@@ -0,0 +1,14 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Modify "client_packages" table
+        EXECUTE format('ALTER TABLE %1$I."client_packages"
+            ADD COLUMN IF NOT EXISTS "payment_id" uuid', schema_name);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS "idx_client_packages_payment_id" ON %1$I."client_packages" ("payment_id")', schema_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,15 @@
+-- Tenant tables live in the schema of every company
+DO $$
+DECLARE
+    schema_name TEXT;
+BEGIN
+    FOR schema_name IN
+        SELECT nspname FROM pg_namespace WHERE nspname LIKE 'company_%'
+    LOOP
+        -- Modify "client_profiles" table
+        EXECUTE format('ALTER TABLE %1$I."client_profiles"
+            ADD COLUMN IF NOT EXISTS "name" varchar(100),
+            ADD COLUMN IF NOT EXISTS "surname" varchar(100),
+            ADD COLUMN IF NOT EXISTS "phone" varchar(20)', schema_name);
+    END LOOP;
+END $$;
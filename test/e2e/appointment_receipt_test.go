@@ -0,0 +1,125 @@
+package e2e_test
+
+import (
+	"bytes"
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	coreModel "mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+)
+
+func Test_Appointment_Receipt(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	owner := cy.Owner
+	service := cy.Services[0]
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+	other := &testModel.Client{}
+	tt.Describe("Other client creation").Test(other.Set())
+
+	unpaid := &testModel.Appointment{}
+	tt.Describe("Booking without a prepayment").Test(unpaid.CreateAtRandomSlot(200, ct.X_Auth_Token, cy, service, ct, TimeZone))
+
+	tt.Describe("Receipt is not issued for an appointment neither fulfilled nor paid").Test(handler.NewHttpClient().
+		Method("GET").
+		URL("/appointment/"+unpaid.Created.ID.String()+"/receipt").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Service asks for the full price when booking").Test(service.Update(200, map[string]any{
+		"price":           12000,
+		"prepayment_type": coreModel.PrepaymentFull,
+	}, owner.X_Auth_Token, nil))
+
+	a := &testModel.Appointment{}
+	tt.Describe("Booking paid in full").Test(a.CreateAtRandomSlotWith(200, ct.X_Auth_Token, cy, service, ct, TimeZone, func(d *DTO.CreateAppointment) {
+		d.Prepayment = &DTO.PaymentInput{Method: "CREDIT_CARD", Token: "tok_visa"}
+	}))
+	receiptURL := "/appointment/" + a.Created.ID.String() + "/receipt"
+
+	var receipt DTO.AppointmentReceipt
+	tt.Describe("Client gets the receipt").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(receiptURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).
+		ParseResponse(&receipt).Error)
+	tt.Describe("Receipt shows what was paid").Test(func() error {
+		if receipt.AppointmentID != a.Created.ID || receipt.Number == "" || receipt.URL == "" {
+			return fmt.Errorf("unexpected receipt %+v", receipt)
+		}
+		if receipt.Total != 12000 || receipt.Paid != 12000 {
+			return fmt.Errorf("expected 12000 paid of 12000, got %d of %d", receipt.Paid, receipt.Total)
+		}
+		return nil
+	}())
+
+	tt.Describe("Owner gets the same receipt").Test(func() error {
+		var again DTO.AppointmentReceipt
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(receiptURL).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&again).Error; err != nil {
+			return err
+		}
+		if again.Number != receipt.Number || again.IssuedAt != receipt.IssuedAt {
+			return fmt.Errorf("expected receipt %s issued at %s, got %s issued at %s", receipt.Number, receipt.IssuedAt, again.Number, again.IssuedAt)
+		}
+		return nil
+	}())
+
+	tt.Describe("Receipt is downloaded as PDF").Test(func() error {
+		var pdf []byte
+		http := handler.NewHttpClient().
+			Method("GET").
+			URL(receiptURL+"?format=pdf&language=pt").
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&pdf)
+		if http.Error != nil {
+			return http.Error
+		}
+		if !bytes.HasPrefix(pdf, []byte("%PDF")) {
+			return fmt.Errorf("expected a PDF, got %d bytes of %v", len(pdf), http.ResHeaders["Content-Type"])
+		}
+		return nil
+	}())
+
+	tt.Describe("Other client can not get the receipt").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(receiptURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Receipt can not be read without a token").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(receiptURL).
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+}
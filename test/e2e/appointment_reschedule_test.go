@@ -0,0 +1,85 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+	"time"
+)
+
+func Test_Appointment_Reschedule(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+
+	a := &testModel.Appointment{}
+	tt.Describe("Appointment creation").Test(a.CreateAtRandomSlot(200, ct.X_Auth_Token, cy, cy.Services[0], ct, TimeZone))
+	duration := a.Created.EndTime.Sub(a.Created.StartTime)
+
+	newStart, err := a.FindRescheduleSlot(TimeZone)
+	tt.Describe("Finding another slot").Test(err)
+
+	tt.Describe("Rescheduling the appointment").Test(a.Reschedule(200, newStart, cy.Owner.X_Auth_Token, nil))
+
+	tt.Describe("Reloaded appointment keeps its duration from the new start").Test(func() error {
+		start, err := time.Parse(time.RFC3339, newStart)
+		if err != nil {
+			return err
+		}
+		if !a.Created.StartTime.Equal(start) {
+			return fmt.Errorf("expected start_time %s, got %s", start, a.Created.StartTime)
+		}
+		if want := start.Add(duration); !a.Created.EndTime.Equal(want) {
+			return fmt.Errorf("expected end_time %s after rescheduling, got %s", want, a.Created.EndTime)
+		}
+		return nil
+	}())
+
+	tt.Describe("Rescheduling is recorded in the history").Test(func() error {
+		for _, change := range a.Created.History.FieldChanges {
+			if change.Field == "StartTime" {
+				return nil
+			}
+		}
+		return fmt.Errorf("no StartTime change in the history of appointment %s", a.Created.ID)
+	}())
+
+	tt.Describe("Owner reads the StartTime history with who changed it and how").Test(func() error {
+		history, err := a.GetHistory(200, "StartTime", cy.Owner.X_Auth_Token, nil)
+		if err != nil {
+			return err
+		}
+		if history.TotalCount != 1 || len(history.Changes) != 1 {
+			return fmt.Errorf("expected 1 StartTime change, got %d", history.TotalCount)
+		}
+		change := history.Changes[0]
+		if change.ActorID == nil || *change.ActorID != cy.Owner.Created.ID {
+			return fmt.Errorf("expected the owner %s as actor, got %v", cy.Owner.Created.ID, change.ActorID)
+		}
+		if change.Endpoint != "PATCH /appointment/:id" {
+			return fmt.Errorf("unexpected endpoint %q", change.Endpoint)
+		}
+		return nil
+	}())
+
+	_, err = a.GetHistory(200, "", ct.X_Auth_Token, nil)
+	tt.Describe("Client reads the history of its appointment").Test(err)
+
+	other := &testModel.Client{}
+	tt.Describe("Other client creation").Test(other.Set())
+	_, err = a.GetHistory(403, "", other.X_Auth_Token, nil)
+	tt.Describe("Other client can not read the history").Test(err)
+
+	_, err = a.GetHistory(401, "", "", nil)
+	tt.Describe("History can not be read without a token").Test(err)
+}
@@ -0,0 +1,200 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+)
+
+func Test_BookableResource(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	owner := cy.Owner
+	employee := cy.Employees[1]
+	service := cy.Services[0]
+	branchURL := "/branch/" + cy.Branches[0].Created.ID.String()
+	serviceURL := "/service/" + service.Created.ID.String()
+
+	other := &testModel.Company{}
+	tt.Describe("Other company creation").Test(other.Create(200))
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+
+	body := DTO.CreateBookableResource{Name: "Massage room", Kind: "ROOM", Quantity: 1}
+
+	tt.Describe("Employee can not create a resource").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(branchURL+"/resource").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	tt.Describe("Owner of another company can not create a resource").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(branchURL+"/resource").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	var created DTO.BookableResource
+	tt.Describe("Owner creates a resource").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(branchURL+"/resource").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).
+		ParseResponse(&created).Error)
+	resourceURL := branchURL + "/resource/" + created.ID.String()
+
+	tt.Describe("Employee lists the resources of the branch").Test(func() error {
+		var list DTO.BookableResourceList
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(branchURL+"/resources").
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&list).Error; err != nil {
+			return err
+		}
+		if len(list.Resources) != 1 || list.Resources[0].ID != created.ID {
+			return fmt.Errorf("expected resource %s, got %+v", created.ID, list.Resources)
+		}
+		return nil
+	}())
+
+	tt.Describe("Resources can not be listed without a token").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(branchURL+"/resources").
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Employee can not update the resource").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(resourceURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"quantity": 2}).Error)
+
+	var updated DTO.BookableResource
+	tt.Describe("Owner adds a unit to the resource").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(resourceURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"quantity": 2}).
+		ParseResponse(&updated).Error)
+	tt.Describe("Quantity is updated").Test(func() error {
+		if updated.Quantity != 2 {
+			return fmt.Errorf("expected quantity 2, got %d", updated.Quantity)
+		}
+		return nil
+	}())
+
+	requirement := DTO.AddServiceResource{ResourceID: created.ID, Quantity: 1}
+
+	tt.Describe("Employee can not make the service hold the resource").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(serviceURL+"/resource").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(requirement).Error)
+
+	var requirements DTO.ServiceResourceRequirementList
+	tt.Describe("Owner makes the service hold the resource").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(serviceURL+"/resource").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(requirement).
+		ParseResponse(&requirements).Error)
+	tt.Describe("Service holds one of the two units").Test(func() error {
+		if len(requirements.Resources) != 1 {
+			return fmt.Errorf("expected 1 resource, got %d", len(requirements.Resources))
+		}
+		if r := requirements.Resources[0]; r.ResourceID != created.ID || r.Quantity != 1 || r.Capacity != 2 {
+			return fmt.Errorf("unexpected requirement %+v", r)
+		}
+		return nil
+	}())
+
+	tt.Describe("Employee lists the resources of the service").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(serviceURL+"/resources").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	a := &testModel.Appointment{}
+	tt.Describe("Booking the service holding the resource").Test(a.CreateAtRandomSlot(200, ct.X_Auth_Token, cy, service, ct, TimeZone))
+
+	tt.Describe("Employee can not release the resource from the service").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(serviceURL+"/resource/"+created.ID.String()).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner releases the resource from the service").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(serviceURL+"/resource/"+created.ID.String()).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Released resource is not held anymore").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(serviceURL+"/resource/"+created.ID.String()).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Employee can not delete the resource").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(resourceURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner deletes the resource").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(resourceURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Deleted resource is not found").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(resourceURL).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+}
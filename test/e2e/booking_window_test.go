@@ -0,0 +1,171 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+	"time"
+)
+
+func Test_Booking_Window(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	owner := cy.Owner
+	service := cy.Services[0]
+	services := []DTO.ServiceBase{{ID: service.Created.ID}}
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+
+	// The branch is in a time zone where it is 08:xx now, so today has slots left
+	// to check the notice and the cutoff against whenever the test runs
+	offset := 8 - time.Now().UTC().Hour()
+	if offset < -12 {
+		offset += 24
+	}
+	TimeZone := fmt.Sprintf("Etc/GMT%+d", -offset)
+	loc, err := time.LoadLocation(TimeZone)
+	tt.Describe("Time zone").Test(err)
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	at := func(day, hour int) *string {
+		start := today.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour).Format(time.RFC3339)
+		return &start
+	}
+	date := func(day int) string { return today.AddDate(0, 0, day).Format(time.DateOnly) }
+
+	branch := &testModel.Branch{Company: cy}
+	tt.Describe("Branch creation").Test(branch.Create(200, owner.X_Auth_Token, nil))
+	tt.Describe("Branch moves to the time zone").Test(branch.Update(200, map[string]any{"time_zone": TimeZone}, owner.X_Auth_Token, nil))
+	tt.Describe("Branch offers the service").Test(branch.AddService(200, service, owner.X_Auth_Token, nil))
+	tt.Describe("Employee creation").Test(cy.GenerateEmployees(1))
+	employee := cy.Employees[len(cy.Employees)-1]
+	tt.Describe("Employee offers the service").Test(employee.AddService(200, service, &owner.X_Auth_Token, nil))
+	tt.Describe("Employee joins the branch").Test(employee.AddBranch(200, branch, &owner.X_Auth_Token, nil))
+
+	var branchRanges []DTO.CreateBranchWorkRange
+	var employeeRanges []DTO.CreateEmployeeWorkRange
+	for day := range 7 {
+		branchRanges = append(branchRanges, DTO.CreateBranchWorkRange{
+			BranchID:                branch.Created.ID,
+			Weekday:                 uint8(day),
+			StartTime:               "06:00",
+			EndTime:                 "22:00",
+			TimeZone:                TimeZone,
+			BranchWorkRangeServices: DTO.BranchWorkRangeServices{Services: services},
+		})
+		employeeRanges = append(employeeRanges, DTO.CreateEmployeeWorkRange{
+			EmployeeID:                employee.Created.ID,
+			BranchID:                  branch.Created.ID,
+			Weekday:                   uint8(day),
+			StartTime:                 "06:00",
+			EndTime:                   "22:00",
+			TimeZone:                  TimeZone,
+			EmployeeWorkRangeServices: DTO.EmployeeWorkRangeServices{Services: services},
+		})
+	}
+	tt.Describe("Branch opens every day from 06:00 to 22:00").Test(branch.CreateWorkSchedule(200, DTO.CreateBranchWorkSchedule{WorkRanges: branchRanges}, owner.X_Auth_Token, nil))
+	tt.Describe("Employee works every day from 06:00 to 22:00").Test(employee.CreateWorkSchedule(200, DTO.CreateEmployeeWorkSchedule{WorkRanges: employeeRanges}, nil, nil))
+
+	// slots lists the times the service is available at the branch, per date
+	slots := func() (map[string][]string, error) {
+		var availability DTO.ServiceAvailability
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(fmt.Sprintf("/service/%s/availability?date_forward_start=0&date_forward_end=7&timezone=%s", service.Created.ID, TimeZone)).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, cy.Created.ID.String()).
+			Send(nil).
+			ParseResponse(&availability).Error; err != nil {
+			return nil, err
+		}
+		byDate := map[string][]string{}
+		for _, d := range availability.AvailableDates {
+			if d.BranchID != branch.Created.ID {
+				continue
+			}
+			for _, slot := range d.AvailableTimes {
+				byDate[d.Date] = append(byDate[d.Date], slot.Time)
+			}
+		}
+		return byDate, nil
+	}
+	// expectSlots checks the availability against check, which gets the slots of the date
+	expectSlots := func(check func(byDate map[string][]string) error) error {
+		byDate, err := slots()
+		if err != nil {
+			return err
+		}
+		return check(byDate)
+	}
+
+	tt.Describe("Without a booking window the branch is available today and next week").Test(expectSlots(func(byDate map[string][]string) error {
+		if len(byDate[date(0)]) == 0 || len(byDate[date(6)]) == 0 {
+			return fmt.Errorf("expected slots on %s and %s, got %v", date(0), date(6), byDate)
+		}
+		return nil
+	}))
+
+	// --- Company: max days ahead --- //
+
+	tt.Describe("Company books at most one day ahead").Test(cy.Update(200, map[string]any{"max_days_ahead": 1}, owner.X_Auth_Token, nil))
+	tt.Describe("Availability ends tomorrow").Test(expectSlots(func(byDate map[string][]string) error {
+		if len(byDate[date(1)]) == 0 {
+			return fmt.Errorf("expected slots on %s", date(1))
+		}
+		for d := range byDate {
+			if d > date(1) {
+				return fmt.Errorf("slots on %s are too far ahead: %v", d, byDate[d])
+			}
+		}
+		return nil
+	}))
+	tt.Describe("Booking three days ahead is rejected").Test((&testModel.Appointment{}).Create(400, ct.X_Auth_Token, nil, at(3, 10), TimeZone, branch, employee, service, cy, ct))
+	a := &testModel.Appointment{}
+	tt.Describe("Booking tomorrow is accepted").Test(a.Create(200, ct.X_Auth_Token, nil, at(1, 10), TimeZone, branch, employee, service, cy, ct))
+	tt.Describe("Rescheduling three days ahead is rejected").Test(a.Reschedule(400, *at(3, 10), ct.X_Auth_Token, nil))
+
+	// --- Branch: min notice, overriding the days ahead of the company --- //
+
+	tt.Describe("Branch asks for three hours of notice and five days ahead").Test(branch.Update(200, map[string]any{"min_notice_minutes": 180, "max_days_ahead": 5}, owner.X_Auth_Token, nil))
+	earliest := now.Add(3 * time.Hour).Format("15:04")
+	tt.Describe("Availability starts three hours from now and follows the branch horizon").Test(expectSlots(func(byDate map[string][]string) error {
+		for _, slot := range byDate[date(0)] {
+			if slot < earliest {
+				return fmt.Errorf("slot at %s today is before %s", slot, earliest)
+			}
+		}
+		if len(byDate[date(3)]) == 0 {
+			return fmt.Errorf("expected slots on %s once the branch allows five days ahead", date(3))
+		}
+		return nil
+	}))
+	tt.Describe("Booking at 10:00 today is rejected").Test((&testModel.Appointment{}).Create(400, ct.X_Auth_Token, nil, at(0, 10), TimeZone, branch, employee, service, cy, ct))
+	tt.Describe("Rescheduling to 10:00 today is rejected").Test(a.Reschedule(400, *at(0, 10), ct.X_Auth_Token, nil))
+	tt.Describe("Booking three days ahead is accepted at the branch").Test((&testModel.Appointment{}).Create(200, ct.X_Auth_Token, nil, at(3, 10), TimeZone, branch, employee, service, cy, ct))
+
+	// --- Service: same day cutoff --- //
+
+	tt.Describe("Service closes same day bookings at 08:00").Test(service.Update(200, map[string]any{"same_day_cutoff": "08:00"}, owner.X_Auth_Token, nil))
+	tt.Describe("Availability has no slots left today").Test(expectSlots(func(byDate map[string][]string) error {
+		if len(byDate[date(0)]) != 0 {
+			return fmt.Errorf("expected no slots on %s past the cutoff, got %v", date(0), byDate[date(0)])
+		}
+		if len(byDate[date(1)]) == 0 {
+			return fmt.Errorf("expected slots on %s", date(1))
+		}
+		return nil
+	}))
+	tt.Describe("Booking at 14:00 today is rejected").Test((&testModel.Appointment{}).Create(400, ct.X_Auth_Token, nil, at(0, 14), TimeZone, branch, employee, service, cy, ct))
+	tt.Describe("Rescheduling to 14:00 today is rejected").Test(a.Reschedule(400, *at(0, 14), ct.X_Auth_Token, nil))
+	tt.Describe("Rescheduling within the window is accepted").Test(a.Reschedule(200, *at(2, 10), ct.X_Auth_Token, nil))
+}
@@ -0,0 +1,109 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	coreModel "mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_Branch_Availability(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	owner := cy.Owner
+	branch := cy.Branches[0]
+	service := cy.Services[0]
+
+	tt.Describe("Branch work schedule").Test(branch.GetWorkSchedule(200, owner.X_Auth_Token, nil))
+	var wr coreModel.BranchWorkRange
+	if len(branch.Created.WorkSchedule) > 0 {
+		wr = branch.Created.WorkSchedule[0]
+	}
+	timeZone := wr.TimeZone
+
+	// slots lists the times the service is available at the branch on the weekday of the range
+	slots := func() ([]string, error) {
+		var availability DTO.ServiceAvailability
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(fmt.Sprintf("/service/%s/availability?date_forward_start=0&date_forward_end=14&timezone=%s", service.Created.ID, timeZone)).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, cy.Created.ID.String()).
+			Send(nil).
+			ParseResponse(&availability).Error; err != nil {
+			return nil, err
+		}
+		var times []string
+		for _, date := range availability.AvailableDates {
+			day, err := time.Parse(time.DateOnly, date.Date)
+			if err != nil {
+				return nil, err
+			}
+			if date.BranchID != branch.Created.ID || day.Weekday() != wr.Weekday {
+				continue
+			}
+			for _, slot := range date.AvailableTimes {
+				times = append(times, slot.Time)
+			}
+		}
+		return times, nil
+	}
+
+	tt.Describe("Branch is available on the weekday of its range").Test(func() error {
+		if wr.ID == uuid.Nil {
+			return fmt.Errorf("branch %s has no work range", branch.Created.ID)
+		}
+		times, err := slots()
+		if err != nil {
+			return err
+		}
+		if len(times) == 0 {
+			return fmt.Errorf("expected slots on %s at branch %s", wr.Weekday, branch.Created.ID)
+		}
+		return nil
+	}())
+
+	tt.Describe("Owner shortens the branch range").Test(branch.UpdateWorkRange(200, wr.ID.String(), map[string]any{
+		"start_time": "03:00",
+		"end_time":   "04:00",
+		"time_zone":  timeZone,
+		"weekday":    int(wr.Weekday),
+	}, owner.X_Auth_Token, nil))
+
+	tt.Describe("Availability follows the new branch hours").Test(func() error {
+		times, err := slots()
+		if err != nil {
+			return err
+		}
+		for _, at := range times {
+			if at < "03:00" || at >= "04:00" {
+				return fmt.Errorf("slot at %s on %s is outside of the branch hours", at, wr.Weekday)
+			}
+		}
+		return nil
+	}())
+
+	tt.Describe("Owner removes the service from the branch").Test(branch.RemoveService(200, service, owner.X_Auth_Token, nil))
+	tt.Describe("Service is no longer available at the branch").Test(func() error {
+		times, err := slots()
+		if err != nil {
+			return err
+		}
+		if len(times) != 0 {
+			return fmt.Errorf("expected no slots at branch %s, got %v", branch.Created.ID, times)
+		}
+		return nil
+	}())
+}
@@ -0,0 +1,193 @@
+package e2e_test
+
+import (
+	"bytes"
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+	"time"
+)
+
+func Test_Client_DataRequest(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+	other := &testModel.Client{}
+	tt.Describe("Other client creation").Test(other.Set())
+
+	a := &testModel.Appointment{}
+	tt.Describe("Client books an appointment").Test(a.CreateAtRandomSlot(200, ct.X_Auth_Token, cy, cy.Services[0], ct, TimeZone))
+
+	clientURL := "/client/" + ct.Created.ID.String() + "/data"
+
+	tt.Describe("Other client can not export the data of the client").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(clientURL+"/export").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.X_Auth_Token).
+		Send(nil).Error)
+
+	tt.Describe("Company owner can not export the data of the client").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(clientURL+"/export").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, cy.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Data can not be exported without a token").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(clientURL + "/export").
+		ExpectedStatus(401).
+		Send(nil).Error)
+
+	var export DTO.ClientDataRequest
+	tt.Describe("Client requests an export of its data").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(clientURL+"/export").
+		ExpectedStatus(202).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Send(nil).
+		ParseResponse(&export).Error)
+	tt.Describe("Export is queued with who asked for it").Test(func() error {
+		if export.Kind != "EXPORT" || export.Status != "PENDING" {
+			return fmt.Errorf("expected a pending export, got %s %s", export.Status, export.Kind)
+		}
+		if export.RequestedByID == nil || *export.RequestedByID != ct.Created.ID {
+			return fmt.Errorf("expected the client as requester, got %v", export.RequestedByID)
+		}
+		return nil
+	}())
+
+	tt.Describe("Export is completed by the outbox").Test(func() error {
+		deadline := time.Now().Add(30 * time.Second)
+		for {
+			var list DTO.ClientDataRequestList
+			if err := handler.NewHttpClient().
+				Method("GET").
+				URL(clientURL+"/requests").
+				ExpectedStatus(200).
+				Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+				Send(nil).
+				ParseResponse(&list).Error; err != nil {
+				return err
+			}
+			for _, r := range list.DataRequests {
+				if r.ID == export.ID && r.Status == "COMPLETED" {
+					if len(r.CompanyIDs) != 1 || r.CompanyIDs[0] != cy.Created.ID {
+						return fmt.Errorf("expected the export to reach company %s, got %v", cy.Created.ID, r.CompanyIDs)
+					}
+					return nil
+				}
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("export %s not completed after 30s", export.ID)
+			}
+			time.Sleep(time.Second)
+		}
+	}())
+
+	tt.Describe("Other client can not list the data requests").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(clientURL+"/requests").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.X_Auth_Token).
+		Send(nil).Error)
+
+	downloadURL := clientURL + "/requests/" + export.ID.String() + "/download"
+
+	tt.Describe("Client downloads its data as JSON").Test(func() error {
+		var data []byte
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(downloadURL).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+			Send(nil).
+			ParseResponse(&data).Error; err != nil {
+			return err
+		}
+		if !bytes.Contains(data, []byte(ct.Created.Email)) || !bytes.Contains(data, []byte(a.Created.ID.String())) {
+			return fmt.Errorf("expected the email and the appointment of the client in the export")
+		}
+		return nil
+	}())
+
+	tt.Describe("Client downloads its data as CSV").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(downloadURL+"?format=csv").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Send(nil).Error)
+
+	tt.Describe("Unknown export format is rejected").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(downloadURL+"?format=xml").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Send(nil).Error)
+
+	tt.Describe("Other client can not download the export").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(downloadURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.X_Auth_Token).
+		Send(nil).Error)
+
+	tt.Describe("Other client can not erase the client").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(clientURL+"/erasure").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.X_Auth_Token).
+		Send(nil).Error)
+
+	var erasure DTO.ClientDataRequest
+	tt.Describe("Client requests the erasure of its data").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(clientURL+"/erasure").
+		ExpectedStatus(202).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Send(nil).
+		ParseResponse(&erasure).Error)
+	tt.Describe("Erasure is queued").Test(func() error {
+		if erasure.Kind != "ERASURE" || erasure.Status != "PENDING" {
+			return fmt.Errorf("expected a pending erasure, got %s %s", erasure.Status, erasure.Kind)
+		}
+		return nil
+	}())
+
+	tt.Describe("Erased client is gone from the company").Test(func() error {
+		deadline := time.Now().Add(30 * time.Second)
+		for {
+			http := handler.NewHttpClient().
+				Method("GET").
+				URL("/client/"+ct.Created.ID.String()+"/profile").
+				Header(namespace.HeadersKey.Auth, cy.Owner.X_Auth_Token).
+				Header(namespace.HeadersKey.Company, companyID).
+				Send(nil)
+			if http.Error != nil {
+				return http.Error
+			}
+			if http.Status == 404 {
+				return nil
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("client %s still found 30s after the erasure request", ct.Created.ID)
+			}
+			time.Sleep(time.Second)
+		}
+	}())
+}
@@ -0,0 +1,165 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+	"time"
+)
+
+func Test_ClientDependent(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+	other := &testModel.Client{}
+	tt.Describe("Other client creation").Test(other.Set())
+
+	clientURL := "/client/" + ct.Created.ID.String()
+	birth := time.Date(2018, time.April, 2, 15, 30, 0, 0, time.UTC)
+	body := DTO.CreateClientDependent{Name: "Maria", BirthDate: &birth, Notes: "Allergic to latex"}
+
+	tt.Describe("Other client can not add a dependent to the client").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(clientURL+"/dependent").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.X_Auth_Token).
+		Send(body).Error)
+
+	tt.Describe("Company owner can not add a dependent to the client").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(clientURL+"/dependent").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, cy.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	tt.Describe("Dependent can not be added without a token").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(clientURL + "/dependent").
+		ExpectedStatus(401).
+		Send(body).Error)
+
+	future := time.Now().AddDate(1, 0, 0)
+	tt.Describe("Dependent can not be born in the future").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(clientURL+"/dependent").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Send(DTO.CreateClientDependent{Name: "Unborn", BirthDate: &future}).Error)
+
+	var dependent DTO.ClientDependent
+	tt.Describe("Client adds a dependent").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(clientURL+"/dependent").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Send(body).
+		ParseResponse(&dependent).Error)
+	tt.Describe("Birth date is kept as a date").Test(func() error {
+		if dependent.ClientID != ct.Created.ID || dependent.Name != "Maria" {
+			return fmt.Errorf("unexpected dependent %+v", dependent)
+		}
+		if dependent.BirthDate == nil || !dependent.BirthDate.Equal(time.Date(2018, time.April, 2, 0, 0, 0, 0, time.UTC)) {
+			return fmt.Errorf("expected birth date 2018-04-02, got %v", dependent.BirthDate)
+		}
+		return nil
+	}())
+	dependentURL := clientURL + "/dependent/" + dependent.ID.String()
+
+	tt.Describe("Client lists its dependents").Test(func() error {
+		var list DTO.ClientDependentList
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(clientURL+"/dependents").
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+			Send(nil).
+			ParseResponse(&list).Error; err != nil {
+			return err
+		}
+		if len(list.Dependents) != 1 || list.Dependents[0].ID != dependent.ID {
+			return fmt.Errorf("expected dependent %s, got %+v", dependent.ID, list.Dependents)
+		}
+		return nil
+	}())
+
+	tt.Describe("Other client can not list the dependents of the client").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(clientURL+"/dependents").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.X_Auth_Token).
+		Send(nil).Error)
+
+	tt.Describe("Other client can not update the dependent").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(dependentURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.X_Auth_Token).
+		Send(map[string]any{"name": "Mariana"}).Error)
+
+	var updated DTO.ClientDependent
+	tt.Describe("Client clears the notes of the dependent").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(dependentURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Send(map[string]any{"name": "Mariana", "notes": ""}).
+		ParseResponse(&updated).Error)
+	tt.Describe("Name and notes are updated").Test(func() error {
+		if updated.Name != "Mariana" || updated.Notes != "" || updated.BirthDate == nil {
+			return fmt.Errorf("unexpected dependent %+v", updated)
+		}
+		return nil
+	}())
+
+	a := &testModel.Appointment{}
+	tt.Describe("Client books an appointment for the dependent").Test(a.CreateAtRandomSlotWith(200, ct.X_Auth_Token, cy, cy.Services[0], ct, TimeZone, func(d *DTO.CreateAppointment) {
+		d.DependentID = &dependent.ID
+	}))
+	tt.Describe("Appointment is attended by the dependent").Test(func() error {
+		if a.Created.DependentID == nil || *a.Created.DependentID != dependent.ID {
+			return fmt.Errorf("expected dependent %s, got %v", dependent.ID, a.Created.DependentID)
+		}
+		return nil
+	}())
+
+	tt.Describe("Other client can not book for the dependent").Test((&testModel.Appointment{}).CreateAtRandomSlotWith(404, other.X_Auth_Token, cy, cy.Services[0], other, TimeZone, func(d *DTO.CreateAppointment) {
+		d.DependentID = &dependent.ID
+	}))
+
+	tt.Describe("Other client can not remove the dependent").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(dependentURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.X_Auth_Token).
+		Send(nil).Error)
+
+	tt.Describe("Client removes the dependent").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(dependentURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Send(nil).Error)
+
+	tt.Describe("Removed dependent is not found").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(dependentURL).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Send(map[string]any{"name": "Maria"}).Error)
+
+	tt.Describe("Appointment of the removed dependent is kept").Test(a.GetById(200, ct.X_Auth_Token, &companyID))
+}
@@ -0,0 +1,97 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/lib"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+)
+
+func Test_Client_Guest(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+
+	tt.Describe("Email with an account can not check out as a guest").Test((&testModel.Client{}).CreateGuest(400, DTO.CreateGuestClient{
+		Name:    "Taken",
+		Surname: "Email",
+		Email:   ct.Created.Email,
+		Phone:   lib.GenerateRandomPhoneNumber(),
+	}))
+
+	details := DTO.CreateGuestClient{
+		Name:    lib.GenerateRandomName("Guest Name"),
+		Surname: lib.GenerateRandomName("Guest Surname"),
+		Email:   lib.GenerateRandomEmail("guest"),
+		Phone:   lib.GenerateRandomPhoneNumber(),
+	}
+	guest := &testModel.Client{}
+	tt.Describe("Guest checks out with its contact details").Test(guest.CreateGuest(200, details))
+	tt.Describe("Guest has no account").Test(func() error {
+		if !guest.Created.IsGuest || guest.Created.Name != details.Name {
+			return fmt.Errorf("expected guest %s, got %+v", details.Name, guest.Created.ClientMeta)
+		}
+		return nil
+	}())
+
+	code, err := guest.GetLoginCodeFromEmail()
+	tt.Describe("Guest receives a login code").Test(err)
+	tt.Describe("Guest verifies the code").Test(guest.VerifyGuest(200, code))
+
+	a := &testModel.Appointment{}
+	tt.Describe("Guest books an appointment").Test(a.CreateAtRandomSlot(200, guest.X_Auth_Token, cy, cy.Services[0], guest, TimeZone))
+
+	renamed := details
+	renamed.Name = lib.GenerateRandomName("Guest New Name")
+	tt.Describe("Guest checks out again with another name").Test(guest.CreateGuest(200, renamed))
+	tt.Describe("Stored details are kept until the code is verified").Test(func() error {
+		if err := guest.GetByEmail(200); err != nil {
+			return err
+		}
+		if guest.Created.Name != details.Name {
+			return fmt.Errorf("expected name %s before verification, got %s", details.Name, guest.Created.Name)
+		}
+		return nil
+	}())
+
+	code, err = guest.GetLoginCodeFromEmail()
+	tt.Describe("Guest receives another login code").Test(err)
+	tt.Describe("Guest verifies the new code").Test(guest.VerifyGuest(200, code))
+	tt.Describe("New name is applied once verified").Test(func() error {
+		if guest.Created.Name != renamed.Name {
+			return fmt.Errorf("expected name %s after verification, got %s", renamed.Name, guest.Created.Name)
+		}
+		return nil
+	}())
+
+	password := lib.GenerateValidPassword()
+	tt.Describe("Other client can not upgrade the guest").Test(guest.UpgradeGuest(403, password, ct.X_Auth_Token))
+	tt.Describe("Guest can not be upgraded without a token").Test(guest.UpgradeGuest(401, password, ""))
+	tt.Describe("Guest sets a password").Test(guest.UpgradeGuest(200, password, guest.X_Auth_Token))
+	tt.Describe("Upgraded client can not be upgraded again").Test(guest.UpgradeGuest(409, lib.GenerateValidPassword(), guest.X_Auth_Token))
+	tt.Describe("Upgraded client logs in with its password").Test(guest.LoginWithPassword(200))
+	tt.Describe("Upgraded client keeps its appointment").Test(func() error {
+		if guest.Created.IsGuest {
+			return fmt.Errorf("expected the client to have an account")
+		}
+		list, err := guest.GetAppointments(200, 1, 10, "", "", "", TimeZone, &guest.X_Auth_Token, nil)
+		if err != nil {
+			return err
+		}
+		if list.TotalCount != 1 {
+			return fmt.Errorf("expected 1 appointment, got %d", list.TotalCount)
+		}
+		return nil
+	}())
+}
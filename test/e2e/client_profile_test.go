@@ -0,0 +1,156 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func Test_ClientProfile(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	owner := cy.Owner
+	employee := cy.Employees[1]
+
+	other := &testModel.Company{}
+	tt.Describe("Other company creation").Test(other.Create(200))
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+
+	a := &testModel.Appointment{}
+	tt.Describe("Client books an appointment").Test(a.CreateAtRandomSlot(200, ct.X_Auth_Token, cy, cy.Services[0], ct, TimeZone))
+
+	profileURL := "/client/" + ct.Created.ID.String() + "/profile"
+
+	var profile DTO.ClientProfile
+	tt.Describe("Employee gets the profile of the client").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(profileURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).
+		ParseResponse(&profile).Error)
+	tt.Describe("Profile counts the appointment").Test(func() error {
+		if profile.Client.ID != ct.Created.ID {
+			return fmt.Errorf("expected client %s, got %s", ct.Created.ID, profile.Client.ID)
+		}
+		if profile.Stats.Appointments != 1 || profile.Stats.NextAppointmentAt == nil {
+			return fmt.Errorf("expected 1 upcoming appointment, got %+v", profile.Stats)
+		}
+		return nil
+	}())
+
+	tt.Describe("Client can not read the notes the company keeps").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(profileURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner of another company can not read the profile").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(profileURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Profile can not be read without a token").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(profileURL).
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Client can not update its profile").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(profileURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"notes": "Self note"}).Error)
+
+	var updated DTO.ClientProfile
+	tt.Describe("Owner tags the client and writes a note").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(profileURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{
+			"tags":              []string{" VIP ", "vip", "Regular"},
+			"notes":             "Prefers mornings",
+			"marketing_consent": true,
+		}).
+		ParseResponse(&updated).Error)
+	tt.Describe("Tags are normalized and consent is dated").Test(func() error {
+		if !slices.Equal(updated.Tags, []string{"vip", "regular"}) {
+			return fmt.Errorf("expected tags [vip regular], got %v", updated.Tags)
+		}
+		if updated.Notes != "Prefers mornings" || !updated.MarketingConsent || updated.MarketingConsentAt == nil {
+			return fmt.Errorf("unexpected profile %+v", updated)
+		}
+		return nil
+	}())
+
+	listClients := func(query string) (*DTO.ClientProfileList, error) {
+		var list DTO.ClientProfileList
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL("/company/"+companyID+"/clients?"+query).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&list).Error; err != nil {
+			return nil, err
+		}
+		return &list, nil
+	}
+
+	tt.Describe("Employee finds the client by tag and email").Test(func() error {
+		list, err := listClients("tag=VIP&search=" + url.QueryEscape(ct.Created.Email))
+		if err != nil {
+			return err
+		}
+		if list.TotalCount != 1 || list.Clients[0].Client.ID != ct.Created.ID {
+			return fmt.Errorf("expected client %s, got %+v", ct.Created.ID, list.Clients)
+		}
+		return nil
+	}())
+
+	tt.Describe("Client is not listed under another tag").Test(func() error {
+		list, err := listClients("tag=blocked")
+		if err != nil {
+			return err
+		}
+		if list.TotalCount != 0 {
+			return fmt.Errorf("expected no client tagged blocked, got %d", list.TotalCount)
+		}
+		return nil
+	}())
+
+	tt.Describe("Client can not list the clients of the company").Test(handler.NewHttpClient().
+		Method("GET").
+		URL("/company/"+companyID+"/clients").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+}
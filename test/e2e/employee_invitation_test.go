@@ -0,0 +1,432 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/core/src/lib"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_EmployeeInvitation(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	owner := cy.Owner
+	employee := cy.Employees[1]
+
+	other := &testModel.Company{}
+	tt.Describe("Other company creation").Test(other.Create(200))
+
+	invitee := &testModel.Employee{
+		Created: &model.Employee{Email: lib.GenerateRandomEmail("invited")},
+		Company: cy,
+	}
+	invite := func(email string) DTO.InviteEmployee {
+		return DTO.InviteEmployee{
+			Email:     email,
+			Name:      lib.GenerateRandomName("Invited"),
+			Surname:   lib.GenerateRandomName("Employee"),
+			Phone:     lib.GenerateRandomPhoneNumber(),
+			BranchIDs: []uuid.UUID{cy.Branches[0].Created.ID},
+		}
+	}
+	inviteURL := "/company/" + companyID + "/employee_invitation?email_language=en"
+
+	tt.Describe("Employee can not invite employees").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(inviteURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(invite(invitee.Created.Email)).Error)
+
+	tt.Describe("Owner of another company can not invite employees").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(inviteURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(invite(invitee.Created.Email)).Error)
+
+	tt.Describe("Employees can not be invited without a token").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(inviteURL).
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(invite(invitee.Created.Email)).Error)
+
+	tt.Describe("Email of an employee of the company can not be invited").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(inviteURL).
+		ExpectedStatus(409).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(invite(employee.Created.Email)).Error)
+
+	var invited DTO.EmployeeInvitation
+	tt.Describe("Owner invites an employee").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(inviteURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(invite(invitee.Created.Email)).
+		ParseResponse(&invited).Error)
+	tt.Describe("Invitation is pending and sent once").Test(func() error {
+		if invited.Status != "PENDING" || invited.SentCount != 1 || invited.Email != invitee.Created.Email {
+			return fmt.Errorf("unexpected invitation %+v", invited)
+		}
+		if invited.InvitedByID == nil || *invited.InvitedByID != owner.Created.ID {
+			return fmt.Errorf("expected owner %s as inviter, got %v", owner.Created.ID, invited.InvitedByID)
+		}
+		return nil
+	}())
+	invitee.Created.ID = invited.EmployeeID
+	invitationURL := "/employee/" + invitee.Created.ID.String() + "/invitation"
+
+	tt.Describe("Employee can not get the invitation").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(invitationURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner gets the invitation").Test(func() error {
+		var latest DTO.EmployeeInvitation
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(invitationURL).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&latest).Error; err != nil {
+			return err
+		}
+		if latest.ID != invited.ID || latest.Status != "PENDING" {
+			return fmt.Errorf("expected invitation %s pending, got %+v", invited.ID, latest)
+		}
+		return nil
+	}())
+
+	statusURL := "/employee/" + invitee.Created.ID.String() + "/status"
+
+	tt.Describe("Invited employee can not be suspended").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(statusURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.UpdateEmployeeStatus{Status: "SUSPENDED"}).Error)
+
+	tt.Describe("Invitation can not be sent again right away").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(invitationURL+"/resend").
+		ExpectedStatus(429).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	waitForLink := func(previous string) (string, error) {
+		deadline := time.Now().Add(30 * time.Second)
+		for {
+			token, err := invitee.GetInvitationTokenFromEmail()
+			if err == nil && token != previous {
+				return token, nil
+			}
+			if err == nil {
+				err = fmt.Errorf("only the previous link was emailed")
+			}
+			if time.Now().After(deadline) {
+				return "", fmt.Errorf("invitation link not emailed after 30s: %w", err)
+			}
+			time.Sleep(time.Second)
+		}
+	}
+
+	var revokedToken string
+	tt.Describe("Invitee is emailed a link to accept the invitation").Test(func() (err error) {
+		revokedToken, err = waitForLink("")
+		return err
+	}())
+
+	tt.Describe("Employee can not revoke the invitation").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(invitationURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner revokes the invitation").Test(func() error {
+		var revoked DTO.EmployeeInvitation
+		if err := handler.NewHttpClient().
+			Method("DELETE").
+			URL(invitationURL).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&revoked).Error; err != nil {
+			return err
+		}
+		if revoked.Status != "REVOKED" || revoked.RevokedAt == nil {
+			return fmt.Errorf("expected a revoked invitation, got %+v", revoked)
+		}
+		return nil
+	}())
+
+	tt.Describe("Revoked link stops working").Test(handler.NewHttpClient().
+		Method("GET").
+		URL("/employee_invitation/"+revokedToken).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Revoked invitation can not be sent again").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(invitationURL+"/resend").
+		ExpectedStatus(409).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Revoked invitation can not be revoked again").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(invitationURL).
+		ExpectedStatus(409).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner invites the employee again").Test(func() error {
+		var again DTO.EmployeeInvitation
+		if err := handler.NewHttpClient().
+			Method("POST").
+			URL(inviteURL).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(invite(invitee.Created.Email)).
+			ParseResponse(&again).Error; err != nil {
+			return err
+		}
+		if again.EmployeeID != invitee.Created.ID || again.ID == invited.ID || again.Status != "PENDING" {
+			return fmt.Errorf("expected a new invitation for employee %s, got %+v", invitee.Created.ID, again)
+		}
+		return nil
+	}())
+
+	var token string
+	tt.Describe("Invitee is emailed a new link").Test(func() (err error) {
+		token, err = waitForLink(revokedToken)
+		return err
+	}())
+	tokenURL := "/employee_invitation/" + token
+
+	tt.Describe("Invitee opens the emailed link").Test(func() error {
+		var details DTO.EmployeeInvitationDetails
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(tokenURL).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&details).Error; err != nil {
+			return err
+		}
+		if details.Email != invitee.Created.Email {
+			return fmt.Errorf("expected the invitation of %s, got %+v", invitee.Created.Email, details)
+		}
+		return nil
+	}())
+
+	tt.Describe("Weak password is rejected").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(tokenURL).
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.AcceptEmployeeInvitation{Password: "123"}).Error)
+
+	password := lib.GenerateValidPassword()
+	tt.Describe("Invitee accepts the invitation").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(tokenURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.AcceptEmployeeInvitation{Password: password}).Error)
+	invitee.Created.Password = password
+
+	tt.Describe("Link stops working once accepted").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(tokenURL).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.AcceptEmployeeInvitation{Password: lib.GenerateValidPassword()}).Error)
+
+	tt.Describe("Invitee logs in with the password set").Test(invitee.LoginByPassword(200, password, nil))
+
+	tt.Describe("Invitation is accepted").Test(func() error {
+		var latest DTO.EmployeeInvitation
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(invitationURL).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&latest).Error; err != nil {
+			return err
+		}
+		if latest.Status != "ACCEPTED" || latest.AcceptedAt == nil {
+			return fmt.Errorf("expected an accepted invitation, got %+v", latest)
+		}
+		return nil
+	}())
+
+	tt.Describe("Employee can not suspend employees").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(statusURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.UpdateEmployeeStatus{Status: "SUSPENDED"}).Error)
+
+	tt.Describe("Owner of another company can not suspend employees").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(statusURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.UpdateEmployeeStatus{Status: "SUSPENDED"}).Error)
+
+	tt.Describe("Owner can not change its own status").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL("/employee/"+owner.Created.ID.String()+"/status").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.UpdateEmployeeStatus{Status: "SUSPENDED"}).Error)
+
+	tt.Describe("Unknown status is rejected").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(statusURL).
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.UpdateEmployeeStatus{Status: "FIRED"}).Error)
+
+	setStatus := func(e *testModel.Employee, status string) error {
+		var updated DTO.EmployeeFull
+		if err := handler.NewHttpClient().
+			Method("PATCH").
+			URL("/employee/"+e.Created.ID.String()+"/status").
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(DTO.UpdateEmployeeStatus{Status: status}).
+			ParseResponse(&updated).Error; err != nil {
+			return err
+		}
+		if updated.Status != status {
+			return fmt.Errorf("expected status %s, got %s", status, updated.Status)
+		}
+		return nil
+	}
+
+	tt.Describe("Owner suspends the employee").Test(setStatus(invitee, "SUSPENDED"))
+	tt.Describe("Suspended employee loses its session").Test(invitee.GetById(403, nil, nil))
+	tt.Describe("Suspended employee can not log in").Test(invitee.LoginByPassword(403, password, nil))
+
+	tt.Describe("Owner reactivates the employee").Test(setStatus(invitee, "ACTIVE"))
+	tt.Describe("Reactivated employee logs in again").Test(invitee.LoginByPassword(200, password, nil))
+
+	// A suspended employee keeps its appointments but takes no new ones
+	TimeZone := "America/Sao_Paulo"
+	service := cy.Services[0]
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+
+	// employeeSlot returns the first slot the employee is available at, nil when none
+	employeeSlot := func(e *testModel.Employee) (*testModel.Branch, string, error) {
+		var availability DTO.ServiceAvailability
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(fmt.Sprintf("/service/%s/availability?date_forward_start=1&date_forward_end=14&timezone=%s", service.Created.ID, TimeZone)).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&availability).Error; err != nil {
+			return nil, "", err
+		}
+		loc, err := time.LoadLocation(TimeZone)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, date := range availability.AvailableDates {
+			for _, slot := range date.AvailableTimes {
+				if !slices.Contains(slot.EmployeesID, e.Created.ID) {
+					continue
+				}
+				start, err := time.ParseInLocation("2006-01-02 15:04", date.Date+" "+slot.Time, loc)
+				if err != nil {
+					return nil, "", err
+				}
+				for _, b := range cy.Branches {
+					if b.Created.ID == date.BranchID {
+						return b, start.Format(time.RFC3339), nil
+					}
+				}
+			}
+		}
+		return nil, "", nil
+	}
+
+	var branch *testModel.Branch
+	var startTime string
+	tt.Describe("Active employee is available").Test(func() (err error) {
+		branch, startTime, err = employeeSlot(employee)
+		if err == nil && branch == nil {
+			err = fmt.Errorf("employee %s has no slot", employee.Created.ID)
+		}
+		return err
+	}())
+
+	tt.Describe("Owner suspends an employee with a schedule").Test(setStatus(employee, "SUSPENDED"))
+	tt.Describe("Suspended employee leaves the availability").Test(func() error {
+		b, at, err := employeeSlot(employee)
+		if err != nil {
+			return err
+		}
+		if b != nil {
+			return fmt.Errorf("suspended employee %s is still available at %s", employee.Created.ID, at)
+		}
+		return nil
+	}())
+	tt.Describe("Suspended employee can not be booked").Test((&testModel.Appointment{}).Create(400, ct.X_Auth_Token, nil, &startTime, TimeZone, branch, employee, service, cy, ct))
+
+	tt.Describe("Owner reactivates the employee with a schedule").Test(setStatus(employee, "ACTIVE"))
+	tt.Describe("Reactivated employee is available again").Test(func() error {
+		b, _, err := employeeSlot(employee)
+		if err == nil && b == nil {
+			err = fmt.Errorf("reactivated employee %s has no slot", employee.Created.ID)
+		}
+		return err
+	}())
+	tt.Describe("Reactivated employee is booked").Test((&testModel.Appointment{}).Create(200, ct.X_Auth_Token, nil, &startTime, TimeZone, branch, employee, service, cy, ct))
+}
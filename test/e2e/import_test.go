@@ -0,0 +1,250 @@
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/core/src/lib"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"net/url"
+	"testing"
+)
+
+func Test_Import(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	owner := cy.Owner
+	employee := cy.Employees[1]
+	service := cy.Services[0]
+	branch := cy.Branches[0]
+	price := service.Created.Price
+
+	other := &testModel.Company{}
+	tt.Describe("Other company creation").Test(other.Create(200))
+
+	spreadsheet := func(name string, records ...[]string) handler.Files {
+		var b bytes.Buffer
+		w := csv.NewWriter(&b)
+		_ = w.WriteAll(records)
+		return handler.Files{"file": {Name: name, Content: b.Bytes()}}
+	}
+	newService := lib.GenerateRandomName("Imported Service")
+	services := func() handler.Files {
+		return spreadsheet("services.csv",
+			[]string{"name", "description", "price", "duration", "branches"},
+			[]string{newService, "Imported from the old system", "150.00", "60", branch.Created.Name},
+			[]string{service.Created.Name, "", "99.90", "", ""},
+			[]string{"", "Row without a name", "10.00", "30", ""},
+		)
+	}
+	importURL := "/company/" + companyID + "/import/"
+
+	importRecords := func(kind, query string, status int, token string, file handler.Files) (*DTO.ImportResult, error) {
+		var result DTO.ImportResult
+		if err := handler.NewHttpClient().
+			Method("POST").
+			URL(importURL+kind+query).
+			ExpectedStatus(status).
+			Header(namespace.HeadersKey.Auth, token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(file).
+			ParseResponse(&result).Error; err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+	expectCounts := func(result *DTO.ImportResult, created, updated, failed int) error {
+		if result.Created != created || result.Updated != updated || result.Failed != failed {
+			return fmt.Errorf("expected %d created, %d updated and %d failed, got %+v", created, updated, failed, result.Rows)
+		}
+		return nil
+	}
+
+	tt.Describe("Employee can not import services").Test(func() error {
+		_, err := importRecords("services", "", 403, employee.X_Auth_Token, services())
+		return err
+	}())
+
+	tt.Describe("Owner of another company can not import services").Test(func() error {
+		_, err := importRecords("services", "", 403, other.Owner.X_Auth_Token, services())
+		return err
+	}())
+
+	tt.Describe("Services can not be imported without a token").Test(func() error {
+		_, err := importRecords("services", "", 401, "", services())
+		return err
+	}())
+
+	tt.Describe("Unknown kind of record is rejected").Test(func() error {
+		_, err := importRecords("products", "", 400, owner.X_Auth_Token, services())
+		return err
+	}())
+
+	tt.Describe("Unsupported file type is rejected").Test(func() error {
+		_, err := importRecords("services", "", 400, owner.X_Auth_Token, handler.Files{"file": {Name: "services.pdf", Content: []byte("%PDF-1.4")}})
+		return err
+	}())
+
+	tt.Describe("Dry run reports every row").Test(func() error {
+		result, err := importRecords("services", "?dry_run=true", 200, owner.X_Auth_Token, services())
+		if err != nil {
+			return err
+		}
+		if !result.DryRun {
+			return fmt.Errorf("expected a dry run")
+		}
+		if err := expectCounts(result, 1, 1, 1); err != nil {
+			return err
+		}
+		if row := result.Rows[2]; row.Line != 4 || row.Action != "FAILED" || len(row.Errors) == 0 {
+			return fmt.Errorf("expected line 4 to fail with its reason, got %+v", row)
+		}
+		return nil
+	}())
+
+	tt.Describe("Dry run saves nothing").Test(func() error {
+		if err := service.GetById(200, owner.X_Auth_Token, &companyID); err != nil {
+			return err
+		}
+		if service.Created.Price != price {
+			return fmt.Errorf("expected price %d kept after the dry run, got %d", price, service.Created.Price)
+		}
+		return nil
+	}())
+
+	tt.Describe("Owner imports the services").Test(func() error {
+		result, err := importRecords("services", "", 200, owner.X_Auth_Token, services())
+		if err != nil {
+			return err
+		}
+		return expectCounts(result, 1, 1, 1)
+	}())
+
+	tt.Describe("Existing service is updated").Test(func() error {
+		if err := service.GetById(200, owner.X_Auth_Token, &companyID); err != nil {
+			return err
+		}
+		if service.Created.Price != 9990 {
+			return fmt.Errorf("expected price 9990, got %d", service.Created.Price)
+		}
+		return nil
+	}())
+
+	tt.Describe("Importing the file again updates rather than duplicates").Test(func() error {
+		result, err := importRecords("services", "", 200, owner.X_Auth_Token, services())
+		if err != nil {
+			return err
+		}
+		return expectCounts(result, 0, 2, 1)
+	}())
+
+	clientEmail := lib.GenerateRandomEmail("imported")
+	tt.Describe("Owner imports a client").Test(func() error {
+		result, err := importRecords("clients", "", 200, owner.X_Auth_Token, spreadsheet("clients.csv",
+			[]string{"name", "surname", "email", "phone", "tags"},
+			[]string{"Imported", "Client", clientEmail, lib.GenerateRandomPhoneNumber(), "Imported|VIP"},
+		))
+		if err != nil {
+			return err
+		}
+		return expectCounts(result, 1, 0, 0)
+	}())
+
+	tt.Describe("Imported client is listed by its tags").Test(func() error {
+		var list DTO.ClientProfileList
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL("/company/"+companyID+"/clients?tag=imported&search="+url.QueryEscape(clientEmail)).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&list).Error; err != nil {
+			return err
+		}
+		if list.TotalCount != 1 || list.Clients[0].Client.Name != "Imported" {
+			return fmt.Errorf("expected the imported client, got %+v", list.Clients)
+		}
+		return nil
+	}())
+
+	// profileOf reads the profile of the client at the company
+	profileOf := func(clientID string) (*DTO.ClientProfile, error) {
+		var profile DTO.ClientProfile
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL("/client/"+clientID+"/profile").
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&profile).Error; err != nil {
+			return nil, err
+		}
+		return &profile, nil
+	}
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+	importedPhone := lib.GenerateRandomPhoneNumber()
+	tt.Describe("Owner imports a client that already has an account").Test(func() error {
+		result, err := importRecords("clients", "", 200, owner.X_Auth_Token, spreadsheet("clients.csv",
+			[]string{"name", "surname", "email", "phone"},
+			[]string{"Renamed", "Elsewhere", ct.Created.Email, importedPhone},
+		))
+		if err != nil {
+			return err
+		}
+		return expectCounts(result, 0, 1, 0)
+	}())
+	tt.Describe("Client keeps its details and the company keeps the imported ones").Test(func() error {
+		profile, err := profileOf(ct.Created.ID.String())
+		if err != nil {
+			return err
+		}
+		if profile.Client.Name != ct.Created.Name || profile.Client.Surname != ct.Created.Surname || profile.Client.Phone != ct.Created.Phone {
+			return fmt.Errorf("expected the client details to be kept, got %+v", profile.Client)
+		}
+		if profile.Name != "Renamed" || profile.Surname != "Elsewhere" || profile.Phone != importedPhone {
+			return fmt.Errorf("expected the imported details in the profile, got %s %s %s", profile.Name, profile.Surname, profile.Phone)
+		}
+		return nil
+	}())
+
+	tt.Describe("Owner imports the guest again with another name").Test(func() error {
+		result, err := importRecords("clients", "", 200, owner.X_Auth_Token, spreadsheet("clients.csv",
+			[]string{"name", "surname", "email"},
+			[]string{"Changed", "Guest", clientEmail},
+		))
+		if err != nil {
+			return err
+		}
+		return expectCounts(result, 0, 1, 0)
+	}())
+	tt.Describe("Guest keeps its name and the company keeps the new one").Test(func() error {
+		var list DTO.ClientProfileList
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL("/company/"+companyID+"/clients?search="+url.QueryEscape("Changed Guest")).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&list).Error; err != nil {
+			return err
+		}
+		if list.TotalCount != 1 || list.Clients[0].Client.Name != "Imported" || list.Clients[0].Name != "Changed" {
+			return fmt.Errorf("expected the guest found by the name kept by the company, got %+v", list.Clients)
+		}
+		return nil
+	}())
+}
@@ -0,0 +1,317 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func Test_IntakeForm(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	owner := cy.Owner
+	employee := cy.Employees[1]
+	service := cy.Services[0]
+
+	other := &testModel.Company{}
+	tt.Describe("Other company creation").Test(other.Create(200))
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+	late := &testModel.Client{}
+	tt.Describe("Client answering by email creation").Test(late.Set())
+
+	body := DTO.CreateIntakeForm{
+		CompanyID:   cy.Created.ID,
+		Name:        "Health questionnaire",
+		Description: "Asked before the first appointment",
+		ServiceIDs:  []uuid.UUID{service.Created.ID},
+		Fields: []DTO.IntakeField{
+			{Key: "allergies", Label: "Do you have any allergies?", Type: "LONG_TEXT", Required: true},
+		},
+	}
+
+	tt.Describe("Employee can not create an intake form").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/intake_form").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	tt.Describe("Owner of another company can not create an intake form").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/intake_form").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	var created DTO.IntakeForm
+	tt.Describe("Owner creates an intake form").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/intake_form").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).
+		ParseResponse(&created).Error)
+	tt.Describe("Intake form starts at its first version").Test(func() error {
+		if created.Version != 1 || !created.IsActive || len(created.Fields) != 1 {
+			return fmt.Errorf("unexpected intake form %+v", created)
+		}
+		return nil
+	}())
+	formURL := "/intake_form/" + created.ID.String()
+
+	tt.Describe("Employee gets the intake form").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(formURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Intake form can not be read without a token").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(formURL).
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Employee lists the intake forms of the company").Test(func() error {
+		var list DTO.IntakeFormList
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL("/company/"+companyID+"/intake_forms?active=true").
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&list).Error; err != nil {
+			return err
+		}
+		if len(list.IntakeForms) != 1 || list.IntakeForms[0].ID != created.ID {
+			return fmt.Errorf("expected intake form %s, got %+v", created.ID, list.IntakeForms)
+		}
+		return nil
+	}())
+
+	tt.Describe("Client can not list the intake forms of the company").Test(handler.NewHttpClient().
+		Method("GET").
+		URL("/company/"+companyID+"/intake_forms").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Employee can not update the intake form").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(formURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"name": "Health check"}).Error)
+
+	fields := append(body.Fields, DTO.IntakeField{Key: "smoker", Label: "Do you smoke?", Type: "BOOLEAN"})
+	var updated DTO.IntakeForm
+	tt.Describe("Owner adds a field to the intake form").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(formURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.UpdateIntakeForm{Fields: &fields}).
+		ParseResponse(&updated).Error)
+	tt.Describe("Changing the fields makes a new version").Test(func() error {
+		if updated.Version != 2 || len(updated.Fields) != 2 {
+			return fmt.Errorf("expected version 2 with 2 fields, got %+v", updated)
+		}
+		return nil
+	}())
+
+	tt.Describe("Anyone gets the intake forms asked by the service").Test(func() error {
+		var list DTO.IntakeFormList
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL("/service/"+service.Created.ID.String()+"/intake_forms").
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&list).Error; err != nil {
+			return err
+		}
+		if len(list.IntakeForms) != 1 || list.IntakeForms[0].Version != 2 {
+			return fmt.Errorf("expected intake form %s at version 2, got %+v", created.ID, list.IntakeForms)
+		}
+		return nil
+	}())
+
+	tt.Describe("Booking with a required answer missing is rejected").Test((&testModel.Appointment{}).CreateAtRandomSlotWith(400, ct.X_Auth_Token, cy, service, ct, TimeZone, func(d *DTO.CreateAppointment) {
+		d.Intake = []DTO.IntakeSubmission{{FormID: created.ID, Answers: map[string]any{"smoker": false}}}
+	}))
+
+	a := &testModel.Appointment{}
+	tt.Describe("Client answers the intake form when booking").Test(a.CreateAtRandomSlotWith(200, ct.X_Auth_Token, cy, service, ct, TimeZone, func(d *DTO.CreateAppointment) {
+		d.Intake = []DTO.IntakeSubmission{{FormID: created.ID, Answers: map[string]any{"allergies": " Latex ", "smoker": false}}}
+	}))
+	intakeURL := "/appointment/" + a.Created.ID.String() + "/intake"
+
+	tt.Describe("Employee gets the answers of the appointment").Test(func() error {
+		var res DTO.AppointmentIntake
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(intakeURL).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&res).Error; err != nil {
+			return err
+		}
+		if !res.Complete || len(res.Responses) != 1 {
+			return fmt.Errorf("expected the intake complete, got %+v", res)
+		}
+		if r := res.Responses[0]; r.Status != "COMPLETED" || r.Version != 2 || r.Answers["allergies"] != "Latex" {
+			return fmt.Errorf("unexpected response %+v", r)
+		}
+		return nil
+	}())
+
+	tt.Describe("Client can not read the answers kept by the company").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(intakeURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Answers can not be read without a token").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(intakeURL).
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	b := &testModel.Appointment{}
+	tt.Describe("Client books without answering the intake form").Test(b.CreateAtRandomSlot(200, late.X_Auth_Token, cy, service, late, TimeZone))
+
+	var token string
+	tt.Describe("Client is emailed a link to the intake form").Test(func() error {
+		deadline := time.Now().Add(30 * time.Second)
+		for {
+			var err error
+			if token, err = late.GetIntakeTokenFromEmail(); err == nil {
+				return nil
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("intake form link not emailed after 30s: %w", err)
+			}
+			time.Sleep(time.Second)
+		}
+	}())
+	requestURL := "/intake/" + token
+
+	var request DTO.IntakeResponse
+	tt.Describe("Client opens the emailed link").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(requestURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).
+		ParseResponse(&request).Error)
+	tt.Describe("Link asks the current version of the form").Test(func() error {
+		if request.Status != "PENDING" || request.Form.Version != 2 || request.ClientID != late.Created.ID {
+			return fmt.Errorf("unexpected intake request %+v", request)
+		}
+		if request.AppointmentID == nil || *request.AppointmentID != b.Created.ID {
+			return fmt.Errorf("expected appointment %s, got %v", b.Created.ID, request.AppointmentID)
+		}
+		return nil
+	}())
+
+	tt.Describe("Required answers are checked through the link too").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(requestURL).
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.SubmitIntakeForm{Answers: map[string]any{"smoker": true}}).Error)
+
+	var answered DTO.IntakeResponse
+	tt.Describe("Client answers through the link").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(requestURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.SubmitIntakeForm{Answers: map[string]any{"allergies": "None", "smoker": true}}).
+		ParseResponse(&answered).Error)
+	tt.Describe("Response is completed").Test(func() error {
+		if answered.Status != "COMPLETED" || answered.CompletedAt == nil {
+			return fmt.Errorf("expected a completed response, got %+v", answered)
+		}
+		return nil
+	}())
+
+	tt.Describe("Link stops working once answered").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(requestURL).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Appointment answered through the link is complete").Test(func() error {
+		var res DTO.AppointmentIntake
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL("/appointment/"+b.Created.ID.String()+"/intake").
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&res).Error; err != nil {
+			return err
+		}
+		if !res.Complete {
+			return fmt.Errorf("expected the intake complete, got %+v", res)
+		}
+		return nil
+	}())
+
+	tt.Describe("Employee can not delete the intake form").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(formURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner deletes the intake form").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(formURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Deleted intake form is not found").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(formURL).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+}
@@ -0,0 +1,239 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+)
+
+func Test_Loyalty(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	owner := cy.Owner
+	employee := cy.Employees[1]
+	service := cy.Services[0]
+
+	other := &testModel.Company{}
+	tt.Describe("Other company creation").Test(other.Create(200))
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+	otherClient := &testModel.Client{}
+	tt.Describe("Other client creation").Test(otherClient.Set())
+
+	tt.Describe("Service price").Test(service.Update(200, map[string]any{"price": 10000}, owner.X_Auth_Token, nil))
+
+	programURL := "/company/" + companyID + "/loyalty_program"
+
+	tt.Describe("Company has no loyalty program yet").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(programURL).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	program := DTO.SaveLoyaltyProgram{
+		IsActive:         true,
+		LoyaltyEarning:   DTO.LoyaltyEarning{PointsPerAppointment: 10, PointsPerAmount: 1, AmountUnit: 1000},
+		PointValue:       10,
+		MinRedeemPoints:  50,
+		MaxRedeemPercent: 50,
+	}
+
+	tt.Describe("Employee can not save the loyalty program").Test(handler.NewHttpClient().
+		Method("PUT").
+		URL(programURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(program).Error)
+
+	tt.Describe("Owner of another company can not save the loyalty program").Test(handler.NewHttpClient().
+		Method("PUT").
+		URL(programURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(program).Error)
+
+	tt.Describe("Loyalty program can not be saved without a token").Test(handler.NewHttpClient().
+		Method("PUT").
+		URL(programURL).
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(program).Error)
+
+	worthless := program
+	worthless.PointValue = 0
+	tt.Describe("Points must be worth something").Test(handler.NewHttpClient().
+		Method("PUT").
+		URL(programURL).
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(worthless).Error)
+
+	tt.Describe("Owner saves the loyalty program").Test(handler.NewHttpClient().
+		Method("PUT").
+		URL(programURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(program).Error)
+
+	tt.Describe("Anyone gets the loyalty program").Test(func() error {
+		var saved DTO.LoyaltyProgram
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(programURL).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&saved).Error; err != nil {
+			return err
+		}
+		if !saved.IsActive || saved.PointsPerAppointment != 10 || saved.PointValue != 10 {
+			return fmt.Errorf("unexpected loyalty program %+v", saved)
+		}
+		return nil
+	}())
+
+	a := &testModel.Appointment{}
+	tt.Describe("Client books an appointment").Test(a.CreateAtRandomSlot(200, ct.X_Auth_Token, cy, service, ct, TimeZone))
+	tt.Describe("Owner marks the appointment as fulfilled").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL("/appointment/"+a.Created.ID.String()).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"is_fulfilled": true}).Error)
+
+	loyaltyURL := "/company/" + companyID + "/client/" + ct.Created.ID.String() + "/loyalty"
+	getBalance := func(token string) (*DTO.LoyaltyBalance, error) {
+		var balance DTO.LoyaltyBalance
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(loyaltyURL).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&balance).Error; err != nil {
+			return nil, err
+		}
+		return &balance, nil
+	}
+
+	tt.Describe("Client earns points on the fulfilled appointment").Test(func() error {
+		balance, err := getBalance(ct.X_Auth_Token)
+		if err != nil {
+			return err
+		}
+		// 10 for the appointment and 1 for every 10.00 of its 100.00
+		if balance.Balance != 20 || balance.Value != 200 || len(balance.Transactions) != 1 {
+			return fmt.Errorf("expected 20 points worth 200, got %+v", balance)
+		}
+		if e := balance.Transactions[0]; e.Type != "EARN" || e.AppointmentID == nil || *e.AppointmentID != a.Created.ID {
+			return fmt.Errorf("unexpected ledger entry %+v", e)
+		}
+		return nil
+	}())
+
+	tt.Describe("Employee reads the points of the client").Test(func() error {
+		_, err := getBalance(employee.X_Auth_Token)
+		return err
+	}())
+
+	tt.Describe("Other client can not read the points of the client").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(loyaltyURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, otherClient.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Points can not be read without a token").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(loyaltyURL).
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	grant := DTO.AdjustLoyaltyPoints{Points: 100, Description: "Compensation for the delay"}
+
+	tt.Describe("Employee can not adjust the points of the client").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(loyaltyURL+"/adjust").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(grant).Error)
+
+	tt.Describe("Client can not adjust its own points").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(loyaltyURL+"/adjust").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(grant).Error)
+
+	var adjusted DTO.LoyaltyTransaction
+	tt.Describe("Owner grants points to the client").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(loyaltyURL+"/adjust").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(grant).
+		ParseResponse(&adjusted).Error)
+	tt.Describe("Adjustment is recorded with who made it").Test(func() error {
+		if adjusted.Type != "ADJUSTMENT" || adjusted.BalanceAfter != 120 {
+			return fmt.Errorf("expected an adjustment to 120 points, got %+v", adjusted)
+		}
+		if adjusted.CreatedByID == nil || *adjusted.CreatedByID != owner.Created.ID {
+			return fmt.Errorf("expected owner %s as author, got %v", owner.Created.ID, adjusted.CreatedByID)
+		}
+		return nil
+	}())
+
+	tt.Describe("More points than the balance can not be removed").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(loyaltyURL+"/adjust").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.AdjustLoyaltyPoints{Points: -1000, Description: "Mistake"}).Error)
+
+	tt.Describe("Fewer points than the minimum can not be redeemed").Test((&testModel.Appointment{}).CreateAtRandomSlotWith(400, ct.X_Auth_Token, cy, service, ct, TimeZone, func(d *DTO.CreateAppointment) {
+		d.RedeemPoints = 10
+	}))
+
+	b := &testModel.Appointment{}
+	tt.Describe("Client redeems points when booking").Test(b.CreateAtRandomSlotWith(200, ct.X_Auth_Token, cy, service, ct, TimeZone, func(d *DTO.CreateAppointment) {
+		d.RedeemPoints = 100
+	}))
+	tt.Describe("Redeemed points are a discount on the price").Test(func() error {
+		if b.Created.LoyaltyDiscount != 1000 {
+			return fmt.Errorf("expected a loyalty discount of 1000, got %d", b.Created.LoyaltyDiscount)
+		}
+		balance, err := getBalance(ct.X_Auth_Token)
+		if err != nil {
+			return err
+		}
+		if balance.Balance != 20 || balance.Redeemed != 100 {
+			return fmt.Errorf("expected 20 points left after redeeming 100, got %+v", balance)
+		}
+		return nil
+	}())
+}
@@ -0,0 +1,126 @@
+package e2e_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	coreModel "mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/lib/payment"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Payment_Pix(t *testing.T) {
+	secret := "pix-e2e-secret"
+	t.Setenv("PIX_WEBHOOK_SECRET", secret)
+	t.Setenv("PIX_LOCATION_URL", "") // Static codes with the company key
+
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	owner := cy.Owner
+	employee := cy.Employees[1]
+	service := cy.Services[0]
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+	other := &testModel.Client{}
+	tt.Describe("Other client creation").Test(other.Set())
+
+	tt.Describe("Service asks for the full price when booking").Test(service.Update(200, map[string]any{
+		"price":           4990,
+		"prepayment_type": coreModel.PrepaymentFull,
+	}, owner.X_Auth_Token, nil))
+
+	pixPayment := func(a *DTO.CreateAppointment) {
+		a.Prepayment = &DTO.PaymentInput{Method: payment.PixMethod}
+	}
+
+	unconfigured := &testModel.Appointment{}
+	tt.Describe("Pix is refused while the company has no key").Test(unconfigured.CreateAtRandomSlotWith(400, ct.X_Auth_Token, cy, service, ct, TimeZone, pixPayment))
+
+	tt.Describe("Owner sets the Pix key of the company").Test(cy.Update(200, map[string]any{
+		"pix_key":  "pix@mynute.app",
+		"pix_city": "Sao Paulo",
+	}, owner.X_Auth_Token, nil))
+
+	a := &testModel.Appointment{}
+	tt.Describe("Booking paid with Pix").Test(a.CreateAtRandomSlotWith(200, ct.X_Auth_Token, cy, service, ct, TimeZone, pixPayment))
+
+	p := &testModel.Payment{Company: cy}
+	tt.Describe("Pix payment waits for the transfer").Test(func() error {
+		if a.Created.PaymentID == nil {
+			return fmt.Errorf("appointment %s has no payment", a.Created.ID)
+		}
+		if err := p.GetById(200, *a.Created.PaymentID, ct.X_Auth_Token, nil); err != nil {
+			return err
+		}
+		if p.Created.Status != string(coreModel.StatusPending) || p.Created.PaymentMethod != payment.PixMethod {
+			return fmt.Errorf("expected a pending Pix payment, got %s %s", p.Created.PaymentMethod, p.Created.Status)
+		}
+		if p.Created.Provider != payment.PixProviderName || p.Created.TransactionID == nil {
+			return fmt.Errorf("expected a transaction at provider %s, got %s", payment.PixProviderName, p.Created.Provider)
+		}
+		return nil
+	}())
+
+	charge, err := p.GetPix(200, ct.X_Auth_Token, nil)
+	tt.Describe("Client gets the Pix code").Test(err)
+	tt.Describe("Pix code charges the price of the service").Test(func() error {
+		if charge.PaymentID != p.Created.ID || charge.Amount != 4990 {
+			return fmt.Errorf("expected a charge of 4990 for payment %s, got %d for %s", p.Created.ID, charge.Amount, charge.PaymentID)
+		}
+		if !strings.HasPrefix(charge.Payload, "000201") {
+			return fmt.Errorf("expected a BR Code, got %q", charge.Payload)
+		}
+		if charge.QRCodeURL == "" {
+			return fmt.Errorf("expected the QR code URL")
+		}
+		return nil
+	}())
+
+	_, err = p.GetPix(200, employee.X_Auth_Token, nil)
+	tt.Describe("Employee gets the Pix code").Test(err)
+	_, err = p.GetPix(403, other.X_Auth_Token, nil)
+	tt.Describe("Other client can not get the Pix code").Test(err)
+	_, err = p.GetPix(401, "", nil)
+	tt.Describe("Pix code can not be read without a token").Test(err)
+
+	body, err := json.Marshal(map[string]any{
+		"pix": []map[string]any{{
+			"endToEndId": "E00000000202610191200abcdefghijk",
+			"txid":       *p.Created.TransactionID,
+			"valor":      "49.90",
+			"horario":    time.Now().UTC().Format(time.RFC3339),
+		}},
+	})
+	tt.Describe("Pix notification body").Test(err)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	tt.Describe("Unsigned Pix notification is rejected").Test(p.Notify(400, payment.PixProviderName, body, payment.PixSignatureHeader, ""))
+	tt.Describe("PSP confirms the Pix").Test(p.Notify(200, payment.PixProviderName, body, payment.PixSignatureHeader, signature))
+
+	tt.Describe("Pix payment is completed").Test(func() error {
+		if err := p.GetById(200, p.Created.ID, ct.X_Auth_Token, nil); err != nil {
+			return err
+		}
+		if p.Created.Status != string(coreModel.StatusCompleted) {
+			return fmt.Errorf("expected status %s after the notification, got %s", coreModel.StatusCompleted, p.Created.Status)
+		}
+		return nil
+	}())
+}
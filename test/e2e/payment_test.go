@@ -0,0 +1,177 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	coreModel "mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/core/src/lib/payment"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_Payment(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	owner := cy.Owner
+	employee := cy.Employees[1]
+	service := cy.Services[0]
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+	other := &testModel.Client{}
+	tt.Describe("Other client creation").Test(other.Set())
+
+	tt.Describe("Service asks for a deposit when booking").Test(service.Update(200, map[string]any{
+		"price":           10000,
+		"prepayment_type": coreModel.PrepaymentDeposit,
+		"deposit_amount":  2500,
+	}, owner.X_Auth_Token, nil))
+
+	card := func(token string) func(*DTO.CreateAppointment) {
+		return func(a *DTO.CreateAppointment) {
+			a.Prepayment = &DTO.PaymentInput{Method: "CREDIT_CARD", Token: token}
+		}
+	}
+
+	unpaid := &testModel.Appointment{}
+	tt.Describe("Booking without the prepayment is refused").Test(unpaid.CreateAtRandomSlot(402, ct.X_Auth_Token, cy, service, ct, TimeZone))
+
+	declined := &testModel.Appointment{}
+	tt.Describe("Booking with a declined card is refused").Test(declined.CreateAtRandomSlotWith(402, ct.X_Auth_Token, cy, service, ct, TimeZone, card(payment.FakeTokenDeclined)))
+
+	a := &testModel.Appointment{}
+	tt.Describe("Booking with the deposit paid").Test(a.CreateAtRandomSlotWith(200, ct.X_Auth_Token, cy, service, ct, TimeZone, card("tok_visa")))
+
+	p := &testModel.Payment{Company: cy}
+	tt.Describe("Appointment is linked to its payment").Test(func() error {
+		if a.Created.PaymentID == nil {
+			return fmt.Errorf("appointment %s has no payment", a.Created.ID)
+		}
+		return nil
+	}())
+	paymentID := *a.Created.PaymentID
+
+	tt.Describe("Client gets the payment of its booking").Test(p.GetById(200, paymentID, ct.X_Auth_Token, nil))
+	tt.Describe("Deposit was charged").Test(func() error {
+		if p.Created.Status != string(coreModel.StatusCompleted) {
+			return fmt.Errorf("expected status %s, got %s", coreModel.StatusCompleted, p.Created.Status)
+		}
+		if p.Created.Type != string(coreModel.PaymentTypeDeposit) || p.Created.Price != 2500 {
+			return fmt.Errorf("expected a deposit of 2500, got %s of %d", p.Created.Type, p.Created.Price)
+		}
+		if p.Created.ClientID != ct.Created.ID {
+			return fmt.Errorf("expected client %s, got %s", ct.Created.ID, p.Created.ClientID)
+		}
+		return nil
+	}())
+
+	tt.Describe("Employee gets the payment").Test((&testModel.Payment{Company: cy}).GetById(200, paymentID, employee.X_Auth_Token, nil))
+	tt.Describe("Other client can not get the payment").Test((&testModel.Payment{Company: cy}).GetById(403, paymentID, other.X_Auth_Token, nil))
+	tt.Describe("Payment can not be read without a token").Test((&testModel.Payment{Company: cy}).GetById(401, paymentID, "", nil))
+
+	tt.Describe("Client can not refund the payment").Test(p.Refund(403, 0, ct.X_Auth_Token, nil))
+	tt.Describe("Employee can not refund the payment").Test(p.Refund(403, 0, employee.X_Auth_Token, nil))
+	tt.Describe("Owner refunds part of the payment").Test(p.Refund(200, 1000, owner.X_Auth_Token, nil))
+	tt.Describe("Partly refunded payment stays completed").Test(func() error {
+		if err := p.WaitForRefund(1000, owner.X_Auth_Token); err != nil {
+			return err
+		}
+		if p.Created.Status != string(coreModel.StatusCompleted) || p.Created.RefundedAmount != 1000 {
+			return fmt.Errorf("expected status %s with 1000 refunded, got %s with %d", coreModel.StatusCompleted, p.Created.Status, p.Created.RefundedAmount)
+		}
+		return nil
+	}())
+	tt.Describe("Owner refunds the rest of the payment").Test(p.Refund(200, 0, owner.X_Auth_Token, nil))
+	tt.Describe("Fully refunded payment is refunded").Test(func() error {
+		if err := p.WaitForRefund(2500, owner.X_Auth_Token); err != nil {
+			return err
+		}
+		if p.Created.Status != string(coreModel.StatusRefunded) {
+			return fmt.Errorf("expected status %s, got %s", coreModel.StatusRefunded, p.Created.Status)
+		}
+		return nil
+	}())
+	tt.Describe("Refunded payment has nothing left to refund").Test(p.Refund(409, 0, owner.X_Auth_Token, nil))
+
+	pending := &testModel.Appointment{}
+	tt.Describe("Booking with a payment confirmed later").Test(pending.CreateAtRandomSlotWith(200, ct.X_Auth_Token, cy, service, ct, TimeZone, card(payment.FakeTokenPending)))
+	pp := &testModel.Payment{Company: cy}
+	tt.Describe("Pending payment is loaded").Test(func() error {
+		if pending.Created.PaymentID == nil {
+			return fmt.Errorf("appointment %s has no payment", pending.Created.ID)
+		}
+		if err := pp.GetById(200, *pending.Created.PaymentID, owner.X_Auth_Token, nil); err != nil {
+			return err
+		}
+		if pp.Created.Status != string(coreModel.StatusPending) || pp.Created.TransactionID == nil {
+			return fmt.Errorf("expected a pending payment with a transaction, got %s", pp.Created.Status)
+		}
+		return nil
+	}())
+
+	notification, err := json.Marshal(payment.Notification{
+		TransactionID: *pp.Created.TransactionID,
+		Status:        coreModel.StatusCompleted,
+		Amount:        pp.Created.Price,
+	})
+	tt.Describe("Notification body").Test(err)
+	fake := payment.NewFake(os.Getenv("PAYMENT_FAKE_SECRET"))
+
+	tt.Describe("Unsigned notification is rejected").Test(pp.Notify(400, payment.FakeProviderName, notification, payment.FakeSignatureHeader, ""))
+	tt.Describe("Notification with a wrong signature is rejected").Test(pp.Notify(400, payment.FakeProviderName, notification, payment.FakeSignatureHeader, fake.Sign([]byte("{}"))))
+	tt.Describe("Provider confirms the payment").Test(pp.Notify(200, payment.FakeProviderName, notification, payment.FakeSignatureHeader, fake.Sign(notification)))
+
+	tt.Describe("Confirmed payment is completed").Test(func() error {
+		if err := pp.GetById(200, pp.Created.ID, ct.X_Auth_Token, nil); err != nil {
+			return err
+		}
+		if pp.Created.Status != string(coreModel.StatusCompleted) {
+			return fmt.Errorf("expected status %s after the notification, got %s", coreModel.StatusCompleted, pp.Created.Status)
+		}
+		return nil
+	}())
+
+	intakeBody := DTO.CreateIntakeForm{
+		CompanyID:  cy.Created.ID,
+		Name:       "Consent form",
+		ServiceIDs: []uuid.UUID{service.Created.ID},
+		Fields: []DTO.IntakeField{
+			{Key: "consent", Label: "Do you agree with the terms?", Type: "LONG_TEXT", Required: true},
+		},
+	}
+	var form DTO.IntakeForm
+	tt.Describe("Service asks for an intake form too").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/intake_form").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, cy.Created.ID.String()).
+		Send(intakeBody).
+		ParseResponse(&form).Error)
+
+	token := "tok_" + uuid.NewString()
+	refused := &testModel.Appointment{}
+	tt.Describe("Booking refused by the intake form").Test(refused.CreateAtRandomSlotWith(400, ct.X_Auth_Token, cy, service, ct, TimeZone, func(d *DTO.CreateAppointment) {
+		card(token)(d)
+		d.Intake = []DTO.IntakeSubmission{{FormID: form.ID, Answers: map[string]any{}}}
+	}))
+	tt.Describe("Client is not charged for the refused booking").Test(func() error {
+		if charged := payment.FakeCharged(token); charged != 0 {
+			return fmt.Errorf("expected nothing charged on the card, got %d", charged)
+		}
+		return nil
+	}())
+}
@@ -0,0 +1,191 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+)
+
+func Test_PromoCode(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	owner := cy.Owner
+	employee := cy.Employees[1]
+	service := cy.Services[0]
+
+	other := &testModel.Company{}
+	tt.Describe("Other company creation").Test(other.Create(200))
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+
+	tt.Describe("Service price").Test(service.Update(200, map[string]any{"price": 10000}, owner.X_Auth_Token, nil))
+
+	body := DTO.CreatePromoCode{
+		CompanyID:        cy.Created.ID,
+		Code:             "welcome20",
+		Description:      "20% off",
+		DiscountType:     "PERCENTAGE",
+		DiscountValue:    20,
+		MaxUsesPerClient: 1,
+	}
+
+	tt.Describe("Employee can not create a promo code").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/promo_code").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	tt.Describe("Owner of another company can not create a promo code").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/promo_code").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	var created DTO.PromoCode
+	tt.Describe("Owner creates a promo code").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/promo_code").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).
+		ParseResponse(&created).Error)
+	tt.Describe("Code is stored in upper case").Test(func() error {
+		if created.Code != "WELCOME20" || !created.IsActive {
+			return fmt.Errorf("unexpected promo code %+v", created)
+		}
+		return nil
+	}())
+
+	tt.Describe("Same code can not be created twice").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/promo_code").
+		ExpectedStatus(409).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	promoURL := "/promo_code/" + created.ID.String()
+
+	tt.Describe("Employee gets the promo code").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(promoURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Client can not get the promo code").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(promoURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Promo code can not be read without a token").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(promoURL).
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Employee can not update the promo code").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(promoURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"description": "Changed"}).Error)
+
+	var updated DTO.PromoCode
+	tt.Describe("Owner updates the promo code").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(promoURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"description": "20% off the first booking"}).
+		ParseResponse(&updated).Error)
+	tt.Describe("Description is updated").Test(func() error {
+		if updated.Description != "20% off the first booking" {
+			return fmt.Errorf("expected the new description, got %q", updated.Description)
+		}
+		return nil
+	}())
+
+	withCode := func(a *DTO.CreateAppointment) { a.PromoCode = "Welcome20" }
+
+	a := &testModel.Appointment{}
+	tt.Describe("Booking with the promo code").Test(a.CreateAtRandomSlotWith(200, ct.X_Auth_Token, cy, service, ct, TimeZone, withCode))
+	tt.Describe("Booking is discounted").Test(func() error {
+		if a.Created.PromoCodeID == nil || *a.Created.PromoCodeID != created.ID {
+			return fmt.Errorf("expected promo code %s on the appointment, got %v", created.ID, a.Created.PromoCodeID)
+		}
+		if a.Created.Discount != 2000 {
+			return fmt.Errorf("expected a discount of 2000, got %d", a.Created.Discount)
+		}
+		return nil
+	}())
+
+	again := &testModel.Appointment{}
+	tt.Describe("Client can not use the promo code twice").Test(again.CreateAtRandomSlotWith(409, ct.X_Auth_Token, cy, service, ct, TimeZone, withCode))
+
+	tt.Describe("Listing the promo codes counts their uses").Test(func() error {
+		var list DTO.PromoCodeList
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL("/company/"+companyID+"/promo_codes").
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&list).Error; err != nil {
+			return err
+		}
+		if len(list.PromoCodes) != 1 || list.PromoCodes[0].Uses != 1 {
+			return fmt.Errorf("expected 1 promo code used once, got %+v", list.PromoCodes)
+		}
+		return nil
+	}())
+
+	tt.Describe("Employee can not delete the promo code").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(promoURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner deletes the promo code").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(promoURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Deleted promo code is not found").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(promoURL).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+}
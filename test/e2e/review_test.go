@@ -0,0 +1,238 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+	"time"
+)
+
+func Test_Review(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	owner := cy.Owner
+	employee := cy.Employees[1]
+	service := cy.Services[0]
+
+	other := &testModel.Company{}
+	tt.Describe("Other company creation").Test(other.Create(200))
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+
+	a := &testModel.Appointment{}
+	tt.Describe("Client books an appointment").Test(a.CreateAtRandomSlot(200, ct.X_Auth_Token, cy, service, ct, TimeZone))
+
+	tt.Describe("Owner marks the appointment as fulfilled").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL("/appointment/"+a.Created.ID.String()).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"is_fulfilled": true}).Error)
+
+	reviewsURL := "/company/" + companyID + "/reviews"
+
+	var invited DTO.Review
+	tt.Describe("Employee finds the client invited to review").Test(func() error {
+		var list DTO.ReviewList
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(reviewsURL+"?status=invited&service_id="+service.Created.ID.String()).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&list).Error; err != nil {
+			return err
+		}
+		if list.TotalCount != 1 || list.Reviews[0].AppointmentID != a.Created.ID {
+			return fmt.Errorf("expected the review of appointment %s, got %+v", a.Created.ID, list.Reviews)
+		}
+		invited = list.Reviews[0]
+		return nil
+	}())
+	reviewURL := "/review/" + invited.ID.String()
+
+	tt.Describe("Client can not list the reviews of the company").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(reviewsURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner of another company can not list the reviews").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(reviewsURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Reviews can not be listed without a token").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(reviewsURL).
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Employee gets the review").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(reviewURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Client can not get the review through the company").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(reviewURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Review not answered yet can not be moderated").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(reviewURL+"/moderate").
+		ExpectedStatus(409).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.ModerateReview{Status: "PUBLISHED"}).Error)
+
+	var token string
+	tt.Describe("Client is emailed a link to review the appointment").Test(func() error {
+		deadline := time.Now().Add(30 * time.Second)
+		for {
+			var err error
+			if token, err = ct.GetReviewTokenFromEmail(); err == nil {
+				return nil
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("review link not emailed after 30s: %w", err)
+			}
+			time.Sleep(time.Second)
+		}
+	}())
+	invitationURL := "/review_invitation/" + token
+
+	var invitation DTO.ReviewInvitation
+	tt.Describe("Client opens the emailed link").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(invitationURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).
+		ParseResponse(&invitation).Error)
+	tt.Describe("Link shows the appointment to review").Test(func() error {
+		if invitation.ID != invited.ID || invitation.Status != "INVITED" || invitation.ServiceName != service.Created.Name {
+			return fmt.Errorf("unexpected invitation %+v", invitation)
+		}
+		return nil
+	}())
+
+	tt.Describe("Rating out of range is rejected").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(invitationURL).
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.SubmitReview{Rating: 6}).Error)
+
+	serviceRating := 4
+	var answered DTO.ReviewInvitation
+	tt.Describe("Client reviews the appointment").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(invitationURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.SubmitReview{Rating: 5, ServiceRating: &serviceRating, Comment: " Great service "}).
+		ParseResponse(&answered).Error)
+	tt.Describe("Review waits for moderation").Test(func() error {
+		if answered.Status != "PENDING" {
+			return fmt.Errorf("expected a pending review, got %s", answered.Status)
+		}
+		return nil
+	}())
+
+	tt.Describe("Link stops working once answered").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(invitationURL).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.SubmitReview{Rating: 1}).Error)
+
+	tt.Describe("Employee can not moderate the review").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(reviewURL+"/moderate").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.ModerateReview{Status: "PUBLISHED"}).Error)
+
+	tt.Describe("Unknown moderation status is rejected").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(reviewURL+"/moderate").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.ModerateReview{Status: "HIDDEN"}).Error)
+
+	var published DTO.Review
+	tt.Describe("Owner publishes the review").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(reviewURL+"/moderate").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.ModerateReview{Status: "published"}).
+		ParseResponse(&published).Error)
+	tt.Describe("Published review keeps the answer and its moderator").Test(func() error {
+		if published.Status != "PUBLISHED" || published.Rating != 5 || published.Comment != "Great service" {
+			return fmt.Errorf("unexpected review %+v", published)
+		}
+		if published.ModeratedByID == nil || *published.ModeratedByID != owner.Created.ID {
+			return fmt.Errorf("expected owner %s as moderator, got %v", owner.Created.ID, published.ModeratedByID)
+		}
+		return nil
+	}())
+
+	tt.Describe("Service is rated by the published review").Test(func() error {
+		if err := service.GetById(200, owner.X_Auth_Token, &companyID); err != nil {
+			return err
+		}
+		if service.Created.Rating.Count != 1 || service.Created.Rating.Average != 4 {
+			return fmt.Errorf("expected 1 rating of 4, got %+v", service.Created.Rating)
+		}
+		return nil
+	}())
+
+	tt.Describe("Owner rejects the review").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(reviewURL+"/moderate").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.ModerateReview{Status: "REJECTED", ModerationNote: "Posted twice"}).Error)
+
+	tt.Describe("Rejected review no longer rates the service").Test(func() error {
+		if err := service.GetById(200, owner.X_Auth_Token, &companyID); err != nil {
+			return err
+		}
+		if service.Created.Rating.Count != 0 {
+			return fmt.Errorf("expected no rating, got %+v", service.Created.Rating)
+		}
+		return nil
+	}())
+}
@@ -0,0 +1,241 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_ScheduleTemplate(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	owner := cy.Owner
+	employee := cy.Employees[1]
+	branchID := cy.Branches[0].Created.ID
+
+	other := &testModel.Company{}
+	tt.Describe("Other company creation").Test(other.Create(200))
+
+	body := DTO.CreateScheduleTemplate{
+		CompanyID:   cy.Created.ID,
+		Name:        "Morning shift",
+		Description: "Monday and Tuesday, 09:00 to 13:00",
+		Ranges: []DTO.ScheduleTemplateRange{
+			{Weekday: 1, StartTime: "09:00", EndTime: "13:00"},
+			{Weekday: 2, StartTime: "09:00", EndTime: "13:00"},
+		},
+	}
+
+	tt.Describe("Employee can not create a schedule template").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/schedule_template").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	tt.Describe("Owner of another company can not create a schedule template").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/schedule_template").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	var created DTO.ScheduleTemplate
+	tt.Describe("Owner creates a schedule template").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/schedule_template").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).
+		ParseResponse(&created).Error)
+	templateURL := "/schedule_template/" + created.ID.String()
+
+	tt.Describe("Employee gets the schedule template").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(templateURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Schedule template can not be read without a token").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(templateURL).
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Employee lists the schedule templates").Test(func() error {
+		var list DTO.ScheduleTemplateList
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL("/company/"+companyID+"/schedule_templates").
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&list).Error; err != nil {
+			return err
+		}
+		if len(list.ScheduleTemplates) != 1 || len(list.ScheduleTemplates[0].Ranges) != 2 {
+			return fmt.Errorf("expected 1 template with 2 ranges, got %+v", list.ScheduleTemplates)
+		}
+		return nil
+	}())
+
+	tt.Describe("Employee can not update the schedule template").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(templateURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"name": "Early shift"}).Error)
+
+	var updated DTO.ScheduleTemplate
+	tt.Describe("Owner renames the schedule template").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(templateURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"name": "Early shift"}).
+		ParseResponse(&updated).Error)
+	tt.Describe("Name is updated and the ranges are kept").Test(func() error {
+		if updated.Name != "Early shift" || len(updated.Ranges) != 2 {
+			return fmt.Errorf("unexpected template %+v", updated)
+		}
+		return nil
+	}())
+
+	apply := DTO.ApplyScheduleTemplate{
+		BranchID:    branchID,
+		EmployeeIDs: []uuid.UUID{employee.Created.ID},
+		Replace:     true,
+		Preview:     true,
+	}
+
+	tt.Describe("Employee can not apply the schedule template").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(templateURL+"/apply").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(apply).Error)
+
+	tt.Describe("Applying to employees needs their branch").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(templateURL+"/apply").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.ApplyScheduleTemplate{EmployeeIDs: apply.EmployeeIDs}).Error)
+
+	var preview DTO.ScheduleDiff
+	tt.Describe("Owner previews the template on the employee").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(templateURL+"/apply").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(apply).
+		ParseResponse(&preview).Error)
+	tt.Describe("Preview is not applied").Test(func() error {
+		if preview.Applied {
+			return fmt.Errorf("expected the preview not to be applied")
+		}
+		if len(preview.Targets) != 1 || len(preview.Targets[0].Added) != 2 {
+			return fmt.Errorf("expected 2 ranges added to 1 employee, got %+v", preview.Targets)
+		}
+		return nil
+	}())
+
+	apply.Preview = false
+	var applied DTO.ScheduleDiff
+	tt.Describe("Owner applies the template to the employee").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(templateURL+"/apply").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(apply).
+		ParseResponse(&applied).Error)
+	tt.Describe("Template is applied").Test(func() error {
+		if !applied.Applied || len(applied.Targets) != 1 || applied.Targets[0].Error != "" {
+			return fmt.Errorf("expected the template applied, got %+v", applied)
+		}
+		return nil
+	}())
+
+	copyURL := "/employee/" + owner.Created.ID.String() + "/work_schedule/copy"
+	copyBody := DTO.CopyEmployeeWorkSchedule{FromEmployeeID: employee.Created.ID, BranchID: branchID, Replace: true, Preview: true}
+
+	tt.Describe("Employee can not copy a work schedule").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(copyURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(copyBody).Error)
+
+	tt.Describe("Work schedule can not be copied onto the same employee").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/employee/"+employee.Created.ID.String()+"/work_schedule/copy").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(copyBody).Error)
+
+	var copied DTO.ScheduleDiff
+	tt.Describe("Owner previews copying the schedule of the employee").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(copyURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(copyBody).
+		ParseResponse(&copied).Error)
+	tt.Describe("Copy adds the ranges of the template").Test(func() error {
+		if copied.Applied || len(copied.Targets) != 1 || len(copied.Targets[0].Added) != 2 {
+			return fmt.Errorf("expected 2 ranges added in preview, got %+v", copied)
+		}
+		return nil
+	}())
+
+	tt.Describe("Employee can not delete the schedule template").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(templateURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner deletes the schedule template").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(templateURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Deleted schedule template is not found").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(templateURL).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+}
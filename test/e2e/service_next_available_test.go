@@ -0,0 +1,100 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_Service_NextAvailable(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	service := cy.Services[0]
+	nextURL := "/service/" + service.Created.ID.String() + "/next-available"
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+
+	tt.Describe("Days out of range are rejected").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(nextURL+"?days=101").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Unknown time zone is rejected").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(nextURL+"?timezone=Mars/Olympus").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	var next DTO.NextAvailableSlot
+	tt.Describe("Next slot is public").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(nextURL+"?timezone="+TimeZone+"&client_id="+ct.Created.ID.String()).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).
+		ParseResponse(&next).Error)
+	tt.Describe("Next slot has the employees free for it").Test(func() error {
+		if next.ServiceID != service.Created.ID || next.StartTime == "" || next.TimeZone != TimeZone {
+			return fmt.Errorf("unexpected slot %+v", next)
+		}
+		if len(next.EmployeesID) == 0 || next.EmployeesID[0] != next.EmployeeID {
+			return fmt.Errorf("expected the picked employee first, got %s in %v", next.EmployeeID, next.EmployeesID)
+		}
+		return nil
+	}())
+
+	tt.Describe("Next slot with an employee filter").Test(func() error {
+		var filtered DTO.NextAvailableSlot
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(nextURL+"?timezone="+TimeZone+"&employee_id="+next.EmployeeID.String()).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&filtered).Error; err != nil {
+			return err
+		}
+		if len(filtered.EmployeesID) != 1 || filtered.EmployeeID != next.EmployeeID {
+			return fmt.Errorf("expected only employee %s, got %v", next.EmployeeID, filtered.EmployeesID)
+		}
+		return nil
+	}())
+
+	branch, employee := cy.BranchByID(next.BranchID.String()), cy.EmployeeByID(next.EmployeeID.String())
+	tt.Describe("Slot entities are loaded").Test(func() error {
+		if branch == nil || employee == nil {
+			return fmt.Errorf("branch %s or employee %s not loaded at company %s", next.BranchID, next.EmployeeID, companyID)
+		}
+		return nil
+	}())
+
+	a := &testModel.Appointment{}
+	tt.Describe("Client books the slot with any employee").Test(a.CreateWith(200, ct.X_Auth_Token, nil, &next.StartTime, TimeZone, branch, employee, service, cy, ct, func(d *DTO.CreateAppointment) {
+		d.EmployeeID = uuid.Nil
+		d.AnyEmployee = true
+	}))
+	tt.Describe("An employee free for the slot is assigned").Test(func() error {
+		if !slices.Contains(next.EmployeesID, a.Created.EmployeeID) {
+			return fmt.Errorf("expected one of %v, got %s", next.EmployeesID, a.Created.EmployeeID)
+		}
+		return nil
+	}())
+}
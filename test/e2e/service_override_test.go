@@ -0,0 +1,131 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+)
+
+func Test_Service_Override(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	owner := cy.Owner
+	employee := cy.Employees[1]
+	service := cy.Services[0]
+	branch := cy.Branches[0]
+	serviceURL := "/service/" + service.Created.ID.String()
+
+	other := &testModel.Company{}
+	tt.Describe("Other company creation").Test(other.Create(200))
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+
+	price := int64(18000)
+	body := DTO.CreateServiceOverride{BranchID: &branch.Created.ID, Price: &price}
+
+	tt.Describe("Employee can not override the service").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(serviceURL+"/override").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	tt.Describe("Owner of another company can not override the service").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(serviceURL+"/override").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	tt.Describe("Service can not be overridden without a token").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(serviceURL+"/override").
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	var created DTO.ServiceOverride
+	tt.Describe("Owner overrides the price at the branch").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(serviceURL+"/override").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).
+		ParseResponse(&created).Error)
+
+	var list DTO.ServiceOverrideList
+	tt.Describe("Employee lists the overrides").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(serviceURL+"/overrides").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).
+		ParseResponse(&list).Error)
+	tt.Describe("Override is listed").Test(func() error {
+		if len(list.Overrides) != 1 || list.Overrides[0].ID != created.ID {
+			return fmt.Errorf("expected override %s, got %+v", created.ID, list.Overrides)
+		}
+		if o := list.Overrides[0]; o.Price == nil || *o.Price != price || o.BranchID == nil || *o.BranchID != branch.Created.ID {
+			return fmt.Errorf("unexpected override %+v", o)
+		}
+		return nil
+	}())
+
+	tt.Describe("Client can not list the overrides").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(serviceURL+"/overrides").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	a := &testModel.Appointment{}
+	tt.Describe("Booking at the branch").Test(a.CreateAtRandomSlot(200, ct.X_Auth_Token, cy, service, ct, TimeZone))
+	tt.Describe("Booking is charged the branch price").Test(func() error {
+		if a.Created.Price != price {
+			return fmt.Errorf("expected price %d, got %d", price, a.Created.Price)
+		}
+		return nil
+	}())
+
+	overrideURL := serviceURL + "/override/" + created.ID.String()
+	tt.Describe("Employee can not delete the override").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(overrideURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner deletes the override").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(overrideURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Deleted override is not found").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(overrideURL).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+}
@@ -0,0 +1,292 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+)
+
+func Test_ServicePackage(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	companyID := cy.Created.ID.String()
+	owner := cy.Owner
+	employee := cy.Employees[1]
+	service := cy.Services[0]
+
+	other := &testModel.Company{}
+	tt.Describe("Other company creation").Test(other.Create(200))
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+	otherClient := &testModel.Client{}
+	tt.Describe("Other client creation").Test(otherClient.Set())
+
+	body := DTO.CreateServicePackage{
+		CompanyID:    cy.Created.ID,
+		Name:         "2 sessions",
+		Description:  "Two sessions of any service",
+		Type:         "PACKAGE",
+		Price:        18000,
+		Currency:     "BRL",
+		Credits:      2,
+		ValidityDays: 180,
+	}
+
+	tt.Describe("Employee can not create a package").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/package").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	tt.Describe("Owner of another company can not create a package").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/package").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	var created DTO.ServicePackage
+	tt.Describe("Owner creates a package").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/package").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).
+		ParseResponse(&created).Error)
+	packageURL := "/package/" + created.ID.String()
+
+	tt.Describe("Employee gets the package").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(packageURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Package can not be read without a token").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(packageURL).
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Employee lists the packages").Test(func() error {
+		var list DTO.ServicePackageList
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL("/company/"+companyID+"/packages").
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&list).Error; err != nil {
+			return err
+		}
+		if len(list.Packages) != 1 || list.Packages[0].ID != created.ID {
+			return fmt.Errorf("expected package %s, got %+v", created.ID, list.Packages)
+		}
+		return nil
+	}())
+
+	tt.Describe("Employee can not update the package").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(packageURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"price": 15000}).Error)
+
+	var updated DTO.ServicePackage
+	tt.Describe("Owner updates the package").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(packageURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"price": 15000}).
+		ParseResponse(&updated).Error)
+	tt.Describe("Price is updated").Test(func() error {
+		if updated.Price != 15000 {
+			return fmt.Errorf("expected price 15000, got %d", updated.Price)
+		}
+		return nil
+	}())
+
+	sale := DTO.SellServicePackage{ClientID: ct.Created.ID, PaymentMethod: "CASH"}
+
+	tt.Describe("Client can not sell a package").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(packageURL+"/sell").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, ct.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(sale).Error)
+
+	tt.Describe("Owner of another company can not sell the package").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(packageURL+"/sell").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(sale).Error)
+
+	tt.Describe("Employee can not sell the package").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(packageURL+"/sell").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(sale).Error)
+
+	tt.Describe("Package can not be sold without a payment method").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(packageURL+"/sell").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.SellServicePackage{ClientID: ct.Created.ID}).Error)
+
+	var sold DTO.ClientPackage
+	tt.Describe("Owner sells the package to the client").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(packageURL+"/sell").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(sale).
+		ParseResponse(&sold).Error)
+	tt.Describe("Client holds the credits").Test(func() error {
+		if sold.ClientID != ct.Created.ID || sold.Remaining != 2 || sold.Price != 15000 || sold.PaymentID == nil {
+			return fmt.Errorf("unexpected client package %+v", sold)
+		}
+		return nil
+	}())
+
+	var paid DTO.Payment
+	tt.Describe("Sale payment is recorded").Test(handler.NewHttpClient().
+		Method("GET").
+		URL("/payment/"+sold.PaymentID.String()).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).
+		ParseResponse(&paid).Error)
+	tt.Describe("Sale payment is completed offline").Test(func() error {
+		if paid.Price != 15000 || paid.Status != "COMPLETED" || paid.PaymentMethod != "CASH" || paid.Provider != "offline" || paid.ClientID != ct.Created.ID {
+			return fmt.Errorf("unexpected sale payment %+v", paid)
+		}
+		return nil
+	}())
+
+	creditsURL := "/company/" + companyID + "/client/" + ct.Created.ID.String() + "/credits"
+	balance := func(token string, want int64) error {
+		var b DTO.ClientCreditBalance
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(creditsURL).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&b).Error; err != nil {
+			return err
+		}
+		if b.Credits != want || b.Unlimited {
+			return fmt.Errorf("expected %d credits, got %d (unlimited %t)", want, b.Credits, b.Unlimited)
+		}
+		return nil
+	}
+
+	tt.Describe("Client reads its credits").Test(balance(ct.X_Auth_Token, 2))
+	tt.Describe("Employee reads the credits of the client").Test(balance(employee.X_Auth_Token, 2))
+	tt.Describe("Other client can not read the credits").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(creditsURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, otherClient.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	useCredit := func(a *DTO.CreateAppointment) { a.UseCredit = true }
+
+	a := &testModel.Appointment{}
+	tt.Describe("Booking paid with a credit").Test(a.CreateAtRandomSlotWith(200, ct.X_Auth_Token, cy, service, ct, TimeZone, useCredit))
+	tt.Describe("Appointment is paid by the package").Test(func() error {
+		if a.Created.ClientPackageID == nil || *a.Created.ClientPackageID != sold.ID {
+			return fmt.Errorf("expected client package %s on the appointment, got %v", sold.ID, a.Created.ClientPackageID)
+		}
+		return nil
+	}())
+	tt.Describe("Credit is used").Test(balance(ct.X_Auth_Token, 1))
+
+	noCredit := &testModel.Appointment{}
+	tt.Describe("Client without credits can not book with one").Test(noCredit.CreateAtRandomSlotWith(402, otherClient.X_Auth_Token, cy, service, otherClient, TimeZone, useCredit))
+
+	tt.Describe("Owner stops selling the package").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(packageURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"is_active": false}).Error)
+
+	tt.Describe("Inactive package can not be sold").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(packageURL+"/sell").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(DTO.SellServicePackage{ClientID: otherClient.Created.ID}).Error)
+
+	unsold := body
+	unsold.Name = "Unsold"
+	var draft DTO.ServicePackage
+	tt.Describe("Owner creates a package never sold").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/package").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(unsold).
+		ParseResponse(&draft).Error)
+	draftURL := "/package/" + draft.ID.String()
+
+	tt.Describe("Employee can not delete the package").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(draftURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner deletes the package").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(draftURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Deleted package is not found").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(draftURL).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+}
@@ -0,0 +1,125 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_Slot_Rules(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	owner := cy.Owner
+	service := cy.Services[0]
+	services := []DTO.ServiceBase{{ID: service.Created.ID}}
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+
+	loc, err := time.LoadLocation(TimeZone)
+	tt.Describe("Time zone").Test(err)
+	now := time.Now().In(loc)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 3)
+	at := func(clock string) string {
+		start, _ := time.ParseInLocation(time.DateTime, day.Format(time.DateOnly)+" "+clock+":00", loc)
+		return start.Format(time.RFC3339)
+	}
+
+	// The employee only works at the branch from 09:10 to 12:10, so starts aligned
+	// to midnight and to the start of the shift differ
+	branch := &testModel.Branch{Company: cy}
+	tt.Describe("Branch creation").Test(branch.Create(200, owner.X_Auth_Token, nil))
+	tt.Describe("Branch offers the service").Test(branch.AddService(200, service, owner.X_Auth_Token, nil))
+	tt.Describe("Branch opens from 08:00 to 20:00").Test(branch.CreateWorkSchedule(200, DTO.CreateBranchWorkSchedule{
+		WorkRanges: []DTO.CreateBranchWorkRange{{
+			BranchID:                branch.Created.ID,
+			Weekday:                 uint8(day.Weekday()),
+			StartTime:               "08:00",
+			EndTime:                 "20:00",
+			TimeZone:                TimeZone,
+			BranchWorkRangeServices: DTO.BranchWorkRangeServices{Services: services},
+		}},
+	}, owner.X_Auth_Token, nil))
+	tt.Describe("Employee creation").Test(cy.GenerateEmployees(1))
+	employee := cy.Employees[len(cy.Employees)-1]
+	tt.Describe("Employee offers the service").Test(employee.AddService(200, service, &owner.X_Auth_Token, nil))
+	tt.Describe("Employee joins the branch").Test(employee.AddBranch(200, branch, &owner.X_Auth_Token, nil))
+	tt.Describe("Employee works from 09:10 to 12:10").Test(employee.CreateWorkSchedule(200, DTO.CreateEmployeeWorkSchedule{
+		WorkRanges: []DTO.CreateEmployeeWorkRange{{
+			EmployeeID:                employee.Created.ID,
+			BranchID:                  branch.Created.ID,
+			Weekday:                   uint8(day.Weekday()),
+			StartTime:                 "09:10",
+			EndTime:                   "12:10",
+			TimeZone:                  TimeZone,
+			EmployeeWorkRangeServices: DTO.EmployeeWorkRangeServices{Services: services},
+		}},
+	}, nil, nil))
+
+	// expectSlots checks the 60 minutes starts the service lists at the branch on the day
+	expectSlots := func(want ...string) error {
+		var availability DTO.ServiceAvailability
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL(fmt.Sprintf("/service/%s/availability?date_forward_start=0&date_forward_end=7&timezone=%s", service.Created.ID, TimeZone)).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, cy.Created.ID.String()).
+			Send(nil).
+			ParseResponse(&availability).Error; err != nil {
+			return err
+		}
+		var got []string
+		for _, d := range availability.AvailableDates {
+			if d.BranchID != branch.Created.ID || d.Date != day.Format(time.DateOnly) {
+				continue
+			}
+			for _, slot := range d.AvailableTimes {
+				got = append(got, slot.Time)
+			}
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			return fmt.Errorf("expected starts %v on %s, got %v", want, day.Format(time.DateOnly), got)
+		}
+		return nil
+	}
+	book := func(status int, clock string) error {
+		start := at(clock)
+		return (&testModel.Appointment{}).Create(status, ct.X_Auth_Token, nil, &start, TimeZone, branch, employee, service, cy, ct)
+	}
+
+	tt.Describe("Service starts every 30 minutes").Test(service.Update(200, map[string]any{"duration": 60, "slot_interval": 30}, owner.X_Auth_Token, nil))
+	tt.Describe("Starts are aligned to midnight").Test(expectSlots("09:30", "10:00", "10:30", "11:00"))
+	tt.Describe("Booking at the start of the shift is rejected").Test(book(400, "09:10"))
+	tt.Describe("Booking between two starts is rejected").Test(book(400, "09:45"))
+
+	tt.Describe("Service aligns starts to the shift").Test(service.Update(200, map[string]any{"slot_alignment": "SHIFT_START"}, owner.X_Auth_Token, nil))
+	tt.Describe("Starts are aligned to the start of the shift").Test(expectSlots("09:10", "09:40", "10:10", "10:40", "11:10"))
+	tt.Describe("Booking at a start aligned to midnight is rejected").Test(book(400, "09:30"))
+	a := &testModel.Appointment{}
+	start := at("10:10")
+	tt.Describe("Booking at a start of the shift is accepted").Test(a.Create(200, ct.X_Auth_Token, nil, &start, TimeZone, branch, employee, service, cy, ct))
+	tt.Describe("Rescheduling to a misaligned start is rejected").Test(a.Reschedule(400, at("10:25"), ct.X_Auth_Token, nil))
+	tt.Describe("Rescheduling to the start of the shift is accepted").Test(a.Reschedule(200, at("09:10"), ct.X_Auth_Token, nil))
+
+	// The appointment now takes 09:10 to 10:10
+	tt.Describe("Service optimizes gaps").Test(service.Update(200, map[string]any{"optimize_gaps": true}, owner.X_Auth_Token, nil))
+	tt.Describe("Only starts next to the appointment or the end of the shift are listed").Test(expectSlots("10:10", "11:10"))
+	tt.Describe("Booking a start leaving gaps on both sides is rejected").Test(book(400, "10:40"))
+	tt.Describe("Rescheduling to a start leaving gaps on both sides is rejected").Test(a.Reschedule(400, at("10:40"), ct.X_Auth_Token, nil))
+	tt.Describe("Rescheduling to the end of the shift is accepted").Test(a.Reschedule(200, at("11:10"), ct.X_Auth_Token, nil))
+	tt.Describe("Booking next to the appointment is accepted").Test(book(200, "10:10"))
+}
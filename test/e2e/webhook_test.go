@@ -0,0 +1,242 @@
+package e2e_test
+
+import (
+	"fmt"
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	coreModel "mynute-go/core/src/config/db/model"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	"mynute-go/test/src/model"
+	"testing"
+)
+
+func Test_Webhook(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	company := &model.Company{}
+	tt.Describe("Company setup").Test(company.CreateCompanyRandomly(2, 1, 1))
+	companyID := company.Created.ID.String()
+	owner := company.Owner
+	employee := company.Employees[1]
+
+	other := &model.Company{}
+	tt.Describe("Other company creation").Test(other.Create(200))
+
+	body := DTO.CreateWebhook{
+		CompanyID:   company.Created.ID,
+		URL:         "https://example.com/hooks/mynute",
+		Description: "Front desk CRM",
+		Events:      []string{coreModel.WebhookEventAppointmentCreated, coreModel.WebhookEventClientCreated},
+	}
+
+	tt.Describe("Employee can not create a webhook").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/webhook").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	tt.Describe("Owner of another company can not create a webhook").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/webhook").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).Error)
+
+	tt.Describe("Owner of another company can not create a webhook for the company through its own").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/webhook").
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, other.Owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, other.Created.ID.String()).
+		Send(body).Error)
+
+	for _, url := range []string{
+		"http://example.com/hooks/mynute",
+		"https://localhost/hooks/mynute",
+		"https://127.0.0.1/hooks/mynute",
+		"https://10.0.0.5/hooks/mynute",
+		"https://169.254.169.254/latest/meta-data",
+	} {
+		invalid := body
+		invalid.URL = url
+		tt.Describe("Webhook to " + url + " is rejected").Test(handler.NewHttpClient().
+			Method("POST").
+			URL("/webhook").
+			ExpectedStatus(400).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(invalid).Error)
+	}
+
+	var created DTO.WebhookWithSecret
+	tt.Describe("Owner creates a webhook").Test(handler.NewHttpClient().
+		Method("POST").
+		URL("/webhook").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(body).
+		ParseResponse(&created).Error)
+
+	tt.Describe("Created webhook returns its secret").Test(func() error {
+		if created.Secret == "" {
+			return fmt.Errorf("expected the secret on creation")
+		}
+		if created.URL != body.URL || !created.IsActive || created.CompanyID != company.Created.ID {
+			return fmt.Errorf("unexpected webhook %+v", created.Webhook)
+		}
+		return nil
+	}())
+	webhookURL := "/webhook/" + created.ID.String()
+
+	tt.Describe("Getting the webhook does not return its secret").Test(func() error {
+		http := handler.NewHttpClient().
+			Method("GET").
+			URL(webhookURL).
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil)
+		if http.Error != nil {
+			return http.Error
+		}
+		if _, ok := http.ResBody["secret"]; ok {
+			return fmt.Errorf("secret returned by GET %s", webhookURL)
+		}
+		return nil
+	}())
+
+	tt.Describe("Listing the webhooks does not return their secret").Test(func() error {
+		var list struct {
+			Webhooks []map[string]any `json:"webhooks"`
+		}
+		if err := handler.NewHttpClient().
+			Method("GET").
+			URL("/company/"+companyID+"/webhooks").
+			ExpectedStatus(200).
+			Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+			Header(namespace.HeadersKey.Company, companyID).
+			Send(nil).
+			ParseResponse(&list).Error; err != nil {
+			return err
+		}
+		if len(list.Webhooks) != 1 {
+			return fmt.Errorf("expected 1 webhook, got %d", len(list.Webhooks))
+		}
+		if _, ok := list.Webhooks[0]["secret"]; ok {
+			return fmt.Errorf("secret returned by the webhook list")
+		}
+		return nil
+	}())
+
+	tt.Describe("Employee can not list the webhooks").Test(handler.NewHttpClient().
+		Method("GET").
+		URL("/company/"+companyID+"/webhooks").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Webhook can not be read without a token").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(webhookURL).
+		ExpectedStatus(401).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Updating the URL to a private address is rejected").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(webhookURL).
+		ExpectedStatus(400).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"url": "https://192.168.0.10/hooks"}).Error)
+
+	var updated DTO.Webhook
+	tt.Describe("Owner deactivates the webhook").Test(handler.NewHttpClient().
+		Method("PATCH").
+		URL(webhookURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(map[string]any{"is_active": false}).
+		ParseResponse(&updated).Error)
+	tt.Describe("Webhook is inactive").Test(func() error {
+		if updated.IsActive {
+			return fmt.Errorf("expected the webhook to be inactive")
+		}
+		return nil
+	}())
+
+	tt.Describe("Employee can not rotate the secret").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(webhookURL+"/secret").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	var rotated DTO.WebhookWithSecret
+	tt.Describe("Owner rotates the secret").Test(handler.NewHttpClient().
+		Method("POST").
+		URL(webhookURL+"/secret").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).
+		ParseResponse(&rotated).Error)
+	tt.Describe("Rotation returns a new secret").Test(func() error {
+		if rotated.Secret == "" || rotated.Secret == created.Secret {
+			return fmt.Errorf("expected a new secret, got %q", rotated.Secret)
+		}
+		return nil
+	}())
+
+	var deliveries DTO.WebhookDeliveryList
+	tt.Describe("Owner lists the deliveries").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(webhookURL+"/deliveries").
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).
+		ParseResponse(&deliveries).Error)
+
+	tt.Describe("Employee can not list the deliveries").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(webhookURL+"/deliveries").
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Employee can not delete the webhook").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(webhookURL).
+		ExpectedStatus(403).
+		Header(namespace.HeadersKey.Auth, employee.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Owner deletes the webhook").Test(handler.NewHttpClient().
+		Method("DELETE").
+		URL(webhookURL).
+		ExpectedStatus(200).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+
+	tt.Describe("Deleted webhook is not found").Test(handler.NewHttpClient().
+		Method("GET").
+		URL(webhookURL).
+		ExpectedStatus(404).
+		Header(namespace.HeadersKey.Auth, owner.X_Auth_Token).
+		Header(namespace.HeadersKey.Company, companyID).
+		Send(nil).Error)
+}
@@ -0,0 +1,69 @@
+package e2e_test
+
+import (
+	"mynute-go/core"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/test/src/handler"
+	testModel "mynute-go/test/src/model"
+	"testing"
+	"time"
+)
+
+func Test_WorkRange_Overnight(t *testing.T) {
+	server := core.NewServer().Run("parallel")
+	defer server.Shutdown()
+	tt := handler.NewTestErrorHandler(t)
+
+	TimeZone := "America/Sao_Paulo"
+
+	cy := &testModel.Company{}
+	tt.Describe("Company Random Setup").Test(cy.CreateCompanyRandomly(2, 1, 1))
+	owner := cy.Owner
+	employee := cy.Employees[1]
+	service := cy.Services[0]
+	services := []DTO.ServiceBase{{ID: service.Created.ID}}
+
+	ct := &testModel.Client{}
+	tt.Describe("Client creation").Test(ct.Set())
+
+	night := &testModel.Branch{Company: cy}
+	tt.Describe("Night branch creation").Test(night.Create(200, owner.X_Auth_Token, nil))
+	tt.Describe("Night branch offers the service").Test(night.AddService(200, service, owner.X_Auth_Token, nil))
+	tt.Describe("Night branch opens on Sunday from 20:00 to 04:00").Test(night.CreateWorkSchedule(200, DTO.CreateBranchWorkSchedule{
+		WorkRanges: []DTO.CreateBranchWorkRange{{
+			BranchID:                night.Created.ID,
+			Weekday:                 uint8(time.Sunday),
+			StartTime:               "20:00",
+			EndTime:                 "04:00",
+			TimeZone:                TimeZone,
+			BranchWorkRangeServices: DTO.BranchWorkRangeServices{Services: services},
+		}},
+	}, owner.X_Auth_Token, nil))
+
+	tt.Describe("Employee joins the night branch").Test(employee.AddBranch(200, night, &owner.X_Auth_Token, nil))
+	tt.Describe("Employee works on Sunday from 20:00 to 04:00").Test(employee.CreateWorkSchedule(200, DTO.CreateEmployeeWorkSchedule{
+		WorkRanges: []DTO.CreateEmployeeWorkRange{{
+			EmployeeID:                employee.Created.ID,
+			BranchID:                  night.Created.ID,
+			Weekday:                   uint8(time.Sunday),
+			StartTime:                 "20:00",
+			EndTime:                   "04:00",
+			TimeZone:                  TimeZone,
+			EmployeeWorkRangeServices: DTO.EmployeeWorkRangeServices{Services: services},
+		}},
+	}, nil, nil))
+
+	loc, err := time.LoadLocation(TimeZone)
+	tt.Describe("Time zone").Test(err)
+	monday := time.Now().In(loc).AddDate(0, 0, 2)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, 1)
+	}
+	at := func(hour int) *string {
+		start := time.Date(monday.Year(), monday.Month(), monday.Day(), hour, 0, 0, 0, loc).Format(time.RFC3339)
+		return &start
+	}
+
+	tt.Describe("Booking at 02:00 on Monday falls in the Sunday night").Test((&testModel.Appointment{}).Create(200, ct.X_Auth_Token, nil, at(2), TimeZone, night, employee, service, cy, ct))
+	tt.Describe("Booking running past 04:00 is rejected").Test((&testModel.Appointment{}).Create(400, ct.X_Auth_Token, nil, at(4), TimeZone, night, employee, service, cy, ct))
+}
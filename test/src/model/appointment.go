@@ -177,6 +177,12 @@ func (a *Appointment) RescheduleRandomly(s int, x_auth_token string, x_company_i
 }
 
 func (a *Appointment) Create(status int, x_auth_token string, x_company_id *string, startTime *string, tz string, b *Branch, e *Employee, s *Service, cy *Company, ct *Client) error {
+	return a.CreateWith(status, x_auth_token, x_company_id, startTime, tz, b, e, s, cy, ct, nil)
+}
+
+// CreateWith works like Create and lets set fill the rest of the booking (prepayment,
+// promo code, dependent...) before it is sent.
+func (a *Appointment) CreateWith(status int, x_auth_token string, x_company_id *string, startTime *string, tz string, b *Branch, e *Employee, s *Service, cy *Company, ct *Client, set func(*DTO.CreateAppointment)) error {
 	companyIDStr := cy.Created.ID.String()
 	cID, err := Get_x_company_id(x_company_id, &companyIDStr)
 	if err != nil {
@@ -201,6 +207,9 @@ func (a *Appointment) Create(status int, x_auth_token string, x_company_id *stri
 		StartTime:  *startTime,
 		TimeZone:   tz,
 	}
+	if set != nil {
+		set(&A)
+	}
 	debug.Output("test_e2e_CreateAppointment_Payload", A)
 	if err := http.Send(A).Error; err != nil {
 		return fmt.Errorf("failed to create appointment: %w", err)
@@ -233,6 +242,76 @@ func (a *Appointment) Create(status int, x_auth_token string, x_company_id *stri
 	return nil
 }
 
+// CreateAtRandomSlot books the service for the client at a random slot of its availability,
+// trying other slots when the one picked is taken meanwhile.
+func (a *Appointment) CreateAtRandomSlot(status int, x_auth_token string, cy *Company, s *Service, ct *Client, tz string) error {
+	return a.CreateAtRandomSlotWith(status, x_auth_token, cy, s, ct, tz, nil)
+}
+
+// CreateAtRandomSlotWith works like CreateAtRandomSlot and lets set fill the rest of the booking.
+func (a *Appointment) CreateAtRandomSlotWith(status int, x_auth_token string, cy *Company, s *Service, ct *Client, tz string, set func(*DTO.CreateAppointment)) error {
+	clientID := ct.Created.ID.String()
+	var lastErr error
+	for range 10 {
+		slot, err := s.FindValidRandomAppointmentSlot(tz, &clientID)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		branch, employee := cy.BranchByID(slot.BranchID), cy.EmployeeByID(slot.EmployeeID)
+		if branch == nil || employee == nil {
+			lastErr = fmt.Errorf("slot at branch %s with employee %s not loaded at company %s", slot.BranchID, slot.EmployeeID, cy.Created.ID)
+			continue
+		}
+		if lastErr = a.CreateWith(status, x_auth_token, nil, &slot.StartTimeRFC3339, slot.TimeZone, branch, employee, s, cy, ct, set); lastErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to book service %s at a random slot: %w", s.Created.ID, lastErr)
+}
+
+// FindRescheduleSlot finds another start time available for the appointment with its
+// employee at its branch.
+func (a *Appointment) FindRescheduleSlot(tz string) (string, error) {
+	clientID := a.Created.ClientID.String()
+	for range 20 {
+		slot, err := a.Service.FindValidRandomAppointmentSlot(tz, &clientID)
+		if err != nil {
+			continue
+		}
+		if slot.EmployeeID != a.Created.EmployeeID.String() || slot.BranchID != a.Created.BranchID.String() {
+			continue
+		}
+		start, err := time.Parse(time.RFC3339, slot.StartTimeRFC3339)
+		if err != nil {
+			return "", err
+		}
+		if !start.Equal(a.Created.StartTime) {
+			return slot.StartTimeRFC3339, nil
+		}
+	}
+	return "", fmt.Errorf("no other slot found for appointment %s", a.Created.ID)
+}
+
+// Reschedule moves the appointment to the start time and reloads it.
+func (a *Appointment) Reschedule(s int, startTime string, x_auth_token string, x_company_id *string) error {
+	companyIDStr := a.Created.CompanyID.String()
+	cID, err := Get_x_company_id(x_company_id, &companyIDStr)
+	if err != nil {
+		return err
+	}
+	if err := handler.NewHttpClient().
+		Method("PATCH").
+		URL("/appointment/"+a.Created.ID.String()).
+		ExpectedStatus(s).
+		Header(namespace.HeadersKey.Auth, x_auth_token).
+		Header(namespace.HeadersKey.Company, cID).
+		Send(map[string]any{"start_time": startTime}).Error; err != nil {
+		return fmt.Errorf("failed to reschedule appointment: %w", err)
+	}
+	return a.GetById(200, x_auth_token, x_company_id)
+}
+
 func (a *Appointment) GetById(s int, x_auth_token string, x_company_id *string) error {
 	companyIDStr := a.Created.CompanyID.String()
 	cID, err := Get_x_company_id(x_company_id, &companyIDStr)
@@ -252,6 +331,27 @@ func (a *Appointment) GetById(s int, x_auth_token string, x_company_id *string)
 	return nil
 }
 
+// GetHistory loads the page of changes of the appointment, only those of field when not empty.
+func (a *Appointment) GetHistory(s int, field string, x_auth_token string, x_company_id *string) (*DTO.AppointmentHistoryPage, error) {
+	companyIDStr := a.Created.CompanyID.String()
+	cID, err := Get_x_company_id(x_company_id, &companyIDStr)
+	if err != nil {
+		return nil, err
+	}
+	var history DTO.AppointmentHistoryPage
+	if err := handler.NewHttpClient().
+		Method("GET").
+		URL(fmt.Sprintf("/appointment/%s/history?field=%s", a.Created.ID.String(), field)).
+		ExpectedStatus(s).
+		Header(namespace.HeadersKey.Auth, x_auth_token).
+		Header(namespace.HeadersKey.Company, cID).
+		Send(nil).
+		ParseResponse(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to get history of appointment %s: %w", a.Created.ID.String(), err)
+	}
+	return &history, nil
+}
+
 func (a *Appointment) Cancel(s int, x_auth_token string, x_company_id *string) error {
 	companyIDStr := a.Created.CompanyID.String()
 	cID, err := Get_x_company_id(x_company_id, &companyIDStr)
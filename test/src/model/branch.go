@@ -8,6 +8,7 @@ import (
 	"mynute-go/core/src/config/namespace"
 	"mynute-go/core/src/lib"
 	"mynute-go/test/src/handler"
+	"slices"
 
 	"github.com/google/uuid"
 )
@@ -318,6 +319,27 @@ func (b *Branch) AddService(status int, service *Service, x_auth_token string, x
 	return nil
 }
 
+func (b *Branch) RemoveService(status int, service *Service, x_auth_token string, x_company_id *string) error {
+	companyIDStr := b.Company.Created.ID.String()
+	cID, err := Get_x_company_id(x_company_id, &companyIDStr)
+	if err != nil {
+		return err
+	}
+	if err := handler.NewHttpClient().
+		Method("DELETE").
+		URL(fmt.Sprintf("/branch/%s/service/%s", b.Created.ID.String(), service.Created.ID.String())).
+		ExpectedStatus(status).
+		Header(namespace.HeadersKey.Auth, x_auth_token).
+		Header(namespace.HeadersKey.Company, cID).
+		Send(nil).
+		Error; err != nil {
+		return fmt.Errorf("failed to remove service from branch: %w", err)
+	}
+	b.Services = slices.DeleteFunc(b.Services, func(s *Service) bool { return s.Created.ID == service.Created.ID })
+	service.Branches = slices.DeleteFunc(service.Branches, func(br *Branch) bool { return br.Created.ID == b.Created.ID })
+	return nil
+}
+
 func (b *Branch) CreateWorkSchedule(status int, schedule DTO.CreateBranchWorkSchedule, x_auth_token string, x_company_id *string) error {
 	if schedule.WorkRanges == nil {
 		return fmt.Errorf("work schedule cannot be nil")
@@ -12,12 +12,18 @@ import (
 	"mynute-go/test/src/handler"
 	"net/url"
 	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 )
 
 type Client struct {
 	Created      *model.Client
 	Appointments []*Appointment
 	X_Auth_Token string
+	// emailsSeen is how many emails of the kind last asked for the client had before asking
+	emailsSeen int
 }
 
 func (u *Client) Set() error {
@@ -72,6 +78,60 @@ func (u *Client) Create(s int) error {
 	return nil
 }
 
+// CreateGuest creates a guest client with the details of guest, which is sent a login code.
+func (u *Client) CreateGuest(s int, guest DTO.CreateGuestClient) error {
+	u.emailsSeen = countAccountEmails(guest.Email, codeSubjects)
+	if err := handler.NewHttpClient().
+		Method("POST").
+		URL("/client/guest?language=en").
+		ExpectedStatus(s).
+		Send(guest).
+		ParseResponse(&u.Created).Error; err != nil {
+		return fmt.Errorf("failed to create guest client: %w", err)
+	}
+	return nil
+}
+
+// VerifyGuest logs the guest in with the code sent to its email.
+func (u *Client) VerifyGuest(s int, code string) error {
+	http := handler.NewHttpClient()
+	if err := http.
+		Method("POST").
+		URL("/client/guest/verify").
+		ExpectedStatus(s).
+		Send(DTO.LoginByEmailCode{Email: u.Created.Email, Code: code}).Error; err != nil {
+		return fmt.Errorf("failed to verify guest client: %w", err)
+	}
+
+	if s == 200 {
+		auth := http.ResHeaders[namespace.HeadersKey.Auth]
+		if len(auth) == 0 {
+			return fmt.Errorf("authorization header '%s' not found", namespace.HeadersKey.Auth)
+		}
+		u.X_Auth_Token = auth[0]
+		if err := u.GetByEmail(200); err != nil {
+			return fmt.Errorf("failed to get client by email after verifying the guest: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpgradeGuest sets the password of the guest, turning it into a client with an account.
+func (u *Client) UpgradeGuest(s int, password string, x_auth_token string) error {
+	if err := handler.NewHttpClient().
+		Method("POST").
+		URL("/client/"+u.Created.ID.String()+"/upgrade").
+		ExpectedStatus(s).
+		Header(namespace.HeadersKey.Auth, x_auth_token).
+		Send(DTO.UpgradeGuestClient{Password: password}).Error; err != nil {
+		return fmt.Errorf("failed to upgrade guest client: %w", err)
+	}
+	if s == 200 {
+		u.Created.Password = password
+	}
+	return nil
+}
+
 func (u *Client) Update(s int, changes map[string]any) error {
 	if err := handler.NewHttpClient().
 		Method("PATCH").
@@ -202,6 +262,7 @@ func (u *Client) LoginByEmailCode(s int, code string) error {
 }
 
 func (u *Client) SendLoginCode(s int) error {
+	u.emailsSeen = countAccountEmails(u.Created.Email, codeSubjects)
 	http := handler.NewHttpClient()
 	if err := http.
 		Method("POST").
@@ -213,34 +274,57 @@ func (u *Client) SendLoginCode(s int) error {
 	return nil
 }
 
+// GetLoginCodeFromEmail waits for the login code last asked for and returns it.
 func (u *Client) GetLoginCodeFromEmail() (string, error) {
-	// Initialize MailHog client
+	return waitForCode(u.Created.Email, u.emailsSeen)
+}
+
+// GetIntakeTokenFromEmail returns the token of the latest intake form link emailed to the client.
+func (u *Client) GetIntakeTokenFromEmail() (string, error) {
+	return u.getLinkTokenFromEmail("intake")
+}
+
+// GetReviewTokenFromEmail returns the token of the latest review link emailed to the client.
+func (u *Client) GetReviewTokenFromEmail() (string, error) {
+	return u.getLinkTokenFromEmail("review")
+}
+
+// getLinkTokenFromEmail returns the token of the latest link to the page emailed to the client.
+func (u *Client) getLinkTokenFromEmail(page string) (string, error) {
 	mailhog, err := email.MailHog()
 	if err != nil {
 		return "", err
 	}
-
-	// Get the latest email sent to the client
-	message, err := mailhog.GetLatestMessageTo(u.Created.Email)
+	messages, err := mailhog.GetMessages()
 	if err != nil {
 		return "", err
 	}
 
-	// Verify the email has a subject
-	if message.GetSubject() == "" {
-		return "", fmt.Errorf("email subject is empty")
+	// The booking emails of the client are sent meanwhile, so the link is looked for in all of them
+	prefix := "/" + page + "?token="
+	var token string
+	var latest time.Time
+	for i := range messages {
+		msg := &messages[i]
+		if !slices.ContainsFunc(msg.To, func(to email.MailHogPath) bool { return to.Mailbox+"@"+to.Domain == u.Created.Email }) {
+			continue
+		}
+		link, err := msg.ExtractCode(regexp.QuoteMeta(prefix) + `[0-9a-f]{64}`)
+		if err != nil {
+			continue
+		}
+		if token == "" || msg.Created.After(latest) {
+			token, latest = strings.TrimPrefix(link, prefix), msg.Created
+		}
 	}
-
-	// Extract the validation code from the email
-	code, err := message.ExtractValidationCode()
-	if err != nil {
-		return "", err
+	if token == "" {
+		return "", fmt.Errorf("no %s link found for %s", page, u.Created.Email)
 	}
-
-	return code, nil
+	return token, nil
 }
 
 func (u *Client) SendPasswordResetEmail(s int) error {
+	u.emailsSeen = countAccountEmails(u.Created.Email, passwordSubjects)
 	http := handler.NewHttpClient()
 	if err := http.
 		Method("POST").
@@ -252,30 +336,16 @@ func (u *Client) SendPasswordResetEmail(s int) error {
 	return nil
 }
 
+// GetNewPasswordFromEmail waits for the new password last asked for and returns it.
 func (u *Client) GetNewPasswordFromEmail() (string, error) {
-	// Initialize MailHog client
-	mailhog, err := email.MailHog()
+	message, err := waitForAccountEmail(u.Created.Email, passwordSubjects, u.emailsSeen)
 	if err != nil {
 		return "", err
 	}
-
-	// Get the latest email sent to the client
-	message, err := mailhog.GetLatestMessageTo(u.Created.Email)
-	if err != nil {
-		return "", err
-	}
-
-	// Verify the email has a subject
-	if message.GetSubject() == "" {
-		return "", fmt.Errorf("email subject is empty")
-	}
-
-	// Extract the new password from the email
 	password, err := message.ExtractPassword()
 	if err != nil {
 		return "", fmt.Errorf("failed to extract password: %w", err)
 	}
-
 	return password, nil
 }
 
@@ -301,6 +371,7 @@ func (u *Client) ResetPasswordByEmail(s int) error {
 }
 
 func (u *Client) SendVerificationEmail(s int) error {
+	u.emailsSeen = countAccountEmails(u.Created.Email, codeSubjects)
 	http := handler.NewHttpClient()
 	if err := http.
 		Method("POST").
@@ -312,31 +383,9 @@ func (u *Client) SendVerificationEmail(s int) error {
 	return nil
 }
 
+// GetVerificationCodeFromEmail waits for the verification code last asked for and returns it.
 func (u *Client) GetVerificationCodeFromEmail() (string, error) {
-	// Initialize MailHog client
-	mailhog, err := email.MailHog()
-	if err != nil {
-		return "", err
-	}
-
-	// Get the latest email sent to the client
-	message, err := mailhog.GetLatestMessageTo(u.Created.Email)
-	if err != nil {
-		return "", err
-	}
-
-	// Verify the email has a subject
-	if message.GetSubject() == "" {
-		return "", fmt.Errorf("email subject is empty")
-	}
-
-	// Extract the verification code from the email
-	code, err := message.ExtractValidationCode()
-	if err != nil {
-		return "", err
-	}
-
-	return code, nil
+	return waitForCode(u.Created.Email, u.emailsSeen)
 }
 
 func (u *Client) VerifyEmailByCode(s int, code string) error {
@@ -390,6 +439,10 @@ func ValidateUpdateChanges(modelName string, v any, changes map[string]any) erro
 				return fmt.Errorf("failed to convert expected value for key '%s' to map: %w", key, err)
 			}
 		}
+		// Numbers of the struct come back from JSON as float64
+		if n, ok := toFloat(expected); ok {
+			expected = n
+		}
 
 		actual := mappy[key]
 
@@ -401,6 +454,20 @@ func ValidateUpdateChanges(modelName string, v any, changes map[string]any) erro
 	return nil
 }
 
+// toFloat returns v as a float64 when it is an integer or a float.
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
 func (c *Client) UploadImages(status int, files map[string][]byte, x_auth_token *string) error {
 	var fileMap = make(handler.Files)
 	for field, content := range files {
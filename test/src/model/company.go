@@ -1434,6 +1434,26 @@ func (c *Company) GetImage(status int, imageURL string, compareImgBytes *[]byte)
 	return nil
 }
 
+// BranchByID returns the branch of the company loaded with the ID, nil when none is.
+func (c *Company) BranchByID(id string) *Branch {
+	for _, b := range c.Branches {
+		if b.Created.ID.String() == id {
+			return b
+		}
+	}
+	return nil
+}
+
+// EmployeeByID returns the employee of the company loaded with the ID, nil when none is.
+func (c *Company) EmployeeByID(id string) *Employee {
+	for _, e := range c.Employees {
+		if e.Created.ID.String() == id {
+			return e
+		}
+	}
+	return nil
+}
+
 func (c *Company) GetRandomService() (*Service, error) {
 	if len(c.Services) == 0 {
 		return nil, fmt.Errorf("no services available in the company to select a random service")
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"fmt"
+	"mynute-go/core/src/lib/email"
+	"slices"
+	"time"
+)
+
+// codeSubjects are the subjects of the login and verification code emails.
+var codeSubjects = []string{
+	"Your Login Validation Code",
+	"Seu Código de Validação de Login",
+	"Su Código de Validación de Inicio de Sesión",
+	"Verify Your Email Address",
+	"Verifique Seu Endereço de E-mail",
+	"Verifique Su Dirección de Correo Electrónico",
+}
+
+// passwordSubjects are the subjects of the new password emails.
+var passwordSubjects = []string{
+	"Your New Password",
+	"Sua Nova Senha",
+	"Su Nueva Contraseña",
+}
+
+// accountEmails returns the emails with one of the subjects sent to the address, oldest first.
+func accountEmails(address string, subjects []string) ([]*email.MailHogMessage, error) {
+	mailhog, err := email.MailHog()
+	if err != nil {
+		return nil, err
+	}
+	messages, err := mailhog.GetMessages()
+	if err != nil {
+		return nil, err
+	}
+	var sent []*email.MailHogMessage
+	for i := range messages {
+		msg := &messages[i]
+		if !slices.Contains(subjects, msg.GetSubject()) {
+			continue
+		}
+		if slices.ContainsFunc(msg.To, func(to email.MailHogPath) bool { return to.Mailbox+"@"+to.Domain == address }) {
+			sent = append(sent, msg)
+		}
+	}
+	slices.SortFunc(sent, func(a, b *email.MailHogMessage) int { return a.Created.Compare(b.Created) })
+	return sent, nil
+}
+
+// countAccountEmails returns how many emails with one of the subjects were sent to the
+// address so far. It is taken before asking for an email, to be passed to waitForAccountEmail.
+func countAccountEmails(address string, subjects []string) int {
+	sent, err := accountEmails(address, subjects)
+	if err != nil {
+		return 0
+	}
+	return len(sent)
+}
+
+// waitForAccountEmail returns the latest email with one of the subjects sent to the address
+// once more than seen were sent. Account emails are sent by the outbox worker, so they
+// may arrive some time after the request asking for them.
+func waitForAccountEmail(address string, subjects []string, seen int) (*email.MailHogMessage, error) {
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		sent, err := accountEmails(address, subjects)
+		if err == nil && len(sent) > seen {
+			return sent[len(sent)-1], nil
+		}
+		if err == nil {
+			err = fmt.Errorf("no new %q email for %s", subjects[0], address)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("email not sent after 30s: %w", err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// waitForCode returns the code of the latest code email sent to the address once more than seen were sent.
+func waitForCode(address string, seen int) (string, error) {
+	msg, err := waitForAccountEmail(address, codeSubjects, seen)
+	if err != nil {
+		return "", err
+	}
+	return msg.ExtractValidationCode()
+}
@@ -10,6 +10,10 @@ import (
 	"mynute-go/core/src/lib/email"
 	"mynute-go/test/src/handler"
 	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -21,6 +25,8 @@ type Employee struct {
 	Branches     []*Branch
 	Appointments []*Appointment
 	X_Auth_Token string
+	// emailsSeen is how many emails of the kind last asked for the employee had before asking
+	emailsSeen int
 }
 
 func (e *Employee) GetID() string        { return e.Created.ID.String() }
@@ -434,6 +440,7 @@ func (e *Employee) LoginByEmailCode(s int, code string, x_company_id *string) er
 }
 
 func (e *Employee) SendLoginCode(s int, x_company_id *string) error {
+	e.emailsSeen = countAccountEmails(e.Created.Email, codeSubjects)
 	// Note: The employee send-login-code endpoint DOES require X-Company-ID header
 	// to switch to the correct company schema before querying for the employee
 	companyIDStr := e.Company.Created.ID.String()
@@ -454,62 +461,46 @@ func (e *Employee) SendLoginCode(s int, x_company_id *string) error {
 	return nil
 }
 
+// GetLoginCodeFromEmail waits for the login code last asked for and returns it.
 func (e *Employee) GetLoginCodeFromEmail() (string, error) {
-	// Initialize MailHog client
+	return waitForCode(e.Created.Email, e.emailsSeen)
+}
+
+// GetInvitationTokenFromEmail returns the token of the latest invitation link emailed to the employee.
+func (e *Employee) GetInvitationTokenFromEmail() (string, error) {
 	mailhog, err := email.MailHog()
 	if err != nil {
 		return "", err
 	}
-
-	// Get all messages to find the login validation email
 	messages, err := mailhog.GetMessages()
 	if err != nil {
 		return "", err
 	}
 
-	// Search for the most recent login validation email (searching from newest to oldest)
-	var loginMessage *email.MailHogMessage
-	for i := len(messages) - 1; i >= 0; i-- {
+	prefix := "/employee/invitation?token="
+	var token string
+	var latest time.Time
+	for i := range messages {
 		msg := &messages[i]
-
-		// Check if this message is for the employee
-		isForEmployee := false
-		for _, to := range msg.To {
-			recipientEmail := fmt.Sprintf("%s@%s", to.Mailbox, to.Domain)
-			if recipientEmail == e.Created.Email {
-				isForEmployee = true
-				break
-			}
+		if !slices.ContainsFunc(msg.To, func(to email.MailHogPath) bool { return to.Mailbox+"@"+to.Domain == e.Created.Email }) {
+			continue
 		}
-
-		if !isForEmployee {
+		link, err := msg.ExtractCode(regexp.QuoteMeta(prefix) + `[0-9a-f]{64}`)
+		if err != nil {
 			continue
 		}
-
-		// Check if this is a login validation email
-		subject := msg.GetSubject()
-		if subject == "Your Login Validation Code" ||
-			subject == "Seu Código de Validação de Login" ||
-			subject == "Su Código de Validación de Inicio de Sesión" {
-			loginMessage = msg
-			break
+		if token == "" || msg.Created.After(latest) {
+			token, latest = strings.TrimPrefix(link, prefix), msg.Created
 		}
 	}
-
-	if loginMessage == nil {
-		return "", fmt.Errorf("no login validation email found for %s", e.Created.Email)
+	if token == "" {
+		return "", fmt.Errorf("no invitation link found for %s", e.Created.Email)
 	}
-
-	// Extract the validation code from the email
-	code, err := loginMessage.ExtractValidationCode()
-	if err != nil {
-		return "", err
-	}
-
-	return code, nil
+	return token, nil
 }
 
 func (e *Employee) SendPasswordResetEmail(s int, x_company_id *string) error {
+	e.emailsSeen = countAccountEmails(e.Created.Email, passwordSubjects)
 	// Note: The employee reset-password endpoint requires X-Company-ID header
 	companyIDStr := e.Company.Created.ID.String()
 	cID, err := Get_x_company_id(x_company_id, &companyIDStr)
@@ -529,30 +520,16 @@ func (e *Employee) SendPasswordResetEmail(s int, x_company_id *string) error {
 	return nil
 }
 
+// GetNewPasswordFromEmail waits for the new password last asked for and returns it.
 func (e *Employee) GetNewPasswordFromEmail() (string, error) {
-	// Initialize MailHog client
-	mailhog, err := email.MailHog()
+	message, err := waitForAccountEmail(e.Created.Email, passwordSubjects, e.emailsSeen)
 	if err != nil {
 		return "", err
 	}
-
-	// Get the latest email sent to the employee
-	message, err := mailhog.GetLatestMessageTo(e.Created.Email)
-	if err != nil {
-		return "", err
-	}
-
-	// Verify the email has a subject
-	if message.GetSubject() == "" {
-		return "", fmt.Errorf("email subject is empty")
-	}
-
-	// Extract the new password from the email
 	password, err := message.ExtractPassword()
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to extract password: %w", err)
 	}
-
 	return password, nil
 }
 
@@ -578,6 +555,7 @@ func (e *Employee) ResetPasswordByEmail(s int, x_company_id *string) error {
 }
 
 func (e *Employee) SendVerificationEmail(s int, x_company_id *string) error {
+	e.emailsSeen = countAccountEmails(e.Created.Email, codeSubjects)
 	// Note: The employee send-verification-code endpoint requires X-Company-ID header
 	companyIDStr := e.Company.Created.ID.String()
 	cID, err := Get_x_company_id(x_company_id, &companyIDStr)
@@ -597,31 +575,9 @@ func (e *Employee) SendVerificationEmail(s int, x_company_id *string) error {
 	return nil
 }
 
+// GetVerificationCodeFromEmail waits for the verification code last asked for and returns it.
 func (e *Employee) GetVerificationCodeFromEmail() (string, error) {
-	// Initialize MailHog client
-	mailhog, err := email.MailHog()
-	if err != nil {
-		return "", err
-	}
-
-	// Get the latest email sent to the employee
-	message, err := mailhog.GetLatestMessageTo(e.Created.Email)
-	if err != nil {
-		return "", err
-	}
-
-	// Verify the email has a subject
-	if message.GetSubject() == "" {
-		return "", fmt.Errorf("email subject is empty")
-	}
-
-	// Extract the verification code from the email
-	code, err := message.ExtractValidationCode()
-	if err != nil {
-		return "", err
-	}
-
-	return code, nil
+	return waitForCode(e.Created.Email, e.emailsSeen)
 }
 
 func (e *Employee) VerifyEmailByCode(s int, code string, x_company_id *string) error {
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"fmt"
+	DTO "mynute-go/core/src/config/api/dto"
+	"mynute-go/core/src/config/namespace"
+	"mynute-go/test/src/handler"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Payment struct {
+	Created *DTO.Payment
+	Company *Company
+}
+
+func (p *Payment) GetById(s int, id uuid.UUID, x_auth_token string, x_company_id *string) error {
+	companyIDStr := p.Company.Created.ID.String()
+	cID, err := Get_x_company_id(x_company_id, &companyIDStr)
+	if err != nil {
+		return err
+	}
+	if err := handler.NewHttpClient().
+		Method("GET").
+		URL("/payment/"+id.String()).
+		ExpectedStatus(s).
+		Header(namespace.HeadersKey.Auth, x_auth_token).
+		Header(namespace.HeadersKey.Company, cID).
+		Send(nil).
+		ParseResponse(&p.Created).Error; err != nil {
+		return fmt.Errorf("failed to get payment %s: %w", id, err)
+	}
+	return nil
+}
+
+// Refund refunds amount of the payment, everything not refunded yet when amount is 0.
+func (p *Payment) Refund(s int, amount int64, x_auth_token string, x_company_id *string) error {
+	companyIDStr := p.Company.Created.ID.String()
+	cID, err := Get_x_company_id(x_company_id, &companyIDStr)
+	if err != nil {
+		return err
+	}
+	var refunded DTO.Payment
+	if err := handler.NewHttpClient().
+		Method("POST").
+		URL("/payment/"+p.Created.ID.String()+"/refund").
+		ExpectedStatus(s).
+		Header(namespace.HeadersKey.Auth, x_auth_token).
+		Header(namespace.HeadersKey.Company, cID).
+		Send(DTO.RefundPayment{Amount: amount}).
+		ParseResponse(&refunded).Error; err != nil {
+		return fmt.Errorf("failed to refund payment %s: %w", p.Created.ID, err)
+	}
+	return nil
+}
+
+// WaitForRefund reloads the payment until amount of it was refunded. Refunds are sent to the
+// provider by the outbox worker, so they are recorded some time after they are asked for.
+func (p *Payment) WaitForRefund(amount int64, x_auth_token string) error {
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		if err := p.GetById(200, p.Created.ID, x_auth_token, nil); err != nil {
+			return err
+		}
+		if p.Created.RefundedAmount >= amount {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("payment %s has %d refunded after 30s, expected %d", p.Created.ID, p.Created.RefundedAmount, amount)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// GetPix loads the BR Code of a Pix payment.
+func (p *Payment) GetPix(s int, x_auth_token string, x_company_id *string) (*DTO.PixCharge, error) {
+	companyIDStr := p.Company.Created.ID.String()
+	cID, err := Get_x_company_id(x_company_id, &companyIDStr)
+	if err != nil {
+		return nil, err
+	}
+	var charge DTO.PixCharge
+	if err := handler.NewHttpClient().
+		Method("GET").
+		URL("/payment/"+p.Created.ID.String()+"/pix").
+		ExpectedStatus(s).
+		Header(namespace.HeadersKey.Auth, x_auth_token).
+		Header(namespace.HeadersKey.Company, cID).
+		Send(nil).
+		ParseResponse(&charge).Error; err != nil {
+		return nil, fmt.Errorf("failed to get Pix code of payment %s: %w", p.Created.ID, err)
+	}
+	return &charge, nil
+}
+
+// Notify posts a notification of the provider about the payments of the company,
+// signed with signatureHeader.
+func (p *Payment) Notify(s int, provider string, body []byte, signatureHeader, signature string) error {
+	if err := handler.NewHttpClient().
+		Method("POST").
+		URL(fmt.Sprintf("/payment/webhook/%s/%s", provider, p.Company.Created.ID.String())).
+		ExpectedStatus(s).
+		Header(signatureHeader, signature).
+		Send(body).Error; err != nil {
+		return fmt.Errorf("failed to notify payments of company %s: %w", p.Company.Created.ID, err)
+	}
+	return nil
+}